@@ -15,24 +15,36 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
+	"path"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/cloud-bulldozer/go-commons/v2/version"
 	uid "github.com/google/uuid"
 	"github.com/kube-burner/kube-burner/pkg/alerting"
 	"github.com/kube-burner/kube-burner/pkg/burner"
 	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/controller"
+	"github.com/kube-burner/kube-burner/pkg/dashboards"
 	"github.com/kube-burner/kube-burner/pkg/measurements"
 	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	"github.com/kube-burner/kube-burner/pkg/report"
 	"github.com/kube-burner/kube-burner/pkg/util"
 	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	"github.com/kube-burner/kube-burner/pkg/util/junit"
 	"github.com/kube-burner/kube-burner/pkg/util/metrics"
+	"github.com/kube-burner/kube-burner/pkg/util/runs"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
@@ -40,6 +52,7 @@ import (
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/rest"
 )
 
 var binName = filepath.Base(os.Args[0])
@@ -121,7 +134,12 @@ func initCmd() *cobra.Command {
 	var skipTLSVerify bool
 	var timeout time.Duration
 	var userDataFile string
-	var allowMissingKeys bool
+	var allowMissingKeys, strict, allowProduction bool
+	var workers, workerIndex int
+	var workerResults string
+	var junitFile string
+	var compareKubeConfig, compareKubeContext, compareSide, compareAddress string
+	var runsRegistry string
 	var rc int
 	cmd := &cobra.Command{
 		Use:   "init",
@@ -137,17 +155,43 @@ func initCmd() *cobra.Command {
 			}
 		},
 		Run: func(cmd *cobra.Command, args []string) {
+			// Coordinator process of an A/B benchmark, it launches and keeps in step one process per cluster
+			if (compareKubeConfig != "" || compareKubeContext != "") && compareSide == "" {
+				if workers > 1 {
+					log.Fatal("The A/B comparison mode can't be combined with workers")
+				}
+				rc = util.RunComparison(uuid, compareKubeConfig, compareKubeContext)
+				return
+			}
+			if workerIndex >= workers {
+				log.Fatalf("Invalid worker index %d, it must be lower than the number of workers: %d", workerIndex, workers)
+			}
+			configSource := configFile
 			if configMap != "" {
+				configSource = fmt.Sprintf("configmap://%s/%s", namespace, configMap)
 				metricsProfile, alertProfile, err = config.FetchConfigMap(configMap, namespace)
 				if err != nil {
 					log.Fatal(err.Error())
 				}
 				// We assume configFile is config.yml
 				configFile = "config.yml"
+			} else if strings.HasPrefix(configFile, config.OCIScheme) {
+				metricsProfile, alertProfile, err = config.FetchOCIBundle(configFile)
+				if err != nil {
+					log.Fatal(err.Error())
+				}
+				configFile = "config.yml"
+			}
+			if workers > 1 && workerIndex >= 0 {
+				util.SetupFileLogging(fmt.Sprintf("%s-worker-%d", uuid, workerIndex))
+			} else if compareSide != "" {
+				util.SetupFileLogging(fmt.Sprintf("%s-%s", uuid, compareSide))
+			} else {
+				util.SetupFileLogging(uuid)
 			}
-			util.SetupFileLogging(uuid)
 			kubeClientProvider := config.NewKubeClientProvider(kubeConfig, kubeContext)
-			clientSet, _ = kubeClientProvider.DefaultClientSet()
+			var restConfig *rest.Config
+			clientSet, restConfig = kubeClientProvider.DefaultClientSet()
 			configFileReader, err := fileutils.GetWorkloadReader(configFile, nil)
 			if err != nil {
 				log.Fatalf("Error reading configuration file %s: %s\nPlease ensure the file exists and is accessible", configFile, err)
@@ -163,19 +207,103 @@ func initCmd() *cobra.Command {
 			if err != nil {
 				log.Fatalf("Config error: %s", err.Error())
 			}
+			if err := configSpec.ValidateWorkers(workers); err != nil {
+				log.Fatalf("Config error: %s", err.Error())
+			}
+			if strict {
+				configSpec.GlobalConfig.Strict = true
+			}
+			if allowProduction && configSpec.GlobalConfig.ProductionGuard != nil {
+				configSpec.GlobalConfig.ProductionGuard.Allow = true
+			}
+			var summaryMetadata, metricsMetadata map[string]any
+			if compareSide != "" {
+				configSpec.GlobalConfig.CompareSide = compareSide
+				configSpec.GlobalConfig.CompareAddress = compareAddress
+				compareSideDirectories(&configSpec, compareSide)
+				// Tells apart the documents indexed by each cluster
+				summaryMetadata = map[string]any{"compareSide": compareSide}
+				metricsMetadata = map[string]any{"compareSide": compareSide}
+			}
 			metricsScraper := metrics.ProcessMetricsScraperConfig(metrics.ScraperConfig{
-				ConfigSpec:      &configSpec,
-				MetricsEndpoint: metricsEndpoint,
-				UserMetaData:    userMetadata,
-				AlertProfile:    alertProfile,
-				MetricsProfile:  metricsProfile,
+				ConfigSpec:         &configSpec,
+				MetricsEndpoint:    metricsEndpoint,
+				UserMetaData:       userMetadata,
+				SummaryMetadata:    summaryMetadata,
+				MetricsMetadata:    metricsMetadata,
+				AlertProfile:       alertProfile,
+				MetricsProfile:     metricsProfile,
+				KubeClientProvider: kubeClientProvider,
 			})
+			defer metricsScraper.Close()
+			// Coordinator process, it launches the workers and indexes the results of all of them once they finish
+			if workers > 1 && workerIndex < 0 {
+				resultsDirectory, err := os.MkdirTemp("", fmt.Sprintf("kube-burner-%s-", uuid))
+				if err != nil {
+					log.Fatalf("Error creating the workers results directory: %v", err)
+				}
+				defer os.RemoveAll(resultsDirectory)
+				rc = util.RunWorkers(workers, uuid, resultsDirectory)
+				indexRC, err := burner.IndexWorkerResults(configSpec, metricsScraper, resultsDirectory)
+				if err != nil {
+					log.Error(err.Error())
+				}
+				rc = max(rc, indexRC)
+				return
+			}
+			if workers > 1 {
+				if workerResults == "" {
+					log.Fatal("Worker processes require --worker-results, it's set by the coordinator")
+				}
+				configSpec.GlobalConfig.Workers = workers
+				configSpec.GlobalConfig.WorkerIndex = workerIndex
+				if metricsScraper, err = burner.WorkerScraper(metricsScraper, workerResults); err != nil {
+					log.Fatal(err.Error())
+				}
+			}
 			if configSpec.GlobalConfig.ClusterHealth {
 				clientSet, _ = kubeClientProvider.ClientSet(0, 0)
 				util.ClusterHealthCheck(clientSet)
 			}
 
+			// Workers share the UUID of the benchmark, only the first one records it
+			if workers > 1 && workerIndex != 0 {
+				runsRegistry = ""
+			}
+			run := runs.Run{
+				UUID:        uuid,
+				Workload:    runWorkload(metricsScraper.SummaryMetadata, configSource),
+				Config:      configSource,
+				Cluster:     restConfig.Host,
+				CompareSide: compareSide,
+				Version:     fmt.Sprintf("%v@%v", version.Version, version.GitCommit),
+				Start:       time.Now().UTC(),
+				Status:      runs.StatusRunning,
+			}
+			for _, job := range configSpec.Jobs {
+				run.Jobs = append(run.Jobs, job.Name)
+			}
+			if err := runs.Record(runsRegistry, run); err != nil {
+				log.Warnf("Error recording the run in the registry %s: %v", runsRegistry, err)
+			}
 			rc, err = burner.Run(configSpec, kubeClientProvider, metricsScraper, nil, nil)
+			run.End, run.ReturnCode, run.Status = time.Now().UTC(), rc, runs.StatusPassed
+			if rc != 0 {
+				run.Status = runs.StatusFailed
+			}
+			if err := runs.Record(runsRegistry, run); err != nil {
+				log.Warnf("Error recording the run in the registry %s: %v", runsRegistry, err)
+			}
+			if junitFile != "" {
+				if workers > 1 {
+					junitFile = fmt.Sprintf("%s-worker-%d%s", strings.TrimSuffix(junitFile, filepath.Ext(junitFile)), workerIndex, filepath.Ext(junitFile))
+				} else if compareSide != "" {
+					junitFile = fmt.Sprintf("%s-%s%s", strings.TrimSuffix(junitFile, filepath.Ext(junitFile)), compareSide, filepath.Ext(junitFile))
+				}
+				if err := junit.Write(junitFile, uuid); err != nil {
+					log.Errorf("Error writing JUnit file: %v", err)
+				}
+			}
 			if err != nil {
 				log.Error(err.Error())
 				os.Exit(rc)
@@ -186,7 +314,7 @@ func initCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&metricsEndpoint, "metrics-endpoint", "e", "", "YAML file with a list of metric endpoints")
 	cmd.Flags().BoolVar(&skipTLSVerify, "skip-tls-verify", true, "Verify prometheus TLS certificate")
 	cmd.Flags().DurationVarP(&timeout, "timeout", "", 4*time.Hour, "Benchmark timeout")
-	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file path or URL")
+	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file path, URL or OCI workload bundle reference")
 	cmd.Flags().StringVarP(&configMap, "configmap", "", "", "Configmap holding all the configuration: config.yml, metrics.yml and alerts.yml. metrics and alerts are optional")
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Namespace where the configmap is")
 	cmd.Flags().StringVar(&userMetadata, "user-metadata", "", "User provided metadata file, in YAML format")
@@ -194,11 +322,144 @@ func initCmd() *cobra.Command {
 	cmd.Flags().StringVar(&kubeContext, "kube-context", "", "The name of the kubeconfig context to use")
 	cmd.Flags().StringVar(&userDataFile, "user-data", "", "User provided data file for rendering the configuration file, in JSON or YAML format")
 	cmd.Flags().BoolVar(&allowMissingKeys, "allow-missing", false, "Do not fail on missing values in the config file")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail the creation and patching of objects with unknown or duplicated fields, instead of dropping them")
+	cmd.Flags().BoolVar(&allowProduction, "allow-production", false, "Confirm running against a cluster that looks like production to the productionGuard")
+	cmd.Flags().IntVar(&workers, "workers", 1, "Number of worker processes to shard the create jobs iterations across")
+	cmd.Flags().IntVar(&workerIndex, "worker-index", -1, "Index of this worker process, set by the coordinator")
+	cmd.Flags().StringVar(&workerResults, "worker-results", "", "Directory where this worker process hands its results over to the coordinator, set by the coordinator")
+	cmd.Flags().MarkHidden("worker-index")
+	cmd.Flags().MarkHidden("worker-results")
+	cmd.Flags().StringVar(&junitFile, "junit-file", "", "Write the results of jobs, latency thresholds and alerts to this file in JUnit XML format")
+	cmd.Flags().StringVar(&compareKubeConfig, "compare-kubeconfig", "", "Path to the kubeconfig file of a second cluster to run the benchmark on concurrently and compare with")
+	cmd.Flags().StringVar(&compareKubeContext, "compare-kube-context", "", "The name of the kubeconfig context of a second cluster to run the benchmark on concurrently and compare with")
+	cmd.Flags().StringVar(&compareSide, "compare-side", "", "Side of the A/B benchmark run by this process, set by the coordinator")
+	cmd.Flags().StringVar(&compareAddress, "compare-address", "", "Address of the A/B lockstep server, set by the coordinator")
+	cmd.Flags().StringVar(&runsRegistry, "runs-registry", runs.DefaultPath(), fmt.Sprintf("File of the local run registry browsed by the runs subcommand, empty to disable it. Can be set with %s", runs.RegistryEnv))
+	cmd.Flags().MarkHidden("compare-side")
+	cmd.Flags().MarkHidden("compare-address")
 	cmd.Flags().SortFlags = false
 	cmd.MarkFlagsMutuallyExclusive("config", "configmap")
 	return cmd
 }
 
+// runWorkload returns the workload of the run, from the user metadata or the name of its configuration
+func runWorkload(summaryMetadata map[string]any, configSource string) string {
+	if workload, ok := summaryMetadata["workload"].(string); ok && workload != "" {
+		return workload
+	}
+	name := path.Base(strings.TrimSuffix(configSource, "/"))
+	if i := strings.IndexAny(name, ":@"); i > 0 && strings.HasPrefix(configSource, config.OCIScheme) {
+		name = name[:i]
+	}
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// compareSideDirectories suffixes the files and directories written by the local and OpenMetrics indexers with the
+// side of the A/B benchmark, as both sides run from the same directory
+func compareSideDirectories(configSpec *config.Spec, side string) {
+	directory := func(name string) string {
+		if name == "" {
+			return name
+		}
+		return fmt.Sprintf("%s-%s", strings.TrimSuffix(name, "/"), side)
+	}
+	for i := range configSpec.MetricsEndpoints {
+		endpoint := &configSpec.MetricsEndpoints[i]
+		indexerConfigs := []*config.IndexerConfig{&endpoint.IndexerConfig}
+		for j := range endpoint.Indexers {
+			indexerConfigs = append(indexerConfigs, &endpoint.Indexers[j])
+		}
+		for _, indexerConfig := range indexerConfigs {
+			switch indexerConfig.Type {
+			case indexers.LocalIndexer:
+				indexerConfig.MetricsDirectory = directory(indexerConfig.MetricsDirectory)
+				ext := filepath.Ext(indexerConfig.TarballName)
+				indexerConfig.TarballName = fmt.Sprintf("%s-%s%s", strings.TrimSuffix(indexerConfig.TarballName, ext), side, ext)
+			case metrics.OpenMetricsIndexer:
+				indexerConfig.MetricsDirectory = directory(indexerConfig.MetricsDirectory)
+				indexerConfig.TSDBDirectory = directory(indexerConfig.TSDBDirectory)
+			}
+		}
+	}
+}
+
+func runsCmd() *cobra.Command {
+	var registry string
+	cmd := &cobra.Command{
+		Use:   "runs",
+		Short: "Browse the benchmarks run from this machine",
+		Long:  "Browse the local registry of the benchmarks launched by the init subcommand from this machine",
+	}
+	cmd.PersistentFlags().StringVar(&registry, "runs-registry", runs.DefaultPath(), fmt.Sprintf("File of the local run registry. Can be set with %s", runs.RegistryEnv))
+	cmd.AddCommand(runsListCmd(&registry), runsShowCmd(&registry))
+	return cmd
+}
+
+func runsListCmd(registry *string) *cobra.Command {
+	var workload, status string
+	var limit int
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the runs of the registry, the most recent first",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			allRuns, err := runs.List(*registry)
+			if err != nil {
+				log.Fatalf("Error reading the run registry: %v", err)
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "UUID\tSTART\tDURATION\tWORKLOAD\tCLUSTER\tSTATUS")
+			var listed int
+			for _, run := range allRuns {
+				if (workload != "" && run.Workload != workload) || (status != "" && run.Status != status) {
+					continue
+				}
+				if limit > 0 && listed == limit {
+					break
+				}
+				cluster := run.Cluster
+				if run.CompareSide != "" {
+					cluster = fmt.Sprintf("%s (%s)", cluster, run.CompareSide)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%v\t%s\t%s\t%s\n", run.UUID, run.Start.Format(time.RFC3339), run.Duration(), run.Workload, cluster, run.Status)
+				listed++
+			}
+			w.Flush()
+		},
+	}
+	cmd.Flags().StringVar(&workload, "workload", "", "Only list the runs of this workload")
+	cmd.Flags().StringVar(&status, "status", "", fmt.Sprintf("Only list the runs with this status: %s, %s or %s", runs.StatusRunning, runs.StatusPassed, runs.StatusFailed))
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of runs listed, 0 lists all of them")
+	cmd.Flags().SortFlags = false
+	return cmd
+}
+
+func runsShowCmd(registry *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <uuid>",
+		Short: "Show the runs of the registry with the given UUID or UUID prefix",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			found, err := runs.Find(*registry, args[0])
+			if err != nil {
+				log.Fatalf("Error reading the run registry: %v", err)
+			}
+			if len(found) == 0 {
+				log.Fatalf("No run matches %s in the registry %s", args[0], *registry)
+			}
+			uuids := make(map[string]bool)
+			for _, run := range found {
+				uuids[run.UUID] = true
+			}
+			if len(uuids) > 1 {
+				log.Fatalf("%s matches %d runs, please provide a longer UUID prefix", args[0], len(uuids))
+			}
+			out, _ := json.MarshalIndent(found, "", "  ")
+			fmt.Println(string(out))
+		},
+	}
+}
+
 func healthCheck() *cobra.Command {
 	var kubeConfig, kubeContext string
 	var rc int
@@ -287,11 +548,11 @@ func measureCmd() *cobra.Command {
 			}
 			for pos, indexer := range configSpec.MetricsEndpoints {
 				log.Infof("📁 Creating indexer: %s", indexer.Type)
-				idx, err := indexers.NewIndexer(indexer.IndexerConfig)
+				idx, err := metrics.NewIndexer(indexer.IndexerConfig)
 				if err != nil {
 					log.Fatalf("Error creating indexer %d: %v", pos, err.Error())
 				}
-				indexerList[indexer.Alias] = *idx
+				indexerList[indexer.Alias] = idx
 			}
 			if userMetadata != "" {
 				metadata, err = util.ReadUserMetadata(userMetadata)
@@ -320,11 +581,15 @@ func measureCmd() *cobra.Command {
 				config.NewKubeClientProvider(kubeConfig, kubeContext),
 				nil,
 			)
-			measurementsInstance.Collect()
-			if err = measurementsInstance.Stop(); err != nil {
+			if err = measurementsInstance.Collect().Err(); err != nil {
+				log.Error(err.Error())
+			}
+			if err = measurementsInstance.Stop().Err(); err != nil {
+				log.Error(err.Error())
+			}
+			if err = measurementsInstance.Index(jobName, indexerList).Err(); err != nil {
 				log.Error(err.Error())
 			}
-			measurementsInstance.Index(jobName, indexerList)
 		},
 	}
 	cmd.Flags().StringVar(&uuid, "uuid", "", "UUID")
@@ -342,11 +607,12 @@ func indexCmd() *cobra.Command {
 	var url, metricsEndpoint, metricsProfile, jobName string
 	var start, end int64
 	var username, password, uuid, token, userMetadata string
-	var esServer, esIndex, metricsDirectory string
+	var esServer, esIndex, osServer, osIndex, metricsDirectory string
 	var configSpec config.Spec
 	var skipTLSVerify bool
 	var prometheusStep time.Duration
-	var tarballName string
+	var tarballName, tsdbDirectory string
+	var replace bool
 	var indexer config.MetricsEndpoint
 	cmd := &cobra.Command{
 		Use:   "index",
@@ -376,18 +642,23 @@ func indexCmd() *cobra.Command {
 				Metrics:       metricsProfiles,
 				SkipTLSVerify: skipTLSVerify,
 			}
-			if esServer != "" && esIndex != "" {
-				indexer.IndexerConfig = indexers.IndexerConfig{
-					Type:    indexers.ElasticIndexer,
-					Servers: []string{esServer},
-					Index:   esIndex,
+			if indexerConfig, ok := remoteIndexerConfig(esServer, esIndex, osServer, osIndex); ok {
+				indexerConfig.Replace = replace
+				indexer.IndexerConfig = indexerConfig
+			} else if tsdbDirectory != "" {
+				indexer.IndexerConfig = config.IndexerConfig{
+					IndexerConfig: indexers.IndexerConfig{
+						Type:             metrics.OpenMetricsIndexer,
+						MetricsDirectory: metricsDirectory,
+					},
+					TSDBDirectory: tsdbDirectory,
 				}
 			} else {
-				indexer.IndexerConfig = indexers.IndexerConfig{
+				indexer.IndexerConfig = config.IndexerConfig{IndexerConfig: indexers.IndexerConfig{
 					Type:             indexers.LocalIndexer,
 					MetricsDirectory: metricsDirectory,
 					TarballName:      tarballName,
-				}
+				}}
 			}
 			configSpec.MetricsEndpoints = append(configSpec.MetricsEndpoints, indexer)
 			metricsScraper := metrics.ProcessMetricsScraperConfig(metrics.ScraperConfig{
@@ -395,6 +666,7 @@ func indexCmd() *cobra.Command {
 				MetricsEndpoint: metricsEndpoint,
 				UserMetaData:    userMetadata,
 			})
+			defer metricsScraper.Close()
 			for _, prometheusClient := range metricsScraper.PrometheusClients {
 				prometheusJob := prometheus.Job{
 					Start: time.Unix(start, 0),
@@ -408,7 +680,12 @@ func indexCmd() *cobra.Command {
 				}
 			}
 			if configSpec.MetricsEndpoints[0].Type == indexers.LocalIndexer && tarballName != "" {
-				if err := metrics.CreateTarball(configSpec.MetricsEndpoints[0].IndexerConfig); err != nil {
+				if err := metrics.CreateTarball(configSpec.MetricsEndpoints[0].IndexerConfig.IndexerConfig); err != nil {
+					log.Fatal(err)
+				}
+			}
+			if configSpec.MetricsEndpoints[0].Type == metrics.OpenMetricsIndexer {
+				if err := metrics.CreateTSDBBlocks(configSpec.MetricsEndpoints[0].IndexerConfig); err != nil {
 					log.Fatal(err)
 				}
 			}
@@ -430,37 +707,38 @@ func indexCmd() *cobra.Command {
 	cmd.Flags().StringVar(&metricsDirectory, "metrics-directory", "collected-metrics", "Directory to dump the metrics files in, when using default local indexing")
 	cmd.Flags().StringVar(&esServer, "es-server", "", "Elastic Search endpoint")
 	cmd.Flags().StringVar(&esIndex, "es-index", "", "Elastic Search index")
+	cmd.Flags().StringVar(&osServer, "os-server", "", "OpenSearch endpoint")
+	cmd.Flags().StringVar(&osIndex, "os-index", "", "OpenSearch index")
+	cmd.Flags().BoolVar(&replace, "replace", false, "Overwrite the documents already indexed in Elastic Search or OpenSearch, instead of skipping them")
 	cmd.Flags().StringVar(&tarballName, "tarball-name", "", "Dump collected metrics into a tarball with the given name, requires local indexing")
+	cmd.Flags().StringVar(&tsdbDirectory, "tsdb-directory", "", "Write the metrics as OpenMetrics files into the metrics directory and convert them into Prometheus TSDB blocks in the given directory, requires promtool")
 	cmd.Flags().SortFlags = false
 	return cmd
 }
 
 func importCmd() *cobra.Command {
 	var tarball string
-	var esServer, esIndex, metricsDirectory string
-	var indexerConfig indexers.IndexerConfig
+	var esServer, esIndex, osServer, osIndex, metricsDirectory string
+	var replace bool
 	cmd := &cobra.Command{
 		Use:   "import",
 		Short: "Import metrics tarball",
 		Run: func(cmd *cobra.Command, args []string) {
-			if esServer != "" && esIndex != "" {
-				indexerConfig = indexers.IndexerConfig{
-					Type:    indexers.ElasticIndexer,
-					Servers: []string{esServer},
-					Index:   esIndex,
-				}
+			indexerConfig, ok := remoteIndexerConfig(esServer, esIndex, osServer, osIndex)
+			if ok {
+				indexerConfig.Replace = replace
 			} else {
-				indexerConfig = indexers.IndexerConfig{
+				indexerConfig = config.IndexerConfig{IndexerConfig: indexers.IndexerConfig{
 					Type:             indexers.LocalIndexer,
 					MetricsDirectory: metricsDirectory,
-				}
+				}}
 			}
 			log.Infof("📁 Creating indexer: %s", indexerConfig.Type)
-			indexer, err := indexers.NewIndexer(indexerConfig)
+			indexer, err := metrics.NewIndexer(indexerConfig)
 			if err != nil {
 				log.Fatal(err.Error())
 			}
-			err = metrics.ImportTarball(tarball, indexer)
+			err = metrics.ImportTarball(tarball, &indexer)
 			if err != nil {
 				log.Fatal(err.Error())
 			}
@@ -470,21 +748,42 @@ func importCmd() *cobra.Command {
 	cmd.Flags().StringVar(&metricsDirectory, "metrics-directory", "collected-metrics", "Directory to dump the metrics files in, when using default local indexing")
 	cmd.Flags().StringVar(&esServer, "es-server", "", "Elastic Search endpoint")
 	cmd.Flags().StringVar(&esIndex, "es-index", "", "Elastic Search index")
+	cmd.Flags().StringVar(&osServer, "os-server", "", "OpenSearch endpoint")
+	cmd.Flags().StringVar(&osIndex, "os-index", "", "OpenSearch index")
+	cmd.Flags().BoolVar(&replace, "replace", false, "Overwrite the documents already indexed in Elastic Search or OpenSearch, instead of skipping them")
 	cmd.MarkFlagRequired("tarball")
 	return cmd
 }
 
+// remoteIndexerConfig returns the Elastic Search or OpenSearch indexer configuration given by the command line flags
+func remoteIndexerConfig(esServer, esIndex, osServer, osIndex string) (config.IndexerConfig, bool) {
+	switch {
+	case esServer != "" && esIndex != "":
+		return config.IndexerConfig{IndexerConfig: indexers.IndexerConfig{
+			Type:    indexers.ElasticIndexer,
+			Servers: []string{esServer},
+			Index:   esIndex,
+		}}, true
+	case osServer != "" && osIndex != "":
+		return config.IndexerConfig{IndexerConfig: indexers.IndexerConfig{
+			Type:    indexers.OpenSearchIndexer,
+			Servers: []string{osServer},
+			Index:   osIndex,
+		}}, true
+	}
+	return config.IndexerConfig{}, false
+}
+
 func alertCmd() *cobra.Command {
 	var configSpec config.Spec
-	var err error
 	var url, alertProfile, username, password, uuid, token string
-	var esServer, esIndex, metricsDirectory string
+	var esServer, esIndex, osServer, osIndex, metricsDirectory, junitFile string
+	var slackWebhook, notificationWebhook, pagerDutyRoutingKey string
 	var start, end int64
 	var skipTLSVerify bool
 	var alertM *alerting.AlertManager
 	var prometheusStep time.Duration
 	var indexer *indexers.Indexer
-	var indexerConfig indexers.IndexerConfig
 	cmd := &cobra.Command{
 		Use:   "check-alerts",
 		Short: "Evaluate alerts for the given time range",
@@ -496,24 +795,20 @@ func alertCmd() *cobra.Command {
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			configSpec.GlobalConfig.UUID = uuid
-			if esServer != "" && esIndex != "" {
-				indexerConfig = indexers.IndexerConfig{
-					Type:    indexers.ElasticIndexer,
-					Servers: []string{esServer},
-					Index:   esIndex,
-				}
-			} else if metricsDirectory != "" {
-				indexerConfig = indexers.IndexerConfig{
+			indexerConfig, ok := remoteIndexerConfig(esServer, esIndex, osServer, osIndex)
+			if !ok && metricsDirectory != "" {
+				indexerConfig = config.IndexerConfig{IndexerConfig: indexers.IndexerConfig{
 					Type:             indexers.LocalIndexer,
 					MetricsDirectory: metricsDirectory,
-				}
+				}}
 			}
 			if indexerConfig.Type != "" {
 				log.Infof("📁 Creating indexer: %s", indexerConfig.Type)
-				indexer, err = indexers.NewIndexer(indexerConfig)
+				newIndexer, err := metrics.NewIndexer(indexerConfig)
 				if err != nil {
 					log.Fatal(err.Error())
 				}
+				indexer = &newIndexer
 			}
 			auth := prometheus.Auth{
 				Username:      username,
@@ -532,7 +827,24 @@ func alertCmd() *cobra.Command {
 			if alertM, err = alerting.NewAlertManager(alertProfile, uuid, p, indexer, nil, nil); err != nil {
 				log.Fatalf("Error creating alert manager: %s", err)
 			}
+			var notifications []config.AlertNotification
+			for notificationType, notification := range map[config.AlertNotificationType]config.AlertNotification{
+				config.AlertNotificationSlack:     {URL: slackWebhook},
+				config.AlertNotificationWebhook:   {URL: notificationWebhook},
+				config.AlertNotificationPagerDuty: {RoutingKey: pagerDutyRoutingKey},
+			} {
+				if notification.URL != "" || notification.RoutingKey != "" {
+					notification.Type = notificationType
+					notifications = append(notifications, notification)
+				}
+			}
+			alertM.SetNotifications(notifications)
 			err = alertM.Evaluate(job)
+			if junitFile != "" {
+				if err := junit.Write(junitFile, uuid); err != nil {
+					log.Errorf("Error writing JUnit file: %v", err)
+				}
+			}
 			log.Info("👋 Exiting kube-burner ", uuid)
 			if err != nil {
 				os.Exit(1)
@@ -545,6 +857,9 @@ func alertCmd() *cobra.Command {
 	cmd.Flags().StringVar(&username, "username", "", "Prometheus username for authentication")
 	cmd.Flags().StringVarP(&password, "password", "p", "", "Prometheus password for basic authentication")
 	cmd.Flags().StringVarP(&alertProfile, "alert-profile", "a", "alerts.yaml", "Alert profile file or URL")
+	cmd.Flags().StringVar(&slackWebhook, "slack-webhook", "", "Slack incoming webhook URL notified of the fired alerts")
+	cmd.Flags().StringVar(&notificationWebhook, "notification-webhook", "", "Webhook URL the fired alerts are POSTed to")
+	cmd.Flags().StringVar(&pagerDutyRoutingKey, "pagerduty-routing-key", "", "Integration key of the PagerDuty service notified of the fired alerts")
 	cmd.Flags().BoolVar(&skipTLSVerify, "skip-tls-verify", true, "Verify prometheus TLS certificate")
 	cmd.Flags().DurationVarP(&prometheusStep, "step", "s", 30*time.Second, "Prometheus step size")
 	cmd.Flags().Int64VarP(&start, "start", "", time.Now().Unix()-3600, "Epoch start time")
@@ -552,12 +867,359 @@ func alertCmd() *cobra.Command {
 	cmd.Flags().StringVar(&metricsDirectory, "metrics-directory", "", "Directory to dump the alert files in, enables local indexing when specified")
 	cmd.Flags().StringVar(&esServer, "es-server", "", "Elastic Search endpoint")
 	cmd.Flags().StringVar(&esIndex, "es-index", "", "Elastic Search index")
+	cmd.Flags().StringVar(&osServer, "os-server", "", "OpenSearch endpoint")
+	cmd.Flags().StringVar(&osIndex, "os-index", "", "OpenSearch index")
+	cmd.Flags().StringVar(&junitFile, "junit-file", "", "Write the results of the alerts to this file in JUnit XML format")
 	cmd.MarkFlagRequired("prometheus-url")
 	cmd.MarkFlagRequired("alert-profile")
 	cmd.Flags().SortFlags = false
 	return cmd
 }
 
+func reportCmd() *cobra.Command {
+	var uuid, metricsDirectory, format, output string
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Generate an HTML or Markdown report of a benchmark from its indexed documents",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			r, err := report.NewReport(metricsDirectory, uuid)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			if output == "" {
+				extension := "html"
+				if format == report.FormatMarkdown {
+					extension = "md"
+				}
+				output = fmt.Sprintf("kube-burner-report-%s.%s", uuid, extension)
+			}
+			f, err := os.Create(output)
+			if err != nil {
+				log.Fatalf("Error creating report file: %v", err)
+			}
+			defer f.Close()
+			if err := r.Render(f, format); err != nil {
+				log.Fatalf("Error rendering report: %v", err)
+			}
+			log.Infof("Report %s generated", output)
+		},
+	}
+	cmd.Flags().StringVar(&uuid, "uuid", "", "Benchmark UUID")
+	cmd.Flags().StringVar(&metricsDirectory, "metrics-directory", "collected-metrics", "Directory holding the documents indexed by the local indexer")
+	cmd.Flags().StringVarP(&format, "format", "f", report.FormatHTML, "Report format: html or markdown")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Report file, kube-burner-report-<uuid>.<html|md> by default")
+	cmd.MarkFlagRequired("uuid")
+	cmd.Flags().SortFlags = false
+	return cmd
+}
+
+func pruneCmd() *cobra.Command {
+	var esServer, esIndex, osServer, osIndex, metricsDirectory, olderThan string
+	var opts metrics.PruneOptions
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete the documents of old benchmark runs from the indexer store",
+		Long:  "Delete the documents of the runs started before the retention period from an Elasticsearch or OpenSearch index, or the run directories of a local metrics directory",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			var err error
+			if opts.OlderThan, err = metrics.ParseRetention(olderThan); err != nil {
+				log.Fatalf("Invalid --older-than value: %v", err)
+			}
+			indexerConfig, ok := remoteIndexerConfig(esServer, esIndex, osServer, osIndex)
+			if !ok {
+				if metricsDirectory == "" {
+					log.Fatal("An index or a metrics directory is required")
+				}
+				indexerConfig = config.IndexerConfig{IndexerConfig: indexers.IndexerConfig{
+					Type:             indexers.LocalIndexer,
+					MetricsDirectory: metricsDirectory,
+				}}
+			}
+			runs, err := metrics.Prune(indexerConfig, opts)
+			if len(runs) > 0 {
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "UUID\tTIMESTAMP\tWORKLOAD\tDOCUMENTS\tLOCATION")
+				for _, run := range runs {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", run.UUID, run.Timestamp.Format(time.RFC3339), run.Workload, run.Documents, run.Location)
+				}
+				w.Flush()
+			}
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			if opts.DryRun {
+				log.Infof("Dry run: %d runs would be pruned", len(runs))
+			} else {
+				log.Infof("%d runs pruned", len(runs))
+			}
+		},
+	}
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "Prune the runs started before this retention period, e.g. 90d, 2w or 12h")
+	cmd.Flags().StringVar(&opts.Workload, "workload", "", "Only prune the runs of this workload, matched against the workload metadata field or the job names")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "List the runs to prune without deleting them")
+	cmd.Flags().StringVar(&esServer, "es-server", "", "Elastic Search endpoint")
+	cmd.Flags().StringVar(&esIndex, "es-index", "", "Elastic Search index")
+	cmd.Flags().StringVar(&osServer, "os-server", "", "OpenSearch endpoint")
+	cmd.Flags().StringVar(&osIndex, "os-index", "", "OpenSearch index")
+	cmd.Flags().StringVar(&metricsDirectory, "metrics-directory", "", "Directory holding the run directories of the local indexer")
+	cmd.MarkFlagRequired("older-than")
+	cmd.Flags().SortFlags = false
+	return cmd
+}
+
+func renderCmd() *cobra.Command {
+	var configFile, userDataFile, kubeConfig, kubeContext string
+	var allowMissingKeys, strict bool
+	var opts burner.RenderOptions
+	cmd := &cobra.Command{
+		Use:   "render",
+		Short: "Render the objects of the create jobs without creating them",
+		Long:  "Render the object templates of the create jobs for every iteration, or a sample of them, and print them or write them to a directory. The cluster is only accessed for server-side dry-run validation",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, userData := readConfig(configFile, userDataFile)
+			configSpec := parseConfig(cfg, userData, allowMissingKeys)
+			if strict {
+				configSpec.GlobalConfig.Strict = true
+			}
+			var kubeClientProvider *config.KubeClientProvider
+			if opts.DryRun {
+				kubeClientProvider = config.NewKubeClientProvider(kubeConfig, kubeContext)
+			}
+			if err := burner.Render(configSpec, kubeClientProvider, nil, opts); err != nil {
+				log.Fatal(err.Error())
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file path or URL")
+	cmd.Flags().StringVar(&userDataFile, "user-data", "", "User provided data file for rendering the configuration file, in JSON or YAML format")
+	cmd.Flags().BoolVar(&allowMissingKeys, "allow-missing", false, "Do not fail on missing values in the config file")
+	cmd.Flags().IntVar(&opts.Sample, "sample", 0, "Number of evenly spaced iterations rendered per job, all of them by default")
+	cmd.Flags().StringVarP(&opts.OutputDir, "output-dir", "o", "", "Write the objects of each job to <output-dir>/<job>.yml instead of stdout")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Validate the rendered objects with server-side dry-run requests")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail the dry-run requests of objects with unknown or duplicated fields")
+	cmd.Flags().StringVar(&kubeConfig, "kubeconfig", "", "Path to the kubeconfig file")
+	cmd.Flags().StringVar(&kubeContext, "kube-context", "", "The name of the kubeconfig context to use")
+	cmd.MarkFlagRequired("config")
+	cmd.Flags().SortFlags = false
+	return cmd
+}
+
+func estimateCmd() *cobra.Command {
+	var configFile, userDataFile, format string
+	var allowMissingKeys bool
+	cmd := &cobra.Command{
+		Use:   "estimate",
+		Short: "Estimate the duration and API load of a benchmark without running it",
+		Long:  "Estimate the duration, API requests by verb, etcd writes and peak number of watches of each job from the configuration. The cluster is not accessed, time spent waiting for objects to be ready is not included",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if format != "text" && format != "json" {
+				log.Fatalf("Invalid format %s, valid formats are text and json", format)
+			}
+			cfg, userData := readConfig(configFile, userDataFile)
+			configSpec := parseConfig(cfg, userData, allowMissingKeys)
+			estimate := burner.EstimateBenchmark(configSpec, nil)
+			if format == "json" {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(estimate); err != nil {
+					log.Fatal(err.Error())
+				}
+				return
+			}
+			estimate.Write(os.Stdout)
+		},
+	}
+	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file path or URL")
+	cmd.Flags().StringVar(&userDataFile, "user-data", "", "User provided data file for rendering the configuration file, in JSON or YAML format")
+	cmd.Flags().BoolVar(&allowMissingKeys, "allow-missing", false, "Do not fail on missing values in the config file")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text or json")
+	cmd.MarkFlagRequired("config")
+	cmd.Flags().SortFlags = false
+	return cmd
+}
+
+func describeCmd() *cobra.Command {
+	var configFile, userDataFile, kubeConfig, kubeContext, format string
+	var allowMissingKeys bool
+	cmd := &cobra.Command{
+		Use:   "describe",
+		Short: "Describe what a benchmark will exercise on the cluster",
+		Long:  "Cross-reference the configuration with the connected cluster, reporting the job types, objects, waits, measurements, indexers and metrics endpoints the benchmark will exercise, and why the rest are disabled. Nothing is created in the cluster and indexers are not contacted",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if format != "text" && format != "json" {
+				log.Fatalf("Invalid format %s, valid formats are text and json", format)
+			}
+			cfg, userData := readConfig(configFile, userDataFile)
+			configSpec := parseConfig(cfg, userData, allowMissingKeys)
+			description, err := burner.DescribeBenchmark(configSpec, config.NewKubeClientProvider(kubeConfig, kubeContext), nil)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			if format == "json" {
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(description); err != nil {
+					log.Fatal(err.Error())
+				}
+				return
+			}
+			description.Write(os.Stdout)
+		},
+	}
+	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file path or URL")
+	cmd.Flags().StringVar(&userDataFile, "user-data", "", "User provided data file for rendering the configuration file, in JSON or YAML format")
+	cmd.Flags().BoolVar(&allowMissingKeys, "allow-missing", false, "Do not fail on missing values in the config file")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text or json")
+	cmd.Flags().StringVar(&kubeConfig, "kubeconfig", "", "Path to the kubeconfig file")
+	cmd.Flags().StringVar(&kubeContext, "kube-context", "", "The name of the kubeconfig context to use")
+	cmd.MarkFlagRequired("config")
+	cmd.Flags().SortFlags = false
+	return cmd
+}
+
+func validateCmd() *cobra.Command {
+	var configFile, userDataFile string
+	var allowMissingKeys bool
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate a configuration file and its object templates",
+		Long:  "Check the configuration file against its JSON Schema, reporting unknown fields, type mismatches and mutually exclusive options, and verify that the object templates exist and render. The cluster is not accessed",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			cfg, userData := readConfig(configFile, userDataFile)
+			if err := config.ValidateSchema(bytes.NewReader(cfg), bytes.NewReader(userData), allowMissingKeys); err != nil {
+				log.Fatalf("Configuration file %s is not valid:\n%s", configFile, err)
+			}
+			configSpec := parseConfig(cfg, userData, allowMissingKeys)
+			if err := burner.ValidateTemplates(configSpec, nil); err != nil {
+				log.Fatalf("Object templates are not valid:\n%s", err)
+			}
+			log.Infof("Configuration file %s is valid 👍", configFile)
+		},
+	}
+	cmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file path or URL")
+	cmd.Flags().StringVar(&userDataFile, "user-data", "", "User provided data file for rendering the configuration file, in JSON or YAML format")
+	cmd.Flags().BoolVar(&allowMissingKeys, "allow-missing", false, "Do not fail on missing values in the config file")
+	cmd.MarkFlagRequired("config")
+	cmd.Flags().SortFlags = false
+	return cmd
+}
+
+// readConfig reads the configuration file and the user data file, when given, of the subcommands inspecting a benchmark
+// without running it
+func readConfig(configFile, userDataFile string) (cfg, userData []byte) {
+	cfg, err := readFile(configFile)
+	if err != nil {
+		log.Fatalf("Error reading configuration file %s: %s\nPlease ensure the file exists and is accessible", configFile, err)
+	}
+	if userDataFile != "" {
+		userData, err = readFile(userDataFile)
+		if err != nil {
+			log.Fatalf("Error reading user data file %s: %s\nPlease ensure the file exists and is accessible", userDataFile, err)
+		}
+	}
+	return cfg, userData
+}
+
+// parseConfig parses the configuration read by readConfig, with a random UUID and the default timeout
+func parseConfig(cfg, userData []byte, allowMissingKeys bool) config.Spec {
+	configSpec, err := config.ParseWithUserdata(uid.NewString(), 4*time.Hour, bytes.NewReader(cfg), bytes.NewReader(userData), allowMissingKeys, nil)
+	if err != nil {
+		log.Fatalf("Config error: %s", err.Error())
+	}
+	return configSpec
+}
+
+// readFile reads a local file or URL
+func readFile(path string) ([]byte, error) {
+	f, err := fileutils.GetWorkloadReader(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(f)
+}
+
+func dashboardsCmd() *cobra.Command {
+	var datasource, datasourceUID, output string
+	cmd := &cobra.Command{
+		Use:   "dashboards",
+		Short: "Generate a Grafana dashboard of the documents indexed by kube-burner",
+		Long:  "Generate a Grafana dashboard, ready to import, of the job summaries, pod latency quantiles and alerts indexed by kube-burner into Elasticsearch, OpenSearch or Prometheus",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			dashboard, err := dashboards.New(datasource, datasourceUID)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+			w := os.Stdout
+			if output != "" {
+				if w, err = os.Create(output); err != nil {
+					log.Fatalf("Error creating dashboard file: %v", err)
+				}
+				defer w.Close()
+			}
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(dashboard); err != nil {
+				log.Fatal(err.Error())
+			}
+			if output != "" {
+				log.Infof("Dashboard %s generated", output)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&datasource, "datasource", "", "Datasource type: elasticsearch, also for OpenSearch, or prometheus, fed by the remote write indexer")
+	cmd.Flags().StringVar(&datasourceUID, "datasource-uid", "", "UID of the Grafana datasource, Grafana asks for the datasource when importing the dashboard by default")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Dashboard file, stdout by default")
+	cmd.MarkFlagRequired("datasource")
+	cmd.Flags().SortFlags = false
+	return cmd
+}
+
+func schemaCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of the configuration file",
+		Long:  "Print the JSON Schema of the configuration file, it can be used by editors to validate and autocomplete configuration files",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(config.Schema()); err != nil {
+				log.Fatal(err.Error())
+			}
+		},
+	}
+}
+
+func controllerCmd() *cobra.Command {
+	var kubeConfig, kubeContext, namespace string
+	cmd := &cobra.Command{
+		Use:   "controller",
+		Short: "Run kube-burner as a controller executing KubeBurnerJob resources",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.SetupFileLogging("controller")
+			kubeClientProvider := config.NewKubeClientProvider(kubeConfig, kubeContext)
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			if err := controller.NewController(kubeClientProvider, namespace).Run(ctx); err != nil {
+				log.Fatal(err.Error())
+			}
+			log.Info("👋 Exiting kube-burner controller")
+		},
+	}
+	cmd.Flags().StringVar(&kubeConfig, "kubeconfig", "", "Path to the kubeconfig file")
+	cmd.Flags().StringVar(&kubeContext, "kube-context", "", "The name of the kubeconfig context to use")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace to watch for KubeBurnerJobs, all namespaces when empty")
+	return cmd
+}
+
 // executes rootCmd
 func main() {
 	util.SetupCmd(rootCmd)
@@ -569,6 +1231,16 @@ func main() {
 		indexCmd(),
 		alertCmd(),
 		importCmd(),
+		controllerCmd(),
+		reportCmd(),
+		dashboardsCmd(),
+		pruneCmd(),
+		runsCmd(),
+		renderCmd(),
+		estimateCmd(),
+		describeCmd(),
+		validateCmd(),
+		schemaCmd(),
 		completionCmd,
 	)
 	if err := rootCmd.Execute(); err != nil {
@@ -29,6 +29,7 @@ import (
 	"github.com/kube-burner/kube-burner/pkg/prometheus"
 	"github.com/kube-burner/kube-burner/pkg/util"
 	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	"github.com/kube-burner/kube-burner/pkg/util/junit"
 	"github.com/prometheus/common/model"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
@@ -45,6 +46,9 @@ const (
 	rcAlert                       = 3
 )
 
+// ErrCriticalAlert is returned when a critical alert fires while the alerts are evaluated during the benchmark
+var ErrCriticalAlert = errors.New("critical alert")
+
 // alertProfile expression list
 type alertProfile []struct {
 	// PromQL expression to evaluate
@@ -57,13 +61,15 @@ type alertProfile []struct {
 
 // alert definition
 type alert struct {
-	Timestamp   time.Time     `json:"timestamp"`
-	UUID        string        `json:"uuid"`
-	Severity    severityLevel `json:"severity"`
-	Description string        `json:"description"`
-	MetricName  string        `json:"metricName"`
-	ChurnMetric bool          `json:"churnMetric,omitempty"`
-	Metadata    any           `json:"metadata,omitempty"`
+	Timestamp   time.Time         `json:"timestamp"`
+	UUID        string            `json:"uuid"`
+	Severity    severityLevel     `json:"severity"`
+	Description string            `json:"description"`
+	Value       float64           `json:"value"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	MetricName  string            `json:"metricName"`
+	ChurnMetric bool              `json:"churnMetric,omitempty"`
+	Metadata    any               `json:"metadata,omitempty"`
 }
 
 // AlertManager configuration
@@ -74,6 +80,7 @@ type AlertManager struct {
 	uuid         string
 	metadata     any
 	embedCfg     *fileutils.EmbedConfiguration
+	notifiers    []notifier
 }
 
 var baseTemplate = []string{
@@ -142,10 +149,56 @@ func (a *AlertManager) Evaluate(job prometheus.Job) error {
 			continue
 		}
 		alertData, err := parseMatrix(v, a.uuid, alert.Description, a.metadata, alert.Severity, job.ChurnStart, job.ChurnEnd)
+		a.notify(job.JobConfig.Name, expr, alert.Severity, alertData)
+		if errors.Is(err, ErrCriticalAlert) {
+			os.Exit(rcAlert)
+		}
 		if err != nil {
 			log.Error(err.Error())
 			errs = append(errs, err)
 		}
+		testCase := alert.Description
+		if job.JobConfig.Name != "" {
+			testCase = fmt.Sprintf("%s: %s", job.JobConfig.Name, alert.Description)
+		}
+		junit.AddTestCase(junit.SuiteAlerts, testCase, 0, err)
+		alertList = append(alertList, alertData...)
+	}
+	if len(alertList) > 0 && a.indexer != nil {
+		a.index(alertList)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// EvaluateCritical evaluates the critical expressions from start to end while the benchmark is running, the elapsed
+// variable holds the time since the benchmark started at since. Returns an error wrapping ErrCriticalAlert when any of
+// them fires
+func (a *AlertManager) EvaluateCritical(since, start, end time.Time) error {
+	var alertList []any
+	var errs []error
+	var renderedQuery bytes.Buffer
+	vars := util.EnvToMap()
+	vars["elapsed"] = fmt.Sprintf("%dm", max(int(end.Sub(since).Minutes()), 1))
+	for _, alert := range a.alertProfile {
+		if alert.Severity != sevCritical {
+			continue
+		}
+		t, _ := template.New("").Parse(alert.Expr)
+		t.Execute(&renderedQuery, vars)
+		expr := renderedQuery.String()
+		renderedQuery.Reset()
+		log.Debugf("Evaluating expression: '%s'", expr)
+		v, err := a.prometheus.Client.QueryRange(expr, start, end, a.prometheus.Step)
+		if err != nil {
+			log.Warnf("Error performing query %s: %s", expr, err)
+			continue
+		}
+		alertData, err := parseMatrix(v, a.uuid, alert.Description, a.metadata, alert.Severity, nil, nil)
+		a.notify("", expr, alert.Severity, alertData)
+		if err != nil {
+			errs = append(errs, err)
+			junit.AddTestCase(junit.SuiteAlerts, alert.Description, 0, err)
+		}
 		alertList = append(alertList, alertData...)
 	}
 	if len(alertList) > 0 && a.indexer != nil {
@@ -154,6 +207,16 @@ func (a *AlertManager) Evaluate(job prometheus.Job) error {
 	return utilerrors.NewAggregate(errs)
 }
 
+// HasCritical returns whether the alert profile has critical expressions
+func (a *AlertManager) HasCritical() bool {
+	for _, alert := range a.alertProfile {
+		if alert.Severity == sevCritical {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *AlertManager) validateTemplates() error {
 	for _, a := range a.alertProfile {
 		if _, err := template.New("").Parse(strings.Join(append(baseTemplate, a.Description), "")); err != nil {
@@ -195,6 +258,8 @@ func parseMatrix(value model.Value, uuid, description string, metadata any, seve
 				Timestamp:   val.Timestamp.Time().UTC(),
 				Severity:    severity,
 				Description: renderedDesc.String(),
+				Value:       templateData.Value,
+				Labels:      templateData.Labels,
 				MetricName:  alertMetricName,
 			}
 			if churnStart != nil && alert.Timestamp.After(*churnStart) && alert.Timestamp.Before(*churnEnd) {
@@ -208,7 +273,7 @@ func parseMatrix(value model.Value, uuid, description string, metadata any, seve
 				errs = append(errs, errors.New(msg))
 			case sevCritical:
 				log.Errorf("🚨 %s", msg)
-				os.Exit(rcAlert)
+				errs = append(errs, fmt.Errorf("%w: %s", ErrCriticalAlert, msg))
 			default:
 				log.Infof("🚨 %s", msg)
 			}
@@ -0,0 +1,171 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	notificationTimeout = 10 * time.Second
+	pagerDutyEventsURL  = "https://events.pagerduty.com/v2/enqueue"
+	// Samples listed in the Slack messages, the rest are only counted
+	slackMaxSamples = 10
+)
+
+// notification payload of the generic webhooks, sent for each expression that fired
+type notification struct {
+	UUID       string        `json:"uuid"`
+	JobName    string        `json:"jobName,omitempty"`
+	Expression string        `json:"expression"`
+	Severity   severityLevel `json:"severity"`
+	Samples    []alert       `json:"samples"`
+}
+
+type notifier struct {
+	config.AlertNotification
+}
+
+// SetNotifications sets the destinations notified of the fired alerts
+func (a *AlertManager) SetNotifications(notifications []config.AlertNotification) {
+	a.notifiers = nil
+	for _, n := range notifications {
+		a.notifiers = append(a.notifiers, notifier{AlertNotification: n})
+	}
+}
+
+// notify sends the alerts fired by an expression to the notification destinations, failures are only logged
+func (a *AlertManager) notify(jobName, expr string, severity severityLevel, alerts []any) {
+	if len(alerts) == 0 || len(a.notifiers) == 0 {
+		return
+	}
+	n := notification{
+		UUID:       a.uuid,
+		JobName:    jobName,
+		Expression: expr,
+		Severity:   severity,
+	}
+	for _, al := range alerts {
+		sample := al.(alert)
+		sample.Metadata = nil
+		n.Samples = append(n.Samples, sample)
+	}
+	for _, nt := range a.notifiers {
+		if len(nt.Severities) > 0 && !slices.Contains(nt.Severities, severity.String()) {
+			continue
+		}
+		if err := nt.send(n); err != nil {
+			log.Warnf("Error sending %s alert notification: %v", nt.Type, err)
+		}
+	}
+}
+
+// String returns the severity, info when not set
+func (s severityLevel) String() string {
+	if s == "" {
+		return "info"
+	}
+	return string(s)
+}
+
+func (nt notifier) send(n notification) error {
+	url := nt.URL
+	var payload any
+	switch nt.Type {
+	case config.AlertNotificationSlack:
+		payload = map[string]string{"text": slackMessage(n)}
+	case config.AlertNotificationPagerDuty:
+		if url == "" {
+			url = pagerDutyEventsURL
+		}
+		payload = pagerDutyEvent(nt.RoutingKey, n)
+	default:
+		payload = n
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if nt.Type == config.AlertNotificationWebhook {
+		for k, v := range nt.Headers {
+			req.Header.Set(k, v)
+		}
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+func slackMessage(n notification) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, ":rotating_light: *%s* alert fired in kube-burner run `%s`", n.Severity, n.UUID)
+	if n.JobName != "" {
+		fmt.Fprintf(&sb, ", job `%s`", n.JobName)
+	}
+	fmt.Fprintf(&sb, "\n```%s```", n.Expression)
+	for i, sample := range n.Samples {
+		if i == slackMaxSamples {
+			fmt.Fprintf(&sb, "\n… and %d more", len(n.Samples)-slackMaxSamples)
+			break
+		}
+		fmt.Fprintf(&sb, "\n• %s: %s", sample.Timestamp.Format(time.RFC3339), sample.Description)
+	}
+	return sb.String()
+}
+
+// pagerDutyEvent returns an Events API v2 trigger event, deduplicated by run and expression so that repeated
+// evaluations update the same incident
+func pagerDutyEvent(routingKey string, n notification) map[string]any {
+	summary := n.Samples[0].Description
+	if len(n.Samples) > 1 {
+		summary = fmt.Sprintf("%s (and %d more)", summary, len(n.Samples)-1)
+	}
+	return map[string]any{
+		"routing_key":  routingKey,
+		"event_action": "trigger",
+		"dedup_key":    fmt.Sprintf("kube-burner-%s-%x", n.UUID, sha256.Sum256([]byte(n.Expression)))[:64],
+		"payload": map[string]any{
+			"summary":        fmt.Sprintf("kube-burner %s: %s", n.UUID, summary),
+			"source":         "kube-burner",
+			"severity":       n.Severity.String(),
+			"timestamp":      n.Samples[0].Timestamp.Format(time.RFC3339),
+			"custom_details": n,
+		},
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	"github.com/prometheus/common/model"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+const (
+	inqueueRequestsQuery = "sum(apiserver_flowcontrol_current_inqueue_requests)"
+	requestLatencyQuery  = `histogram_quantile(0.99, sum(rate(apiserver_request_duration_seconds_bucket{verb=~"POST|PUT|PATCH|DELETE"}[1m])) by (le))`
+)
+
+// adaptiveQPS lowers the QPS of the job limiter when the API server shows signs of overload, and raises it
+// back to the job QPS once it recovers
+type adaptiveQPS struct {
+	config.AdaptiveQPS
+	targetQPS float64
+	limiter   *rate.Limiter
+	// 429 responses received by the job clients
+	throttledRequests atomic.Int64
+	prometheus        *prometheus.Prometheus
+	events            []prometheus.ThrottlingEvent
+	stopCh            chan struct{}
+	doneCh            chan struct{}
+}
+
+// newAdaptiveQPS returns the controller of the job, wrapping the client transport to account the 429 responses.
+// It returns nil when the job has no adaptive QPS
+func (ex *JobExecutor) newAdaptiveQPS() *adaptiveQPS {
+	if ex.AdaptiveQPS == nil {
+		return nil
+	}
+	a := &adaptiveQPS{
+		AdaptiveQPS: *ex.AdaptiveQPS,
+		targetQPS:   float64(ex.QPS),
+		limiter:     ex.limiter,
+	}
+	if a.IncreaseStep == 0 {
+		a.IncreaseStep = ex.QPS / 10
+	}
+	ex.restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &throttleCounter{rt: rt, count: &a.throttledRequests}
+	})
+	return a
+}
+
+// start starts evaluating the API server health, the Prometheus thresholds are ignored without Prometheus clients
+func (a *adaptiveQPS) start(jobName string, prometheusClients []*prometheus.Prometheus) {
+	if a == nil {
+		return
+	}
+	if len(prometheusClients) > 0 {
+		a.prometheus = prometheusClients[0]
+	} else if a.MaxInqueueRequests > 0 || a.MaxRequestLatency > 0 {
+		log.Warnf("Job %s: no Prometheus endpoint configured, adaptive QPS only reacts to throttled requests", jobName)
+	}
+	a.events = nil
+	a.stopCh = make(chan struct{})
+	a.doneCh = make(chan struct{})
+	go a.run()
+}
+
+func (a *adaptiveQPS) run() {
+	defer close(a.doneCh)
+	ticker := time.NewTicker(a.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.stopCh:
+			return
+		case <-ticker.C:
+			a.evaluate()
+		}
+	}
+}
+
+// evaluate adjusts the limiter QPS following an additive increase, multiplicative decrease policy
+func (a *adaptiveQPS) evaluate() {
+	qps := float64(a.limiter.Limit())
+	reason, value, threshold := a.overload()
+	if reason == "" {
+		if qps < a.targetQPS {
+			a.limiter.SetLimit(rate.Limit(math.Min(qps+float64(a.IncreaseStep), a.targetQPS)))
+		}
+		return
+	}
+	newQPS := math.Max(qps*a.DecreaseFactor, float64(a.MinQPS))
+	log.Warnf("API server overloaded, %s %v reached the %v threshold: QPS %.2f, previously %.2f", reason, value, threshold, newQPS, qps)
+	a.limiter.SetLimit(rate.Limit(newQPS))
+	a.events = append(a.events, prometheus.ThrottlingEvent{
+		Timestamp:   time.Now().UTC(),
+		Reason:      reason,
+		Value:       value,
+		Threshold:   threshold,
+		PreviousQPS: math.Round(qps*1000) / 1000,
+		QPS:         math.Round(newQPS*1000) / 1000,
+	})
+}
+
+// overload returns the first health signal exceeding its threshold, if any
+func (a *adaptiveQPS) overload() (string, float64, float64) {
+	if throttled := a.throttledRequests.Swap(0); throttled >= int64(a.MaxThrottledRequests) {
+		return "throttledRequests", float64(throttled), float64(a.MaxThrottledRequests)
+	}
+	if a.prometheus == nil {
+		return "", 0, 0
+	}
+	if a.MaxInqueueRequests > 0 {
+		if inqueue, err := a.query(inqueueRequestsQuery); err != nil {
+			log.Warnf("Error querying in-queue requests: %v", err)
+		} else if inqueue >= a.MaxInqueueRequests {
+			return "inqueueRequests", inqueue, a.MaxInqueueRequests
+		}
+	}
+	if a.MaxRequestLatency > 0 {
+		if latency, err := a.query(requestLatencyQuery); err != nil {
+			log.Warnf("Error querying request latency: %v", err)
+		} else if latency >= a.MaxRequestLatency.Seconds() {
+			return "requestLatency", latency, a.MaxRequestLatency.Seconds()
+		}
+	}
+	return "", 0, 0
+}
+
+// query returns the value of a single sample instant query
+func (a *adaptiveQPS) query(query string) (float64, error) {
+	return queryValue(a.prometheus, query)
+}
+
+// queryValue returns the value of the first sample of an instant query, 0 when there are none
+func queryValue(p *prometheus.Prometheus, query string) (float64, error) {
+	v, err := p.Client.Query(query, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	vector, ok := v.(model.Vector)
+	if !ok {
+		return 0, fmt.Errorf("unexpected result type %s", v.Type())
+	}
+	if len(vector) == 0 || math.IsNaN(float64(vector[0].Value)) {
+		return 0, nil
+	}
+	return float64(vector[0].Value), nil
+}
+
+// stop stops the controller and returns the throttling events recorded, the job limiter is restored to the job QPS
+func (a *adaptiveQPS) stop() []prometheus.ThrottlingEvent {
+	if a == nil || a.stopCh == nil {
+		return nil
+	}
+	close(a.stopCh)
+	<-a.doneCh
+	a.stopCh = nil
+	a.limiter.SetLimit(rate.Limit(a.targetQPS))
+	return a.events
+}
+
+// throttleCounter counts the 429 responses, including those retried by the client
+type throttleCounter struct {
+	rt    http.RoundTripper
+	count *atomic.Int64
+}
+
+func (t *throttleCounter) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.rt.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		t.count.Add(1)
+	}
+	return resp, err
+}
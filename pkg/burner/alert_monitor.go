@@ -0,0 +1,127 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/alerting"
+	log "github.com/sirupsen/logrus"
+)
+
+// alertMonitor evaluates the critical alerts on an interval during the benchmark, each evaluation covering the time
+// since the previous one, and aborts the benchmark when any of them fires
+type alertMonitor struct {
+	interval time.Duration
+	alertMs  []*alerting.AlertManager
+	// cancel cancels the context of the benchmark when it's aborted
+	cancel   context.CancelFunc
+	start    time.Time
+	last     time.Time
+	abortCh  chan struct{}
+	abortErr error
+	stopCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// startAlertMonitor starts evaluating the critical alerts, returns nil when not configured or there are no critical
+// alerts to evaluate
+func startAlertMonitor(interval time.Duration, alertMs []*alerting.AlertManager, cancel context.CancelFunc) *alertMonitor {
+	if interval == 0 {
+		return nil
+	}
+	am := &alertMonitor{
+		interval: interval,
+		cancel:   cancel,
+		start:    time.Now().UTC(),
+		abortCh:  make(chan struct{}),
+		stopCh:   make(chan struct{}),
+	}
+	for _, alertM := range alertMs {
+		if alertM.HasCritical() {
+			am.alertMs = append(am.alertMs, alertM)
+		}
+	}
+	if len(am.alertMs) == 0 {
+		log.Warn("alertInterval is set but there are no critical alerts to evaluate during the benchmark")
+		return nil
+	}
+	am.last = am.start
+	log.Infof("Evaluating critical alerts every %v during the benchmark", interval)
+	am.wg.Add(1)
+	go am.run()
+	return am
+}
+
+func (am *alertMonitor) run() {
+	defer am.wg.Done()
+	ticker := time.NewTicker(am.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-am.stopCh:
+			return
+		case <-ticker.C:
+			if am.evaluate() {
+				return
+			}
+		}
+	}
+}
+
+// evaluate evaluates the critical alerts since the previous evaluation, returns whether the benchmark was aborted
+func (am *alertMonitor) evaluate() bool {
+	now := time.Now().UTC()
+	for _, alertM := range am.alertMs {
+		err := alertM.EvaluateCritical(am.start, am.last, now)
+		if errors.Is(err, alerting.ErrCriticalAlert) {
+			am.abortErr = fmt.Errorf("aborting the benchmark: %v", err)
+			log.Error(am.abortErr.Error())
+			close(am.abortCh)
+			am.cancel()
+			return true
+		} else if err != nil {
+			log.Warnf("Error evaluating critical alerts: %v", err)
+		}
+	}
+	am.last = now
+	return false
+}
+
+// aborted returns a channel closed when the benchmark is aborted, nil when not configured
+func (am *alertMonitor) aborted() <-chan struct{} {
+	if am == nil {
+		return nil
+	}
+	return am.abortCh
+}
+
+// abortError returns the critical alerts that aborted the benchmark, it's only set once aborted is closed
+func (am *alertMonitor) abortError() error {
+	return am.abortErr
+}
+
+// stop stops evaluating the alerts
+func (am *alertMonitor) stop() {
+	if am == nil {
+		return
+	}
+	close(am.stopCh)
+	am.wg.Wait()
+}
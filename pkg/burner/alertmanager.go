@@ -0,0 +1,240 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+)
+
+const alertmanagerAlertsPath = "api/v2/alerts"
+
+// alertmanagerPoller polls the alerts of the Alertmanager of the cluster during the benchmark, recording the
+// periods they're firing, so that the alerts defined by the platform are indexed along with kube-burner's own
+type alertmanagerPoller struct {
+	config    config.Alertmanager
+	clientSet kubernetes.Interface
+	client    *http.Client
+	start     time.Time
+	mu        sync.Mutex
+	// open alerts by fingerprint
+	open   map[string]*prometheus.AlertmanagerAlert
+	alerts []*prometheus.AlertmanagerAlert
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// gettableAlert alert returned by the Alertmanager API v2
+type gettableAlert struct {
+	Fingerprint string            `json:"fingerprint"`
+	StartsAt    time.Time         `json:"startsAt"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	Status      struct {
+		State string `json:"state"`
+	} `json:"status"`
+}
+
+// startAlertmanagerPoller starts polling the Alertmanager, returns nil when not configured
+func startAlertmanagerPoller(alertmanagerConfig *config.Alertmanager, kubeClientProvider *config.KubeClientProvider) *alertmanagerPoller {
+	if alertmanagerConfig == nil {
+		return nil
+	}
+	ap := &alertmanagerPoller{
+		config: *alertmanagerConfig,
+		start:  time.Now().UTC(),
+		open:   make(map[string]*prometheus.AlertmanagerAlert),
+		stopCh: make(chan struct{}),
+	}
+	if ap.config.Service != "" {
+		ap.clientSet, _ = kubeClientProvider.DefaultClientSet()
+		log.Infof("Polling the alerts of Alertmanager service %s every %v", ap.config.Service, ap.config.Interval)
+	} else {
+		ap.client = &http.Client{
+			Timeout:   ap.config.Interval,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: ap.config.SkipTLSVerify}},
+		}
+		log.Infof("Polling the alerts of Alertmanager %s every %v", ap.config.URL, ap.config.Interval)
+	}
+	ap.poll()
+	ap.wg.Add(1)
+	go ap.run()
+	return ap
+}
+
+func (ap *alertmanagerPoller) run() {
+	defer ap.wg.Done()
+	ticker := time.NewTicker(ap.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ap.stopCh:
+			return
+		case <-ticker.C:
+			ap.poll()
+		}
+	}
+}
+
+// poll gets the active alerts, silenced and inhibited ones included, and updates the recorded ones
+func (ap *alertmanagerPoller) poll() {
+	active, err := ap.getAlerts()
+	if err != nil {
+		log.Warnf("Alertmanager: error getting alerts: %v", err)
+		return
+	}
+	now := time.Now().UTC()
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+	seen := make(map[string]bool)
+	for _, ga := range active {
+		seen[ga.Fingerprint] = true
+		if alert, ok := ap.open[ga.Fingerprint]; ok {
+			alert.State = ga.Status.State
+			continue
+		}
+		alert := &prometheus.AlertmanagerAlert{
+			Timestamp:   ga.StartsAt.UTC(),
+			Fingerprint: ga.Fingerprint,
+			AlertName:   ga.Labels["alertname"],
+			Severity:    ga.Labels["severity"],
+			State:       ga.Status.State,
+			Labels:      ga.Labels,
+			Annotations: ga.Annotations,
+			PreExisting: ga.StartsAt.Before(ap.start),
+		}
+		if !alert.PreExisting {
+			log.Warnf("Alertmanager: alert %s firing %v", alert.AlertName, ga.Labels)
+		}
+		ap.open[ga.Fingerprint] = alert
+		ap.alerts = append(ap.alerts, alert)
+	}
+	// Alerts resolved since the previous poll
+	for fingerprint, alert := range ap.open {
+		if !seen[fingerprint] {
+			log.Infof("Alertmanager: alert %s resolved after %v", alert.AlertName, now.Sub(alert.Timestamp).Round(time.Second))
+			alert.EndTimestamp = &now
+			delete(ap.open, fingerprint)
+		}
+	}
+}
+
+func (ap *alertmanagerPoller) getAlerts() ([]gettableAlert, error) {
+	params := url.Values{}
+	params.Set("active", "true")
+	params.Set("silenced", "true")
+	params.Set("inhibited", "true")
+	for _, filter := range ap.config.Filter {
+		params.Add("filter", filter)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ap.config.Interval)
+	defer cancel()
+	var body []byte
+	var err error
+	if ap.clientSet != nil {
+		namespace, nameAndPort, _ := strings.Cut(ap.config.Service, "/")
+		name, port, _ := strings.Cut(nameAndPort, ":")
+		// The proxy takes a single value per parameter
+		proxyParams := make(map[string]string)
+		for key := range params {
+			proxyParams[key] = params.Get(key)
+		}
+		body, err = ap.clientSet.CoreV1().Services(namespace).ProxyGet("", name, port, alertmanagerAlertsPath, proxyParams).DoRaw(ctx)
+	} else {
+		body, err = ap.get(ctx, params)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var alerts []gettableAlert
+	if err := json.Unmarshal(body, &alerts); err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+	return alerts, nil
+}
+
+func (ap *alertmanagerPoller) get(ctx context.Context, params url.Values) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(ap.config.URL, "/")+"/"+alertmanagerAlertsPath+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if ap.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+ap.config.Token)
+	}
+	resp, err := ap.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", ap.config.URL, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// stop stops polling the Alertmanager
+func (ap *alertmanagerPoller) stop() {
+	if ap == nil {
+		return
+	}
+	close(ap.stopCh)
+	ap.wg.Wait()
+}
+
+// attribute sets the alerts firing during each job, the duration of the alerts still firing is accounted until the
+// end of the job
+func (ap *alertmanagerPoller) attribute(jobs []prometheus.Job) {
+	if ap == nil {
+		return
+	}
+	ap.mu.Lock()
+	defer ap.mu.Unlock()
+	for i, job := range jobs {
+		end := job.End
+		if end.IsZero() {
+			end = time.Now().UTC()
+		}
+		var alerts []prometheus.AlertmanagerAlert
+		for _, alert := range ap.alerts {
+			if alert.Timestamp.After(end) || (alert.EndTimestamp != nil && alert.EndTimestamp.Before(job.Start)) {
+				continue
+			}
+			jobAlert := *alert
+			if jobAlert.EndTimestamp != nil {
+				jobAlert.Duration = jobAlert.EndTimestamp.Sub(jobAlert.Timestamp).Milliseconds()
+			} else {
+				jobAlert.Duration = end.Sub(jobAlert.Timestamp).Milliseconds()
+			}
+			alerts = append(alerts, jobAlert)
+		}
+		if len(alerts) > 0 {
+			log.Warnf("Job %s: %d Alertmanager alerts firing", job.JobConfig.Name, len(alerts))
+		}
+		jobs[i].AlertmanagerAlerts = alerts
+	}
+}
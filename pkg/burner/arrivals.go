@@ -0,0 +1,174 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// arrivalScheduler schedules the creations of open-loop jobs. Arrival times are computed from the start of the job,
+// hence slow requests don't reduce the offered load: arrivals exceeding the in-flight requests limit are queued,
+// and dropped once the queue is full
+type arrivalScheduler struct {
+	config.ArrivalRate
+	rng *rand.Rand
+	mu  sync.Mutex
+	// start of the current creation phase and time spent in the previous ones
+	start   time.Time
+	elapsed time.Duration
+	// next and last arrival times
+	next     time.Time
+	last     time.Time
+	inFlight int
+	// queue of the arrivals waiting for a request slot, closed when the slot is handed over
+	queue     []chan struct{}
+	queueWait time.Duration
+	stats     prometheus.ArrivalStats
+}
+
+func newArrivalScheduler(arrivalRate config.ArrivalRate) *arrivalScheduler {
+	return &arrivalScheduler{
+		ArrivalRate: arrivalRate,
+		rng:         rand.New(rand.NewSource(time.Now().UnixNano())),
+		stats: prometheus.ArrivalStats{
+			Distribution: string(arrivalRate.Distribution),
+			Rate:         arrivalRate.Rate,
+		},
+	}
+}
+
+// begin starts a creation phase, like the initial creation or a churn cycle. Arrivals are scheduled
+// from its start, so that the time between phases doesn't trigger a burst of late arrivals
+func (a *arrivalScheduler) begin() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.last.After(a.start) {
+		a.elapsed += a.last.Sub(a.start)
+	}
+	a.start = time.Now()
+	a.next = a.start
+}
+
+// wait blocks until the next arrival
+func (a *arrivalScheduler) wait(ctx context.Context) error {
+	a.mu.Lock()
+	arrival := a.next
+	a.next = a.next.Add(a.interArrival())
+	a.mu.Unlock()
+	timer := time.NewTimer(time.Until(arrival))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+	a.mu.Lock()
+	a.last = time.Now()
+	a.mu.Unlock()
+	return nil
+}
+
+// interArrival returns the time until the next arrival
+func (a *arrivalScheduler) interArrival() time.Duration {
+	mean := float64(time.Second) / a.Rate
+	if a.Distribution == config.ArrivalPoisson {
+		return time.Duration(a.rng.ExpFloat64() * mean)
+	}
+	return time.Duration(mean)
+}
+
+// admit accounts an arrival, it returns false when the arrival is dropped. Queued arrivals get a channel
+// closed once they're given a request slot, admitted arrivals a nil one
+func (a *arrivalScheduler) admit() (<-chan struct{}, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stats.Arrivals++
+	if a.MaxInFlight == 0 || a.inFlight < a.MaxInFlight {
+		a.inFlight++
+		a.stats.MaxInFlight = max(a.stats.MaxInFlight, a.inFlight)
+		return nil, true
+	}
+	if len(a.queue) < a.MaxQueued {
+		slot := make(chan struct{})
+		a.queue = append(a.queue, slot)
+		a.stats.Queued++
+		a.stats.MaxQueued = max(a.stats.MaxQueued, len(a.queue))
+		return slot, true
+	}
+	a.stats.Dropped++
+	log.Debugf("Arrival dropped: %d requests in flight and %d queued", a.inFlight, len(a.queue))
+	return nil, false
+}
+
+// acquire waits until a queued arrival gets its request slot
+func (a *arrivalScheduler) acquire(ctx context.Context, slot <-chan struct{}) bool {
+	if slot == nil {
+		return true
+	}
+	start := time.Now()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-slot:
+	}
+	a.mu.Lock()
+	a.queueWait += time.Since(start)
+	a.mu.Unlock()
+	return true
+}
+
+// release frees a request slot, handing it over to the oldest queued arrival
+func (a *arrivalScheduler) release() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.queue) > 0 {
+		close(a.queue[0])
+		a.queue = a.queue[1:]
+		return
+	}
+	a.inFlight--
+}
+
+// summary returns the arrival stats of the job, it's nil-safe
+func (a *arrivalScheduler) summary() *prometheus.ArrivalStats {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	stats := a.stats
+	elapsed := a.elapsed
+	if a.last.After(a.start) {
+		elapsed += a.last.Sub(a.start)
+	}
+	if stats.Arrivals > 1 && elapsed > 0 {
+		stats.OfferedRate = math.Round(float64(stats.Arrivals-1)/elapsed.Seconds()*1000) / 1000
+	}
+	if stats.Queued > 0 {
+		stats.AvgQueueWait = (a.queueWait / time.Duration(stats.Queued)).Milliseconds()
+	}
+	if stats.Dropped > 0 {
+		log.Warnf("%d/%d arrivals were dropped, the in-flight requests and queue limits were reached", stats.Dropped, stats.Arrivals)
+	}
+	return &stats
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// Pods the artifacts are copied from in parallel
+const artifactsParallelism = 10
+
+// artifactFile file copied from a pod
+type artifactFile struct {
+	// path of the file in the container
+	path string
+	// localPath path of the copy
+	localPath string
+}
+
+// fetchArtifacts copies the artifacts of the job from its running pods into <directory>/<job>/<namespace>/<pod>, and
+// returns the documents parsed from them and the number of files copied. Errors are logged and don't fail the job
+func (ex *JobExecutor) fetchArtifacts(ctx context.Context, directory string) ([]prometheus.ArtifactDocument, int) {
+	if len(ex.Artifacts) == 0 {
+		return nil, 0
+	}
+	var documents []prometheus.ArtifactDocument
+	var files int
+	var mu sync.Mutex
+	namespaces := ex.jobNamespaces(ctx)
+	for _, artifact := range ex.Artifacts {
+		var pods []corev1.Pod
+		listOptions := metav1.ListOptions{LabelSelector: labels.SelectorFromSet(artifact.LabelSelector).String()}
+		for _, ns := range namespaces {
+			podList, err := ex.clientSet.CoreV1().Pods(ns).List(ctx, listOptions)
+			if err != nil {
+				log.Errorf("Job %s: error listing pods in namespace %s: %v", ex.Name, ns, err)
+				continue
+			}
+			for _, pod := range podList.Items {
+				if pod.Status.Phase == corev1.PodRunning {
+					pods = append(pods, pod)
+				}
+			}
+		}
+		log.Infof("Job %s: copying %s from %d pods", ex.Name, artifact.Path, len(pods))
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, artifactsParallelism)
+		for _, pod := range pods {
+			sem <- struct{}{}
+			wg.Add(1)
+			go func(pod corev1.Pod) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				container := artifact.Container
+				if container == "" {
+					container = pod.Spec.Containers[0].Name
+				}
+				copied, err := ex.copyArtifact(ctx, pod, container, artifact.Path, filepath.Join(directory, ex.Name, pod.Namespace, pod.Name))
+				if err != nil {
+					log.Errorf("Job %s: error copying %s from pod %s/%s: %v", ex.Name, artifact.Path, pod.Namespace, pod.Name, err)
+				}
+				var parsed []prometheus.ArtifactDocument
+				for _, file := range copied {
+					if artifact.Format == "" {
+						continue
+					}
+					data, err := parseArtifact(file.localPath, artifact.Format)
+					if err != nil {
+						log.Errorf("Job %s: error parsing %s from pod %s/%s: %v", ex.Name, file.path, pod.Namespace, pod.Name, err)
+						continue
+					}
+					for _, d := range data {
+						parsed = append(parsed, prometheus.ArtifactDocument{
+							Timestamp:  time.Now().UTC(),
+							Namespace:  pod.Namespace,
+							Pod:        pod.Name,
+							Container:  container,
+							Path:       file.path,
+							Data:       d,
+							MetricName: artifact.MetricName,
+						})
+					}
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				files += len(copied)
+				documents = append(documents, parsed...)
+			}(pod)
+		}
+		wg.Wait()
+	}
+	log.Infof("Job %s: %d artifact files copied into %s", ex.Name, files, filepath.Join(directory, ex.Name))
+	return documents, files
+}
+
+// copyArtifact copies a file or directory from a container into the destination directory, like kubectl cp does:
+// streaming a tar archive of it, so tar must be available in the container. Returns the files copied
+func (ex *JobExecutor) copyArtifact(ctx context.Context, pod corev1.Pod, container, artifactPath, destination string) ([]artifactFile, error) {
+	req := ex.clientSet.CoreV1().
+		RESTClient().
+		Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		SubResource("exec")
+	req.VersionedParams(&corev1.PodExecOptions{
+		Command:   []string{"tar", "cf", "-", "-C", path.Dir(artifactPath), path.Base(artifactPath)},
+		Container: container,
+		Stdout:    true,
+		Stderr:    true,
+	}, scheme.ParameterCodec)
+	exec, err := remotecommand.NewSPDYExecutor(ex.restConfig, "POST", req.URL())
+	if err != nil {
+		return nil, err
+	}
+	reader, writer := io.Pipe()
+	go func() {
+		var stderr bytes.Buffer
+		err := exec.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: writer, Stderr: &stderr})
+		if err != nil {
+			err = fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		writer.CloseWithError(err)
+	}()
+	files, err := untarArtifact(reader, path.Dir(artifactPath), destination)
+	// Unblock the stream when the archive couldn't be read to the end
+	reader.CloseWithError(err)
+	return files, err
+}
+
+// untarArtifact extracts the regular files of the archive into the destination directory, the entries escaping it
+// are skipped
+func untarArtifact(reader io.Reader, parent, destination string) ([]artifactFile, error) {
+	var files []artifactFile
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return files, err
+		}
+		if header.Typeflag != tar.TypeReg || !filepath.IsLocal(header.Name) {
+			continue
+		}
+		localPath := filepath.Join(destination, header.Name)
+		if err := os.MkdirAll(filepath.Dir(localPath), 0755); err != nil {
+			return files, err
+		}
+		f, err := os.Create(localPath)
+		if err != nil {
+			return files, err
+		}
+		_, err = io.Copy(f, tarReader)
+		f.Close()
+		if err != nil {
+			return files, err
+		}
+		files = append(files, artifactFile{path: path.Join(parent, header.Name), localPath: localPath})
+	}
+}
+
+// parseArtifact returns the JSON documents of a file: the elements of a JSON array, a single JSON document, or a
+// document per line
+func parseArtifact(localPath string, format config.ArtifactFormat) ([]any, error) {
+	content, err := os.ReadFile(localPath)
+	if err != nil {
+		return nil, err
+	}
+	var documents []any
+	if format == config.ArtifactJSONLines {
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		scanner.Buffer(nil, len(content)+1)
+		for line := 1; scanner.Scan(); line++ {
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+			var document any
+			if err := json.Unmarshal(scanner.Bytes(), &document); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			documents = append(documents, document)
+		}
+		return documents, scanner.Err()
+	}
+	var document any
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	if array, ok := document.([]any); ok {
+		return array, nil
+	}
+	return []any{document}, nil
+}
@@ -0,0 +1,375 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+)
+
+// resourceUsage resources accounted by the budget, CPU in millicores and memory and storage in bytes
+type resourceUsage struct {
+	pods    int
+	cpu     int64
+	memory  int64
+	storage int64
+}
+
+func (u resourceUsage) add(o resourceUsage) resourceUsage {
+	return resourceUsage{
+		pods:    u.pods + o.pods,
+		cpu:     u.cpu + o.cpu,
+		memory:  u.memory + o.memory,
+		storage: u.storage + o.storage,
+	}
+}
+
+func (u resourceUsage) sub(o resourceUsage) resourceUsage {
+	return u.add(resourceUsage{pods: -o.pods, cpu: -o.cpu, memory: -o.memory, storage: -o.storage})
+}
+
+// exceeds returns the limits exceeded by the usage, zero limits aren't enforced
+func (u resourceUsage) exceeds(limits resourceUsage) []string {
+	var exceeded []string
+	if limits.pods > 0 && u.pods > limits.pods {
+		exceeded = append(exceeded, fmt.Sprintf("pods %d > %d", u.pods, limits.pods))
+	}
+	if limits.cpu > 0 && u.cpu > limits.cpu {
+		exceeded = append(exceeded, fmt.Sprintf("CPU requests %v > %v", resource.NewMilliQuantity(u.cpu, resource.DecimalSI), resource.NewMilliQuantity(limits.cpu, resource.DecimalSI)))
+	}
+	if limits.memory > 0 && u.memory > limits.memory {
+		exceeded = append(exceeded, fmt.Sprintf("memory requests %v > %v", resource.NewQuantity(u.memory, resource.BinarySI), resource.NewQuantity(limits.memory, resource.BinarySI)))
+	}
+	if limits.storage > 0 && u.storage > limits.storage {
+		exceeded = append(exceeded, fmt.Sprintf("PVC storage %v > %v", resource.NewQuantity(u.storage, resource.BinarySI), resource.NewQuantity(limits.storage, resource.BinarySI)))
+	}
+	return exceeded
+}
+
+// budgetLimits returns the limits of the budget, its quantities are validated while parsing the configuration
+func budgetLimits(budget config.ResourceBudget) resourceUsage {
+	parse := func(q string) resource.Quantity {
+		if q == "" {
+			return resource.Quantity{}
+		}
+		return resource.MustParse(q)
+	}
+	cpu := parse(budget.MaxCPURequests)
+	memory := parse(budget.MaxMemoryRequests)
+	storage := parse(budget.MaxPVCStorage)
+	return resourceUsage{
+		pods:    budget.MaxPods,
+		cpu:     cpu.MilliValue(),
+		memory:  memory.Value(),
+		storage: storage.Value(),
+	}
+}
+
+// nodeCounter returns the number of nodes running the pods of the DaemonSets created by the job with the given pod spec
+type nodeCounter func(job config.Job, podSpec map[string]any) int
+
+// taints tolerated by the pods of DaemonSets, added by the DaemonSet controller
+var daemonSetTolerations = []corev1.Toleration{
+	{Key: corev1.TaintNodeNotReady, Operator: corev1.TolerationOpExists},
+	{Key: corev1.TaintNodeUnreachable, Operator: corev1.TolerationOpExists},
+	{Key: corev1.TaintNodeDiskPressure, Operator: corev1.TolerationOpExists},
+	{Key: corev1.TaintNodeMemoryPressure, Operator: corev1.TolerationOpExists},
+	{Key: corev1.TaintNodePIDPressure, Operator: corev1.TolerationOpExists},
+	{Key: corev1.TaintNodeUnschedulable, Operator: corev1.TolerationOpExists},
+	{Key: corev1.TaintNodeNetworkUnavailable, Operator: corev1.TolerationOpExists},
+}
+
+// newNodeCounter lists the nodes of the cluster, the pods of a DaemonSet run on the nodes targeted by the job and selected
+// by the nodeSelector of its pod template, whose NoSchedule and NoExecute taints are tolerated by the pod
+func newNodeCounter(clientSet kubernetes.Interface) (nodeCounter, error) {
+	nodes, err := clientSet.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %v", err)
+	}
+	return func(job config.Job, podSpec map[string]any) int {
+		var spec corev1.PodSpec
+		if podSpec != nil {
+			runtime.DefaultUnstructuredConverter.FromUnstructured(podSpec, &spec)
+		}
+		tolerations := slices.Concat(spec.Tolerations, daemonSetTolerations)
+		if job.Architecture != "" {
+			tolerations = append(tolerations, corev1.Toleration{Key: archLabel, Operator: corev1.TolerationOpEqual, Value: job.Architecture, Effect: corev1.TaintEffectNoSchedule})
+		}
+		// Selectors are validated when parsing the configuration
+		target, _ := labels.Parse(targetNodeSelector(job))
+		exclude, _ := labels.Parse(job.ExcludeNodes)
+		nodeSelector := labels.SelectorFromSet(spec.NodeSelector)
+		var count int
+		for _, node := range nodes.Items {
+			nodeLabels := labels.Set(node.Labels)
+			if !target.Matches(nodeLabels) || !nodeSelector.Matches(nodeLabels) || (!exclude.Empty() && exclude.Matches(nodeLabels)) {
+				continue
+			}
+			if !slices.ContainsFunc(node.Spec.Taints, func(taint corev1.Taint) bool {
+				return taint.Effect != corev1.TaintEffectPreferNoSchedule && !slices.ContainsFunc(tolerations, func(toleration corev1.Toleration) bool {
+					return toleration.ToleratesTaint(&taint)
+				})
+			}) {
+				count++
+			}
+		}
+		return count
+	}, nil
+}
+
+// objectPods returns the pods created per replica of the object, directly or through its controllers,
+// and the objects created by controllers, like the ReplicaSet of a Deployment. The pods of a DaemonSet are
+// counted with the node counter, one per DaemonSet without it. CronJobs create their Jobs on schedule, so their
+// pods can't be accounted
+func objectPods(uns *unstructured.Unstructured, job config.Job, countNodes nodeCounter) (pods, derived int, err error) {
+	replicas, found, _ := unstructured.NestedInt64(uns.Object, "spec", "replicas")
+	if !found {
+		replicas = 1
+	}
+	switch uns.GetKind() {
+	case Pod, VirtualMachineInstance:
+		return 1, 0, nil
+	case Deployment:
+		return int(replicas), 1, nil
+	case ReplicaSet, ReplicationController, StatefulSet:
+		return int(replicas), 0, nil
+	case DaemonSet:
+		if countNodes == nil {
+			return 1, 0, nil
+		}
+		podSpec, _, _ := unstructured.NestedMap(uns.Object, "spec", "template", "spec")
+		return countNodes(job, podSpec), 0, nil
+	case Job:
+		completions, found, _ := unstructured.NestedInt64(uns.Object, "spec", "completions")
+		if !found {
+			completions = 1
+		}
+		return int(completions), 0, nil
+	case "CronJob":
+		return 0, 0, fmt.Errorf("the pods of CronJob %s can't be accounted, its Jobs are created on schedule", uns.GetName())
+	case VirtualMachine:
+		// VirtualMachines create their VirtualMachineInstance
+		return 1, 1, nil
+	case VirtualMachineInstanceReplicaSet:
+		return int(replicas), int(replicas), nil
+	}
+	return 0, 0, nil
+}
+
+// objectUsage returns the resources requested per replica of the object: its pods and their requests,
+// and the storage of its PersistentVolumeClaims
+func objectUsage(uns *unstructured.Unstructured, job config.Job, countNodes nodeCounter) (resourceUsage, error) {
+	var usage resourceUsage
+	var err error
+	if usage.pods, _, err = objectPods(uns, job, countNodes); err != nil {
+		return usage, err
+	}
+	var podSpec map[string]any
+	switch uns.GetKind() {
+	case Pod:
+		podSpec, _, _ = unstructured.NestedMap(uns.Object, "spec")
+	case Deployment, ReplicaSet, ReplicationController, StatefulSet, DaemonSet, Job:
+		podSpec, _, _ = unstructured.NestedMap(uns.Object, "spec", "template", "spec")
+	case VirtualMachineInstance:
+		usage.cpu, usage.memory = requests(uns.Object, "spec", "domain", "resources", "requests")
+	case VirtualMachine:
+		usage.cpu, usage.memory = requests(uns.Object, "spec", "template", "spec", "domain", "resources", "requests")
+	case VirtualMachineInstanceReplicaSet:
+		cpu, memory := requests(uns.Object, "spec", "template", "spec", "domain", "resources", "requests")
+		usage.cpu, usage.memory = cpu*int64(usage.pods), memory*int64(usage.pods)
+	case PersistentVolumeClaim:
+		usage.storage = quantity(uns.Object, "spec", "resources", "requests", "storage").Value()
+	}
+	if podSpec != nil {
+		cpu, memory := podRequests(podSpec)
+		usage.cpu, usage.memory = cpu*int64(usage.pods), memory*int64(usage.pods)
+	}
+	if uns.GetKind() == StatefulSet {
+		claims, _, _ := unstructured.NestedSlice(uns.Object, "spec", "volumeClaimTemplates")
+		for _, claim := range claims {
+			if c, ok := claim.(map[string]any); ok {
+				usage.storage += quantity(c, "spec", "resources", "requests", "storage").Value() * int64(usage.pods)
+			}
+		}
+	}
+	return usage, nil
+}
+
+// podRequests returns the effective requests of a pod: the largest of the sum of its containers requests
+// and the requests of each init container
+func podRequests(podSpec map[string]any) (cpu, memory int64) {
+	containers, _, _ := unstructured.NestedSlice(podSpec, "containers")
+	for _, container := range containers {
+		if c, ok := container.(map[string]any); ok {
+			containerCPU, containerMemory := requests(c, "resources", "requests")
+			cpu += containerCPU
+			memory += containerMemory
+		}
+	}
+	initContainers, _, _ := unstructured.NestedSlice(podSpec, "initContainers")
+	for _, container := range initContainers {
+		if c, ok := container.(map[string]any); ok {
+			containerCPU, containerMemory := requests(c, "resources", "requests")
+			cpu = max(cpu, containerCPU)
+			memory = max(memory, containerMemory)
+		}
+	}
+	return cpu, memory
+}
+
+// requests returns the CPU in millicores and the memory in bytes of the requests found at the path
+func requests(obj map[string]any, fields ...string) (cpu, memory int64) {
+	return quantity(obj, slices.Concat(fields, []string{"cpu"})...).MilliValue(), quantity(obj, slices.Concat(fields, []string{"memory"})...).Value()
+}
+
+// quantity returns the quantity found at the path, missing or invalid quantities are zero
+func quantity(obj map[string]any, fields ...string) *resource.Quantity {
+	value, found, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	if !found {
+		return &resource.Quantity{}
+	}
+	q, err := resource.ParseQuantity(fmt.Sprint(value))
+	if err != nil {
+		return &resource.Quantity{}
+	}
+	return &q
+}
+
+// checkBudget estimates the peak usage of the benchmark and returns an error when it exceeds the budget.
+// Objects of the jobs with garbage collection are released once the job finishes
+func checkBudget(configSpec config.Spec, embedCfg *fileutils.EmbedConfiguration, kubeClientProvider *config.KubeClientProvider) error {
+	if configSpec.GlobalConfig.Budget == nil {
+		return nil
+	}
+	clientSet, _ := kubeClientProvider.DefaultClientSet()
+	countNodes, err := newNodeCounter(clientSet)
+	if err != nil {
+		return err
+	}
+	limits := budgetLimits(*configSpec.GlobalConfig.Budget)
+	estimate, err := estimateBenchmark(configSpec, embedCfg, countNodes)
+	if err != nil {
+		return fmt.Errorf("resource budget: %v", err)
+	}
+	var used, peak resourceUsage
+	for i, je := range estimate.Jobs {
+		usage := resourceUsage{pods: je.Pods, cpu: je.CPURequests, memory: je.MemoryRequests, storage: je.PVCStorage}
+		used = used.add(usage)
+		if exceeded := used.exceeds(limits); len(exceeded) > 0 {
+			return fmt.Errorf("job %s exceeds the resource budget: %s", je.Name, strings.Join(exceeded, ", "))
+		}
+		peak = resourceUsage{max(peak.pods, used.pods), max(peak.cpu, used.cpu), max(peak.memory, used.memory), max(peak.storage, used.storage)}
+		// The estimate assumes the jobs succeed
+		if configSpec.Jobs[i].GC && configSpec.Jobs[i].GCPolicy != config.GCOnFailure {
+			used = used.sub(usage)
+		}
+	}
+	log.Infof("Estimated peak usage within the resource budget: %d pods, %v CPU requests, %v memory requests, %v PVC storage",
+		peak.pods, resource.NewMilliQuantity(peak.cpu, resource.DecimalSI), resource.NewQuantity(peak.memory, resource.BinarySI), resource.NewQuantity(peak.storage, resource.BinarySI))
+	return nil
+}
+
+// budgetEntry usage of a created object
+type budgetEntry struct {
+	job   string
+	usage resourceUsage
+}
+
+// resourceBudget accounts the resources of the objects created by all the jobs, refusing the creations exceeding the budget.
+// Objects are identified by their kind, namespace and name, so that the objects re-created by churn are accounted once
+type resourceBudget struct {
+	limits     resourceUsage
+	countNodes nodeCounter
+	mu         sync.Mutex
+	used       resourceUsage
+	objects    map[string]budgetEntry
+	exceeded   bool
+}
+
+func newResourceBudget(budget *config.ResourceBudget, clientSet kubernetes.Interface) (*resourceBudget, error) {
+	if budget == nil {
+		return nil, nil
+	}
+	countNodes, err := newNodeCounter(clientSet)
+	if err != nil {
+		return nil, err
+	}
+	return &resourceBudget{
+		limits:     budgetLimits(*budget),
+		countNodes: countNodes,
+		objects:    make(map[string]budgetEntry),
+	}, nil
+}
+
+// reserve accounts the object, or returns an error when it would exceed the budget. It's nil-safe
+func (b *resourceBudget) reserve(job config.Job, namespace string, obj *unstructured.Unstructured) error {
+	if b == nil {
+		return nil
+	}
+	key := fmt.Sprintf("%s/%s/%s", obj.GetKind(), namespace, obj.GetName())
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, exists := b.objects[key]; exists {
+		return nil
+	}
+	usage, err := objectUsage(obj, job, b.countNodes)
+	if err != nil {
+		log.Errorf("Job %s: refusing to create %s: %v", job.Name, key, err)
+		return err
+	}
+	if exceeded := b.used.add(usage).exceeds(b.limits); len(exceeded) > 0 {
+		err := fmt.Errorf("resource budget exceeded: %s", strings.Join(exceeded, ", "))
+		// Only the first refusal is logged as an error, the rest would flood the log
+		if !b.exceeded {
+			b.exceeded = true
+			log.Errorf("Job %s: refusing to create %s: %v", job.Name, key, err)
+		} else {
+			log.Debugf("Job %s: refusing to create %s: %v", job.Name, key, err)
+		}
+		return err
+	}
+	b.used = b.used.add(usage)
+	b.objects[key] = budgetEntry{job: job.Name, usage: usage}
+	return nil
+}
+
+// releaseJob releases the objects of the job once garbage collected. It's nil-safe
+func (b *resourceBudget) releaseJob(job string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for key, entry := range b.objects {
+		if entry.job == job {
+			b.used = b.used.sub(entry.usage)
+			delete(b.objects, key)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"testing"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+)
+
+func newTestObject(t *testing.T, manifest string) *unstructured.Unstructured {
+	t.Helper()
+	// Flow style manifests are taken for JSON by the deserializer
+	j, err := yaml.YAMLToJSON([]byte(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj := &unstructured.Unstructured{}
+	if _, _, err := yamlToUnstructured("test", j, obj); err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
+
+const (
+	testDeployment = `
+apiVersion: apps/v1
+kind: Deployment
+metadata: {name: app}
+spec:
+  replicas: 3
+  template:
+    spec:
+      initContainers:
+      - resources: {requests: {cpu: 500m, memory: 64Mi}}
+      containers:
+      - resources: {requests: {cpu: 100m, memory: 128Mi}}
+      - resources: {requests: {cpu: 200m, memory: 128Mi}}
+`
+	testDaemonSet = `
+apiVersion: apps/v1
+kind: DaemonSet
+metadata: {name: agent}
+spec:
+  template:
+    spec:
+      containers:
+      - resources: {requests: {cpu: 50m, memory: 32Mi}}
+`
+)
+
+func TestObjectPods(t *testing.T) {
+	fourNodes := func(config.Job, map[string]any) int { return 4 }
+	tests := []struct {
+		manifest   string
+		countNodes nodeCounter
+		pods       int
+		derived    int
+	}{
+		{manifest: "{apiVersion: v1, kind: Pod}", pods: 1},
+		{manifest: testDeployment, pods: 3, derived: 1},
+		{manifest: "{apiVersion: apps/v1, kind: Deployment}", pods: 1, derived: 1},
+		{manifest: "{apiVersion: apps/v1, kind: StatefulSet, spec: {replicas: 2}}", pods: 2},
+		{manifest: "{apiVersion: batch/v1, kind: Job, spec: {completions: 5}}", pods: 5},
+		{manifest: testDaemonSet, pods: 1},
+		{manifest: testDaemonSet, countNodes: fourNodes, pods: 4},
+		{manifest: "{apiVersion: kubevirt.io/v1, kind: VirtualMachine}", pods: 1, derived: 1},
+		{manifest: "{apiVersion: kubevirt.io/v1, kind: VirtualMachineInstanceReplicaSet, spec: {replicas: 2}}", pods: 2, derived: 2},
+		{manifest: "{apiVersion: v1, kind: ConfigMap}"},
+	}
+	for _, tt := range tests {
+		obj := newTestObject(t, tt.manifest)
+		pods, derived, err := objectPods(obj, config.Job{}, tt.countNodes)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", obj.GetKind(), err)
+		} else if pods != tt.pods || derived != tt.derived {
+			t.Errorf("%s: got %d pods and %d derived objects, expected %d and %d", obj.GetKind(), pods, derived, tt.pods, tt.derived)
+		}
+	}
+	if _, _, err := objectPods(newTestObject(t, "{apiVersion: batch/v1, kind: CronJob}"), config.Job{}, nil); err == nil {
+		t.Error("CronJob: expected an error")
+	}
+}
+
+func TestObjectUsage(t *testing.T) {
+	const mi, gi = 1 << 20, 1 << 30
+	tests := []struct {
+		manifest string
+		expected resourceUsage
+	}{
+		// Init containers requests are compared to the sum of the containers requests
+		{manifest: testDeployment, expected: resourceUsage{pods: 3, cpu: 1500, memory: 768 * mi}},
+		{manifest: testDaemonSet, expected: resourceUsage{pods: 1, cpu: 50, memory: 32 * mi}},
+		{
+			manifest: `
+apiVersion: apps/v1
+kind: StatefulSet
+spec:
+  replicas: 2
+  template: {spec: {containers: [{resources: {requests: {cpu: "1"}}}]}}
+  volumeClaimTemplates:
+  - spec: {resources: {requests: {storage: 1Gi}}}
+`,
+			expected: resourceUsage{pods: 2, cpu: 2000, storage: 2 * gi},
+		},
+		{
+			manifest: "{apiVersion: v1, kind: PersistentVolumeClaim, spec: {resources: {requests: {storage: 5Gi}}}}",
+			expected: resourceUsage{storage: 5 * gi},
+		},
+		{
+			manifest: `
+apiVersion: kubevirt.io/v1
+kind: VirtualMachineInstanceReplicaSet
+spec:
+  replicas: 2
+  template: {spec: {domain: {resources: {requests: {cpu: "1", memory: 2Gi}}}}}
+`,
+			expected: resourceUsage{pods: 2, cpu: 2000, memory: 4 * gi},
+		},
+		{
+			manifest: "{apiVersion: kubevirt.io/v1, kind: VirtualMachine, spec: {template: {spec: {domain: {resources: {requests: {cpu: 500m, memory: 1Gi}}}}}}}",
+			expected: resourceUsage{pods: 1, cpu: 500, memory: gi},
+		},
+	}
+	for _, tt := range tests {
+		obj := newTestObject(t, tt.manifest)
+		usage, err := objectUsage(obj, config.Job{}, nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", obj.GetKind(), err)
+		} else if usage != tt.expected {
+			t.Errorf("%s: got %+v, expected %+v", obj.GetKind(), usage, tt.expected)
+		}
+	}
+}
+
+func TestNodeCounter(t *testing.T) {
+	node := func(name, arch string, taints ...corev1.Taint) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{archLabel: arch, "node": name}},
+			Spec:       corev1.NodeSpec{Taints: taints},
+		}
+	}
+	controlPlaneTaint := corev1.Taint{Key: "node-role.kubernetes.io/control-plane", Effect: corev1.TaintEffectNoSchedule}
+	clientSet := fake.NewClientset(
+		node("control-plane", "amd64", controlPlaneTaint),
+		node("worker-1", "amd64"),
+		node("worker-2", "amd64", corev1.Taint{Key: "preferred", Effect: corev1.TaintEffectPreferNoSchedule}),
+		// Tolerated by the pods of DaemonSets
+		node("worker-3", "amd64", corev1.Taint{Key: corev1.TaintNodeNotReady, Effect: corev1.TaintEffectNoExecute}),
+		node("worker-4", "arm64", corev1.Taint{Key: archLabel, Value: "arm64", Effect: corev1.TaintEffectNoSchedule}),
+	)
+	countNodes, err := newNodeCounter(clientSet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name     string
+		job      config.Job
+		podSpec  map[string]any
+		expected int
+	}{
+		{name: "untainted nodes", expected: 3},
+		{
+			name:     "tolerated control plane",
+			podSpec:  map[string]any{"tolerations": []any{map[string]any{"key": controlPlaneTaint.Key, "operator": "Exists"}}},
+			expected: 4,
+		},
+		{name: "node selector", podSpec: map[string]any{"nodeSelector": map[string]any{"node": "worker-1"}}, expected: 1},
+		{name: "target nodes", job: config.Job{TargetNodes: "node in (worker-1,worker-4)"}, expected: 1},
+		{name: "excluded nodes", job: config.Job{ExcludeNodes: "node=worker-1"}, expected: 2},
+		{name: "architecture", job: config.Job{Architecture: "arm64"}, expected: 1},
+	}
+	for _, tt := range tests {
+		if count := countNodes(tt.job, tt.podSpec); count != tt.expected {
+			t.Errorf("%s: got %d nodes, expected %d", tt.name, count, tt.expected)
+		}
+	}
+}
+
+func TestResourceBudget(t *testing.T) {
+	var nilBudget *resourceBudget
+	if err := nilBudget.reserve(config.Job{Name: "a"}, "ns", newTestObject(t, "{apiVersion: v1, kind: Pod}")); err != nil {
+		t.Errorf("unexpected error from a nil budget: %v", err)
+	}
+	budget, err := newResourceBudget(&config.ResourceBudget{MaxPods: 4, MaxCPURequests: "2"}, fake.NewClientset())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	jobA, jobB := config.Job{Name: "a"}, config.Job{Name: "b"}
+	if err := budget.reserve(jobA, "ns", newTestObject(t, testDeployment)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Re-created objects, like the ones churned, are accounted once
+	if err := budget.reserve(jobA, "ns", newTestObject(t, testDeployment)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := budget.reserve(jobB, "ns", newTestObject(t, "{apiVersion: v1, kind: Pod, metadata: {name: p1}}")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if budget.used.pods != 4 || budget.used.cpu != 1500 {
+		t.Errorf("got %+v, expected 4 pods and 1500m CPU", budget.used)
+	}
+	if err := budget.reserve(jobB, "ns", newTestObject(t, "{apiVersion: v1, kind: Pod, metadata: {name: p2}}")); err == nil {
+		t.Error("expected the pods limit to be exceeded")
+	}
+	if err := budget.reserve(jobB, "ns", newTestObject(t, "{apiVersion: batch/v1, kind: CronJob, metadata: {name: c}}")); err == nil {
+		t.Error("expected CronJobs to be refused")
+	}
+	budget.releaseJob("a")
+	if budget.used.pods != 1 || budget.used.cpu != 0 {
+		t.Errorf("got %+v after releasing job a, expected 1 pod", budget.used)
+	}
+	if err := budget.reserve(jobB, "ns", newTestObject(t, "{apiVersion: v1, kind: Pod, metadata: {name: p2}}")); err != nil {
+		t.Errorf("unexpected error once job a is released: %v", err)
+	}
+	if err := budget.reserve(jobB, "ns", newTestObject(t, testDeployment)); err == nil {
+		t.Error("expected the CPU limit to be exceeded")
+	}
+}
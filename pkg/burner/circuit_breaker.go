@@ -0,0 +1,116 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Minimum number of requests before evaluating the error rate
+const errorRateMinRequests = 10
+
+// circuitBreaker halts a job once its error limits are breached
+type circuitBreaker struct {
+	maxErrors    int
+	maxErrorRate float64
+	errors       int32
+	ctx          context.Context
+	cancel       context.CancelFunc
+	breach       error
+	mu           sync.Mutex
+}
+
+// newCircuitBreaker returns a context that gets cancelled once the job breaches its error limits
+func (ex *JobExecutor) newCircuitBreaker(ctx context.Context) context.Context {
+	breakerCtx, cancel := context.WithCancel(ctx)
+	ex.breaker = &circuitBreaker{
+		maxErrors:    ex.MaxErrors,
+		maxErrorRate: ex.MaxErrorRate,
+		ctx:          breakerCtx,
+		cancel:       cancel,
+	}
+	return breakerCtx
+}
+
+// recordError accounts a failed request and trips the breaker when any of the limits is breached
+func (ex *JobExecutor) recordError() {
+	cb := ex.breaker
+	if cb == nil || (cb.maxErrors == 0 && cb.maxErrorRate == 0) {
+		return
+	}
+	errors := atomic.AddInt32(&cb.errors, 1)
+	var breach error
+	if cb.maxErrors > 0 && int(errors) >= cb.maxErrors {
+		breach = fmt.Errorf("job %s reached the maximum number of errors: %d", ex.Name, cb.maxErrors)
+	} else if total := errors + atomic.LoadInt32(&ex.objectOperations); cb.maxErrorRate > 0 && total >= errorRateMinRequests {
+		if errorRate := float64(errors) / float64(total) * 100; errorRate > cb.maxErrorRate {
+			breach = fmt.Errorf("job %s error rate %.2f%% higher than the maximum error rate: %.2f%%", ex.Name, errorRate, cb.maxErrorRate)
+		}
+	}
+	if breach != nil {
+		cb.mu.Lock()
+		defer cb.mu.Unlock()
+		if cb.breach == nil {
+			log.Error(breach.Error())
+			cb.breach = breach
+			cb.cancel()
+		}
+	}
+}
+
+// abort fails the job with the given error, cancelling it as breaching its error limits does
+func (ex *JobExecutor) abort(err error) {
+	log.Error(err.Error())
+	cb := ex.breaker
+	if cb == nil {
+		return
+	}
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.breach == nil {
+		cb.breach = err
+		cb.cancel()
+	}
+}
+
+// errorBreach returns the error limit breach of the job, if any
+func (ex *JobExecutor) errorBreach() error {
+	if ex.breaker == nil {
+		return nil
+	}
+	ex.breaker.mu.Lock()
+	defer ex.breaker.mu.Unlock()
+	return ex.breaker.breach
+}
+
+// jobContext returns the context cancelled when the job breaches its error limits
+func (ex *JobExecutor) jobContext() context.Context {
+	if ex.breaker == nil {
+		return context.TODO()
+	}
+	return ex.breaker.ctx
+}
+
+// stopCircuitBreaker releases the resources of the breaker context
+func (ex *JobExecutor) stopCircuitBreaker() {
+	if ex.breaker != nil {
+		ex.breaker.cancel()
+	}
+}
@@ -0,0 +1,366 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// Clock ticks per second of the CPU times reported in /proc, fixed by the kernel ABI
+	userHZ = 100
+	// Percentage of the CFS periods throttled above which the client is saturated
+	clientThrottlingThreshold = 10
+)
+
+// clientCounters cumulative counters read from /proc and the cgroup filesystem, zero when unavailable
+type clientCounters struct {
+	timestamp    time.Time
+	processTicks int64
+	hostTotal    int64
+	hostIdle     int64
+	rxBytes      int64
+	txBytes      int64
+	throttled    int64
+	periods      int64
+}
+
+// clientMonitor samples the resource usage of kube-burner during the benchmark. The resource usage is read from
+// /proc, so that it's only available on Linux, and the limits from the cgroup, so that the pod resources are accounted
+// when running in-cluster
+type clientMonitor struct {
+	config      config.ClientMetrics
+	hostname    string
+	inCluster   bool
+	cpus        float64
+	memoryLimit int64
+	mu          sync.Mutex
+	samples     []prometheus.ClientSample
+	stopCh      chan struct{}
+	wg          sync.WaitGroup
+}
+
+// startClientMonitor starts sampling the resource usage of kube-burner, returns nil when not configured
+func startClientMonitor(clientMetrics *config.ClientMetrics) *clientMonitor {
+	if clientMetrics == nil {
+		return nil
+	}
+	hostname, _ := os.Hostname()
+	cm := &clientMonitor{
+		config:      *clientMetrics,
+		hostname:    hostname,
+		inCluster:   os.Getenv("KUBERNETES_SERVICE_HOST") != "",
+		cpus:        availableCPUs(),
+		memoryLimit: availableMemory(),
+		stopCh:      make(chan struct{}),
+	}
+	if runtime.GOOS != "linux" {
+		log.Warnf("Client metrics are only available on Linux, only goroutines will be sampled")
+	}
+	log.Infof("Sampling client metrics every %v: %.2f CPUs and %d MiB of memory available", cm.config.Interval, cm.cpus, cm.memoryLimit>>20)
+	cm.wg.Add(1)
+	go cm.run()
+	return cm
+}
+
+func (cm *clientMonitor) run() {
+	defer cm.wg.Done()
+	ticker := time.NewTicker(cm.config.Interval)
+	defer ticker.Stop()
+	previous := readClientCounters()
+	for {
+		select {
+		case <-cm.stopCh:
+			return
+		case <-ticker.C:
+			current := readClientCounters()
+			sample := cm.sample(previous, current)
+			cm.mu.Lock()
+			cm.samples = append(cm.samples, sample)
+			cm.mu.Unlock()
+			previous = current
+		}
+	}
+}
+
+// sample computes the usage between two readings of the counters
+func (cm *clientMonitor) sample(previous, current clientCounters) prometheus.ClientSample {
+	elapsed := current.timestamp.Sub(previous.timestamp).Seconds()
+	sample := prometheus.ClientSample{
+		Timestamp:        current.timestamp,
+		CPU:              round2(float64(current.processTicks-previous.processTicks) / userHZ / elapsed / cm.cpus * 100),
+		RSS:              readRSS(),
+		RxBytesPerSecond: float64(current.rxBytes-previous.rxBytes) / elapsed,
+		TxBytesPerSecond: float64(current.txBytes-previous.txBytes) / elapsed,
+		ThrottledPeriods: current.throttled - previous.throttled,
+		Periods:          current.periods - previous.periods,
+		Goroutines:       runtime.NumGoroutine(),
+	}
+	if hostTotal := current.hostTotal - previous.hostTotal; hostTotal > 0 {
+		sample.HostCPU = round2(float64(hostTotal-(current.hostIdle-previous.hostIdle)) / float64(hostTotal) * 100)
+	}
+	if cm.memoryLimit > 0 {
+		sample.Memory = round2(float64(sample.RSS) / float64(cm.memoryLimit) * 100)
+	}
+	return sample
+}
+
+// stop stops sampling
+func (cm *clientMonitor) stop() {
+	if cm == nil {
+		return
+	}
+	close(cm.stopCh)
+	cm.wg.Wait()
+}
+
+// attribute sets the samples taken during each job and their summary, warning about the jobs where kube-burner was saturated
+func (cm *clientMonitor) attribute(jobs []prometheus.Job) {
+	if cm == nil {
+		return
+	}
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	for i, job := range jobs {
+		end := job.End
+		if end.IsZero() {
+			end = time.Now().UTC()
+		}
+		var samples []prometheus.ClientSample
+		for _, sample := range cm.samples {
+			if !sample.Timestamp.Before(job.Start) && !sample.Timestamp.After(end) {
+				samples = append(samples, sample)
+			}
+		}
+		if len(samples) == 0 {
+			continue
+		}
+		jobs[i].ClientSamples = samples
+		jobs[i].ClientUsage = cm.summary(samples)
+		if jobs[i].ClientUsage.Saturated {
+			log.Warnf("Job %s: kube-burner was saturated, results may be limited by the client: %s", job.JobConfig.Name, strings.Join(jobs[i].ClientUsage.SaturationReasons, ", "))
+		}
+	}
+}
+
+// summary aggregates the samples of a job and checks them against the saturation thresholds
+func (cm *clientMonitor) summary(samples []prometheus.ClientSample) *prometheus.ClientUsage {
+	usage := &prometheus.ClientUsage{
+		Hostname:    cm.hostname,
+		InCluster:   cm.inCluster,
+		CPUs:        cm.cpus,
+		MemoryLimit: cm.memoryLimit,
+	}
+	var periods int64
+	cpu := make([]float64, 0, len(samples))
+	for _, sample := range samples {
+		cpu = append(cpu, sample.CPU)
+		usage.AvgCPU += sample.CPU
+		usage.AvgHostCPU += sample.HostCPU
+		usage.MaxRSS = max(usage.MaxRSS, sample.RSS)
+		usage.MaxMemory = max(usage.MaxMemory, sample.Memory)
+		usage.RxBytes += int64(sample.RxBytesPerSecond * cm.config.Interval.Seconds())
+		usage.TxBytes += int64(sample.TxBytesPerSecond * cm.config.Interval.Seconds())
+		usage.ThrottledPeriods += sample.ThrottledPeriods
+		usage.MaxGoroutines = max(usage.MaxGoroutines, sample.Goroutines)
+		periods += sample.Periods
+	}
+	slices.Sort(cpu)
+	usage.AvgCPU = round2(usage.AvgCPU / float64(len(samples)))
+	usage.AvgHostCPU = round2(usage.AvgHostCPU / float64(len(samples)))
+	usage.P95CPU = round2(cpu[int(math.Ceil(float64(len(cpu))*0.95))-1])
+	usage.MaxCPU = round2(cpu[len(cpu)-1])
+	usage.MaxMemory = round2(usage.MaxMemory)
+	if usage.P95CPU >= cm.config.CPUThreshold {
+		usage.SaturationReasons = append(usage.SaturationReasons, fmt.Sprintf("P95 CPU usage %.2f%% above %.2f%%", usage.P95CPU, cm.config.CPUThreshold))
+	}
+	if usage.AvgHostCPU >= cm.config.CPUThreshold {
+		usage.SaturationReasons = append(usage.SaturationReasons, fmt.Sprintf("average host CPU usage %.2f%% above %.2f%%", usage.AvgHostCPU, cm.config.CPUThreshold))
+	}
+	if usage.MaxMemory >= cm.config.MemoryThreshold {
+		usage.SaturationReasons = append(usage.SaturationReasons, fmt.Sprintf("memory usage %.2f%% above %.2f%%", usage.MaxMemory, cm.config.MemoryThreshold))
+	}
+	if periods > 0 && usage.ThrottledPeriods*100 >= periods*clientThrottlingThreshold {
+		usage.SaturationReasons = append(usage.SaturationReasons, fmt.Sprintf("CPU throttled in %d of %d CFS periods", usage.ThrottledPeriods, periods))
+	}
+	usage.Saturated = len(usage.SaturationReasons) > 0
+	return usage
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// readClientCounters reads the cumulative counters, those unavailable are left as zero
+func readClientCounters() clientCounters {
+	counters := clientCounters{timestamp: time.Now().UTC()}
+	// The command of the process may contain spaces, the fields following it are read
+	if stat, err := os.ReadFile("/proc/self/stat"); err == nil {
+		fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+		// utime and stime, the 14th and 15th fields of the file
+		if len(fields) > 12 {
+			utime, _ := strconv.ParseInt(fields[11], 10, 64)
+			stime, _ := strconv.ParseInt(fields[12], 10, 64)
+			counters.processTicks = utime + stime
+		}
+	}
+	if stat, err := os.ReadFile("/proc/stat"); err == nil {
+		line, _, _ := strings.Cut(string(stat), "\n")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			fields = []string{"cpu"}
+		}
+		for i, field := range fields[1:] {
+			value, _ := strconv.ParseInt(field, 10, 64)
+			// guest and guest_nice are already accounted in user and nice
+			if i < 8 {
+				counters.hostTotal += value
+			}
+			// idle and iowait
+			if i == 3 || i == 4 {
+				counters.hostIdle += value
+			}
+		}
+	}
+	counters.rxBytes, counters.txBytes = readNetworkBytes()
+	// cgroup v2, then v1
+	for _, path := range []string{"/sys/fs/cgroup/cpu.stat", "/sys/fs/cgroup/cpu/cpu.stat"} {
+		stats := readKeyValues(path)
+		if stats != nil {
+			counters.throttled, counters.periods = stats["nr_throttled"], stats["nr_periods"]
+			break
+		}
+	}
+	return counters
+}
+
+// readNetworkBytes returns the bytes received and transmitted by the network interfaces but loopback
+func readNetworkBytes() (int64, int64) {
+	f, err := os.Open("/proc/net/dev")
+	if err != nil {
+		return 0, 0
+	}
+	defer f.Close()
+	var rx, tx int64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		iface, stats, found := strings.Cut(scanner.Text(), ":")
+		if !found || strings.TrimSpace(iface) == "lo" {
+			continue
+		}
+		fields := strings.Fields(stats)
+		if len(fields) < 9 {
+			continue
+		}
+		ifaceRx, _ := strconv.ParseInt(fields[0], 10, 64)
+		ifaceTx, _ := strconv.ParseInt(fields[8], 10, 64)
+		rx += ifaceRx
+		tx += ifaceTx
+	}
+	return rx, tx
+}
+
+// readRSS returns the resident memory of the process in bytes
+func readRSS() int64 {
+	statm, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(statm))
+	if len(fields) < 2 {
+		return 0
+	}
+	pages, _ := strconv.ParseInt(fields[1], 10, 64)
+	return pages * int64(os.Getpagesize())
+}
+
+// readKeyValues reads a file made of "key value" lines, returns nil when it can't be read
+func readKeyValues(path string) map[string]int64 {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	values := make(map[string]int64)
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			values[strings.TrimSuffix(fields[0], ":")], _ = strconv.ParseInt(fields[1], 10, 64)
+		}
+	}
+	return values
+}
+
+// readInt reads a file holding an integer
+func readInt(path string) (int64, bool) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	value, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	return value, err == nil
+}
+
+// availableCPUs returns the CPUs available to the process, limited by the cgroup CPU quota
+func availableCPUs() float64 {
+	cpus := float64(runtime.NumCPU())
+	// cgroup v2: "<quota> <period>", quota being "max" when unlimited
+	if cpuMax, err := os.ReadFile("/sys/fs/cgroup/cpu.max"); err == nil {
+		fields := strings.Fields(string(cpuMax))
+		if len(fields) == 2 && fields[0] != "max" {
+			quota, _ := strconv.ParseFloat(fields[0], 64)
+			period, _ := strconv.ParseFloat(fields[1], 64)
+			if quota > 0 && period > 0 {
+				return min(cpus, quota/period)
+			}
+		}
+		return cpus
+	}
+	// cgroup v1: quota is -1 when unlimited
+	quota, quotaOK := readInt("/sys/fs/cgroup/cpu/cpu.cfs_quota_us")
+	period, periodOK := readInt("/sys/fs/cgroup/cpu/cpu.cfs_period_us")
+	if quotaOK && periodOK && quota > 0 && period > 0 {
+		return min(cpus, float64(quota)/float64(period))
+	}
+	return cpus
+}
+
+// availableMemory returns the memory available to the process in bytes, limited by the cgroup memory limit
+func availableMemory() int64 {
+	var total int64
+	if meminfo := readKeyValues("/proc/meminfo"); meminfo != nil {
+		// Reported in kB
+		total = meminfo["MemTotal"] << 10
+	}
+	// cgroup v2 limit is "max" when unlimited, and cgroup v1 one a value close to the max int64
+	for _, path := range []string{"/sys/fs/cgroup/memory.max", "/sys/fs/cgroup/memory/memory.limit_in_bytes"} {
+		if limit, ok := readInt(path); ok && limit > 0 && (total == 0 || limit < total) {
+			return limit
+		}
+	}
+	return total
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	"github.com/kube-burner/kube-burner/pkg/util"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+)
+
+// clusterHealthMonitor checks the health of the API server and the nodes on an interval during the benchmark. The
+// health of the cluster is degraded once the consecutive failed checks reach the configured threshold
+type clusterHealthMonitor struct {
+	config    config.ClusterHealthMonitor
+	clientSet kubernetes.Interface
+	mu        sync.Mutex
+	checks    []prometheus.ClusterHealthCheck
+	// failedChecks consecutive failed checks
+	failedChecks int
+	// degradedErr first degradation of the cluster health
+	degradedErr error
+	stopCh      chan struct{}
+	wg          sync.WaitGroup
+}
+
+// startClusterHealthMonitor starts checking the cluster health, returns nil when not configured
+func startClusterHealthMonitor(clusterHealthConfig *config.ClusterHealthMonitor, kubeClientProvider *config.KubeClientProvider) *clusterHealthMonitor {
+	if clusterHealthConfig == nil {
+		return nil
+	}
+	clientSet, _ := kubeClientProvider.DefaultClientSet()
+	m := &clusterHealthMonitor{
+		config:    *clusterHealthConfig,
+		clientSet: clientSet,
+		stopCh:    make(chan struct{}),
+	}
+	log.Infof("🏥 Checking the cluster health every %v, %s when %d consecutive checks fail", m.config.Interval, m.config.Policy, m.config.MaxFailedChecks)
+	m.check()
+	m.wg.Add(1)
+	go m.run()
+	return m
+}
+
+func (m *clusterHealthMonitor) run() {
+	defer m.wg.Done()
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+			m.check()
+		}
+	}
+}
+
+// check checks the readiness of the API server and the conditions of the nodes
+func (m *clusterHealthMonitor) check() {
+	ctx, cancel := context.WithTimeout(context.Background(), m.config.Interval)
+	defer cancel()
+	start := time.Now()
+	_, err := m.clientSet.Discovery().RESTClient().Get().AbsPath("/readyz").DoRaw(ctx)
+	check := prometheus.ClusterHealthCheck{
+		Timestamp:        start.UTC(),
+		APIServerReady:   err == nil,
+		APIServerLatency: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		check.Issues = append(check.Issues, fmt.Sprintf("API server is not ready: %v", err))
+	}
+	nodes, nodeIssues, err := util.NodeHealthIssues(m.clientSet)
+	if err != nil {
+		check.Issues = append(check.Issues, fmt.Sprintf("Error getting nodes: %v", err))
+	}
+	check.Nodes = nodes
+	check.UnhealthyNodes = len(nodeIssues)
+	for _, node := range slices.Sorted(maps.Keys(nodeIssues)) {
+		check.Issues = append(check.Issues, nodeIssues[node]...)
+	}
+	check.Healthy = check.APIServerReady && err == nil && check.UnhealthyNodes <= m.config.MaxUnhealthyNodes
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if check.Healthy {
+		if m.failedChecks >= m.config.MaxFailedChecks {
+			log.Infof("Cluster health recovered after %d failed checks", m.failedChecks)
+		}
+		m.failedChecks = 0
+	} else {
+		m.failedChecks++
+		log.Warnf("Cluster health check failed (%d/%d): %s", m.failedChecks, m.config.MaxFailedChecks, strings.Join(check.Issues, "; "))
+	}
+	check.FailedChecks = m.failedChecks
+	check.Degraded = m.failedChecks >= m.config.MaxFailedChecks
+	if check.Degraded && m.degradedErr == nil {
+		m.degradedErr = fmt.Errorf("cluster health degraded at %s after %d consecutive failed checks: %s", check.Timestamp.Format(time.RFC3339), m.failedChecks, strings.Join(check.Issues, "; "))
+		log.Error(m.degradedErr.Error())
+	}
+	m.checks = append(m.checks, check)
+}
+
+// stop stops checking the cluster health
+func (m *clusterHealthMonitor) stop() {
+	if m == nil {
+		return
+	}
+	close(m.stopCh)
+	m.wg.Wait()
+}
+
+// attribute sets the health checks taken during each job
+func (m *clusterHealthMonitor) attribute(jobs []prometheus.Job) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, job := range jobs {
+		end := job.End
+		if end.IsZero() {
+			end = time.Now().UTC()
+		}
+		var checks []prometheus.ClusterHealthCheck
+		for _, check := range m.checks {
+			if !check.Timestamp.Before(job.Start) && !check.Timestamp.After(end) {
+				checks = append(checks, check)
+			}
+		}
+		jobs[i].ClusterHealthChecks = checks
+	}
+}
+
+// verdict returns the degradation of the cluster health when the policy fails the benchmark
+func (m *clusterHealthMonitor) verdict() error {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.degradedErr == nil {
+		return nil
+	}
+	if m.config.Policy == config.ClusterHealthFail {
+		return m.degradedErr
+	}
+	log.Warnf("Cluster health was degraded during the benchmark, ignored by the %s policy", m.config.Policy)
+	return nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// Run metadata keys of the captured configuration and its drift
+	clusterConfigMetadata      = "clusterConfig"
+	clusterConfigDriftMetadata = "clusterConfigDrift"
+	clusterSnapshotTimeout     = time.Minute
+)
+
+// clusterConfig control-plane configuration captured at a point of the benchmark
+type clusterConfig struct {
+	Timestamp  time.Time                  `json:"timestamp"`
+	Version    string                     `json:"version"`
+	Components map[string]componentConfig `json:"components,omitempty"`
+	// Resources spec of the captured objects, by resource and name
+	Resources map[string]any `json:"resources,omitempty"`
+}
+
+// componentConfig configuration of a control-plane component, taken from the command line of its first pod
+type componentConfig struct {
+	Pod              string            `json:"pod"`
+	Image            string            `json:"image"`
+	Flags            map[string]string `json:"flags"`
+	FeatureGates     map[string]string `json:"featureGates,omitempty"`
+	AdmissionPlugins []string          `json:"admissionPlugins,omitempty"`
+}
+
+// configDrift setting whose value changed during the benchmark, empty when it was unset
+type configDrift struct {
+	Setting string `json:"setting"`
+	Before  string `json:"before"`
+	After   string `json:"after"`
+}
+
+// clusterSnapshot captures the control-plane configuration before and after the benchmark, so that result
+// differences between runs can be attributed to configuration changes
+type clusterSnapshot struct {
+	config        config.ClusterSnapshot
+	clientSet     kubernetes.Interface
+	dynamicClient dynamic.Interface
+	before        clusterConfig
+}
+
+// takeClusterSnapshot captures the configuration before the benchmark, returns nil when not configured
+func takeClusterSnapshot(clusterSnapshotConfig *config.ClusterSnapshot, kubeClientProvider *config.KubeClientProvider) *clusterSnapshot {
+	if clusterSnapshotConfig == nil {
+		return nil
+	}
+	clientSet, restConfig := kubeClientProvider.DefaultClientSet()
+	cs := &clusterSnapshot{
+		config:        *clusterSnapshotConfig,
+		clientSet:     clientSet,
+		dynamicClient: dynamic.NewForConfigOrDie(restConfig),
+	}
+	log.Info("📸 Capturing the control-plane configuration")
+	cs.before = cs.capture()
+	return cs
+}
+
+// capture returns the current configuration, components and resources that can't be read are skipped
+func (cs *clusterSnapshot) capture() clusterConfig {
+	ctx, cancel := context.WithTimeout(context.Background(), clusterSnapshotTimeout)
+	defer cancel()
+	snapshot := clusterConfig{
+		Timestamp:  time.Now().UTC(),
+		Components: make(map[string]componentConfig),
+		Resources:  make(map[string]any),
+	}
+	if version, err := cs.clientSet.Discovery().ServerVersion(); err != nil {
+		log.Warnf("Cluster snapshot: error getting the server version: %v", err)
+	} else {
+		snapshot.Version = version.GitVersion
+	}
+	for _, component := range cs.config.Components {
+		pods, err := cs.clientSet.CoreV1().Pods(component.Namespace).List(ctx, metav1.ListOptions{LabelSelector: component.LabelSelector})
+		if err != nil {
+			log.Warnf("Cluster snapshot: error listing the %s pods: %v", component.Name, err)
+			continue
+		}
+		if len(pods.Items) == 0 {
+			log.Debugf("Cluster snapshot: no %s pods found", component.Name)
+			continue
+		}
+		slices.SortFunc(pods.Items, func(a, b corev1.Pod) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+		snapshot.Components[component.Name] = newComponentConfig(pods.Items[0])
+	}
+	for _, resource := range cs.config.Resources {
+		gvr := schema.GroupVersionResource{Group: resource.Group, Version: resource.Version, Resource: resource.Resource}
+		obj, err := cs.dynamicClient.Resource(gvr).Namespace(resource.Namespace).Get(ctx, resource.Name, metav1.GetOptions{})
+		if err != nil {
+			log.Warnf("Cluster snapshot: error getting %s %s: %v", resource.Resource, resource.Name, err)
+			continue
+		}
+		snapshot.Resources[snapshotResourceKey(resource)] = obj.Object["spec"]
+	}
+	return snapshot
+}
+
+// snapshotResourceKey returns the key of the resource in the snapshot
+func snapshotResourceKey(resource config.SnapshotResource) string {
+	if resource.Namespace != "" {
+		return fmt.Sprintf("%s/%s/%s", resource.Resource, resource.Namespace, resource.Name)
+	}
+	return fmt.Sprintf("%s/%s", resource.Resource, resource.Name)
+}
+
+// newComponentConfig parses the flags of the first container of the pod, like --feature-gates=A=true,B=false
+func newComponentConfig(pod corev1.Pod) componentConfig {
+	component := componentConfig{
+		Pod:   pod.Name,
+		Flags: make(map[string]string),
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return component
+	}
+	container := pod.Spec.Containers[0]
+	component.Image = container.Image
+	args := slices.Concat(container.Command, container.Args)
+	for i := 0; i < len(args); i++ {
+		flag, ok := strings.CutPrefix(args[i], "--")
+		if !ok {
+			continue
+		}
+		name, value, hasValue := strings.Cut(flag, "=")
+		if !hasValue {
+			value = "true"
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				value = args[i+1]
+				i++
+			}
+		}
+		component.Flags[name] = value
+	}
+	if featureGates := component.Flags["feature-gates"]; featureGates != "" {
+		component.FeatureGates = make(map[string]string)
+		for _, featureGate := range strings.Split(featureGates, ",") {
+			name, value, _ := strings.Cut(featureGate, "=")
+			component.FeatureGates[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+	if plugins := component.Flags["enable-admission-plugins"]; plugins != "" {
+		component.AdmissionPlugins = strings.Split(plugins, ",")
+	}
+	return component
+}
+
+// settings flattens the configuration into setting-value pairs
+func (c clusterConfig) settings() map[string]string {
+	settings := map[string]string{"version": c.Version}
+	for name, component := range c.Components {
+		settings[name+".image"] = component.Image
+		for flag, value := range component.Flags {
+			settings[name+".flags."+flag] = value
+		}
+	}
+	for key, spec := range c.Resources {
+		raw, _ := json.Marshal(spec)
+		settings[key] = string(raw)
+	}
+	return settings
+}
+
+// finish captures the configuration after the benchmark and records the initial one, along with the settings that
+// changed meanwhile, into the run metadata
+func (cs *clusterSnapshot) finish(metadata map[string]any) {
+	if cs == nil {
+		return
+	}
+	after := cs.capture()
+	before, current := cs.before.settings(), after.settings()
+	// Settings present in either snapshot
+	all := maps.Clone(before)
+	maps.Copy(all, current)
+	drift := []configDrift{}
+	for _, setting := range slices.Sorted(maps.Keys(all)) {
+		if before[setting] != current[setting] {
+			log.Warnf("Cluster snapshot: %s changed during the benchmark from %q to %q", setting, before[setting], current[setting])
+			drift = append(drift, configDrift{Setting: setting, Before: before[setting], After: current[setting]})
+		}
+	}
+	metadata[clusterConfigMetadata] = cs.before
+	metadata[clusterConfigDriftMetadata] = drift
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	"github.com/kube-burner/kube-burner/pkg/util"
+	log "github.com/sirupsen/logrus"
+)
+
+// comparison runs the jobs of an A/B benchmark in lockstep with the other cluster, and compares their results
+type comparison struct {
+	client  *util.LockstepClient
+	cluster string
+}
+
+// newComparison returns nil when the benchmark isn't an A/B one
+func newComparison(globalConfig config.GlobalConfig, kubeClientProvider *config.KubeClientProvider) *comparison {
+	if globalConfig.CompareSide == "" {
+		return nil
+	}
+	_, restConfig := kubeClientProvider.DefaultClientSet()
+	return &comparison{
+		client:  util.NewLockstepClient(globalConfig.CompareAddress, globalConfig.CompareSide),
+		cluster: restConfig.Host,
+	}
+}
+
+// start waits for the other cluster to reach the job, so that both clusters run it, and their measurements, at the same time
+func (c *comparison) start(seq int, jobName string) {
+	if c == nil {
+		return
+	}
+	log.Infof("Waiting for the other cluster to reach job %s", jobName)
+	if err := c.client.Start(seq); err != nil {
+		log.Errorf("Error synchronizing job %s with the other cluster: %v", jobName, err)
+	}
+}
+
+// finish waits for the other cluster to finish its jobs
+func (c *comparison) finish(jobs int) {
+	if c == nil {
+		return
+	}
+	log.Info("Waiting for the other cluster to finish its jobs")
+	if err := c.client.Start(jobs); err != nil {
+		log.Errorf("Error synchronizing with the other cluster: %v", err)
+	}
+}
+
+// exchange sends the result of the job to the other cluster and compares it with its own. The comparison is returned
+// by side a only, which indexes it
+func (c *comparison) exchange(seq int, job prometheus.Job, passed bool, quantiles map[string]float64) *prometheus.Comparison {
+	if c == nil {
+		return nil
+	}
+	end := job.End
+	if end.IsZero() {
+		end = time.Now().UTC()
+	}
+	result := prometheus.CompareResult{
+		Cluster:          c.cluster,
+		Start:            job.Start,
+		End:              end,
+		ElapsedTime:      end.Sub(job.Start).Round(time.Second).Seconds(),
+		ObjectOperations: job.ObjectOperations,
+		Passed:           passed,
+		Quantiles:        quantiles,
+	}
+	if result.ElapsedTime > 0 {
+		result.AchievedQps = math.Round(float64(job.ObjectOperations)/result.ElapsedTime*1000) / 1000
+	}
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		log.Errorf("Error encoding the result of job %s: %v", job.JobConfig.Name, err)
+		return nil
+	}
+	peerJSON, err := c.client.Exchange(seq, resultJSON)
+	if err != nil {
+		log.Errorf("Error exchanging the result of job %s with the other cluster: %v", job.JobConfig.Name, err)
+		return nil
+	}
+	if peerJSON == nil {
+		log.Warnf("Job %s didn't run on the other cluster, it won't be compared", job.JobConfig.Name)
+		return nil
+	}
+	if c.client.Side != util.CompareSideA {
+		return nil
+	}
+	var peer prometheus.CompareResult
+	if err := json.Unmarshal(peerJSON, &peer); err != nil {
+		log.Errorf("Error decoding the result of job %s from the other cluster: %v", job.JobConfig.Name, err)
+		return nil
+	}
+	comparison := &prometheus.Comparison{
+		A:                result,
+		B:                peer,
+		StartSkew:        peer.Start.Sub(result.Start).Milliseconds(),
+		ElapsedTimeDelta: relativeDelta(result.ElapsedTime, peer.ElapsedTime),
+		AchievedQpsDelta: relativeDelta(result.AchievedQps, peer.AchievedQps),
+		QuantileDeltas:   make(map[string]float64),
+	}
+	for name, value := range result.Quantiles {
+		if peerValue, ok := peer.Quantiles[name]; ok {
+			comparison.QuantileDeltas[name] = relativeDelta(value, peerValue)
+		}
+	}
+	log.Infof("Job %s: %vs on cluster a, %vs on cluster b (%+.2f%%)", job.JobConfig.Name, result.ElapsedTime, peer.ElapsedTime, comparison.ElapsedTimeDelta)
+	return comparison
+}
+
+// relativeDelta returns the difference of b over a in percent, 0 when a is 0
+func relativeDelta(a, b float64) float64 {
+	if a == 0 {
+		return 0
+	}
+	return math.Round((b-a)/a*10000) / 100
+}
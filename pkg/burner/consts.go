@@ -17,6 +17,9 @@ const (
 	VolumeSnapshot                   = "VolumeSnapshot"
 	DataVolume                       = "DataVolume"
 	DataSource                       = "DataSource"
+	ResourceClaim                    = "ResourceClaim"
+	ResourceClaimTemplate            = "ResourceClaimTemplate"
+	BareMetalHost                    = "BareMetalHost"
 )
 
 type statusPath struct {
@@ -0,0 +1,154 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Allocation API path of each provider
+var costAllocationPaths = map[config.CostProvider]string{
+	config.CostOpenCost: "/allocation/compute",
+	config.CostKubecost: "/model/allocation",
+}
+
+// costAllocation cost of an allocation, aggregated by namespace
+type costAllocation struct {
+	CPUCost          float64 `json:"cpuCost"`
+	GPUCost          float64 `json:"gpuCost"`
+	RAMCost          float64 `json:"ramCost"`
+	PVCost           float64 `json:"pvCost"`
+	NetworkCost      float64 `json:"networkCost"`
+	LoadBalancerCost float64 `json:"loadBalancerCost"`
+	TotalCost        float64 `json:"totalCost"`
+}
+
+// jobNamespaces returns the namespaces created by the job, captured before they're garbage collected
+func (ex *JobExecutor) jobNamespaces(ctx context.Context) []string {
+	labelSelector := fmt.Sprintf("kube-burner-runid=%s,kube-burner-job=%s", ex.runid, ex.Name)
+	namespaces, err := ex.clientSet.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		log.Errorf("Job %s: error listing namespaces: %v", ex.Name, err)
+		return nil
+	}
+	var names []string
+	for _, ns := range namespaces.Items {
+		names = append(names, ns.Name)
+	}
+	return names
+}
+
+// attributeCosts queries the cost allocated to the namespaces of each job during its execution. It runs once the
+// benchmark finishes, giving the provider time to account the last minutes of the jobs
+func attributeCosts(costConfig *config.CostConfig, jobs []prometheus.Job) {
+	if costConfig == nil {
+		return
+	}
+	client := &http.Client{Timeout: costConfig.Timeout}
+	for i, job := range jobs {
+		if len(job.Namespaces) == 0 || job.End.IsZero() {
+			continue
+		}
+		allocations, err := queryCostAllocations(client, costConfig, job.Start, job.End)
+		if err != nil {
+			log.Errorf("Job %s: error querying %s: %v", job.JobConfig.Name, costConfig.Provider, err)
+			continue
+		}
+		cost := &prometheus.JobCost{}
+		for _, ns := range job.Namespaces {
+			allocation, ok := allocations[ns]
+			if !ok {
+				continue
+			}
+			cost.Namespaces++
+			cost.CPUCost += allocation.CPUCost
+			cost.GPUCost += allocation.GPUCost
+			cost.RAMCost += allocation.RAMCost
+			cost.PVCost += allocation.PVCost
+			cost.NetworkCost += allocation.NetworkCost
+			cost.LoadBalancerCost += allocation.LoadBalancerCost
+			cost.TotalCost += allocation.TotalCost
+		}
+		if job.ObjectOperations > 0 {
+			cost.CostPerOperation = cost.TotalCost / float64(job.ObjectOperations)
+		}
+		if cost.Namespaces < len(job.Namespaces) {
+			log.Warnf("Job %s: %s allocated costs to %d/%d namespaces", job.JobConfig.Name, costConfig.Provider, cost.Namespaces, len(job.Namespaces))
+		}
+		log.Infof("Job %s: total cost %.4f (CPU %.4f, RAM %.4f, PV %.4f, network %.4f)", job.JobConfig.Name, cost.TotalCost, cost.CPUCost, cost.RAMCost, cost.PVCost, cost.NetworkCost)
+		jobs[i].Cost = cost
+	}
+}
+
+// queryCostAllocations returns the costs allocated to each namespace within the window
+func queryCostAllocations(client *http.Client, costConfig *config.CostConfig, start, end time.Time) (map[string]costAllocation, error) {
+	query := url.Values{}
+	query.Set("window", fmt.Sprintf("%s,%s", start.UTC().Format(time.RFC3339), end.UTC().Format(time.RFC3339)))
+	query.Set("aggregate", "namespace")
+	query.Set("accumulate", "true")
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(costConfig.Endpoint, "/")+costAllocationPaths[costConfig.Provider]+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range costConfig.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	var response struct {
+		Code    int                         `json:"code"`
+		Message string                      `json:"message"`
+		Data    []map[string]costAllocation `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("error decoding response: %v", err)
+	}
+	if response.Code != 0 && response.Code != http.StatusOK {
+		return nil, fmt.Errorf("error %d: %s", response.Code, response.Message)
+	}
+	allocations := make(map[string]costAllocation)
+	// Accumulated responses hold a single set of allocations, otherwise they're added up
+	for _, set := range response.Data {
+		for ns, allocation := range set {
+			total := allocations[ns]
+			total.CPUCost += allocation.CPUCost
+			total.GPUCost += allocation.GPUCost
+			total.RAMCost += allocation.RAMCost
+			total.PVCost += allocation.PVCost
+			total.NetworkCost += allocation.NetworkCost
+			total.LoadBalancerCost += allocation.LoadBalancerCost
+			total.TotalCost += allocation.TotalCost
+			allocations[ns] = total
+		}
+	}
+	return allocations, nil
+}
@@ -38,9 +38,10 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
 )
 
-func (ex *JobExecutor) setupCreateJob(mapper meta.RESTMapper) {
+func (ex *JobExecutor) setupCreateJob(mapper meta.RESTMapper) error {
 	var f io.Reader
 	var err error
 	log.Debugf("Preparing create job: %s", ex.Name)
@@ -52,22 +53,25 @@ func (ex *JobExecutor) setupCreateJob(mapper meta.RESTMapper) {
 		log.Debugf("Rendering template: %s", o.ObjectTemplate)
 		f, err = fileutils.GetWorkloadReader(o.ObjectTemplate, ex.embedCfg)
 		if err != nil {
-			log.Fatalf("Error reading template %s: %s", o.ObjectTemplate, err)
+			return fmt.Errorf("error reading template %s: %s", o.ObjectTemplate, err)
 		}
 		t, err := io.ReadAll(f)
 		if err != nil {
-			log.Fatalf("Error reading template %s: %s", o.ObjectTemplate, err)
+			return fmt.Errorf("error reading template %s: %s", o.ObjectTemplate, err)
 		}
 		// Deserialize YAML
 		uns := &unstructured.Unstructured{}
 		cleanTemplate, err := util.CleanupTemplate(t)
 		if err != nil {
-			log.Fatalf("Error cleaning up template %s: %s", o.ObjectTemplate, err)
+			return fmt.Errorf("error cleaning up template %s: %s", o.ObjectTemplate, err)
+		}
+		_, gvk, err := yamlToUnstructured(o.ObjectTemplate, cleanTemplate, uns)
+		if err != nil {
+			return err
 		}
-		_, gvk := yamlToUnstructured(o.ObjectTemplate, cleanTemplate, uns)
 		mapping, err := mapper.RESTMapping(gvk.GroupKind())
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		obj := &object{
 			gvr:        mapping.Resource,
@@ -84,6 +88,7 @@ func (ex *JobExecutor) setupCreateJob(mapper meta.RESTMapper) {
 		log.Infof("Job %s: %d iterations with %d %s replicas", ex.Name, ex.JobIterations, obj.Replicas, gvk.Kind)
 		ex.objects = append(ex.objects, obj)
 	}
+	return nil
 }
 
 // RunCreateJob executes a creation job
@@ -99,10 +104,17 @@ func (ex *JobExecutor) RunCreateJob(ctx context.Context, iterationStart, iterati
 	var err error
 	maps.Copy(nsLabels, ex.NamespaceLabels)
 	maps.Copy(nsAnnotations, ex.NamespaceAnnotations)
+	if ex.arrivals != nil {
+		ex.arrivals.begin()
+	}
+	if ex.iterationPacer != nil {
+		ex.iterationPacer.begin()
+	}
 	if ex.nsRequired && !ex.NamespacedIterations {
-		ns = ex.Namespace
+		ns = ex.namespaceName(ex.Namespace)
 		if err = util.CreateNamespace(ex.clientSet, ns, nsLabels, nsAnnotations); err != nil {
-			log.Fatal(err.Error())
+			ex.abort(err)
+			return
 		}
 		*waitListNamespaces = append(*waitListNamespaces, ns)
 	}
@@ -112,7 +124,10 @@ func (ex *JobExecutor) RunCreateJob(ctx context.Context, iterationStart, iterati
 	var namespacesCreated = make(map[string]bool)
 	var namespacesWaited = make(map[string]bool)
 	for i := iterationStart; i < iterationEnd; i++ {
-		if ctx.Err() != nil {
+		if ctx.Err() != nil || ex.nodeWatchdog.wait(ctx) != nil {
+			return
+		}
+		if ex.iterationPacer != nil && ex.iterationPacer.wait(ctx) != nil {
 			return
 		}
 		if i == iterationStart+iterationProgress*percent {
@@ -132,22 +147,16 @@ func (ex *JobExecutor) RunCreateJob(ctx context.Context, iterationStart, iterati
 			}
 		}
 		for objectIndex, obj := range ex.objects {
-			labels := map[string]string{
-				"kube-burner-uuid":                 ex.uuid,
-				"kube-burner-job":                  ex.Name,
-				"kube-burner-index":                strconv.Itoa(objectIndex),
-				"kube-burner-runid":                ex.runid,
-				config.KubeBurnerLabelJobIteration: strconv.Itoa(i),
-			}
+			labels := ex.objectLabels(objectIndex, i)
 			ex.objects[objectIndex].LabelSelector = labels
 			if obj.RunOnce {
 				if i == 0 {
 					// this executes only once during the first iteration of an object
 					log.Debugf("RunOnce set to %s, so creating object once", obj.ObjectTemplate)
-					ex.replicaHandler(ctx, labels, obj, ns, i, &wg)
+					ex.replicaHandler(ctx, labels, obj, objectIndex, ns, i, &wg)
 				}
 			} else {
-				ex.replicaHandler(ctx, labels, obj, ns, i, &wg)
+				ex.replicaHandler(ctx, labels, obj, objectIndex, ns, i, &wg)
 			}
 		}
 		if !ex.WaitWhenFinished && ex.PodWait {
@@ -165,6 +174,9 @@ func (ex *JobExecutor) RunCreateJob(ctx context.Context, iterationStart, iterati
 	}
 	// Wait for all replicas to be created
 	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
 	if ex.WaitWhenFinished {
 		log.Infof("Waiting up to %s for actions to be completed", ex.MaxWaitTimeout)
 		// This semaphore is used to limit the maximum number of concurrent goroutines
@@ -193,15 +205,26 @@ func (ex *JobExecutor) RunCreateJob(ctx context.Context, iterationStart, iterati
 	}
 }
 
+// objectLabels returns the labels kube-burner sets on the objects of the given iteration
+func (ex *JobExecutor) objectLabels(objectIndex, iteration int) map[string]string {
+	return map[string]string{
+		"kube-burner-uuid":                 ex.uuid,
+		"kube-burner-job":                  ex.Name,
+		"kube-burner-index":                strconv.Itoa(objectIndex),
+		"kube-burner-runid":                ex.runid,
+		config.KubeBurnerLabelJobIteration: strconv.Itoa(iteration),
+	}
+}
+
 // Simple integer division on the iteration allows us to batch iterations into
 // namespaces. Division means namespaces are populated to their desired number
 // of iterations before the next namespace is created.
 func (ex *JobExecutor) generateNamespace(iteration int) string {
 	nsIndex := iteration / ex.IterationsPerNamespace
-	return fmt.Sprintf("%s-%d", ex.Namespace, nsIndex)
+	return ex.namespaceName(fmt.Sprintf("%s-%s", ex.Namespace, ex.padIndex(nsIndex)))
 }
 
-func (ex *JobExecutor) replicaHandler(ctx context.Context, labels map[string]string, obj *object, ns string, iteration int, replicaWg *sync.WaitGroup) {
+func (ex *JobExecutor) replicaHandler(ctx context.Context, labels map[string]string, obj *object, objectIndex int, ns string, iteration int, replicaWg *sync.WaitGroup) {
 	var wg sync.WaitGroup
 
 	for r := 1; r <= obj.Replicas; r++ {
@@ -217,34 +240,81 @@ func (ex *JobExecutor) replicaHandler(ctx context.Context, labels map[string]str
 		go func(r int) {
 			defer wg.Done()
 			var newObject = new(unstructured.Unstructured)
-			ex.limiter.Wait(context.TODO())
-			renderedObj := ex.renderTemplateForObject(obj, iteration, r, false)
+			if ex.arrivals != nil {
+				if ex.arrivals.wait(ctx) != nil {
+					return
+				}
+			} else if ex.limiter.Wait(ctx) != nil {
+				return
+			}
+			renderedObj, err := ex.renderTemplateForObject(obj, iteration, r, false)
+			if err != nil {
+				ex.abort(err)
+				return
+			}
 			// Re-decode rendered object
-			yamlToUnstructured(obj.ObjectTemplate, renderedObj, newObject)
+			if _, _, err := yamlToUnstructured(obj.ObjectTemplate, renderedObj, newObject); err != nil {
+				ex.abort(err)
+				return
+			}
+			name, err := ex.objectName(newObject.GetName(), objectIndex, iteration, r)
+			if err != nil {
+				log.Errorf("Error naming object from %s: %v", obj.ObjectTemplate, err)
+				return
+			}
+			newObject.SetName(name)
 
 			maps.Copy(copiedLabels, newObject.GetLabels())
 			newObject.SetLabels(copiedLabels)
 			setMetadataLabels(newObject, copiedLabels)
+			ex.setNodeAffinity(newObject)
+			ex.setArchToleration(newObject)
+			if !ex.applyWasmHook(newObject) {
+				return
+			}
+			budgetNs := ns
+			if !obj.namespaced {
+				budgetNs = ""
+			} else if objNs := newObject.GetNamespace(); objNs != "" {
+				budgetNs = objNs
+			}
+			if ex.budget.reserve(ex.Job, budgetNs, newObject) != nil {
+				ex.recordError()
+				return
+			}
 
 			// replicaWg is necessary because we want to wait for all replicas
 			// to be created before running any other action such as verify objects,
 			// wait for ready, etc. Without this wait group, running for example,
 			// verify objects can lead into a race condition when some objects
 			// hasn't been created yet
+			var slot <-chan struct{}
+			if ex.arrivals != nil {
+				var admitted bool
+				if slot, admitted = ex.arrivals.admit(); !admitted {
+					return
+				}
+			}
 			replicaWg.Add(1)
 			go func(n string) {
+				defer replicaWg.Done()
 				if !obj.namespaced {
 					n = ""
 				}
-				ex.createRequest(ctx, obj.gvr, n, newObject, ex.MaxWaitTimeout)
-				replicaWg.Done()
+				if ex.arrivals != nil {
+					if !ex.arrivals.acquire(ctx, slot) {
+						return
+					}
+					defer ex.arrivals.release()
+				}
+				ex.createRequest(ctx, ex.client(iteration), obj.gvr, n, newObject, ex.MaxWaitTimeout)
 			}(ns)
 		}(r)
 	}
 	wg.Wait()
 }
 
-func (ex *JobExecutor) createRequest(ctx context.Context, gvr schema.GroupVersionResource, ns string, obj *unstructured.Unstructured, timeout time.Duration) {
+func (ex *JobExecutor) createRequest(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, ns string, obj *unstructured.Unstructured, timeout time.Duration) {
 	var uns *unstructured.Unstructured
 	var err error
 	util.RetryWithExponentialBackOff(func() (bool, error) {
@@ -256,13 +326,13 @@ func (ex *JobExecutor) createRequest(ctx context.Context, gvr schema.GroupVersio
 			ns = objNs
 		}
 		if ns != "" {
-			uns, err = ex.dynamicClient.Resource(gvr).Namespace(ns).Create(context.TODO(), obj, metav1.CreateOptions{})
+			uns, err = client.Resource(gvr).Namespace(ns).Create(context.TODO(), obj, metav1.CreateOptions{FieldValidation: ex.fieldValidation})
 		} else {
-			uns, err = ex.dynamicClient.Resource(gvr).Create(context.TODO(), obj, metav1.CreateOptions{})
+			uns, err = client.Resource(gvr).Create(context.TODO(), obj, metav1.CreateOptions{FieldValidation: ex.fieldValidation})
 		}
 		if err != nil {
 			if kerrors.IsUnauthorized(err) {
-				log.Fatalf("Authorization error creating %s/%s: %s", obj.GetKind(), obj.GetName(), err)
+				ex.abort(fmt.Errorf("authorization error creating %s/%s: %s", obj.GetKind(), obj.GetName(), err))
 				return true, err
 			} else if kerrors.IsAlreadyExists(err) {
 				if ns != "" {
@@ -271,8 +341,10 @@ func (ex *JobExecutor) createRequest(ctx context.Context, gvr schema.GroupVersio
 					log.Errorf("%s/%s already exists", obj.GetKind(), obj.GetName())
 				}
 				return true, nil
-			} else if kerrors.IsNotFound(err) {
+			} else if kerrors.IsNotFound(err) || kerrors.IsBadRequest(err) {
+				// Malformed objects, like those rejected by the strict field validation, aren't retried
 				log.Errorf("Error creating object %s/%s: %v", obj.GetKind(), obj.GetName(), err.Error())
+				ex.recordError()
 				return true, nil
 			}
 			if ns != "" {
@@ -280,6 +352,7 @@ func (ex *JobExecutor) createRequest(ctx context.Context, gvr schema.GroupVersio
 			} else {
 				log.Errorf("Error creating object %s/%s: %s", obj.GetKind(), obj.GetName(), err)
 			}
+			ex.recordError()
 			log.Error("Retrying object creation")
 			return false, nil
 		}
@@ -303,8 +376,6 @@ func (ex *JobExecutor) RunCreateJobWithChurn(ctx context.Context) {
 		return
 	}
 	var err error
-	// Determine the number of job iterations to churn (min 1)
-	numToChurn := int(math.Max(float64(ex.ChurnPercent*ex.JobIterations/100), 1))
 	now := time.Now().UTC()
 	cyclesCount := 0
 	rand.NewSource(now.UnixNano())
@@ -325,6 +396,12 @@ func (ex *JobExecutor) RunCreateJobWithChurn(ctx context.Context) {
 			log.Infof("Reached specified number of churn cycles (%d), stopping churn job", ex.ChurnCycles)
 			return
 		}
+		if ex.nodeWatchdog.wait(ctx) != nil {
+			return
+		}
+		churnPercent, churnDelay := ex.churnParameters()
+		// Determine the number of job iterations to churn (min 1)
+		numToChurn := int(math.Max(float64(churnPercent*ex.JobIterations/100), 1))
 		// Max amount of churn is 100% of namespaces
 		randStart := 1
 		if ex.JobIterations-numToChurn+1 > 0 {
@@ -332,6 +409,7 @@ func (ex *JobExecutor) RunCreateJobWithChurn(ctx context.Context) {
 		} else {
 			numToChurn = ex.JobIterations
 		}
+		ex.phases.record(ex.Name, phaseChurnCycleStarted, cyclesCount+1, fmt.Sprintf("Churn cycle %d of job %s started", cyclesCount+1, ex.Name))
 		var namespacesPatched = make(map[string]bool)
 		var namespacesToDelete []string
 		// delete numToChurn namespaces starting at randStart
@@ -354,8 +432,16 @@ func (ex *JobExecutor) RunCreateJobWithChurn(ctx context.Context) {
 		// Cleanup namespaces based on the labels we added
 		if ex.JobIterations < ex.IterationsPerNamespace && len(namespacesToDelete) == 1 {
 			log.Infof("Churning through iterations: %d to %d in namespace: %s", randStart, numToChurn+randStart, namespacesToDelete[0])
+			if ex.ChurnPodDeletion == config.ChurnPodEvict {
+				for i := randStart; i < numToChurn+randStart; i++ {
+					ex.evictPods(ctx, namespacesToDelete, fmt.Sprintf("kube-burner-job=%s,%s=%d", ex.Name, config.KubeBurnerLabelJobIteration, i))
+				}
+			}
 			CleanupIterations(ctx, *ex, randStart, numToChurn+randStart, namespacesToDelete[0])
 		} else {
+			if ex.ChurnPodDeletion == config.ChurnPodEvict {
+				ex.evictPods(ctx, namespacesToDelete, fmt.Sprintf("kube-burner-job=%s", ex.Name))
+			}
 			if ex.ChurnDeletionStrategy == "gvr" {
 				CleanupNamespacesUsingGVR(ctx, *ex, namespacesToDelete)
 			}
@@ -364,8 +450,9 @@ func (ex *JobExecutor) RunCreateJobWithChurn(ctx context.Context) {
 		log.Info("Re-creating deleted objects")
 		// Re-create objects that were deleted
 		ex.RunCreateJob(ctx, randStart, numToChurn+randStart, &[]string{})
-		log.Infof("Sleeping for %v", ex.ChurnDelay)
-		time.Sleep(ex.ChurnDelay)
+		ex.phases.record(ex.Name, phaseChurnCycleFinished, cyclesCount+1, fmt.Sprintf("Churn cycle %d of job %s finished", cyclesCount+1, ex.Name))
+		log.Infof("Sleeping for %v", churnDelay)
+		time.Sleep(churnDelay)
 		cyclesCount++
 	}
 }
@@ -53,10 +53,10 @@ func deleteHandler(ex *JobExecutor, obj *object, item unstructured.Unstructured,
 	var err error
 	if obj.namespaced {
 		log.Debugf("Removing %s/%s from namespace %s", item.GetKind(), item.GetName(), item.GetNamespace())
-		err = ex.dynamicClient.Resource(obj.gvr).Namespace(item.GetNamespace()).Delete(context.TODO(), item.GetName(), metav1.DeleteOptions{})
+		err = ex.client(iteration).Resource(obj.gvr).Namespace(item.GetNamespace()).Delete(context.TODO(), item.GetName(), metav1.DeleteOptions{})
 	} else {
 		log.Debugf("Removing %s/%s", item.GetKind(), item.GetName())
-		err = ex.dynamicClient.Resource(obj.gvr).Delete(context.TODO(), item.GetName(), metav1.DeleteOptions{})
+		err = ex.client(iteration).Resource(obj.gvr).Delete(context.TODO(), item.GetName(), metav1.DeleteOptions{})
 	}
 	if err != nil {
 		log.Errorf("Error found removing %s/%s: %s", item.GetKind(), item.GetName(), err)
@@ -0,0 +1,331 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	"github.com/kube-burner/kube-burner/pkg/util/metrics"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/restmapper"
+)
+
+// Components of the benchmark reported by the description
+const (
+	componentJob             = "job"
+	componentObject          = "object"
+	componentWait            = "wait"
+	componentMeasurement     = "measurement"
+	componentIndexer         = "indexer"
+	componentIndexing        = "indexing"
+	componentMetricsEndpoint = "metricsEndpoint"
+	componentMetricsProfile  = "metricsProfile"
+	componentAlertProfile    = "alertProfile"
+)
+
+// Statuses of the described components
+const (
+	componentActive   = "active"
+	componentDisabled = "disabled"
+)
+
+// API groups the measurements watch, they don't collect anything when the group isn't served
+var measurementAPIGroups = map[string]string{
+	"vmiLatency":            "kubevirt.io",
+	"dataVolumeLatency":     "cdi.kubevirt.io",
+	"volumeSnapshotLatency": "snapshot.storage.k8s.io",
+	"draLatency":            "resource.k8s.io",
+	"bareMetalLatency":      "metal3.io",
+}
+
+// DescribedComponent component of the benchmark, and whether it will be exercised
+type DescribedComponent struct {
+	Component string `json:"component"`
+	Name      string `json:"name"`
+	// Job job the component belongs to, empty for the components of the benchmark
+	Job    string `json:"job,omitempty"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Description components the benchmark will exercise on the cluster, and the ones disabled
+type Description struct {
+	Cluster    string               `json:"cluster"`
+	Version    string               `json:"version"`
+	Components []DescribedComponent `json:"components"`
+}
+
+// servedAPIs kinds served by the cluster per group/version, and the groups served
+type servedAPIs struct {
+	kinds  map[string]map[string]struct{}
+	groups map[string]struct{}
+}
+
+func (s servedAPIs) kindServed(apiVersion, kind string) bool {
+	if apiVersion != "" {
+		_, ok := s.kinds[apiVersion][kind]
+		return ok
+	}
+	for _, kinds := range s.kinds {
+		if _, ok := kinds[kind]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (s servedAPIs) groupServed(group string) bool {
+	_, ok := s.groups[group]
+	return ok
+}
+
+// DescribeBenchmark cross-references the configuration with the cluster, reporting the jobs, objects, waits, measurements,
+// indexers and metrics endpoints the benchmark will exercise, and the reason of the ones disabled. Indexers aren't contacted,
+// since creating them may create indices
+func DescribeBenchmark(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider, embedCfg *fileutils.EmbedConfiguration) (Description, error) {
+	clientSet, restConfig := kubeClientProvider.DefaultClientSet()
+	description := Description{Cluster: restConfig.Host}
+	version, err := clientSet.Discovery().ServerVersion()
+	if err != nil {
+		return description, fmt.Errorf("error connecting to the cluster: %v", err)
+	}
+	description.Version = version.GitVersion
+	served, err := discoverServedAPIs(discovery.NewDiscoveryClientForConfigOrDie(restConfig))
+	if err != nil {
+		return description, err
+	}
+	d := &description
+	e := estimator{configSpec: configSpec, embedCfg: embedCfg}
+	for _, job := range configSpec.Jobs {
+		verifyJobDefaults(&job, configSpec.GlobalConfig.Timeout)
+		d.describeJob(e, job, served)
+	}
+	d.describeMeasurements(configSpec, served)
+	d.describeMetricsEndpoints(configSpec, kubeClientProvider)
+	return description, nil
+}
+
+func discoverServedAPIs(discoveryClient *discovery.DiscoveryClient) (servedAPIs, error) {
+	served := servedAPIs{kinds: make(map[string]map[string]struct{}), groups: make(map[string]struct{})}
+	groupResources, err := restmapper.GetAPIGroupResources(discoveryClient)
+	if err != nil {
+		return served, fmt.Errorf("error discovering the APIs of the cluster: %v", err)
+	}
+	for _, group := range groupResources {
+		served.groups[group.Group.Name] = struct{}{}
+		for groupVersion, resources := range group.VersionedResources {
+			apiVersion := groupVersion
+			if group.Group.Name != "" {
+				apiVersion = group.Group.Name + "/" + groupVersion
+			}
+			if served.kinds[apiVersion] == nil {
+				served.kinds[apiVersion] = make(map[string]struct{})
+			}
+			for _, resource := range resources {
+				served.kinds[apiVersion][resource.Kind] = struct{}{}
+			}
+		}
+	}
+	return served, nil
+}
+
+func (d *Description) add(component, name, job, status, reason string) {
+	d.Components = append(d.Components, DescribedComponent{Component: component, Name: name, Job: job, Status: status, Reason: reason})
+}
+
+func (d *Description) describeJob(e estimator, job config.Job, served servedAPIs) {
+	jobStatus, jobReason := componentActive, ""
+	if job.JobType == config.KubeVirtJob && !served.groupServed("kubevirt.io") {
+		jobStatus, jobReason = componentDisabled, "the kubevirt.io API group isn't served, the job would fail"
+	}
+	d.add(componentJob, string(job.JobType), job.Name, jobStatus, jobReason)
+	if job.JobType != config.CreationJob {
+		for _, o := range job.Objects {
+			if o.Kind == "" {
+				continue
+			}
+			if served.kindServed(o.APIVersion, o.Kind) {
+				d.add(componentObject, o.Kind, job.Name, componentActive, "")
+			} else {
+				d.add(componentObject, o.Kind, job.Name, componentDisabled, fmt.Sprintf("kind %s isn't served by the cluster, no objects are matched", o.Kind))
+			}
+		}
+		return
+	}
+	ex := JobExecutor{
+		Job:               job,
+		uuid:              e.configSpec.GlobalConfig.UUID,
+		runid:             e.configSpec.GlobalConfig.RUNID,
+		functionTemplates: e.configSpec.GlobalConfig.FunctionTemplates,
+		embedCfg:          e.embedCfg,
+	}
+	waited := job.PodWait || job.WaitWhenFinished || e.configSpec.GlobalConfig.WaitWhenFinished
+	for _, o := range job.Objects {
+		if o.Replicas < 1 {
+			d.add(componentObject, o.ObjectTemplate, job.Name, componentDisabled, "replicas is lower than 1")
+			continue
+		}
+		obj, err := e.renderEstimatedObject(&ex, o)
+		if err != nil {
+			d.add(componentObject, o.ObjectTemplate, job.Name, componentDisabled, err.Error())
+			continue
+		}
+		name := fmt.Sprintf("%s (%s)", obj.kind, o.ObjectTemplate)
+		if !served.kindServed(obj.apiVersion, obj.kind) {
+			d.add(componentObject, name, job.Name, componentDisabled, fmt.Sprintf("%s %s isn't served by the cluster, the job would fail", obj.apiVersion, obj.kind))
+			continue
+		}
+		d.add(componentObject, name, job.Name, componentActive, "")
+		kind := obj.kind
+		if o.WaitOptions.Kind != "" {
+			kind = o.WaitOptions.Kind
+		}
+		switch {
+		case !waited:
+			d.add(componentWait, name, job.Name, componentDisabled, "podWait and waitWhenFinished are disabled")
+		case !o.Wait:
+			d.add(componentWait, name, job.Name, componentDisabled, "wait is disabled for the object")
+		case len(o.WaitOptions.CustomStatusPaths) > 0:
+			d.add(componentWait, name, job.Name, componentActive, "custom status paths")
+		case hasWaiter(kind):
+			d.add(componentWait, name, job.Name, componentActive, "")
+		default:
+			d.add(componentWait, name, job.Name, componentDisabled, fmt.Sprintf("kind %s has no waiter, set waitOptions.customStatusPaths", kind))
+		}
+	}
+}
+
+func (d *Description) describeMeasurements(configSpec config.Spec, served servedAPIs) {
+	for _, measurement := range configSpec.GlobalConfig.Measurements {
+		group, requiresGroup := measurementAPIGroups[measurement.Name]
+		switch {
+		case !measurements.IsSupported(measurement):
+			d.add(componentMeasurement, measurement.Name, "", componentDisabled, "measurement not supported, it's skipped")
+		case !measurements.IsIndexerOk(configSpec, measurement):
+			d.add(componentMeasurement, measurement.Name, "", componentDisabled, "its quantilesIndexer or timeseriesIndexer isn't configured, the benchmark would fail")
+		case requiresGroup && !served.groupServed(group):
+			d.add(componentMeasurement, measurement.Name, "", componentDisabled, fmt.Sprintf("the %s API group isn't served, nothing is measured", group))
+		default:
+			d.add(componentMeasurement, measurement.Name, "", componentActive, "")
+		}
+	}
+}
+
+func (d *Description) describeMetricsEndpoints(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider) {
+	var indexerAliases []string
+	for pos, metricsEndpoint := range configSpec.MetricsEndpoints {
+		alias := metricsEndpoint.Alias
+		if alias == "" {
+			alias = fmt.Sprintf("indexer-%d", pos)
+		}
+		var hasIndexer bool
+		if metricsEndpoint.Type != "" {
+			hasIndexer = true
+			d.add(componentIndexer, alias, "", componentActive, fmt.Sprintf("%s indexer, not contacted", metricsEndpoint.Type))
+		} else if len(metricsEndpoint.Indexers) > 0 {
+			hasIndexer = true
+			d.add(componentIndexer, alias, "", componentActive, fmt.Sprintf("fan-out to %d indexers, not contacted", len(metricsEndpoint.Indexers)))
+		}
+		if hasIndexer {
+			indexerAliases = append(indexerAliases, alias)
+		}
+		if len(metricsEndpoint.Metrics) == 0 && len(metricsEndpoint.Alerts) == 0 {
+			continue
+		}
+		endpointOk, reason := describeMetricsEndpoint(configSpec, &metricsEndpoint, kubeClientProvider)
+		if endpointOk {
+			d.add(componentMetricsEndpoint, alias, "", componentActive, reason)
+		} else {
+			d.add(componentMetricsEndpoint, alias, "", componentDisabled, reason)
+		}
+		for _, profile := range metricsEndpoint.Metrics {
+			switch {
+			case !endpointOk:
+				d.add(componentMetricsProfile, profile, "", componentDisabled, fmt.Sprintf("metrics endpoint %s is disabled", alias))
+			case !hasIndexer:
+				d.add(componentMetricsProfile, profile, "", componentDisabled, "no indexer configured in the metrics endpoint, the benchmark would fail")
+			default:
+				d.add(componentMetricsProfile, profile, "", componentActive, "")
+			}
+		}
+		for _, profile := range metricsEndpoint.Alerts {
+			if endpointOk {
+				d.add(componentAlertProfile, profile, "", componentActive, "")
+			} else {
+				d.add(componentAlertProfile, profile, "", componentDisabled, fmt.Sprintf("metrics endpoint %s is disabled", alias))
+			}
+		}
+	}
+	if len(indexerAliases) == 0 {
+		d.add(componentIndexer, "", "", componentDisabled, "no indexer configured, metrics and measurements aren't indexed")
+	}
+	for _, job := range configSpec.Jobs {
+		switch {
+		case len(indexerAliases) == 0:
+			d.add(componentIndexing, "metrics and measurements", job.Name, componentDisabled, "no indexer configured")
+		case job.SkipIndexing:
+			d.add(componentIndexing, "metrics and measurements", job.Name, componentDisabled, "skipIndexing is set")
+		default:
+			d.add(componentIndexing, "metrics and measurements", job.Name, componentActive, strings.Join(indexerAliases, ","))
+		}
+	}
+}
+
+// describeMetricsEndpoint resolves the Prometheus endpoint, discovering it when not given, and checks it's reachable.
+// Endpoints reached through a UNIX socket or a port-forward aren't checked
+func describeMetricsEndpoint(configSpec config.Spec, metricsEndpoint *config.MetricsEndpoint, kubeClientProvider *config.KubeClientProvider) (bool, string) {
+	var reason string
+	if metricsEndpoint.Endpoint == "" && metricsEndpoint.UnixSocket == "" && metricsEndpoint.PortForward == nil {
+		discovered, err := metrics.DiscoverEndpoint(metricsEndpoint, kubeClientProvider, configSpec.GlobalConfig.Timeout)
+		if err != nil {
+			return false, fmt.Sprintf("no endpoint given and discovery failed, its metrics and alerts are skipped: %v", err)
+		}
+		reason = fmt.Sprintf("discovered %s endpoint, ", discovered.Stack)
+	}
+	switch {
+	case metricsEndpoint.UnixSocket != "":
+		return true, reason + "reached through a UNIX socket, not checked"
+	case metricsEndpoint.PortForward != nil:
+		return true, reason + "reached through a port-forward, not checked"
+	}
+	auth := prometheus.Auth{
+		Username:      metricsEndpoint.Username,
+		Password:      metricsEndpoint.Password,
+		Token:         metricsEndpoint.Token,
+		SkipTLSVerify: metricsEndpoint.SkipTLSVerify,
+	}
+	if _, err := prometheus.NewPrometheusClient(configSpec, metricsEndpoint.Endpoint, auth, metricsEndpoint.Step, nil, nil); err != nil {
+		return false, fmt.Sprintf("%s unreachable, the benchmark would fail: %v", metricsEndpoint.Endpoint, err)
+	}
+	return true, reason + metricsEndpoint.Endpoint
+}
+
+// Write writes the description as a table
+func (description Description) Write(out io.Writer) {
+	fmt.Fprintf(out, "Cluster: %s (%s)\n", description.Cluster, description.Version)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COMPONENT\tNAME\tJOB\tSTATUS\tREASON")
+	for _, c := range description.Components {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Component, c.Name, c.Job, c.Status, c.Reason)
+	}
+	w.Flush()
+}
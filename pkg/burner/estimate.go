@@ -0,0 +1,428 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// Writes caused by each pod: creation, binding and the status updates of the kubelet
+const estimatedPodWrites = 4
+
+const daemonSetNote = "DaemonSets are counted as a single pod, their pods depend on the nodes of the cluster"
+
+// Kinds not requiring a namespace, the estimator doesn't have access to the cluster discovery
+var clusterScopedKinds = map[string]struct{}{
+	"Namespace":                      {},
+	"Node":                           {},
+	"PersistentVolume":               {},
+	"StorageClass":                   {},
+	"ClusterRole":                    {},
+	"ClusterRoleBinding":             {},
+	"CustomResourceDefinition":       {},
+	"PriorityClass":                  {},
+	"IngressClass":                   {},
+	"RuntimeClass":                   {},
+	"CSIDriver":                      {},
+	"VolumeSnapshotClass":            {},
+	"DeviceClass":                    {},
+	"ValidatingWebhookConfiguration": {},
+	"MutatingWebhookConfiguration":   {},
+	"APIService":                     {},
+}
+
+// JobEstimate predicted cost of a job
+type JobEstimate struct {
+	Name       string         `json:"name"`
+	JobType    config.JobType `json:"jobType"`
+	Duration   time.Duration  `json:"duration"`
+	Objects    int            `json:"objects"`
+	Namespaces int            `json:"namespaces"`
+	Pods       int            `json:"pods"`
+	// CPURequests sum of the CPU requests of the pods, in millicores
+	CPURequests int64 `json:"cpuRequests"`
+	// MemoryRequests sum of the memory requests of the pods, in bytes
+	MemoryRequests int64 `json:"memoryRequests"`
+	// PVCStorage storage requested by the PersistentVolumeClaims, in bytes
+	PVCStorage int64          `json:"pvcStorage"`
+	Requests   map[string]int `json:"requests"`
+	EtcdWrites int            `json:"etcdWrites"`
+	Watches    int            `json:"watches"`
+	Notes      []string       `json:"notes,omitempty"`
+}
+
+// Estimate predicted cost of the benchmark
+type Estimate struct {
+	Jobs        []JobEstimate  `json:"jobs"`
+	Duration    time.Duration  `json:"duration"`
+	Requests    map[string]int `json:"requests"`
+	EtcdWrites  int            `json:"etcdWrites"`
+	PeakWatches int            `json:"peakWatches"`
+}
+
+// estimatedObject object of a create job, rendered from its first iteration
+type estimatedObject struct {
+	config.Object
+	apiVersion string
+	kind       string
+	namespaced bool
+	// Pods created per replica of the object, directly or through its controllers
+	pods int
+	// Objects created by controllers per replica, like the ReplicaSet of a Deployment
+	derived int
+	// Resources requested per replica
+	usage resourceUsage
+	// Error accounting the pods of the object, like those of CronJobs
+	accountingErr error
+}
+
+// estimator keeps the objects created by the previous jobs, so that delete, patch and read jobs can guess how many objects they match
+type estimator struct {
+	configSpec config.Spec
+	embedCfg   *fileutils.EmbedConfiguration
+	// Objects created per job and kind
+	created map[string]map[string]int
+	// Counts the nodes running the pods of DaemonSets, nil without access to the cluster
+	countNodes nodeCounter
+	// Errors accounting the pods of the objects
+	accountingErrs []error
+}
+
+// EstimateBenchmark predicts the duration, API requests, etcd writes and watches of the benchmark from its configuration,
+// without accessing the cluster. Time spent waiting for objects to be ready can't be predicted and is excluded
+func EstimateBenchmark(configSpec config.Spec, embedCfg *fileutils.EmbedConfiguration) Estimate {
+	estimate, _ := estimateBenchmark(configSpec, embedCfg, nil)
+	return estimate
+}
+
+// estimateBenchmark estimates the benchmark counting the pods of DaemonSets with the node counter, and returns the
+// errors accounting the pods of the objects along with the estimate
+func estimateBenchmark(configSpec config.Spec, embedCfg *fileutils.EmbedConfiguration, countNodes nodeCounter) (Estimate, error) {
+	e := estimator{
+		configSpec: configSpec,
+		embedCfg:   embedCfg,
+		created:    make(map[string]map[string]int),
+		countNodes: countNodes,
+	}
+	estimate := Estimate{Requests: make(map[string]int)}
+	for _, job := range configSpec.Jobs {
+		verifyJobDefaults(&job, configSpec.GlobalConfig.Timeout)
+		var je JobEstimate
+		switch job.JobType {
+		case config.CreationJob:
+			je = e.estimateCreateJob(job)
+		case config.DeletionJob, config.PatchJob, config.ReadJob, config.InflateJob:
+			je = e.estimateMatchingJob(job)
+		default:
+			je = newJobEstimate(job)
+			je.Notes = append(je.Notes, fmt.Sprintf("%s jobs can't be estimated", job.JobType))
+		}
+		je.Watches += e.measurementWatches()
+		for _, watcher := range job.Watchers {
+			je.Watches += watcher.Replicas
+		}
+		je.Duration += job.JobPause
+		estimate.Jobs = append(estimate.Jobs, je)
+		estimate.Duration += je.Duration
+		estimate.EtcdWrites += je.EtcdWrites
+		estimate.PeakWatches = max(estimate.PeakWatches, je.Watches)
+		for verb, requests := range je.Requests {
+			estimate.Requests[verb] += requests
+		}
+	}
+	return estimate, errors.Join(e.accountingErrs...)
+}
+
+func newJobEstimate(job config.Job) JobEstimate {
+	return JobEstimate{
+		Name:     job.Name,
+		JobType:  job.JobType,
+		Requests: make(map[string]int),
+	}
+}
+
+func (e *estimator) estimateCreateJob(job config.Job) JobEstimate {
+	je := newJobEstimate(job)
+	ex := JobExecutor{
+		Job:               job,
+		uuid:              e.configSpec.GlobalConfig.UUID,
+		runid:             e.configSpec.GlobalConfig.RUNID,
+		functionTemplates: e.configSpec.GlobalConfig.FunctionTemplates,
+		embedCfg:          e.embedCfg,
+	}
+	var objects []estimatedObject
+	var nsRequired bool
+	var perIteration, runOnce, podsPerIteration, runOncePods, derived int
+	var usage resourceUsage
+	for _, o := range job.Objects {
+		if o.Replicas < 1 {
+			continue
+		}
+		obj, err := e.renderEstimatedObject(&ex, o)
+		if err != nil {
+			je.Notes = append(je.Notes, err.Error())
+			continue
+		}
+		if obj.accountingErr != nil {
+			je.Notes = append(je.Notes, obj.accountingErr.Error())
+			e.accountingErrs = append(e.accountingErrs, fmt.Errorf("job %s: %v", job.Name, obj.accountingErr))
+		}
+		if obj.kind == DaemonSet && e.countNodes == nil && !slices.Contains(je.Notes, daemonSetNote) {
+			je.Notes = append(je.Notes, daemonSetNote)
+		}
+		nsRequired = nsRequired || obj.namespaced
+		if obj.RunOnce {
+			runOnce += obj.Replicas
+			runOncePods += obj.Replicas * obj.pods
+		} else {
+			perIteration += obj.Replicas
+			podsPerIteration += obj.Replicas * obj.pods
+		}
+		derived += obj.Replicas * obj.derived
+		created := int64(obj.Replicas)
+		if !obj.RunOnce {
+			created *= int64(job.JobIterations)
+		}
+		usage = usage.add(resourceUsage{cpu: created * obj.usage.cpu, memory: created * obj.usage.memory, storage: created * obj.usage.storage})
+		objects = append(objects, obj)
+	}
+	je.Objects = job.JobIterations*perIteration + runOnce
+	je.Pods = job.JobIterations*podsPerIteration + runOncePods
+	je.CPURequests, je.MemoryRequests, je.PVCStorage = usage.cpu, usage.memory, usage.storage
+	if nsRequired {
+		je.Namespaces = 1
+		if job.NamespacedIterations {
+			je.Namespaces = int(math.Ceil(float64(job.JobIterations) / float64(job.IterationsPerNamespace)))
+		}
+	}
+	if e.created[job.Name] == nil {
+		e.created[job.Name] = make(map[string]int)
+	}
+	for _, obj := range objects {
+		created := obj.Replicas
+		if !obj.RunOnce {
+			created *= job.JobIterations
+		}
+		e.created[job.Name][obj.kind] += created
+	}
+	je.Requests["create"] = je.Objects + je.Namespaces
+	je.EtcdWrites = je.Objects + je.Namespaces + job.JobIterations*derived + je.Pods*estimatedPodWrites
+	createQPS := job.QPS
+	if job.ArrivalRate != nil {
+		createQPS = float32(job.ArrivalRate.Rate)
+	}
+	je.Duration = requestsDuration(je.Requests["create"], createQPS) + time.Duration(job.JobIterations)*job.JobIterationDelay
+	if job.IterationsPerMinute > 0 {
+		je.Duration = max(je.Duration, time.Duration(float64(job.JobIterations)/job.IterationsPerMinute*float64(time.Minute)))
+	}
+	// Waiters list each waited object once per namespace at least, polling adds a list request per second until the objects are ready
+	if job.PodWait || job.WaitWhenFinished || e.configSpec.GlobalConfig.WaitWhenFinished {
+		waitedKinds := make(map[string]struct{})
+		for _, obj := range objects {
+			if obj.Wait {
+				waitedKinds[obj.kind] = struct{}{}
+			}
+		}
+		if job.WaiterMode == config.WaiterModeWatch {
+			je.Requests["list"] += len(waitedKinds)
+			je.Requests["watch"] += len(waitedKinds)
+			je.Watches += len(waitedKinds)
+		} else {
+			je.Requests["list"] += max(je.Namespaces, 1) * len(waitedKinds)
+		}
+		je.Notes = append(je.Notes, "time waiting for objects to be ready is not included")
+	}
+	if job.VerifyObjects {
+		for _, obj := range objects {
+			je.Requests["list"] += int(math.Ceil(float64(obj.Replicas*job.JobIterations) / objectLimit))
+		}
+	}
+	if job.Churn {
+		e.estimateChurn(job, &je, perIteration, podsPerIteration, derived)
+	}
+	if job.GC || e.configSpec.GlobalConfig.GC {
+		je.Requests["delete"] += je.Namespaces
+		for _, obj := range objects {
+			if !obj.namespaced {
+				je.Requests["delete"] += obj.Replicas * job.JobIterations
+			}
+		}
+		je.EtcdWrites += je.Objects + je.Namespaces + je.Pods
+	}
+	return je
+}
+
+// renderEstimatedObject renders the first replica of the first iteration of the object
+func (e *estimator) renderEstimatedObject(ex *JobExecutor, o config.Object) (estimatedObject, error) {
+	obj := estimatedObject{Object: o, pods: 0}
+	f, err := fileutils.GetWorkloadReader(o.ObjectTemplate, e.embedCfg)
+	if err != nil {
+		return obj, fmt.Errorf("error reading template %s: %s", o.ObjectTemplate, err)
+	}
+	t, err := io.ReadAll(f)
+	if err != nil {
+		return obj, fmt.Errorf("error reading template %s: %s", o.ObjectTemplate, err)
+	}
+	rendered, err := ex.renderObject(&object{Object: o, objectSpec: t}, 0, 1)
+	if err != nil {
+		return obj, fmt.Errorf("template error in %s: %v", o.ObjectTemplate, err)
+	}
+	uns := &unstructured.Unstructured{}
+	if _, _, err := scheme.Codecs.UniversalDeserializer().Decode(rendered, nil, uns); err != nil {
+		return obj, fmt.Errorf("error decoding YAML (%s): %v", o.ObjectTemplate, err)
+	}
+	obj.apiVersion, obj.kind = uns.GetAPIVersion(), uns.GetKind()
+	_, clusterScoped := clusterScopedKinds[obj.kind]
+	obj.namespaced = !clusterScoped && uns.GetNamespace() == ""
+	// The object is estimated anyway, without its pods and requests
+	if obj.pods, obj.derived, obj.accountingErr = objectPods(uns, ex.Job, e.countNodes); obj.accountingErr == nil {
+		obj.usage, obj.accountingErr = objectUsage(uns, ex.Job, e.countNodes)
+	}
+	return obj, nil
+}
+
+// estimateChurn adds the cost of the churn cycles: namespaces are labeled, deleted, and their objects created again
+func (e *estimator) estimateChurn(job config.Job, je *JobEstimate, perIteration, podsPerIteration, derived int) {
+	if je.Namespaces == 0 {
+		return
+	}
+	churnedIterations := max(job.ChurnPercent*job.JobIterations/100, 1)
+	churnedNamespaces := int(math.Ceil(float64(churnedIterations) / float64(job.IterationsPerNamespace)))
+	createsPerCycle := churnedIterations*perIteration + churnedNamespaces
+	cycleDuration := requestsDuration(createsPerCycle, job.QPS) + job.ChurnDelay
+	cycles := int(math.Ceil(float64(job.ChurnDuration) / float64(cycleDuration)))
+	if job.ChurnCycles > 0 {
+		cycles = min(cycles, job.ChurnCycles)
+	}
+	je.Duration += min(job.ChurnDuration, time.Duration(cycles)*cycleDuration)
+	je.Requests["patch"] += cycles * churnedNamespaces
+	je.Requests["delete"] += cycles * churnedNamespaces
+	je.Requests["create"] += cycles * createsPerCycle
+	churnedObjects := churnedIterations*(perIteration+derived) + churnedNamespaces
+	churnedPods := churnedIterations * podsPerIteration
+	// Deletions and creations
+	je.EtcdWrites += cycles * (churnedNamespaces + 2*churnedObjects + churnedPods*(estimatedPodWrites+1))
+	je.Notes = append(je.Notes, fmt.Sprintf("%d churn cycles, namespace deletion time is not included", cycles))
+}
+
+// estimateMatchingJob estimates delete, patch, read and inflate jobs, the objects they match are guessed from the objects created by the previous jobs
+func (e *estimator) estimateMatchingJob(job config.Job) JobEstimate {
+	je := newJobEstimate(job)
+	iterations := max(job.JobIterations, 1)
+	if job.JobType == config.DeletionJob {
+		iterations = 1
+	}
+	verb := map[config.JobType]string{
+		config.DeletionJob: "delete",
+		config.PatchJob:    "patch",
+		config.ReadJob:     "get",
+		config.InflateJob:  "patch",
+	}[job.JobType]
+	for _, o := range job.Objects {
+		matched := e.matchedObjects(o)
+		if matched == 0 {
+			je.Notes = append(je.Notes, fmt.Sprintf("objects matched by the %s selector %v are unknown", o.Kind, o.LabelSelector))
+		}
+		je.Objects += matched
+		je.Requests["list"] += iterations
+		je.Requests[verb] += iterations * matched
+	}
+	if job.JobType != config.ReadJob {
+		je.EtcdWrites = je.Requests[verb]
+	}
+	je.Duration = requestsDuration(je.Requests[verb]+je.Requests["list"], job.QPS) + time.Duration(iterations)*job.JobIterationDelay
+	if job.JobType == config.PatchJob || job.JobType == config.InflateJob {
+		je.Duration += time.Duration(iterations*len(job.Objects)) * job.ObjectDelay
+	}
+	return je
+}
+
+// matchedObjects guesses the number of objects matched: the ones of the same kind created by the job referenced by the
+// kube-burner-job label, or by all the previous jobs otherwise
+func (e *estimator) matchedObjects(o config.Object) int {
+	if jobName, ok := o.LabelSelector["kube-burner-job"]; ok {
+		return e.created[jobName][o.Kind]
+	}
+	var matched int
+	for _, created := range e.created {
+		matched += created[o.Kind]
+	}
+	return matched
+}
+
+// measurementWatches returns the watches opened by the measurements
+func (e *estimator) measurementWatches() int {
+	var watches int
+	for _, m := range e.configSpec.GlobalConfig.Measurements {
+		switch m.Name {
+		case "pprof":
+		case "podLatency":
+			watches++
+			if m.TimelineSampleRate > 0 {
+				watches++
+			}
+			if m.ClockSkewCorrection {
+				watches++
+			}
+		default:
+			watches++
+		}
+	}
+	return watches
+}
+
+func requestsDuration(requests int, qps float32) time.Duration {
+	return time.Duration(float64(requests) / float64(qps) * float64(time.Second))
+}
+
+// Write writes the estimate as a table
+func (estimate Estimate) Write(out io.Writer) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "JOB\tTYPE\tDURATION\tOBJECTS\tNAMESPACES\tPODS\tCPU REQUESTS\tMEMORY REQUESTS\tPVC STORAGE\tREQUESTS\tETCD WRITES\tWATCHES")
+	for _, je := range estimate.Jobs {
+		fmt.Fprintf(w, "%s\t%s\t%v\t%d\t%d\t%d\t%v\t%v\t%v\t%s\t%d\t%d\n", je.Name, je.JobType, je.Duration.Round(time.Second), je.Objects, je.Namespaces, je.Pods,
+			resource.NewMilliQuantity(je.CPURequests, resource.DecimalSI), resource.NewQuantity(je.MemoryRequests, resource.BinarySI), resource.NewQuantity(je.PVCStorage, resource.BinarySI),
+			formatRequests(je.Requests), je.EtcdWrites, je.Watches)
+	}
+	fmt.Fprintf(w, "TOTAL\t\t%v\t\t\t\t\t\t\t%s\t%d\t%d (peak)\n", estimate.Duration.Round(time.Second), formatRequests(estimate.Requests), estimate.EtcdWrites, estimate.PeakWatches)
+	w.Flush()
+	for _, je := range estimate.Jobs {
+		for _, note := range je.Notes {
+			fmt.Fprintf(out, "%s: %s\n", je.Name, note)
+		}
+	}
+}
+
+func formatRequests(requests map[string]int) string {
+	var formatted []string
+	for _, verb := range slices.Sorted(maps.Keys(requests)) {
+		formatted = append(formatted, fmt.Sprintf("%s=%d", verb, requests[verb]))
+	}
+	return strings.Join(formatted, ",")
+}
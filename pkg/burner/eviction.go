@@ -0,0 +1,136 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	log "github.com/sirupsen/logrus"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Time between the eviction attempts of a pod protected by a PodDisruptionBudget
+const evictionRetryInterval = time.Second
+
+// evictionRecorder accounts the pod evictions blocked by PodDisruptionBudgets, shared by the copies of the executor
+type evictionRecorder struct {
+	mu     sync.Mutex
+	events []prometheus.PDBBlockedEviction
+}
+
+func (r *evictionRecorder) record(event prometheus.PDBBlockedEviction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+// summary returns the blocked evictions recorded, it's nil-safe
+func (r *evictionRecorder) summary() []prometheus.PDBBlockedEviction {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.events) > 0 {
+		var evicted int
+		for _, event := range r.events {
+			if event.Evicted {
+				evicted++
+			}
+		}
+		log.Infof("%d pod evictions were blocked by PodDisruptionBudgets, %d of them eventually allowed", len(r.events), evicted)
+	}
+	return r.events
+}
+
+// evictPods evicts the pods of the namespaces matching the label selector through the eviction API, so that
+// the churned objects are removed honoring their PodDisruptionBudgets. Evictions are issued in parallel and those
+// refused by a PodDisruptionBudget are retried until allowed or the churn eviction timeout is reached
+func (ex *JobExecutor) evictPods(ctx context.Context, namespaces []string, labelSelector string) {
+	var wg sync.WaitGroup
+	for _, namespace := range namespaces {
+		pods, err := ex.clientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+		if err != nil {
+			log.Errorf("Unable to list pods in %s: %v", namespace, err)
+			continue
+		}
+		log.Infof("Evicting %d pods labeled with %s in %s", len(pods.Items), labelSelector, namespace)
+		for _, pod := range pods.Items {
+			if pod.DeletionTimestamp != nil {
+				continue
+			}
+			wg.Add(1)
+			go func(namespace, name string) {
+				defer wg.Done()
+				ex.evictPod(ctx, namespace, name)
+			}(namespace, pod.Name)
+		}
+	}
+	wg.Wait()
+}
+
+// evictPod evicts a pod, a 429 response means that a PodDisruptionBudget doesn't allow the disruption yet
+func (ex *JobExecutor) evictPod(ctx context.Context, namespace, name string) {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	start := time.Now().UTC()
+	deadline := time.NewTimer(ex.ChurnEvictionTimeout)
+	defer deadline.Stop()
+	var attempts int
+	for {
+		if ex.limiter.Wait(ctx) != nil {
+			return
+		}
+		attempts++
+		err := ex.clientSet.PolicyV1().Evictions(namespace).Evict(ctx, eviction)
+		if err == nil || errors.IsNotFound(err) {
+			if attempts > 1 {
+				ex.evictions.record(blockedEviction(start, namespace, name, attempts, true))
+			}
+			return
+		}
+		if !errors.IsTooManyRequests(err) {
+			log.Errorf("Error evicting pod %s/%s: %v", namespace, name, err)
+			return
+		}
+		log.Debugf("Eviction of pod %s/%s blocked by a PodDisruptionBudget: %v", namespace, name, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			log.Warnf("Pod %s/%s not evicted after %v, it will be deleted bypassing its PodDisruptionBudget", namespace, name, ex.ChurnEvictionTimeout)
+			ex.evictions.record(blockedEviction(start, namespace, name, attempts, false))
+			return
+		case <-time.After(evictionRetryInterval):
+		}
+	}
+}
+
+func blockedEviction(start time.Time, namespace, name string, attempts int, evicted bool) prometheus.PDBBlockedEviction {
+	return prometheus.PDBBlockedEviction{
+		Timestamp: start,
+		Namespace: namespace,
+		Pod:       name,
+		Wait:      time.Since(start).Milliseconds(),
+		Attempts:  attempts,
+		Evicted:   evicted,
+	}
+}
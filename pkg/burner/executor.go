@@ -15,7 +15,10 @@
 package burner
 
 import (
+	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"maps"
 
@@ -24,11 +27,15 @@ import (
 	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/client-go/discovery"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
 	"kubevirt.io/client-go/kubecli"
 )
 
@@ -53,9 +60,58 @@ type JobExecutor struct {
 	functionTemplates []string
 	embedCfg          *fileutils.EmbedConfiguration
 	objectOperations  int32
+	iterationStart    int
+	iterationEnd      int
+	workerMode        bool
+	breaker           *circuitBreaker
+	// Time spent building the discovery RESTMapper, shared by all the jobs
+	discoveryLatency time.Duration
+	// Time spent warming up the client connections of the job
+	warmUpLatency time.Duration
+	waiterStats   *waiterStats
+	// waiterCache watch-fed cache used by the waiters in watch mode
+	waiterCache *waiterCache
+	// arrivals scheduler of the creations in open-loop jobs
+	arrivals *arrivalScheduler
+	// iterationPacer starts the iterations of calendar-paced jobs at fixed slots
+	iterationPacer *iterationPacer
+	// qpsController adaptive QPS controller of the job
+	qpsController *adaptiveQPS
+	// evictions pod evictions blocked by PodDisruptionBudgets during churn
+	evictions *evictionRecorder
+	// helm runner of helm jobs
+	helm *helmRunner
+	// fio runner of fio jobs
+	fio *fioRunner
+	// network runner of network jobs
+	network *networkRunner
+	// inflate runner of inflate jobs
+	inflate *inflateRunner
+	// custom job of a job type registered by a wrapper
+	custom *customJob
+	// identities clients of the identities the object requests are round-robined across
+	identities *identityPool
+	// wasmHook module the rendered objects are handed to before their creation
+	wasmHook *wasmHook
+	// budget resource budget shared by all the jobs
+	budget *resourceBudget
+	// mapper discovery RESTMapper, used to apply the hook manifests
+	mapper meta.RESTMapper
+	// fieldValidation server-side field validation of the objects created and patched
+	fieldValidation string
+	// phases publishes the phase transitions of the job
+	phases *phaseRecorder
+	// nodeWatchdog pauses the job while too many nodes are unhealthy
+	nodeWatchdog *nodeWatchdog
+	// liveReconfig live reconfiguration endpoint, set while the job runs
+	liveReconfig *liveReconfigurer
+	// clientLimiter client-side rate limiter of the job, set when the QPS can be changed live
+	clientLimiter clientLimiter
+	// nodeAffinity node selector pinning the pods of the created objects to the target nodes
+	nodeAffinity *corev1.NodeSelector
 }
 
-func newExecutor(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider, job config.Job, embedCfg *fileutils.EmbedConfiguration) JobExecutor {
+func newExecutor(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider, job config.Job, embedCfg *fileutils.EmbedConfiguration, mapper meta.RESTMapper) (JobExecutor, error) {
 	ex := JobExecutor{
 		Job:               job,
 		limiter:           rate.NewLimiter(rate.Limit(job.QPS), job.Burst),
@@ -64,20 +120,66 @@ func newExecutor(configSpec config.Spec, kubeClientProvider *config.KubeClientPr
 		waitLimiter:       rate.NewLimiter(rate.Limit(job.QPS), job.Burst),
 		functionTemplates: configSpec.GlobalConfig.FunctionTemplates,
 		embedCfg:          embedCfg,
+		nodeAffinity:      newNodeAffinity(targetNodeSelector(job), job.ExcludeNodes),
 		objectOperations:  0,
+		iterationStart:    0,
+		iterationEnd:      job.JobIterations,
+		workerMode:        configSpec.GlobalConfig.Workers > 1,
+		mapper:            mapper,
+		fieldValidation:   fieldValidation(configSpec.GlobalConfig.Strict),
+	}
+	if ex.workerMode && job.JobType == config.CreationJob {
+		ex.iterationStart, ex.iterationEnd = util.WorkerIterations(job.JobIterations, configSpec.GlobalConfig.Workers, configSpec.GlobalConfig.WorkerIndex)
+		log.Infof("Job %s: worker %d running iterations [%d, %d)", job.Name, configSpec.GlobalConfig.WorkerIndex, ex.iterationStart, ex.iterationEnd)
 	}
 
-	clientSet, runtimeRestConfig := kubeClientProvider.ClientSet(job.QPS, job.Burst)
+	// The client rate limiter must not throttle the QPS ramp
+	clientQPS := job.QPS
+	if job.QPSRamp != nil {
+		clientQPS = max(job.QPS, job.QPSRamp.StartQPS)
+	}
+	clientSet, runtimeRestConfig := kubeClientProvider.ClientSet(clientQPS, job.Burst)
 	ex.clientSet = clientSet
 	ex.restConfig = runtimeRestConfig
+	// Open-loop jobs issue their creations on arrival, the client must not delay them
+	if job.ArrivalRate != nil {
+		ex.arrivals = newArrivalScheduler(*job.ArrivalRate)
+		ex.restConfig.RateLimiter = flowcontrol.NewFakeAlwaysRateLimiter()
+	}
+	// The client rate limiter follows the live changes of the QPS and burst
+	if configSpec.GlobalConfig.LiveReconfiguration != nil && job.ArrivalRate == nil {
+		ex.clientLimiter = clientLimiter{rate.NewLimiter(rate.Limit(clientQPS), job.Burst)}
+		ex.restConfig.RateLimiter = ex.clientLimiter
+		ex.clientSet = kubernetes.NewForConfigOrDie(ex.restConfig)
+	}
+	if job.IterationsPerMinute > 0 {
+		ex.iterationPacer = newIterationPacer(job.IterationsPerMinute, configSpec.GlobalConfig.Workers, configSpec.GlobalConfig.WorkerIndex)
+	}
+	if job.AdaptiveQPS != nil {
+		ex.qpsController = ex.newAdaptiveQPS()
+		ex.clientSet = kubernetes.NewForConfigOrDie(ex.restConfig)
+	}
 	ex.dynamicClient = dynamic.NewForConfigOrDie(ex.restConfig)
-
-	_, setupRestConfig := kubeClientProvider.ClientSet(100, 100) // Hardcoded QPS/Burst
-	mapper := newRESTMapper(discovery.NewDiscoveryClientForConfigOrDie(setupRestConfig))
+	ex.setupIdentities()
+	ex.setupWasmHook()
+	ex.waiterStats = &waiterStats{}
+	if job.Churn && job.ChurnPodDeletion == config.ChurnPodEvict {
+		ex.evictions = &evictionRecorder{}
+	}
+	if job.WaiterMode == config.WaiterModeWatch {
+		ex.waiterCache = newWaiterCache(ex.dynamicClient, ex.runid, ex.ListOptions.DisableWatchBookmarks, ex.waiterStats)
+	}
 
 	switch job.JobType {
 	case config.CreationJob:
-		ex.setupCreateJob(mapper)
+		if err := ex.setupCreateJob(mapper); err != nil {
+			return ex, err
+		}
+		if job.Naming != nil {
+			if err := ex.checkNames(mapper); err != nil {
+				return ex, fmt.Errorf("job %s: %v", job.Name, err)
+			}
+		}
 	case config.DeletionJob:
 		ex.setupDeleteJob(mapper)
 	case config.PatchJob:
@@ -86,20 +188,56 @@ func newExecutor(configSpec config.Spec, kubeClientProvider *config.KubeClientPr
 		ex.setupReadJob(mapper)
 	case config.KubeVirtJob:
 		ex.setupKubeVirtJob(mapper)
+	case config.HelmJob:
+		ex.setupHelmJob(kubeClientProvider)
+	case config.FioJob:
+		ex.setupFioJob()
+	case config.NetworkJob:
+		ex.setupNetworkJob()
+	case config.InflateJob:
+		ex.setupInflateJob(mapper)
 	default:
-		log.Fatalf("Unknown jobType: %s", job.JobType)
+		newRunner, ok := registeredJobRunner(job.JobType)
+		if !ok {
+			return ex, fmt.Errorf("unknown jobType: %s", job.JobType)
+		}
+		ex.setupCustomJob(newRunner)
 	}
-	return ex
+	return ex, nil
 }
 
-func (ex *JobExecutor) renderTemplateForObject(obj *object, iteration, replicaIndex int, asJson bool) []byte {
-	// Processing template
+// warmUp establishes the client connections and primes the API server caches with a minimal list request per resource,
+// so that the first iterations of the job don't pay these one-time costs. Errors are not fatal, e.g. due to missing permissions
+func (ex *JobExecutor) warmUp() {
+	start := time.Now()
+	if _, err := ex.clientSet.Discovery().ServerVersion(); err != nil {
+		log.Debugf("Job %s: error warming up client: %v", ex.Name, err)
+	}
+	warmedUp := make(map[schema.GroupVersionResource]bool)
+	for _, obj := range ex.objects {
+		if obj.gvr.Empty() || warmedUp[obj.gvr] {
+			continue
+		}
+		warmedUp[obj.gvr] = true
+		if _, err := ex.dynamicClient.Resource(obj.gvr).List(context.TODO(), metav1.ListOptions{Limit: 1}); err != nil {
+			log.Debugf("Job %s: error warming up %s: %v", ex.Name, obj.gvr.Resource, err)
+		}
+	}
+	ex.warmUpLatency = time.Since(start)
+	log.Debugf("Job %s: clients warmed up in %v", ex.Name, ex.warmUpLatency.Truncate(time.Millisecond))
+}
+
+// renderObject renders the object template for the given iteration and replica
+func (ex *JobExecutor) renderObject(obj *object, iteration, replicaIndex int) ([]byte, error) {
 	templateData := map[string]any{
-		jobName:      ex.Name,
-		jobIteration: iteration,
-		jobUUID:      ex.uuid,
-		jobRunId:     ex.runid,
-		replica:      replicaIndex,
+		jobName:         ex.Name,
+		jobIteration:    iteration,
+		jobUUID:         ex.uuid,
+		jobRunId:        ex.runid,
+		replica:         replicaIndex,
+		paddedIteration: ex.padIndex(iteration),
+		paddedReplica:   ex.padIndex(replicaIndex),
+		architecture:    ex.Architecture,
 	}
 	maps.Copy(templateData, obj.InputVars)
 
@@ -107,20 +245,26 @@ func (ex *JobExecutor) renderTemplateForObject(obj *object, iteration, replicaIn
 	if ex.DefaultMissingKeysWithZero {
 		templateOption = util.MissingKeyZero
 	}
+	return util.RenderTemplate(obj.objectSpec, templateData, templateOption, ex.functionTemplates)
+}
 
-	renderedObj, err := util.RenderTemplate(obj.objectSpec, templateData, templateOption, ex.functionTemplates)
+func (ex *JobExecutor) renderTemplateForObject(obj *object, iteration, replicaIndex int, asJson bool) ([]byte, error) {
+	// Processing template
+	renderedObj, err := ex.renderObject(obj, iteration, replicaIndex)
 	if err != nil {
-		log.Fatalf("Template error in %s: %s", obj.ObjectTemplate, err)
+		return nil, fmt.Errorf("template error in %s: %s", obj.ObjectTemplate, err)
 	}
 
 	if asJson {
 		newObject := &unstructured.Unstructured{}
-		yamlToUnstructured(obj.ObjectTemplate, renderedObj, newObject)
+		if _, _, err := yamlToUnstructured(obj.ObjectTemplate, renderedObj, newObject); err != nil {
+			return nil, err
+		}
 		renderedObj, err = newObject.MarshalJSON()
 		if err != nil {
-			log.Fatalf("Error converting YAML to JSON")
+			return nil, fmt.Errorf("error converting %s from YAML to JSON: %v", obj.ObjectTemplate, err)
 		}
 	}
 
-	return renderedObj
+	return renderedObj, nil
 }
@@ -0,0 +1,134 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/version"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/util"
+	log "github.com/sirupsen/logrus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// RunResults final results of the benchmark, passed to the exit hooks
+type RunResults struct {
+	UUID            string         `json:"uuid"`
+	RunID           string         `json:"runid"`
+	Timestamp       time.Time      `json:"timestamp"`
+	EndTimestamp    time.Time      `json:"endTimestamp"`
+	Passed          bool           `json:"passed"`
+	ReturnCode      int            `json:"returnCode"`
+	ExecutionErrors string         `json:"executionErrors,omitempty"`
+	Version         string         `json:"version"`
+	JobSummaries    []JobSummary   `json:"jobSummaries"`
+	Metadata        map[string]any `json:"metadata,omitempty"`
+}
+
+func newRunResults(configSpec config.Spec, start time.Time, rc int, errs []error, jobSummaries []JobSummary, metadata map[string]any) RunResults {
+	results := RunResults{
+		UUID:         configSpec.GlobalConfig.UUID,
+		RunID:        configSpec.GlobalConfig.RUNID,
+		Timestamp:    start,
+		EndTimestamp: time.Now().UTC(),
+		Passed:       rc == 0,
+		ReturnCode:   rc,
+		Version:      fmt.Sprintf("%v@%v", version.Version, version.GitCommit),
+		JobSummaries: jobSummaries,
+		Metadata:     metadata,
+	}
+	if err := utilerrors.NewAggregate(errs); err != nil {
+		results.ExecutionErrors = util.RedactSecrets(err.Error())
+	}
+	return results
+}
+
+// runExitHooks sends the results JSON to the configured hooks, hook failures don't affect the benchmark return code
+func runExitHooks(hooks []config.ExitHook, results RunResults) {
+	if len(hooks) == 0 {
+		return
+	}
+	resultsJSON, err := json.Marshal(results)
+	if err != nil {
+		log.Errorf("Error encoding results for the exit hooks: %v", err)
+		return
+	}
+	for _, hook := range hooks {
+		ctx, cancel := context.WithTimeout(context.Background(), hook.Timeout)
+		if len(hook.Command) > 0 {
+			log.Infof("Running exit hook: %s", strings.Join(hook.Command, " "))
+			err = execExitHook(ctx, hook, results, resultsJSON)
+		} else {
+			log.Infof("Sending results to exit hook: %s", hook.URL)
+			err = postExitHook(ctx, hook, resultsJSON)
+		}
+		cancel()
+		if err != nil {
+			log.Errorf("Exit hook failed: %v", err)
+		}
+	}
+}
+
+// execExitHook runs the command with the results JSON on its stdin
+func execExitHook(ctx context.Context, hook config.ExitHook, results RunResults, resultsJSON []byte) error {
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	cmd.Stdin = bytes.NewReader(resultsJSON)
+	cmd.Env = append(os.Environ(),
+		"KUBE_BURNER_UUID="+results.UUID,
+		"KUBE_BURNER_RC="+strconv.Itoa(results.ReturnCode),
+		"KUBE_BURNER_PASSED="+strconv.FormatBool(results.Passed),
+	)
+	out, err := cmd.CombinedOutput()
+	if len(out) > 0 {
+		log.Infof("%s output:\n%s", hook.Command[0], strings.TrimSpace(string(out)))
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %v", hook.Command[0], err)
+	}
+	return nil
+}
+
+// postExitHook POSTs the results JSON to the hook URL
+func postExitHook(ctx context.Context, hook config.ExitHook, resultsJSON []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(resultsJSON))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range hook.Headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s %s", hook.URL, resp.Status, msg)
+	}
+	return nil
+}
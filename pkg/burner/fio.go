@@ -0,0 +1,329 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	"github.com/kube-burner/kube-burner/pkg/util"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Mount path of the PVC in the fio pods, the default job file runs the workload in it
+	fioDataDir = "/data"
+	fioJobFile = "job.fio"
+	fioPoll    = 5 * time.Second
+)
+
+// Default fio job file, rendered with the workload options of the job
+const fioDefaultJobFile = `[global]
+ioengine=libaio
+direct=1
+time_based=1
+runtime={{.runtime}}
+directory={{.directory}}
+size={{.fileSize}}
+bs={{.blockSize}}
+iodepth={{.ioDepth}}
+numjobs={{.numJobs}}
+group_reporting=1
+
+[{{.rw}}]
+rw={{.rw}}
+`
+
+// fioRunner runs the fio pods of a fio job and records their results
+type fioRunner struct {
+	config.FioBenchmark
+	jobFileTemplate []byte
+	pvcTemplate     []byte
+	mu              sync.Mutex
+	results         []prometheus.FioResult
+}
+
+// fioOutput subset of the fio JSON output
+type fioOutput struct {
+	Jobs []struct {
+		JobName string       `json:"jobname"`
+		Error   int          `json:"error"`
+		Read    fioDirection `json:"read"`
+		Write   fioDirection `json:"write"`
+		Trim    fioDirection `json:"trim"`
+	} `json:"jobs"`
+}
+
+type fioDirection struct {
+	IOBytes  int64   `json:"io_bytes"`
+	BwBytes  int64   `json:"bw_bytes"`
+	IOPS     float64 `json:"iops"`
+	Runtime  int64   `json:"runtime"`
+	ClatNano struct {
+		Mean       float64            `json:"mean"`
+		Percentile map[string]float64 `json:"percentile"`
+	} `json:"clat_ns"`
+}
+
+func (ex *JobExecutor) setupFioJob() {
+	if len(ex.ExecutionMode) == 0 {
+		ex.ExecutionMode = config.ExecutionModeParallel
+	}
+	ex.fio = &fioRunner{FioBenchmark: *ex.Fio}
+	readTemplate := func(path string) []byte {
+		f, err := fileutils.GetWorkloadReader(path, ex.embedCfg)
+		if err != nil {
+			log.Fatalf("Error reading template %s: %s", path, err)
+		}
+		t, err := io.ReadAll(f)
+		if err != nil {
+			log.Fatalf("Error reading template %s: %s", path, err)
+		}
+		return t
+	}
+	ex.fio.jobFileTemplate = []byte(fioDefaultJobFile)
+	if ex.Fio.JobFile != "" {
+		ex.fio.jobFileTemplate = readTemplate(ex.Fio.JobFile)
+	}
+	if ex.Fio.PVCTemplate != "" {
+		ex.fio.pvcTemplate = readTemplate(ex.Fio.PVCTemplate)
+	}
+	log.Infof("Job %s: %d iterations with fio pods using image %s", ex.Name, ex.JobIterations, ex.Fio.Image)
+}
+
+// runFioJob runs a fio pod with its own PVC per iteration, in the namespace of the iteration.
+// Namespaces are created by kube-burner, so that the pods and PVCs are garbage collected along with them
+func (ex *JobExecutor) runFioJob(ctx context.Context) {
+	ex.runIterations(ctx, true, ex.runFio)
+}
+
+// runFio creates the PVC, job file and pod of an iteration, waits for the pod to complete and parses its output
+func (ex *JobExecutor) runFio(ctx context.Context, iteration int, ns string) {
+	name := fmt.Sprintf("%s-%d", ex.Name, iteration)
+	result := prometheus.FioResult{
+		Timestamp: time.Now().UTC(),
+		Namespace: ns,
+		Pod:       name,
+		Iteration: iteration,
+	}
+	results, err := ex.fioIteration(ctx, iteration, ns, name, &result)
+	if err != nil {
+		log.Errorf("Error running fio of iteration %d in namespace %s: %v", iteration, ns, err)
+		result.Error = util.RedactSecrets(err.Error())
+		results = []prometheus.FioResult{result}
+		ex.recordError()
+	} else {
+		log.Debugf("Fio pod %s/%s completed", ns, name)
+		atomic.AddInt32(&ex.objectOperations, 1)
+	}
+	ex.fio.mu.Lock()
+	ex.fio.results = append(ex.fio.results, results...)
+	ex.fio.mu.Unlock()
+}
+
+// fioIteration runs the fio pod of an iteration and returns a result per fio job and I/O direction.
+// The fields shared by all of them are set in the given result
+func (ex *JobExecutor) fioIteration(ctx context.Context, iteration int, ns, name string, result *prometheus.FioResult) ([]prometheus.FioResult, error) {
+	labels := ex.objectLabels(0, iteration)
+	templateData := map[string]any{
+		jobName:      ex.Name,
+		jobIteration: iteration,
+		jobUUID:      ex.uuid,
+		jobRunId:     ex.runid,
+		"Namespace":  ns,
+		"runtime":    int(ex.Fio.Runtime.Seconds()),
+		"directory":  fioDataDir,
+		"fileSize":   ex.Fio.FileSize,
+		"blockSize":  ex.Fio.BlockSize,
+		"ioDepth":    ex.Fio.IODepth,
+		"numJobs":    ex.Fio.NumJobs,
+		"rw":         ex.Fio.RW,
+	}
+	pvc, err := ex.fio.pvc(name, templateData, ex.functionTemplates)
+	if err != nil {
+		return nil, err
+	}
+	if pvc.Labels == nil {
+		pvc.Labels = make(map[string]string)
+	}
+	maps.Copy(pvc.Labels, labels)
+	if pvc, err = ex.clientSet.CoreV1().PersistentVolumeClaims(ns).Create(ctx, pvc, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("error creating PVC: %v", err)
+	}
+	result.PVC = pvc.Name
+	if pvc.Spec.StorageClassName != nil {
+		result.StorageClass = *pvc.Spec.StorageClassName
+	}
+	jobFile, err := util.RenderTemplate(ex.fio.jobFileTemplate, templateData, util.MissingKeyError, ex.functionTemplates)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering job file: %v", err)
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Data:       map[string]string{fioJobFile: string(jobFile)},
+	}
+	if _, err := ex.clientSet.CoreV1().ConfigMaps(ns).Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("error creating job file: %v", err)
+	}
+	pod := ex.fio.pod(name, pvc.Name, labels)
+	if _, err := ex.clientSet.CoreV1().Pods(ns).Create(ctx, pod, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("error creating pod: %v", err)
+	}
+	err = wait.PollUntilContextTimeout(ctx, fioPoll, ex.Fio.Timeout, true, func(ctx context.Context) (bool, error) {
+		if pod, err = ex.clientSet.CoreV1().Pods(ns).Get(ctx, name, metav1.GetOptions{}); err != nil {
+			log.Debugf("Error getting fio pod %s/%s: %v", ns, name, err)
+			return false, nil
+		}
+		return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pod %s didn't complete within %v: %v", name, ex.Fio.Timeout, err)
+	}
+	result.NodeName = pod.Spec.NodeName
+	logs, err := ex.clientSet.CoreV1().Pods(ns).GetLogs(name, &corev1.PodLogOptions{Container: "fio"}).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting pod logs: %v", err)
+	}
+	if pod.Status.Phase == corev1.PodFailed {
+		return nil, fmt.Errorf("pod %s failed: %s", name, bytes.TrimSpace(logs))
+	}
+	return parseFioOutput(logs, *result)
+}
+
+// pvc returns the PVC of an iteration, rendered from the template or built from the storage class and volume size
+func (f *fioRunner) pvc(name string, templateData map[string]any, functionTemplates []string) (*corev1.PersistentVolumeClaim, error) {
+	pvc := &corev1.PersistentVolumeClaim{}
+	if f.pvcTemplate != nil {
+		rendered, err := util.RenderTemplate(f.pvcTemplate, templateData, util.MissingKeyError, functionTemplates)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering PVC template %s: %v", f.PVCTemplate, err)
+		}
+		if err := yaml.Unmarshal(rendered, pvc); err != nil {
+			return nil, fmt.Errorf("error decoding PVC template %s: %v", f.PVCTemplate, err)
+		}
+		if pvc.Name == "" {
+			pvc.Name = name
+		}
+		return pvc, nil
+	}
+	pvc.Name = name
+	pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+	pvc.Spec.Resources.Requests = corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(f.VolumeSize)}
+	if f.StorageClass != "" {
+		pvc.Spec.StorageClassName = &f.StorageClass
+	}
+	return pvc, nil
+}
+
+// pod returns the fio pod of an iteration, running the job file mounted from its ConfigMap against the PVC
+func (f *fioRunner) pod(name, pvc string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			NodeSelector:  f.NodeSelector,
+			Containers: []corev1.Container{{
+				Name:         "fio",
+				Image:        f.Image,
+				Command:      []string{"fio", "--output-format=json", "/etc/fio/" + fioJobFile},
+				VolumeMounts: []corev1.VolumeMount{{Name: "data", MountPath: fioDataDir}, {Name: "job", MountPath: "/etc/fio"}},
+			}},
+			Volumes: []corev1.Volume{
+				{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc}}},
+				{Name: "job", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}}}},
+			},
+		},
+	}
+}
+
+// parseFioOutput returns a result per fio job and I/O direction, directions without I/O are skipped.
+// fio may log warnings before its JSON output
+func parseFioOutput(logs []byte, base prometheus.FioResult) ([]prometheus.FioResult, error) {
+	start := bytes.IndexByte(logs, '{')
+	if start < 0 {
+		return nil, fmt.Errorf("fio JSON output not found: %s", bytes.TrimSpace(logs))
+	}
+	var output fioOutput
+	if err := json.NewDecoder(bytes.NewReader(logs[start:])).Decode(&output); err != nil {
+		return nil, fmt.Errorf("error decoding fio output: %v", err)
+	}
+	var results []prometheus.FioResult
+	for _, job := range output.Jobs {
+		if job.Error != 0 {
+			return nil, fmt.Errorf("fio job %s failed with error %d", job.JobName, job.Error)
+		}
+		for _, d := range []struct {
+			operation string
+			direction fioDirection
+		}{{"read", job.Read}, {"write", job.Write}, {"trim", job.Trim}} {
+			operation, direction := d.operation, d.direction
+			if direction.IOBytes == 0 {
+				continue
+			}
+			result := base
+			result.FioJob = job.JobName
+			result.Operation = operation
+			result.IOPS = direction.IOPS
+			result.Bandwidth = direction.BwBytes
+			result.IOBytes = direction.IOBytes
+			result.Runtime = direction.Runtime
+			result.LatencyAvg = direction.ClatNano.Mean / 1e3
+			result.LatencyP50 = direction.ClatNano.Percentile["50.000000"] / 1e3
+			result.LatencyP95 = direction.ClatNano.Percentile["95.000000"] / 1e3
+			result.LatencyP99 = direction.ClatNano.Percentile["99.000000"] / 1e3
+			result.LatencyP999 = direction.ClatNano.Percentile["99.900000"] / 1e3
+			log.Infof("Fio %s/%s %s %s: %.0f IOPS, %.2f MiB/s, p99 latency %.0fµs", base.Namespace, base.Pod, job.JobName, operation, result.IOPS, float64(result.Bandwidth)/(1<<20), result.LatencyP99)
+			results = append(results, result)
+		}
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("fio didn't perform any I/O")
+	}
+	return results, nil
+}
+
+// summary returns the results of the fio pods, it's nil-safe
+func (f *fioRunner) summary() []prometheus.FioResult {
+	if f == nil {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var failed int
+	for _, result := range f.results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		log.Warnf("%d fio pods failed", failed)
+	}
+	return f.results
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	"github.com/kube-burner/kube-burner/pkg/util"
+	"github.com/kube-burner/kube-burner/pkg/util/junit"
+	log "github.com/sirupsen/logrus"
+)
+
+// Maximum size of the summary of a check run accepted by GitHub
+const gitHubCheckSummaryLimit = 65535
+
+// publishGitHubReport posts the summary of the benchmark as a check run or pull request comment, failures don't
+// affect the benchmark return code. In A/B benchmarks only cluster a, which holds the comparisons, publishes it
+func publishGitHubReport(globalConfig config.GlobalConfig, results RunResults, executedJobs []prometheus.Job) {
+	report := globalConfig.GitHubReport
+	if report == nil || globalConfig.CompareSide == util.CompareSideB {
+		return
+	}
+	// Workers shard the iterations of the benchmark, only the first one publishes its share
+	if globalConfig.Workers > 1 && globalConfig.WorkerIndex != 0 {
+		return
+	}
+	summary := gitHubSummary(results, executedJobs)
+	ctx, cancel := context.WithTimeout(context.Background(), report.Timeout)
+	defer cancel()
+	var err error
+	if report.Mode == config.GitHubComment {
+		log.Infof("Commenting the benchmark summary on pull request %s#%d", report.Repository, report.PullRequest)
+		err = postGitHub(ctx, report, fmt.Sprintf("issues/%d/comments", report.PullRequest), map[string]any{"body": summary})
+	} else {
+		log.Infof("Publishing the benchmark summary as check run %q of %s@%s", report.Name, report.Repository, report.SHA)
+		conclusion := "success"
+		if !results.Passed {
+			conclusion = "failure"
+		}
+		if len(summary) > gitHubCheckSummaryLimit {
+			summary = strings.ToValidUTF8(summary[:gitHubCheckSummaryLimit-len("\n…")], "") + "\n…"
+		}
+		err = postGitHub(ctx, report, "check-runs", map[string]any{
+			"name":         report.Name,
+			"head_sha":     report.SHA,
+			"status":       "completed",
+			"started_at":   results.Timestamp.Format(time.RFC3339),
+			"completed_at": results.EndTimestamp.Format(time.RFC3339),
+			"conclusion":   conclusion,
+			"output": map[string]string{
+				"title":   fmt.Sprintf("kube-burner %s", gitHubResult(results.Passed)),
+				"summary": summary,
+			},
+		})
+	}
+	if err != nil {
+		log.Errorf("Error publishing the benchmark summary on GitHub: %v", err)
+	}
+}
+
+// postGitHub POSTs the body to the given path of the repository API
+func postGitHub(ctx context.Context, report *config.GitHubReport, path string, body map[string]any) error {
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/repos/%s/%s", strings.TrimSuffix(report.APIURL, "/"), report.Repository, path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyJSON))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+report.Token)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s %s", url, resp.Status, msg)
+	}
+	return nil
+}
+
+func gitHubResult(passed bool) string {
+	if passed {
+		return "✅ passed"
+	}
+	return "❌ failed"
+}
+
+// gitHubSummary renders the results of the benchmark in markdown: jobs, failed thresholds and alerts, and the
+// comparison with the other cluster of A/B benchmarks
+func gitHubSummary(results RunResults, executedJobs []prometheus.Job) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "### kube-burner %s\n\n", gitHubResult(results.Passed))
+	fmt.Fprintf(&sb, "UUID `%s`, version `%s`, return code %d, took %v\n\n", results.UUID, results.Version, results.ReturnCode, results.EndTimestamp.Sub(results.Timestamp).Round(time.Second))
+	if len(results.JobSummaries) > 0 {
+		sb.WriteString("| Job | Type | Iterations | Elapsed | Achieved QPS | Result |\n|---|---|---|---|---|---|\n")
+		for _, jobSummary := range results.JobSummaries {
+			fmt.Fprintf(&sb, "| %s | %s | %d | %vs | %v | %s |\n", jobSummary.JobConfig.Name, jobSummary.JobConfig.JobType, jobSummary.JobConfig.JobIterations,
+				jobSummary.ElapsedTime, jobSummary.AchievedQps, gitHubResult(jobSummary.Passed))
+		}
+		sb.WriteString("\n")
+	}
+	if results.ExecutionErrors != "" {
+		fmt.Fprintf(&sb, "**Execution errors**\n\n```\n%s\n```\n\n", results.ExecutionErrors)
+	}
+	for _, suite := range []struct {
+		name  string
+		title string
+	}{
+		{junit.SuiteThresholds, "Failed thresholds"},
+		{junit.SuiteAlerts, "Alerts"},
+	} {
+		if failures := junit.Failures(suite.name); len(failures) > 0 {
+			fmt.Fprintf(&sb, "**%s**\n\n", suite.title)
+			for _, failure := range failures {
+				fmt.Fprintf(&sb, "- %s\n", failure)
+			}
+			sb.WriteString("\n")
+		}
+	}
+	var compared bool
+	for _, job := range executedJobs {
+		c := job.Comparison
+		if c == nil {
+			continue
+		}
+		if !compared {
+			sb.WriteString("**A/B comparison**, deltas of cluster b over cluster a\n\n| Job | Elapsed a | Elapsed b | Elapsed Δ | QPS a | QPS b | QPS Δ |\n|---|---|---|---|---|---|---|\n")
+			compared = true
+		}
+		fmt.Fprintf(&sb, "| %s | %vs | %vs | %+.2f%% | %v | %v | %+.2f%% |\n", job.JobConfig.Name, c.A.ElapsedTime, c.B.ElapsedTime, c.ElapsedTimeDelta,
+			c.A.AchievedQps, c.B.AchievedQps, c.AchievedQpsDelta)
+	}
+	if compared {
+		sb.WriteString("\n")
+		for _, job := range executedJobs {
+			if job.Comparison == nil || len(job.Comparison.QuantileDeltas) == 0 {
+				continue
+			}
+			fmt.Fprintf(&sb, "<details><summary>%s latency quantiles</summary>\n\n| Quantile | a | b | Δ |\n|---|---|---|---|\n", job.JobConfig.Name)
+			names := make([]string, 0, len(job.Comparison.QuantileDeltas))
+			for name := range job.Comparison.QuantileDeltas {
+				names = append(names, name)
+			}
+			slices.Sort(names)
+			for _, name := range names {
+				fmt.Fprintf(&sb, "| %s | %v | %v | %+.2f%% |\n", name, job.Comparison.A.Quantiles[name], job.Comparison.B.Quantiles[name], job.Comparison.QuantileDeltas[name])
+			}
+			sb.WriteString("\n</details>\n\n")
+		}
+	}
+	return sb.String()
+}
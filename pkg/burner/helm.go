@@ -0,0 +1,188 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	"github.com/kube-burner/kube-burner/pkg/util"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+)
+
+// Time given to helm to give up once the release timeout is reached
+const helmTimeoutMargin = time.Minute
+
+// helmRunner runs the helm commands of a helm job and records the outcome of the releases
+type helmRunner struct {
+	config.HelmChart
+	kubeConfig     string
+	kubeContext    string
+	valuesTemplate []byte
+	mu             sync.Mutex
+	releases       []prometheus.HelmRelease
+}
+
+func (ex *JobExecutor) setupHelmJob(kubeClientProvider *config.KubeClientProvider) {
+	if _, err := exec.LookPath(ex.Helm.Binary); err != nil {
+		log.Fatalf("Job %s: %v", ex.Name, err)
+	}
+	if len(ex.ExecutionMode) == 0 {
+		ex.ExecutionMode = config.ExecutionModeParallel
+	}
+	ex.helm = &helmRunner{HelmChart: *ex.Helm}
+	ex.helm.kubeConfig, ex.helm.kubeContext = kubeClientProvider.KubeConfig()
+	if ex.Helm.Values != "" {
+		f, err := fileutils.GetWorkloadReader(ex.Helm.Values, ex.embedCfg)
+		if err != nil {
+			log.Fatalf("Error reading values %s: %s", ex.Helm.Values, err)
+		}
+		if ex.helm.valuesTemplate, err = io.ReadAll(f); err != nil {
+			log.Fatalf("Error reading values %s: %s", ex.Helm.Values, err)
+		}
+	}
+	log.Infof("Job %s: %d iterations with %s of chart %s", ex.Name, ex.JobIterations, ex.Helm.Operation, ex.Helm.Chart)
+}
+
+// runHelmJob performs the helm operation of the job once per iteration, in the namespace of the iteration.
+// Namespaces are created by kube-burner, so that the releases are garbage collected along with them
+func (ex *JobExecutor) runHelmJob(ctx context.Context) {
+	ex.runIterations(ctx, ex.Helm.Operation == config.HelmInstall, ex.runHelm)
+}
+
+// runHelm performs the helm operation of an iteration
+func (ex *JobExecutor) runHelm(ctx context.Context, iteration int, ns string) {
+	templateData := map[string]any{
+		jobName:      ex.Name,
+		jobIteration: iteration,
+		jobUUID:      ex.uuid,
+		jobRunId:     ex.runid,
+		"Namespace":  ns,
+	}
+	release := prometheus.HelmRelease{
+		Timestamp: time.Now().UTC(),
+		Namespace: ns,
+		Iteration: iteration,
+		Operation: string(ex.Helm.Operation),
+	}
+	var args []string
+	var values []byte
+	var err error
+	release.Release, args, values, err = ex.helm.args(ns, templateData, ex.functionTemplates)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(ctx, ex.Helm.Timeout+helmTimeoutMargin)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, ex.Helm.Binary, args...)
+		cmd.Stdin = bytes.NewReader(values)
+		var out []byte
+		start := time.Now()
+		out, err = cmd.CombinedOutput()
+		release.Latency = time.Since(start).Milliseconds()
+		if err != nil {
+			err = fmt.Errorf("%v: %s", err, strings.TrimSpace(string(out)))
+		}
+	}
+	if err != nil {
+		log.Errorf("Error running helm %s of iteration %d in namespace %s: %v", ex.Helm.Operation, iteration, ns, err)
+		release.Error = util.RedactSecrets(err.Error())
+		ex.recordError()
+	} else {
+		log.Debugf("Helm %s of release %s in namespace %s completed in %dms", ex.Helm.Operation, release.Release, ns, release.Latency)
+		atomic.AddInt32(&ex.objectOperations, 1)
+	}
+	ex.helm.mu.Lock()
+	ex.helm.releases = append(ex.helm.releases, release)
+	ex.helm.mu.Unlock()
+}
+
+// args returns the release name, the helm arguments and the rendered values of an iteration
+func (h *helmRunner) args(ns string, templateData map[string]any, functionTemplates []string) (string, []string, []byte, error) {
+	render := func(t string) (string, error) {
+		rendered, err := util.RenderTemplate([]byte(t), templateData, util.MissingKeyError, functionTemplates)
+		return string(rendered), err
+	}
+	release, err := render(h.ReleaseName)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("error rendering release name: %v", err)
+	}
+	var args []string
+	var values []byte
+	if h.Operation == config.HelmUninstall {
+		args = []string{"uninstall", release, "--namespace", ns}
+	} else {
+		args = []string{"upgrade", "--install", release, h.Chart, "--namespace", ns}
+		if h.Version != "" {
+			args = append(args, "--version", h.Version)
+		}
+		if h.Repo != "" {
+			args = append(args, "--repo", h.Repo)
+		}
+		if h.valuesTemplate != nil {
+			if values, err = util.RenderTemplate(h.valuesTemplate, templateData, util.MissingKeyError, functionTemplates); err != nil {
+				return release, nil, nil, fmt.Errorf("error rendering values %s: %v", h.Values, err)
+			}
+			args = append(args, "--values", "-")
+		}
+		for _, key := range slices.Sorted(maps.Keys(h.Set)) {
+			value, err := render(h.Set[key])
+			if err != nil {
+				return release, nil, nil, fmt.Errorf("error rendering value %s: %v", key, err)
+			}
+			args = append(args, "--set", key+"="+value)
+		}
+	}
+	args = append(args, "--timeout", h.Timeout.String())
+	if h.Wait {
+		args = append(args, "--wait")
+	}
+	if h.kubeConfig != "" {
+		args = append(args, "--kubeconfig", h.kubeConfig)
+	}
+	if h.kubeContext != "" {
+		args = append(args, "--kube-context", h.kubeContext)
+	}
+	return release, append(args, h.ExtraArgs...), values, nil
+}
+
+// summary returns the outcome of the releases, it's nil-safe
+func (h *helmRunner) summary() []prometheus.HelmRelease {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var failed int
+	for _, release := range h.releases {
+		if release.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		log.Warnf("%d/%d helm releases failed", failed, len(h.releases))
+	}
+	return h.releases
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	log "github.com/sirupsen/logrus"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// Lifetime requested for the service account tokens, the API server may shorten it
+const identityTokenExpiration = 24 * time.Hour
+
+// identityPool clients of the identities of a job, the object requests of each iteration are sent with one of them
+type identityPool struct {
+	identities []*identity
+	// rateLimiter shared by the clients of the identities
+	rateLimiter flowcontrol.RateLimiter
+}
+
+type identity struct {
+	name     string
+	client   *dynamic.DynamicClient
+	requests atomic.Int64
+}
+
+// countingTransport counts the requests sent with an identity
+type countingTransport struct {
+	http.RoundTripper
+	requests *atomic.Int64
+}
+
+func (ct *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ct.requests.Add(1)
+	return ct.RoundTripper.RoundTrip(req)
+}
+
+// setupIdentities creates a client per identity of the job, sharing the rate limiter of the job clients
+func (ex *JobExecutor) setupIdentities() {
+	if len(ex.Identities) == 0 {
+		return
+	}
+	rateLimiter := ex.restConfig.RateLimiter
+	if rateLimiter == nil {
+		rateLimiter = flowcontrol.NewTokenBucketRateLimiter(ex.restConfig.QPS, ex.restConfig.Burst)
+	}
+	ex.identities = &identityPool{rateLimiter: rateLimiter}
+	for _, id := range ex.Identities {
+		var restConfig *rest.Config
+		if id.Impersonate != "" {
+			restConfig = rest.CopyConfig(ex.restConfig)
+			restConfig.Impersonate = rest.ImpersonationConfig{UserName: id.Impersonate, Groups: id.ImpersonateGroups}
+		} else {
+			// Requests must only carry the credentials of the identity
+			restConfig = rest.AnonymousClientConfig(ex.restConfig)
+			switch {
+			case id.Token != "":
+				restConfig.BearerToken = id.Token
+			case id.TokenFile != "":
+				restConfig.BearerTokenFile = id.TokenFile
+			default:
+				restConfig.BearerToken = ex.serviceAccountToken(id)
+			}
+		}
+		restConfig.RateLimiter = rateLimiter
+		identity := &identity{name: id.Name}
+		restConfig.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &countingTransport{RoundTripper: rt, requests: &identity.requests}
+		})
+		identity.client = dynamic.NewForConfigOrDie(restConfig)
+		ex.identities.identities = append(ex.identities.identities, identity)
+	}
+	log.Infof("Job %s: object requests round-robined across %d identities", ex.Name, len(ex.identities.identities))
+}
+
+// serviceAccountToken requests a token of the service account of the identity
+func (ex *JobExecutor) serviceAccountToken(id config.Identity) string {
+	namespace, name, _ := strings.Cut(id.ServiceAccount, "/")
+	expiration := int64(identityTokenExpiration.Seconds())
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expiration},
+	}
+	token, err := ex.clientSet.CoreV1().ServiceAccounts(namespace).CreateToken(context.TODO(), name, tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		log.Fatalf("Job %s: error requesting a token of service account %s: %v", ex.Name, id.ServiceAccount, err)
+	}
+	return token.Status.Token
+}
+
+// client returns the client of the object requests of the given iteration, the job client when the job has no identities
+func (ex *JobExecutor) client(iteration int) dynamic.Interface {
+	if ex.identities == nil {
+		return ex.dynamicClient
+	}
+	return ex.identities.identities[iteration%len(ex.identities.identities)].client
+}
+
+// summary returns the requests sent with each identity and logs them
+func (ip *identityPool) summary() map[string]int64 {
+	if ip == nil {
+		return nil
+	}
+	requests := make(map[string]int64, len(ip.identities))
+	for _, identity := range ip.identities {
+		requests[identity.name] = identity.requests.Load()
+		log.Infof("Identity %s: %d requests", identity.name, requests[identity.name])
+	}
+	return requests
+}
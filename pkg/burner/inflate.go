@@ -0,0 +1,207 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	"github.com/prometheus/common/expfmt"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// API server metrics reporting the size of the etcd database, the first one replaces the second since Kubernetes 1.28
+var etcdDBSizeMetrics = []string{"apiserver_storage_size_bytes", "apiserver_storage_db_total_size_in_bytes"}
+
+// inflateRunner grows the objects of an inflate job and records their size and update latency by iteration
+type inflateRunner struct {
+	config.Inflate
+	// padding of the maximum size, the padding of each iteration is a prefix of it
+	padding string
+	mu      sync.Mutex
+	// Updates of the object being grown in the current iteration
+	iteration int
+	latencies []float64
+	sizes     []int64
+	errors    int
+	samples   []prometheus.InflateSample
+}
+
+func (ex *JobExecutor) setupInflateJob(mapper meta.RESTMapper) {
+	log.Debugf("Preparing inflate job: %s", ex.Name)
+	ex.itemHandler = inflateHandler
+	ex.objectFinalizer = inflateFinalizer
+	// Objects must grow an iteration after another
+	ex.ExecutionMode = config.ExecutionModeSequential
+	ex.inflate = &inflateRunner{
+		Inflate: *ex.Inflate,
+		padding: strings.Repeat("x", ex.Inflate.MaxSize),
+	}
+	for _, o := range ex.Objects {
+		log.Infof("Job %s: %s %s with selector %s", ex.Name, ex.JobType, o.Kind, labels.Set(o.LabelSelector))
+		ex.objects = append(ex.objects, newObject(o, mapper, APIVersionV1, ex.embedCfg))
+	}
+	if maxIterations := int(math.Ceil(float64(ex.Inflate.MaxSize) / float64(ex.Inflate.BytesPerIteration))); ex.JobIterations > maxIterations {
+		log.Warnf("Job %s: the padding reaches %d bytes at iteration %d, the remaining iterations update the objects without growing them", ex.Name, ex.Inflate.MaxSize, maxIterations)
+	}
+	log.Infof("Job %s: %d iterations growing the %s %s by %d bytes", ex.Name, ex.JobIterations, ex.Inflate.Target, ex.Inflate.Field, ex.Inflate.BytesPerIteration)
+}
+
+// patch returns the merge patch setting the padding of the given size
+func (ir *inflateRunner) patch(size int) ([]byte, []string) {
+	padding := map[string]string{ir.Field: ir.padding[:size]}
+	switch ir.Target {
+	case config.InflateData:
+		data, _ := json.Marshal(map[string]any{"data": padding})
+		return data, nil
+	case config.InflateStatus:
+		data, _ := json.Marshal(map[string]any{"status": padding})
+		return data, []string{"status"}
+	default:
+		data, _ := json.Marshal(map[string]any{"metadata": map[string]any{"annotations": padding}})
+		return data, nil
+	}
+}
+
+func inflateHandler(ex *JobExecutor, obj *object, item unstructured.Unstructured, iteration int, objectTimeUTC int64, wg *sync.WaitGroup) {
+	defer wg.Done()
+	size := min((iteration+1)*ex.inflate.BytesPerIteration, ex.inflate.MaxSize)
+	data, subresources := ex.inflate.patch(size)
+	// The job context is cancelled once the job breaches its error limits
+	if ex.limiter.Wait(ex.jobContext()) != nil {
+		return
+	}
+	patchOptions := metav1.PatchOptions{FieldValidation: ex.fieldValidation}
+	start := time.Now()
+	var uns *unstructured.Unstructured
+	var err error
+	if obj.namespaced {
+		uns, err = ex.client(iteration).Resource(obj.gvr).Namespace(item.GetNamespace()).Patch(context.TODO(), item.GetName(), types.MergePatchType, data, patchOptions, subresources...)
+	} else {
+		uns, err = ex.client(iteration).Resource(obj.gvr).Patch(context.TODO(), item.GetName(), types.MergePatchType, data, patchOptions, subresources...)
+	}
+	latency := float64(time.Since(start).Microseconds()) / 1000
+	ex.inflate.mu.Lock()
+	defer ex.inflate.mu.Unlock()
+	ex.inflate.iteration = iteration
+	if err != nil {
+		if errors.IsForbidden(err) {
+			log.Fatalf("Authorization error inflating %s/%s: %s", item.GetKind(), item.GetName(), err)
+		}
+		log.Errorf("Error inflating %s/%s in namespace %s: %s", item.GetKind(), item.GetName(), item.GetNamespace(), err)
+		ex.inflate.errors++
+		ex.recordError()
+		return
+	}
+	objectSize, _ := json.Marshal(uns.Object)
+	ex.inflate.latencies = append(ex.inflate.latencies, latency)
+	ex.inflate.sizes = append(ex.inflate.sizes, int64(len(objectSize)))
+	atomic.AddInt32(&ex.objectOperations, 1)
+}
+
+// inflateFinalizer records the sample of the object grown in the iteration, along with the etcd database size
+func inflateFinalizer(ex *JobExecutor, obj *object) {
+	dbSize := etcdDBSize(ex)
+	ir := ex.inflate
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	if len(ir.sizes) == 0 && ir.errors == 0 {
+		return
+	}
+	sample := prometheus.InflateSample{
+		Timestamp:   time.Now().UTC(),
+		Iteration:   ir.iteration,
+		Kind:        obj.Kind,
+		Objects:     len(ir.sizes),
+		PaddingSize: min((ir.iteration+1)*ir.BytesPerIteration, ir.MaxSize),
+		DBSize:      dbSize,
+		Errors:      ir.errors,
+	}
+	if len(ir.sizes) > 0 {
+		var totalSize int64
+		for _, size := range ir.sizes {
+			totalSize += size
+			sample.MaxObjectSize = max(sample.MaxObjectSize, size)
+		}
+		sample.AvgObjectSize = totalSize / int64(len(ir.sizes))
+		slices.Sort(ir.latencies)
+		for _, latency := range ir.latencies {
+			sample.LatencyAvg += latency
+		}
+		sample.LatencyAvg = round2(sample.LatencyAvg / float64(len(ir.latencies)))
+		sample.LatencyP99 = ir.latencies[int(math.Ceil(float64(len(ir.latencies))*0.99))-1]
+		sample.LatencyMax = ir.latencies[len(ir.latencies)-1]
+	}
+	log.Debugf("Job %s: %d %s grown to %d bytes in %.2fms on average", ex.Name, sample.Objects, obj.Kind, sample.AvgObjectSize, sample.LatencyAvg)
+	ir.samples = append(ir.samples, sample)
+	ir.latencies, ir.sizes, ir.errors = nil, nil, 0
+}
+
+// etcdDBSize returns the size of the etcd database reported by the API server, 0 when not available
+func etcdDBSize(ex *JobExecutor) int64 {
+	data, err := ex.clientSet.CoreV1().RESTClient().Get().AbsPath("/metrics").DoRaw(context.TODO())
+	if err != nil {
+		log.Debugf("Job %s: error getting the API server metrics: %v", ex.Name, err)
+		return 0
+	}
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(data))
+	if err != nil {
+		log.Debugf("Job %s: error parsing the API server metrics: %v", ex.Name, err)
+		return 0
+	}
+	for _, name := range etcdDBSizeMetrics {
+		family, ok := families[name]
+		if !ok {
+			continue
+		}
+		// A series per etcd cluster or endpoint, the largest one holds the objects
+		var dbSize float64
+		for _, metric := range family.GetMetric() {
+			dbSize = max(dbSize, metric.GetGauge().GetValue())
+		}
+		return int64(dbSize)
+	}
+	return 0
+}
+
+// summary returns the samples of the job and logs the last one
+func (ir *inflateRunner) summary() []prometheus.InflateSample {
+	if ir == nil {
+		return nil
+	}
+	ir.mu.Lock()
+	defer ir.mu.Unlock()
+	if len(ir.samples) > 0 {
+		last := ir.samples[len(ir.samples)-1]
+		log.Infof("Objects inflated to %d bytes on average, last updates taking %.2fms on average, etcd database size %d bytes", last.AvgObjectSize, last.LatencyAvg, last.DBSize)
+	}
+	return ir.samples
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// iterationPacer starts the iterations of calendar-paced jobs at wall-clock slots, evenly spaced and aligned to the
+// minute. An iteration still running when its following slots come skips them instead of starting a burst of late
+// iterations, so that the arrival rate never exceeds the configured one
+type iterationPacer struct {
+	// period time between the slots of this worker, workers take turns so that together they fill every slot
+	period time.Duration
+	offset time.Duration
+	// slot of the running iteration, zero before the first iteration of a creation phase
+	slot  time.Time
+	stats prometheus.IterationPacing
+}
+
+func newIterationPacer(iterationsPerMinute float64, workers, workerIndex int) *iterationPacer {
+	interval := time.Duration(float64(time.Minute) / iterationsPerMinute)
+	workers = max(workers, 1)
+	return &iterationPacer{
+		period: interval * time.Duration(workers),
+		offset: interval * time.Duration(workerIndex),
+		stats:  prometheus.IterationPacing{IterationsPerMinute: iterationsPerMinute},
+	}
+}
+
+// begin starts a creation phase, like the initial creation or a churn cycle. The slots passed between phases
+// aren't accounted as skipped
+func (p *iterationPacer) begin() {
+	p.slot = time.Time{}
+}
+
+// wait blocks until the next free slot
+func (p *iterationPacer) wait(ctx context.Context) error {
+	now := time.Now()
+	next := now.Add(-p.offset).Truncate(p.period).Add(p.offset)
+	if next.Before(now) {
+		next = next.Add(p.period)
+	}
+	if !p.slot.IsZero() {
+		p.stats.MaxIterationTime = max(p.stats.MaxIterationTime, now.Sub(p.slot).Milliseconds())
+		if skipped := int(next.Sub(p.slot)/p.period) - 1; skipped > 0 {
+			log.Debugf("Iteration overran its slot by %v, skipping %d slots", now.Sub(p.slot.Add(p.period)).Round(time.Millisecond), skipped)
+			p.stats.SkippedSlots += skipped
+			p.stats.Slots += skipped
+		}
+	}
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+	p.slot = next
+	p.stats.Slots++
+	p.stats.Iterations++
+	return nil
+}
+
+// summary returns the slot accounting of the job, it's nil-safe
+func (p *iterationPacer) summary() *prometheus.IterationPacing {
+	if p == nil {
+		return nil
+	}
+	stats := p.stats
+	if stats.SkippedSlots > 0 {
+		log.Warnf("%d/%d iteration slots were skipped, iterations took up to %v", stats.SkippedSlots, stats.Slots, time.Duration(stats.MaxIterationTime)*time.Millisecond)
+	}
+	return &stats
+}
@@ -15,6 +15,7 @@
 package burner
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
@@ -31,11 +32,13 @@ import (
 	"github.com/kube-burner/kube-burner/pkg/prometheus"
 	"github.com/kube-burner/kube-burner/pkg/util"
 	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	"github.com/kube-burner/kube-burner/pkg/util/junit"
 	"github.com/kube-burner/kube-burner/pkg/util/metrics"
 	"github.com/kube-burner/kube-burner/pkg/watchers"
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/time/rate"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 )
 
@@ -45,15 +48,38 @@ type returnPair struct {
 	executionErrors string
 }
 
+type runResult struct {
+	rc           int
+	jobSummaries []JobSummary
+	executedJobs []prometheus.Job
+	errs         []error
+	// gcCtx context of the garbage collection started when the benchmark finished, nil when it didn't start
+	gcCtx    context.Context
+	cancelGC context.CancelFunc
+}
+
+// runProgress snapshot of the jobs started so far and the errors raised, published by the goroutine running the jobs
+// so that a halted benchmark doesn't read the slices being appended to
+type runProgress struct {
+	executedJobs      []prometheus.Job
+	executedExecutors []JobExecutor
+	errs              []error
+}
+
 const (
 	jobName              = "JobName"
 	replica              = "Replica"
+	paddedIteration      = "PaddedIteration"
+	paddedReplica        = "PaddedReplica"
 	jobIteration         = "Iteration"
 	jobUUID              = "UUID"
 	jobRunId             = "RunID"
+	architecture         = "Architecture"
 	rcTimeout            = 2
 	rcAlert              = 3
 	rcMeasurement        = 4
+	rcNodeWatchdog       = 5
+	rcClusterHealth      = 6
 	garbageCollectionJob = "garbage-collection"
 	APIVersionV1         = "v1"
 )
@@ -69,41 +95,161 @@ var (
 // Returns:
 // - error code
 // - error
+func Run(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider, metricsScraper metrics.Scraper, additionalMeasurementFactoryMap map[string]measurements.NewMeasurementFactory, embedCfg *fileutils.EmbedConfiguration) (int, error) {
+	return RunWithContext(context.Background(), configSpec, kubeClientProvider, metricsScraper, additionalMeasurementFactoryMap, embedCfg)
+}
+
+// RunWithContext runs the benchmark as Run does, halting it as its timeout does when the given context is cancelled
 //
 //nolint:gocyclo
-func Run(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider, metricsScraper metrics.Scraper, additionalMeasurementFactoryMap map[string]measurements.NewMeasurementFactory, embedCfg *fileutils.EmbedConfiguration) (int, error) {
+func RunWithContext(parentCtx context.Context, configSpec config.Spec, kubeClientProvider *config.KubeClientProvider, metricsScraper metrics.Scraper, additionalMeasurementFactoryMap map[string]measurements.NewMeasurementFactory, embedCfg *fileutils.EmbedConfiguration) (int, error) {
 	var err error
 	var rc int
 	var executedJobs []prometheus.Job
-	var jobExecutors []JobExecutor
+	var jobSummaries []JobSummary
 	var msWg, gcWg sync.WaitGroup
 	var gcCtx context.Context
 	var cancelGC context.CancelFunc
 	errs := []error{}
-	res := make(chan int, 1)
+	res := make(chan runResult, 1)
+	progress := make(chan runProgress, 1)
+	start := time.Now().UTC()
 	uuid := configSpec.GlobalConfig.UUID
 	globalConfig := configSpec.GlobalConfig
 	globalWaitMap := make(map[string][]string)
 	executorMap := make(map[string]JobExecutor)
-	returnMap := make(map[string]returnPair)
+	jobBreaches := make(map[string]error)
+	jobStatuses := make(map[string]jobStatus)
+	// jobOutcomes outcome of the jobs that ran to completion, read by the garbage collection of halted benchmarks
+	var jobOutcomes sync.Map
+	meshBaselines := make(map[string]prometheus.MeshPhaseStats)
+	schedulerColds := make(map[string]prometheus.SchedulerPhaseStats)
 	timeoutGCStarted := false
 	log.Infof("🔥 Starting kube-burner (%s@%s) with UUID %s", version.Version, version.GitCommit, uuid)
-	ctx, cancel := context.WithTimeout(context.Background(), configSpec.GlobalConfig.Timeout)
+	if err := checkProductionGuard(globalConfig.ProductionGuard, kubeClientProvider); err != nil {
+		return 1, err
+	}
+	if err := checkBudget(configSpec, embedCfg, kubeClientProvider); err != nil {
+		return 1, err
+	}
+	for _, prometheusClient := range metricsScraper.PrometheusClients {
+		if err := prometheusClient.CheckTargets(); err != nil {
+			return 1, err
+		}
+	}
+	jobExecutors, err := newExecutorList(configSpec, kubeClientProvider, embedCfg)
+	if err != nil {
+		return 1, err
+	}
+	clientMonitor := startClientMonitor(globalConfig.ClientMetrics)
+	defer clientMonitor.stop()
+	phases := newPhaseRecorder(globalConfig, kubeClientProvider)
+	for _, recordingRules := range metricsScraper.RecordingRules {
+		if err := recordingRules.Install(kubeClientProvider, uuid); err != nil {
+			log.Error(err.Error())
+		}
+	}
+	ctx, cancel := context.WithTimeout(parentCtx, configSpec.GlobalConfig.Timeout)
 	defer cancel()
+	nodeWatchdog := startNodeWatchdog(globalConfig.NodeWatchdog, kubeClientProvider, cancel)
+	defer nodeWatchdog.stop()
+	alertMonitor := startAlertMonitor(globalConfig.AlertInterval, metricsScraper.AlertMs, cancel)
+	defer alertMonitor.stop()
+	alertmanager := startAlertmanagerPoller(globalConfig.Alertmanager, kubeClientProvider)
+	defer alertmanager.stop()
+	liveReconfig := startLiveReconfigurer(globalConfig.LiveReconfiguration)
+	defer liveReconfig.stop()
+	clusterHealth := startClusterHealthMonitor(globalConfig.ClusterHealthMonitor, kubeClientProvider)
+	defer clusterHealth.stop()
+	clusterSnapshot := takeClusterSnapshot(globalConfig.ClusterSnapshot, kubeClientProvider)
+	if clusterSnapshot != nil && metricsScraper.SummaryMetadata == nil {
+		metricsScraper.SummaryMetadata = make(map[string]any)
+	}
 	go func() {
 		var innerRC int
+		var executedJobs []prometheus.Job
+		var executedExecutors []JobExecutor
+		var gcCtx context.Context
+		var cancelGC context.CancelFunc
+		errs := []error{}
+		returnMap := make(map[string]returnPair)
+		// publishProgress replaces the previous snapshot, this goroutine is the only sender
+		publishProgress := func() {
+			select {
+			case <-progress:
+			default:
+			}
+			progress <- runProgress{executedJobs: slices.Clone(executedJobs), executedExecutors: slices.Clone(executedExecutors), errs: slices.Clone(errs)}
+		}
 		clientSet, _ := kubeClientProvider.DefaultClientSet()
 		measurementsFactory := measurements.NewMeasurementsFactory(configSpec, metricsScraper.MetricsMetadata, additionalMeasurementFactoryMap)
-		jobExecutors = newExecutorList(configSpec, kubeClientProvider, embedCfg)
+		comparison := newComparison(globalConfig, kubeClientProvider)
+		handleSyntheticImages(jobExecutors)
 		handlePreloadImages(jobExecutors, kubeClientProvider)
 		// Iterate job list
 		var measurementsInstance *measurements.Measurements
 		var measurementsJobName string
 		for jobExecutorIdx, jobExecutor := range jobExecutors {
+			publishProgress()
+			comparison.start(jobExecutorIdx, jobExecutor.Name)
+			if reason := jobExecutor.skipReason(jobStatuses, metricsScraper.PrometheusClients); reason != "" {
+				log.Warnf("Skipping job %s: %s", jobExecutor.Name, reason)
+				jobStatuses[jobExecutor.Name] = jobSkipped
+				continue
+			}
+			if nodeWatchdog.wait(ctx) != nil {
+				return
+			}
+			// Any error raised while running the job fails it
+			jobErrs := len(errs)
+			waitForLatency, gateErr := jobExecutor.waitForGate(ctx, metricsScraper.PrometheusClients)
+			if gateErr != nil {
+				if jobExecutor.WaitFor.FailurePolicy == config.HookIgnore {
+					log.Warnf("Job %s: %v, starting it anyway", jobExecutor.Name, gateErr)
+				} else {
+					err := fmt.Errorf("job %s: %v", jobExecutor.Name, gateErr)
+					log.Error(err.Error())
+					errs = append(errs, err)
+					innerRC = 1
+					jobStatuses[jobExecutor.Name] = jobFailed
+					continue
+				}
+			}
+			if err := jobExecutor.runHook(ctx, "preHook", jobExecutor.PreHook); err != nil {
+				log.Error(err.Error())
+				errs = append(errs, err)
+				innerRC = 1
+				jobStatuses[jobExecutor.Name] = jobFailed
+				continue
+			}
+			targetNodes, targetErr := jobExecutor.resolveTargetNodes(ctx)
+			if targetErr != nil {
+				err := fmt.Errorf("job %s: %v", jobExecutor.Name, targetErr)
+				log.Error(err.Error())
+				errs = append(errs, err)
+				innerRC = 1
+				jobStatuses[jobExecutor.Name] = jobFailed
+				continue
+			}
+			restartLatency, schedulerErr := jobExecutor.prepareSchedulerCache(ctx)
+			if schedulerErr != nil {
+				err := fmt.Errorf("job %s: %v", jobExecutor.Name, schedulerErr)
+				log.Error(err.Error())
+				errs = append(errs, err)
+				innerRC = 1
+				jobStatuses[jobExecutor.Name] = jobFailed
+				continue
+			}
+			executedExecutors = append(executedExecutors, jobExecutor)
 			executedJobs = append(executedJobs, prometheus.Job{
-				Start:     time.Now().UTC(),
-				JobConfig: jobExecutor.Job,
+				Start:            time.Now().UTC(),
+				JobConfig:        jobExecutor.Job,
+				DiscoveryLatency: jobExecutor.discoveryLatency,
+				WarmUpLatency:    jobExecutor.warmUpLatency,
+				WaitForLatency:   waitForLatency,
+				TargetNodeCount:  targetNodes,
 			})
+			publishProgress()
 			watcherManager := watchers.NewWatcherManager(clientSet, rate.NewLimiter(rate.Limit(jobExecutor.QPS), jobExecutor.Burst))
 			for idx, watcher := range jobExecutor.Watchers {
 				for replica := range watcher.Replicas {
@@ -111,14 +257,26 @@ func Run(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider,
 				}
 			}
 			watcherStartErrors := watcherManager.Wait()
-			slices.Concat(errs, watcherStartErrors)
+			errs = slices.Concat(errs, watcherStartErrors)
 			var waitListNamespaces []string
 			if measurementsInstance == nil {
 				measurementsJobName = jobExecutor.Name
 				measurementsInstance = measurementsFactory.NewMeasurements(&jobExecutor.Job, kubeClientProvider, embedCfg)
-				measurementsInstance.Start()
+				if !jobExecutor.SkipIndexing && len(metricsScraper.IndexerList) > 0 {
+					measurementsInstance.StreamTo(metricsScraper.IndexerList)
+				}
+				if err := measurementsInstance.Start().Err(); err != nil {
+					log.Error(err.Error())
+				}
 			}
 			log.Infof("Triggering job: %s", jobExecutor.Name)
+			jobExecutor.phases = phases
+			jobExecutor.nodeWatchdog = nodeWatchdog
+			phases.record(jobExecutor.Name, PhaseJobStarted, 0, fmt.Sprintf("Job %s started", jobExecutor.Name))
+			liveReconfig.setJob(&jobExecutor)
+			jobCtx := jobExecutor.newCircuitBreaker(ctx)
+			qpsRamp := jobExecutor.startQPSRamp()
+			jobExecutor.qpsController.start(jobExecutor.Name, metricsScraper.PrometheusClients)
 			if jobExecutor.JobType == config.CreationJob {
 				if jobExecutor.Cleanup {
 					// No timeout for initial job cleanup
@@ -131,13 +289,15 @@ func Run(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider,
 					log.Infof("Churn percent: %v", jobExecutor.ChurnPercent)
 					log.Infof("Churn delay: %v", jobExecutor.ChurnDelay)
 					log.Infof("Churn deletion strategy: %v", jobExecutor.ChurnDeletionStrategy)
+					log.Infof("Churn pod deletion: %v", jobExecutor.ChurnPodDeletion)
 				}
-				jobExecutor.RunCreateJob(ctx, 0, jobExecutor.JobIterations, &waitListNamespaces)
+				jobExecutor.RunCreateJob(jobCtx, jobExecutor.iterationStart, jobExecutor.iterationEnd, &waitListNamespaces)
 				if ctx.Err() != nil {
+					qpsRamp.stop()
 					return
 				}
-				// If object verification is enabled
-				if jobExecutor.VerifyObjects && !jobExecutor.Verify() {
+				// If object verification is enabled, verification is pointless when the job was halted
+				if jobExecutor.errorBreach() == nil && jobExecutor.VerifyObjects && !jobExecutor.Verify() {
 					err := errors.New("object verification failed")
 					// If errorOnVerify is enabled. Set RC to 1 and append error
 					if jobExecutor.ErrorOnVerify {
@@ -146,20 +306,49 @@ func Run(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider,
 					}
 					log.Error(err.Error())
 				}
-				if jobExecutor.Churn {
+				if jobExecutor.errorBreach() == nil && jobExecutor.Churn {
 					churnStart := time.Now().UTC()
 					executedJobs[len(executedJobs)-1].ChurnStart = &churnStart
-					jobExecutor.RunCreateJobWithChurn(ctx)
+					jobExecutor.RunCreateJobWithChurn(jobCtx)
 					churnEnd := time.Now().UTC()
 					executedJobs[len(executedJobs)-1].ChurnEnd = &churnEnd
 				}
 				globalWaitMap[strconv.Itoa(jobExecutorIdx)+jobExecutor.Name] = waitListNamespaces
 				executorMap[strconv.Itoa(jobExecutorIdx)+jobExecutor.Name] = jobExecutor
 			} else {
-				jobExecutor.Run(ctx)
+				jobExecutor.Run(jobCtx)
 				if ctx.Err() != nil {
+					qpsRamp.stop()
 					return
 				}
+				if err := jobExecutor.custom.error(); err != nil {
+					errs = append(errs, err)
+					innerRC = 1
+				}
+			}
+			executedJobs[len(executedJobs)-1].QPSTimeseries = qpsRamp.stop()
+			executedJobs[len(executedJobs)-1].ArrivalStats = jobExecutor.arrivals.summary()
+			executedJobs[len(executedJobs)-1].IterationPacing = jobExecutor.iterationPacer.summary()
+			executedJobs[len(executedJobs)-1].ThrottlingEvents = jobExecutor.qpsController.stop()
+			executedJobs[len(executedJobs)-1].Reconfigurations = liveReconfig.unsetJob()
+			executedJobs[len(executedJobs)-1].PDBBlockedEvictions = jobExecutor.evictions.summary()
+			executedJobs[len(executedJobs)-1].HelmReleases = jobExecutor.helm.summary()
+			executedJobs[len(executedJobs)-1].FioResults = jobExecutor.fio.summary()
+			executedJobs[len(executedJobs)-1].NetworkResults = jobExecutor.network.summary()
+			executedJobs[len(executedJobs)-1].InflateSamples = jobExecutor.inflate.summary()
+			executedJobs[len(executedJobs)-1].IdentityRequests = jobExecutor.identities.summary()
+			executedJobs[len(executedJobs)-1].WasmHookStats = jobExecutor.wasmHook.summary()
+			jobExecutor.wasmHook.close()
+			jobExecutor.stopCircuitBreaker()
+			jobExecutor.waiterCache.stop()
+			if breach := jobExecutor.errorBreach(); breach != nil {
+				errs = append(errs, breach)
+				jobBreaches[jobExecutor.Name] = breach
+				innerRC = 1
+				if jobExecutor.ErrorBreachPolicy == config.ErrorBreachCleanup {
+					log.Infof("Garbage collecting job %s after breaching its error limits", jobExecutor.Name)
+					jobExecutor.gc(ctx, nil)
+				}
 			}
 			if jobExecutor.BeforeCleanup != "" {
 				log.Infof("Waiting for beforeCleanup command %s to finish", jobExecutor.BeforeCleanup)
@@ -172,10 +361,24 @@ func Run(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider,
 				}
 				log.Infof("BeforeCleanup out: %v, err: %v", stdOut.String(), stdErr.String())
 			}
+			if err := jobExecutor.runHook(ctx, "postHook", jobExecutor.PostHook); err != nil {
+				log.Error(err.Error())
+				errs = append(errs, err)
+				innerRC = 1
+			}
+			executedJobs[len(executedJobs)-1].Artifacts, executedJobs[len(executedJobs)-1].ArtifactFiles = jobExecutor.fetchArtifacts(ctx, globalConfig.ArtifactsDirectory)
+			var jobQuantiles map[string]float64
+			executedJobs[len(executedJobs)-1].MeshOverhead = jobExecutor.meshOverhead(ctx, executedJobs[len(executedJobs)-1].Start, meshBaselines, metricsScraper.PrometheusClients)
+			executedJobs[len(executedJobs)-1].SchedulerCache = jobExecutor.schedulerCache(ctx, restartLatency, schedulerColds)
+			if globalConfig.Cost != nil {
+				executedJobs[len(executedJobs)-1].Namespaces = jobExecutor.jobNamespaces(ctx)
+			}
 			jobEnd := time.Now().UTC()
 			if jobExecutor.MetricsClosing == config.AfterJob {
 				executedJobs[len(executedJobs)-1].End = jobEnd
 				executedJobs[len(executedJobs)-1].ObjectOperations = jobExecutor.objectOperations
+				executedJobs[len(executedJobs)-1].WaiterListRequests = jobExecutor.waiterStats.listRequests.Load()
+				executedJobs[len(executedJobs)-1].WaiterWatchEvents = jobExecutor.waiterStats.watchEvents.Load()
 			}
 			if jobExecutor.JobPause > 0 {
 				log.Infof("Pausing for %v before finishing job", jobExecutor.JobPause)
@@ -184,6 +387,8 @@ func Run(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider,
 			if jobExecutor.MetricsClosing == config.AfterJobPause {
 				executedJobs[len(executedJobs)-1].End = time.Now().UTC()
 				executedJobs[len(executedJobs)-1].ObjectOperations = jobExecutor.objectOperations
+				executedJobs[len(executedJobs)-1].WaiterListRequests = jobExecutor.waiterStats.listRequests.Load()
+				executedJobs[len(executedJobs)-1].WaiterWatchEvents = jobExecutor.waiterStats.watchEvents.Load()
 			}
 			if !globalConfig.WaitWhenFinished {
 				elapsedTime := jobEnd.Sub(executedJobs[len(executedJobs)-1].Start).Round(time.Second)
@@ -191,7 +396,7 @@ func Run(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider,
 			}
 			if !jobExecutor.MetricsAggregate {
 				// We stop and index measurements per job
-				if err = measurementsInstance.Stop(); err != nil {
+				if err = measurementsInstance.Stop().Err(); err != nil {
 					errs = append(errs, err)
 					log.Error(err.Error())
 					innerRC = rcMeasurement
@@ -199,30 +404,58 @@ func Run(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider,
 				if jobExecutor.MetricsClosing == config.AfterMeasurements {
 					executedJobs[len(executedJobs)-1].End = time.Now().UTC()
 					executedJobs[len(executedJobs)-1].ObjectOperations = jobExecutor.objectOperations
+					executedJobs[len(executedJobs)-1].WaiterListRequests = jobExecutor.waiterStats.listRequests.Load()
+					executedJobs[len(executedJobs)-1].WaiterWatchEvents = jobExecutor.waiterStats.watchEvents.Load()
 				}
 				if !jobExecutor.SkipIndexing && len(metricsScraper.IndexerList) > 0 {
 					msWg.Add(1)
 					go func(msi *measurements.Measurements, jobName string) {
 						defer msWg.Done()
-						msi.Index(jobName, metricsScraper.IndexerList)
+						if err := msi.Index(jobName, metricsScraper.IndexerList).Err(); err != nil {
+							log.Error(err.Error())
+						}
 					}(measurementsInstance, measurementsJobName)
 				}
+				jobQuantiles = measurementsInstance.LatencyQuantiles()
+				executedJobs[len(executedJobs)-1].SLOWarnings = measurementsInstance.SLOWarnings()
 				measurementsInstance = nil
 			}
+			executedJobs[len(executedJobs)-1].Comparison = comparison.exchange(jobExecutorIdx, executedJobs[len(executedJobs)-1], len(errs) == jobErrs, jobQuantiles)
 			watcherStopErrs := watcherManager.StopAll()
-			slices.Concat(errs, watcherStopErrs)
-			if jobExecutor.GC {
+			errs = slices.Concat(errs, watcherStopErrs)
+			jobStatuses[jobExecutor.Name] = jobSucceeded
+			if len(errs) > jobErrs {
+				jobStatuses[jobExecutor.Name] = jobFailed
+			}
+			jobOutcomes.Store(jobExecutor.Name, jobStatuses[jobExecutor.Name])
+			if jobExecutor.GC && jobExecutor.gcScoped(jobStatuses[jobExecutor.Name], true) {
+				phases.record(jobExecutor.Name, phaseGarbageCollectionStarted, 0, fmt.Sprintf("Garbage collection of job %s started", jobExecutor.Name))
 				jobExecutor.gc(ctx, nil)
+				phases.record(jobExecutor.Name, phaseGarbageCollectionFinished, 0, fmt.Sprintf("Garbage collection of job %s finished", jobExecutor.Name))
+			} else if jobExecutor.GC {
+				log.Infof("Keeping the objects of job %s, garbage collection policy %s", jobExecutor.Name, jobExecutor.GCPolicy)
+			}
+			if jobStatuses[jobExecutor.Name] == jobFailed {
+				phases.record(jobExecutor.Name, PhaseJobFinished, 0, fmt.Sprintf("Job %s failed", jobExecutor.Name))
+			} else {
+				phases.record(jobExecutor.Name, PhaseJobFinished, 0, fmt.Sprintf("Job %s succeeded", jobExecutor.Name))
 			}
 		}
+		publishProgress()
+		comparison.finish(len(jobExecutors))
 		if globalConfig.WaitWhenFinished {
 			runWaitList(globalWaitMap, executorMap)
 		}
 		// We initialize garbage collection as soon as the benchmark finishes
 		if globalConfig.GC {
-			//nolint:govet
-			gcCtx, _ = context.WithTimeout(context.Background(), globalConfig.GCTimeout)
+			phases.record("", phaseGarbageCollectionStarted, 0, "Garbage collection started")
+			gcCtx, cancelGC = context.WithTimeout(context.Background(), globalConfig.GCTimeout)
 			for _, jobExecutor := range jobExecutors {
+				status, hasStatus := jobStatuses[jobExecutor.Name]
+				if !jobExecutor.gcScoped(status, hasStatus) {
+					log.Infof("Keeping the objects of job %s, garbage collection policy %s", jobExecutor.Name, jobExecutor.GCPolicy)
+					continue
+				}
 				gcWg.Add(1)
 				go jobExecutor.gc(gcCtx, &gcWg)
 			}
@@ -231,6 +464,7 @@ func Run(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider,
 				log.Info("Garbage collection metrics on, waiting for GC")
 				// If gcMetrics is enabled, garbage collection must be blocker
 				gcWg.Wait()
+				phases.record("", phaseGarbageCollectionFinished, 0, "Garbage collection finished")
 				// We add an extra dummy job to executedJobs to index metrics from this stage
 				executedJobs = append(executedJobs, prometheus.Job{
 					Start: cleanupStart,
@@ -245,57 +479,125 @@ func Run(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider,
 		msWg.Wait()
 		for _, job := range executedJobs {
 			// Declare slice on each iteration
-			var jobAlerts []error
+			var jobErrors []error
 			var executionErrors string
+			if breach, ok := jobBreaches[job.JobConfig.Name]; ok {
+				jobErrors = append(jobErrors, breach)
+			}
 			for _, alertM := range metricsScraper.AlertMs {
+				// The coordinator of the worker processes evaluates the alerts of the whole benchmark
+				if globalConfig.Workers > 1 {
+					break
+				}
 				if err := alertM.Evaluate(job); err != nil {
 					errs = append(errs, err)
-					jobAlerts = append(jobAlerts, err)
+					jobErrors = append(jobErrors, err)
 					innerRC = rcAlert
 				}
 			}
-			if len(jobAlerts) > 0 {
-				executionErrors = utilerrors.NewAggregate(jobAlerts).Error()
+			if len(jobErrors) > 0 {
+				executionErrors = utilerrors.NewAggregate(jobErrors).Error()
 			}
 			returnMap[job.JobConfig.Name] = returnPair{innerRC: innerRC, executionErrors: executionErrors}
 		}
-		indexMetrics(uuid, executedJobs, returnMap, metricsScraper, configSpec, true, "", false)
+		clientMonitor.attribute(executedJobs)
+		nodeWatchdog.attribute(executedJobs)
+		alertmanager.attribute(executedJobs)
+		clusterHealth.attribute(executedJobs)
+		clusterSnapshot.finish(metricsScraper.SummaryMetadata)
+		summaries := indexMetrics(uuid, executedJobs, returnMap, metricsScraper, configSpec, true, "", false)
 		log.Infof("Finished execution with UUID: %s", uuid)
-		res <- innerRC
+		res <- runResult{rc: innerRC, jobSummaries: summaries, executedJobs: executedJobs, errs: errs, gcCtx: gcCtx, cancelGC: cancelGC}
 	}()
+	var haltErr error
 	select {
-	case rc = <-res:
+	case result := <-res:
+		rc, jobSummaries, executedJobs, errs = result.rc, result.jobSummaries, result.executedJobs, result.errs
+		gcCtx, cancelGC = result.gcCtx, result.cancelGC
 	// When benchmark times out
 	case <-time.After(configSpec.GlobalConfig.Timeout):
-		err := fmt.Errorf("%v timeout reached", configSpec.GlobalConfig.Timeout)
-		log.Error(err.Error())
-		executedJobs[len(executedJobs)-1].End = time.Now().UTC()
-		errs = append(errs, err)
+		haltErr = fmt.Errorf("%v timeout reached", configSpec.GlobalConfig.Timeout)
+		log.Error(haltErr.Error())
 		rc = rcTimeout
-		if globalConfig.GC {
+	// When the caller halts the benchmark
+	case <-parentCtx.Done():
+		haltErr = fmt.Errorf("benchmark interrupted: %v", parentCtx.Err())
+		log.Error(haltErr.Error())
+		rc = 1
+	// When the node watchdog aborts the benchmark
+	case <-nodeWatchdog.aborted():
+		haltErr = nodeWatchdog.abortError()
+		rc = rcNodeWatchdog
+	// When a critical alert fires during the benchmark
+	case <-alertMonitor.aborted():
+		haltErr = alertMonitor.abortError()
+		rc = rcAlert
+	}
+	if haltErr != nil {
+		var snapshot runProgress
+		select {
+		case snapshot = <-progress:
+		default:
+		}
+		executedJobs, errs = snapshot.executedJobs, snapshot.errs
+		executedExecutors := snapshot.executedExecutors
+		if len(executedJobs) > 0 {
+			executedJobs[len(executedJobs)-1].End = time.Now().UTC()
+		}
+		errs = append(errs, haltErr)
+		if globalConfig.GC && len(executedExecutors) > 0 {
+			phases.record("", phaseGarbageCollectionStarted, 0, "Garbage collection started")
 			gcCtx, cancelGC = context.WithTimeout(context.Background(), globalConfig.GCTimeout)
 			defer cancelGC()
-			for _, jobExecutor := range jobExecutors[:len(executedJobs)-1] {
+			for _, jobExecutor := range executedExecutors[:len(executedExecutors)-1] {
+				outcome, hasStatus := jobOutcomes.Load(jobExecutor.Name)
+				if status, _ := outcome.(jobStatus); !jobExecutor.gcScoped(status, hasStatus) {
+					log.Infof("Keeping the objects of job %s, garbage collection policy %s", jobExecutor.Name, jobExecutor.GCPolicy)
+					continue
+				}
 				gcWg.Add(1)
 				go jobExecutor.gc(gcCtx, &gcWg)
 			}
 			timeoutGCStarted = true
 		}
-		indexMetrics(uuid, executedJobs, returnMap, metricsScraper, configSpec, false, utilerrors.NewAggregate(errs).Error(), true)
+		clientMonitor.attribute(executedJobs)
+		nodeWatchdog.attribute(executedJobs)
+		alertmanager.attribute(executedJobs)
+		clusterHealth.attribute(executedJobs)
+		clusterSnapshot.finish(metricsScraper.SummaryMetadata)
+		jobSummaries = indexMetrics(uuid, executedJobs, nil, metricsScraper, configSpec, false, utilerrors.NewAggregate(errs).Error(), true)
 	}
-	if globalConfig.GC {
+	if err := clusterHealth.verdict(); err != nil {
+		errs = append(errs, err)
+		if rc == 0 {
+			rc = rcClusterHealth
+		}
+	}
+	// The garbage collection context is only set when garbage collection started, a benchmark halted before running any
+	// job doesn't garbage collect
+	if globalConfig.GC && gcCtx != nil {
 		// When GC is enabled and GCMetrics is disabled, we assume previous GC operation ran in background, so we have to ensure there's no garbage left
 		// Also wait if timeout GC was started, regardless of GCMetrics setting
 		if !globalConfig.GCMetrics || timeoutGCStarted {
 			log.Info("Garbage collecting jobs")
 			gcWg.Wait()
+			phases.record("", phaseGarbageCollectionFinished, 0, "Garbage collection finished")
 		}
 		// When GC times out and job execution has finished successfully, return timeout
 		if gcCtx.Err() == context.DeadlineExceeded && rc == 0 {
 			errs = append(errs, fmt.Errorf("garbage collection timeout reached"))
 			rc = rcTimeout
 		}
+		cancelGC()
+	}
+	for _, recordingRules := range metricsScraper.RecordingRules {
+		if err := recordingRules.Remove(); err != nil {
+			log.Error(err.Error())
+		}
 	}
+	runResults := newRunResults(configSpec, start, rc, errs, jobSummaries, metricsScraper.SummaryMetadata)
+	runExitHooks(globalConfig.ExitHooks, runResults)
+	publishGitHubReport(globalConfig, runResults, executedJobs)
 	return rc, utilerrors.NewAggregate(errs)
 }
 
@@ -311,46 +613,103 @@ func handlePreloadImages(executorList []JobExecutor, kubeClientProvider *config.
 	}
 }
 
-// indexMetrics indexes metrics for the executed jobs
-func indexMetrics(uuid string, executedJobs []prometheus.Job, returnMap map[string]returnPair, metricsScraper metrics.Scraper, configSpec config.Spec, innerRC bool, executionErrors string, isTimeout bool) {
-	var jobSummaries []JobSummary
+// indexMetrics indexes metrics for the executed jobs, returns the summaries of all of them
+func indexMetrics(uuid string, executedJobs []prometheus.Job, returnMap map[string]returnPair, metricsScraper metrics.Scraper, configSpec config.Spec, innerRC bool, executionErrors string, isTimeout bool) []JobSummary {
+	var jobSummaries, indexedSummaries []JobSummary
+	attributeCosts(configSpec.GlobalConfig.Cost, executedJobs)
 	for _, job := range executedJobs {
+		if value, exists := returnMap[job.JobConfig.Name]; exists && !isTimeout {
+			innerRC = value.innerRC == 0
+			executionErrors = value.executionErrors
+		}
+		var jobErr error
+		if !innerRC {
+			jobErr = errors.New(cmp.Or(executionErrors, "job failed"))
+		}
+		junit.AddTestCase(junit.SuiteJobs, job.JobConfig.Name, job.End.Sub(job.Start), jobErr)
+		var achievedQps float64
+		elapsedTime := job.End.Sub(job.Start).Round(time.Second).Seconds()
+		if elapsedTime > 0 {
+			achievedQps = math.Round((float64(job.ObjectOperations)/elapsedTime)*1000) / 1000
+		}
+		jobSummary := JobSummary{
+			UUID:                uuid,
+			Timestamp:           job.Start,
+			EndTimestamp:        job.End,
+			ElapsedTime:         elapsedTime,
+			AchievedQps:         achievedQps,
+			ChurnStartTimestamp: job.ChurnStart,
+			ChurnEndTimestamp:   job.ChurnEnd,
+			JobConfig:           job.JobConfig,
+			Metadata:            metricsScraper.SummaryMetadata,
+			Passed:              innerRC,
+			ExecutionErrors:     util.RedactSecrets(executionErrors),
+			Version:             fmt.Sprintf("%v@%v", version.Version, version.GitCommit),
+			MetricName:          jobSummaryMetric,
+			Disruptions:         configSpec.GlobalConfig.Disruptions(job.Start, job.End),
+			DiscoveryLatency:    job.DiscoveryLatency.Milliseconds(),
+			WarmUpLatency:       job.WarmUpLatency.Milliseconds(),
+			WaitForLatency:      job.WaitForLatency.Milliseconds(),
+			WaiterListRequests:  job.WaiterListRequests,
+			WaiterWatchEvents:   job.WaiterWatchEvents,
+			QPSTimeseries:       job.QPSTimeseries,
+			ArrivalStats:        job.ArrivalStats,
+			IterationPacing:     job.IterationPacing,
+			Cost:                job.Cost,
+			ClientUsage:         job.ClientUsage,
+			ClientTransport:     configSpec.GlobalConfig.ClientTransport,
+			TargetNodeCount:     job.TargetNodeCount,
+			IdentityRequests:    job.IdentityRequests,
+			SLOWarnings:         job.SLOWarnings,
+			WasmHookStats:       job.WasmHookStats,
+			ArtifactFiles:       job.ArtifactFiles,
+		}
+		jobSummaries = append(jobSummaries, jobSummary)
 		if !job.JobConfig.SkipIndexing {
-			if value, exists := returnMap[job.JobConfig.Name]; exists && !isTimeout {
-				innerRC = value.innerRC == 0
-				executionErrors = value.executionErrors
-			}
-			var achievedQps float64
-			elapsedTime := job.End.Sub(job.Start).Round(time.Second).Seconds()
-			if elapsedTime > 0 {
-				achievedQps = math.Round((float64(job.ObjectOperations)/elapsedTime)*1000) / 1000
-			}
-			jobSummaries = append(jobSummaries, JobSummary{
-				UUID:                uuid,
-				Timestamp:           job.Start,
-				EndTimestamp:        job.End,
-				ElapsedTime:         elapsedTime,
-				AchievedQps:         achievedQps,
-				ChurnStartTimestamp: job.ChurnStart,
-				ChurnEndTimestamp:   job.ChurnEnd,
-				JobConfig:           job.JobConfig,
-				Metadata:            metricsScraper.SummaryMetadata,
-				Passed:              innerRC,
-				ExecutionErrors:     executionErrors,
-				Version:             fmt.Sprintf("%v@%v", version.Version, version.GitCommit),
-				MetricName:          jobSummaryMetric,
-			})
+			indexedSummaries = append(indexedSummaries, jobSummary)
 		}
 	}
 	for _, indexer := range metricsScraper.IndexerList {
-		IndexJobSummary(jobSummaries, indexer)
+		IndexJobSummary(indexedSummaries, indexer)
+		indexThrottlingEvents(uuid, executedJobs, metricsScraper.MetricsMetadata, indexer)
+		indexReconfigurations(uuid, executedJobs, metricsScraper.MetricsMetadata, indexer)
+		indexPDBBlockedEvictions(uuid, executedJobs, metricsScraper.MetricsMetadata, indexer)
+		indexHelmReleases(uuid, executedJobs, metricsScraper.MetricsMetadata, indexer)
+		indexFioResults(uuid, executedJobs, metricsScraper.MetricsMetadata, indexer)
+		indexNetworkResults(uuid, executedJobs, metricsScraper.MetricsMetadata, indexer)
+		indexInflateSamples(uuid, executedJobs, metricsScraper.MetricsMetadata, indexer)
+		indexClientSamples(uuid, executedJobs, metricsScraper.MetricsMetadata, indexer)
+		indexComparisons(uuid, executedJobs, metricsScraper.MetricsMetadata, indexer)
+		indexMeshOverhead(uuid, executedJobs, metricsScraper.MetricsMetadata, indexer)
+		indexSchedulerCache(uuid, executedJobs, metricsScraper.MetricsMetadata, indexer)
+		indexNodeIncidents(uuid, executedJobs, metricsScraper.MetricsMetadata, indexer)
+		indexAlertmanagerAlerts(uuid, executedJobs, metricsScraper.MetricsMetadata, indexer)
+		indexArtifacts(uuid, executedJobs, metricsScraper.MetricsMetadata, indexer)
+		indexClusterHealth(uuid, executedJobs, metricsScraper.MetricsMetadata, indexer)
+	}
+	// The coordinator of the worker processes scrapes the metrics of the whole benchmark
+	if configSpec.GlobalConfig.Workers > 1 {
+		return jobSummaries
 	}
 	for _, prometheusClient := range metricsScraper.PrometheusClients {
 		prometheusClient.ScrapeJobsMetrics(executedJobs...)
 	}
+	finishIndexers(configSpec)
+	return jobSummaries
+}
+
+// finishIndexers packs the documents written by the local and OpenMetrics indexers once everything is indexed
+func finishIndexers(configSpec config.Spec) {
 	for _, indexer := range configSpec.MetricsEndpoints {
-		if indexer.Type == indexers.LocalIndexer && indexer.CreateTarball {
-			metrics.CreateTarball(indexer.IndexerConfig)
+		for _, indexerConfig := range append([]config.IndexerConfig{indexer.IndexerConfig}, indexer.Indexers...) {
+			if indexerConfig.Type == indexers.LocalIndexer && indexerConfig.CreateTarball {
+				metrics.CreateTarball(indexerConfig.IndexerConfig)
+			}
+			if indexerConfig.Type == metrics.OpenMetricsIndexer && indexerConfig.TSDBDirectory != "" {
+				if err := metrics.CreateTSDBBlocks(indexerConfig); err != nil {
+					log.Error(err.Error())
+				}
+			}
 		}
 	}
 }
@@ -384,13 +743,30 @@ func verifyJobDefaults(job *config.Job, defaultTimeout time.Duration) {
 }
 
 // newExecutorList Returns a list of executors
-func newExecutorList(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider, embedCfg *fileutils.EmbedConfiguration) []JobExecutor {
+func newExecutorList(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider, embedCfg *fileutils.EmbedConfiguration) ([]JobExecutor, error) {
 	var executorList []JobExecutor
+	// Discovery is resolved once and shared by all the jobs, before any job timer starts
+	setupClientSet, setupRestConfig := kubeClientProvider.ClientSet(100, 100) // Hardcoded QPS/Burst
+	start := time.Now()
+	mapper := newRESTMapper(discovery.NewDiscoveryClientForConfigOrDie(setupRestConfig))
+	discoveryLatency := time.Since(start)
+	log.Infof("API discovery completed in %v", discoveryLatency.Truncate(time.Millisecond))
+	budget, err := newResourceBudget(configSpec.GlobalConfig.Budget, setupClientSet)
+	if err != nil {
+		return nil, err
+	}
 	for _, job := range configSpec.Jobs {
 		verifyJobDefaults(&job, configSpec.GlobalConfig.Timeout)
-		executorList = append(executorList, newExecutor(configSpec, kubeClientProvider, job, embedCfg))
+		executor, err := newExecutor(configSpec, kubeClientProvider, job, embedCfg, mapper)
+		if err != nil {
+			return nil, err
+		}
+		executor.discoveryLatency = discoveryLatency
+		executor.budget = budget
+		executor.warmUp()
+		executorList = append(executorList, executor)
 	}
-	return executorList
+	return executorList, nil
 }
 
 // Runs on wait list at the end of benchmark
@@ -411,11 +787,16 @@ func runWaitList(globalWaitMap map[string][]string, executorMap map[string]JobEx
 			}(ns)
 		}
 		wg.Wait()
+		executor.waiterCache.stop()
 	}
 }
 
 func (ex *JobExecutor) gc(ctx context.Context, wg *sync.WaitGroup) {
 	labelSelector := fmt.Sprintf("kube-burner-job=%s", ex.Name)
+	// Workers only garbage collect the objects they created
+	if ex.workerMode {
+		labelSelector = fmt.Sprintf("kube-burner-job=%s,kube-burner-runid=%s", ex.Name, ex.runid)
+	}
 	if wg != nil {
 		defer wg.Done()
 	}
@@ -432,4 +813,5 @@ func (ex *JobExecutor) gc(ctx context.Context, wg *sync.WaitGroup) {
 			CleanupNamespaceResourcesUsingGVR(ctx, *ex, obj, obj.namespace, labelSelector)
 		}
 	}
+	ex.budget.releaseJob(ex.Name)
 }
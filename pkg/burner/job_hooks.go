@@ -0,0 +1,207 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/util"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	hookFieldManager = "kube-burner"
+	// Namespace of the hook objects not specifying any
+	hookNamespace = "default"
+)
+
+// runHook runs a hook of the job, the error returned is nil when the hook succeeds or its failure policy ignores it
+func (ex *JobExecutor) runHook(ctx context.Context, name string, hook *config.JobHook) error {
+	if hook == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, hook.Timeout)
+	defer cancel()
+	var err error
+	switch {
+	case len(hook.Command) > 0:
+		log.Infof("Job %s: running %s command %s", ex.Name, name, strings.Join(hook.Command, " "))
+		err = ex.execHookCommand(ctx, hook.Command)
+	case hook.Manifest != "":
+		log.Infof("Job %s: applying %s manifest %s", ex.Name, name, hook.Manifest)
+		err = ex.applyHookManifest(ctx, hook.Manifest)
+	default:
+		log.Infof("Job %s: running %s Kubernetes Job %s", ex.Name, name, hook.Job)
+		err = ex.runHookJob(ctx, hook.Job)
+	}
+	if err == nil {
+		return nil
+	}
+	err = fmt.Errorf("job %s %s failed: %v", ex.Name, name, err)
+	if hook.FailurePolicy == config.HookIgnore {
+		log.Warnf("%v, ignoring it", err)
+		return nil
+	}
+	return err
+}
+
+// execHookCommand runs the command with the job identity in its environment
+func (ex *JobExecutor) execHookCommand(ctx context.Context, command []string) error {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Env = append(os.Environ(),
+		"KUBE_BURNER_UUID="+ex.uuid,
+		"KUBE_BURNER_RUNID="+ex.runid,
+		"KUBE_BURNER_JOB="+ex.Name,
+	)
+	out, err := cmd.CombinedOutput()
+	if len(out) > 0 {
+		log.Infof("%s output:\n%s", command[0], strings.TrimSpace(string(out)))
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %v", command[0], err)
+	}
+	return nil
+}
+
+// renderHookTemplate renders a hook template with the job variables
+func (ex *JobExecutor) renderHookTemplate(location string) ([]byte, error) {
+	f, err := fileutils.GetWorkloadReader(location, ex.embedCfg)
+	if err != nil {
+		return nil, err
+	}
+	t, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	templateData := map[string]any{
+		jobName:  ex.Name,
+		jobUUID:  ex.uuid,
+		jobRunId: ex.runid,
+	}
+	return util.RenderTemplate(t, templateData, util.MissingKeyError, ex.functionTemplates)
+}
+
+// applyHookManifest server-side applies the objects of the manifest, which isn't labeled and hence
+// isn't garbage collected by kube-burner
+func (ex *JobExecutor) applyHookManifest(ctx context.Context, manifest string) error {
+	rendered, err := ex.renderHookTemplate(manifest)
+	if err != nil {
+		return err
+	}
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(rendered), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("error decoding %s: %v", manifest, err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		mapping, err := ex.mapper.RESTMapping(obj.GroupVersionKind().GroupKind(), obj.GroupVersionKind().Version)
+		if err != nil {
+			return err
+		}
+		var ri dynamic.ResourceInterface = ex.dynamicClient.Resource(mapping.Resource)
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			if obj.GetNamespace() == "" {
+				obj.SetNamespace(hookNamespace)
+			}
+			ri = ex.dynamicClient.Resource(mapping.Resource).Namespace(obj.GetNamespace())
+		}
+		if ex.limiter.Wait(ctx) != nil {
+			return ctx.Err()
+		}
+		if _, err := ri.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: hookFieldManager, Force: true}); err != nil {
+			return fmt.Errorf("error applying %s/%s: %v", obj.GetKind(), obj.GetName(), err)
+		}
+		log.Debugf("Applied %s/%s", obj.GetKind(), obj.GetName())
+	}
+}
+
+// runHookJob creates the Kubernetes Job and waits for it to complete. Completed Jobs are deleted,
+// failed ones are kept for inspection
+func (ex *JobExecutor) runHookJob(ctx context.Context, jobTemplate string) error {
+	rendered, err := ex.renderHookTemplate(jobTemplate)
+	if err != nil {
+		return err
+	}
+	job := &batchv1.Job{}
+	if err := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(rendered), 4096).Decode(job); err != nil {
+		return fmt.Errorf("error decoding %s: %v", jobTemplate, err)
+	}
+	if job.Namespace == "" {
+		job.Namespace = hookNamespace
+	}
+	jobClient := ex.clientSet.BatchV1().Jobs(job.Namespace)
+	job, err = jobClient.Create(ctx, job, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("error creating job: %v", err)
+	}
+	start := time.Now()
+	var failed *batchv1.JobCondition
+	err = wait.PollUntilContextCancel(ctx, time.Second, true, func(ctx context.Context) (bool, error) {
+		current, err := jobClient.Get(ctx, job.Name, metav1.GetOptions{})
+		if err != nil {
+			log.Debugf("Error getting Job %s/%s: %v", job.Namespace, job.Name, err)
+			return false, nil
+		}
+		for _, c := range current.Status.Conditions {
+			if c.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch c.Type {
+			case batchv1.JobComplete:
+				return true, nil
+			case batchv1.JobFailed:
+				failed = &c
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("job %s/%s not completed: %v", job.Namespace, job.Name, err)
+	}
+	if failed != nil {
+		return fmt.Errorf("job %s/%s failed: %s %s", job.Namespace, job.Name, failed.Reason, failed.Message)
+	}
+	log.Infof("Job %s/%s completed in %v", job.Namespace, job.Name, time.Since(start).Round(time.Second))
+	propagationPolicy := metav1.DeletePropagationBackground
+	if err := jobClient.Delete(context.TODO(), job.Name, metav1.DeleteOptions{PropagationPolicy: &propagationPolicy}); err != nil {
+		log.Warnf("Error deleting Job %s/%s: %v", job.Namespace, job.Name, err)
+	}
+	return nil
+}
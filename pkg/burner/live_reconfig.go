@@ -0,0 +1,257 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+// liveReconfigurer serves the endpoint changing the QPS, burst and churn parameters of the running job, so that
+// long benchmarks can be tuned without restarting them
+type liveReconfigurer struct {
+	server *http.Server
+	// token required in the Authorization header of the requests, when set
+	token string
+	mu    sync.Mutex
+	// job running job, nil between jobs
+	job          *JobExecutor
+	churnPercent int
+	churnDelay   time.Duration
+	changes      []prometheus.Reconfiguration
+}
+
+// jobParameters parameters of the running job, the ones set in a request are changed
+type jobParameters struct {
+	Job          string   `json:"job,omitempty"`
+	QPS          *float64 `json:"qps,omitempty"`
+	Burst        *int     `json:"burst,omitempty"`
+	ChurnPercent *int     `json:"churnPercent,omitempty"`
+	ChurnDelay   *string  `json:"churnDelay,omitempty"`
+}
+
+// clientLimiter client-side rate limiter of the job whose QPS and burst can be changed
+type clientLimiter struct {
+	*rate.Limiter
+}
+
+func (c clientLimiter) TryAccept() bool {
+	return c.Allow()
+}
+
+func (c clientLimiter) Accept() {
+	c.Wait(context.Background())
+}
+
+func (c clientLimiter) Stop() {}
+
+func (c clientLimiter) QPS() float32 {
+	return float32(c.Limit())
+}
+
+// startLiveReconfigurer starts the live reconfiguration endpoint, returns nil when not configured
+func startLiveReconfigurer(liveReconfiguration *config.LiveReconfiguration) *liveReconfigurer {
+	if liveReconfiguration == nil {
+		return nil
+	}
+	listener, err := net.Listen("tcp", liveReconfiguration.Address)
+	if err != nil {
+		log.Errorf("Error starting the live reconfiguration endpoint: %v", err)
+		return nil
+	}
+	lr := &liveReconfigurer{token: liveReconfiguration.Token}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/job", lr.handleJob)
+	lr.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go lr.server.Serve(listener)
+	log.Infof("Live reconfiguration endpoint listening on http://%s/job", listener.Addr())
+	return lr
+}
+
+// stop stops the endpoint
+func (lr *liveReconfigurer) stop() {
+	if lr == nil {
+		return
+	}
+	lr.server.Close()
+}
+
+// setJob makes the job the target of the changes until unsetJob is called
+func (lr *liveReconfigurer) setJob(ex *JobExecutor) {
+	if lr == nil {
+		return
+	}
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	lr.job = ex
+	lr.churnPercent, lr.churnDelay = ex.ChurnPercent, ex.ChurnDelay
+	lr.changes = nil
+	ex.liveReconfig = lr
+}
+
+// unsetJob returns the changes applied to the job that was running
+func (lr *liveReconfigurer) unsetJob() []prometheus.Reconfiguration {
+	if lr == nil {
+		return nil
+	}
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	lr.job = nil
+	return lr.changes
+}
+
+// requestLimiters returns the rate limiters of the object requests of the job: its own, the one of its clients and the
+// one shared by the clients of its identities
+func (ex *JobExecutor) requestLimiters() []*rate.Limiter {
+	limiters := []*rate.Limiter{ex.limiter}
+	if ex.clientLimiter.Limiter != nil {
+		limiters = append(limiters, ex.clientLimiter.Limiter)
+	}
+	if ex.identities != nil {
+		if identityLimiter, ok := ex.identities.rateLimiter.(clientLimiter); ok && !slices.Contains(limiters, identityLimiter.Limiter) {
+			limiters = append(limiters, identityLimiter.Limiter)
+		}
+	}
+	return limiters
+}
+
+// churnParameters returns the churn percent and delay of the running job, read at the start of each churn cycle
+func (ex *JobExecutor) churnParameters() (int, time.Duration) {
+	if ex.liveReconfig == nil {
+		return ex.ChurnPercent, ex.ChurnDelay
+	}
+	ex.liveReconfig.mu.Lock()
+	defer ex.liveReconfig.mu.Unlock()
+	return ex.liveReconfig.churnPercent, ex.liveReconfig.churnDelay
+}
+
+// handleJob returns the parameters of the running job, and changes them on PATCH or POST requests
+func (lr *liveReconfigurer) handleJob(w http.ResponseWriter, r *http.Request) {
+	if lr.token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+lr.token)) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	if lr.job == nil {
+		http.Error(w, "no job running", http.StatusConflict)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPatch, http.MethodPost:
+		var params jobParameters
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&params); err != nil {
+			http.Error(w, fmt.Sprintf("invalid parameters: %v", err), http.StatusBadRequest)
+			return
+		}
+		if status, err := lr.apply(params); err != nil {
+			http.Error(w, err.Error(), status)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lr.parameters())
+}
+
+// parameters returns the current parameters of the running job
+func (lr *liveReconfigurer) parameters() jobParameters {
+	ex := lr.job
+	qps, burst := float64(ex.limiter.Limit()), ex.limiter.Burst()
+	params := jobParameters{Job: ex.Name, QPS: &qps, Burst: &burst}
+	if ex.Churn {
+		churnDelay := lr.churnDelay.String()
+		params.ChurnPercent, params.ChurnDelay = &lr.churnPercent, &churnDelay
+	}
+	return params
+}
+
+// apply validates the requested parameters and applies them all, or none. Returns the status code of the error
+func (lr *liveReconfigurer) apply(params jobParameters) (int, error) {
+	ex := lr.job
+	var churnDelay time.Duration
+	if params.QPS != nil {
+		if *params.QPS <= 0 {
+			return http.StatusBadRequest, fmt.Errorf("qps must be greater than 0")
+		}
+		if ex.QPSRamp != nil || ex.AdaptiveQPS != nil || ex.ArrivalRate != nil {
+			return http.StatusConflict, fmt.Errorf("the QPS of job %s is controlled by its qpsRamp, adaptiveQPS or arrivalRate", ex.Name)
+		}
+	}
+	if params.Burst != nil && *params.Burst <= 0 {
+		return http.StatusBadRequest, fmt.Errorf("burst must be greater than 0")
+	}
+	if (params.ChurnPercent != nil || params.ChurnDelay != nil) && !ex.Churn {
+		return http.StatusConflict, fmt.Errorf("job %s doesn't churn", ex.Name)
+	}
+	if params.ChurnPercent != nil && (*params.ChurnPercent <= 0 || *params.ChurnPercent > 100) {
+		return http.StatusBadRequest, fmt.Errorf("churnPercent must be between 1 and 100")
+	}
+	if params.ChurnDelay != nil {
+		var err error
+		if churnDelay, err = time.ParseDuration(*params.ChurnDelay); err != nil || churnDelay < 0 {
+			return http.StatusBadRequest, fmt.Errorf("invalid churnDelay %s", *params.ChurnDelay)
+		}
+	}
+	now := time.Now().UTC()
+	var changes []string
+	record := func(parameter string, previous, value float64) {
+		lr.changes = append(lr.changes, prometheus.Reconfiguration{Timestamp: now, Parameter: parameter, PreviousValue: previous, Value: value})
+		changes = append(changes, fmt.Sprintf("%s %v → %v", parameter, previous, value))
+	}
+	if params.QPS != nil {
+		record("qps", float64(ex.limiter.Limit()), *params.QPS)
+		for _, limiter := range ex.requestLimiters() {
+			limiter.SetLimit(rate.Limit(*params.QPS))
+		}
+	}
+	if params.Burst != nil {
+		record("burst", float64(ex.limiter.Burst()), float64(*params.Burst))
+		for _, limiter := range ex.requestLimiters() {
+			limiter.SetBurst(*params.Burst)
+		}
+	}
+	if params.ChurnPercent != nil {
+		record("churnPercent", float64(lr.churnPercent), float64(*params.ChurnPercent))
+		lr.churnPercent = *params.ChurnPercent
+	}
+	if params.ChurnDelay != nil {
+		record("churnDelay", lr.churnDelay.Seconds(), churnDelay.Seconds())
+		lr.churnDelay = churnDelay
+	}
+	if len(changes) > 0 {
+		log.Infof("Job %s reconfigured: %s", ex.Name, strings.Join(changes, ", "))
+	}
+	return 0, nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/metrics"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	"github.com/kube-burner/kube-burner/pkg/util"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Sidecar container injected by each service mesh
+var meshSidecars = map[config.MeshProvider]string{
+	config.MeshIstio:   "istio-proxy",
+	config.MeshLinkerd: "linkerd-proxy",
+}
+
+// meshOverhead collects the measurements of the mesh overhead phase run by the job. The baseline phase stats are kept
+// in baselines until the mesh phase of the same job finishes, which returns the comparison of both
+func (ex *JobExecutor) meshOverhead(ctx context.Context, start time.Time, baselines map[string]prometheus.MeshPhaseStats, prometheusClients []*prometheus.Prometheus) *prometheus.MeshOverhead {
+	if ex.MeshOverhead == nil {
+		return nil
+	}
+	stats := ex.meshPhaseStats(ctx, start, prometheusClients)
+	if ex.MeshOverhead.Phase == config.MeshBaseline {
+		if stats.SidecarPods > 0 {
+			log.Warnf("Job %s: %d pods got a sidecar with injection disabled", ex.Name, stats.SidecarPods)
+		}
+		baselines[ex.MeshOverhead.Job] = stats
+		return nil
+	}
+	if stats.SidecarPods < stats.Pods {
+		log.Warnf("Job %s: only %d/%d pods got a sidecar, is %s installed?", ex.Name, stats.SidecarPods, stats.Pods, ex.MeshOverhead.Provider)
+	}
+	baseline, ok := baselines[ex.MeshOverhead.Job]
+	if !ok {
+		log.Warnf("Job %s: the baseline phase didn't run, mesh overhead can't be computed", ex.Name)
+		return nil
+	}
+	overhead := &prometheus.MeshOverhead{
+		Provider:            string(ex.MeshOverhead.Provider),
+		Baseline:            baseline,
+		Mesh:                stats,
+		PodReadyP50Delta:    stats.PodReadyP50 - baseline.PodReadyP50,
+		PodReadyP99Delta:    stats.PodReadyP99 - baseline.PodReadyP99,
+		PodReadyAvgDelta:    stats.PodReadyAvg - baseline.PodReadyAvg,
+		MemoryDelta:         stats.Memory - baseline.Memory,
+		RequestLatencyDelta: stats.RequestLatency - baseline.RequestLatency,
+	}
+	if stats.Pods > 0 && baseline.Pods > 0 {
+		overhead.MemoryPerPodDelta = stats.Memory/float64(stats.Pods) - baseline.Memory/float64(baseline.Pods)
+	}
+	for name, value := range stats.ControlPlane {
+		if baselineValue, ok := baseline.ControlPlane[name]; ok {
+			if overhead.ControlPlaneDelta == nil {
+				overhead.ControlPlaneDelta = make(map[string]float64)
+			}
+			overhead.ControlPlaneDelta[name] = value - baselineValue
+		}
+	}
+	log.Infof("Job %s: %s overhead: pod ready P50 %+dms P99 %+dms avg %+dms, memory %+.0f bytes (%+.0f bytes per pod)",
+		ex.MeshOverhead.Job, ex.MeshOverhead.Provider, overhead.PodReadyP50Delta, overhead.PodReadyP99Delta, overhead.PodReadyAvgDelta, overhead.MemoryDelta, overhead.MemoryPerPodDelta)
+	if ex.MeshOverhead.RequestLatencyQuery != "" {
+		log.Infof("Job %s: request latency %v → %v (%+v)", ex.MeshOverhead.Job, baseline.RequestLatency, stats.RequestLatency, overhead.RequestLatencyDelta)
+	}
+	for _, name := range slices.Sorted(maps.Keys(overhead.ControlPlaneDelta)) {
+		log.Infof("Job %s: control plane %s %v → %v (%+v)", ex.MeshOverhead.Job, name, baseline.ControlPlane[name], stats.ControlPlane[name], overhead.ControlPlaneDelta[name])
+	}
+	return overhead
+}
+
+// meshPhaseStats measures the pods created by the job, and queries their memory footprint, their request latency
+// and the control plane metrics since the start of the job
+func (ex *JobExecutor) meshPhaseStats(ctx context.Context, start time.Time, prometheusClients []*prometheus.Prometheus) prometheus.MeshPhaseStats {
+	var stats prometheus.MeshPhaseStats
+	labelSelector := fmt.Sprintf("kube-burner-runid=%s,kube-burner-job=%s", ex.runid, ex.Name)
+	pods, err := ex.clientSet.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		log.Errorf("Job %s: error listing pods: %v", ex.Name, err)
+		return stats
+	}
+	namespaces := make(map[string]struct{})
+	var latencies []float64
+	for _, pod := range pods.Items {
+		stats.Pods++
+		namespaces[pod.Namespace] = struct{}{}
+		if hasContainer(pod, meshSidecars[ex.MeshOverhead.Provider]) {
+			stats.SidecarPods++
+		}
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+				latencies = append(latencies, float64(c.LastTransitionTime.Sub(pod.CreationTimestamp.Time).Milliseconds()))
+			}
+		}
+	}
+	if len(latencies) > 0 {
+		summary := metrics.NewLatencySummary(latencies, "Ready")
+		stats.PodReadyP50, stats.PodReadyP99, stats.PodReadyAvg = int64(summary.P50), int64(summary.P99), int64(summary.Avg)
+	}
+	if len(prometheusClients) == 0 {
+		log.Warnf("Job %s: no Prometheus endpoint configured, memory, request latency and control plane metrics are not collected", ex.Name)
+		return stats
+	}
+	var patterns []string
+	for _, ns := range slices.Sorted(maps.Keys(namespaces)) {
+		patterns = append(patterns, regexp.QuoteMeta(ns))
+	}
+	templateData := map[string]any{
+		"Namespaces": strings.Join(patterns, "|"),
+		"Duration":   fmt.Sprintf("%ds", max(int(time.Since(start).Seconds()), 1)),
+	}
+	query := func(name, expr string) float64 {
+		rendered, err := util.RenderTemplate([]byte(expr), templateData, util.MissingKeyError, ex.functionTemplates)
+		if err == nil {
+			var value float64
+			if value, err = queryValue(prometheusClients[0], string(rendered)); err == nil {
+				return value
+			}
+		}
+		log.Warnf("Job %s: error querying %s: %v", ex.Name, name, err)
+		return 0
+	}
+	if len(namespaces) > 0 {
+		stats.Memory = query("memory", ex.MeshOverhead.MemoryQuery)
+	}
+	if ex.MeshOverhead.RequestLatencyQuery != "" {
+		stats.RequestLatency = query("request latency", ex.MeshOverhead.RequestLatencyQuery)
+	}
+	for name, expr := range ex.MeshOverhead.ControlPlaneQueries {
+		if stats.ControlPlane == nil {
+			stats.ControlPlane = make(map[string]float64)
+		}
+		stats.ControlPlane[name] = query(name, expr)
+	}
+	return stats
+}
+
+// hasContainer returns true when the pod runs the container, native sidecars are init containers
+func hasContainer(pod corev1.Pod, name string) bool {
+	isNamed := func(c corev1.Container) bool { return c.Name == name }
+	return slices.ContainsFunc(pod.Spec.Containers, isNamed) || slices.ContainsFunc(pod.Spec.InitContainers, isNamed)
+}
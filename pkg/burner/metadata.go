@@ -22,26 +22,197 @@ import (
 
 	"github.com/cloud-bulldozer/go-commons/v2/indexers"
 	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
 	log "github.com/sirupsen/logrus"
 )
 
 type JobSummary struct {
-	Timestamp           time.Time      `json:"timestamp"`
-	EndTimestamp        time.Time      `json:"endTimestamp"`
-	ChurnStartTimestamp *time.Time     `json:"churnStartTimestamp,omitempty"`
-	ChurnEndTimestamp   *time.Time     `json:"churnEndTimestamp,omitempty"`
-	ElapsedTime         float64        `json:"elapsedTime"`
-	AchievedQps         float64        `json:"achievedQps,omitempty"`
-	UUID                string         `json:"uuid"`
-	MetricName          string         `json:"metricName"`
-	JobConfig           config.Job     `json:"jobConfig"`
-	Version             string         `json:"version,omitempty"`
-	Passed              bool           `json:"passed"`
-	ExecutionErrors     string         `json:"executionErrors,omitempty"`
-	Metadata            map[string]any `json:"-"`
-}
-
-const jobSummaryMetric = "jobSummary"
+	Timestamp           time.Time                   `json:"timestamp"`
+	EndTimestamp        time.Time                   `json:"endTimestamp"`
+	ChurnStartTimestamp *time.Time                  `json:"churnStartTimestamp,omitempty"`
+	ChurnEndTimestamp   *time.Time                  `json:"churnEndTimestamp,omitempty"`
+	ElapsedTime         float64                     `json:"elapsedTime"`
+	AchievedQps         float64                     `json:"achievedQps,omitempty"`
+	UUID                string                      `json:"uuid"`
+	MetricName          string                      `json:"metricName"`
+	JobConfig           config.Job                  `json:"jobConfig"`
+	Version             string                      `json:"version,omitempty"`
+	Passed              bool                        `json:"passed"`
+	ExecutionErrors     string                      `json:"executionErrors,omitempty"`
+	Disruptions         []string                    `json:"disruptions,omitempty"`
+	DiscoveryLatency    int64                       `json:"discoveryLatency,omitempty"`
+	WarmUpLatency       int64                       `json:"warmUpLatency,omitempty"`
+	WaitForLatency      int64                       `json:"waitForLatency,omitempty"`
+	WaiterListRequests  int64                       `json:"waiterListRequests,omitempty"`
+	WaiterWatchEvents   int64                       `json:"waiterWatchEvents,omitempty"`
+	QPSTimeseries       []prometheus.QPSSample      `json:"qpsTimeseries,omitempty"`
+	ArrivalStats        *prometheus.ArrivalStats    `json:"arrivalStats,omitempty"`
+	IterationPacing     *prometheus.IterationPacing `json:"iterationPacing,omitempty"`
+	Cost                *prometheus.JobCost         `json:"cost,omitempty"`
+	ClientUsage         *prometheus.ClientUsage     `json:"clientUsage,omitempty"`
+	ClientTransport     config.ClientTransport      `json:"clientTransport"`
+	TargetNodeCount     int                         `json:"targetNodeCount,omitempty"`
+	IdentityRequests    map[string]int64            `json:"identityRequests,omitempty"`
+	SLOWarnings         []string                    `json:"sloWarnings,omitempty"`
+	WasmHookStats       *prometheus.WasmHookStats   `json:"wasmHookStats,omitempty"`
+	ArtifactFiles       int                         `json:"artifactFiles,omitempty"`
+	Metadata            map[string]any              `json:"-"`
+}
+
+const (
+	jobSummaryMetric         = "jobSummary"
+	throttlingEventMetric    = "throttlingEvent"
+	reconfigurationMetric    = "jobReconfiguration"
+	pdbBlockedEvictionMetric = "pdbBlockedEviction"
+	helmReleaseMetric        = "helmRelease"
+	fioResultMetric          = "fioResult"
+	networkResultMetric      = "networkResult"
+	inflateSampleMetric      = "inflateSample"
+	clientMetricsMetric      = "clientMetrics"
+	jobComparisonMetric      = "jobComparison"
+	meshOverheadMetric       = "meshOverhead"
+	schedulerCacheMetric     = "schedulerCache"
+	nodeIncidentMetric       = "nodeIncident"
+	alertmanagerAlertMetric  = "alertmanagerAlert"
+	clusterHealthMetric      = "clusterHealth"
+)
+
+// throttlingEventDocument indexed document of an adaptive QPS decrease
+type throttlingEventDocument struct {
+	prometheus.ThrottlingEvent
+	UUID       string         `json:"uuid"`
+	JobName    string         `json:"jobName"`
+	MetricName string         `json:"metricName"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// reconfigurationDocument indexed document of a live change of a parameter of a job
+type reconfigurationDocument struct {
+	prometheus.Reconfiguration
+	UUID       string         `json:"uuid"`
+	JobName    string         `json:"jobName"`
+	MetricName string         `json:"metricName"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// pdbBlockedEvictionDocument indexed document of a churn pod eviction blocked by a PodDisruptionBudget
+type pdbBlockedEvictionDocument struct {
+	prometheus.PDBBlockedEviction
+	UUID       string         `json:"uuid"`
+	JobName    string         `json:"jobName"`
+	MetricName string         `json:"metricName"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// helmReleaseDocument indexed document of a release operation of a helm job
+type helmReleaseDocument struct {
+	prometheus.HelmRelease
+	UUID       string         `json:"uuid"`
+	JobName    string         `json:"jobName"`
+	MetricName string         `json:"metricName"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// fioResultDocument indexed document of a result of a fio job
+type fioResultDocument struct {
+	prometheus.FioResult
+	UUID       string         `json:"uuid"`
+	JobName    string         `json:"jobName"`
+	MetricName string         `json:"metricName"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// inflateSampleDocument indexed document of an iteration of an inflate job
+type inflateSampleDocument struct {
+	prometheus.InflateSample
+	UUID       string         `json:"uuid"`
+	JobName    string         `json:"jobName"`
+	MetricName string         `json:"metricName"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// networkResultDocument indexed document of a result of a network job
+type networkResultDocument struct {
+	prometheus.NetworkResult
+	UUID       string         `json:"uuid"`
+	JobName    string         `json:"jobName"`
+	MetricName string         `json:"metricName"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// nodeIncidentDocument indexed document of an unhealthy node condition observed during a job
+type nodeIncidentDocument struct {
+	prometheus.NodeIncident
+	UUID       string         `json:"uuid"`
+	JobName    string         `json:"jobName"`
+	MetricName string         `json:"metricName"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// alertmanagerAlertDocument indexed document of an Alertmanager alert firing during a job
+type alertmanagerAlertDocument struct {
+	prometheus.AlertmanagerAlert
+	UUID       string         `json:"uuid"`
+	JobName    string         `json:"jobName"`
+	MetricName string         `json:"metricName"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// artifactDocument indexed document parsed from an artifact of a job
+type artifactDocument struct {
+	prometheus.ArtifactDocument
+	UUID     string         `json:"uuid"`
+	JobName  string         `json:"jobName"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// clusterHealthDocument indexed document of a health check of the cluster taken during a job
+type clusterHealthDocument struct {
+	prometheus.ClusterHealthCheck
+	UUID       string         `json:"uuid"`
+	JobName    string         `json:"jobName"`
+	MetricName string         `json:"metricName"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// clientSampleDocument indexed document of a sample of the resource usage of kube-burner
+type clientSampleDocument struct {
+	prometheus.ClientSample
+	UUID       string         `json:"uuid"`
+	JobName    string         `json:"jobName"`
+	MetricName string         `json:"metricName"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// comparisonDocument indexed document of the comparison of a job run on the two clusters of an A/B benchmark
+type comparisonDocument struct {
+	prometheus.Comparison
+	Timestamp  time.Time      `json:"timestamp"`
+	UUID       string         `json:"uuid"`
+	JobName    string         `json:"jobName"`
+	MetricName string         `json:"metricName"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// meshOverheadDocument indexed document of the overhead measured by a mesh overhead job
+type meshOverheadDocument struct {
+	prometheus.MeshOverhead
+	Timestamp  time.Time      `json:"timestamp"`
+	UUID       string         `json:"uuid"`
+	JobName    string         `json:"jobName"`
+	MetricName string         `json:"metricName"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
+
+// schedulerCacheDocument indexed document of the cold cache penalty measured by a scheduler cache job
+type schedulerCacheDocument struct {
+	prometheus.SchedulerCache
+	Timestamp  time.Time      `json:"timestamp"`
+	UUID       string         `json:"uuid"`
+	JobName    string         `json:"jobName"`
+	MetricName string         `json:"metricName"`
+	Metadata   map[string]any `json:"metadata,omitempty"`
+}
 
 // IndexJobSummary indexes jobSummaries Generates and indexes a document with metadata information of the passed job
 func IndexJobSummary(jobSummaries []JobSummary, indexer indexers.Indexer) {
@@ -65,3 +236,433 @@ func IndexJobSummary(jobSummaries []JobSummary, indexer indexers.Indexer) {
 		log.Info(resp)
 	}
 }
+
+// indexThrottlingEvents indexes the QPS decreases of the jobs with adaptive QPS
+func indexThrottlingEvents(uuid string, jobs []prometheus.Job, metadata map[string]any, indexer indexers.Indexer) {
+	var documents []any
+	for _, job := range jobs {
+		if job.JobConfig.SkipIndexing {
+			continue
+		}
+		for _, event := range job.ThrottlingEvents {
+			documents = append(documents, throttlingEventDocument{
+				ThrottlingEvent: event,
+				UUID:            uuid,
+				JobName:         job.JobConfig.Name,
+				MetricName:      throttlingEventMetric,
+				Metadata:        metadata,
+			})
+		}
+	}
+	if len(documents) == 0 {
+		return
+	}
+	log.Info("Indexing throttling events")
+	resp, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: throttlingEventMetric})
+	if err != nil {
+		log.Error(err)
+	} else {
+		log.Info(resp)
+	}
+}
+
+// indexReconfigurations indexes the live changes of the parameters of the jobs
+func indexReconfigurations(uuid string, jobs []prometheus.Job, metadata map[string]any, indexer indexers.Indexer) {
+	var documents []any
+	for _, job := range jobs {
+		if job.JobConfig.SkipIndexing {
+			continue
+		}
+		for _, event := range job.Reconfigurations {
+			documents = append(documents, reconfigurationDocument{
+				Reconfiguration: event,
+				UUID:            uuid,
+				JobName:         job.JobConfig.Name,
+				MetricName:      reconfigurationMetric,
+				Metadata:        metadata,
+			})
+		}
+	}
+	if len(documents) == 0 {
+		return
+	}
+	log.Info("Indexing job reconfigurations")
+	resp, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: reconfigurationMetric})
+	if err != nil {
+		log.Error(err)
+	} else {
+		log.Info(resp)
+	}
+}
+
+// indexPDBBlockedEvictions indexes the churn pod evictions blocked by PodDisruptionBudgets
+func indexPDBBlockedEvictions(uuid string, jobs []prometheus.Job, metadata map[string]any, indexer indexers.Indexer) {
+	var documents []any
+	for _, job := range jobs {
+		if job.JobConfig.SkipIndexing {
+			continue
+		}
+		for _, eviction := range job.PDBBlockedEvictions {
+			documents = append(documents, pdbBlockedEvictionDocument{
+				PDBBlockedEviction: eviction,
+				UUID:               uuid,
+				JobName:            job.JobConfig.Name,
+				MetricName:         pdbBlockedEvictionMetric,
+				Metadata:           metadata,
+			})
+		}
+	}
+	if len(documents) == 0 {
+		return
+	}
+	log.Info("Indexing PDB blocked evictions")
+	resp, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: pdbBlockedEvictionMetric})
+	if err != nil {
+		log.Error(err)
+	} else {
+		log.Info(resp)
+	}
+}
+
+// indexHelmReleases indexes the release operations of the helm jobs
+func indexHelmReleases(uuid string, jobs []prometheus.Job, metadata map[string]any, indexer indexers.Indexer) {
+	var documents []any
+	for _, job := range jobs {
+		if job.JobConfig.SkipIndexing {
+			continue
+		}
+		for _, release := range job.HelmReleases {
+			documents = append(documents, helmReleaseDocument{
+				HelmRelease: release,
+				UUID:        uuid,
+				JobName:     job.JobConfig.Name,
+				MetricName:  helmReleaseMetric,
+				Metadata:    metadata,
+			})
+		}
+	}
+	if len(documents) == 0 {
+		return
+	}
+	log.Info("Indexing helm releases")
+	resp, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: helmReleaseMetric})
+	if err != nil {
+		log.Error(err)
+	} else {
+		log.Info(resp)
+	}
+}
+
+// indexFioResults indexes the results of the fio jobs
+func indexFioResults(uuid string, jobs []prometheus.Job, metadata map[string]any, indexer indexers.Indexer) {
+	var documents []any
+	for _, job := range jobs {
+		if job.JobConfig.SkipIndexing {
+			continue
+		}
+		for _, result := range job.FioResults {
+			documents = append(documents, fioResultDocument{
+				FioResult:  result,
+				UUID:       uuid,
+				JobName:    job.JobConfig.Name,
+				MetricName: fioResultMetric,
+				Metadata:   metadata,
+			})
+		}
+	}
+	if len(documents) == 0 {
+		return
+	}
+	log.Info("Indexing fio results")
+	resp, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: fioResultMetric})
+	if err != nil {
+		log.Error(err)
+	} else {
+		log.Info(resp)
+	}
+}
+
+// indexNetworkResults indexes the results of the network jobs
+func indexNetworkResults(uuid string, jobs []prometheus.Job, metadata map[string]any, indexer indexers.Indexer) {
+	var documents []any
+	for _, job := range jobs {
+		if job.JobConfig.SkipIndexing {
+			continue
+		}
+		for _, result := range job.NetworkResults {
+			documents = append(documents, networkResultDocument{
+				NetworkResult: result,
+				UUID:          uuid,
+				JobName:       job.JobConfig.Name,
+				MetricName:    networkResultMetric,
+				Metadata:      metadata,
+			})
+		}
+	}
+	if len(documents) == 0 {
+		return
+	}
+	log.Info("Indexing network results")
+	resp, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: networkResultMetric})
+	if err != nil {
+		log.Error(err)
+	} else {
+		log.Info(resp)
+	}
+}
+
+// indexInflateSamples indexes the samples of the inflate jobs
+func indexInflateSamples(uuid string, jobs []prometheus.Job, metadata map[string]any, indexer indexers.Indexer) {
+	var documents []any
+	for _, job := range jobs {
+		if job.JobConfig.SkipIndexing {
+			continue
+		}
+		for _, sample := range job.InflateSamples {
+			documents = append(documents, inflateSampleDocument{
+				InflateSample: sample,
+				UUID:          uuid,
+				JobName:       job.JobConfig.Name,
+				MetricName:    inflateSampleMetric,
+				Metadata:      metadata,
+			})
+		}
+	}
+	if len(documents) == 0 {
+		return
+	}
+	log.Info("Indexing inflate samples")
+	resp, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: inflateSampleMetric})
+	if err != nil {
+		log.Error(err)
+	} else {
+		log.Info(resp)
+	}
+}
+
+// indexClientSamples indexes the resource usage of kube-burner sampled during the jobs
+func indexClientSamples(uuid string, jobs []prometheus.Job, metadata map[string]any, indexer indexers.Indexer) {
+	var documents []any
+	for _, job := range jobs {
+		if job.JobConfig.SkipIndexing {
+			continue
+		}
+		for _, sample := range job.ClientSamples {
+			documents = append(documents, clientSampleDocument{
+				ClientSample: sample,
+				UUID:         uuid,
+				JobName:      job.JobConfig.Name,
+				MetricName:   clientMetricsMetric,
+				Metadata:     metadata,
+			})
+		}
+	}
+	if len(documents) == 0 {
+		return
+	}
+	log.Info("Indexing client metrics")
+	resp, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: clientMetricsMetric})
+	if err != nil {
+		log.Error(err)
+	} else {
+		log.Info(resp)
+	}
+}
+
+// indexComparisons indexes the comparisons of the jobs of an A/B benchmark
+func indexComparisons(uuid string, jobs []prometheus.Job, metadata map[string]any, indexer indexers.Indexer) {
+	var documents []any
+	for _, job := range jobs {
+		if job.JobConfig.SkipIndexing || job.Comparison == nil {
+			continue
+		}
+		documents = append(documents, comparisonDocument{
+			Comparison: *job.Comparison,
+			Timestamp:  job.Comparison.A.Start,
+			UUID:       uuid,
+			JobName:    job.JobConfig.Name,
+			MetricName: jobComparisonMetric,
+			Metadata:   metadata,
+		})
+	}
+	if len(documents) == 0 {
+		return
+	}
+	log.Info("Indexing job comparisons")
+	resp, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: jobComparisonMetric})
+	if err != nil {
+		log.Error(err)
+	} else {
+		log.Info(resp)
+	}
+}
+
+// indexMeshOverhead indexes the overhead measured by the mesh overhead jobs, named after the job before being split in phases
+func indexMeshOverhead(uuid string, jobs []prometheus.Job, metadata map[string]any, indexer indexers.Indexer) {
+	var documents []any
+	for _, job := range jobs {
+		if job.JobConfig.SkipIndexing || job.MeshOverhead == nil {
+			continue
+		}
+		documents = append(documents, meshOverheadDocument{
+			MeshOverhead: *job.MeshOverhead,
+			Timestamp:    job.End,
+			UUID:         uuid,
+			JobName:      job.JobConfig.MeshOverhead.Job,
+			MetricName:   meshOverheadMetric,
+			Metadata:     metadata,
+		})
+	}
+	if len(documents) == 0 {
+		return
+	}
+	log.Info("Indexing mesh overhead")
+	resp, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: meshOverheadMetric})
+	if err != nil {
+		log.Error(err)
+	} else {
+		log.Info(resp)
+	}
+}
+
+// indexSchedulerCache indexes the cold cache penalty measured by the scheduler cache jobs, named after the job before being split in phases
+func indexSchedulerCache(uuid string, jobs []prometheus.Job, metadata map[string]any, indexer indexers.Indexer) {
+	var documents []any
+	for _, job := range jobs {
+		if job.JobConfig.SkipIndexing || job.SchedulerCache == nil {
+			continue
+		}
+		documents = append(documents, schedulerCacheDocument{
+			SchedulerCache: *job.SchedulerCache,
+			Timestamp:      job.End,
+			UUID:           uuid,
+			JobName:        job.JobConfig.SchedulerCache.Job,
+			MetricName:     schedulerCacheMetric,
+			Metadata:       metadata,
+		})
+	}
+	if len(documents) == 0 {
+		return
+	}
+	log.Info("Indexing scheduler cache penalty")
+	resp, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: schedulerCacheMetric})
+	if err != nil {
+		log.Error(err)
+	} else {
+		log.Info(resp)
+	}
+}
+
+// indexNodeIncidents indexes the unhealthy node conditions observed by the node watchdog during each job
+func indexNodeIncidents(uuid string, jobs []prometheus.Job, metadata map[string]any, indexer indexers.Indexer) {
+	var documents []any
+	for _, job := range jobs {
+		if job.JobConfig.SkipIndexing {
+			continue
+		}
+		for _, incident := range job.NodeIncidents {
+			documents = append(documents, nodeIncidentDocument{
+				NodeIncident: incident,
+				UUID:         uuid,
+				JobName:      job.JobConfig.Name,
+				MetricName:   nodeIncidentMetric,
+				Metadata:     metadata,
+			})
+		}
+	}
+	if len(documents) == 0 {
+		return
+	}
+	log.Info("Indexing node incidents")
+	resp, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: nodeIncidentMetric})
+	if err != nil {
+		log.Error(err)
+	} else {
+		log.Info(resp)
+	}
+}
+
+// indexClusterHealth indexes the timeline of the cluster health checks taken during each job
+func indexClusterHealth(uuid string, jobs []prometheus.Job, metadata map[string]any, indexer indexers.Indexer) {
+	var documents []any
+	for _, job := range jobs {
+		if job.JobConfig.SkipIndexing {
+			continue
+		}
+		for _, check := range job.ClusterHealthChecks {
+			documents = append(documents, clusterHealthDocument{
+				ClusterHealthCheck: check,
+				UUID:               uuid,
+				JobName:            job.JobConfig.Name,
+				MetricName:         clusterHealthMetric,
+				Metadata:           metadata,
+			})
+		}
+	}
+	if len(documents) == 0 {
+		return
+	}
+	log.Info("Indexing cluster health checks")
+	resp, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: clusterHealthMetric})
+	if err != nil {
+		log.Error(err)
+	} else {
+		log.Info(resp)
+	}
+}
+
+// indexAlertmanagerAlerts indexes the Alertmanager alerts firing during each job
+func indexAlertmanagerAlerts(uuid string, jobs []prometheus.Job, metadata map[string]any, indexer indexers.Indexer) {
+	var documents []any
+	for _, job := range jobs {
+		if job.JobConfig.SkipIndexing {
+			continue
+		}
+		for _, alert := range job.AlertmanagerAlerts {
+			documents = append(documents, alertmanagerAlertDocument{
+				AlertmanagerAlert: alert,
+				UUID:              uuid,
+				JobName:           job.JobConfig.Name,
+				MetricName:        alertmanagerAlertMetric,
+				Metadata:          metadata,
+			})
+		}
+	}
+	if len(documents) == 0 {
+		return
+	}
+	log.Info("Indexing Alertmanager alerts")
+	resp, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: alertmanagerAlertMetric})
+	if err != nil {
+		log.Error(err)
+	} else {
+		log.Info(resp)
+	}
+}
+
+// indexArtifacts indexes the documents parsed from the artifacts of each job, by metric name
+func indexArtifacts(uuid string, jobs []prometheus.Job, metadata map[string]any, indexer indexers.Indexer) {
+	documents := make(map[string][]any)
+	for _, job := range jobs {
+		if job.JobConfig.SkipIndexing {
+			continue
+		}
+		for _, artifact := range job.Artifacts {
+			documents[artifact.MetricName] = append(documents[artifact.MetricName], artifactDocument{
+				ArtifactDocument: artifact,
+				UUID:             uuid,
+				JobName:          job.JobConfig.Name,
+				Metadata:         metadata,
+			})
+		}
+	}
+	for metricName, docs := range documents {
+		log.Infof("Indexing [%d] artifact documents from metric %s", len(docs), metricName)
+		resp, err := indexer.Index(docs, indexers.IndexingOpts{MetricName: metricName})
+		if err != nil {
+			log.Error(err)
+		} else {
+			log.Info(resp)
+		}
+	}
+}
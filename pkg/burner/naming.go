@@ -0,0 +1,109 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Length of the hash appended to the truncated names, keeping them unique
+const truncationHashLength = 5
+
+// padIndex zero-pads the index following the naming policy of the job
+func (ex *JobExecutor) padIndex(index int) string {
+	if ex.Naming == nil {
+		return fmt.Sprint(index)
+	}
+	return fmt.Sprintf("%0*d", ex.Naming.IndexPadding, index)
+}
+
+// objectName applies the naming policy of the job to the rendered name of an object. The hash suffix
+// is computed from the object identity within the job, so that it's the same across runs
+func (ex *JobExecutor) objectName(name string, objectIndex, iteration, replica int) (string, error) {
+	if ex.Naming == nil || name == "" {
+		return name, nil
+	}
+	name = ex.Naming.Prefix + name
+	if ex.Naming.HashSuffix > 0 {
+		name += "-" + nameHash(fmt.Sprintf("%s/%d/%d/%d", ex.Name, objectIndex, iteration, replica), ex.Naming.HashSuffix)
+	}
+	return ex.enforceLength(name, ex.Naming.MaxLength)
+}
+
+// namespaceName applies the naming policy of the job to a namespace name, which is always truncated
+// to the maximum length of a namespace
+func (ex *JobExecutor) namespaceName(name string) string {
+	if ex.Naming == nil {
+		return name
+	}
+	name = ex.Naming.Prefix + name
+	if len(name) > validation.DNS1123LabelMaxLength {
+		name, _ = truncateName(name, validation.DNS1123LabelMaxLength)
+	}
+	return name
+}
+
+// enforceLength truncates the names exceeding the maximum length when the policy allows it
+func (ex *JobExecutor) enforceLength(name string, maxLength int) (string, error) {
+	if len(name) <= maxLength {
+		return name, nil
+	}
+	if !ex.Naming.Truncate {
+		return "", fmt.Errorf("name %s exceeds %d characters", name, maxLength)
+	}
+	return truncateName(name, maxLength)
+}
+
+// truncateName truncates the name, replacing its tail by a hash of the full name so that
+// names sharing a long common prefix don't collide
+func truncateName(name string, maxLength int) (string, error) {
+	if maxLength <= truncationHashLength+1 {
+		return "", fmt.Errorf("name %s can't be truncated to %d characters", name, maxLength)
+	}
+	head := strings.TrimRight(name[:maxLength-truncationHashLength-1], "-.")
+	return head + "-" + nameHash(name, truncationHashLength), nil
+}
+
+func nameHash(s string, length int) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:length]
+}
+
+// checkNames renders the names of all the objects of the job, so that invalid names and collisions
+// are detected before creating any object
+func (ex *JobExecutor) checkNames(mapper meta.RESTMapper) error {
+	r := renderer{
+		mapper: mapper,
+		ex: JobExecutor{
+			Job:               ex.Job,
+			uuid:              ex.uuid,
+			runid:             ex.runid,
+			functionTemplates: ex.functionTemplates,
+			embedCfg:          ex.embedCfg,
+		},
+	}
+	r.renderJob(io.Discard, 0)
+	if len(r.errs) > 0 {
+		return fmt.Errorf("%d naming errors, first one: %v", len(r.errs), r.errs[0])
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	networkRoleLabel = "kube-burner-network-role"
+	iperfPort        = 5201
+	// Separates the iperf3 and netperf outputs in the client logs
+	netperfMarker = "--- netperf ---"
+	networkPoll   = 2 * time.Second
+)
+
+// Topology key of the scheduling constraint of the clients of each topology
+var networkTopologyKeys = map[config.NetworkTopology]string{
+	config.NetworkSameNode:  corev1.LabelHostname,
+	config.NetworkCrossNode: corev1.LabelHostname,
+	config.NetworkCrossZone: corev1.LabelTopologyZone,
+}
+
+// networkRunner runs the client and server pairs of a network job and records their results
+type networkRunner struct {
+	config.NetworkBenchmark
+	mu      sync.Mutex
+	results []prometheus.NetworkResult
+}
+
+// iperfOutput subset of the iperf3 JSON output
+type iperfOutput struct {
+	Error string `json:"error"`
+	End   struct {
+		SumSent struct {
+			Retransmits int64 `json:"retransmits"`
+		} `json:"sum_sent"`
+		SumReceived struct {
+			BitsPerSecond float64 `json:"bits_per_second"`
+		} `json:"sum_received"`
+		// UDP tests report the received throughput, jitter and losses in sum
+		Sum struct {
+			BitsPerSecond float64 `json:"bits_per_second"`
+			JitterMs      float64 `json:"jitter_ms"`
+			LostPercent   float64 `json:"lost_percent"`
+		} `json:"sum"`
+	} `json:"end"`
+}
+
+func (ex *JobExecutor) setupNetworkJob() {
+	if len(ex.ExecutionMode) == 0 {
+		ex.ExecutionMode = config.ExecutionModeParallel
+	}
+	ex.network = &networkRunner{NetworkBenchmark: *ex.Network}
+	log.Infof("Job %s: %d %s %s pairs using image %s", ex.Name, ex.JobIterations, ex.Network.Topology, ex.Network.Protocol, ex.Network.Image)
+}
+
+// runNetworkJob runs a client and server pair per iteration, in the namespace of the iteration
+func (ex *JobExecutor) runNetworkJob(ctx context.Context) {
+	ex.runIterations(ctx, true, ex.runNetworkPair)
+}
+
+// runNetworkPair runs the client and server pair of an iteration and records its result
+func (ex *JobExecutor) runNetworkPair(ctx context.Context, iteration int, ns string) {
+	result := prometheus.NetworkResult{
+		Timestamp: time.Now().UTC(),
+		Namespace: ns,
+		Iteration: iteration,
+		Topology:  string(ex.Network.Topology),
+		Protocol:  string(ex.Network.Protocol),
+		Streams:   ex.Network.Parallel,
+	}
+	ctx, cancel := context.WithTimeout(ctx, ex.Network.Timeout)
+	defer cancel()
+	if err := ex.networkPair(ctx, iteration, ns, &result); err != nil {
+		log.Errorf("Error running network pair of iteration %d in namespace %s: %v", iteration, ns, err)
+		result.Error = err.Error()
+		ex.recordError()
+	} else {
+		log.Infof("Network %s/%d %s → %s: %.2f Gbps, p99 latency %.0fµs", ns, iteration, result.ClientNode, result.ServerNode, result.Throughput/1e9, result.LatencyP99)
+		atomic.AddInt32(&ex.objectOperations, 1)
+	}
+	ex.network.mu.Lock()
+	ex.network.results = append(ex.network.results, result)
+	ex.network.mu.Unlock()
+}
+
+// networkPair creates the server of an iteration, waits for it to be ready, and then runs the client against it
+func (ex *JobExecutor) networkPair(ctx context.Context, iteration int, ns string, result *prometheus.NetworkResult) error {
+	serverLabels := ex.objectLabels(0, iteration)
+	serverLabels[networkRoleLabel] = "server"
+	server := ex.network.pod(fmt.Sprintf("%s-server-%d", ex.Name, iteration), serverLabels)
+	server.Spec.Containers[0].Command = []string{"sh", "-c", fmt.Sprintf("netserver -D & exec iperf3 -s -p %d", iperfPort)}
+	server.Spec.Containers[0].ReadinessProbe = &corev1.Probe{
+		ProbeHandler:  corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(iperfPort)}},
+		PeriodSeconds: 1,
+	}
+	if _, err := ex.clientSet.CoreV1().Pods(ns).Create(ctx, server, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("error creating server: %v", err)
+	}
+	server, err := ex.waitForNetworkPod(ctx, ns, server.Name, func(pod *corev1.Pod) bool {
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodReady {
+				return c.Status == corev1.ConditionTrue
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return fmt.Errorf("server not ready: %v", err)
+	}
+	result.ServerNode = server.Spec.NodeName
+	clientLabels := ex.objectLabels(0, iteration)
+	clientLabels[networkRoleLabel] = "client"
+	client := ex.network.pod(fmt.Sprintf("%s-client-%d", ex.Name, iteration), clientLabels)
+	client.Spec.RestartPolicy = corev1.RestartPolicyNever
+	client.Spec.Containers[0].Command = []string{"sh", "-c", ex.network.clientScript(server.Status.PodIP)}
+	client.Spec.Affinity = ex.network.clientAffinity(maps.Clone(serverLabels))
+	if _, err := ex.clientSet.CoreV1().Pods(ns).Create(ctx, client, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("error creating client: %v", err)
+	}
+	client, err = ex.waitForNetworkPod(ctx, ns, client.Name, func(pod *corev1.Pod) bool {
+		return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+	})
+	if err != nil {
+		return fmt.Errorf("client didn't complete: %v", err)
+	}
+	result.ClientNode = client.Spec.NodeName
+	logs, err := ex.clientSet.CoreV1().Pods(ns).GetLogs(client.Name, &corev1.PodLogOptions{}).DoRaw(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting client logs: %v", err)
+	}
+	if client.Status.Phase == corev1.PodFailed {
+		return fmt.Errorf("client failed: %s", bytes.TrimSpace(logs))
+	}
+	return parseNetworkOutput(logs, ex.Network.Latency, result)
+}
+
+// waitForNetworkPod waits for the pod to meet the condition, it's bounded by the timeout of the pair
+func (ex *JobExecutor) waitForNetworkPod(ctx context.Context, ns, name string, condition func(*corev1.Pod) bool) (*corev1.Pod, error) {
+	var pod *corev1.Pod
+	err := wait.PollUntilContextCancel(ctx, networkPoll, true, func(ctx context.Context) (bool, error) {
+		var err error
+		if pod, err = ex.clientSet.CoreV1().Pods(ns).Get(ctx, name, metav1.GetOptions{}); err != nil {
+			log.Debugf("Error getting pod %s/%s: %v", ns, name, err)
+			return false, nil
+		}
+		return condition(pod), nil
+	})
+	return pod, err
+}
+
+// pod returns a pod of a pair, its command is set by the caller
+func (n *networkRunner) pod(name string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec: corev1.PodSpec{
+			HostNetwork:  n.HostNetwork,
+			NodeSelector: n.NodeSelector,
+			Containers: []corev1.Container{{
+				Name:  "network",
+				Image: n.Image,
+			}},
+		},
+	}
+}
+
+// clientScript returns the script run by the clients: the iperf3 throughput test followed by the netperf latency test
+func (n *networkRunner) clientScript(serverIP string) string {
+	duration := int(n.Duration.Seconds())
+	iperf := fmt.Sprintf("iperf3 -c %s -p %d -t %d -P %d -J", serverIP, iperfPort, duration, n.Parallel)
+	if n.Protocol == config.NetworkUDP {
+		iperf += " -u -b 0"
+	}
+	if !n.Latency {
+		return iperf
+	}
+	test := "TCP_RR"
+	if n.Protocol == config.NetworkUDP {
+		test = "UDP_RR"
+	}
+	netperf := fmt.Sprintf("netperf -H %s -l %d -t %s -P 0 -- -r %d,%d -o MEAN_LATENCY,P50_LATENCY,P90_LATENCY,P99_LATENCY,TRANSACTION_RATE",
+		serverIP, duration, test, n.MessageSize, n.MessageSize)
+	return fmt.Sprintf("%s && echo '%s' && %s", iperf, netperfMarker, netperf)
+}
+
+// clientAffinity returns the constraint placing the client relative to its server, selected by its labels
+func (n *networkRunner) clientAffinity(serverLabels map[string]string) *corev1.Affinity {
+	term := []corev1.PodAffinityTerm{{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: serverLabels},
+		TopologyKey:   networkTopologyKeys[n.Topology],
+	}}
+	if n.Topology == config.NetworkSameNode {
+		return &corev1.Affinity{PodAffinity: &corev1.PodAffinity{RequiredDuringSchedulingIgnoredDuringExecution: term}}
+	}
+	return &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{RequiredDuringSchedulingIgnoredDuringExecution: term}}
+}
+
+// parseNetworkOutput parses the iperf3 JSON output and the netperf CSV output of the latency test
+func parseNetworkOutput(logs []byte, latency bool, result *prometheus.NetworkResult) error {
+	iperfLogs, netperfLogs, found := bytes.Cut(logs, []byte(netperfMarker))
+	if latency && !found {
+		return fmt.Errorf("netperf output not found: %s", bytes.TrimSpace(logs))
+	}
+	start := bytes.IndexByte(iperfLogs, '{')
+	if start < 0 {
+		return fmt.Errorf("iperf3 JSON output not found: %s", bytes.TrimSpace(iperfLogs))
+	}
+	var iperf iperfOutput
+	if err := json.NewDecoder(bytes.NewReader(iperfLogs[start:])).Decode(&iperf); err != nil {
+		return fmt.Errorf("error decoding iperf3 output: %v", err)
+	}
+	if iperf.Error != "" {
+		return fmt.Errorf("iperf3 failed: %s", iperf.Error)
+	}
+	if result.Protocol == string(config.NetworkUDP) {
+		result.Throughput = iperf.End.Sum.BitsPerSecond
+		result.Jitter = iperf.End.Sum.JitterMs
+		result.LostPercent = iperf.End.Sum.LostPercent
+	} else {
+		result.Throughput = iperf.End.SumReceived.BitsPerSecond
+		result.Retransmits = iperf.End.SumSent.Retransmits
+	}
+	if !latency {
+		return nil
+	}
+	// The last line holds the values of the output selectors
+	lines := strings.Split(strings.TrimSpace(string(netperfLogs)), "\n")
+	fields := strings.Split(strings.TrimSpace(lines[len(lines)-1]), ",")
+	values := make([]float64, len(fields))
+	for i, field := range fields {
+		value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return fmt.Errorf("invalid netperf output: %s", bytes.TrimSpace(netperfLogs))
+		}
+		values[i] = value
+	}
+	if len(values) != 5 {
+		return fmt.Errorf("invalid netperf output: %s", bytes.TrimSpace(netperfLogs))
+	}
+	result.LatencyAvg, result.LatencyP50, result.LatencyP90, result.LatencyP99, result.TransactionRate = values[0], values[1], values[2], values[3], values[4]
+	return nil
+}
+
+// summary returns the results of the pairs, it's nil-safe
+func (n *networkRunner) summary() []prometheus.NetworkResult {
+	if n == nil {
+		return nil
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var failed int
+	for _, result := range n.results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		log.Warnf("%d/%d network pairs failed", failed, len(n.results))
+	}
+	return n.results
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+// Path of the pod spec of the kinds whose pods are pinned to the target nodes
+var kindToPodSpecPath = map[string][]string{
+	Pod:                              {"spec"},
+	Deployment:                       {"spec", "template", "spec"},
+	DaemonSet:                        {"spec", "template", "spec"},
+	ReplicaSet:                       {"spec", "template", "spec"},
+	StatefulSet:                      {"spec", "template", "spec"},
+	ReplicationController:            {"spec", "template", "spec"},
+	Job:                              {"spec", "template", "spec"},
+	"CronJob":                        {"spec", "jobTemplate", "spec", "template", "spec"},
+	VirtualMachine:                   {"spec", "template", "spec"},
+	VirtualMachineInstance:           {"spec"},
+	VirtualMachineInstanceReplicaSet: {"spec", "template", "spec"},
+}
+
+// archLabel well-known node label holding the CPU architecture of the node
+const archLabel = "kubernetes.io/arch"
+
+// targetNodeSelector returns the targetNodes selector of the job, restricted to the nodes of its architecture
+func targetNodeSelector(job config.Job) string {
+	if job.Architecture == "" {
+		return job.TargetNodes
+	}
+	archSelector := archLabel + "=" + job.Architecture
+	if job.TargetNodes == "" {
+		return archSelector
+	}
+	return job.TargetNodes + "," + archSelector
+}
+
+// newNodeAffinity returns the node selector matching the nodes selected by targetNodes and not by excludeNodes,
+// nil when neither is set. Selectors are validated when parsing the configuration
+func newNodeAffinity(targetNodes, excludeNodes string) *corev1.NodeSelector {
+	if targetNodes == "" && excludeNodes == "" {
+		return nil
+	}
+	target, _ := labels.Parse(targetNodes)
+	exclude, _ := labels.Parse(excludeNodes)
+	targetRequirements, _ := target.Requirements()
+	excludeRequirements, _ := exclude.Requirements()
+	var targetExpressions []corev1.NodeSelectorRequirement
+	for _, requirement := range targetRequirements {
+		targetExpressions = append(targetExpressions, nodeSelectorRequirements(requirement, false)...)
+	}
+	if len(excludeRequirements) == 0 {
+		return &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: targetExpressions}}}
+	}
+	// Excluded nodes match every requirement of excludeNodes, so the remaining nodes are those not matching any of
+	// them: one term, ORed, per negated requirement
+	nodeSelector := &corev1.NodeSelector{}
+	for _, requirement := range excludeRequirements {
+		for _, negated := range nodeSelectorRequirements(requirement, true) {
+			nodeSelector.NodeSelectorTerms = append(nodeSelector.NodeSelectorTerms, corev1.NodeSelectorTerm{
+				MatchExpressions: append(slices.Clone(targetExpressions), negated),
+			})
+		}
+	}
+	return nodeSelector
+}
+
+// nodeSelectorRequirements converts a label selector requirement into node selector requirements, ORed when negated
+func nodeSelectorRequirements(requirement labels.Requirement, negate bool) []corev1.NodeSelectorRequirement {
+	key, values := requirement.Key(), requirement.Values().List()
+	var operator corev1.NodeSelectorOperator
+	switch requirement.Operator() {
+	case selection.In, selection.Equals, selection.DoubleEquals:
+		operator = corev1.NodeSelectorOpIn
+		if negate {
+			operator = corev1.NodeSelectorOpNotIn
+		}
+	case selection.NotIn, selection.NotEquals:
+		operator = corev1.NodeSelectorOpNotIn
+		if negate {
+			operator = corev1.NodeSelectorOpIn
+		}
+	case selection.Exists:
+		operator = corev1.NodeSelectorOpExists
+		if negate {
+			operator = corev1.NodeSelectorOpDoesNotExist
+		}
+	case selection.DoesNotExist:
+		operator = corev1.NodeSelectorOpDoesNotExist
+		if negate {
+			operator = corev1.NodeSelectorOpExists
+		}
+	case selection.GreaterThan, selection.LessThan:
+		operator = corev1.NodeSelectorOpGt
+		if requirement.Operator() == selection.LessThan {
+			operator = corev1.NodeSelectorOpLt
+		}
+		if negate {
+			// The label is either missing, or not greater (lower) than the value
+			value, _ := strconv.ParseInt(values[0], 10, 64)
+			if operator == corev1.NodeSelectorOpGt {
+				operator, value = corev1.NodeSelectorOpLt, value+1
+			} else {
+				operator, value = corev1.NodeSelectorOpGt, value-1
+			}
+			return []corev1.NodeSelectorRequirement{
+				{Key: key, Operator: operator, Values: []string{strconv.FormatInt(value, 10)}},
+				{Key: key, Operator: corev1.NodeSelectorOpDoesNotExist},
+			}
+		}
+	}
+	if operator == corev1.NodeSelectorOpExists || operator == corev1.NodeSelectorOpDoesNotExist {
+		values = nil
+	}
+	return []corev1.NodeSelectorRequirement{{Key: key, Operator: operator, Values: values}}
+}
+
+// resolveTargetNodes returns the number of nodes the pods of the job can be scheduled on
+func (ex *JobExecutor) resolveTargetNodes(ctx context.Context) (int, error) {
+	if ex.nodeAffinity == nil {
+		return 0, nil
+	}
+	targetNodes := targetNodeSelector(ex.Job)
+	target, _ := labels.Parse(targetNodes)
+	exclude, _ := labels.Parse(ex.ExcludeNodes)
+	nodes, err := ex.clientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: targetNodes})
+	if err != nil {
+		return 0, fmt.Errorf("error listing target nodes: %v", err)
+	}
+	var count int
+	for _, node := range nodes.Items {
+		nodeLabels := labels.Set(node.Labels)
+		if target.Matches(nodeLabels) && (exclude.Empty() || !exclude.Matches(nodeLabels)) {
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("no nodes match targetNodes %q and excludeNodes %q", targetNodes, ex.ExcludeNodes)
+	}
+	log.Infof("Job %s: pods pinned to %d nodes", ex.Name, count)
+	return count, nil
+}
+
+// setNodeAffinity pins the pods of the object to the target nodes. Required node affinity terms of the template are
+// combined with the target ones, so that pods still satisfy both
+func (ex *JobExecutor) setNodeAffinity(obj *unstructured.Unstructured) {
+	podSpecPath, ok := kindToPodSpecPath[obj.GetKind()]
+	if ex.nodeAffinity == nil || !ok {
+		return
+	}
+	path := append(slices.Clone(podSpecPath), "affinity", "nodeAffinity", "requiredDuringSchedulingIgnoredDuringExecution")
+	nodeSelector := ex.nodeAffinity
+	if existing, found, _ := unstructured.NestedMap(obj.Object, path...); found {
+		var templateSelector corev1.NodeSelector
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(existing, &templateSelector); err != nil {
+			log.Errorf("Error decoding the node affinity of %s: %v", obj.GetName(), err)
+			return
+		}
+		if len(templateSelector.NodeSelectorTerms) > 0 {
+			nodeSelector = &corev1.NodeSelector{}
+		}
+		for _, templateTerm := range templateSelector.NodeSelectorTerms {
+			for _, term := range ex.nodeAffinity.NodeSelectorTerms {
+				nodeSelector.NodeSelectorTerms = append(nodeSelector.NodeSelectorTerms, corev1.NodeSelectorTerm{
+					MatchExpressions: slices.Concat(templateTerm.MatchExpressions, term.MatchExpressions),
+					MatchFields:      templateTerm.MatchFields,
+				})
+			}
+		}
+	}
+	affinity, err := runtime.DefaultUnstructuredConverter.ToUnstructured(nodeSelector)
+	if err != nil {
+		log.Errorf("Error encoding the node affinity of %s: %v", obj.GetName(), err)
+		return
+	}
+	unstructured.SetNestedMap(obj.Object, affinity, path...)
+}
+
+// setArchToleration lets the pods of the object tolerate the architecture taint, like kubernetes.io/arch=arm64:NoSchedule,
+// that some providers set on the nodes not matching the default architecture of the cluster
+func (ex *JobExecutor) setArchToleration(obj *unstructured.Unstructured) {
+	podSpecPath, ok := kindToPodSpecPath[obj.GetKind()]
+	if ex.Architecture == "" || !ok {
+		return
+	}
+	path := append(slices.Clone(podSpecPath), "tolerations")
+	tolerations, _, _ := unstructured.NestedSlice(obj.Object, path...)
+	for _, t := range tolerations {
+		if toleration, ok := t.(map[string]any); ok && toleration["key"] == archLabel {
+			return
+		}
+	}
+	tolerations = append(tolerations, map[string]any{
+		"key":      archLabel,
+		"operator": string(corev1.TolerationOpEqual),
+		"value":    ex.Architecture,
+		"effect":   string(corev1.TaintEffectNoSchedule),
+	})
+	unstructured.SetNestedSlice(obj.Object, tolerations, path...)
+}
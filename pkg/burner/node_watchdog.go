@@ -0,0 +1,281 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// nodeWatchdog polls the conditions of the nodes during the benchmark, recording the periods they're unhealthy as
+// incidents. When the fraction of unhealthy nodes reaches the configured ratio, the benchmark is paused until they
+// recover, or aborted
+type nodeWatchdog struct {
+	config    config.NodeWatchdog
+	clientSet kubernetes.Interface
+	// cancel cancels the context of the benchmark when it's aborted
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	// open incidents by node and condition
+	open      map[string]*prometheus.NodeIncident
+	incidents []*prometheus.NodeIncident
+	// breached whether the unhealthy ratio was reached in the last poll
+	breached bool
+	// resume closed when the nodes recover, nil while the benchmark isn't paused
+	resume      chan struct{}
+	pausedSince time.Time
+	abortCh     chan struct{}
+	abortErr    error
+	stopCh      chan struct{}
+	wg          sync.WaitGroup
+}
+
+// startNodeWatchdog starts monitoring the nodes, returns nil when not configured
+func startNodeWatchdog(nodeWatchdogConfig *config.NodeWatchdog, kubeClientProvider *config.KubeClientProvider, cancel context.CancelFunc) *nodeWatchdog {
+	if nodeWatchdogConfig == nil {
+		return nil
+	}
+	clientSet, _ := kubeClientProvider.DefaultClientSet()
+	nw := &nodeWatchdog{
+		config:    *nodeWatchdogConfig,
+		clientSet: clientSet,
+		cancel:    cancel,
+		open:      make(map[string]*prometheus.NodeIncident),
+		abortCh:   make(chan struct{}),
+		stopCh:    make(chan struct{}),
+	}
+	log.Infof("Node watchdog monitoring %v every %v, action %s when %.0f%% of the nodes are unhealthy", nw.config.Conditions, nw.config.Interval, nw.config.Action, nw.config.MaxUnhealthyRatio*100)
+	nw.poll()
+	nw.wg.Add(1)
+	go nw.run()
+	return nw
+}
+
+func (nw *nodeWatchdog) run() {
+	defer nw.wg.Done()
+	ticker := time.NewTicker(nw.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-nw.stopCh:
+			return
+		case <-ticker.C:
+			nw.poll()
+		}
+	}
+}
+
+// poll lists the nodes, updates the incidents and takes the action when too many nodes are unhealthy
+func (nw *nodeWatchdog) poll() {
+	ctx, cancel := context.WithTimeout(context.Background(), nw.config.Interval)
+	defer cancel()
+	nodes, err := nw.clientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: nw.config.LabelSelector})
+	if err != nil {
+		log.Warnf("Node watchdog: error listing nodes: %v", err)
+		return
+	}
+	now := time.Now().UTC()
+	nw.mu.Lock()
+	defer nw.mu.Unlock()
+	seen := make(map[string]bool)
+	unhealthyNodes := 0
+	for _, node := range nodes.Items {
+		unhealthy := false
+		for _, condition := range node.Status.Conditions {
+			if !slices.Contains(nw.config.Conditions, string(condition.Type)) {
+				continue
+			}
+			key := node.Name + "/" + string(condition.Type)
+			if !conditionUnhealthy(condition) {
+				continue
+			}
+			unhealthy = true
+			seen[key] = true
+			if _, ok := nw.open[key]; ok {
+				continue
+			}
+			start := now
+			if !condition.LastTransitionTime.IsZero() {
+				start = condition.LastTransitionTime.UTC()
+			}
+			log.Warnf("Node watchdog: node %s condition %s is %s %s", node.Name, condition.Type, condition.Status, condition.Message)
+			incident := &prometheus.NodeIncident{
+				Timestamp: start,
+				Node:      node.Name,
+				Condition: string(condition.Type),
+				Status:    string(condition.Status),
+				Reason:    condition.Reason,
+				Message:   condition.Message,
+			}
+			nw.open[key] = incident
+			nw.incidents = append(nw.incidents, incident)
+		}
+		if unhealthy {
+			unhealthyNodes++
+		}
+	}
+	// Incidents of recovered or deleted nodes
+	for key, incident := range nw.open {
+		if !seen[key] {
+			log.Infof("Node watchdog: node %s condition %s recovered after %v", incident.Node, incident.Condition, now.Sub(incident.Timestamp).Round(time.Second))
+			incident.EndTimestamp = &now
+			delete(nw.open, key)
+		}
+	}
+	if len(nodes.Items) == 0 {
+		return
+	}
+	nw.react(now, unhealthyNodes, len(nodes.Items))
+}
+
+// conditionUnhealthy returns whether the node condition is unhealthy: Ready when it's not True, and the rest when True
+func conditionUnhealthy(condition corev1.NodeCondition) bool {
+	if condition.Type == corev1.NodeReady {
+		return condition.Status != corev1.ConditionTrue
+	}
+	return condition.Status == corev1.ConditionTrue
+}
+
+// react pauses, resumes or aborts the benchmark according to the fraction of unhealthy nodes
+func (nw *nodeWatchdog) react(now time.Time, unhealthyNodes, nodes int) {
+	ratio := float64(unhealthyNodes) / float64(nodes)
+	breached := ratio >= nw.config.MaxUnhealthyRatio
+	wasBreached := nw.breached
+	nw.breached = breached
+	if nw.abortErr != nil {
+		return
+	}
+	switch {
+	case nw.resume != nil && !breached:
+		log.Infof("Node watchdog: %d/%d nodes unhealthy, resuming the benchmark after %v", unhealthyNodes, nodes, now.Sub(nw.pausedSince).Round(time.Second))
+		close(nw.resume)
+		nw.resume = nil
+	case !breached:
+	case nw.config.Action == config.NodeWatchdogAbort:
+		nw.abort(fmt.Errorf("node watchdog: %d/%d nodes unhealthy, reaching the maximum unhealthy ratio %v", unhealthyNodes, nodes, nw.config.MaxUnhealthyRatio))
+	case nw.config.Action == config.NodeWatchdogPause && nw.resume == nil:
+		log.Warnf("Node watchdog: %d/%d nodes unhealthy, pausing the benchmark for up to %v", unhealthyNodes, nodes, nw.config.PauseTimeout)
+		nw.resume = make(chan struct{})
+		nw.pausedSince = now
+	case nw.config.Action == config.NodeWatchdogPause && now.Sub(nw.pausedSince) >= nw.config.PauseTimeout:
+		nw.abort(fmt.Errorf("node watchdog: %d/%d nodes still unhealthy after pausing the benchmark for %v", unhealthyNodes, nodes, nw.config.PauseTimeout))
+	case nw.config.Action == config.NodeWatchdogRecord && !wasBreached:
+		log.Warnf("Node watchdog: %d/%d nodes unhealthy, reaching the maximum unhealthy ratio %v", unhealthyNodes, nodes, nw.config.MaxUnhealthyRatio)
+	}
+}
+
+// abort cancels the benchmark, it must be called holding the lock
+func (nw *nodeWatchdog) abort(err error) {
+	log.Error(err.Error())
+	nw.abortErr = err
+	if nw.resume != nil {
+		close(nw.resume)
+		nw.resume = nil
+	}
+	close(nw.abortCh)
+	nw.cancel()
+}
+
+// wait blocks while the benchmark is paused
+func (nw *nodeWatchdog) wait(ctx context.Context) error {
+	if nw == nil {
+		return nil
+	}
+	nw.mu.Lock()
+	resume := nw.resume
+	nw.mu.Unlock()
+	if resume == nil {
+		return nil
+	}
+	select {
+	case <-resume:
+		return ctx.Err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// aborted returns a channel closed when the benchmark is aborted, nil when not configured
+func (nw *nodeWatchdog) aborted() <-chan struct{} {
+	if nw == nil {
+		return nil
+	}
+	return nw.abortCh
+}
+
+// abortError returns the reason the benchmark was aborted
+func (nw *nodeWatchdog) abortError() error {
+	nw.mu.Lock()
+	defer nw.mu.Unlock()
+	return nw.abortErr
+}
+
+// stop stops monitoring the nodes, releasing the paused jobs
+func (nw *nodeWatchdog) stop() {
+	if nw == nil {
+		return
+	}
+	close(nw.stopCh)
+	nw.wg.Wait()
+	nw.mu.Lock()
+	defer nw.mu.Unlock()
+	if nw.resume != nil {
+		close(nw.resume)
+		nw.resume = nil
+	}
+}
+
+// attribute sets the incidents overlapping each job, the duration of the incidents still open is accounted until
+// the end of the job
+func (nw *nodeWatchdog) attribute(jobs []prometheus.Job) {
+	if nw == nil {
+		return
+	}
+	nw.mu.Lock()
+	defer nw.mu.Unlock()
+	for i, job := range jobs {
+		end := job.End
+		if end.IsZero() {
+			end = time.Now().UTC()
+		}
+		var incidents []prometheus.NodeIncident
+		for _, incident := range nw.incidents {
+			if incident.Timestamp.After(end) || (incident.EndTimestamp != nil && incident.EndTimestamp.Before(job.Start)) {
+				continue
+			}
+			jobIncident := *incident
+			if jobIncident.EndTimestamp != nil {
+				jobIncident.Duration = jobIncident.EndTimestamp.Sub(jobIncident.Timestamp).Milliseconds()
+			} else {
+				jobIncident.Duration = end.Sub(jobIncident.Timestamp).Milliseconds()
+			}
+			incidents = append(incidents, jobIncident)
+		}
+		if len(incidents) > 0 {
+			log.Warnf("Job %s: %d node incidents observed", job.JobConfig.Name, len(incidents))
+		}
+		jobs[i].NodeIncidents = incidents
+	}
+}
@@ -54,7 +54,7 @@ func patchHandler(ex *JobExecutor, obj *object, originalItem unstructured.Unstru
 	// There are several patch modes. Three of them are client-side, and one
 	// of them is server-side.
 	var data []byte
-	patchOptions := metav1.PatchOptions{}
+	patchOptions := metav1.PatchOptions{FieldValidation: ex.fieldValidation}
 
 	if strings.HasSuffix(obj.ObjectTemplate, "json") {
 		if obj.PatchType == string(types.ApplyPatchType) {
@@ -69,22 +69,29 @@ func patchHandler(ex *JobExecutor, obj *object, originalItem unstructured.Unstru
 		} else {
 			asJson = true
 		}
-		data = ex.renderTemplateForObject(obj, iteration, 0, asJson)
+		var err error
+		if data, err = ex.renderTemplateForObject(obj, iteration, 0, asJson); err != nil {
+			ex.abort(err)
+			return
+		}
 	}
 
 	ns := originalItem.GetNamespace()
 	log.Debugf("Patching %s/%s in namespace %s", originalItem.GetKind(),
 		originalItem.GetName(), ns)
-	ex.limiter.Wait(context.TODO())
+	// The job context is cancelled once the job breaches its error limits
+	if ex.limiter.Wait(ex.jobContext()) != nil {
+		return
+	}
 
 	var uns *unstructured.Unstructured
 	var err error
 	if obj.namespaced {
-		uns, err = ex.dynamicClient.Resource(obj.gvr).Namespace(ns).
+		uns, err = ex.client(iteration).Resource(obj.gvr).Namespace(ns).
 			Patch(context.TODO(), originalItem.GetName(),
 				types.PatchType(obj.PatchType), data, patchOptions)
 	} else {
-		uns, err = ex.dynamicClient.Resource(obj.gvr).
+		uns, err = ex.client(iteration).Resource(obj.gvr).
 			Patch(context.TODO(), originalItem.GetName(),
 				types.PatchType(obj.PatchType), data, patchOptions)
 	}
@@ -94,6 +101,7 @@ func patchHandler(ex *JobExecutor, obj *object, originalItem unstructured.Unstru
 		} else {
 			log.Errorf("Error patching object %s/%s in namespace %s: %s", originalItem.GetKind(),
 				originalItem.GetName(), ns, err)
+			ex.recordError()
 		}
 	} else {
 		log.Debugf("Patched %s/%s in namespace %s", uns.GetKind(), uns.GetName(), ns)
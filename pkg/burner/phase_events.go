@@ -0,0 +1,161 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Reasons of the phase transition events
+const (
+	PhaseJobStarted                = "JobStarted"
+	PhaseJobFinished               = "JobFinished"
+	phaseChurnCycleStarted         = "ChurnCycleStarted"
+	phaseChurnCycleFinished        = "ChurnCycleFinished"
+	phaseGarbageCollectionStarted  = "GarbageCollectionStarted"
+	phaseGarbageCollectionFinished = "GarbageCollectionFinished"
+)
+
+// Annotations of the run ConfigMap holding the latest phase transition
+const (
+	phaseAnnotation          = "kube-burner.io/phase"
+	phaseJobAnnotation       = "kube-burner.io/phase-job"
+	phaseTimestampAnnotation = "kube-burner.io/phase-timestamp"
+)
+
+// phaseRecorder publishes the phase transitions of the benchmark as events involving the run ConfigMap, which
+// annotations hold the latest transition and which data holds the timestamps of all of them
+type phaseRecorder struct {
+	mu        sync.Mutex
+	clientSet kubernetes.Interface
+	uuid      string
+	configMap *corev1.ConfigMap
+}
+
+// PhaseObserver is notified of the phase transitions of the benchmarks, the job is empty for the transitions of the
+// whole benchmark
+type PhaseObserver func(job, phase, message string)
+
+var phaseObserver atomic.Pointer[PhaseObserver]
+
+// ObservePhases sets the observer of the phase transitions of the benchmarks run by this process, nil to unset it.
+// It's notified regardless of the phaseEvents configuration
+func ObservePhases(observer PhaseObserver) {
+	if observer == nil {
+		phaseObserver.Store(nil)
+		return
+	}
+	phaseObserver.Store(&observer)
+}
+
+// newPhaseRecorder creates the run ConfigMap, returns nil when phase events are disabled or it can't be created
+func newPhaseRecorder(globalConfig config.GlobalConfig, kubeClientProvider *config.KubeClientProvider) *phaseRecorder {
+	if globalConfig.PhaseEvents == nil {
+		return nil
+	}
+	clientSet, _ := kubeClientProvider.DefaultClientSet()
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kube-burner-" + globalConfig.UUID,
+			Namespace: globalConfig.PhaseEvents.Namespace,
+			Labels:    map[string]string{"kube-burner-uuid": globalConfig.UUID},
+		},
+		Data: map[string]string{"uuid": globalConfig.UUID},
+	}
+	created, err := clientSet.CoreV1().ConfigMaps(configMap.Namespace).Create(context.TODO(), configMap, metav1.CreateOptions{})
+	// Workers, and runs reusing the UUID, share the ConfigMap
+	if errors.IsAlreadyExists(err) {
+		created, err = clientSet.CoreV1().ConfigMaps(configMap.Namespace).Get(context.TODO(), configMap.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		log.Errorf("Error creating phase events ConfigMap %s/%s, phase events disabled: %v", configMap.Namespace, configMap.Name, err)
+		return nil
+	}
+	log.Infof("Publishing phase transitions in ConfigMap %s/%s", created.Namespace, created.Name)
+	return &phaseRecorder{clientSet: clientSet, uuid: globalConfig.UUID, configMap: created}
+}
+
+// record publishes a phase transition of a job, or of the whole benchmark when job is empty. The key of the transition
+// in the ConfigMap data is <job>.<phase>, suffixed by the cycle number in churn cycles. Errors aren't fatal
+func (pr *phaseRecorder) record(job, phase string, cycle int, message string) {
+	if observer := phaseObserver.Load(); observer != nil {
+		(*observer)(job, phase, message)
+	}
+	if pr == nil {
+		return
+	}
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	now := time.Now().UTC()
+	key := phase
+	if job != "" {
+		key = job + "." + phase
+	}
+	if cycle > 0 {
+		key = fmt.Sprintf("%s.%d", key, cycle)
+	}
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: pr.configMap.Name + "-",
+			Namespace:    pr.configMap.Namespace,
+			Labels:       map[string]string{"kube-burner-uuid": pr.uuid},
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion:      "v1",
+			Kind:            "ConfigMap",
+			Name:            pr.configMap.Name,
+			Namespace:       pr.configMap.Namespace,
+			UID:             pr.configMap.UID,
+			ResourceVersion: pr.configMap.ResourceVersion,
+		},
+		Reason:         phase,
+		Message:        message,
+		Type:           corev1.EventTypeNormal,
+		Source:         corev1.EventSource{Component: "kube-burner"},
+		FirstTimestamp: metav1.NewTime(now),
+		LastTimestamp:  metav1.NewTime(now),
+		Count:          1,
+	}
+	if _, err := pr.clientSet.CoreV1().Events(pr.configMap.Namespace).Create(context.TODO(), event, metav1.CreateOptions{}); err != nil {
+		log.Warnf("Error creating %s event: %v", phase, err)
+	}
+	timestamp := now.Format(time.RFC3339Nano)
+	patch, _ := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{
+				phaseAnnotation:          phase,
+				phaseJobAnnotation:       job,
+				phaseTimestampAnnotation: timestamp,
+			},
+		},
+		"data": map[string]string{key: timestamp},
+	})
+	if _, err := pr.clientSet.CoreV1().ConfigMaps(pr.configMap.Namespace).Patch(context.TODO(), pr.configMap.Name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		log.Warnf("Error annotating ConfigMap %s/%s with the %s phase: %v", pr.configMap.Namespace, pr.configMap.Name, phase, err)
+	}
+}
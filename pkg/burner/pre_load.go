@@ -100,7 +100,9 @@ func getJobImages(job JobExecutor) ([]string, error) {
 		if err != nil {
 			return imageList, err
 		}
-		yamlToUnstructured(object.ObjectTemplate, renderedObj, &unstructuredObject)
+		if _, _, err := yamlToUnstructured(object.ObjectTemplate, renderedObj, &unstructuredObject); err != nil {
+			return imageList, err
+		}
 		switch unstructuredObject.GetKind() {
 		case Deployment, DaemonSet, ReplicaSet, Job, StatefulSet:
 			var pod NestedPod
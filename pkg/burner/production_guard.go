@@ -0,0 +1,161 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const productionGuardTimeout = time.Minute
+
+// Label selectors of the API server pods, kubeadm static pods and OpenShift
+var apiServerPodSelectors = []string{"component=kube-apiserver", "app=openshift-kube-apiserver"}
+
+// API server flags enabling audit logging
+var auditFlags = []string{"--audit-log-path", "--audit-webhook-config-file"}
+
+var openShiftAPIServerGVR = schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "apiservers"}
+
+// checkProductionGuard looks for the production markers in the cluster, running against a cluster matching any of
+// them requires to be confirmed, either with the API server URL of the cluster in the configuration or with the
+// --allow-production flag
+func checkProductionGuard(productionGuard *config.ProductionGuard, kubeClientProvider *config.KubeClientProvider) error {
+	if productionGuard == nil {
+		return nil
+	}
+	clientSet, restConfig := kubeClientProvider.DefaultClientSet()
+	markers := productionMarkers(*productionGuard, clientSet, dynamic.NewForConfigOrDie(restConfig), restConfig.Host)
+	if len(markers) == 0 {
+		log.Infof("Production guard: %s doesn't look like a production cluster", restConfig.Host)
+		return nil
+	}
+	for _, marker := range markers {
+		log.Warnf("Production guard: %s", marker)
+	}
+	switch {
+	case productionGuard.Allow:
+		log.Warnf("⚠️ Production guard: running against %s, confirmed with --allow-production", restConfig.Host)
+	case productionGuard.Confirm == restConfig.Host:
+		log.Warnf("⚠️ Production guard: running against %s, confirmed in the configuration", restConfig.Host)
+	default:
+		return fmt.Errorf("%s looks like a production cluster, confirm it by setting productionGuard.confirm to %q or with --allow-production", restConfig.Host, restConfig.Host)
+	}
+	return nil
+}
+
+// productionMarkers returns the production markers found in the cluster. Markers that can't be checked, e.g. due to
+// missing permissions, are reported as found
+func productionMarkers(productionGuard config.ProductionGuard, clientSet kubernetes.Interface, dynamicClient dynamic.Interface, host string) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), productionGuardTimeout)
+	defer cancel()
+	var markers []string
+	for _, apiServer := range productionGuard.APIServers {
+		if regexp.MustCompile(apiServer).MatchString(host) {
+			markers = append(markers, fmt.Sprintf("API server URL %s matches %q", host, apiServer))
+		}
+	}
+	if productionGuard.MaxNodes > 0 || len(productionGuard.Labels) > 0 || len(productionGuard.Annotations) > 0 {
+		nodes, err := clientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			markers = append(markers, fmt.Sprintf("error listing nodes: %v", err))
+		} else {
+			if productionGuard.MaxNodes > 0 && len(nodes.Items) > productionGuard.MaxNodes {
+				markers = append(markers, fmt.Sprintf("%d nodes, more than %d", len(nodes.Items), productionGuard.MaxNodes))
+			}
+			for _, node := range nodes.Items {
+				markers = append(markers, metadataMarkers(productionGuard, "node", node.ObjectMeta)...)
+			}
+		}
+	}
+	if len(productionGuard.Labels) > 0 || len(productionGuard.Annotations) > 0 {
+		namespaces, err := clientSet.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			markers = append(markers, fmt.Sprintf("error listing namespaces: %v", err))
+		} else {
+			for _, namespace := range namespaces.Items {
+				markers = append(markers, metadataMarkers(productionGuard, "namespace", namespace.ObjectMeta)...)
+			}
+		}
+	}
+	if productionGuard.AuditLogging {
+		if marker := auditLoggingMarker(ctx, clientSet, dynamicClient); marker != "" {
+			markers = append(markers, marker)
+		}
+	}
+	// Markers found in many objects are reported once
+	slices.Sort(markers)
+	return slices.Compact(markers)
+}
+
+// metadataMarkers returns the production labels and annotations of an object
+func metadataMarkers(productionGuard config.ProductionGuard, kind string, objectMeta metav1.ObjectMeta) []string {
+	var markers []string
+	match := func(field string, values, markerValues map[string]string) {
+		for key, markerValue := range markerValues {
+			if value, ok := values[key]; ok && (markerValue == "" || value == markerValue) {
+				markers = append(markers, fmt.Sprintf("%s with %s %s=%s", kind, field, key, value))
+			}
+		}
+	}
+	match("label", objectMeta.Labels, productionGuard.Labels)
+	match("annotation", objectMeta.Annotations, productionGuard.Annotations)
+	return markers
+}
+
+// auditLoggingMarker returns the marker of the API server audit logging, from the flags of its pods or from the audit
+// profile of OpenShift, empty when not enabled
+func auditLoggingMarker(ctx context.Context, clientSet kubernetes.Interface, dynamicClient dynamic.Interface) string {
+	if apiServer, err := dynamicClient.Resource(openShiftAPIServerGVR).Get(ctx, "cluster", metav1.GetOptions{}); err == nil {
+		profile, found, _ := unstructured.NestedString(apiServer.Object, "spec", "audit", "profile")
+		// The Default profile is used when not set
+		if !found || profile != "None" {
+			return fmt.Sprintf("API server audit logging enabled with the %s profile", cmp.Or(profile, "Default"))
+		}
+		return ""
+	}
+	for _, selector := range apiServerPodSelectors {
+		pods, err := clientSet.CoreV1().Pods(corev1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return fmt.Sprintf("error listing the API server pods: %v", err)
+		}
+		for _, pod := range pods.Items {
+			for _, container := range pod.Spec.Containers {
+				for _, arg := range slices.Concat(container.Command, container.Args) {
+					for _, flag := range auditFlags {
+						if strings.HasPrefix(arg, flag) {
+							return fmt.Sprintf("API server audit logging enabled in pod %s/%s with %s", pod.Namespace, pod.Name, flag)
+						}
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// How often the QPS of the limiter is updated
+	qpsRampUpdateInterval = time.Second
+	// How often the target and achieved QPS are sampled
+	qpsRampSampleInterval = 10 * time.Second
+)
+
+// qpsRamper updates the QPS of the job limiter following its ramp profile
+type qpsRamper struct {
+	ramp       config.QPSRamp
+	targetQPS  float64
+	limiter    *rate.Limiter
+	operations *int32
+	samples    []prometheus.QPSSample
+	stopCh     chan struct{}
+	doneCh     chan struct{}
+	stopOnce   sync.Once
+}
+
+// startQPSRamp starts shaping the QPS of the job, it returns nil when the job has no QPS ramp
+func (ex *JobExecutor) startQPSRamp() *qpsRamper {
+	if ex.QPSRamp == nil {
+		return nil
+	}
+	r := &qpsRamper{
+		ramp:       *ex.QPSRamp,
+		targetQPS:  float64(ex.QPS),
+		limiter:    ex.limiter,
+		operations: &ex.objectOperations,
+		stopCh:     make(chan struct{}),
+		doneCh:     make(chan struct{}),
+	}
+	log.Infof("Job %s: ramping QPS from %v to %v in %v, %s profile", ex.Name, r.ramp.StartQPS, ex.QPS, r.ramp.Duration, r.ramp.Profile)
+	go r.run()
+	return r
+}
+
+func (r *qpsRamper) run() {
+	defer close(r.doneCh)
+	start := time.Now()
+	lastSample, lastOperations := start, atomic.LoadInt32(r.operations)
+	qps := r.qps(0)
+	r.limiter.SetLimit(rate.Limit(qps))
+	updateTicker := time.NewTicker(qpsRampUpdateInterval)
+	defer updateTicker.Stop()
+	for {
+		select {
+		case <-r.stopCh:
+			return
+		case now := <-updateTicker.C:
+			if now.Sub(lastSample) >= qpsRampSampleInterval {
+				operations := atomic.LoadInt32(r.operations)
+				r.samples = append(r.samples, prometheus.QPSSample{
+					Timestamp:   now.UTC(),
+					TargetQPS:   math.Round(qps*1000) / 1000,
+					AchievedQPS: math.Round(float64(operations-lastOperations)/now.Sub(lastSample).Seconds()*1000) / 1000,
+				})
+				lastSample, lastOperations = now, operations
+			}
+			qps = r.qps(now.Sub(start))
+			r.limiter.SetLimit(rate.Limit(qps))
+		}
+	}
+}
+
+// qps returns the QPS of the ramp after the given elapsed time
+func (r *qpsRamper) qps(elapsed time.Duration) float64 {
+	progress := min(float64(elapsed)/float64(r.ramp.Duration), 1)
+	startQPS := float64(r.ramp.StartQPS)
+	switch r.ramp.Profile {
+	case config.RampStep:
+		progress = math.Floor(progress*float64(r.ramp.Steps)) / float64(r.ramp.Steps)
+	case config.RampExponential:
+		return startQPS * math.Pow(r.targetQPS/startQPS, progress)
+	}
+	return startQPS + (r.targetQPS-startQPS)*progress
+}
+
+// stop stops the ramp and returns the QPS samples taken, the job limiter keeps the last QPS. It can be called several times
+func (r *qpsRamper) stop() []prometheus.QPSSample {
+	if r == nil {
+		return nil
+	}
+	r.stopOnce.Do(func() { close(r.stopCh) })
+	<-r.doneCh
+	return r.samples
+}
@@ -41,17 +41,21 @@ func (ex *JobExecutor) setupReadJob(mapper meta.RESTMapper) {
 
 func readHandler(ex *JobExecutor, obj *object, item unstructured.Unstructured, iteration int, objectTimeUTC int64, wg *sync.WaitGroup) {
 	defer wg.Done()
-	ex.limiter.Wait(context.TODO())
+	// The job context is cancelled once the job breaches its error limits
+	if ex.limiter.Wait(ex.jobContext()) != nil {
+		return
+	}
 	var err error
 	if obj.namespaced {
 		log.Debugf("Reading %s/%s from namespace %s", item.GetKind(), item.GetName(), item.GetNamespace())
-		_, err = ex.dynamicClient.Resource(obj.gvr).Namespace(item.GetNamespace()).Get(context.TODO(), item.GetName(), metav1.GetOptions{})
+		_, err = ex.client(iteration).Resource(obj.gvr).Namespace(item.GetNamespace()).Get(context.TODO(), item.GetName(), metav1.GetOptions{ResourceVersion: obj.ResourceVersion})
 	} else {
 		log.Debugf("Reading %s/%s", item.GetKind(), item.GetName())
-		_, err = ex.dynamicClient.Resource(obj.gvr).Get(context.TODO(), item.GetName(), metav1.GetOptions{})
+		_, err = ex.client(iteration).Resource(obj.gvr).Get(context.TODO(), item.GetName(), metav1.GetOptions{ResourceVersion: obj.ResourceVersion})
 	}
 	if err != nil {
 		log.Errorf("Error found reading %s/%s: %s", item.GetKind(), item.GetName(), err)
+		ex.recordError()
 	}
 	atomic.AddInt32(&ex.objectOperations, 1)
 }
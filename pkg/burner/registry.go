@@ -0,0 +1,221 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// ObjectWaiter reports whether an object is ready, objects are waited until all of them are ready or the wait times out
+type ObjectWaiter func(item *unstructured.Unstructured) (bool, error)
+
+// JobRunner runs a job of a custom job type
+type JobRunner interface {
+	// Run runs the job, ctx is cancelled when the benchmark is interrupted or the job breaches its error limits
+	Run(ctx context.Context, job JobContext) error
+}
+
+// NewJobRunner creates the runner of a job of a custom job type, called when the jobs are set up before the benchmark
+// starts, an error aborts the benchmark
+type NewJobRunner func(job config.Job) (JobRunner, error)
+
+// JobContext gives custom job runners access to the job and its clients
+type JobContext interface {
+	// Config returns the configuration of the job
+	Config() config.Job
+	// UUID returns the benchmark UUID
+	UUID() string
+	// RunID returns the run ID, set on the objects created by the benchmark
+	RunID() string
+	// Labels returns the labels of the objects created by the job, used to garbage collect them
+	Labels() map[string]string
+	ClientSet() kubernetes.Interface
+	DynamicClient() dynamic.Interface
+	RestConfig() *rest.Config
+	// Wait blocks until the job QPS and burst allow a new request
+	Wait(ctx context.Context) error
+	// RunIterations runs an iteration of the job in the namespace of each one, in parallel or sequentially depending
+	// on the execution mode. The namespaces are created and labeled to be garbage collected when createNamespaces is set
+	RunIterations(ctx context.Context, createNamespaces bool, run func(ctx context.Context, iteration int, namespace string))
+	// RecordOperation counts a successful operation of the job
+	RecordOperation()
+	// RecordError counts a failed operation of the job towards its error limits
+	RecordError()
+}
+
+// Kinds with built-in waiters, they can't be overridden
+var builtInWaiterKinds = []string{Deployment, ReplicaSet, ReplicationController, StatefulSet, DaemonSet, Pod, Build, BuildConfig, VirtualMachine, VirtualMachineInstanceReplicaSet, PersistentVolumeClaim, ResourceClaim, VolumeSnapshot, BareMetalHost}
+
+var (
+	registryLock  sync.RWMutex
+	objectWaiters = map[string]ObjectWaiter{}
+	jobRunners    = map[config.JobType]NewJobRunner{}
+)
+
+// RegisterObjectWaiter registers the waiter of the objects of the given kind, so that wrappers can wait for kinds
+// without a built-in waiter
+func RegisterObjectWaiter(kind string, waiter ObjectWaiter) error {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if _, ok := waitersConditionPaths[kind]; ok || slices.Contains(builtInWaiterKinds, kind) {
+		return fmt.Errorf("kind %s has a built-in waiter", kind)
+	}
+	if _, ok := objectWaiters[kind]; ok {
+		return fmt.Errorf("a waiter for kind %s is already registered", kind)
+	}
+	log.Debugf("Registering waiter for kind %s", kind)
+	objectWaiters[kind] = waiter
+	return nil
+}
+
+// RegisterJobType registers a custom job type, so that wrappers can run jobs not supported by kube-burner
+func RegisterJobType(jobType config.JobType, newRunner NewJobRunner) error {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if config.IsBuiltInJobType(jobType) {
+		return fmt.Errorf("job type %s is built-in", jobType)
+	}
+	if _, ok := jobRunners[jobType]; ok {
+		return fmt.Errorf("job type %s is already registered", jobType)
+	}
+	log.Debugf("Registering job type %s", jobType)
+	config.AddJobType(jobType)
+	jobRunners[jobType] = newRunner
+	return nil
+}
+
+func registeredObjectWaiter(kind string) (ObjectWaiter, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	waiter, ok := objectWaiters[kind]
+	return waiter, ok
+}
+
+// hasWaiter returns whether the objects of the kind can be waited without custom status paths
+func hasWaiter(kind string) bool {
+	if _, ok := waitersConditionPaths[kind]; ok || slices.Contains(builtInWaiterKinds, kind) {
+		return true
+	}
+	_, ok := registeredObjectWaiter(kind)
+	return ok
+}
+
+func registeredJobRunner(jobType config.JobType) (NewJobRunner, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	newRunner, ok := jobRunners[jobType]
+	return newRunner, ok
+}
+
+// customJob job of a registered job type
+type customJob struct {
+	runner JobRunner
+	err    error
+}
+
+func (ex *JobExecutor) setupCustomJob(newRunner NewJobRunner) {
+	log.Debugf("Preparing %s job: %s", ex.JobType, ex.Name)
+	runner, err := newRunner(ex.Job)
+	if err != nil {
+		log.Fatalf("Job %s: %v", ex.Name, err)
+	}
+	ex.custom = &customJob{runner: runner}
+}
+
+// runCustomJob runs the job with its registered runner, its error fails the job
+func (ex *JobExecutor) runCustomJob(ctx context.Context) {
+	if err := ex.custom.runner.Run(ctx, ex); err != nil {
+		log.Errorf("Job %s: %v", ex.Name, err)
+		ex.custom.err = fmt.Errorf("job %s: %v", ex.Name, err)
+	}
+}
+
+// error returns the error of the job, nil when it isn't a custom job
+func (cj *customJob) error() error {
+	if cj == nil {
+		return nil
+	}
+	return cj.err
+}
+
+// Config returns the configuration of the job
+func (ex *JobExecutor) Config() config.Job {
+	return ex.Job
+}
+
+// UUID returns the benchmark UUID
+func (ex *JobExecutor) UUID() string {
+	return ex.uuid
+}
+
+// RunID returns the run ID
+func (ex *JobExecutor) RunID() string {
+	return ex.runid
+}
+
+// Labels returns the labels of the objects created by the job
+func (ex *JobExecutor) Labels() map[string]string {
+	return map[string]string{
+		"kube-burner-uuid":  ex.uuid,
+		"kube-burner-job":   ex.Name,
+		"kube-burner-runid": ex.runid,
+	}
+}
+
+// ClientSet returns the clientset of the job, throttled by its QPS and burst
+func (ex *JobExecutor) ClientSet() kubernetes.Interface {
+	return ex.clientSet
+}
+
+// DynamicClient returns the dynamic client of the job, throttled by its QPS and burst
+func (ex *JobExecutor) DynamicClient() dynamic.Interface {
+	return ex.dynamicClient
+}
+
+// RestConfig returns the REST configuration of the job clients
+func (ex *JobExecutor) RestConfig() *rest.Config {
+	return ex.restConfig
+}
+
+// Wait blocks until the job rate limiter allows a new request
+func (ex *JobExecutor) Wait(ctx context.Context) error {
+	return ex.limiter.Wait(ctx)
+}
+
+// RunIterations runs an iteration in the namespace of each iteration
+func (ex *JobExecutor) RunIterations(ctx context.Context, createNamespaces bool, run func(ctx context.Context, iteration int, namespace string)) {
+	ex.runIterations(ctx, createNamespaces, run)
+}
+
+// RecordOperation counts a successful operation of the job
+func (ex *JobExecutor) RecordOperation() {
+	atomic.AddInt32(&ex.objectOperations, 1)
+}
+
+// RecordError counts a failed operation of the job towards its error limits
+func (ex *JobExecutor) RecordError() {
+	ex.recordError()
+}
@@ -0,0 +1,273 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// RenderOptions options of the render subcommand
+type RenderOptions struct {
+	// Sample number of evenly spaced iterations rendered per job, all of them when 0
+	Sample int
+	// OutputDir directory where a file per job is written, objects are written to stdout when empty
+	OutputDir string
+	// DryRun validates the rendered objects with server-side dry-run requests
+	DryRun bool
+}
+
+// renderer renders the objects of a job and optionally validates them against the API server
+type renderer struct {
+	ex            JobExecutor
+	mapper        meta.RESTMapper
+	dynamicClient dynamic.Interface
+	errs          []error
+	// Sources of the rendered object names, used to detect collisions
+	names map[string]string
+}
+
+// Render renders the objects of the create jobs without creating them, so that template errors show up before running the benchmark.
+// The kubeClientProvider is only required for server-side dry-run validation
+func Render(configSpec config.Spec, kubeClientProvider *config.KubeClientProvider, embedCfg *fileutils.EmbedConfiguration, opts RenderOptions) error {
+	var errs []error
+	r := renderer{}
+	if opts.DryRun {
+		_, restConfig := kubeClientProvider.ClientSet(100, 100) // Hardcoded QPS/Burst
+		r.mapper = newRESTMapper(discovery.NewDiscoveryClientForConfigOrDie(restConfig))
+		r.dynamicClient = dynamic.NewForConfigOrDie(restConfig)
+	}
+	if opts.OutputDir != "" {
+		if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+			return err
+		}
+	}
+	for _, job := range configSpec.Jobs {
+		if job.JobType != config.CreationJob {
+			log.Infof("Skipping job %s: only create jobs are rendered", job.Name)
+			continue
+		}
+		r.ex = JobExecutor{
+			Job:               job,
+			uuid:              configSpec.GlobalConfig.UUID,
+			runid:             configSpec.GlobalConfig.RUNID,
+			functionTemplates: configSpec.GlobalConfig.FunctionTemplates,
+			embedCfg:          embedCfg,
+			fieldValidation:   fieldValidation(configSpec.GlobalConfig.Strict),
+		}
+		out := os.Stdout
+		if opts.OutputDir != "" {
+			f, err := os.Create(filepath.Join(opts.OutputDir, job.Name+".yml"))
+			if err != nil {
+				return err
+			}
+			out = f
+		}
+		r.errs = nil
+		rendered := r.renderJob(out, opts.Sample)
+		if out != os.Stdout {
+			out.Close()
+			log.Infof("Job %s: %d objects rendered into %s", job.Name, rendered, out.Name())
+		}
+		errs = append(errs, r.errs...)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// ValidateTemplates checks that the object templates referenced by the jobs exist and render, the objects of the
+// create jobs are also decoded. Only the first iteration is rendered
+func ValidateTemplates(configSpec config.Spec, embedCfg *fileutils.EmbedConfiguration) error {
+	var errs []error
+	for _, job := range configSpec.Jobs {
+		r := renderer{
+			ex: JobExecutor{
+				Job:               job,
+				uuid:              configSpec.GlobalConfig.UUID,
+				runid:             configSpec.GlobalConfig.RUNID,
+				functionTemplates: configSpec.GlobalConfig.FunctionTemplates,
+				embedCfg:          embedCfg,
+			},
+		}
+		if job.JobType == config.CreationJob {
+			r.renderJob(io.Discard, 1)
+			errs = append(errs, r.errs...)
+			continue
+		}
+		for _, o := range job.Objects {
+			if o.ObjectTemplate == "" {
+				continue
+			}
+			f, err := fileutils.GetWorkloadReader(o.ObjectTemplate, embedCfg)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("job %s: error reading template %s: %s", job.Name, o.ObjectTemplate, err))
+				continue
+			}
+			t, err := io.ReadAll(f)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("job %s: error reading template %s: %s", job.Name, o.ObjectTemplate, err))
+				continue
+			}
+			if _, err := r.ex.renderObject(&object{Object: o, objectSpec: t}, 0, 1); err != nil {
+				errs = append(errs, fmt.Errorf("job %s: template error in %s: %v", job.Name, o.ObjectTemplate, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// renderJob writes the rendered objects of the sampled iterations, returns the number of objects rendered
+func (r *renderer) renderJob(out io.Writer, sample int) int {
+	var rendered int
+	r.names = make(map[string]string)
+	for _, o := range r.ex.Objects {
+		if o.Replicas < 1 {
+			continue
+		}
+		f, err := fileutils.GetWorkloadReader(o.ObjectTemplate, r.ex.embedCfg)
+		if err != nil {
+			r.errs = append(r.errs, fmt.Errorf("error reading template %s: %s", o.ObjectTemplate, err))
+			continue
+		}
+		t, err := io.ReadAll(f)
+		if err != nil {
+			r.errs = append(r.errs, fmt.Errorf("error reading template %s: %s", o.ObjectTemplate, err))
+			continue
+		}
+		r.ex.objects = append(r.ex.objects, &object{Object: o, objectSpec: t})
+	}
+	for _, iteration := range sampleIterations(r.ex.JobIterations, sample) {
+		ns := r.ex.namespaceName(r.ex.Namespace)
+		if r.ex.NamespacedIterations {
+			ns = r.ex.generateNamespace(iteration)
+		}
+		for objectIndex, obj := range r.ex.objects {
+			if obj.RunOnce && iteration != 0 {
+				continue
+			}
+			for replica := 1; replica <= obj.Replicas; replica++ {
+				if r.renderReplica(out, obj, objectIndex, iteration, replica, ns) {
+					rendered++
+				}
+			}
+		}
+	}
+	return rendered
+}
+
+func (r *renderer) renderReplica(out io.Writer, obj *object, objectIndex, iteration, replica int, ns string) bool {
+	source := fmt.Sprintf("job %s, iteration %d, replica %d, %s", r.ex.Name, iteration, replica, obj.ObjectTemplate)
+	renderedObj, err := r.ex.renderObject(obj, iteration, replica)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s: template error: %v", source, err))
+		return false
+	}
+	newObject := &unstructured.Unstructured{}
+	if _, _, err := scheme.Codecs.UniversalDeserializer().Decode(renderedObj, nil, newObject); err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s: error decoding YAML: %v", source, err))
+		return false
+	}
+	name, err := r.ex.objectName(newObject.GetName(), objectIndex, iteration, replica)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s: %v", source, err))
+		return false
+	}
+	newObject.SetName(name)
+	if name != "" {
+		// Without a mapper, objects are assumed to be namespaced
+		scope := cmp.Or(newObject.GetNamespace(), ns)
+		if r.mapper != nil {
+			if mapping, err := r.mapper.RESTMapping(newObject.GroupVersionKind().GroupKind()); err == nil && mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+				scope = ""
+			}
+		}
+		key := fmt.Sprintf("%s/%s/%s", newObject.GetKind(), scope, name)
+		if previous, ok := r.names[key]; ok {
+			r.errs = append(r.errs, fmt.Errorf("%s: name collision, %s %s is also rendered by %s", source, newObject.GetKind(), name, previous))
+		}
+		r.names[key] = source
+	}
+	labels := r.ex.objectLabels(objectIndex, iteration)
+	labels[config.KubeBurnerLabelReplica] = strconv.Itoa(replica)
+	maps.Copy(labels, newObject.GetLabels())
+	newObject.SetLabels(labels)
+	setMetadataLabels(newObject, labels)
+	if r.dynamicClient != nil {
+		if err := r.dryRun(newObject); err != nil {
+			r.errs = append(r.errs, fmt.Errorf("%s: dry-run failed: %v", source, err))
+		}
+	}
+	var document bytes.Buffer
+	enc := yaml.NewEncoder(&document)
+	enc.SetIndent(2)
+	if err := enc.Encode(newObject.Object); err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s: error encoding object: %v", source, err))
+		return false
+	}
+	fmt.Fprintf(out, "---\n# %s, namespace: %s\n%s", source, ns, document.String())
+	return true
+}
+
+// dryRun sends a server-side dry-run create request. The namespaces of the job don't exist yet, hence namespaced objects
+// without their own namespace are validated in the default namespace
+func (r *renderer) dryRun(obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+	mapping, err := r.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return err
+	}
+	createOptions := metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}, FieldValidation: r.ex.fieldValidation}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		_, err = r.dynamicClient.Resource(mapping.Resource).Create(context.TODO(), obj, createOptions)
+		return err
+	}
+	ns := obj.GetNamespace()
+	if ns == "" {
+		ns = metav1.NamespaceDefault
+	}
+	_, err = r.dynamicClient.Resource(mapping.Resource).Namespace(ns).Create(context.TODO(), obj, createOptions)
+	return err
+}
+
+// sampleIterations returns the given number of iterations, evenly spaced and including the first one, or all of them when sample is 0
+func sampleIterations(jobIterations, sample int) []int {
+	if sample <= 0 || sample > jobIterations {
+		sample = jobIterations
+	}
+	iterations := make([]int, sample)
+	for i := range iterations {
+		iterations[i] = i * jobIterations / sample
+	}
+	return iterations
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	"github.com/prometheus/common/model"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// jobStatus outcome of a job, evaluated by the jobs depending on it
+type jobStatus int
+
+const (
+	jobSucceeded jobStatus = iota
+	jobFailed
+	jobSkipped
+)
+
+// gcScoped reports whether the job is garbage collected according to its gcPolicy, given its outcome when it has
+// one. Jobs without outcome are only garbage collected with the always policy
+func (ex *JobExecutor) gcScoped(status jobStatus, hasStatus bool) bool {
+	switch ex.GCPolicy {
+	case config.GCOnSuccess:
+		return hasStatus && status == jobSucceeded
+	case config.GCOnFailure:
+		return hasStatus && status == jobFailed
+	}
+	return true
+}
+
+// skipReason returns why the job must be skipped, or an empty string when it can run
+func (ex *JobExecutor) skipReason(jobStatuses map[string]jobStatus, prometheusClients []*prometheus.Prometheus) string {
+	for _, dependency := range ex.DependsOn {
+		switch jobStatuses[dependency] {
+		case jobFailed:
+			return fmt.Sprintf("dependency %s failed", dependency)
+		case jobSkipped:
+			return fmt.Sprintf("dependency %s was skipped", dependency)
+		}
+	}
+	if ex.RunIf == nil {
+		return ""
+	}
+	if len(prometheusClients) == 0 {
+		return "no Prometheus endpoint configured to evaluate runIf"
+	}
+	met, err := evaluateCondition(prometheusClients[0], ex.RunIf.Expr)
+	if err != nil {
+		return fmt.Sprintf("error evaluating runIf expression: %v", err)
+	}
+	if !met {
+		return fmt.Sprintf("runIf expression %q not met", ex.RunIf.Expr)
+	}
+	return ""
+}
+
+// waitForGate blocks until the waitFor expression of the job is met continuously for stableFor,
+// it returns the time spent waiting
+func (ex *JobExecutor) waitForGate(ctx context.Context, prometheusClients []*prometheus.Prometheus) (time.Duration, error) {
+	if ex.WaitFor == nil {
+		return 0, nil
+	}
+	if len(prometheusClients) == 0 {
+		return 0, fmt.Errorf("no Prometheus endpoint configured to evaluate waitFor")
+	}
+	log.Infof("Job %s: waiting for %q up to %v", ex.Name, ex.WaitFor.Expr, ex.WaitFor.Timeout)
+	start := time.Now()
+	var metSince time.Time
+	ctx, cancel := context.WithTimeout(ctx, ex.WaitFor.Timeout)
+	defer cancel()
+	err := wait.PollUntilContextCancel(ctx, ex.WaitFor.Interval, true, func(ctx context.Context) (bool, error) {
+		met, err := evaluateCondition(prometheusClients[0], ex.WaitFor.Expr)
+		if err != nil {
+			log.Warnf("Error evaluating waitFor expression: %v", err)
+		}
+		if !met {
+			metSince = time.Time{}
+			return false, nil
+		}
+		if metSince.IsZero() {
+			metSince = time.Now()
+		}
+		return time.Since(metSince) >= ex.WaitFor.StableFor, nil
+	})
+	waited := time.Since(start)
+	if err != nil {
+		return waited, fmt.Errorf("waitFor expression %q not met after %v", ex.WaitFor.Expr, waited.Round(time.Second))
+	}
+	log.Infof("Job %s: waitFor expression met after %v", ex.Name, waited.Round(time.Second))
+	return waited, nil
+}
+
+// evaluateCondition returns true when the instant query returns any non-zero value
+func evaluateCondition(p *prometheus.Prometheus, expr string) (bool, error) {
+	v, err := p.Client.Query(expr, time.Now().UTC())
+	if err != nil {
+		return false, err
+	}
+	switch result := v.(type) {
+	case model.Vector:
+		for _, sample := range result {
+			if sample.Value != 0 && !math.IsNaN(float64(sample.Value)) {
+				return true, nil
+			}
+		}
+	case *model.Scalar:
+		return result.Value != 0 && !math.IsNaN(float64(result.Value)), nil
+	default:
+		return false, fmt.Errorf("unexpected result type %s", v.Type())
+	}
+	return false, nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/metrics"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+)
+
+// prepareSchedulerCache brings kube-scheduler to the state of the phase run by the job: the cold phase restarts it and
+// waits for it to lead again, returning the time it took, and the warm phase lets it settle once the cold phase finished
+func (ex *JobExecutor) prepareSchedulerCache(ctx context.Context) (time.Duration, error) {
+	if ex.SchedulerCache == nil {
+		return 0, nil
+	}
+	if ex.SchedulerCache.Phase == config.SchedulerCacheWarm {
+		log.Infof("Job %s: letting kube-scheduler warm up for %v", ex.Name, ex.SchedulerCache.WarmUp)
+		select {
+		case <-time.After(ex.SchedulerCache.WarmUp):
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+		return 0, nil
+	}
+	return ex.restartScheduler(ctx)
+}
+
+// restartScheduler restarts kube-scheduler and waits for a new instance to acquire its leader election lease, so that
+// the job doesn't start before the scheduler, and its cache, are back
+func (ex *JobExecutor) restartScheduler(ctx context.Context) (time.Duration, error) {
+	sc := ex.SchedulerCache
+	leases := ex.clientSet.CoordinationV1().Leases(sc.Namespace)
+	lease, err := leases.Get(ctx, sc.Lease, metav1.GetOptions{})
+	if err != nil {
+		return 0, fmt.Errorf("error getting kube-scheduler lease %s/%s: %v", sc.Namespace, sc.Lease, err)
+	}
+	holder := ptr.Deref(lease.Spec.HolderIdentity, "")
+	start := time.Now()
+	if len(sc.RestartCommand) > 0 {
+		log.Infof("Job %s: restarting kube-scheduler: %s", ex.Name, strings.Join(sc.RestartCommand, " "))
+		out, err := exec.CommandContext(ctx, sc.RestartCommand[0], sc.RestartCommand[1:]...).CombinedOutput()
+		if err != nil {
+			return 0, fmt.Errorf("kube-scheduler restart command failed: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+	} else {
+		pods, err := ex.clientSet.CoreV1().Pods(sc.Namespace).List(ctx, metav1.ListOptions{LabelSelector: sc.LabelSelector})
+		if err != nil {
+			return 0, fmt.Errorf("error listing kube-scheduler pods: %v", err)
+		}
+		if len(pods.Items) == 0 {
+			return 0, fmt.Errorf("no kube-scheduler pods match %s in namespace %s", sc.LabelSelector, sc.Namespace)
+		}
+		log.Infof("Job %s: restarting kube-scheduler, deleting %d pods", ex.Name, len(pods.Items))
+		for _, pod := range pods.Items {
+			if err := ex.clientSet.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil {
+				return 0, fmt.Errorf("error deleting kube-scheduler pod %s: %v", pod.Name, err)
+			}
+		}
+	}
+	// Each kube-scheduler instance has its own identity, a new holder means the restarted scheduler is leading
+	err = wait.PollUntilContextTimeout(ctx, time.Second, sc.RestartTimeout, true, func(ctx context.Context) (bool, error) {
+		lease, err := leases.Get(ctx, sc.Lease, metav1.GetOptions{})
+		if err != nil {
+			log.Debugf("Error getting kube-scheduler lease: %v", err)
+			return false, nil
+		}
+		current := ptr.Deref(lease.Spec.HolderIdentity, "")
+		return current != "" && current != holder, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("kube-scheduler didn't acquire lease %s/%s again within %v, static pods are only restarted by restartCommand", sc.Namespace, sc.Lease, sc.RestartTimeout)
+	}
+	restartLatency := time.Since(start)
+	log.Infof("Job %s: kube-scheduler restarted in %v", ex.Name, restartLatency.Round(time.Millisecond))
+	return restartLatency, nil
+}
+
+// schedulerCache measures the scheduling latencies of the phase run by the job. The cold phase stats are kept in
+// colds until the warm phase of the same job finishes, which returns the comparison of both
+func (ex *JobExecutor) schedulerCache(ctx context.Context, restartLatency time.Duration, colds map[string]prometheus.SchedulerPhaseStats) *prometheus.SchedulerCache {
+	if ex.SchedulerCache == nil {
+		return nil
+	}
+	stats := ex.schedulerPhaseStats(ctx)
+	if ex.SchedulerCache.Phase == config.SchedulerCacheCold {
+		stats.RestartLatency = restartLatency.Milliseconds()
+		colds[ex.SchedulerCache.Job] = stats
+		return nil
+	}
+	cold, ok := colds[ex.SchedulerCache.Job]
+	if !ok {
+		log.Warnf("Job %s: the cold phase didn't run, scheduler cache deltas can't be computed", ex.Name)
+		return nil
+	}
+	schedulerCache := &prometheus.SchedulerCache{
+		Cold:              cold,
+		Warm:              stats,
+		ScheduledP50Delta: cold.ScheduledP50 - stats.ScheduledP50,
+		ScheduledP99Delta: cold.ScheduledP99 - stats.ScheduledP99,
+		ScheduledAvgDelta: cold.ScheduledAvg - stats.ScheduledAvg,
+		ScheduledMaxDelta: cold.ScheduledMax - stats.ScheduledMax,
+	}
+	log.Infof("Job %s: cold scheduler cache penalty: scheduling P50 %+dms P99 %+dms avg %+dms max %+dms",
+		ex.SchedulerCache.Job, schedulerCache.ScheduledP50Delta, schedulerCache.ScheduledP99Delta, schedulerCache.ScheduledAvgDelta, schedulerCache.ScheduledMaxDelta)
+	return schedulerCache
+}
+
+// schedulerPhaseStats computes the scheduling latencies of the pods created by the job, since their creation
+func (ex *JobExecutor) schedulerPhaseStats(ctx context.Context) prometheus.SchedulerPhaseStats {
+	var stats prometheus.SchedulerPhaseStats
+	labelSelector := fmt.Sprintf("kube-burner-runid=%s,kube-burner-job=%s", ex.runid, ex.Name)
+	pods, err := ex.clientSet.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: labelSelector})
+	if err != nil {
+		log.Errorf("Job %s: error listing pods: %v", ex.Name, err)
+		return stats
+	}
+	var latencies []float64
+	for _, pod := range pods.Items {
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionTrue {
+				latencies = append(latencies, float64(c.LastTransitionTime.Sub(pod.CreationTimestamp.Time).Milliseconds()))
+			}
+		}
+	}
+	stats.Pods = len(latencies)
+	if len(latencies) > 0 {
+		summary := metrics.NewLatencySummary(latencies, "Scheduled")
+		stats.ScheduledP50, stats.ScheduledP99 = int64(summary.P50), int64(summary.P99)
+		stats.ScheduledAvg, stats.ScheduledMax = int64(summary.Avg), int64(summary.Max)
+	}
+	return stats
+}
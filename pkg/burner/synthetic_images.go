@@ -0,0 +1,104 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"archive/tar"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Path of the random data within the synthetic image layer
+const syntheticImageFile = "synthetic/data"
+
+// handleSyntheticImages pushes the synthetic images of the jobs before running them
+func handleSyntheticImages(executorList []JobExecutor) {
+	for _, executor := range executorList {
+		if executor.SyntheticImages != nil && executor.JobType == config.CreationJob {
+			if err := pushSyntheticImages(*executor.SyntheticImages); err != nil {
+				log.Fatalf("Job %s: %v", executor.Name, err)
+			}
+		}
+	}
+}
+
+// pushSyntheticImages pushes the images tagged from 0 to count-1, each one made of a layer of random data
+func pushSyntheticImages(images config.SyntheticImages) error {
+	log.Infof("Pushing %d synthetic images to %s", images.Count, images.Repository)
+	start := time.Now()
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var pushErr error
+	sem := make(chan struct{}, images.Parallelism)
+	for i := range images.Count {
+		// Sizes are validated while parsing the configuration
+		size := resource.MustParse(images.Sizes[i%len(images.Sizes)])
+		reference := fmt.Sprintf("%s:%d", images.Repository, i)
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			log.Debugf("Pushing synthetic image %s of %v", reference, size.String())
+			if err := config.PushOCIImage(reference, []config.OCILayer{syntheticLayer(uint64(i), size.Value())}); err != nil {
+				errOnce.Do(func() { pushErr = fmt.Errorf("error pushing synthetic image %s: %v", reference, err) })
+			}
+		}()
+	}
+	wg.Wait()
+	if pushErr != nil {
+		return pushErr
+	}
+	log.Infof("Synthetic images pushed in %v", time.Since(start).Round(time.Second))
+	return nil
+}
+
+// syntheticLayer returns a tar layer holding size bytes of random data, seeded by the image index so that
+// each image is distinct and its content is the same on every run
+func syntheticLayer(seed uint64, size int64) config.OCILayer {
+	// Header and content padded to blocks of 512 bytes, followed by two empty blocks
+	tarSize := 512 + (size+511)/512*512 + 1024
+	return func() (io.Reader, int64) {
+		pr, pw := io.Pipe()
+		go func() {
+			var key [32]byte
+			binary.LittleEndian.PutUint64(key[:], seed)
+			tw := tar.NewWriter(pw)
+			err := tw.WriteHeader(&tar.Header{
+				Name:     syntheticImageFile,
+				Mode:     0644,
+				Size:     size,
+				Typeflag: tar.TypeReg,
+				Format:   tar.FormatUSTAR,
+			})
+			if err == nil {
+				_, err = io.CopyN(tw, rand.NewChaCha8(key), size)
+			}
+			if err == nil {
+				err = tw.Close()
+			}
+			pw.CloseWithError(err)
+		}()
+		return pr, tarSize
+	}
+}
@@ -15,10 +15,13 @@
 package burner
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"maps"
 	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -50,6 +53,8 @@ var (
 		ReplicaSet:     {commonUnderlyingObjectLabelsPath},
 		StatefulSet:    {commonUnderlyingObjectLabelsPath, []string{"spec", "selector", "matchLabels"}},
 		VirtualMachine: {commonUnderlyingObjectLabelsPath},
+		// Claims generated from the template inherit these labels
+		ResourceClaimTemplate: {{"spec", "metadata", "labels"}},
 	}
 
 	kindToLabelPathsInArray = map[string][][][]string{
@@ -103,12 +108,25 @@ func setMetadataLabels(obj *unstructured.Unstructured, labels map[string]string)
 	}
 }
 
-func yamlToUnstructured(fileName string, y []byte, uns *unstructured.Unstructured) (runtime.Object, *schema.GroupVersionKind) {
+func yamlToUnstructured(fileName string, y []byte, uns *unstructured.Unstructured) (runtime.Object, *schema.GroupVersionKind, error) {
 	o, gvk, err := scheme.Codecs.UniversalDeserializer().Decode(y, nil, uns)
 	if err != nil {
-		log.Fatalf("Error decoding YAML (%s): %s", fileName, err)
+		return nil, nil, fmt.Errorf("error decoding YAML (%s): %s", fileName, err)
 	}
-	return o, gvk
+	return o, gvk, nil
+}
+
+// newListOptions returns the options of the first LIST request, following the list options of the job.
+// The page size defaults to the given limit
+func (ex *JobExecutor) newListOptions(labelSelector string, defaultLimit int64) metav1.ListOptions {
+	listOptions := metav1.ListOptions{
+		LabelSelector: labelSelector,
+		Limit:         cmp.Or(ex.ListOptions.Limit, defaultLimit),
+	}
+	if ex.ListOptions.ResourceVersion == config.ResourceVersionAny {
+		listOptions.ResourceVersion = "0"
+	}
+	return listOptions
 }
 
 // Verify verifies the number of created objects
@@ -118,12 +136,10 @@ func (ex *JobExecutor) Verify() bool {
 	success := true
 	log.Info("Verifying created objects")
 	for objectIndex, obj := range ex.objects {
-		listOptions := metav1.ListOptions{
-			LabelSelector: fmt.Sprintf("kube-burner-uuid=%s,kube-burner-runid=%s,kube-burner-job=%s,kube-burner-index=%d", ex.uuid, ex.runid, ex.Name, objectIndex),
-			Limit:         objectLimit,
-		}
+		labelSelector := fmt.Sprintf("kube-burner-uuid=%s,kube-burner-runid=%s,kube-burner-job=%s,kube-burner-index=%d", ex.uuid, ex.runid, ex.Name, objectIndex)
 		err := util.RetryWithExponentialBackOff(func() (done bool, err error) {
 			replicas = 0
+			listOptions := ex.newListOptions(labelSelector, objectLimit)
 			for {
 				objList, err = ex.dynamicClient.Resource(obj.gvr).Namespace(metav1.NamespaceAll).List(context.TODO(), listOptions)
 				if err != nil {
@@ -136,6 +152,8 @@ func (ex *JobExecutor) Verify() bool {
 				if listOptions.Continue == "" {
 					break
 				}
+				// resourceVersion can't be set along with a continue token
+				listOptions.ResourceVersion = ""
 			}
 			return true, nil
 		}, 1*time.Second, 3, 0, 1*time.Minute)
@@ -146,9 +164,12 @@ func (ex *JobExecutor) Verify() bool {
 		}
 		var objectsExpected int
 		if obj.RunOnce {
-			objectsExpected = obj.Replicas
+			// RunOnce objects are only created by the worker running the first iteration
+			if ex.iterationStart == 0 {
+				objectsExpected = obj.Replicas
+			}
 		} else {
-			objectsExpected = obj.Replicas * ex.JobIterations
+			objectsExpected = obj.Replicas * (ex.iterationEnd - ex.iterationStart)
 		}
 		if replicas != objectsExpected {
 			log.Errorf("%s found: %d Expected: %d", obj.gvr.Resource, replicas, objectsExpected)
@@ -182,6 +203,22 @@ func newRESTMapper(discoveryClient *discovery.DiscoveryClient) meta.RESTMapper {
 }
 
 func (ex *JobExecutor) Run(ctx context.Context) {
+	if ex.JobType == config.HelmJob {
+		ex.runHelmJob(ctx)
+		return
+	}
+	if ex.JobType == config.FioJob {
+		ex.runFioJob(ctx)
+		return
+	}
+	if ex.JobType == config.NetworkJob {
+		ex.runNetworkJob(ctx)
+		return
+	}
+	if ex.custom != nil {
+		ex.runCustomJob(ctx)
+		return
+	}
 	switch ex.ExecutionMode {
 	case config.ExecutionModeParallel:
 		ex.runParallel(ctx)
@@ -190,21 +227,88 @@ func (ex *JobExecutor) Run(ctx context.Context) {
 	}
 }
 
+// runIterations runs an iteration in the namespace of each iteration, in parallel or one after the other depending on
+// the execution mode, throttled by the job rate limiter. Namespaces are created and labeled by kube-burner when requested,
+// so that the objects created within them are garbage collected along with them
+func (ex *JobExecutor) runIterations(ctx context.Context, createNamespaces bool, run func(ctx context.Context, iteration int, ns string)) {
+	nsLabels := map[string]string{
+		"kube-burner-job":   ex.Name,
+		"kube-burner-uuid":  ex.uuid,
+		"kube-burner-runid": ex.runid,
+	}
+	maps.Copy(nsLabels, ex.NamespaceLabels)
+	namespacesCreated := make(map[string]bool)
+	var wg sync.WaitGroup
+	for i := ex.iterationStart; i < ex.iterationEnd; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		ns := ex.namespaceName(ex.Namespace)
+		if ex.NamespacedIterations {
+			ns = ex.generateNamespace(i)
+		}
+		if createNamespaces && !namespacesCreated[ns] {
+			if err := util.CreateNamespace(ex.clientSet, ns, nsLabels, ex.NamespaceAnnotations); err != nil {
+				log.Error(err.Error())
+				ex.recordError()
+				continue
+			}
+			namespacesCreated[ns] = true
+		}
+		if ex.limiter.Wait(ctx) != nil {
+			break
+		}
+		if ex.ExecutionMode == config.ExecutionModeSequential {
+			run(ctx, i, ns)
+			continue
+		}
+		wg.Add(1)
+		go func(iteration int, ns string) {
+			defer wg.Done()
+			run(ctx, iteration, ns)
+		}(i, ns)
+	}
+	wg.Wait()
+}
+
 func (ex *JobExecutor) getItemListForObject(obj *object) (*unstructured.UnstructuredList, error) {
-	var itemList *unstructured.UnstructuredList
+	itemList := &unstructured.UnstructuredList{}
 	labelSelector := labels.Set(obj.LabelSelector).String()
-	listOptions := metav1.ListOptions{
-		LabelSelector: labelSelector,
+	// The options of the object take precedence over the job ones
+	listOptions := ex.newListOptions(labelSelector, obj.ListLimit)
+	if obj.ListLimit != 0 {
+		listOptions.Limit = obj.ListLimit
+	}
+	if obj.ResourceVersion != "" {
+		listOptions.ResourceVersion = obj.ResourceVersion
 	}
+	firstResourceVersion := listOptions.ResourceVersion
 
 	// Try to find the list of resources by GroupVersionResource.
 	err := util.RetryWithExponentialBackOff(func() (done bool, err error) {
-		itemList, err = ex.dynamicClient.Resource(obj.gvr).List(context.TODO(), listOptions)
-		if err != nil {
-			log.Errorf("Error found listing %s labeled with %s: %s", obj.gvr.Resource, labelSelector, err)
-			return false, nil
+		itemList.Items = nil
+		listOptions.Continue = ""
+		for {
+			// List requests are part of the load generated by read jobs
+			if ex.JobType == config.ReadJob {
+				ex.limiter.Wait(context.TODO())
+				atomic.AddInt32(&ex.objectOperations, 1)
+			}
+			page, err := ex.dynamicClient.Resource(obj.gvr).List(context.TODO(), listOptions)
+			if err != nil {
+				log.Errorf("Error found listing %s labeled with %s: %s", obj.gvr.Resource, labelSelector, err)
+				return false, nil
+			}
+			itemList.Items = append(itemList.Items, page.Items...)
+			listOptions.Continue = page.GetContinue()
+			if listOptions.Continue == "" {
+				break
+			}
+			// resourceVersion can't be set along with a continue token
+			listOptions.ResourceVersion = ""
 		}
-		log.Infof("Found %d %s with selector %s; patching them", len(itemList.Items), obj.gvr.Resource, labelSelector)
+		listOptions.ResourceVersion = firstResourceVersion
+		log.Infof("Found %d %s with selector %s", len(itemList.Items), obj.gvr.Resource, labelSelector)
 		return true, nil
 	}, 1*time.Second, 3, 0, ex.MaxWaitTimeout)
 	if err != nil {
@@ -286,3 +390,12 @@ func (ex *JobExecutor) runParallel(ctx context.Context) {
 	wg.Wait()
 	ex.waitForObjects("")
 }
+
+// fieldValidation returns the field validation directive of the requests creating and patching objects, in strict mode
+// the requests of objects with unknown or duplicated fields fail, otherwise the API server drops them with a warning
+func fieldValidation(strict bool) string {
+	if strict {
+		return metav1.FieldValidationStrict
+	}
+	return metav1.FieldValidationWarn
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"sync"
+	"sync/atomic"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// waiterStats API overhead of the object waiters
+type waiterStats struct {
+	listRequests atomic.Int64
+	watchEvents  atomic.Int64
+}
+
+// waiterCache keeps a watch-fed cache per resource, shared by all the waiters of a job.
+// Caches are scoped to the objects labeled with the run id and started on demand
+type waiterCache struct {
+	mutex         sync.Mutex
+	dynamicClient dynamic.Interface
+	runid         string
+	noBookmarks   bool
+	stats         *waiterStats
+	factory       dynamicinformer.DynamicSharedInformerFactory
+	stopCh        chan struct{}
+	informers     map[schema.GroupVersionResource]cache.SharedIndexInformer
+}
+
+func newWaiterCache(dynamicClient dynamic.Interface, runid string, noBookmarks bool, stats *waiterStats) *waiterCache {
+	return &waiterCache{
+		dynamicClient: dynamicClient,
+		runid:         runid,
+		noBookmarks:   noBookmarks,
+		stats:         stats,
+	}
+}
+
+// informer returns the informer of the given resource, starting it when required
+func (wc *waiterCache) informer(gvr schema.GroupVersionResource) cache.SharedIndexInformer {
+	wc.mutex.Lock()
+	defer wc.mutex.Unlock()
+	if wc.factory == nil {
+		wc.stopCh = make(chan struct{})
+		wc.informers = make(map[schema.GroupVersionResource]cache.SharedIndexInformer)
+		wc.factory = dynamicinformer.NewFilteredDynamicSharedInformerFactory(wc.dynamicClient, 0, metav1.NamespaceAll, func(lo *metav1.ListOptions) {
+			lo.LabelSelector = labels.Set{"kube-burner-runid": wc.runid}.String()
+			// Reflectors always request bookmarks
+			if wc.noBookmarks {
+				lo.AllowWatchBookmarks = false
+			}
+		})
+	}
+	if informer, exists := wc.informers[gvr]; exists {
+		return informer
+	}
+	log.Debugf("Starting waiter cache for %s", gvr.Resource)
+	informer := wc.factory.ForResource(gvr).Informer()
+	countEvent := func() { wc.stats.watchEvents.Add(1) }
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(any) { countEvent() },
+		UpdateFunc: func(any, any) { countEvent() },
+		DeleteFunc: func(any) { countEvent() },
+	})
+	// The initial list of the informer
+	wc.stats.listRequests.Add(1)
+	wc.informers[gvr] = informer
+	wc.factory.Start(wc.stopCh)
+	return informer
+}
+
+// list returns the cached objects matching the namespace and label selector, synced is false until the initial list completes
+func (wc *waiterCache) list(gvr schema.GroupVersionResource, ns, labelSelector string) (items []unstructured.Unstructured, synced bool, err error) {
+	informer := wc.informer(gvr)
+	if !informer.HasSynced() {
+		return nil, false, nil
+	}
+	selector, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, true, err
+	}
+	err = cache.ListAllByNamespace(informer.GetIndexer(), ns, selector, func(obj any) {
+		if u, ok := obj.(*unstructured.Unstructured); ok {
+			items = append(items, *u)
+		}
+	})
+	return items, true, err
+}
+
+// stop stops the watches, the caches are started again when needed
+func (wc *waiterCache) stop() {
+	if wc == nil {
+		return
+	}
+	wc.mutex.Lock()
+	defer wc.mutex.Unlock()
+	if wc.factory == nil {
+		return
+	}
+	close(wc.stopCh)
+	wc.factory.Shutdown()
+	wc.factory = nil
+	wc.informers = nil
+}
@@ -29,7 +29,10 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
+	kubevirtV1 "kubevirt.io/api/core/v1"
 
 	"github.com/kube-burner/kube-burner/pkg/burner/types"
 	"github.com/kube-burner/kube-burner/pkg/config"
@@ -42,11 +45,6 @@ var (
 			conditionCheckParams: []ConditionCheckParam{conditionCheckParamStatusTrue},
 			timeGreaterThan:      false,
 		},
-		VirtualMachine: {
-			conditionType:        conditionTypeReady,
-			conditionCheckParams: []ConditionCheckParam{conditionCheckParamStatusTrue},
-			timeGreaterThan:      false,
-		},
 		VirtualMachineInstance: {
 			conditionType:        conditionTypeReady,
 			conditionCheckParams: []ConditionCheckParam{conditionCheckParamStatusTrue},
@@ -102,6 +100,8 @@ func (ex *JobExecutor) waitForObject(ns string, obj *object) {
 		if waiterConditionPath, ok := waitersConditionPaths[kind]; ok {
 			obj.WaitOptions.CustomStatusPaths = waiterConditionPath.toStatusPaths(0)
 			err = ex.verifyCondition(ns, obj, labelSelectorString)
+		} else if waiter, ok := registeredObjectWaiter(kind); ok {
+			err = ex.waitForRegisteredKind(ns, obj, labelSelectorString, waiter)
 		} else {
 			switch kind {
 			case Deployment, ReplicaSet, ReplicationController, StatefulSet, DaemonSet, VirtualMachineInstanceReplicaSet:
@@ -112,8 +112,14 @@ func (ex *JobExecutor) waitForObject(ns string, obj *object) {
 				err = ex.waitForBuild(ns, obj, labelSelectorString)
 			case PersistentVolumeClaim:
 				err = ex.waitForPVC(ns, labelSelectorString)
+			case VirtualMachine:
+				err = ex.waitForVirtualMachine(ns, obj, labelSelectorString)
+			case ResourceClaim:
+				err = ex.waitForResourceClaim(ns, obj, labelSelectorString)
 			case VolumeSnapshot:
 				err = ex.waitForVolumeSnapshot(ns, obj, labelSelectorString)
+			case BareMetalHost:
+				err = ex.waitForBareMetalHost(ns, obj, labelSelectorString)
 			}
 		}
 	}
@@ -129,122 +135,205 @@ func (ex *JobExecutor) waitForObject(ns string, obj *object) {
 	}
 }
 
-func (ex *JobExecutor) waitForReplicas(ns string, obj *object, waitPath statusPath, labelSelector string) error {
+// waitForItems waits until all the objects of the resource matching the label selector are ready. Depending on the waiter mode,
+// the objects are either listed from the API periodically or read from a watch-fed cache
+func (ex *JobExecutor) waitForItems(ns string, gvr schema.GroupVersionResource, labelSelector string, minItems int, ready func(item *unstructured.Unstructured) (bool, error)) error {
 	err := wait.PollUntilContextTimeout(context.TODO(), time.Second, ex.MaxWaitTimeout, true, func(ctx context.Context) (done bool, err error) {
-		ex.waitLimiter.Wait(context.TODO())
-		resources, err := ex.dynamicClient.Resource(obj.gvr).Namespace(ns).List(context.TODO(), metav1.ListOptions{
-			LabelSelector: labelSelector,
-		})
+		var items []unstructured.Unstructured
+		if ex.waiterCache != nil {
+			var synced bool
+			items, synced, err = ex.waiterCache.list(gvr, ns, labelSelector)
+			if !synced {
+				log.Debugf("Waiting for %s cache to sync", gvr.Resource)
+				return false, nil
+			}
+		} else {
+			items, err = ex.listItems(ns, gvr, labelSelector)
+		}
 		if err != nil {
-			log.Errorf("Error listing %s in %s: %v", obj.Kind, ns, err)
+			if ns != "" {
+				log.Errorf("Error listing %s in %s: %v", gvr.Resource, ns, err)
+			} else {
+				log.Errorf("Error listing %s: %v", gvr.Resource, err)
+			}
 			return false, nil
 		}
-		for _, resource := range resources.Items {
-			replicas, _, err := unstructured.NestedFieldCopy(resource.Object, waitPath.expectedReplicasPath...)
-			if err != nil {
-				return false, err
-			}
-			readyReplicas, _, err := unstructured.NestedFieldCopy(resource.Object, waitPath.readyReplicasPath...)
-			if err != nil {
+		if len(items) < minItems {
+			log.Debugf("Waiting for %s in ns %s to be created", gvr.Resource, ns)
+			return false, nil
+		}
+		for i := range items {
+			if isReady, err := ready(&items[i]); !isReady || err != nil {
 				return false, err
 			}
-			if replicas != readyReplicas {
-				log.Debugf("Waiting for replicas from %s in ns %s to be ready", obj.Kind, ns)
-				return false, nil
-			}
 		}
 		return true, nil
 	})
 	return err
 }
 
-func (ex *JobExecutor) waitForPVC(ns string, labelSelector string) error {
-	err := wait.PollUntilContextTimeout(context.TODO(), time.Second, ex.MaxWaitTimeout, true, func(ctx context.Context) (done bool, err error) {
-		ex.limiter.Wait(context.TODO())
-		pvcs, err := ex.clientSet.CoreV1().PersistentVolumeClaims(ns).List(context.TODO(), metav1.ListOptions{
-			LabelSelector: labelSelector,
-		})
+// listItems lists all the objects matching the label selector, paginating the requests to ensure we don't miss any object
+func (ex *JobExecutor) listItems(ns string, gvr schema.GroupVersionResource, labelSelector string) ([]unstructured.Unstructured, error) {
+	var items []unstructured.Unstructured
+	listOptions := ex.newListOptions(labelSelector, 1000)
+	for {
+		ex.waitLimiter.Wait(context.TODO())
+		ex.waiterStats.listRequests.Add(1)
+		objs, err := ex.dynamicClient.Resource(gvr).Namespace(ns).List(context.TODO(), listOptions)
 		if err != nil {
-			log.Errorf("Error listing PVCs in %s: %v", ns, err)
+			return nil, err
+		}
+		items = append(items, objs.Items...)
+		listOptions.Continue = objs.GetContinue()
+		if listOptions.Continue == "" {
+			return items, nil
+		}
+		// resourceVersion can't be set along with a continue token
+		listOptions.ResourceVersion = ""
+	}
+}
+
+func (ex *JobExecutor) waitForReplicas(ns string, obj *object, waitPath statusPath, labelSelector string) error {
+	return ex.waitForItems(ns, obj.gvr, labelSelector, 0, func(resource *unstructured.Unstructured) (bool, error) {
+		replicas, _, err := unstructured.NestedFieldCopy(resource.Object, waitPath.expectedReplicasPath...)
+		if err != nil {
+			return false, err
+		}
+		readyReplicas, _, err := unstructured.NestedFieldCopy(resource.Object, waitPath.readyReplicasPath...)
+		if err != nil {
+			return false, err
+		}
+		if replicas != readyReplicas {
+			log.Debugf("Waiting for replicas from %s in ns %s to be ready", obj.Kind, ns)
 			return false, nil
 		}
-		for _, pvc := range pvcs.Items {
-			if pvc.Status.Phase != corev1.ClaimBound {
-				log.Debugf("Waiting for pvcs in ns %s to be Bound", ns)
+		return true, nil
+	})
+}
+
+func (ex *JobExecutor) waitForPVC(ns string, labelSelector string) error {
+	return ex.waitForItems(ns, corev1.SchemeGroupVersion.WithResource("persistentvolumeclaims"), labelSelector, 0, func(item *unstructured.Unstructured) (bool, error) {
+		var pvc corev1.PersistentVolumeClaim
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &pvc); err != nil {
+			return false, err
+		}
+		if pvc.Status.Phase != corev1.ClaimBound {
+			log.Debugf("Waiting for pvcs in ns %s to be Bound", ns)
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+// waitForVirtualMachine waits for the VirtualMachines to be ready, or to be stopped when they're not expected to start
+func (ex *JobExecutor) waitForVirtualMachine(ns string, obj *object, labelSelector string) error {
+	gvr := obj.gvr
+	if obj.waitGVR != nil {
+		gvr = *obj.waitGVR
+	}
+	return ex.waitForItems(ns, gvr, labelSelector, 0, func(item *unstructured.Unstructured) (bool, error) {
+		var vm kubevirtV1.VirtualMachine
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &vm); err != nil {
+			return false, err
+		}
+		halted := vm.Spec.Running != nil && !*vm.Spec.Running
+		if vm.Spec.RunStrategy != nil {
+			halted = *vm.Spec.RunStrategy == kubevirtV1.RunStrategyHalted || *vm.Spec.RunStrategy == kubevirtV1.RunStrategyManual
+		}
+		if halted {
+			if vm.Status.PrintableStatus != kubevirtV1.VirtualMachineStatusStopped {
+				log.Debugf("Waiting for VirtualMachines in ns %s to be stopped", ns)
 				return false, nil
 			}
+			return true, nil
+		}
+		for _, c := range vm.Status.Conditions {
+			if c.Type == kubevirtV1.VirtualMachineReady && c.Status == corev1.ConditionTrue {
+				return true, nil
+			}
+		}
+		log.Debugf("Waiting for VirtualMachines in ns %s to be ready", ns)
+		return false, nil
+	})
+}
+
+// waitForResourceClaim waits for the claims to be allocated, what happens once the pods consuming them are scheduled
+func (ex *JobExecutor) waitForResourceClaim(ns string, obj *object, labelSelector string) error {
+	return ex.waitForItems(ns, obj.gvr, labelSelector, 0, func(claim *unstructured.Unstructured) (bool, error) {
+		if _, found, _ := unstructured.NestedMap(claim.Object, "status", "allocation"); !found {
+			log.Debugf("Waiting for ResourceClaims in ns %s to be allocated", ns)
+			return false, nil
+		}
+		return true, nil
+	})
+}
+
+// waitForRegisteredKind waits for the objects with the waiter registered for their kind
+func (ex *JobExecutor) waitForRegisteredKind(ns string, obj *object, labelSelector string, waiter ObjectWaiter) error {
+	gvr := obj.gvr
+	if obj.waitGVR != nil {
+		gvr = *obj.waitGVR
+	}
+	if !obj.namespaced {
+		ns = metav1.NamespaceAll
+	}
+	return ex.waitForItems(ns, gvr, labelSelector, 0, waiter)
+}
+
+// waitForBareMetalHost waits for the hosts with an image to be provisioned, and for the rest of them to be available
+func (ex *JobExecutor) waitForBareMetalHost(ns string, obj *object, labelSelector string) error {
+	return ex.waitForItems(ns, obj.gvr, labelSelector, 0, func(host *unstructured.Unstructured) (bool, error) {
+		state, _, _ := unstructured.NestedString(host.Object, "status", "provisioning", "state")
+		if errorType, _, _ := unstructured.NestedString(host.Object, "status", "errorType"); errorType != "" {
+			log.Debugf("BareMetalHost %s/%s in %s: %s", host.GetNamespace(), host.GetName(), state, errorType)
+		}
+		expectedState := "available"
+		if _, found, _ := unstructured.NestedMap(host.Object, "spec", "image"); found {
+			expectedState = "provisioned"
+		}
+		if state != expectedState && state != "externally provisioned" {
+			log.Debugf("Waiting for BareMetalHosts in ns %s to be %s", ns, expectedState)
+			return false, nil
 		}
 		return true, nil
 	})
-	return err
 }
 
 func (ex *JobExecutor) waitForPod(ns string, labelSelector string) error {
-	err := wait.PollUntilContextTimeout(context.TODO(), time.Second, ex.MaxWaitTimeout, true, func(ctx context.Context) (done bool, err error) {
-		// We need to paginate these requests to ensure we don't miss any pods
-		listOptions := metav1.ListOptions{
-			Limit:         1000,
-			LabelSelector: labelSelector,
+	return ex.waitForItems(ns, corev1.SchemeGroupVersion.WithResource("pods"), labelSelector, 0, func(item *unstructured.Unstructured) (bool, error) {
+		var pod corev1.Pod
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &pod); err != nil {
+			return false, err
+		}
+		if pod.Status.Phase != corev1.PodRunning {
+			return false, nil
 		}
-		for {
-			ex.limiter.Wait(context.TODO())
-			pods, err := ex.clientSet.CoreV1().Pods(ns).List(context.TODO(), listOptions)
-			if err != nil {
-				log.Errorf("Error listing pods in %s: %v", ns, err)
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodReady && c.Status == corev1.ConditionFalse {
 				return false, nil
 			}
-			listOptions.Continue = pods.GetContinue()
-			for _, pod := range pods.Items {
-				if pod.Status.Phase != corev1.PodRunning {
-					return false, nil
-				}
-				for _, c := range pod.Status.Conditions {
-					if c.Type == corev1.PodReady && c.Status == corev1.ConditionFalse {
-						return false, nil
-					}
-				}
-			}
-			if listOptions.Continue == "" {
-				break
-			}
 		}
 		return true, nil
 	})
-	return err
 }
 
 func (ex *JobExecutor) waitForBuild(ns string, obj *object, labelSelector string) error {
 	buildStatus := []string{"New", "Pending", "Running"}
-	var build types.UnstructuredContent
-	err := wait.PollUntilContextTimeout(context.TODO(), time.Second, ex.MaxWaitTimeout, true, func(ctx context.Context) (done bool, err error) {
-		ex.limiter.Wait(context.TODO())
-		builds, err := ex.dynamicClient.Resource(obj.gvr).Namespace(ns).List(context.TODO(), metav1.ListOptions{
-			LabelSelector: labelSelector,
-		})
+	return ex.waitForItems(ns, obj.gvr, labelSelector, obj.Replicas, func(b *unstructured.Unstructured) (bool, error) {
+		var build types.UnstructuredContent
+		jsonBuild, err := b.MarshalJSON()
 		if err != nil {
-			log.Errorf("Error listing Builds in %s: %v", ns, err)
-			return false, nil
+			log.Errorf("Error decoding Build object: %s", err)
 		}
-		if len(builds.Items) < obj.Replicas {
-			log.Debugf("Waiting for Builds in ns %s to be completed", ns)
-			return false, err
-		}
-		for _, b := range builds.Items {
-			jsonBuild, err := b.MarshalJSON()
-			if err != nil {
-				log.Errorf("Error decoding Build object: %s", err)
-			}
-			_ = json.Unmarshal(jsonBuild, &build)
-			for _, bs := range buildStatus {
-				if build.Status.Phase == "" || build.Status.Phase == bs {
-					log.Debugf("Waiting for Builds in ns %s to be completed", ns)
-					return false, err
-				}
+		_ = json.Unmarshal(jsonBuild, &build)
+		for _, bs := range buildStatus {
+			if build.Status.Phase == "" || build.Status.Phase == bs {
+				log.Debugf("Waiting for Builds in ns %s to be completed", ns)
+				return false, nil
 			}
 		}
 		return true, nil
 	})
-	return err
 }
 
 func (ex *JobExecutor) verifyCondition(ns string, obj *object, labelSelector string) error {
@@ -252,75 +341,53 @@ func (ex *JobExecutor) verifyCondition(ns string, obj *object, labelSelector str
 	if obj.waitGVR != nil {
 		gvr = *obj.waitGVR
 	}
-	err := wait.PollUntilContextTimeout(context.TODO(), time.Second, ex.MaxWaitTimeout, true, func(ctx context.Context) (done bool, err error) {
-		var objs *unstructured.UnstructuredList
-		ex.limiter.Wait(context.TODO())
-		if obj.namespaced {
-			objs, err = ex.dynamicClient.Resource(gvr).Namespace(ns).List(context.TODO(), metav1.ListOptions{
-				LabelSelector: labelSelector,
-			})
-		} else {
-			objs, err = ex.dynamicClient.Resource(gvr).List(context.TODO(), metav1.ListOptions{
-				LabelSelector: labelSelector,
-			})
-		}
-		if err != nil {
-			if ns != "" {
-				log.Errorf("Error listing %s in %s: %v", obj.Kind, ns, err)
-			} else {
-				log.Errorf("Error listing %s: %v", obj.Kind, err)
+	if !obj.namespaced {
+		ns = metav1.NamespaceAll
+	}
+	return ex.waitForItems(ns, gvr, labelSelector, 0, func(item *unstructured.Unstructured) (bool, error) {
+		isVerified := true
+		for _, statusPath := range obj.WaitOptions.CustomStatusPaths {
+			status, found, err := unstructured.NestedMap(item.Object, "status")
+			if err != nil || !found {
+				log.Errorf("Error extracting or finding status in object %s/%s: %v", item.GetKind(), item.GetName(), err)
+				return false, err
 			}
-			return false, nil
-		}
-	VERIFY:
-		for _, item := range objs.Items {
-			isVerified := true
-			for _, statusPath := range obj.WaitOptions.CustomStatusPaths {
-				status, found, err := unstructured.NestedMap(item.Object, "status")
-				if err != nil || !found {
-					log.Errorf("Error extracting or finding status in object %s/%s: %v", item.GetKind(), item.GetName(), err)
+			isStatusValid := false
+			if len(status) != 0 {
+				// Compile and execute the jq query
+				query, err := gojq.Parse(statusPath.Key)
+				if err != nil {
+					log.Errorf("Error parsing jq path: %s", statusPath.Key)
 					return false, err
 				}
-				isStatusValid := false
-				if len(status) != 0 {
-					// Compile and execute the jq query
-					query, err := gojq.Parse(statusPath.Key)
-					if err != nil {
-						log.Errorf("Error parsing jq path: %s", statusPath.Key)
-						return false, err
+				iter := query.Run(status)
+				for {
+					v, ok := iter.Next()
+					if !ok {
+						break
+					}
+					if err, ok := v.(error); ok {
+						log.Warnf("Error evaluating jq path: [%s]: %s", statusPath.Key, err)
+						break
 					}
-					iter := query.Run(status)
-					for {
-						v, ok := iter.Next()
-						if !ok {
-							break
-						}
-						if err, ok := v.(error); ok {
-							log.Warnf("Error evaluating jq path: [%s]: %s", statusPath.Key, err)
-							break
-						}
-						if v == statusPath.Value {
-							isStatusValid = true
-							break
-						}
+					if v == statusPath.Value {
+						isStatusValid = true
+						break
 					}
 				}
-				isVerified = isVerified && isStatusValid
-			}
-			if obj.namespaced {
-				log.Debugf("Waiting for %s in ns %s to be ready", obj.gvr.Resource, ns)
-			} else {
-				log.Debugf("Waiting for %s to be ready", obj.gvr.Resource)
-			}
-			if isVerified {
-				log.Debugf("Status verified for object %s/%s", item.GetKind(), item.GetName())
-				continue VERIFY
 			}
-			return false, err
+			isVerified = isVerified && isStatusValid
 		}
-		return true, nil
+		if obj.namespaced {
+			log.Debugf("Waiting for %s in ns %s to be ready", obj.gvr.Resource, ns)
+		} else {
+			log.Debugf("Waiting for %s to be ready", obj.gvr.Resource)
+		}
+		if isVerified {
+			log.Debugf("Status verified for object %s/%s", item.GetKind(), item.GetName())
+		}
+		return isVerified, nil
 	})
-	return err
 }
 
 func (ex *JobExecutor) waitForVolumeSnapshot(ns string, obj *object, labelSelector string) error {
@@ -0,0 +1,236 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Functions exported by the wasm hook modules, free is optional
+const (
+	wasmAlloc  = "alloc"
+	wasmMutate = "mutate"
+	wasmFree   = "free"
+)
+
+// wasmHook hands the rendered objects of a job to a WebAssembly module. The module exports its memory,
+// alloc(size i32) i32 and mutate(ptr i32, len i32) i64: mutate receives the object as JSON in a buffer returned by
+// alloc, and returns the pointer and length of its response packed as ptr<<32 | len. Module instances aren't safe for
+// concurrent use, so each request takes one from a pool
+type wasmHook struct {
+	config  config.WasmHook
+	runtime wazero.Runtime
+	module  wazero.CompiledModule
+	mu      sync.Mutex
+	idle    []api.Module
+	stats   struct{ objects, mutated, vetoed, failed atomic.Int64 }
+}
+
+// wasmHookResponse response of the module, an empty response creates the object unchanged
+type wasmHookResponse struct {
+	// Object replaces the rendered object
+	Object map[string]any `json:"object"`
+	// Veto reason the object isn't created
+	Veto string `json:"veto"`
+}
+
+// setupWasmHook compiles the module of the wasm hook of the job and checks its exports
+func (ex *JobExecutor) setupWasmHook() {
+	if ex.WasmHook == nil {
+		return
+	}
+	reader, err := fileutils.GetWorkloadReader(ex.WasmHook.Module, ex.embedCfg)
+	if err != nil {
+		log.Fatalf("Job %s: error reading wasm module %s: %v", ex.Name, ex.WasmHook.Module, err)
+	}
+	wasm, err := io.ReadAll(reader)
+	if err != nil {
+		log.Fatalf("Job %s: error reading wasm module %s: %v", ex.Name, ex.WasmHook.Module, err)
+	}
+	ctx := context.Background()
+	wh := &wasmHook{
+		config:  *ex.WasmHook,
+		runtime: wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().WithCloseOnContextDone(true)),
+	}
+	wasi_snapshot_preview1.MustInstantiate(ctx, wh.runtime)
+	if wh.module, err = wh.runtime.CompileModule(ctx, wasm); err != nil {
+		log.Fatalf("Job %s: error compiling wasm module %s: %v", ex.Name, ex.WasmHook.Module, err)
+	}
+	instance, err := wh.instance()
+	if err != nil {
+		log.Fatalf("Job %s: %v", ex.Name, err)
+	}
+	wh.release(instance)
+	ex.wasmHook = wh
+	log.Infof("Job %s: objects handed to wasm module %s before their creation", ex.Name, ex.WasmHook.Module)
+}
+
+// instance takes an idle module instance, or instantiates a new one
+func (wh *wasmHook) instance() (api.Module, error) {
+	wh.mu.Lock()
+	if n := len(wh.idle); n > 0 {
+		instance := wh.idle[n-1]
+		wh.idle = wh.idle[:n-1]
+		wh.mu.Unlock()
+		return instance, nil
+	}
+	wh.mu.Unlock()
+	// Anonymous instances, so that many of them can coexist. Reactor modules are initialized with _initialize
+	moduleConfig := wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize").WithStderr(os.Stderr)
+	instance, err := wh.runtime.InstantiateModule(context.Background(), wh.module, moduleConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error instantiating wasm module %s: %v", wh.config.Module, err)
+	}
+	if instance.Memory() == nil || instance.ExportedFunction(wasmAlloc) == nil || instance.ExportedFunction(wasmMutate) == nil {
+		instance.Close(context.Background())
+		return nil, fmt.Errorf("wasm module %s must export memory, %s and %s", wh.config.Module, wasmAlloc, wasmMutate)
+	}
+	return instance, nil
+}
+
+func (wh *wasmHook) release(instance api.Module) {
+	wh.mu.Lock()
+	defer wh.mu.Unlock()
+	wh.idle = append(wh.idle, instance)
+}
+
+// call hands the object to the module, returns its response
+func (wh *wasmHook) call(input []byte) (wasmHookResponse, error) {
+	var response wasmHookResponse
+	instance, err := wh.instance()
+	if err != nil {
+		return response, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), wh.config.Timeout)
+	defer cancel()
+	output, err := wh.mutate(ctx, instance, input)
+	if err != nil {
+		// The instance may be left in an inconsistent state, or closed when timed out
+		instance.Close(context.Background())
+		return response, err
+	}
+	wh.release(instance)
+	if len(output) == 0 {
+		return response, nil
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return response, fmt.Errorf("invalid response: %v", err)
+	}
+	return response, nil
+}
+
+func (wh *wasmHook) mutate(ctx context.Context, instance api.Module, input []byte) ([]byte, error) {
+	results, err := instance.ExportedFunction(wasmAlloc).Call(ctx, uint64(len(input)))
+	if err != nil {
+		return nil, err
+	}
+	inputPtr := uint32(results[0])
+	if !instance.Memory().Write(inputPtr, input) {
+		return nil, fmt.Errorf("%s returned a buffer out of the memory of the module", wasmAlloc)
+	}
+	if results, err = instance.ExportedFunction(wasmMutate).Call(ctx, uint64(inputPtr), uint64(len(input))); err != nil {
+		return nil, err
+	}
+	outputPtr, outputLen := uint32(results[0]>>32), uint32(results[0])
+	view, ok := instance.Memory().Read(outputPtr, outputLen)
+	if !ok {
+		return nil, fmt.Errorf("%s returned a response out of the memory of the module", wasmMutate)
+	}
+	// The view is only valid until the memory of the module changes
+	output := append([]byte(nil), view...)
+	if free := instance.ExportedFunction(wasmFree); free != nil {
+		for _, buffer := range [][2]uint32{{inputPtr, uint32(len(input))}, {outputPtr, outputLen}} {
+			if _, err := free.Call(ctx, uint64(buffer[0]), uint64(buffer[1])); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return output, nil
+}
+
+// applyWasmHook hands the object to the wasm hook of the job, replacing it with the object returned by the module.
+// Returns whether the object must be created
+func (ex *JobExecutor) applyWasmHook(obj *unstructured.Unstructured) bool {
+	if ex.wasmHook == nil {
+		return true
+	}
+	wh := ex.wasmHook
+	wh.stats.objects.Add(1)
+	input, err := obj.MarshalJSON()
+	if err != nil {
+		log.Errorf("Error encoding object %s: %v", obj.GetName(), err)
+		ex.recordError()
+		return false
+	}
+	response, err := wh.call(input)
+	switch {
+	case err != nil:
+		wh.stats.failed.Add(1)
+		log.Errorf("Wasm hook failed for object %s: %v", obj.GetName(), err)
+		if wh.config.FailurePolicy == config.HookIgnore {
+			return true
+		}
+		ex.recordError()
+		return false
+	case response.Veto != "":
+		wh.stats.vetoed.Add(1)
+		log.Debugf("Wasm hook vetoed object %s: %s", obj.GetName(), response.Veto)
+		return false
+	case response.Object != nil:
+		wh.stats.mutated.Add(1)
+		obj.Object = response.Object
+	}
+	return true
+}
+
+// summary returns the objects handed to the wasm hook and logs them
+func (wh *wasmHook) summary() *prometheus.WasmHookStats {
+	if wh == nil {
+		return nil
+	}
+	stats := &prometheus.WasmHookStats{
+		Objects: wh.stats.objects.Load(),
+		Mutated: wh.stats.mutated.Load(),
+		Vetoed:  wh.stats.vetoed.Load(),
+		Failed:  wh.stats.failed.Load(),
+	}
+	log.Infof("Wasm hook: %d objects, %d mutated, %d vetoed, %d failed", stats.Objects, stats.Mutated, stats.Vetoed, stats.Failed)
+	return stats
+}
+
+// close releases the module instances and the runtime
+func (wh *wasmHook) close() {
+	if wh == nil {
+		return
+	}
+	if err := wh.runtime.Close(context.Background()); err != nil {
+		log.Warnf("Error closing the wasm runtime: %v", err)
+	}
+}
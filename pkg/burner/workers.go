@@ -0,0 +1,366 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package burner
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/metrics"
+	"github.com/kube-burner/kube-burner/pkg/prometheus"
+	utilmetrics "github.com/kube-burner/kube-burner/pkg/util/metrics"
+	log "github.com/sirupsen/logrus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// workerResults documents of an indexing call of a worker process
+type workerResults struct {
+	MetricName string            `json:"metricName"`
+	Documents  []json.RawMessage `json:"documents"`
+}
+
+// workerResultsIndexer writes the documents of a worker process to a directory, one file per indexing call, so that
+// the coordinator of the benchmark aggregates and indexes the results of all the workers once
+type workerResultsIndexer struct {
+	directory string
+	mu        sync.Mutex
+	calls     int
+}
+
+func (w *workerResultsIndexer) Index(documents []any, opts indexers.IndexingOpts) (string, error) {
+	results := workerResults{MetricName: opts.MetricName}
+	for _, document := range documents {
+		j, err := json.Marshal(document)
+		if err != nil {
+			return "", fmt.Errorf("cannot encode document: %v", err)
+		}
+		results.Documents = append(results.Documents, j)
+	}
+	j, err := json.Marshal(results)
+	if err != nil {
+		return "", err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	filename := filepath.Join(w.directory, fmt.Sprintf("%05d.json", w.calls))
+	if err := os.WriteFile(filename, j, 0644); err != nil {
+		return "", fmt.Errorf("cannot write worker results: %v", err)
+	}
+	w.calls++
+	return fmt.Sprintf("%d %s documents handed over to the coordinator", len(documents), opts.MetricName), nil
+}
+
+// WorkerScraper replaces the indexers of the scraper of a worker process by indexers writing to the given directory
+func WorkerScraper(metricsScraper utilmetrics.Scraper, directory string) (utilmetrics.Scraper, error) {
+	indexerList := make(map[string]indexers.Indexer, len(metricsScraper.IndexerList))
+	for alias := range metricsScraper.IndexerList {
+		indexerDirectory := filepath.Join(directory, alias)
+		if err := os.MkdirAll(indexerDirectory, 0755); err != nil {
+			return metricsScraper, fmt.Errorf("cannot create worker results directory: %v", err)
+		}
+		indexerList[alias] = &workerResultsIndexer{directory: indexerDirectory}
+	}
+	metricsScraper.IndexerList = indexerList
+	return metricsScraper, nil
+}
+
+// IndexWorkerResults aggregates the documents written by the worker processes in the given directory and indexes them
+// once: the job summaries of each job are merged, the quantiles are calculated from the latencies of all the workers
+// and the metrics and alerts are scraped and evaluated over the whole duration of each job
+func IndexWorkerResults(configSpec config.Spec, metricsScraper utilmetrics.Scraper, directory string) (int, error) {
+	var errs []error
+	var rc int
+	// Documents by indexer alias and metric name
+	results := make(map[string]map[string][]map[string]any)
+	workerDirectories, _ := filepath.Glob(filepath.Join(directory, "worker-*"))
+	for _, workerDirectory := range workerDirectories {
+		for alias := range metricsScraper.IndexerList {
+			if err := readWorkerResults(filepath.Join(workerDirectory, alias), alias, results); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	jobs, summaries := mergeJobSummaries(results)
+	for i, job := range jobs {
+		var jobErrors []error
+		for _, alertM := range metricsScraper.AlertMs {
+			if err := alertM.Evaluate(job); err != nil {
+				errs = append(errs, err)
+				jobErrors = append(jobErrors, err)
+				rc = rcAlert
+			}
+		}
+		if len(jobErrors) > 0 {
+			summaries[i]["passed"] = false
+			summaries[i]["executionErrors"] = joinErrors(summaries[i]["executionErrors"], utilerrors.NewAggregate(jobErrors).Error())
+		}
+	}
+	for alias, indexer := range metricsScraper.IndexerList {
+		for _, metricName := range slices.Sorted(maps.Keys(results[alias])) {
+			documents := results[alias][metricName]
+			switch {
+			case metricName == jobSummaryMetric:
+				documents = summaries
+			case isDocument(documents, "latencies"):
+				documents = mergeQuantiles(documents)
+			case isDocument(documents, "buckets"):
+				documents = mergeHistograms(documents)
+			}
+			if len(documents) == 0 {
+				continue
+			}
+			log.Infof("Indexing %d %s documents of the workers", len(documents), metricName)
+			documentsInt := make([]any, 0, len(documents))
+			for _, document := range documents {
+				documentsInt = append(documentsInt, document)
+			}
+			resp, err := indexer.Index(documentsInt, indexers.IndexingOpts{MetricName: metricName})
+			if err != nil {
+				errs = append(errs, err)
+			} else {
+				log.Info(resp)
+			}
+		}
+	}
+	for _, prometheusClient := range metricsScraper.PrometheusClients {
+		prometheusClient.ScrapeJobsMetrics(jobs...)
+	}
+	finishIndexers(configSpec)
+	return rc, utilerrors.NewAggregate(errs)
+}
+
+// readWorkerResults reads the documents a worker process indexed with the given indexer
+func readWorkerResults(directory, alias string, results map[string]map[string][]map[string]any) error {
+	filenames, err := filepath.Glob(filepath.Join(directory, "*.json"))
+	if err != nil {
+		return err
+	}
+	// Files are named after the order of the indexing calls
+	slices.Sort(filenames)
+	for _, filename := range filenames {
+		var callResults workerResults
+		j, err := os.ReadFile(filename)
+		if err != nil {
+			return fmt.Errorf("cannot read worker results: %v", err)
+		}
+		if err := json.Unmarshal(j, &callResults); err != nil {
+			return fmt.Errorf("cannot decode worker results %s: %v", filename, err)
+		}
+		if results[alias] == nil {
+			results[alias] = make(map[string][]map[string]any)
+		}
+		for _, rawDocument := range callResults.Documents {
+			var document map[string]any
+			if err := json.Unmarshal(rawDocument, &document); err != nil {
+				return fmt.Errorf("cannot decode worker document %s: %v", filename, err)
+			}
+			results[alias][callResults.MetricName] = append(results[alias][callResults.MetricName], document)
+		}
+	}
+	return nil
+}
+
+// isDocument checks whether the documents have the given field
+func isDocument(documents []map[string]any, field string) bool {
+	if len(documents) == 0 {
+		return false
+	}
+	_, ok := documents[0][field]
+	return ok
+}
+
+// documentKey identifies the documents of the same job, group and condition written by different workers
+func documentKey(document map[string]any) string {
+	return fmt.Sprintf("%v/%v/%v/%v", document["metricName"], document["jobName"], document["group"], document["quantileName"])
+}
+
+// mergeQuantiles calculates the quantiles of the latencies of all the workers
+func mergeQuantiles(documents []map[string]any) []map[string]any {
+	var keys []string
+	merged := make(map[string]map[string]any)
+	latencies := make(map[string][]float64)
+	for _, document := range documents {
+		key := documentKey(document)
+		if _, ok := merged[key]; !ok {
+			keys = append(keys, key)
+			merged[key] = document
+		}
+		var quantiles metrics.LatencyQuantiles
+		j, _ := json.Marshal(document)
+		json.Unmarshal(j, &quantiles)
+		latencies[key] = append(latencies[key], quantiles.Latencies...)
+	}
+	var mergedDocuments []map[string]any
+	for _, key := range keys {
+		document := merged[key]
+		quantileName, _ := document["quantileName"].(string)
+		var summary map[string]any
+		j, _ := json.Marshal(metrics.NewLatencySummary(latencies[key], quantileName))
+		json.Unmarshal(j, &summary)
+		delete(document, "latencies")
+		for _, field := range []string{"P99CI", "P95CI", "P50CI"} {
+			delete(document, field)
+		}
+		for field, value := range summary {
+			// Keep the identity of the document
+			if !slices.Contains([]string{"quantileName", "group", "uuid", "metricName", "jobName", "metadata"}, field) {
+				document[field] = value
+			}
+		}
+		mergedDocuments = append(mergedDocuments, document)
+	}
+	return mergedDocuments
+}
+
+// mergeHistograms adds up the histograms of all the workers, they share the same buckets
+func mergeHistograms(documents []map[string]any) []map[string]any {
+	var keys []string
+	merged := make(map[string]metrics.LatencyHistogram)
+	first := make(map[string]map[string]any)
+	for _, document := range documents {
+		key := documentKey(document)
+		var histogram metrics.LatencyHistogram
+		j, _ := json.Marshal(document)
+		json.Unmarshal(j, &histogram)
+		current, ok := merged[key]
+		if !ok {
+			keys = append(keys, key)
+			first[key] = document
+			merged[key] = histogram
+			continue
+		}
+		current.Count += histogram.Count
+		current.Sum += histogram.Sum
+		for i := range min(len(current.Buckets), len(histogram.Buckets)) {
+			current.Buckets[i].Count += histogram.Buckets[i].Count
+		}
+		merged[key] = current
+	}
+	var mergedDocuments []map[string]any
+	for _, key := range keys {
+		document := first[key]
+		document["count"], document["sum"], document["buckets"] = merged[key].Count, merged[key].Sum, merged[key].Buckets
+		mergedDocuments = append(mergedDocuments, document)
+	}
+	return mergedDocuments
+}
+
+// mergeJobSummaries merges the summaries of each job written by the workers, and returns the jobs they describe
+func mergeJobSummaries(results map[string]map[string][]map[string]any) ([]prometheus.Job, []map[string]any) {
+	// All the indexers get the same summaries, use the ones of any of them
+	var documents []map[string]any
+	for _, alias := range slices.Sorted(maps.Keys(results)) {
+		if len(results[alias][jobSummaryMetric]) > 0 {
+			documents = results[alias][jobSummaryMetric]
+			break
+		}
+	}
+	var jobs []prometheus.Job
+	var summaries []map[string]any
+	index := make(map[string]int)
+	for _, document := range documents {
+		var summary JobSummary
+		j, _ := json.Marshal(document)
+		if err := json.Unmarshal(j, &summary); err != nil {
+			log.Errorf("Cannot decode job summary of a worker: %v", err)
+			continue
+		}
+		i, ok := index[summary.JobConfig.Name]
+		if !ok {
+			index[summary.JobConfig.Name] = len(jobs)
+			jobs = append(jobs, prometheus.Job{
+				Start:      summary.Timestamp,
+				End:        summary.EndTimestamp,
+				ChurnStart: summary.ChurnStartTimestamp,
+				ChurnEnd:   summary.ChurnEndTimestamp,
+				JobConfig:  summary.JobConfig,
+			})
+			summaries = append(summaries, document)
+			continue
+		}
+		job, merged := &jobs[i], summaries[i]
+		if summary.Timestamp.Before(job.Start) {
+			job.Start = summary.Timestamp
+		}
+		if summary.EndTimestamp.After(job.End) {
+			job.End = summary.EndTimestamp
+		}
+		merged["passed"] = merged["passed"] == true && summary.Passed
+		merged["executionErrors"] = joinErrors(merged["executionErrors"], summary.ExecutionErrors)
+		// The workers run concurrently, their throughputs add up
+		merged["achievedQps"] = math.Round((toFloat(merged["achievedQps"])+summary.AchievedQps)*1000) / 1000
+		for _, field := range []string{"waiterListRequests", "waiterWatchEvents", "artifactFiles"} {
+			if value := toFloat(merged[field]) + toFloat(document[field]); value > 0 {
+				merged[field] = value
+			}
+		}
+		for _, field := range []string{"discoveryLatency", "warmUpLatency", "waitForLatency"} {
+			if value := max(toFloat(merged[field]), toFloat(document[field])); value > 0 {
+				merged[field] = value
+			}
+		}
+		if len(summary.IdentityRequests) > 0 {
+			identityRequests, _ := merged["identityRequests"].(map[string]any)
+			if identityRequests == nil {
+				identityRequests = make(map[string]any)
+			}
+			for identity, requests := range summary.IdentityRequests {
+				identityRequests[identity] = toFloat(identityRequests[identity]) + float64(requests)
+			}
+			merged["identityRequests"] = identityRequests
+		}
+		if len(summary.SLOWarnings) > 0 {
+			warnings, _ := merged["sloWarnings"].([]any)
+			for _, warning := range summary.SLOWarnings {
+				warnings = append(warnings, warning)
+			}
+			merged["sloWarnings"] = warnings
+		}
+	}
+	for i, job := range jobs {
+		summaries[i]["timestamp"] = job.Start
+		summaries[i]["endTimestamp"] = job.End
+		summaries[i]["elapsedTime"] = job.End.Sub(job.Start).Round(time.Second).Seconds()
+	}
+	return jobs, summaries
+}
+
+// joinErrors appends an error to the execution errors of a job summary
+func joinErrors(executionErrors any, err string) string {
+	current, _ := executionErrors.(string)
+	switch {
+	case err == "" || strings.Contains(current, err):
+		return current
+	case current == "":
+		return err
+	}
+	return current + "\n" + err
+}
+
+// toFloat returns the value of a numeric field of a decoded document
+func toFloat(value any) float64 {
+	f, _ := value.(float64)
+	return f
+}
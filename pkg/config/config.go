@@ -16,12 +16,20 @@ package config
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"fmt"
 	"io"
 	"maps"
+	"net"
+	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cloud-bulldozer/go-commons/v2/indexers"
@@ -30,34 +38,53 @@ import (
 	"github.com/kube-burner/kube-burner/pkg/util"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
-var configSpec = Spec{
-	GlobalConfig: GlobalConfig{
-		GC:                false,
-		GCMetrics:         false,
-		GCTimeout:         1 * time.Hour,
-		RequestTimeout:    60 * time.Second,
-		Measurements:      []mtypes.Measurement{},
-		WaitWhenFinished:  false,
-		Timeout:           4 * time.Hour,
-		FunctionTemplates: []string{},
-	},
+var configSpec = defaultSpec()
+
+// defaultSpec returns the configuration defaults
+func defaultSpec() Spec {
+	return Spec{
+		GlobalConfig: GlobalConfig{
+			GC:                 false,
+			GCMetrics:          false,
+			ClusterMetadata:    true,
+			GCTimeout:          1 * time.Hour,
+			RequestTimeout:     60 * time.Second,
+			ArtifactsDirectory: "artifacts",
+			Measurements:       []mtypes.Measurement{},
+			WaitWhenFinished:   false,
+			Timeout:            4 * time.Hour,
+			FunctionTemplates:  []string{},
+			// Defaults of client-go
+			ClientTransport: ClientTransport{
+				ContentType:         ContentTypeJSON,
+				MaxIdleConnsPerHost: defaultMaxIdleConnsPerHost,
+				KeepAlive:           defaultKeepAlive,
+			},
+		},
+	}
 }
 
 // UnmarshalYAML unmarshals YAML data into the Indexer struct.
 func (i *MetricsEndpoint) UnmarshalYAML(unmarshal func(any) error) error {
 	type rawIndexer MetricsEndpoint
 	indexer := rawIndexer{
-		IndexerConfig: indexers.IndexerConfig{
-			InsecureSkipVerify: false,
-			MetricsDirectory:   "collected-metrics",
-			TarballName:        "kube-burner-metrics.tgz",
+		IndexerConfig: IndexerConfig{
+			IndexerConfig: indexers.IndexerConfig{
+				InsecureSkipVerify: false,
+				MetricsDirectory:   "collected-metrics",
+				TarballName:        "kube-burner-metrics.tgz",
+			},
 		},
 		SkipTLSVerify: true,
 		Step:          30 * time.Second,
@@ -65,6 +92,17 @@ func (i *MetricsEndpoint) UnmarshalYAML(unmarshal func(any) error) error {
 	if err := unmarshal(&indexer); err != nil {
 		return err
 	}
+	if indexer.Type != "" && len(indexer.Indexers) > 0 {
+		return fmt.Errorf("indexer and indexers are mutually exclusive")
+	}
+	for j := range indexer.Indexers {
+		if indexer.Indexers[j].MetricsDirectory == "" {
+			indexer.Indexers[j].MetricsDirectory = indexer.MetricsDirectory
+		}
+		if indexer.Indexers[j].TarballName == "" {
+			indexer.Indexers[j].TarballName = indexer.TarballName
+		}
+	}
 	*i = MetricsEndpoint(indexer)
 	return nil
 }
@@ -82,6 +120,411 @@ func (o *Object) UnmarshalYAML(unmarshal func(any) error) error {
 	return nil
 }
 
+// UnmarshalYAML implements Unmarshaller to customize exit hook defaults
+func (h *ExitHook) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawExitHook ExitHook
+	hook := rawExitHook{
+		Timeout: 5 * time.Minute,
+	}
+	if err := unmarshal(&hook); err != nil {
+		return err
+	}
+	*h = ExitHook(hook)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize cost defaults
+func (c *CostConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawCostConfig CostConfig
+	cost := rawCostConfig{
+		Provider: CostOpenCost,
+		Timeout:  time.Minute,
+	}
+	if err := unmarshal(&cost); err != nil {
+		return err
+	}
+	*c = CostConfig(cost)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize client metrics defaults
+func (c *ClientMetrics) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawClientMetrics ClientMetrics
+	clientMetrics := rawClientMetrics{
+		Interval:        5 * time.Second,
+		CPUThreshold:    90,
+		MemoryThreshold: 90,
+	}
+	if err := unmarshal(&clientMetrics); err != nil {
+		return err
+	}
+	*c = ClientMetrics(clientMetrics)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize node watchdog defaults
+func (n *NodeWatchdog) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawNodeWatchdog NodeWatchdog
+	nodeWatchdog := rawNodeWatchdog{
+		Interval:          10 * time.Second,
+		Conditions:        []string{"Ready", "MemoryPressure", "DiskPressure"},
+		MaxUnhealthyRatio: 0.1,
+		Action:            NodeWatchdogRecord,
+		PauseTimeout:      10 * time.Minute,
+	}
+	if err := unmarshal(&nodeWatchdog); err != nil {
+		return err
+	}
+	*n = NodeWatchdog(nodeWatchdog)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize artifact defaults
+func (a *Artifact) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawArtifact Artifact
+	artifact := rawArtifact{
+		MetricName: "artifact",
+	}
+	if err := unmarshal(&artifact); err != nil {
+		return err
+	}
+	*a = Artifact(artifact)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize target health defaults
+func (t *TargetHealth) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawTargetHealth TargetHealth
+	targetHealth := rawTargetHealth{
+		Policy: ClusterHealthWarn,
+	}
+	if err := unmarshal(&targetHealth); err != nil {
+		return err
+	}
+	if _, ok := clusterHealthPolicies[targetHealth.Policy]; !ok {
+		return fmt.Errorf("invalid targetHealth policy %s", targetHealth.Policy)
+	}
+	*t = TargetHealth(targetHealth)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize Alertmanager defaults
+func (a *Alertmanager) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawAlertmanager Alertmanager
+	alertmanager := rawAlertmanager{
+		Interval: 30 * time.Second,
+	}
+	if err := unmarshal(&alertmanager); err != nil {
+		return err
+	}
+	*a = Alertmanager(alertmanager)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize live reconfiguration defaults
+func (l *LiveReconfiguration) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawLiveReconfiguration LiveReconfiguration
+	liveReconfiguration := rawLiveReconfiguration{
+		Address: "127.0.0.1:8089",
+	}
+	if err := unmarshal(&liveReconfiguration); err != nil {
+		return err
+	}
+	*l = LiveReconfiguration(liveReconfiguration)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize cluster health monitor defaults
+func (c *ClusterHealthMonitor) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawClusterHealthMonitor ClusterHealthMonitor
+	clusterHealthMonitor := rawClusterHealthMonitor{
+		Interval:        30 * time.Second,
+		MaxFailedChecks: 3,
+		Policy:          ClusterHealthWarn,
+	}
+	if err := unmarshal(&clusterHealthMonitor); err != nil {
+		return err
+	}
+	*c = ClusterHealthMonitor(clusterHealthMonitor)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize anomaly detection defaults
+func (a *AnomalyDetection) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawAnomalyDetection AnomalyDetection
+	anomalyDetection := rawAnomalyDetection{
+		Threshold:      6,
+		MinSegment:     5,
+		SpikeThreshold: 10,
+	}
+	if err := unmarshal(&anomalyDetection); err != nil {
+		return err
+	}
+	*a = AnomalyDetection(anomalyDetection)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize the cluster snapshot defaults: the kubeadm control-plane static pods
+func (c *ClusterSnapshot) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawClusterSnapshot ClusterSnapshot
+	var clusterSnapshot rawClusterSnapshot
+	if err := unmarshal(&clusterSnapshot); err != nil {
+		return err
+	}
+	if len(clusterSnapshot.Components) == 0 && len(clusterSnapshot.Resources) == 0 {
+		for _, component := range []string{"kube-apiserver", "kube-controller-manager", "kube-scheduler", "etcd"} {
+			clusterSnapshot.Components = append(clusterSnapshot.Components, SnapshotComponent{
+				Name:          component,
+				Namespace:     "kube-system",
+				LabelSelector: "component=" + component,
+			})
+		}
+	}
+	*c = ClusterSnapshot(clusterSnapshot)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize phase events defaults
+func (p *PhaseEvents) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawPhaseEvents PhaseEvents
+	phaseEvents := rawPhaseEvents{
+		Namespace: "default",
+	}
+	if err := unmarshal(&phaseEvents); err != nil {
+		return err
+	}
+	*p = PhaseEvents(phaseEvents)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize GitHub report defaults, taken from the GitHub Actions environment
+func (g *GitHubReport) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawGitHubReport GitHubReport
+	gitHubReport := rawGitHubReport{
+		Mode:       GitHubCheckRun,
+		Repository: os.Getenv("GITHUB_REPOSITORY"),
+		SHA:        os.Getenv("GITHUB_SHA"),
+		Name:       "kube-burner",
+		APIURL:     cmp.Or(os.Getenv("GITHUB_API_URL"), "https://api.github.com"),
+		Token:      os.Getenv("GITHUB_TOKEN"),
+		Timeout:    time.Minute,
+	}
+	// GITHUB_REF is refs/pull/<number>/merge in pull request workflows
+	if ref := strings.TrimPrefix(os.Getenv("GITHUB_REF"), "refs/pull/"); ref != os.Getenv("GITHUB_REF") {
+		gitHubReport.PullRequest, _ = strconv.Atoi(strings.TrimSuffix(ref, "/merge"))
+	}
+	if err := unmarshal(&gitHubReport); err != nil {
+		return err
+	}
+	*g = GitHubReport(gitHubReport)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize job hook defaults
+func (h *JobHook) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawJobHook JobHook
+	hook := rawJobHook{
+		Timeout:       5 * time.Minute,
+		FailurePolicy: HookFail,
+	}
+	if err := unmarshal(&hook); err != nil {
+		return err
+	}
+	*h = JobHook(hook)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize wasm hook defaults
+func (h *WasmHook) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawWasmHook WasmHook
+	hook := rawWasmHook{
+		Timeout:       time.Second,
+		FailurePolicy: HookFail,
+	}
+	if err := unmarshal(&hook); err != nil {
+		return err
+	}
+	*h = WasmHook(hook)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize helm chart defaults
+func (h *HelmChart) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawHelmChart HelmChart
+	chart := rawHelmChart{
+		ReleaseName: "{{.JobName}}-{{.Iteration}}",
+		Operation:   HelmInstall,
+		Wait:        true,
+		Timeout:     5 * time.Minute,
+		Binary:      "helm",
+	}
+	if err := unmarshal(&chart); err != nil {
+		return err
+	}
+	*h = HelmChart(chart)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize fio benchmark defaults
+func (f *FioBenchmark) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawFioBenchmark FioBenchmark
+	fio := rawFioBenchmark{
+		Image:      "quay.io/cloud-bulldozer/fio:latest",
+		VolumeSize: "10Gi",
+		RW:         "randrw",
+		BlockSize:  "4k",
+		IODepth:    16,
+		NumJobs:    1,
+		FileSize:   "1g",
+		Runtime:    time.Minute,
+		Timeout:    15 * time.Minute,
+	}
+	if err := unmarshal(&fio); err != nil {
+		return err
+	}
+	*f = FioBenchmark(fio)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize network benchmark defaults
+func (n *NetworkBenchmark) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawNetworkBenchmark NetworkBenchmark
+	network := rawNetworkBenchmark{
+		Image:       "quay.io/cloud-bulldozer/k8s-netperf:latest",
+		Topology:    NetworkCrossNode,
+		Protocol:    NetworkTCP,
+		Duration:    30 * time.Second,
+		Parallel:    1,
+		Latency:     true,
+		MessageSize: 1,
+		Timeout:     10 * time.Minute,
+	}
+	if err := unmarshal(&network); err != nil {
+		return err
+	}
+	*n = NetworkBenchmark(network)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize inflate defaults
+func (i *Inflate) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawInflate Inflate
+	inflate := rawInflate{
+		Target:            InflateAnnotation,
+		Field:             "kube-burner.io/inflate",
+		BytesPerIteration: 10 * 1024,
+		// Below the default 1.5MiB request size limit of etcd
+		MaxSize: 1024 * 1024,
+	}
+	if err := unmarshal(&inflate); err != nil {
+		return err
+	}
+	*i = Inflate(inflate)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize mesh overhead defaults
+func (m *MeshOverhead) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawMeshOverhead MeshOverhead
+	meshOverhead := rawMeshOverhead{
+		MemoryQuery: `sum(container_memory_working_set_bytes{namespace=~"{{.Namespaces}}",container!="",container!="POD"})`,
+	}
+	if err := unmarshal(&meshOverhead); err != nil {
+		return err
+	}
+	*m = MeshOverhead(meshOverhead)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize scheduler cache defaults
+func (s *SchedulerCache) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawSchedulerCache SchedulerCache
+	schedulerCache := rawSchedulerCache{
+		Namespace:      "kube-system",
+		LabelSelector:  "component=kube-scheduler",
+		Lease:          "kube-scheduler",
+		RestartTimeout: 5 * time.Minute,
+		WarmUp:         time.Minute,
+	}
+	if err := unmarshal(&schedulerCache); err != nil {
+		return err
+	}
+	*s = SchedulerCache(schedulerCache)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize synthetic images defaults
+func (s *SyntheticImages) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawSyntheticImages SyntheticImages
+	syntheticImages := rawSyntheticImages{
+		Sizes:       []string{"10Mi"},
+		Parallelism: 4,
+	}
+	if err := unmarshal(&syntheticImages); err != nil {
+		return err
+	}
+	*s = SyntheticImages(syntheticImages)
+	return nil
+}
+
+// UnmarshalYAML implements Unmarshaller to customize waitFor defaults
+func (w *WaitFor) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawWaitFor WaitFor
+	waitFor := rawWaitFor{
+		Interval:      10 * time.Second,
+		Timeout:       10 * time.Minute,
+		FailurePolicy: HookFail,
+	}
+	if err := unmarshal(&waitFor); err != nil {
+		return err
+	}
+	*w = WaitFor(waitFor)
+	return nil
+}
+
+// UnmarshalYAML sets the defaults of the QPS ramp
+func (r *QPSRamp) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawQPSRamp QPSRamp
+	raw := rawQPSRamp{
+		Profile: RampLinear,
+		Steps:   5,
+	}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*r = QPSRamp(raw)
+	return nil
+}
+
+// UnmarshalYAML sets the defaults of the arrival rate
+func (a *ArrivalRate) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawArrivalRate ArrivalRate
+	raw := rawArrivalRate{
+		Distribution: ArrivalConstant,
+	}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*a = ArrivalRate(raw)
+	return nil
+}
+
+// UnmarshalYAML sets the defaults of the adaptive QPS controller
+func (a *AdaptiveQPS) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawAdaptiveQPS AdaptiveQPS
+	raw := rawAdaptiveQPS{
+		Interval:             10 * time.Second,
+		MinQPS:               1,
+		DecreaseFactor:       0.5,
+		MaxThrottledRequests: 1,
+	}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*a = AdaptiveQPS(raw)
+	return nil
+}
+
 // UnmarshalYAML implements Unmarshaller to customize watcher defaults
 func (w *Watcher) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	type rawWatcher Watcher
@@ -116,7 +559,13 @@ func (j *Job) UnmarshalYAML(unmarshal func(any) error) error {
 		ChurnDuration:          1 * time.Hour,
 		ChurnDelay:             5 * time.Minute,
 		ChurnDeletionStrategy:  "default",
+		ChurnPodDeletion:       ChurnPodDelete,
+		ChurnEvictionTimeout:   10 * time.Minute,
 		MetricsClosing:         AfterJobPause,
+		ErrorBreachPolicy:      ErrorBreachStop,
+		GCPolicy:               GCAlways,
+		WaiterMode:             WaiterModePoll,
+		ListOptions:            ListOptions{ResourceVersion: ResourceVersionMostRecent},
 	}
 
 	if err := unmarshal(&raw); err != nil {
@@ -154,19 +603,15 @@ func getInputData(userDataFileReader io.Reader, additionalVars map[string]any) (
 	return inputData, nil
 }
 
-func Parse(uuid string, timeout time.Duration, configFileReader io.Reader) (Spec, error) {
-	return ParseWithUserdata(uuid, timeout, configFileReader, nil, false, nil)
-}
-
-// Parse parses a configuration file
-func ParseWithUserdata(uuid string, timeout time.Duration, configFileReader, userDataFileReader io.Reader, allowMissingKeys bool, additionalVars map[string]any) (Spec, error) {
+// renderConfig renders the configuration file template with the user data and environment variables
+func renderConfig(configFileReader, userDataFileReader io.Reader, allowMissingKeys bool, additionalVars map[string]any) ([]byte, error) {
 	cfg, err := io.ReadAll(configFileReader)
 	if err != nil {
-		return configSpec, fmt.Errorf("error reading configuration file: %s", err)
+		return nil, fmt.Errorf("error reading configuration file: %s", err)
 	}
 	inputData, err := getInputData(userDataFileReader, additionalVars)
 	if err != nil {
-		return configSpec, err
+		return nil, err
 	}
 	templateOptions := util.MissingKeyError
 	if allowMissingKeys {
@@ -174,7 +619,26 @@ func ParseWithUserdata(uuid string, timeout time.Duration, configFileReader, use
 	}
 	renderedCfg, err := util.RenderTemplate(cfg, inputData, templateOptions, []string{})
 	if err != nil {
-		return configSpec, fmt.Errorf("error rendering configuration template: %s", err)
+		return nil, fmt.Errorf("error rendering configuration template: %s", err)
+	}
+	return renderedCfg, nil
+}
+
+func Parse(uuid string, timeout time.Duration, configFileReader io.Reader) (Spec, error) {
+	return ParseWithUserdata(uuid, timeout, configFileReader, nil, false, nil)
+}
+
+// Parse parses a configuration file
+func ParseWithUserdata(uuid string, timeout time.Duration, configFileReader, userDataFileReader io.Reader, allowMissingKeys bool, additionalVars map[string]any) (Spec, error) {
+	// Start from the defaults, so settings from a previously parsed configuration don't leak
+	configSpec = defaultSpec()
+	renderedCfg, err := renderConfig(configFileReader, userDataFileReader, allowMissingKeys, additionalVars)
+	if err != nil {
+		return configSpec, err
+	}
+	// Report all the violations at once, with their line numbers, rather than the first decoding error
+	if err := validateRenderedSchema(renderedCfg); err != nil {
+		return configSpec, fmt.Errorf("configuration file doesn't match its schema:\n%s", err)
 	}
 	cfgReader := bytes.NewReader(renderedCfg)
 	yamlDec := yaml.NewDecoder(cfgReader)
@@ -182,68 +646,420 @@ func ParseWithUserdata(uuid string, timeout time.Duration, configFileReader, use
 	if err = yamlDec.Decode(&configSpec); err != nil {
 		return configSpec, fmt.Errorf("error decoding configuration file: %s", err)
 	}
+	if err := splitMeshOverheadJobs(); err != nil {
+		return configSpec, err
+	}
+	if err := splitSchedulerCacheJobs(); err != nil {
+		return configSpec, err
+	}
 	if err := jobIsDuped(); err != nil {
 		return configSpec, err
 	}
 	if err := validateDNS1123(); err != nil {
 		return configSpec, err
 	}
+	if err := orderJobs(); err != nil {
+		return configSpec, err
+	}
 	if err := validateGC(); err != nil {
 		return configSpec, err
 	}
+	if err := validateDisruptionWindows(); err != nil {
+		return configSpec, err
+	}
+	if err := validateExitHooks(); err != nil {
+		return configSpec, err
+	}
+	if err := validateBudget(); err != nil {
+		return configSpec, err
+	}
+	if err := validateCost(); err != nil {
+		return configSpec, err
+	}
+	if err := validateClientMetrics(); err != nil {
+		return configSpec, err
+	}
+	if err := validateClientTransport(); err != nil {
+		return configSpec, err
+	}
+	if err := validateGitHubReport(); err != nil {
+		return configSpec, err
+	}
+	if err := validateNodeWatchdog(); err != nil {
+		return configSpec, err
+	}
+	if err := validateClusterHealthMonitor(); err != nil {
+		return configSpec, err
+	}
+	if err := validateAnomalyDetection(); err != nil {
+		return configSpec, err
+	}
+	if err := validateClusterSnapshot(); err != nil {
+		return configSpec, err
+	}
+	if err := validateProductionGuard(); err != nil {
+		return configSpec, err
+	}
+	if err := validateAlertmanager(); err != nil {
+		return configSpec, err
+	}
+	if err := validateLiveReconfiguration(); err != nil {
+		return configSpec, err
+	}
+	if configSpec.GlobalConfig.AlertInterval < 0 {
+		return configSpec, fmt.Errorf("alertInterval must be positive")
+	}
+	for _, notification := range configSpec.GlobalConfig.AlertNotifications {
+		if err := ValidateAlertNotification(notification); err != nil {
+			return configSpec, err
+		}
+	}
 	for i, job := range configSpec.Jobs {
 		if len(job.Namespace) > 62 {
 			log.Warnf("Namespace %s length has > 62 characters, truncating it", job.Namespace)
 			configSpec.Jobs[i].Namespace = job.Namespace[:57]
 		}
 		if !job.NamespacedIterations && job.Churn {
-			log.Fatal("Cannot have Churn enabled without Namespaced Iterations also enabled")
+			return configSpec, fmt.Errorf("cannot have Churn enabled without Namespaced Iterations also enabled")
 		}
-		if job.JobIterations < 1 && (job.JobType == CreationJob || job.JobType == ReadJob) {
-			log.Fatalf("Job %s has < 1 iterations", job.Name)
+		if job.JobIterations < 1 && (job.JobType == CreationJob || job.JobType == ReadJob || job.JobType == HelmJob || job.JobType == FioJob || job.JobType == NetworkJob || job.JobType == InflateJob) {
+			return configSpec, fmt.Errorf("job %s has < 1 iterations", job.Name)
 		}
 		if _, ok := metricsClosing[job.MetricsClosing]; !ok {
-			log.Fatalf("Invalid value for metricsClosing: %s", job.MetricsClosing)
+			return configSpec, fmt.Errorf("invalid value for metricsClosing: %s", job.MetricsClosing)
 		}
-		if job.JobType == DeletionJob {
-			configSpec.Jobs[i].PreLoadImages = false
+		if _, ok := errorBreachPolicies[job.ErrorBreachPolicy]; !ok {
+			return configSpec, fmt.Errorf("invalid value for errorBreachPolicy: %s", job.ErrorBreachPolicy)
 		}
-	}
-	configSpec.GlobalConfig.Timeout = timeout
-	configSpec.GlobalConfig.UUID = uuid
-	configSpec.GlobalConfig.RUNID = uid.NewString()
-	return configSpec, nil
-}
-
-func NewKubeClientProvider(config, context string) *KubeClientProvider {
-	var kubeConfigPath string
-	if config != "" {
-		kubeConfigPath = config
-	} else if os.Getenv("KUBECONFIG") != "" {
-		kubeConfigPath = os.Getenv("KUBECONFIG")
-	} else if _, err := os.Stat(filepath.Join(os.Getenv("HOME"), ".kube", "config")); kubeConfigPath == "" && !os.IsNotExist(err) {
-		kubeConfigPath = filepath.Join(os.Getenv("HOME"), ".kube", "config")
-	}
-	var restConfig *rest.Config
-	var err error
-	if kubeConfigPath == "" {
-		if restConfig, err = rest.InClusterConfig(); err != nil {
-			log.Fatalf("error preparing kubernetes client: %s", err)
+		if _, ok := gcPolicies[job.GCPolicy]; !ok {
+			return configSpec, fmt.Errorf("invalid value for gcPolicy: %s", job.GCPolicy)
 		}
-	} else {
-		kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-			&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeConfigPath},
-			&clientcmd.ConfigOverrides{CurrentContext: context},
-		)
-		if restConfig, err = kubeConfig.ClientConfig(); err != nil {
-			log.Fatalf("error preparing kubernetes client: %s", err)
+		if _, ok := churnPodDeletions[job.ChurnPodDeletion]; !ok {
+			return configSpec, fmt.Errorf("invalid value for churnPodDeletion: %s", job.ChurnPodDeletion)
+		}
+		if job.ChurnPodDeletion == ChurnPodEvict && job.ChurnEvictionTimeout <= 0 {
+			return configSpec, fmt.Errorf("job %s: churnEvictionTimeout must be greater than 0", job.Name)
+		}
+		if _, ok := waiterModes[job.WaiterMode]; !ok {
+			return configSpec, fmt.Errorf("invalid value for waiterMode: %s", job.WaiterMode)
+		}
+		if _, ok := resourceVersionSemantics[job.ListOptions.ResourceVersion]; !ok {
+			return configSpec, fmt.Errorf("invalid value for listOptions.resourceVersion: %s", job.ListOptions.ResourceVersion)
+		}
+		if job.QPSRamp != nil {
+			if _, ok := rampProfiles[job.QPSRamp.Profile]; !ok {
+				return configSpec, fmt.Errorf("invalid value for qpsRamp.profile: %s", job.QPSRamp.Profile)
+			}
+			if job.QPSRamp.StartQPS <= 0 || job.QPSRamp.Duration <= 0 || job.QPSRamp.Steps < 1 {
+				return configSpec, fmt.Errorf("job %s: qpsRamp requires startQPS, duration and steps greater than 0", job.Name)
+			}
+		}
+		if job.ArrivalRate != nil {
+			if _, ok := arrivalDistributions[job.ArrivalRate.Distribution]; !ok {
+				return configSpec, fmt.Errorf("invalid value for arrivalRate.distribution: %s", job.ArrivalRate.Distribution)
+			}
+			if job.JobType != CreationJob {
+				return configSpec, fmt.Errorf("job %s: arrivalRate is only supported in create jobs", job.Name)
+			}
+			if job.QPSRamp != nil {
+				return configSpec, fmt.Errorf("job %s: arrivalRate and qpsRamp are mutually exclusive", job.Name)
+			}
+			if job.ArrivalRate.Rate <= 0 || job.ArrivalRate.MaxInFlight < 0 || job.ArrivalRate.MaxQueued < 0 {
+				return configSpec, fmt.Errorf("job %s: arrivalRate requires a rate greater than 0 and positive maxInFlight and maxQueued", job.Name)
+			}
+		}
+		if job.IterationsPerMinute < 0 {
+			return configSpec, fmt.Errorf("job %s: iterationsPerMinute must be greater than 0", job.Name)
+		}
+		if job.IterationsPerMinute > 0 {
+			if job.JobType != CreationJob {
+				return configSpec, fmt.Errorf("job %s: iterationsPerMinute is only supported in create jobs", job.Name)
+			}
+			if job.ArrivalRate != nil || job.JobIterationDelay > 0 {
+				return configSpec, fmt.Errorf("job %s: iterationsPerMinute can't be combined with arrivalRate or jobIterationDelay", job.Name)
+			}
+		}
+		if job.AdaptiveQPS != nil {
+			if job.QPSRamp != nil || job.ArrivalRate != nil {
+				return configSpec, fmt.Errorf("job %s: adaptiveQPS can't be combined with qpsRamp or arrivalRate", job.Name)
+			}
+			if job.AdaptiveQPS.Interval <= 0 || job.AdaptiveQPS.MinQPS <= 0 || job.AdaptiveQPS.MaxThrottledRequests < 1 {
+				return configSpec, fmt.Errorf("job %s: adaptiveQPS requires interval, minQPS and maxThrottledRequests greater than 0", job.Name)
+			}
+			if job.AdaptiveQPS.DecreaseFactor <= 0 || job.AdaptiveQPS.DecreaseFactor >= 1 {
+				return configSpec, fmt.Errorf("job %s: adaptiveQPS.decreaseFactor must be between 0 and 1", job.Name)
+			}
+		}
+		identityNames := map[string]bool{}
+		for i := range job.Identities {
+			identity := &job.Identities[i]
+			credentials := 0
+			for _, credential := range []string{identity.Token, identity.TokenFile, identity.ServiceAccount, identity.Impersonate} {
+				if credential != "" {
+					credentials++
+				}
+			}
+			if credentials != 1 {
+				return configSpec, fmt.Errorf("job %s: identity %d requires exactly one of token, tokenFile, serviceAccount or impersonate", job.Name, i)
+			}
+			if identity.ServiceAccount != "" && strings.Count(identity.ServiceAccount, "/") != 1 {
+				return configSpec, fmt.Errorf("job %s: identity serviceAccount must be namespace/name: %s", job.Name, identity.ServiceAccount)
+			}
+			if len(identity.ImpersonateGroups) > 0 && identity.Impersonate == "" {
+				return configSpec, fmt.Errorf("job %s: identity impersonateGroups requires impersonate", job.Name)
+			}
+			if identity.Name == "" {
+				switch {
+				case identity.ServiceAccount != "":
+					identity.Name = "system:serviceaccount:" + strings.Replace(identity.ServiceAccount, "/", ":", 1)
+				case identity.Impersonate != "":
+					identity.Name = identity.Impersonate
+				default:
+					identity.Name = fmt.Sprintf("identity-%d", i)
+				}
+			}
+			if identityNames[identity.Name] {
+				return configSpec, fmt.Errorf("job %s: duplicated identity %s", job.Name, identity.Name)
+			}
+			identityNames[identity.Name] = true
+		}
+		if job.WasmHook != nil {
+			if job.JobType != CreationJob {
+				return configSpec, fmt.Errorf("job %s: wasmHook is only supported by create jobs", job.Name)
+			}
+			if job.WasmHook.Module == "" {
+				return configSpec, fmt.Errorf("job %s: wasmHook.module is required", job.Name)
+			}
+			if job.WasmHook.Timeout <= 0 {
+				return configSpec, fmt.Errorf("job %s: wasmHook.timeout must be greater than 0", job.Name)
+			}
+			if _, ok := hookFailurePolicies[job.WasmHook.FailurePolicy]; !ok {
+				return configSpec, fmt.Errorf("invalid value for wasmHook.failurePolicy: %s", job.WasmHook.FailurePolicy)
+			}
+		}
+		for _, artifact := range job.Artifacts {
+			if !path.IsAbs(artifact.Path) {
+				return configSpec, fmt.Errorf("job %s: artifact path must be absolute: %q", job.Name, artifact.Path)
+			}
+			if _, ok := artifactFormats[artifact.Format]; artifact.Format != "" && !ok {
+				return configSpec, fmt.Errorf("invalid value for artifact format: %s", artifact.Format)
+			}
+		}
+		if job.RunIf != nil && job.RunIf.Expr == "" {
+			return configSpec, fmt.Errorf("job %s: runIf requires an expression", job.Name)
+		}
+		if job.JobType == HelmJob {
+			if job.Helm == nil {
+				return configSpec, fmt.Errorf("job %s: helm jobs require a helm chart", job.Name)
+			}
+			if _, ok := helmOperations[job.Helm.Operation]; !ok {
+				return configSpec, fmt.Errorf("invalid value for helm.operation: %s", job.Helm.Operation)
+			}
+			if job.Helm.Operation == HelmInstall && job.Helm.Chart == "" {
+				return configSpec, fmt.Errorf("job %s: helm.chart is required to install releases", job.Name)
+			}
+			if job.Namespace == "" {
+				return configSpec, fmt.Errorf("job %s: helm jobs require a namespace", job.Name)
+			}
+			if job.Helm.Timeout <= 0 {
+				return configSpec, fmt.Errorf("job %s: helm.timeout must be greater than 0", job.Name)
+			}
+			if job.Churn {
+				return configSpec, fmt.Errorf("job %s: churn is not supported in helm jobs", job.Name)
+			}
+		}
+		if job.JobType == FioJob {
+			if job.Fio == nil {
+				return configSpec, fmt.Errorf("job %s: fio jobs require a fio benchmark", job.Name)
+			}
+			if job.Namespace == "" {
+				return configSpec, fmt.Errorf("job %s: fio jobs require a namespace", job.Name)
+			}
+			if job.Fio.PVCTemplate == "" {
+				if _, err := resource.ParseQuantity(job.Fio.VolumeSize); err != nil {
+					return configSpec, fmt.Errorf("job %s: invalid fio.volumeSize %s: %v", job.Name, job.Fio.VolumeSize, err)
+				}
+			}
+			if job.Fio.JobFile == "" && (job.Fio.Runtime <= 0 || job.Fio.IODepth < 1 || job.Fio.NumJobs < 1) {
+				return configSpec, fmt.Errorf("job %s: fio.runtime, fio.ioDepth and fio.numJobs must be greater than 0", job.Name)
+			}
+			if job.Fio.Timeout <= 0 {
+				return configSpec, fmt.Errorf("job %s: fio.timeout must be greater than 0", job.Name)
+			}
+			if job.Churn {
+				return configSpec, fmt.Errorf("job %s: churn is not supported in fio jobs", job.Name)
+			}
+		}
+		if job.JobType == NetworkJob {
+			if job.Network == nil {
+				return configSpec, fmt.Errorf("job %s: network jobs require a network benchmark", job.Name)
+			}
+			if _, ok := networkTopologies[job.Network.Topology]; !ok {
+				return configSpec, fmt.Errorf("invalid value for network.topology: %s", job.Network.Topology)
+			}
+			if _, ok := networkProtocols[job.Network.Protocol]; !ok {
+				return configSpec, fmt.Errorf("invalid value for network.protocol: %s", job.Network.Protocol)
+			}
+			if job.Namespace == "" {
+				return configSpec, fmt.Errorf("job %s: network jobs require a namespace", job.Name)
+			}
+			if job.Network.Duration < time.Second || job.Network.Parallel < 1 || job.Network.MessageSize < 1 {
+				return configSpec, fmt.Errorf("job %s: network.duration must be at least 1s, and network.parallel and network.messageSize greater than 0", job.Name)
+			}
+			if job.Network.Timeout <= 0 {
+				return configSpec, fmt.Errorf("job %s: network.timeout must be greater than 0", job.Name)
+			}
+			if job.Churn {
+				return configSpec, fmt.Errorf("job %s: churn is not supported in network jobs", job.Name)
+			}
+		}
+		if job.JobType == InflateJob {
+			if job.Inflate == nil {
+				return configSpec, fmt.Errorf("job %s: inflate jobs require an inflate spec", job.Name)
+			}
+			if _, ok := inflateTargets[job.Inflate.Target]; !ok {
+				return configSpec, fmt.Errorf("invalid value for inflate.target: %s", job.Inflate.Target)
+			}
+			if job.Inflate.Field == "" || job.Inflate.BytesPerIteration < 1 || job.Inflate.MaxSize < job.Inflate.BytesPerIteration {
+				return configSpec, fmt.Errorf("job %s: inflate.field is required, inflate.bytesPerIteration must be greater than 0 and inflate.maxSize not lower than it", job.Name)
+			}
+			if job.Inflate.Target == InflateAnnotation {
+				if errs := validation.IsQualifiedName(job.Inflate.Field); len(errs) > 0 {
+					return configSpec, fmt.Errorf("job %s: invalid inflate.field %s: %s", job.Name, job.Inflate.Field, strings.Join(errs, ", "))
+				}
+			}
+			for _, o := range job.Objects {
+				if job.Inflate.Target == InflateData && o.Kind != "ConfigMap" {
+					return configSpec, fmt.Errorf("job %s: the data inflate target only supports ConfigMaps, got %s", job.Name, o.Kind)
+				}
+			}
+			if job.Churn {
+				return configSpec, fmt.Errorf("job %s: churn is not supported in inflate jobs", job.Name)
+			}
+		}
+		if job.WaitFor != nil {
+			if job.WaitFor.Expr == "" {
+				return configSpec, fmt.Errorf("job %s: waitFor requires an expression", job.Name)
+			}
+			if job.WaitFor.Interval <= 0 || job.WaitFor.Timeout <= 0 || job.WaitFor.StableFor < 0 {
+				return configSpec, fmt.Errorf("job %s: waitFor.interval and waitFor.timeout must be greater than 0", job.Name)
+			}
+			if _, ok := hookFailurePolicies[job.WaitFor.FailurePolicy]; !ok {
+				return configSpec, fmt.Errorf("invalid value for waitFor.failurePolicy: %s", job.WaitFor.FailurePolicy)
+			}
+		}
+		if job.Naming != nil {
+			if job.JobType != CreationJob {
+				return configSpec, fmt.Errorf("job %s: naming is only supported in create jobs", job.Name)
+			}
+			if job.Naming.MaxLength == 0 {
+				configSpec.Jobs[i].Naming.MaxLength = validation.DNS1123SubdomainMaxLength
+			}
+			if job.Naming.Prefix != "" && len(validation.IsDNS1123Label(strings.TrimRight(job.Naming.Prefix, "-"))) > 0 {
+				return configSpec, fmt.Errorf("job %s: naming.prefix must consist of lower case alphanumeric characters or '-'", job.Name)
+			}
+			if job.Naming.IndexPadding < 0 || job.Naming.IndexPadding > 10 || job.Naming.HashSuffix < 0 || job.Naming.HashSuffix > 16 {
+				return configSpec, fmt.Errorf("job %s: naming.indexPadding must be between 0 and 10 and naming.hashSuffix between 0 and 16", job.Name)
+			}
+			if job.Naming.MaxLength < 0 || job.Naming.MaxLength > validation.DNS1123SubdomainMaxLength {
+				return configSpec, fmt.Errorf("job %s: naming.maxLength must be between 1 and %d", job.Name, validation.DNS1123SubdomainMaxLength)
+			}
+		}
+		for name, hook := range map[string]*JobHook{"preHook": job.PreHook, "postHook": job.PostHook} {
+			if hook == nil {
+				continue
+			}
+			var actions int
+			for _, defined := range []bool{len(hook.Command) > 0, hook.Manifest != "", hook.Job != ""} {
+				if defined {
+					actions++
+				}
+			}
+			if actions != 1 {
+				return configSpec, fmt.Errorf("job %s: %s must define one of command, manifest or job", job.Name, name)
+			}
+			if _, ok := hookFailurePolicies[hook.FailurePolicy]; !ok {
+				return configSpec, fmt.Errorf("invalid value for %s.failurePolicy: %s", name, hook.FailurePolicy)
+			}
+			if hook.Timeout <= 0 {
+				return configSpec, fmt.Errorf("job %s: %s.timeout must be greater than 0", job.Name, name)
+			}
+		}
+		if job.ListOptions.Limit < 0 {
+			return configSpec, fmt.Errorf("job %s: listOptions.limit must be a positive number", job.Name)
+		}
+		if job.MaxErrorRate < 0 || job.MaxErrorRate > 100 {
+			return configSpec, fmt.Errorf("job %s: maxErrorRate must be a percentage between 0 and 100", job.Name)
+		}
+		for _, selector := range []string{job.TargetNodes, job.ExcludeNodes} {
+			if _, err := labels.Parse(selector); err != nil {
+				return configSpec, fmt.Errorf("job %s: invalid node selector %q: %v", job.Name, selector, err)
+			}
+		}
+		if errs := validation.IsValidLabelValue(job.Architecture); len(errs) > 0 {
+			return configSpec, fmt.Errorf("job %s: invalid architecture %q: %s", job.Name, job.Architecture, strings.Join(errs, ", "))
+		}
+		if job.SyntheticImages != nil {
+			if job.SyntheticImages.Repository == "" || job.SyntheticImages.Count < 1 || job.SyntheticImages.Parallelism < 1 {
+				return configSpec, fmt.Errorf("job %s: syntheticImages requires a repository, and count and parallelism greater than 0", job.Name)
+			}
+			if len(job.SyntheticImages.Sizes) == 0 {
+				return configSpec, fmt.Errorf("job %s: syntheticImages requires at least one size", job.Name)
+			}
+			for _, size := range job.SyntheticImages.Sizes {
+				if q, err := resource.ParseQuantity(size); err != nil || q.Sign() <= 0 {
+					return configSpec, fmt.Errorf("job %s: invalid syntheticImages size %s", job.Name, size)
+				}
+			}
+		}
+		if job.JobType == DeletionJob {
+			configSpec.Jobs[i].PreLoadImages = false
+		}
+	}
+	if err := buildKustomizations(); err != nil {
+		return configSpec, err
+	}
+	configSpec.GlobalConfig.Timeout = timeout
+	configSpec.GlobalConfig.UUID = uuid
+	configSpec.GlobalConfig.RUNID = uid.NewString()
+	return configSpec, nil
+}
+
+func NewKubeClientProvider(config, context string) *KubeClientProvider {
+	var kubeConfigPath string
+	if config != "" {
+		kubeConfigPath = config
+	} else if os.Getenv("KUBECONFIG") != "" {
+		kubeConfigPath = os.Getenv("KUBECONFIG")
+	} else if _, err := os.Stat(filepath.Join(os.Getenv("HOME"), ".kube", "config")); kubeConfigPath == "" && !os.IsNotExist(err) {
+		kubeConfigPath = filepath.Join(os.Getenv("HOME"), ".kube", "config")
+	}
+	var restConfig *rest.Config
+	var err error
+	if kubeConfigPath == "" {
+		if restConfig, err = rest.InClusterConfig(); err != nil {
+			log.Fatalf("error preparing kubernetes client: %s", err)
+		}
+	} else {
+		kubeConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeConfigPath},
+			&clientcmd.ConfigOverrides{CurrentContext: context},
+		)
+		if restConfig, err = kubeConfig.ClientConfig(); err != nil {
+			log.Fatalf("error preparing kubernetes client: %s", err)
 		}
 	}
-	return &KubeClientProvider{restConfig: restConfig}
+	return &KubeClientProvider{restConfig: restConfig, kubeConfigPath: kubeConfigPath, kubeContext: context}
+}
+
+// KubeConfig returns the kubeconfig file and context of the provider, the file is empty with in-cluster configurations
+func (p *KubeClientProvider) KubeConfig() (string, string) {
+	return p.kubeConfigPath, p.kubeContext
 }
 
 func (p *KubeClientProvider) DefaultClientSet() (kubernetes.Interface, *rest.Config) {
 	restConfig := *p.restConfig
+	p.applyTransport(&restConfig)
 	return kubernetes.NewForConfigOrDie(&restConfig), &restConfig
 }
 
@@ -251,13 +1067,47 @@ func (p *KubeClientProvider) ClientSet(QPS float32, burst int) (kubernetes.Inter
 	restConfig := *p.restConfig
 	restConfig.QPS, restConfig.Burst = QPS, burst
 	restConfig.Timeout = configSpec.GlobalConfig.RequestTimeout
+	p.applyTransport(&restConfig)
 	return kubernetes.NewForConfigOrDie(&restConfig), &restConfig
 }
 
+// applyTransport applies the client transport settings to the REST config. Idle connections and keep-alives can't be
+// set through the REST config, so when they differ from the client-go defaults the clients share a custom transport
+func (p *KubeClientProvider) applyTransport(restConfig *rest.Config) {
+	clientTransport := configSpec.GlobalConfig.ClientTransport
+	if clientTransport.ContentType == ContentTypeProtobuf {
+		// Custom resources don't support protobuf, the API server falls back to JSON for them
+		restConfig.ContentType = runtime.ContentTypeProtobuf
+		restConfig.AcceptContentTypes = runtime.ContentTypeProtobuf + "," + runtime.ContentTypeJSON
+	}
+	if clientTransport.DisableHTTP2 {
+		restConfig.NextProtos = []string{"http/1.1"}
+	}
+	if clientTransport.MaxIdleConns == 0 && clientTransport.MaxIdleConnsPerHost == defaultMaxIdleConnsPerHost && clientTransport.KeepAlive == defaultKeepAlive {
+		return
+	}
+	p.transportOnce.Do(func() {
+		tlsConfig, err := rest.TLSConfigFor(restConfig)
+		if err != nil {
+			log.Fatalf("error preparing kubernetes client transport: %s", err)
+		}
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: clientTransport.KeepAlive}
+		p.transport = utilnet.SetTransportDefaults(&http.Transport{
+			TLSHandshakeTimeout: 10 * time.Second,
+			TLSClientConfig:     tlsConfig,
+			MaxIdleConns:        clientTransport.MaxIdleConns,
+			MaxIdleConnsPerHost: clientTransport.MaxIdleConnsPerHost,
+			DialContext:         dialer.DialContext,
+		})
+	})
+	// The TLS settings are part of the custom transport, client-go rejects setting both
+	restConfig.Transport = p.transport
+	restConfig.TLSClientConfig = rest.TLSClientConfig{}
+}
+
 // FetchConfigMap Fetchs the specified configmap and looks for config.yml, metrics.yml and alerts.yml files
 func FetchConfigMap(configMap, namespace string) (string, string, error) {
-	log.Infof("Fetching configmap %s", configMap)
-	var kubeconfig, metricProfile, alertProfile string
+	var kubeconfig string
 	if os.Getenv("KUBECONFIG") != "" {
 		kubeconfig = os.Getenv("KUBECONFIG")
 	} else if _, err := os.Stat(filepath.Join(os.Getenv("HOME"), ".kube", "config")); kubeconfig == "" && !os.IsNotExist(err) {
@@ -265,24 +1115,31 @@ func FetchConfigMap(configMap, namespace string) (string, string, error) {
 	}
 	restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
 	if err != nil {
-		return metricProfile, alertProfile, err
+		return "", "", err
 	}
-	clientSet := kubernetes.NewForConfigOrDie(restConfig)
+	// We write the configMap data into the CWD
+	return WriteConfigMap(kubernetes.NewForConfigOrDie(restConfig), configMap, namespace, "")
+}
+
+// WriteConfigMap writes the files of the specified configmap into the given directory, and returns the paths of
+// its metrics.yml and alerts.yml files, empty when the configmap doesn't have them
+func WriteConfigMap(clientSet kubernetes.Interface, configMap, namespace, directory string) (string, string, error) {
+	log.Infof("Fetching configmap %s", configMap)
+	var metricProfile, alertProfile string
 	configMapData, err := clientSet.CoreV1().ConfigMaps(namespace).Get(context.TODO(), configMap, v1.GetOptions{})
 	if err != nil {
 		return metricProfile, alertProfile, err
 	}
-
 	for name, data := range configMapData.Data {
-		// We write the configMap data into the CWD
-		if err := os.WriteFile(name, []byte(data), 0644); err != nil {
+		path := filepath.Join(directory, name)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
 			return metricProfile, alertProfile, fmt.Errorf("error writing configmap into disk: %v", err)
 		}
 		if name == "metrics.yml" {
-			metricProfile = "metrics.yml"
+			metricProfile = path
 		}
 		if name == "alerts.yml" {
-			alertProfile = "alerts.yml"
+			alertProfile = path
 		}
 	}
 	return metricProfile, alertProfile, nil
@@ -313,6 +1170,162 @@ func jobIsDuped() error {
 	return nil
 }
 
+// orderJobs sorts the jobs so that each one runs after its dependencies, otherwise keeping the declared order
+func orderJobs() error {
+	jobs := make(map[string]Job, len(configSpec.Jobs))
+	for _, job := range configSpec.Jobs {
+		jobs[job.Name] = job
+	}
+	for _, job := range configSpec.Jobs {
+		for _, dependency := range job.DependsOn {
+			if _, ok := jobs[dependency]; !ok || dependency == job.Name {
+				return fmt.Errorf("job %s depends on an invalid job: %s", job.Name, dependency)
+			}
+		}
+	}
+	ordered := make([]Job, 0, len(configSpec.Jobs))
+	placed := make(map[string]bool, len(configSpec.Jobs))
+	for len(ordered) < len(configSpec.Jobs) {
+		progress := false
+		for _, job := range configSpec.Jobs {
+			pending := slices.ContainsFunc(job.DependsOn, func(dependency string) bool { return !placed[dependency] })
+			if placed[job.Name] || pending {
+				continue
+			}
+			ordered = append(ordered, job)
+			placed[job.Name] = true
+			progress = true
+			// Start over so that the declared order is kept among the jobs ready to run
+			break
+		}
+		if !progress {
+			return fmt.Errorf("circular dependency between jobs")
+		}
+	}
+	configSpec.Jobs = ordered
+	return nil
+}
+
+// Control plane metrics scraped by default in each phase of the mesh overhead jobs
+var meshControlPlaneQueries = map[MeshProvider]map[string]string{
+	MeshIstio: {
+		"istiodCPU":    `sum(rate(container_cpu_usage_seconds_total{namespace="istio-system",container="discovery"}[{{.Duration}}]))`,
+		"istiodMemory": `max_over_time(sum(container_memory_working_set_bytes{namespace="istio-system",container="discovery"})[{{.Duration}}:])`,
+	},
+	MeshLinkerd: {
+		"controlPlaneCPU":    `sum(rate(container_cpu_usage_seconds_total{namespace="linkerd",container!=""}[{{.Duration}}]))`,
+		"controlPlaneMemory": `max_over_time(sum(container_memory_working_set_bytes{namespace="linkerd",container!=""})[{{.Duration}}:])`,
+	},
+}
+
+// splitMeshOverheadJobs replaces each mesh overhead job by its two phases: a baseline job creating its namespaces
+// with sidecar injection disabled, followed by a mesh job enabling it. Jobs depending on the original job depend on the mesh phase
+func splitMeshOverheadJobs() error {
+	var jobs []Job
+	renamed := make(map[string]string)
+	for _, job := range configSpec.Jobs {
+		if job.MeshOverhead == nil {
+			jobs = append(jobs, job)
+			continue
+		}
+		if _, ok := meshProviders[job.MeshOverhead.Provider]; !ok {
+			return fmt.Errorf("invalid value for meshOverhead.provider: %s", job.MeshOverhead.Provider)
+		}
+		if job.JobType != CreationJob {
+			return fmt.Errorf("job %s: meshOverhead is only supported in create jobs", job.Name)
+		}
+		for _, phase := range []MeshPhase{MeshBaseline, MeshSidecar} {
+			phaseJob := job
+			phaseJob.Name = fmt.Sprintf("%s-%s", job.Name, phase)
+			if job.Namespace != "" {
+				phaseJob.Namespace = fmt.Sprintf("%s-%s", job.Namespace, phase)
+			}
+			phaseJob.NamespaceLabels = maps.Clone(job.NamespaceLabels)
+			phaseJob.NamespaceAnnotations = maps.Clone(job.NamespaceAnnotations)
+			injection := "disabled"
+			if phase == MeshSidecar {
+				injection = "enabled"
+			}
+			switch job.MeshOverhead.Provider {
+			case MeshIstio:
+				if phaseJob.NamespaceLabels == nil {
+					phaseJob.NamespaceLabels = make(map[string]string)
+				}
+				phaseJob.NamespaceLabels["istio-injection"] = injection
+			case MeshLinkerd:
+				if phaseJob.NamespaceAnnotations == nil {
+					phaseJob.NamespaceAnnotations = make(map[string]string)
+				}
+				phaseJob.NamespaceAnnotations["linkerd.io/inject"] = injection
+			}
+			meshOverhead := *job.MeshOverhead
+			meshOverhead.Job = job.Name
+			meshOverhead.Phase = phase
+			if meshOverhead.ControlPlaneQueries == nil {
+				meshOverhead.ControlPlaneQueries = meshControlPlaneQueries[meshOverhead.Provider]
+			}
+			phaseJob.MeshOverhead = &meshOverhead
+			jobs = append(jobs, phaseJob)
+		}
+		renamed[job.Name] = fmt.Sprintf("%s-%s", job.Name, MeshSidecar)
+	}
+	for i := range jobs {
+		jobs[i].DependsOn = slices.Clone(jobs[i].DependsOn)
+		for j, dependency := range jobs[i].DependsOn {
+			if name, ok := renamed[dependency]; ok {
+				jobs[i].DependsOn[j] = name
+			}
+		}
+	}
+	configSpec.Jobs = jobs
+	return nil
+}
+
+// splitSchedulerCacheJobs replaces each scheduler cache job by its two phases: a cold job, run right after restarting
+// kube-scheduler, followed by a warm one. Jobs depending on the original job depend on the warm phase
+func splitSchedulerCacheJobs() error {
+	var jobs []Job
+	renamed := make(map[string]string)
+	for _, job := range configSpec.Jobs {
+		if job.SchedulerCache == nil {
+			jobs = append(jobs, job)
+			continue
+		}
+		if job.JobType != CreationJob {
+			return fmt.Errorf("job %s: schedulerCache is only supported in create jobs", job.Name)
+		}
+		if job.MeshOverhead != nil {
+			return fmt.Errorf("job %s: schedulerCache and meshOverhead can't be combined", job.Name)
+		}
+		if _, err := labels.Parse(job.SchedulerCache.LabelSelector); err != nil {
+			return fmt.Errorf("job %s: invalid schedulerCache labelSelector: %v", job.Name, err)
+		}
+		for _, phase := range []SchedulerCachePhase{SchedulerCacheCold, SchedulerCacheWarm} {
+			phaseJob := job
+			phaseJob.Name = fmt.Sprintf("%s-%s", job.Name, phase)
+			if job.Namespace != "" {
+				phaseJob.Namespace = fmt.Sprintf("%s-%s", job.Namespace, phase)
+			}
+			schedulerCache := *job.SchedulerCache
+			schedulerCache.Job = job.Name
+			schedulerCache.Phase = phase
+			phaseJob.SchedulerCache = &schedulerCache
+			jobs = append(jobs, phaseJob)
+		}
+		renamed[job.Name] = fmt.Sprintf("%s-%s", job.Name, SchedulerCacheWarm)
+	}
+	for i := range jobs {
+		jobs[i].DependsOn = slices.Clone(jobs[i].DependsOn)
+		for j, dependency := range jobs[i].DependsOn {
+			if name, ok := renamed[dependency]; ok {
+				jobs[i].DependsOn[j] = name
+			}
+		}
+	}
+	configSpec.Jobs = jobs
+	return nil
+}
+
 // validateGC checks if GC and global waitWhenFinished are enabled at the same time
 func validateGC() error {
 	if !configSpec.GlobalConfig.WaitWhenFinished {
@@ -325,3 +1338,313 @@ func validateGC() error {
 	}
 	return nil
 }
+
+// validateDisruptionWindows checks that all disruption windows are well defined
+func validateDisruptionWindows() error {
+	for _, dw := range configSpec.GlobalConfig.DisruptionWindows {
+		if dw.Start.IsZero() || dw.End.IsZero() {
+			return fmt.Errorf("disruption window %q requires both start and end", dw.Description)
+		}
+		if dw.End.Before(dw.Start) {
+			return fmt.Errorf("disruption window %q ends before it starts", dw.Description)
+		}
+	}
+	return nil
+}
+
+// validateExitHooks checks that each exit hook defines either a command or a URL
+func validateExitHooks() error {
+	for i, hook := range configSpec.GlobalConfig.ExitHooks {
+		if (len(hook.Command) == 0) == (hook.URL == "") {
+			return fmt.Errorf("exit hook %d must define either command or url", i)
+		}
+	}
+	return nil
+}
+
+// validateCost checks the cost allocation API settings
+func validateCost() error {
+	cost := configSpec.GlobalConfig.Cost
+	if cost == nil {
+		return nil
+	}
+	if _, ok := costProviders[cost.Provider]; !ok {
+		return fmt.Errorf("invalid cost provider %s", cost.Provider)
+	}
+	if cost.Endpoint == "" {
+		return fmt.Errorf("cost endpoint is required")
+	}
+	return nil
+}
+
+// validateClientMetrics checks the sampling interval and the saturation thresholds
+func validateClientMetrics() error {
+	clientMetrics := configSpec.GlobalConfig.ClientMetrics
+	if clientMetrics == nil {
+		return nil
+	}
+	if clientMetrics.Interval <= 0 {
+		return fmt.Errorf("clientMetrics interval must be greater than 0")
+	}
+	if clientMetrics.CPUThreshold <= 0 || clientMetrics.CPUThreshold > 100 {
+		return fmt.Errorf("clientMetrics cpuThreshold must be between 0 and 100")
+	}
+	if clientMetrics.MemoryThreshold <= 0 || clientMetrics.MemoryThreshold > 100 {
+		return fmt.Errorf("clientMetrics memoryThreshold must be between 0 and 100")
+	}
+	return nil
+}
+
+// validateClientTransport checks the client transport settings
+func validateClientTransport() error {
+	clientTransport := configSpec.GlobalConfig.ClientTransport
+	if _, ok := contentTypes[clientTransport.ContentType]; !ok {
+		return fmt.Errorf("unsupported clientTransport contentType %s", clientTransport.ContentType)
+	}
+	if clientTransport.MaxIdleConns < 0 || clientTransport.MaxIdleConnsPerHost < 0 {
+		return fmt.Errorf("clientTransport maxIdleConns and maxIdleConnsPerHost can't be negative")
+	}
+	return nil
+}
+
+// validateGitHubReport checks that the GitHub report has a destination and a token
+func validateGitHubReport() error {
+	gitHubReport := configSpec.GlobalConfig.GitHubReport
+	if gitHubReport == nil {
+		return nil
+	}
+	if _, ok := gitHubReportModes[gitHubReport.Mode]; !ok {
+		return fmt.Errorf("invalid gitHubReport mode %s", gitHubReport.Mode)
+	}
+	if owner, name, ok := strings.Cut(gitHubReport.Repository, "/"); !ok || owner == "" || name == "" {
+		return fmt.Errorf("gitHubReport repository must be in owner/name form, got %q", gitHubReport.Repository)
+	}
+	if gitHubReport.Mode == GitHubCheckRun && gitHubReport.SHA == "" {
+		return fmt.Errorf("gitHubReport sha is required by check runs")
+	}
+	if gitHubReport.Mode == GitHubComment && gitHubReport.PullRequest <= 0 {
+		return fmt.Errorf("gitHubReport pullRequest is required by pull request comments")
+	}
+	if gitHubReport.Token == "" {
+		return fmt.Errorf("gitHubReport token is required, either in the configuration or in the GITHUB_TOKEN environment variable")
+	}
+	return nil
+}
+
+// validateNodeWatchdog checks the polling interval, the conditions and the action of the node watchdog
+func validateNodeWatchdog() error {
+	nodeWatchdog := configSpec.GlobalConfig.NodeWatchdog
+	if nodeWatchdog == nil {
+		return nil
+	}
+	if nodeWatchdog.Interval <= 0 {
+		return fmt.Errorf("nodeWatchdog interval must be greater than 0")
+	}
+	if len(nodeWatchdog.Conditions) == 0 {
+		return fmt.Errorf("nodeWatchdog requires at least one condition")
+	}
+	if _, err := labels.Parse(nodeWatchdog.LabelSelector); err != nil {
+		return fmt.Errorf("invalid nodeWatchdog labelSelector: %v", err)
+	}
+	if nodeWatchdog.MaxUnhealthyRatio <= 0 || nodeWatchdog.MaxUnhealthyRatio > 1 {
+		return fmt.Errorf("nodeWatchdog maxUnhealthyRatio must be between 0 and 1")
+	}
+	if _, ok := nodeWatchdogActions[nodeWatchdog.Action]; !ok {
+		return fmt.Errorf("invalid nodeWatchdog action %s", nodeWatchdog.Action)
+	}
+	if nodeWatchdog.Action == NodeWatchdogPause && nodeWatchdog.PauseTimeout <= 0 {
+		return fmt.Errorf("nodeWatchdog pauseTimeout must be greater than 0")
+	}
+	return nil
+}
+
+// validateClusterHealthMonitor checks the interval, the thresholds and the policy of the cluster health monitor
+func validateClusterHealthMonitor() error {
+	clusterHealthMonitor := configSpec.GlobalConfig.ClusterHealthMonitor
+	if clusterHealthMonitor == nil {
+		return nil
+	}
+	if clusterHealthMonitor.Interval <= 0 {
+		return fmt.Errorf("clusterHealthMonitor interval must be greater than 0")
+	}
+	if clusterHealthMonitor.MaxUnhealthyNodes < 0 {
+		return fmt.Errorf("clusterHealthMonitor maxUnhealthyNodes must be greater than or equal to 0")
+	}
+	if clusterHealthMonitor.MaxFailedChecks < 1 {
+		return fmt.Errorf("clusterHealthMonitor maxFailedChecks must be greater than 0")
+	}
+	if _, ok := clusterHealthPolicies[clusterHealthMonitor.Policy]; !ok {
+		return fmt.Errorf("invalid clusterHealthMonitor policy %s", clusterHealthMonitor.Policy)
+	}
+	return nil
+}
+
+// validateAnomalyDetection checks the sensitivity of the anomaly detection
+func validateAnomalyDetection() error {
+	anomalyDetection := configSpec.GlobalConfig.AnomalyDetection
+	if anomalyDetection == nil {
+		return nil
+	}
+	if anomalyDetection.Threshold <= 0 {
+		return fmt.Errorf("anomalyDetection threshold must be greater than 0")
+	}
+	if anomalyDetection.MinSegment < 2 {
+		return fmt.Errorf("anomalyDetection minSegment must be at least 2")
+	}
+	if anomalyDetection.SpikeThreshold < 0 {
+		return fmt.Errorf("anomalyDetection spikeThreshold must be greater than or equal to 0")
+	}
+	return nil
+}
+
+// validateClusterSnapshot checks that the captured components and resources are fully identified
+func validateClusterSnapshot() error {
+	clusterSnapshot := configSpec.GlobalConfig.ClusterSnapshot
+	if clusterSnapshot == nil {
+		return nil
+	}
+	for _, component := range clusterSnapshot.Components {
+		if component.Name == "" || component.Namespace == "" {
+			return fmt.Errorf("clusterSnapshot components require a name and a namespace")
+		}
+		if _, err := labels.Parse(component.LabelSelector); err != nil {
+			return fmt.Errorf("clusterSnapshot component %s: invalid labelSelector %q: %v", component.Name, component.LabelSelector, err)
+		}
+	}
+	for _, resource := range clusterSnapshot.Resources {
+		if resource.Version == "" || resource.Resource == "" || resource.Name == "" {
+			return fmt.Errorf("clusterSnapshot resources require a version, a resource and a name")
+		}
+	}
+	return nil
+}
+
+// validateProductionGuard checks that at least one production marker is set and the API server expressions compile
+func validateProductionGuard() error {
+	productionGuard := configSpec.GlobalConfig.ProductionGuard
+	if productionGuard == nil {
+		return nil
+	}
+	if productionGuard.MaxNodes < 0 {
+		return fmt.Errorf("productionGuard.maxNodes must be greater than or equal to 0")
+	}
+	if productionGuard.MaxNodes == 0 && len(productionGuard.Labels) == 0 && len(productionGuard.Annotations) == 0 && len(productionGuard.APIServers) == 0 && !productionGuard.AuditLogging {
+		return fmt.Errorf("productionGuard requires at least one of maxNodes, labels, annotations, apiServers or auditLogging")
+	}
+	for _, apiServer := range productionGuard.APIServers {
+		if _, err := regexp.Compile(apiServer); err != nil {
+			return fmt.Errorf("productionGuard: invalid apiServers expression %q: %v", apiServer, err)
+		}
+	}
+	return nil
+}
+
+// ValidateAlertNotification checks the destination and the severities of an alert notification
+func ValidateAlertNotification(notification AlertNotification) error {
+	switch notification.Type {
+	case AlertNotificationSlack, AlertNotificationWebhook:
+		if notification.URL == "" {
+			return fmt.Errorf("alertNotifications: %s notifications require url", notification.Type)
+		}
+	case AlertNotificationPagerDuty:
+		if notification.RoutingKey == "" {
+			return fmt.Errorf("alertNotifications: pagerduty notifications require routingKey")
+		}
+	default:
+		return fmt.Errorf("alertNotifications: invalid type %q", notification.Type)
+	}
+	for _, severity := range notification.Severities {
+		if !slices.Contains(alertSeverities, severity) {
+			return fmt.Errorf("alertNotifications: invalid severity %q, valid values are %v", severity, alertSeverities)
+		}
+	}
+	return nil
+}
+
+// validateAlertmanager checks that the Alertmanager is reached either by URL or through its service
+func validateAlertmanager() error {
+	alertmanager := configSpec.GlobalConfig.Alertmanager
+	if alertmanager == nil {
+		return nil
+	}
+	if (alertmanager.URL == "") == (alertmanager.Service == "") {
+		return fmt.Errorf("alertmanager requires exactly one of url or service")
+	}
+	if alertmanager.Service != "" {
+		namespace, nameAndPort, _ := strings.Cut(alertmanager.Service, "/")
+		if name, port, ok := strings.Cut(nameAndPort, ":"); namespace == "" || name == "" || !ok || port == "" {
+			return fmt.Errorf("alertmanager service must be namespace/name:port: %s", alertmanager.Service)
+		}
+		if len(alertmanager.Filter) > 1 {
+			return fmt.Errorf("alertmanager filter takes a single matcher when reached through its service")
+		}
+	}
+	if alertmanager.Interval <= 0 {
+		return fmt.Errorf("alertmanager interval must be greater than 0")
+	}
+	return nil
+}
+
+// validateLiveReconfiguration checks that the live reconfiguration endpoint is only left unauthenticated on loopback addresses
+func validateLiveReconfiguration() error {
+	liveReconfiguration := configSpec.GlobalConfig.LiveReconfiguration
+	if liveReconfiguration == nil || liveReconfiguration.Token != "" {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(liveReconfiguration.Address)
+	if err != nil {
+		return fmt.Errorf("invalid liveReconfiguration address %s: %v", liveReconfiguration.Address, err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("liveReconfiguration requires a token when listening on the non-loopback address %s", liveReconfiguration.Address)
+	}
+	return nil
+}
+
+// validateBudget checks that the budget limits are valid quantities
+func validateBudget() error {
+	budget := configSpec.GlobalConfig.Budget
+	if budget == nil {
+		return nil
+	}
+	if budget.MaxPods < 0 {
+		return fmt.Errorf("budget maxPods must be greater than or equal to 0")
+	}
+	for field, value := range map[string]string{
+		"maxCPURequests":    budget.MaxCPURequests,
+		"maxMemoryRequests": budget.MaxMemoryRequests,
+		"maxPVCStorage":     budget.MaxPVCStorage,
+	} {
+		if value == "" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(value); err != nil {
+			return fmt.Errorf("invalid budget %s %s: %v", field, value, err)
+		}
+	}
+	return nil
+}
+
+// Disruptions returns the description of the disruption windows overlapping the given time range
+func (g GlobalConfig) Disruptions(start, end time.Time) []string {
+	var disruptions []string
+	for _, dw := range g.DisruptionWindows {
+		if !start.After(dw.End) && !end.Before(dw.Start) {
+			disruptions = append(disruptions, dw.Description)
+		}
+	}
+	return disruptions
+}
+
+// ValidateWorkers checks that the jobs can be sharded across the given number of worker processes
+func (s Spec) ValidateWorkers(workers int) error {
+	if workers <= 1 {
+		return nil
+	}
+	for _, job := range s.Jobs {
+		if job.JobType == CreationJob && job.Churn {
+			return fmt.Errorf("job %s: churn is not supported in worker mode", job.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOrderJobs(t *testing.T) {
+	tests := []struct {
+		name     string
+		jobs     []Job
+		expected []string
+		fails    bool
+	}{
+		{
+			name:     "no dependencies keep the declared order",
+			jobs:     []Job{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+			expected: []string{"a", "b", "c"},
+		},
+		{
+			name:     "dependencies run first",
+			jobs:     []Job{{Name: "a", DependsOn: []string{"c"}}, {Name: "b"}, {Name: "c"}},
+			expected: []string{"b", "c", "a"},
+		},
+		{
+			name:     "transitive dependencies",
+			jobs:     []Job{{Name: "a", DependsOn: []string{"b"}}, {Name: "b", DependsOn: []string{"c"}}, {Name: "c"}, {Name: "d"}},
+			expected: []string{"c", "b", "a", "d"},
+		},
+		{
+			name:     "several dependencies",
+			jobs:     []Job{{Name: "a", DependsOn: []string{"b", "c"}}, {Name: "b"}, {Name: "c"}},
+			expected: []string{"b", "c", "a"},
+		},
+		{
+			name:  "unknown dependency",
+			jobs:  []Job{{Name: "a", DependsOn: []string{"z"}}},
+			fails: true,
+		},
+		{
+			name:  "self dependency",
+			jobs:  []Job{{Name: "a", DependsOn: []string{"a"}}},
+			fails: true,
+		},
+		{
+			name:  "circular dependency",
+			jobs:  []Job{{Name: "a", DependsOn: []string{"b"}}, {Name: "b", DependsOn: []string{"a"}}},
+			fails: true,
+		},
+	}
+	defer func() { configSpec = defaultSpec() }()
+	for _, tt := range tests {
+		configSpec.Jobs = tt.jobs
+		err := orderJobs()
+		if tt.fails {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		var names []string
+		for _, job := range configSpec.Jobs {
+			names = append(names, job.Name)
+		}
+		if !slices.Equal(names, tt.expected) {
+			t.Errorf("%s: got %v, expected %v", tt.name, names, tt.expected)
+		}
+	}
+}
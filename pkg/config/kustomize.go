@@ -0,0 +1,179 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/kube-burner/kube-burner/pkg/util"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// Variables rendered per iteration and replica, the patch templates render them back to themselves,
+// so that they're rendered along with the rest of the object templates
+var deferredTemplateVars = []string{"JobName", "Iteration", "Replica", "PaddedIteration", "PaddedReplica", "UUID", "RunID"}
+
+// buildKustomizations replaces the objects of the jobs built from a kustomization by one object per resource
+// built, sharing the rest of the object settings. The resources are written to a temporary directory
+func buildKustomizations() error {
+	var tmpDir string
+	for i, job := range configSpec.Jobs {
+		var objects []Object
+		for objectIndex, o := range job.Objects {
+			if o.KustomizeDir == "" {
+				if len(o.KustomizePatches) > 0 {
+					return fmt.Errorf("job %s: kustomizePatches requires kustomizeDir", job.Name)
+				}
+				objects = append(objects, o)
+				continue
+			}
+			if job.JobType != CreationJob {
+				return fmt.Errorf("job %s: kustomizeDir is only supported in create jobs", job.Name)
+			}
+			if o.ObjectTemplate != "" {
+				return fmt.Errorf("job %s: objectTemplate and kustomizeDir are mutually exclusive", job.Name)
+			}
+			if tmpDir == "" {
+				var err error
+				if tmpDir, err = os.MkdirTemp("", "kube-burner-kustomize-"); err != nil {
+					return err
+				}
+			}
+			resources, err := buildKustomization(o, tmpDir)
+			if err != nil {
+				return fmt.Errorf("job %s: error building kustomization %s: %v", job.Name, o.KustomizeDir, err)
+			}
+			log.Infof("Job %s: kustomization %s built into %d object templates", job.Name, o.KustomizeDir, len(resources))
+			for resourceIndex, resource := range resources {
+				template := filepath.Join(tmpDir, fmt.Sprintf("%s-%d-%d.yml", job.Name, objectIndex, resourceIndex))
+				if err := os.WriteFile(template, resource, 0644); err != nil {
+					return err
+				}
+				object := o
+				object.ObjectTemplate = template
+				object.KustomizeDir = ""
+				object.KustomizePatches = nil
+				objects = append(objects, object)
+			}
+		}
+		configSpec.Jobs[i].Objects = objects
+	}
+	return nil
+}
+
+// buildKustomization builds the kustomization and returns its resources. Patches are rendered with the object input
+// variables and applied by a kustomization wrapping the original one
+func buildKustomization(o Object, tmpDir string) ([][]byte, error) {
+	dir := o.KustomizeDir
+	if len(o.KustomizePatches) > 0 {
+		var err error
+		if dir, err = wrapKustomization(o, tmpDir); err != nil {
+			return nil, err
+		}
+	}
+	var cmd *exec.Cmd
+	if _, err := exec.LookPath("kustomize"); err == nil {
+		cmd = exec.Command("kustomize", "build", dir)
+	} else {
+		cmd = exec.Command("kubectl", "kustomize", dir)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return splitDocuments(out), nil
+}
+
+// wrapKustomization writes a kustomization with the rendered patches, whose resource is the original kustomization
+func wrapKustomization(o Object, tmpDir string) (string, error) {
+	wrapperDir, err := os.MkdirTemp(tmpDir, "overlay-")
+	if err != nil {
+		return "", err
+	}
+	resource := o.KustomizeDir
+	if _, err := os.Stat(resource); err == nil {
+		if resource, err = filepath.Abs(resource); err != nil {
+			return "", err
+		}
+	}
+	templateData := make(map[string]any)
+	for _, v := range deferredTemplateVars {
+		templateData[v] = fmt.Sprintf("{{.%s}}", v)
+	}
+	maps.Copy(templateData, o.InputVars)
+	kustomization := map[string]any{
+		"apiVersion": "kustomize.config.k8s.io/v1beta1",
+		"kind":       "Kustomization",
+		"resources":  []string{resource},
+	}
+	var patches []map[string]string
+	for i, patch := range o.KustomizePatches {
+		f, err := fileutils.GetWorkloadReader(patch, nil)
+		if err != nil {
+			return "", err
+		}
+		t, err := io.ReadAll(f)
+		if err != nil {
+			return "", err
+		}
+		rendered, err := util.RenderTemplate(t, templateData, util.MissingKeyError, configSpec.GlobalConfig.FunctionTemplates)
+		if err != nil {
+			return "", fmt.Errorf("template error in %s: %v", patch, err)
+		}
+		path := fmt.Sprintf("patch-%d.yml", i)
+		if err := os.WriteFile(filepath.Join(wrapperDir, path), rendered, 0644); err != nil {
+			return "", err
+		}
+		patches = append(patches, map[string]string{"path": path})
+	}
+	kustomization["patches"] = patches
+	out, err := yaml.Marshal(kustomization)
+	if err != nil {
+		return "", err
+	}
+	return wrapperDir, os.WriteFile(filepath.Join(wrapperDir, "kustomization.yaml"), out, 0644)
+}
+
+// splitDocuments splits a multi-document YAML stream, empty documents are discarded
+func splitDocuments(stream []byte) [][]byte {
+	var documents [][]byte
+	var document bytes.Buffer
+	flush := func() {
+		if len(bytes.TrimSpace(document.Bytes())) > 0 {
+			documents = append(documents, bytes.Clone(document.Bytes()))
+		}
+		document.Reset()
+	}
+	for _, line := range strings.SplitAfter(string(stream), "\n") {
+		if strings.TrimRight(line, " \r\n") == "---" {
+			flush()
+			continue
+		}
+		document.WriteString(line)
+	}
+	flush()
+	return documents
+}
@@ -0,0 +1,513 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// OCIScheme prefix of the workload bundles pulled from an OCI registry
+const OCIScheme = "oci://"
+
+const (
+	dockerHub         = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
+	// Layer annotation holding the file name, set by oras push
+	ociTitleAnnotation = "org.opencontainers.image.title"
+	// Layer annotation set by oras push on the directories, packed as a tar+gzip layer
+	orasUnpackAnnotation = "io.deis.oras.content.unpack"
+)
+
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion,omitempty"`
+	MediaType     string          `json:"mediaType"`
+	Config        *ociDescriptor  `json:"config,omitempty"`
+	Layers        []ociDescriptor `json:"layers"`
+	// Manifests of an index
+	Manifests []ociDescriptor `json:"manifests,omitempty"`
+}
+
+// OCILayer returns the content of an uncompressed tar layer and its size, from the beginning on each call
+type OCILayer func() (io.Reader, int64)
+
+// ociReference reference of an OCI artifact
+type ociReference struct {
+	registry   string
+	repository string
+	// tag or digest
+	reference string
+}
+
+// ociClient pulls and pushes artifacts, authenticating as requested by the registry challenges
+type ociClient struct {
+	ref        ociReference
+	httpClient *http.Client
+	token      string
+	basicAuth  string
+}
+
+// FetchOCIBundle pulls the workload bundle, holding config.yml and optionally metrics.yml and alerts.yml along with
+// the rest of the files they reference, into the current directory. It returns the paths of the metrics and alert profiles found
+func FetchOCIBundle(reference string) (string, string, error) {
+	var metricProfile, alertProfile string
+	ref, err := parseOCIReference(reference)
+	if err != nil {
+		return metricProfile, alertProfile, err
+	}
+	log.Infof("Pulling workload bundle %s", reference)
+	client := &ociClient{ref: ref, httpClient: http.DefaultClient}
+	manifest, err := client.manifest(ref.reference)
+	if err != nil {
+		return metricProfile, alertProfile, err
+	}
+	// Bundles are platform independent, the first manifest of an index is used
+	if len(manifest.Manifests) > 0 {
+		if manifest, err = client.manifest(manifest.Manifests[0].Digest); err != nil {
+			return metricProfile, alertProfile, err
+		}
+	}
+	for _, layer := range manifest.Layers {
+		blob, err := client.blob(layer)
+		if err != nil {
+			return metricProfile, alertProfile, err
+		}
+		title := layer.Annotations[ociTitleAnnotation]
+		if strings.Contains(layer.MediaType, "tar") || layer.Annotations[orasUnpackAnnotation] == "true" {
+			err = extractLayer(blob, strings.HasSuffix(layer.MediaType, "gzip") || layer.Annotations[orasUnpackAnnotation] == "true")
+		} else if title != "" {
+			err = writeBundleFile(title, blob)
+		} else {
+			log.Warnf("Skipping layer %s of bundle %s: no file name", layer.Digest, reference)
+			continue
+		}
+		if err != nil {
+			return metricProfile, alertProfile, fmt.Errorf("error extracting layer %s: %v", layer.Digest, err)
+		}
+	}
+	if _, err := os.Stat("config.yml"); err != nil {
+		return metricProfile, alertProfile, fmt.Errorf("bundle %s doesn't hold a config.yml file", reference)
+	}
+	if _, err := os.Stat("metrics.yml"); err == nil {
+		metricProfile = "metrics.yml"
+	}
+	if _, err := os.Stat("alerts.yml"); err == nil {
+		alertProfile = "alerts.yml"
+	}
+	return metricProfile, alertProfile, nil
+}
+
+// PushOCIImage pushes a linux/amd64 image made of the layers, the ones already in the registry aren't uploaded again
+func PushOCIImage(reference string, layers []OCILayer) error {
+	ref, err := parseOCIReference(reference)
+	if err != nil {
+		return err
+	}
+	client := &ociClient{ref: ref, httpClient: http.DefaultClient}
+	manifest := ociManifest{
+		SchemaVersion: 2,
+		MediaType:     "application/vnd.oci.image.manifest.v1+json",
+	}
+	var diffIDs []string
+	for _, layer := range layers {
+		r, _ := layer()
+		h := sha256.New()
+		size, err := io.Copy(h, r)
+		if err != nil {
+			return fmt.Errorf("error reading layer: %v", err)
+		}
+		digest := "sha256:" + hex.EncodeToString(h.Sum(nil))
+		if err := client.uploadBlob(digest, layer); err != nil {
+			return err
+		}
+		diffIDs = append(diffIDs, digest)
+		manifest.Layers = append(manifest.Layers, ociDescriptor{MediaType: "application/vnd.oci.image.layer.v1.tar", Digest: digest, Size: size})
+	}
+	imageConfig, err := json.Marshal(map[string]any{
+		"architecture": "amd64",
+		"os":           "linux",
+		"rootfs":       map[string]any{"type": "layers", "diff_ids": diffIDs},
+		"config":       map[string]any{},
+	})
+	if err != nil {
+		return err
+	}
+	configDigest := fmt.Sprintf("sha256:%x", sha256.Sum256(imageConfig))
+	err = client.uploadBlob(configDigest, func() (io.Reader, int64) {
+		return bytes.NewReader(imageConfig), int64(len(imageConfig))
+	})
+	if err != nil {
+		return err
+	}
+	manifest.Config = &ociDescriptor{MediaType: "application/vnd.oci.image.config.v1+json", Digest: configDigest, Size: int64(len(imageConfig))}
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	resp, body, err := client.do(http.MethodPut, fmt.Sprintf("%s/v2/%s/manifests/%s", client.registryURL(), ref.repository, ref.reference),
+		func() (io.Reader, int64) { return bytes.NewReader(manifestJSON), int64(len(manifestJSON)) },
+		map[string]string{"Content-Type": manifest.MediaType})
+	if err != nil {
+		return fmt.Errorf("error pushing manifest %s: %v", reference, err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("error pushing manifest %s: %s: %s", reference, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// uploadBlob uploads the blob in a single request, unless the registry holds it already
+func (c *ociClient) uploadBlob(digest string, blob OCILayer) error {
+	resp, _, err := c.do(http.MethodHead, fmt.Sprintf("%s/v2/%s/blobs/%s", c.registryURL(), c.ref.repository, digest), nil, nil)
+	if err != nil {
+		return fmt.Errorf("error checking blob %s: %v", digest, err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	resp, body, err := c.do(http.MethodPost, fmt.Sprintf("%s/v2/%s/blobs/uploads/", c.registryURL(), c.ref.repository), nil, nil)
+	if err != nil {
+		return fmt.Errorf("error starting upload of blob %s: %v", digest, err)
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("error starting upload of blob %s: %s: %s", digest, resp.Status, strings.TrimSpace(string(body)))
+	}
+	location, err := resp.Request.URL.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return fmt.Errorf("invalid upload location: %v", err)
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+	resp, body, err = c.do(http.MethodPut, location.String(), blob, map[string]string{"Content-Type": "application/octet-stream"})
+	if err != nil {
+		return fmt.Errorf("error uploading blob %s: %v", digest, err)
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("error uploading blob %s: %s: %s", digest, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// parseOCIReference parses references like oci://quay.io/org/workload:tag or oci://quay.io/org/workload@sha256:<digest>
+func parseOCIReference(reference string) (ociReference, error) {
+	var ref ociReference
+	name := strings.TrimPrefix(reference, OCIScheme)
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref.reference = name[:i], name[i+1:]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.reference = name[:i], name[i+1:]
+	} else {
+		ref.reference = "latest"
+	}
+	ref.registry, ref.repository = dockerHub, name
+	if i := strings.Index(name, "/"); i >= 0 {
+		if host := name[:i]; strings.ContainsAny(host, ".:") || host == "localhost" {
+			ref.registry, ref.repository = host, name[i+1:]
+		}
+	}
+	if ref.repository == "" || ref.reference == "" {
+		return ref, fmt.Errorf("invalid OCI reference %s", reference)
+	}
+	if ref.registry == dockerHub {
+		ref.registry = dockerHubRegistry
+		if !strings.Contains(ref.repository, "/") {
+			ref.repository = "library/" + ref.repository
+		}
+	}
+	return ref, nil
+}
+
+func (c *ociClient) manifest(reference string) (ociManifest, error) {
+	var manifest ociManifest
+	body, err := c.get(fmt.Sprintf("/v2/%s/manifests/%s", c.ref.repository, reference), strings.Join(manifestMediaTypes, ","))
+	if err != nil {
+		return manifest, fmt.Errorf("error fetching manifest %s: %v", reference, err)
+	}
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return manifest, fmt.Errorf("error decoding manifest %s: %v", reference, err)
+	}
+	return manifest, nil
+}
+
+// blob downloads the layer and verifies its digest
+func (c *ociClient) blob(layer ociDescriptor) ([]byte, error) {
+	body, err := c.get(fmt.Sprintf("/v2/%s/blobs/%s", c.ref.repository, layer.Digest), "")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching blob %s: %v", layer.Digest, err)
+	}
+	algorithm, digest, _ := strings.Cut(layer.Digest, ":")
+	if algorithm == "sha256" {
+		if sum := sha256.Sum256(body); hex.EncodeToString(sum[:]) != digest {
+			return nil, fmt.Errorf("digest mismatch of blob %s", layer.Digest)
+		}
+	}
+	return body, nil
+}
+
+// get requests the registry, authenticating once it's challenged
+func (c *ociClient) get(path, accept string) ([]byte, error) {
+	headers := make(map[string]string)
+	if accept != "" {
+		headers["Accept"] = accept
+	}
+	resp, body, err := c.do(http.MethodGet, c.registryURL()+path, nil, headers)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// do sends a request to the registry, retrying it once authenticated when it's challenged. The request body
+// is returned by a function, so that it can be sent again
+func (c *ociClient) do(method, url string, body func() (io.Reader, int64), headers map[string]string) (*http.Response, []byte, error) {
+	for attempt := 0; ; attempt++ {
+		var reqBody io.Reader
+		var contentLength int64
+		if body != nil {
+			reqBody, contentLength = body()
+		}
+		req, err := http.NewRequest(method, url, reqBody)
+		if err != nil {
+			return nil, nil, err
+		}
+		req.ContentLength = contentLength
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		} else if c.basicAuth != "" {
+			req.Header.Set("Authorization", "Basic "+c.basicAuth)
+		}
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, nil, err
+		}
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			if err := c.authenticate(resp.Header.Get("WWW-Authenticate")); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		return resp, respBody, nil
+	}
+}
+
+// registryURL returns the URL of the registry, local registries are served over plain HTTP
+func (c *ociClient) registryURL() string {
+	host, _, _ := strings.Cut(c.ref.registry, ":")
+	if host == "localhost" || host == "127.0.0.1" {
+		return "http://" + c.ref.registry
+	}
+	return "https://" + c.ref.registry
+}
+
+// authenticate requests a token to the realm of the bearer challenge, using the registry credentials when found
+func (c *ociClient) authenticate(challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if strings.EqualFold(scheme, "Basic") {
+		if c.basicAuth = registryAuth(c.ref.registry); c.basicAuth == "" {
+			return fmt.Errorf("no credentials found for registry %s", c.ref.registry)
+		}
+		return nil
+	}
+	if !strings.EqualFold(scheme, "Bearer") {
+		return fmt.Errorf("unsupported authentication challenge: %s", challenge)
+	}
+	values := make(map[string]string)
+	for _, param := range strings.Split(params, ",") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(param), "="); ok {
+			values[key] = strings.Trim(value, `"`)
+		}
+	}
+	realm, err := url.Parse(values["realm"])
+	if err != nil || values["realm"] == "" {
+		return fmt.Errorf("invalid authentication realm: %s", challenge)
+	}
+	query := realm.Query()
+	if values["service"] != "" {
+		query.Set("service", values["service"])
+	}
+	scope := values["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", c.ref.repository)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	if auth := registryAuth(c.ref.registry); auth != "" {
+		req.Header.Set("Authorization", "Basic "+auth)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error requesting registry token: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error requesting registry token: %s", resp.Status)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("error decoding registry token: %v", err)
+	}
+	c.token = token.Token
+	if c.token == "" {
+		c.token = token.AccessToken
+	}
+	return nil
+}
+
+// registryAuth returns the base64 encoded credentials of the registry from the auth file of podman or docker
+func registryAuth(registry string) string {
+	var authFiles []string
+	if authFile := os.Getenv("REGISTRY_AUTH_FILE"); authFile != "" {
+		authFiles = append(authFiles, authFile)
+	}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		authFiles = append(authFiles, filepath.Join(runtimeDir, "containers", "auth.json"))
+	}
+	authFiles = append(authFiles, filepath.Join(os.Getenv("HOME"), ".docker", "config.json"))
+	hosts := []string{registry, "https://" + registry}
+	if registry == dockerHubRegistry {
+		hosts = append(hosts, dockerHub, "https://index.docker.io/v1/")
+	}
+	for _, authFile := range authFiles {
+		data, err := os.ReadFile(authFile)
+		if err != nil {
+			continue
+		}
+		var auths struct {
+			Auths map[string]struct {
+				Auth string `json:"auth"`
+			} `json:"auths"`
+		}
+		if json.Unmarshal(data, &auths) != nil {
+			continue
+		}
+		for _, host := range hosts {
+			if auth := auths.Auths[host].Auth; auth != "" {
+				if _, err := base64.StdEncoding.DecodeString(auth); err == nil {
+					return auth
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// extractLayer extracts the regular files and directories of a tar layer into the current directory
+func extractLayer(blob []byte, gzipped bool) error {
+	var r io.Reader = bytes.NewReader(blob)
+	if gzipped {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			path, err := bundlePath(header.Name)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+			if err := writeBundleFile(header.Name, data); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func writeBundleFile(name string, data []byte) error {
+	path, err := bundlePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	log.Debugf("Writing bundle file %s", path)
+	return os.WriteFile(path, data, 0644)
+}
+
+// bundlePath returns the local path of a bundle file, which must be within the current directory
+func bundlePath(name string) (string, error) {
+	path := filepath.Clean(name)
+	if filepath.IsAbs(path) || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("bundle file %s is outside the bundle directory", name)
+	}
+	return path, nil
+}
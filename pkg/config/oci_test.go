@@ -0,0 +1,71 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "testing"
+
+func TestParseOCIReference(t *testing.T) {
+	tests := []struct {
+		reference string
+		expected  ociReference
+	}{
+		{"oci://quay.io/org/workload:v1", ociReference{"quay.io", "org/workload", "v1"}},
+		{"oci://quay.io/org/workload", ociReference{"quay.io", "org/workload", "latest"}},
+		{"oci://quay.io/org/workload@sha256:abcd", ociReference{"quay.io", "org/workload", "sha256:abcd"}},
+		{"oci://localhost:5000/workload:v1", ociReference{"localhost:5000", "workload", "v1"}},
+		{"oci://localhost/org/workload", ociReference{"localhost", "org/workload", "latest"}},
+		{"oci://org/workload:v1", ociReference{dockerHubRegistry, "org/workload", "v1"}},
+		{"oci://workload", ociReference{dockerHubRegistry, "library/workload", "latest"}},
+		{"oci://docker.io/workload:v1", ociReference{dockerHubRegistry, "library/workload", "v1"}},
+	}
+	for _, tt := range tests {
+		ref, err := parseOCIReference(tt.reference)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.reference, err)
+			continue
+		}
+		if ref != tt.expected {
+			t.Errorf("%s: got %+v, expected %+v", tt.reference, ref, tt.expected)
+		}
+	}
+	for _, reference := range []string{"oci://", "oci://quay.io/org/workload:", "oci://quay.io/org/workload@"} {
+		if _, err := parseOCIReference(reference); err == nil {
+			t.Errorf("%s: expected an error", reference)
+		}
+	}
+}
+
+func TestBundlePath(t *testing.T) {
+	tests := map[string]string{
+		"config.yml":               "config.yml",
+		"templates/deployment.yml": "templates/deployment.yml",
+		"./templates//pod.yml":     "templates/pod.yml",
+		"templates/../metrics.yml": "metrics.yml",
+		"..config.yml":             "..config.yml",
+	}
+	for name, expected := range tests {
+		path, err := bundlePath(name)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		} else if path != expected {
+			t.Errorf("%s: got %s, expected %s", name, path, expected)
+		}
+	}
+	for _, name := range []string{"..", "../config.yml", "templates/../../config.yml", "/etc/passwd"} {
+		if _, err := bundlePath(name); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
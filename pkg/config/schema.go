@@ -0,0 +1,313 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const schemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Durations are parsed with time.ParseDuration
+const durationPattern = `^[-+]?(0|([0-9]*(\.[0-9]*)?(ns|us|µs|ms|s|m|h))+)$`
+
+// JSONSchema subset of JSON Schema describing the configuration file
+type JSONSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Type        string                 `json:"type,omitempty"`
+	Format      string                 `json:"format,omitempty"`
+	Pattern     string                 `json:"pattern,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Items       *JSONSchema            `json:"items,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Not         *JSONSchema            `json:"not,omitempty"`
+	OneOf       []*JSONSchema          `json:"oneOf,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	// AdditionalProperties either false or the schema of the map values
+	AdditionalProperties any `json:"additionalProperties,omitempty"`
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// Valid values of the enumerated fields
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeOf(JobType("")):                 {string(CreationJob), string(DeletionJob), string(PatchJob), string(ReadJob), string(KubeVirtJob), string(HelmJob), string(FioJob), string(NetworkJob), string(InflateJob)},
+	reflect.TypeOf(HelmOperation("")):           {string(HelmInstall), string(HelmUninstall)},
+	reflect.TypeOf(InflateTarget("")):           {string(InflateData), string(InflateAnnotation), string(InflateStatus)},
+	reflect.TypeOf(NetworkTopology("")):         {string(NetworkSameNode), string(NetworkCrossNode), string(NetworkCrossZone)},
+	reflect.TypeOf(NetworkProtocol("")):         {string(NetworkTCP), string(NetworkUDP)},
+	reflect.TypeOf(ExecutionMode("")):           {string(ExecutionModeParallel), string(ExecutionModeSequential)},
+	reflect.TypeOf(MetricsClosing("")):          {string(AfterJobPause), string(AfterMeasurements), string(AfterJob)},
+	reflect.TypeOf(ErrorBreachPolicy("")):       {string(ErrorBreachStop), string(ErrorBreachCleanup)},
+	reflect.TypeOf(GCPolicy("")):                {string(GCAlways), string(GCOnSuccess), string(GCOnFailure)},
+	reflect.TypeOf(ChurnPodDeletion("")):        {string(ChurnPodDelete), string(ChurnPodEvict)},
+	reflect.TypeOf(HookFailurePolicy("")):       {string(HookFail), string(HookIgnore)},
+	reflect.TypeOf(MeshProvider("")):            {string(MeshIstio), string(MeshLinkerd)},
+	reflect.TypeOf(CostProvider("")):            {string(CostOpenCost), string(CostKubecost)},
+	reflect.TypeOf(ContentType("")):             {string(ContentTypeJSON), string(ContentTypeProtobuf)},
+	reflect.TypeOf(GitHubReportMode("")):        {string(GitHubCheckRun), string(GitHubComment)},
+	reflect.TypeOf(NodeWatchdogAction("")):      {string(NodeWatchdogRecord), string(NodeWatchdogPause), string(NodeWatchdogAbort)},
+	reflect.TypeOf(ClusterHealthPolicy("")):     {string(ClusterHealthWarn), string(ClusterHealthFail)},
+	reflect.TypeOf(WaiterMode("")):              {string(WaiterModePoll), string(WaiterModeWatch)},
+	reflect.TypeOf(ResourceVersionSemantic("")): {string(ResourceVersionMostRecent), string(ResourceVersionAny)},
+	reflect.TypeOf(RampProfile("")):             {string(RampLinear), string(RampStep), string(RampExponential)},
+	reflect.TypeOf(ArrivalDistribution("")):     {string(ArrivalConstant), string(ArrivalPoisson)},
+	reflect.TypeOf(AlertNotificationType("")):   {string(AlertNotificationSlack), string(AlertNotificationWebhook), string(AlertNotificationPagerDuty)},
+	reflect.TypeOf(ArtifactFormat("")):          {string(ArtifactJSON), string(ArtifactJSONLines)},
+	reflect.TypeOf(KubeVirtOpType("")): {
+		string(KubeVirtOpStart), string(KubeVirtOpStop), string(KubeVirtOpRestart), string(KubeVirtOpPause),
+		string(KubeVirtOpUnpause), string(KubeVirtOpMigrate), string(KubeVirtOpAddVolume), string(KubeVirtOpRemoveVolume),
+	},
+}
+
+// AddJobType makes the configuration accept a job type not run by kube-burner itself, wrappers register their job types
+// with burner.RegisterJobType
+func AddJobType(jobType JobType) {
+	t := reflect.TypeOf(JobType(""))
+	if !slices.Contains(schemaEnums[t], string(jobType)) {
+		schemaEnums[t] = append(schemaEnums[t], string(jobType))
+	}
+}
+
+// Constraints between the fields of a type
+var schemaConstraints = map[reflect.Type]func(*JSONSchema){
+	reflect.TypeOf(MetricsEndpoint{}): func(s *JSONSchema) {
+		s.Not = &JSONSchema{Required: []string{"indexer", "indexers"}, Description: "indexer and indexers are mutually exclusive"}
+	},
+	reflect.TypeOf(Object{}): func(s *JSONSchema) {
+		s.Not = &JSONSchema{Required: []string{"objectTemplate", "kustomizeDir"}, Description: "objectTemplate and kustomizeDir are mutually exclusive"}
+	},
+	reflect.TypeOf(ExitHook{}): func(s *JSONSchema) {
+		s.OneOf = []*JSONSchema{{Required: []string{"command"}}, {Required: []string{"url"}}}
+		s.Description = "either command or url"
+	},
+	reflect.TypeOf(JobHook{}): func(s *JSONSchema) {
+		s.OneOf = []*JSONSchema{{Required: []string{"command"}}, {Required: []string{"manifest"}}, {Required: []string{"job"}}}
+		s.Description = "one of command, manifest or job"
+	},
+}
+
+// Schema returns the JSON Schema of the configuration file, generated from the configuration types
+func Schema() *JSONSchema {
+	s := typeSchema(reflect.TypeOf(Spec{}))
+	s.Schema = schemaDraft
+	s.Title = "kube-burner configuration"
+	return s
+}
+
+func typeSchema(t reflect.Type) *JSONSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case durationType:
+		return &JSONSchema{Type: "string", Pattern: durationPattern}
+	case timeType:
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	}
+	if enum, ok := schemaEnums[t]; ok {
+		return &JSONSchema{Type: "string", Enum: enum}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{Type: "array", Items: typeSchema(t.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: typeSchema(t.Elem())}
+	case reflect.Struct:
+		s := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema), AdditionalProperties: false}
+		addStructProperties(s, t)
+		if constraint, ok := schemaConstraints[t]; ok {
+			constraint(s)
+		}
+		return s
+	}
+	// Interfaces accept any value
+	return &JSONSchema{}
+}
+
+// addStructProperties adds the fields of the struct following the yaml.v3 naming rules
+func addStructProperties(s *JSONSchema, t reflect.Type) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		name, flags, _ := strings.Cut(tag, ",")
+		if slices.Contains(strings.Split(flags, ","), "inline") {
+			addStructProperties(s, field.Type)
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		s.Properties[name] = typeSchema(field.Type)
+	}
+}
+
+// ValidateSchema renders the configuration file and checks it against its JSON Schema, returning all the violations found
+func ValidateSchema(configFileReader, userDataFileReader io.Reader, allowMissingKeys bool) error {
+	renderedCfg, err := renderConfig(configFileReader, userDataFileReader, allowMissingKeys, nil)
+	if err != nil {
+		return err
+	}
+	return validateRenderedSchema(renderedCfg)
+}
+
+// validateRenderedSchema checks the rendered configuration file against its JSON Schema
+func validateRenderedSchema(renderedCfg []byte) error {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(renderedCfg)).Decode(&doc); err != nil {
+		return fmt.Errorf("error decoding configuration file: %s", err)
+	}
+	if len(doc.Content) == 0 {
+		return fmt.Errorf("empty configuration file")
+	}
+	return errors.Join(Schema().validate(doc.Content[0], "")...)
+}
+
+// validate checks the YAML node against the schema
+func (s *JSONSchema) validate(node *yaml.Node, path string) []error {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	// Null values leave the defaults
+	if node.ShortTag() == "!!null" {
+		return nil
+	}
+	violation := func(format string, args ...any) []error {
+		return []error{fmt.Errorf("line %d: %s: %s", node.Line, schemaPath(path), fmt.Sprintf(format, args...))}
+	}
+	if s.Type != "" && !nodeHasType(node, s.Type) {
+		return violation("expected %s, got %s", s.Type, nodeType(node))
+	}
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, node.Value) {
+		return violation("invalid value %q, valid values are %s", node.Value, strings.Join(s.Enum, ", "))
+	}
+	if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(node.Value) {
+		return violation("invalid value %q", node.Value)
+	}
+	var errs []error
+	switch node.Kind {
+	case yaml.SequenceNode:
+		if s.Items != nil {
+			for i, item := range node.Content {
+				errs = append(errs, s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	case yaml.MappingNode:
+		var keys []string
+		for i := 0; i < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			keys = append(keys, key.Value)
+			fieldPath := strings.TrimPrefix(path+"."+key.Value, ".")
+			if property, ok := s.Properties[key.Value]; ok {
+				errs = append(errs, property.validate(value, fieldPath)...)
+				continue
+			}
+			switch additional := s.AdditionalProperties.(type) {
+			case bool:
+				if !additional {
+					errs = append(errs, fmt.Errorf("line %d: %s: unknown field", key.Line, fieldPath))
+				}
+			case *JSONSchema:
+				errs = append(errs, additional.validate(value, fieldPath)...)
+			}
+		}
+		for _, required := range s.Required {
+			if !slices.Contains(keys, required) {
+				errs = append(errs, violation("missing field %s", required)...)
+			}
+		}
+	}
+	if s.Not != nil && len(s.Not.validate(node, path)) == 0 {
+		errs = append(errs, violation("%s", s.Not.Description)...)
+	}
+	if len(s.OneOf) > 0 {
+		var matched int
+		for _, alternative := range s.OneOf {
+			if len(alternative.validate(node, path)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			errs = append(errs, violation("must define %s", s.Description)...)
+		}
+	}
+	return errs
+}
+
+func nodeHasType(node *yaml.Node, schemaType string) bool {
+	switch schemaType {
+	case "object":
+		return node.Kind == yaml.MappingNode
+	case "array":
+		return node.Kind == yaml.SequenceNode
+	case "string":
+		// Like the decoder, any scalar is accepted as a string
+		return node.Kind == yaml.ScalarNode
+	case "integer":
+		return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!int"
+	case "number":
+		return node.Kind == yaml.ScalarNode && (node.ShortTag() == "!!int" || node.ShortTag() == "!!float")
+	case "boolean":
+		return node.Kind == yaml.ScalarNode && node.ShortTag() == "!!bool"
+	}
+	return true
+}
+
+func nodeType(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	return strings.TrimPrefix(node.ShortTag(), "!!")
+}
+
+func schemaPath(path string) string {
+	if path == "" {
+		return "<root>"
+	}
+	return path
+}
@@ -15,6 +15,8 @@
 package config
 
 import (
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/cloud-bulldozer/go-commons/v2/indexers"
@@ -36,8 +38,34 @@ const (
 	ReadJob JobType = "read"
 	// KubeVirtJob used to send command to the KubeVirt service
 	KubeVirtJob JobType = "kubevirt"
+	// HelmJob used to install or uninstall a Helm chart per iteration
+	HelmJob JobType = "helm"
+	// FioJob used to run a fio storage benchmark per iteration
+	FioJob JobType = "fio"
+	// NetworkJob used to run a network throughput and latency benchmark per iteration
+	NetworkJob JobType = "network"
+	// InflateJob used to grow existing objects at a controlled rate
+	InflateJob JobType = "inflate"
 )
 
+var jobTypes = map[JobType]struct{}{
+	CreationJob: {},
+	DeletionJob: {},
+	PatchJob:    {},
+	ReadJob:     {},
+	KubeVirtJob: {},
+	HelmJob:     {},
+	FioJob:      {},
+	NetworkJob:  {},
+	InflateJob:  {},
+}
+
+// IsBuiltInJobType returns whether the job type is run by kube-burner itself, other job types are registered by wrappers
+func IsBuiltInJobType(jobType JobType) bool {
+	_, ok := jobTypes[jobType]
+	return ok
+}
+
 type KubeVirtOpType string
 
 const (
@@ -61,18 +89,104 @@ type Spec struct {
 	Jobs []Job `yaml:"jobs"`
 }
 
+// IndexerConfig extends the indexer configuration with the options of the OpenSearch, object storage and remote write indexers
+type IndexerConfig struct {
+	indexers.IndexerConfig `yaml:",inline"`
+	// Username for basic authentication, supported by the OpenSearch and remote write indexers
+	Username string `yaml:"username"`
+	// Password for basic authentication
+	Password string `yaml:"password"`
+	// DataStream indexes documents into the data stream named after the default index
+	DataStream bool `yaml:"dataStream"`
+	// IndexPeriod appends a date suffix to the index name, daily or monthly
+	IndexPeriod string `yaml:"indexPeriod"`
+	// Replace overwrites the documents already indexed with the same ID, instead of skipping them
+	Replace bool `yaml:"replace"`
+	// CertFile client certificate file, for mutual TLS authentication
+	CertFile string `yaml:"certFile"`
+	// KeyFile client private key file
+	KeyFile string `yaml:"keyFile"`
+	// CAFile CA bundle verifying the server certificate, instead of the system trust store
+	CAFile string `yaml:"caFile"`
+	// ObjectStorage options of the object storage indexer
+	ObjectStorage `yaml:",inline"`
+	// RemoteWriteURL Prometheus remote write endpoint
+	RemoteWriteURL string `yaml:"remoteWriteURL"`
+	// Headers extra HTTP headers sent to the remote write endpoint
+	Headers map[string]string `yaml:"headers"`
+	// TSDBDirectory directory where the OpenMetrics indexer files are converted into Prometheus TSDB blocks
+	TSDBDirectory string `yaml:"tsdbDirectory"`
+}
+
+// ObjectStorage describes the bucket the object storage indexer writes documents to
+type ObjectStorage struct {
+	// BucketURL bucket URL: s3://bucket, gs://bucket or azblob://account/container
+	BucketURL string `yaml:"bucketURL"`
+	// Prefix template of the prefix of the objects
+	Prefix string `yaml:"prefix"`
+	// Region bucket region, only for S3
+	Region string `yaml:"region"`
+	// BucketEndpoint S3 compatible endpoint, like MinIO, or Azure Blob service URL
+	BucketEndpoint string `yaml:"bucketEndpoint"`
+	// AccessKey access key ID for S3
+	AccessKey string `yaml:"accessKey"`
+	// SecretKey secret access key for S3
+	SecretKey string `yaml:"secretKey"`
+	// SASToken Azure Blob shared access signature
+	SASToken string `yaml:"sasToken"`
+}
+
 // metricEndpoint describes prometheus endpoint to scrape
 type MetricsEndpoint struct {
-	indexers.IndexerConfig `yaml:"indexer"`
-	Metrics                []string      `yaml:"metrics"`
-	Alerts                 []string      `yaml:"alerts"`
-	Endpoint               string        `yaml:"endpoint"`
-	Step                   time.Duration `yaml:"step"`
-	SkipTLSVerify          bool          `yaml:"skipTLSVerify"`
-	Token                  string        `yaml:"token"`
-	Username               string        `yaml:"username"`
-	Password               string        `yaml:"password"`
-	Alias                  string        `yaml:"alias"`
+	IndexerConfig `yaml:"indexer"`
+	// Indexers list of indexers receiving the same documents, mutually exclusive with indexer
+	Indexers      []IndexerConfig `yaml:"indexers"`
+	Metrics       []string        `yaml:"metrics"`
+	Alerts        []string        `yaml:"alerts"`
+	Endpoint      string          `yaml:"endpoint"`
+	Step          time.Duration   `yaml:"step"`
+	SkipTLSVerify bool            `yaml:"skipTLSVerify"`
+	Token         string          `yaml:"token"`
+	Username      string          `yaml:"username"`
+	Password      string          `yaml:"password"`
+	Alias         string          `yaml:"alias"`
+	UnixSocket    string          `yaml:"unixSocket"`
+	PortForward   *PortForward    `yaml:"portForward"`
+	// RecordingRules rules evaluated by Prometheus for the duration of the benchmark
+	RecordingRules *RecordingRules `yaml:"recordingRules"`
+	// TargetHealth checks the scrape targets of Prometheus before the benchmark and records their gaps during it
+	TargetHealth *TargetHealth `yaml:"targetHealth"`
+}
+
+// TargetHealth describes the scrape targets checked before the benchmark, whose scrape gaps are recorded during it
+type TargetHealth struct {
+	// Jobs scrape jobs checked, by default the ones exposing the metrics of the metrics profiles
+	Jobs []string `yaml:"jobs"`
+	// Policy warn or fail the benchmark when any of the targets is down before it starts
+	Policy ClusterHealthPolicy `yaml:"policy"`
+}
+
+// RecordingRules describes the recording rules installed when the benchmark starts and removed when it finishes,
+// either in a PrometheusRule or through a ruler API
+type RecordingRules struct {
+	// Files rule files in the Prometheus rule groups format
+	Files []string `yaml:"files"`
+	// Namespace where the PrometheusRule is created
+	Namespace string `yaml:"namespace"`
+	// Labels of the PrometheusRule, they must match the rule selector of the Prometheus instance
+	Labels map[string]string `yaml:"labels"`
+	// RulerURL base URL of a Mimir or Cortex compatible ruler API, the rule groups are pushed there instead of creating a PrometheusRule
+	RulerURL string `yaml:"rulerURL"`
+	// Headers extra HTTP headers sent to the ruler API, like X-Scope-OrgID
+	Headers map[string]string `yaml:"headers"`
+}
+
+// PortForward describes the in-cluster Prometheus pod kube-burner tunnels to
+type PortForward struct {
+	Namespace     string            `yaml:"namespace"`
+	LabelSelector map[string]string `yaml:"labelSelector"`
+	Port          int               `yaml:"port"`
+	Scheme        string            `yaml:"scheme"`
 }
 
 // GlobalConfig holds the global configuration
@@ -81,6 +195,14 @@ type GlobalConfig struct {
 	UUID string
 	// Benchmark RUNID
 	RUNID string
+	// Workers number of kube-burner processes sharing the benchmark
+	Workers int `yaml:"-"`
+	// WorkerIndex index of this process within the workers
+	WorkerIndex int `yaml:"-"`
+	// CompareSide side of an A/B benchmark run by this process, a or b
+	CompareSide string `yaml:"-"`
+	// CompareAddress address of the lockstep server of an A/B benchmark
+	CompareAddress string `yaml:"-"`
 	// Measurements describes a list of measurements kube-burner
 	// will take along with job
 	Measurements []mtypes.Measurement `yaml:"measurements"`
@@ -96,16 +218,364 @@ type GlobalConfig struct {
 	GCMetrics bool `yaml:"gcMetrics"`
 	// Boolean flag to check for cluster-health
 	ClusterHealth bool `yaml:"clusterHealth"`
+	// ClusterMetadata discovers the cluster metadata and attaches it to every indexed document
+	ClusterMetadata bool `yaml:"clusterMetadata"`
 	// Global Benchmark timeout
 	Timeout time.Duration `yaml:"timeout"`
 	// Function templates to render at runtime
 	FunctionTemplates []string `yaml:"functionTemplates"`
+	// DisruptionWindows external disruption windows to stamp on the overlapping documents
+	DisruptionWindows []DisruptionWindow `yaml:"disruptionWindows"`
+	// ExitHooks commands or URLs receiving the results of the benchmark once it finishes
+	ExitHooks []ExitHook `yaml:"exitHooks"`
+	// Budget resource limits the benchmark can't exceed
+	Budget *ResourceBudget `yaml:"budget"`
+	// Cost cost allocation API queried to attribute the cost of the namespaces created by each job
+	Cost *CostConfig `yaml:"cost"`
+	// ClientMetrics sampling of the resource usage of the machine, or pod, running kube-burner
+	ClientMetrics *ClientMetrics `yaml:"clientMetrics"`
+	// Strict rejects the objects with unknown or duplicated fields, instead of the API server dropping them
+	Strict bool `yaml:"strict" json:"strict,omitempty"`
+	// PhaseEvents publishes the phase transitions of the benchmark as Kubernetes events and in a ConfigMap
+	PhaseEvents *PhaseEvents `yaml:"phaseEvents"`
+	// ClientTransport tuning of the transport of the API clients
+	ClientTransport ClientTransport `yaml:"clientTransport"`
+	// GitHubReport publishes the summary of the benchmark as a GitHub check run or pull request comment
+	GitHubReport *GitHubReport `yaml:"gitHubReport"`
+	// NodeWatchdog monitors the node conditions during the benchmark, and can pause or abort it when nodes go unhealthy
+	NodeWatchdog *NodeWatchdog `yaml:"nodeWatchdog"`
+	// ClusterHealthMonitor checks the health of the cluster on an interval during the benchmark
+	ClusterHealthMonitor *ClusterHealthMonitor `yaml:"clusterHealthMonitor"`
+	// AnomalyDetection flags the changepoints and spikes of the series scraped during each job
+	AnomalyDetection *AnomalyDetection `yaml:"anomalyDetection"`
+	// ClusterSnapshot captures the control-plane configuration before and after the benchmark
+	ClusterSnapshot *ClusterSnapshot `yaml:"clusterSnapshot"`
+	// ProductionGuard refuses to run against clusters that look like production unless confirmed
+	ProductionGuard *ProductionGuard `yaml:"productionGuard"`
+	// AlertInterval evaluates the critical alerts on this interval during the benchmark, aborting it when any fires
+	AlertInterval time.Duration `yaml:"alertInterval"`
+	// AlertNotifications destinations notified of the fired alerts
+	AlertNotifications []AlertNotification `yaml:"alertNotifications"`
+	// Alertmanager polls the Alertmanager of the cluster during the benchmark, indexing the alerts that fired
+	Alertmanager *Alertmanager `yaml:"alertmanager"`
+	// ArtifactsDirectory directory the artifacts of the jobs are copied to
+	ArtifactsDirectory string `yaml:"artifactsDirectory"`
+	// LiveReconfiguration HTTP endpoint adjusting the QPS, burst and churn parameters of the running job
+	LiveReconfiguration *LiveReconfiguration `yaml:"liveReconfiguration"`
+}
+
+// ContentType encoding of the requests to the API server
+type ContentType string
+
+const (
+	ContentTypeJSON     ContentType = "json"
+	ContentTypeProtobuf ContentType = "protobuf"
+)
+
+// Transport defaults of client-go
+const (
+	defaultMaxIdleConnsPerHost = 25
+	defaultKeepAlive           = 30 * time.Second
+)
+
+var contentTypes = map[ContentType]struct{}{
+	ContentTypeJSON:     {},
+	ContentTypeProtobuf: {},
+}
+
+// ClientTransport describes the transport of the API clients, which materially changes the load profile of the API server
+type ClientTransport struct {
+	// DisableHTTP2 forces HTTP/1.1, opening a connection per concurrent request instead of multiplexing them
+	DisableHTTP2 bool `yaml:"disableHTTP2" json:"disableHTTP2"`
+	// ContentType encoding of the requests and preferred encoding of the responses. Objects of custom resources are always JSON encoded
+	ContentType ContentType `yaml:"contentType" json:"contentType"`
+	// MaxIdleConns maximum idle connections kept, 0 means no limit
+	MaxIdleConns int `yaml:"maxIdleConns" json:"maxIdleConns"`
+	// MaxIdleConnsPerHost maximum idle connections kept to the API server
+	MaxIdleConnsPerHost int `yaml:"maxIdleConnsPerHost" json:"maxIdleConnsPerHost"`
+	// KeepAlive period of the TCP keep-alive probes, negative disables them
+	KeepAlive time.Duration `yaml:"keepAlive" json:"keepAlive"`
+}
+
+// PhaseEvents describes where the phase transitions of the benchmark are published
+type PhaseEvents struct {
+	// Namespace where the events and the ConfigMap of the run are created
+	Namespace string `yaml:"namespace"`
+}
+
+// GitHubReportMode how the summary of the benchmark is published on GitHub
+type GitHubReportMode string
+
+const (
+	GitHubCheckRun GitHubReportMode = "check"
+	GitHubComment  GitHubReportMode = "comment"
+)
+
+var gitHubReportModes = map[GitHubReportMode]struct{}{
+	GitHubCheckRun: {},
+	GitHubComment:  {},
+}
+
+// GitHubReport describes the repository, and the commit or pull request, the summary of the benchmark is published to
+type GitHubReport struct {
+	// Mode check run or pull request comment
+	Mode GitHubReportMode `yaml:"mode"`
+	// Repository in owner/name form
+	Repository string `yaml:"repository"`
+	// SHA commit the check run is attached to
+	SHA string `yaml:"sha"`
+	// PullRequest number of the pull request commented
+	PullRequest int `yaml:"pullRequest"`
+	// Name name of the check run
+	Name string `yaml:"name"`
+	// APIURL GitHub API URL, for GitHub Enterprise servers
+	APIURL string `yaml:"apiURL"`
+	// Token token with permissions to write checks or pull requests
+	Token string `yaml:"token"`
+	// Timeout request timeout
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// NodeWatchdogAction what the node watchdog does when too many nodes are unhealthy
+type NodeWatchdogAction string
+
+const (
+	NodeWatchdogRecord NodeWatchdogAction = "record"
+	NodeWatchdogPause  NodeWatchdogAction = "pause"
+	NodeWatchdogAbort  NodeWatchdogAction = "abort"
+)
+
+var nodeWatchdogActions = map[NodeWatchdogAction]struct{}{
+	NodeWatchdogRecord: {},
+	NodeWatchdogPause:  {},
+	NodeWatchdogAbort:  {},
+}
+
+// NodeWatchdog describes the node conditions monitored during the benchmark and the reaction to unhealthy nodes
+type NodeWatchdog struct {
+	// Interval polling interval of the nodes
+	Interval time.Duration `yaml:"interval"`
+	// LabelSelector nodes monitored
+	LabelSelector string `yaml:"labelSelector"`
+	// Conditions node conditions monitored, Ready is unhealthy when not True and the rest when True
+	Conditions []string `yaml:"conditions"`
+	// MaxUnhealthyRatio fraction of the monitored nodes that can be unhealthy before the action is taken
+	MaxUnhealthyRatio float64 `yaml:"maxUnhealthyRatio"`
+	// Action record, pause or abort
+	Action NodeWatchdogAction `yaml:"action"`
+	// PauseTimeout time the benchmark waits for the nodes to recover before being aborted
+	PauseTimeout time.Duration `yaml:"pauseTimeout"`
+}
+
+// ClusterHealthPolicy what happens to the benchmark when the health of the cluster degrades
+type ClusterHealthPolicy string
+
+const (
+	ClusterHealthWarn ClusterHealthPolicy = "warn"
+	ClusterHealthFail ClusterHealthPolicy = "fail"
+)
+
+var clusterHealthPolicies = map[ClusterHealthPolicy]struct{}{
+	ClusterHealthWarn: {},
+	ClusterHealthFail: {},
+}
+
+// ClusterHealthMonitor describes the periodic health checks of the cluster and when its health is degraded
+type ClusterHealthMonitor struct {
+	// Interval time between health checks
+	Interval time.Duration `yaml:"interval"`
+	// MaxUnhealthyNodes number of unhealthy nodes a check tolerates
+	MaxUnhealthyNodes int `yaml:"maxUnhealthyNodes"`
+	// MaxFailedChecks consecutive failed checks after which the health of the cluster is degraded
+	MaxFailedChecks int `yaml:"maxFailedChecks"`
+	// Policy warn or fail the benchmark when the health of the cluster is degraded
+	Policy ClusterHealthPolicy `yaml:"policy"`
+}
+
+// AnomalyDetection describes the series analyzed for changepoints and spikes and the detection sensitivity
+type AnomalyDetection struct {
+	// Metrics names of the range metrics analyzed, all of them when empty
+	Metrics []string `yaml:"metrics"`
+	// Threshold minimum score of a changepoint: the shift of the mean in standard errors
+	Threshold float64 `yaml:"threshold"`
+	// MinSegment minimum number of samples at each side of a changepoint
+	MinSegment int `yaml:"minSegment"`
+	// SpikeThreshold minimum robust z-score of a spike, 0 disables the spike detection
+	SpikeThreshold float64 `yaml:"spikeThreshold"`
+}
+
+// ClusterSnapshot describes the control-plane configuration captured into the run metadata
+type ClusterSnapshot struct {
+	// Components control-plane pods whose command-line flags are captured
+	Components []SnapshotComponent `yaml:"components"`
+	// Resources objects whose spec is captured, like the configuration custom resources of managed control planes
+	Resources []SnapshotResource `yaml:"resources"`
+}
+
+// ProductionGuard markers of the production clusters, the benchmark doesn't run against a cluster matching any of
+// them unless confirmed
+type ProductionGuard struct {
+	// MaxNodes clusters with more nodes look like production, 0 disables the check
+	MaxNodes int `yaml:"maxNodes"`
+	// Labels labels of the nodes or namespaces of production clusters, an empty value matches any value
+	Labels map[string]string `yaml:"labels"`
+	// Annotations annotations of the nodes or namespaces of production clusters, an empty value matches any value
+	Annotations map[string]string `yaml:"annotations"`
+	// APIServers regular expressions matching the API server URLs of production clusters
+	APIServers []string `yaml:"apiServers"`
+	// AuditLogging clusters whose API server records audit logs look like production
+	AuditLogging bool `yaml:"auditLogging"`
+	// Confirm API server URL of the cluster the benchmark is confirmed to run against, even if it looks like production
+	Confirm string `yaml:"confirm"`
+	// Allow confirms running against any cluster, set with --allow-production
+	Allow bool `yaml:"-"`
+}
+
+// SnapshotComponent control-plane component run as pods, like the kubeadm static pods
+type SnapshotComponent struct {
+	// Name name of the component
+	Name string `yaml:"name"`
+	// Namespace namespace of the component pods
+	Namespace string `yaml:"namespace"`
+	// LabelSelector label selector of the component pods
+	LabelSelector string `yaml:"labelSelector"`
+}
+
+// SnapshotResource object whose spec is captured
+type SnapshotResource struct {
+	Group     string `yaml:"group"`
+	Version   string `yaml:"version"`
+	Resource  string `yaml:"resource"`
+	Namespace string `yaml:"namespace"`
+	Name      string `yaml:"name"`
+}
+
+// ClientMetrics describes the sampling of the resource usage of kube-burner and the thresholds flagging it as saturated
+type ClientMetrics struct {
+	// Interval sampling interval
+	Interval time.Duration `yaml:"interval"`
+	// CPUThreshold percentage of the available CPUs used by kube-burner above which it's saturated
+	CPUThreshold float64 `yaml:"cpuThreshold"`
+	// MemoryThreshold percentage of the available memory used by kube-burner above which it's saturated
+	MemoryThreshold float64 `yaml:"memoryThreshold"`
+}
+
+// CostProvider cost allocation API
+type CostProvider string
+
+const (
+	CostOpenCost CostProvider = "opencost"
+	CostKubecost CostProvider = "kubecost"
+)
+
+var costProviders = map[CostProvider]struct{}{
+	CostOpenCost: {},
+	CostKubecost: {},
+}
+
+// CostConfig describes the OpenCost or Kubecost allocation API
+type CostConfig struct {
+	// Provider cost allocation API flavor
+	Provider CostProvider `yaml:"provider"`
+	// Endpoint URL of the API
+	Endpoint string `yaml:"endpoint"`
+	// Headers HTTP headers sent along with the requests, like authorization ones
+	Headers map[string]string `yaml:"headers"`
+	// Timeout request timeout
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// ResourceBudget safety limits of the resources created by the benchmark, zero values aren't enforced
+type ResourceBudget struct {
+	// MaxPods maximum number of pods
+	MaxPods int `yaml:"maxPods"`
+	// MaxCPURequests maximum sum of the CPU requests of the pods, as a quantity
+	MaxCPURequests string `yaml:"maxCPURequests"`
+	// MaxMemoryRequests maximum sum of the memory requests of the pods, as a quantity
+	MaxMemoryRequests string `yaml:"maxMemoryRequests"`
+	// MaxPVCStorage maximum storage requested by the PersistentVolumeClaims, as a quantity
+	MaxPVCStorage string `yaml:"maxPVCStorage"`
+}
+
+// ExitHook describes an executable or URL receiving the benchmark results JSON, either on stdin or as the request body
+type ExitHook struct {
+	// Command executable and arguments to run
+	Command []string `yaml:"command"`
+	// URL endpoint the results are POSTed to
+	URL string `yaml:"url"`
+	// Headers HTTP headers sent along with the request
+	Headers map[string]string `yaml:"headers"`
+	// Timeout hook timeout
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Alertmanager API polled for the alerts that fire during the benchmark, reached either by URL or through the API
+// server proxy of its service
+type Alertmanager struct {
+	// URL of the Alertmanager
+	URL string `yaml:"url"`
+	// Token bearer token of the requests to the URL
+	Token string `yaml:"token"`
+	// SkipTLSVerify skips the verification of the certificate of the URL
+	SkipTLSVerify bool `yaml:"skipTLSVerify"`
+	// Service Alertmanager service, as namespace/name:port, reached through the API server proxy with the kubeconfig
+	// credentials
+	Service string `yaml:"service"`
+	// Interval polling interval
+	Interval time.Duration `yaml:"interval"`
+	// Filter label matchers of the alerts, like severity=~"warning|critical"
+	Filter []string `yaml:"filter"`
+}
+
+// LiveReconfiguration endpoint changing the parameters of the running job without restarting the benchmark
+type LiveReconfiguration struct {
+	// Address the endpoint listens on
+	Address string `yaml:"address"`
+	// Token bearer token required by the endpoint, mandatory when it doesn't listen on a loopback address
+	Token string `yaml:"token" json:"-"`
+}
+
+// AlertNotificationType destination of the alert notifications
+type AlertNotificationType string
+
+const (
+	AlertNotificationSlack     AlertNotificationType = "slack"
+	AlertNotificationWebhook   AlertNotificationType = "webhook"
+	AlertNotificationPagerDuty AlertNotificationType = "pagerduty"
+)
+
+// Severities of the alert profiles
+var alertSeverities = []string{"info", "warning", "error", "critical"}
+
+// AlertNotification destination notified of the fired alerts
+type AlertNotification struct {
+	// Type slack, webhook or pagerduty
+	Type AlertNotificationType `yaml:"type"`
+	// URL Slack incoming webhook or generic webhook the notifications are POSTed to, PagerDuty Events API v2 by default
+	URL string `yaml:"url"`
+	// RoutingKey integration key of the PagerDuty service
+	RoutingKey string `yaml:"routingKey"`
+	// Headers HTTP headers sent along with the generic webhook requests
+	Headers map[string]string `yaml:"headers"`
+	// Severities alert severities notified, all by default
+	Severities []string `yaml:"severities"`
+}
+
+// DisruptionWindow describes a time window where an external disruption, such as chaos injection, took place
+type DisruptionWindow struct {
+	Start       time.Time `yaml:"start"`
+	End         time.Time `yaml:"end"`
+	Description string    `yaml:"description"`
 }
 
 // Object defines an object that kube-burner will create
 type Object struct {
 	// ObjectTemplate path to a valid YAML definition of a k8s resource
 	ObjectTemplate string `yaml:"objectTemplate" json:"objectTemplate,omitempty"`
+	// KustomizeDir kustomization built into the object templates, instead of a single template
+	KustomizeDir string `yaml:"kustomizeDir" json:"kustomizeDir,omitempty"`
+	// KustomizePatches templates of the patches applied to the kustomization
+	KustomizePatches []string `yaml:"kustomizePatches" json:"kustomizePatches,omitempty"`
 	// Replicas number of replicas to create of the given object
 	Replicas int `yaml:"replicas" json:"replicas,omitempty"`
 	// InputVars contains a map of arbitrary input variables
@@ -127,6 +597,10 @@ type Object struct {
 	RunOnce bool `yaml:"runOnce" json:"runOnce,omitempty"`
 	// KubeVirt Operation
 	KubeVirtOp KubeVirtOpType `yaml:"kubeVirtOp" json:"kubeVirtOp,omitempty"`
+	// ListLimit page size used when listing objects
+	ListLimit int64 `yaml:"listLimit" json:"listLimit,omitempty"`
+	// ResourceVersion used in read requests, "0" allows serving them from the watch cache
+	ResourceVersion string `yaml:"resourceVersion" json:"resourceVersion,omitempty"`
 }
 
 // Job defines a kube-burner job
@@ -193,6 +667,10 @@ type Job struct {
 	ChurnDelay time.Duration `yaml:"churnDelay" json:"churnDelay,omitempty"`
 	// Churn deletion strategy
 	ChurnDeletionStrategy string `yaml:"churnDeletionStrategy" json:"churnDeletionStrategy,omitempty"`
+	// ChurnPodDeletion how the pods of the churned objects are removed, either deleted or evicted honoring their PodDisruptionBudgets
+	ChurnPodDeletion ChurnPodDeletion `yaml:"churnPodDeletion" json:"churnPodDeletion,omitempty"`
+	// ChurnEvictionTimeout maximum time to wait for the PodDisruptionBudgets to allow the eviction of a pod
+	ChurnEvictionTimeout time.Duration `yaml:"churnEvictionTimeout" json:"churnEvictionTimeout,omitempty"`
 	// Skip this job from indexing
 	SkipIndexing               bool `yaml:"skipIndexing" json:"skipIndexing,omitempty"`
 	DefaultMissingKeysWithZero bool `yaml:"defaultMissingKeysWithZero" json:"defaultMissingKeysWithZero,omitempty"`
@@ -208,6 +686,482 @@ type Job struct {
 	MetricsClosing MetricsClosing `yaml:"metricsClosing" json:"metricsClosing,omitempty"`
 	// Enables job's garbage collection
 	GC bool `yaml:"gc" json:"gc"`
+	// GCPolicy garbage collects the job depending on its outcome
+	GCPolicy GCPolicy `yaml:"gcPolicy" json:"gcPolicy,omitempty"`
+	// MaxErrors maximum number of failed requests before halting the job
+	MaxErrors int `yaml:"maxErrors" json:"maxErrors,omitempty"`
+	// MaxErrorRate maximum percentage of failed requests before halting the job
+	MaxErrorRate float64 `yaml:"maxErrorRate" json:"maxErrorRate,omitempty"`
+	// ErrorBreachPolicy what to do with the job objects when the error limits are breached
+	ErrorBreachPolicy ErrorBreachPolicy `yaml:"errorBreachPolicy" json:"errorBreachPolicy,omitempty"`
+	// WaiterMode how to wait for the objects to be ready, either polling lists or watching them
+	WaiterMode WaiterMode `yaml:"waiterMode" json:"waiterMode,omitempty"`
+	// ListOptions semantics of the LIST and WATCH requests issued by the job
+	ListOptions ListOptions `yaml:"listOptions" json:"listOptions"`
+	// QPSRamp shapes the QPS of the job, from a starting QPS up to qps
+	QPSRamp *QPSRamp `yaml:"qpsRamp" json:"qpsRamp,omitempty"`
+	// ArrivalRate drives the object creations by a target arrival rate, regardless of the API latency
+	ArrivalRate *ArrivalRate `yaml:"arrivalRate" json:"arrivalRate,omitempty"`
+	// IterationsPerMinute starts the iterations of create jobs at fixed wall-clock slots, skipping the slots passed
+	// while the previous iteration was still running
+	IterationsPerMinute float64 `yaml:"iterationsPerMinute" json:"iterationsPerMinute,omitempty"`
+	// AdaptiveQPS lowers the QPS of the job when the API server shows signs of overload
+	AdaptiveQPS *AdaptiveQPS `yaml:"adaptiveQPS" json:"adaptiveQPS,omitempty"`
+	// DependsOn jobs running before this one, which is skipped when any of them fails or is skipped
+	DependsOn []string `yaml:"dependsOn" json:"dependsOn,omitempty"`
+	// RunIf conditions evaluated right before running the job, which is skipped when they aren't met
+	RunIf *RunIf `yaml:"runIf" json:"runIf,omitempty"`
+	// Helm chart installed or uninstalled by helm jobs
+	Helm *HelmChart `yaml:"helm" json:"helm,omitempty"`
+	// Fio storage benchmark run by fio jobs
+	Fio *FioBenchmark `yaml:"fio" json:"fio,omitempty"`
+	// Network network benchmark run by network jobs
+	Network *NetworkBenchmark `yaml:"network" json:"network,omitempty"`
+	// Inflate growth of the objects of inflate jobs
+	Inflate *Inflate `yaml:"inflate" json:"inflate,omitempty"`
+	// WaitFor PromQL gate the job waits for before starting
+	WaitFor *WaitFor `yaml:"waitFor" json:"waitFor,omitempty"`
+	// Naming policy applied to the names of the objects and namespaces created by the job
+	Naming *NamingPolicy `yaml:"naming" json:"naming,omitempty"`
+	// PreHook action run before the job
+	PreHook *JobHook `yaml:"preHook" json:"preHook,omitempty"`
+	// PostHook action run once the job finishes, before its garbage collection
+	PostHook *JobHook `yaml:"postHook" json:"postHook,omitempty"`
+	// MeshOverhead runs the job without and with sidecar injection to quantify the service mesh overhead
+	MeshOverhead *MeshOverhead `yaml:"meshOverhead" json:"meshOverhead,omitempty"`
+	// SchedulerCache runs the job right after restarting kube-scheduler and then in steady state to compare its scheduling latencies
+	SchedulerCache *SchedulerCache `yaml:"schedulerCache" json:"schedulerCache,omitempty"`
+	// SyntheticImages images pushed to a test registry before running the job
+	SyntheticImages *SyntheticImages `yaml:"syntheticImages" json:"syntheticImages,omitempty"`
+	// TargetNodes label selector of the nodes the pods of the created objects are pinned to
+	TargetNodes string `yaml:"targetNodes" json:"targetNodes,omitempty"`
+	// ExcludeNodes label selector of the nodes the pods of the created objects are kept away from
+	ExcludeNodes string `yaml:"excludeNodes" json:"excludeNodes,omitempty"`
+	// Architecture CPU architecture, like amd64 or arm64, of the nodes the pods of the created objects are pinned to
+	Architecture string `yaml:"architecture" json:"architecture,omitempty"`
+	// Identities credentials of the object requests, round-robined across iterations
+	Identities []Identity `yaml:"identities" json:"identities,omitempty"`
+	// WasmHook WebAssembly module mutating or vetoing each rendered object before it's created
+	WasmHook *WasmHook `yaml:"wasmHook" json:"wasmHook,omitempty"`
+	// Artifacts files copied from the pods of the job once it finishes
+	Artifacts []Artifact `yaml:"artifacts" json:"artifacts,omitempty"`
+}
+
+// Artifact file, or directory, copied from the running pods of a job once it finishes, like the results written by
+// the workload inside the containers
+type Artifact struct {
+	// Path absolute path of the file or directory in the containers
+	Path string `yaml:"path" json:"path"`
+	// Container the artifact is copied from, the first container of the pods by default
+	Container string `yaml:"container" json:"container,omitempty"`
+	// LabelSelector of the pods the artifact is copied from, all the pods in the namespaces of the job by default
+	LabelSelector map[string]string `yaml:"labelSelector" json:"labelSelector,omitempty"`
+	// Format parses the copied files into indexed documents, they're only copied when not set
+	Format ArtifactFormat `yaml:"format" json:"format,omitempty"`
+	// MetricName metric name of the documents parsed from the files
+	MetricName string `yaml:"metricName" json:"metricName,omitempty"`
+}
+
+// ArtifactFormat format of the files of an artifact
+type ArtifactFormat string
+
+const (
+	// ArtifactJSON a JSON document per file, or an array of them
+	ArtifactJSON ArtifactFormat = "json"
+	// ArtifactJSONLines a JSON document per line
+	ArtifactJSONLines ArtifactFormat = "jsonLines"
+)
+
+var artifactFormats = map[ArtifactFormat]struct{}{
+	ArtifactJSON:      {},
+	ArtifactJSONLines: {},
+}
+
+// WasmHook WebAssembly module the rendered objects of a creation job are handed to before they're created, it can
+// mutate them or veto their creation
+type WasmHook struct {
+	// Module path or URL of the .wasm module
+	Module string `yaml:"module" json:"module"`
+	// Timeout maximum time the module can take for an object
+	Timeout time.Duration `yaml:"timeout" json:"timeout,omitempty"`
+	// FailurePolicy what to do with the object when the module fails: fail skips it counting an error, ignore creates
+	// it unchanged
+	FailurePolicy HookFailurePolicy `yaml:"failurePolicy" json:"failurePolicy,omitempty"`
+}
+
+// Identity credentials the object requests of some iterations are sent with, so that the load is spread across
+// identities instead of coming from the user of the kubeconfig. Exactly one of the credentials must be set
+type Identity struct {
+	// Name of the identity in the job summary, derived from its credentials by default
+	Name string `yaml:"name" json:"name"`
+	// Token bearer token
+	Token string `yaml:"token" json:"-"`
+	// TokenFile file holding the bearer token, read on every request
+	TokenFile string `yaml:"tokenFile" json:"tokenFile,omitempty"`
+	// ServiceAccount service account, as namespace/name, whose token is requested with the kubeconfig user
+	ServiceAccount string `yaml:"serviceAccount" json:"serviceAccount,omitempty"`
+	// Impersonate user impersonated by the kubeconfig user
+	Impersonate string `yaml:"impersonate" json:"impersonate,omitempty"`
+	// ImpersonateGroups groups of the impersonated user
+	ImpersonateGroups []string `yaml:"impersonateGroups" json:"impersonateGroups,omitempty"`
+}
+
+// SyntheticImages distinct images made of random data, tagged with their index, to load registries and the
+// image subsystem of the kubelets
+type SyntheticImages struct {
+	// Repository repository the images are pushed to, like registry.example.com:5000/kube-burner/synthetic
+	Repository string `yaml:"repository" json:"repository"`
+	// Count number of images
+	Count int `yaml:"count" json:"count"`
+	// Sizes layer sizes, assigned to the images in round-robin
+	Sizes []string `yaml:"sizes" json:"sizes"`
+	// Parallelism images pushed in parallel
+	Parallelism int `yaml:"parallelism" json:"parallelism,omitempty"`
+}
+
+// JobHook action run before or after a job: a local command, a manifest applied to the cluster or
+// a Kubernetes Job run to completion
+type JobHook struct {
+	// Command executable and arguments to run
+	Command []string `yaml:"command" json:"command,omitempty"`
+	// Manifest template of the objects to apply
+	Manifest string `yaml:"manifest" json:"manifest,omitempty"`
+	// Job template of the Kubernetes Job to run to completion
+	Job string `yaml:"job" json:"job,omitempty"`
+	// Timeout hook timeout
+	Timeout time.Duration `yaml:"timeout" json:"timeout,omitempty"`
+	// FailurePolicy what to do when the hook fails
+	FailurePolicy HookFailurePolicy `yaml:"failurePolicy" json:"failurePolicy,omitempty"`
+}
+
+// HookFailurePolicy defines what to do when a job hook fails
+type HookFailurePolicy string
+
+const (
+	// HookFail fails the job, which isn't run when its pre hook fails
+	HookFail HookFailurePolicy = "fail"
+	// HookIgnore logs the error and goes on
+	HookIgnore HookFailurePolicy = "ignore"
+)
+
+var hookFailurePolicies = map[HookFailurePolicy]struct{}{
+	HookFail:   {},
+	HookIgnore: {},
+}
+
+// NamingPolicy naming scheme of the objects and namespaces created by a job
+type NamingPolicy struct {
+	// Prefix prepended to the names
+	Prefix string `yaml:"prefix" json:"prefix,omitempty"`
+	// IndexPadding number of digits the namespace indexes and the padded template variables are zero-padded to
+	IndexPadding int `yaml:"indexPadding" json:"indexPadding,omitempty"`
+	// HashSuffix length of the hash of the object identity appended to the object names
+	HashSuffix int `yaml:"hashSuffix" json:"hashSuffix,omitempty"`
+	// MaxLength maximum length of the object names, namespace names are limited to 63 characters
+	MaxLength int `yaml:"maxLength" json:"maxLength,omitempty"`
+	// Truncate truncates the names exceeding the maximum length instead of failing
+	Truncate bool `yaml:"truncate" json:"truncate,omitempty"`
+}
+
+// RunIf conditional execution of a job
+type RunIf struct {
+	// Expr PromQL expression, the job runs when it returns any non-zero value
+	Expr string `yaml:"expr" json:"expr"`
+}
+
+// HelmChart chart released by helm jobs, one release per iteration
+type HelmChart struct {
+	// Chart chart reference: repo/name, OCI reference, URL or local path
+	Chart string `yaml:"chart" json:"chart"`
+	// Version chart version constraint
+	Version string `yaml:"version" json:"version,omitempty"`
+	// Repo chart repository URL
+	Repo string `yaml:"repo" json:"repo,omitempty"`
+	// ReleaseName template of the release names
+	ReleaseName string `yaml:"releaseName" json:"releaseName,omitempty"`
+	// Values template of the values file
+	Values string `yaml:"values" json:"values,omitempty"`
+	// Set values set on the command line, templates as well
+	Set map[string]string `yaml:"set" json:"set,omitempty"`
+	// Operation what to do with the releases
+	Operation HelmOperation `yaml:"operation" json:"operation,omitempty"`
+	// Wait waits for the resources of the releases to be ready
+	Wait bool `yaml:"wait" json:"wait"`
+	// Timeout timeout of each release operation
+	Timeout time.Duration `yaml:"timeout" json:"timeout,omitempty"`
+	// ExtraArgs additional arguments passed to helm
+	ExtraArgs []string `yaml:"extraArgs" json:"extraArgs,omitempty"`
+	// Binary helm executable
+	Binary string `yaml:"binary" json:"binary,omitempty"`
+}
+
+// FioBenchmark storage benchmark run by fio jobs, one fio pod with its own PVC per iteration
+type FioBenchmark struct {
+	// Image container image providing fio
+	Image string `yaml:"image" json:"image,omitempty"`
+	// StorageClass storage class of the PVCs
+	StorageClass string `yaml:"storageClass" json:"storageClass,omitempty"`
+	// VolumeSize size of the PVCs
+	VolumeSize string `yaml:"volumeSize" json:"volumeSize,omitempty"`
+	// PVCTemplate template of the PVCs, replaces storageClass and volumeSize
+	PVCTemplate string `yaml:"pvcTemplate" json:"pvcTemplate,omitempty"`
+	// JobFile template of the fio job file, replaces the workload options below
+	JobFile string `yaml:"jobFile" json:"jobFile,omitempty"`
+	// RW I/O pattern, as the fio rw option
+	RW string `yaml:"rw" json:"rw,omitempty"`
+	// BlockSize block size, as the fio bs option
+	BlockSize string `yaml:"blockSize" json:"blockSize,omitempty"`
+	// IODepth number of I/O units kept in flight
+	IODepth int `yaml:"ioDepth" json:"ioDepth,omitempty"`
+	// NumJobs number of fio processes
+	NumJobs int `yaml:"numJobs" json:"numJobs,omitempty"`
+	// FileSize size of the file written by each fio process, as the fio size option
+	FileSize string `yaml:"fileSize" json:"fileSize,omitempty"`
+	// Runtime duration of the workload
+	Runtime time.Duration `yaml:"runtime" json:"runtime,omitempty"`
+	// NodeSelector node selector of the fio pods
+	NodeSelector map[string]string `yaml:"nodeSelector" json:"nodeSelector,omitempty"`
+	// Timeout timeout of each fio pod, from its creation to its completion
+	Timeout time.Duration `yaml:"timeout" json:"timeout,omitempty"`
+}
+
+// NetworkBenchmark network benchmark run by network jobs, one client and server pod pair per iteration
+type NetworkBenchmark struct {
+	// Image container image providing iperf3 and netperf
+	Image string `yaml:"image" json:"image,omitempty"`
+	// Topology placement of the client pods relative to their servers
+	Topology NetworkTopology `yaml:"topology" json:"topology"`
+	// Protocol transport protocol
+	Protocol NetworkProtocol `yaml:"protocol" json:"protocol"`
+	// Duration duration of each test
+	Duration time.Duration `yaml:"duration" json:"duration,omitempty"`
+	// Parallel number of parallel streams of the throughput test
+	Parallel int `yaml:"parallel" json:"parallel,omitempty"`
+	// Latency runs a request/response test measuring the latency after the throughput test
+	Latency bool `yaml:"latency" json:"latency"`
+	// MessageSize size in bytes of the requests and responses of the latency test
+	MessageSize int `yaml:"messageSize" json:"messageSize,omitempty"`
+	// HostNetwork runs the pods in the host network
+	HostNetwork bool `yaml:"hostNetwork" json:"hostNetwork,omitempty"`
+	// NodeSelector node selector of the pods
+	NodeSelector map[string]string `yaml:"nodeSelector" json:"nodeSelector,omitempty"`
+	// Timeout timeout of each pair, from the creation of the server to the completion of the client
+	Timeout time.Duration `yaml:"timeout" json:"timeout,omitempty"`
+}
+
+// NetworkTopology placement of the client pods of network jobs
+type NetworkTopology string
+
+const (
+	// NetworkSameNode clients run on the node of their server
+	NetworkSameNode NetworkTopology = "same-node"
+	// NetworkCrossNode clients run on a different node than their server
+	NetworkCrossNode NetworkTopology = "cross-node"
+	// NetworkCrossZone clients run on a different zone than their server
+	NetworkCrossZone NetworkTopology = "cross-zone"
+)
+
+var networkTopologies = map[NetworkTopology]struct{}{
+	NetworkSameNode:  {},
+	NetworkCrossNode: {},
+	NetworkCrossZone: {},
+}
+
+// NetworkProtocol transport protocol of network jobs
+type NetworkProtocol string
+
+const (
+	NetworkTCP NetworkProtocol = "tcp"
+	NetworkUDP NetworkProtocol = "udp"
+)
+
+var networkProtocols = map[NetworkProtocol]struct{}{
+	NetworkTCP: {},
+	NetworkUDP: {},
+}
+
+// Inflate growth of the objects of inflate jobs, which grow by a padding field on every iteration
+type Inflate struct {
+	// Target part of the objects holding the padding
+	Target InflateTarget `yaml:"target" json:"target"`
+	// Field key of the padding within the target
+	Field string `yaml:"field" json:"field,omitempty"`
+	// BytesPerIteration bytes appended to the padding on every iteration
+	BytesPerIteration int `yaml:"bytesPerIteration" json:"bytesPerIteration"`
+	// MaxSize size in bytes the padding stops growing at
+	MaxSize int `yaml:"maxSize" json:"maxSize"`
+}
+
+// InflateTarget part of the objects holding the padding of inflate jobs
+type InflateTarget string
+
+const (
+	// InflateData padding in a data key, for ConfigMaps
+	InflateData InflateTarget = "data"
+	// InflateAnnotation padding in an annotation
+	InflateAnnotation InflateTarget = "annotation"
+	// InflateStatus padding in a status field, updated through the status subresource
+	InflateStatus InflateTarget = "status"
+)
+
+var inflateTargets = map[InflateTarget]struct{}{
+	InflateData:       {},
+	InflateAnnotation: {},
+	InflateStatus:     {},
+}
+
+// HelmOperation operation performed on the releases of helm jobs
+type HelmOperation string
+
+const (
+	// HelmInstall installs or upgrades the releases
+	HelmInstall HelmOperation = "install"
+	// HelmUninstall uninstalls the releases
+	HelmUninstall HelmOperation = "uninstall"
+)
+
+var helmOperations = map[HelmOperation]struct{}{
+	HelmInstall:   {},
+	HelmUninstall: {},
+}
+
+// MeshOverhead service mesh overhead benchmark: the job is run twice, first with sidecar injection
+// disabled and then enabled, and the measurements of both phases are compared
+type MeshOverhead struct {
+	// Provider service mesh injecting the sidecars
+	Provider MeshProvider `yaml:"provider" json:"provider"`
+	// MemoryQuery PromQL returning the memory footprint of the workload
+	MemoryQuery string `yaml:"memoryQuery" json:"memoryQuery,omitempty"`
+	// RequestLatencyQuery PromQL returning the request latency of the workload
+	RequestLatencyQuery string `yaml:"requestLatencyQuery" json:"requestLatencyQuery,omitempty"`
+	// ControlPlaneQueries PromQL of the mesh control plane metrics, by name
+	ControlPlaneQueries map[string]string `yaml:"controlPlaneQueries" json:"controlPlaneQueries,omitempty"`
+	// Job name of the job before being split in phases
+	Job string `yaml:"-" json:"job"`
+	// Phase phase run by the job
+	Phase MeshPhase `yaml:"-" json:"phase"`
+}
+
+// MeshProvider service mesh whose sidecars are injected
+type MeshProvider string
+
+const (
+	MeshIstio   MeshProvider = "istio"
+	MeshLinkerd MeshProvider = "linkerd"
+)
+
+var meshProviders = map[MeshProvider]struct{}{
+	MeshIstio:   {},
+	MeshLinkerd: {},
+}
+
+// MeshPhase phase of a mesh overhead job
+type MeshPhase string
+
+const (
+	// MeshBaseline phase with sidecar injection disabled
+	MeshBaseline MeshPhase = "baseline"
+	// MeshSidecar phase with sidecar injection enabled
+	MeshSidecar MeshPhase = "mesh"
+)
+
+// SchedulerCache cold versus warm scheduler cache experiment: the job is run twice, first right after restarting
+// kube-scheduler and then once it has warmed up, and the scheduling latencies of both phases are compared
+type SchedulerCache struct {
+	// Namespace namespace of the kube-scheduler pods and of their leader election lease
+	Namespace string `yaml:"namespace" json:"namespace"`
+	// LabelSelector label selector of the kube-scheduler pods deleted to restart it
+	LabelSelector string `yaml:"labelSelector" json:"labelSelector"`
+	// Lease name of the leader election lease of kube-scheduler
+	Lease string `yaml:"lease" json:"lease"`
+	// RestartCommand command restarting kube-scheduler instead of deleting its pods, required by static pods
+	RestartCommand []string `yaml:"restartCommand" json:"restartCommand,omitempty"`
+	// RestartTimeout time to wait for the restarted kube-scheduler to acquire its lease
+	RestartTimeout time.Duration `yaml:"restartTimeout" json:"restartTimeout"`
+	// WarmUp time the warm phase waits, once the cold phase finished, before starting
+	WarmUp time.Duration `yaml:"warmUp" json:"warmUp"`
+	// Job name of the job before being split in phases
+	Job string `yaml:"-" json:"job"`
+	// Phase phase run by the job
+	Phase SchedulerCachePhase `yaml:"-" json:"phase"`
+}
+
+// SchedulerCachePhase phase of a scheduler cache job
+type SchedulerCachePhase string
+
+const (
+	// SchedulerCacheCold phase run right after restarting kube-scheduler
+	SchedulerCacheCold SchedulerCachePhase = "cold"
+	// SchedulerCacheWarm phase run in steady state
+	SchedulerCacheWarm SchedulerCachePhase = "warm"
+)
+
+// WaitFor PromQL gate blocking the start of a job
+type WaitFor struct {
+	// Expr PromQL expression, the gate opens when it returns any non-zero value
+	Expr string `yaml:"expr" json:"expr"`
+	// StableFor time the expression must be met continuously
+	StableFor time.Duration `yaml:"stableFor" json:"stableFor,omitempty"`
+	// Interval between evaluations
+	Interval time.Duration `yaml:"interval" json:"interval,omitempty"`
+	// Timeout maximum time to wait
+	Timeout time.Duration `yaml:"timeout" json:"timeout,omitempty"`
+	// FailurePolicy what to do when the timeout is reached
+	FailurePolicy HookFailurePolicy `yaml:"failurePolicy" json:"failurePolicy,omitempty"`
+}
+
+// AdaptiveQPS feedback controller of the job QPS: the QPS is multiplied by DecreaseFactor when any of the
+// thresholds is exceeded, and raised by IncreaseStep up to the job QPS otherwise
+type AdaptiveQPS struct {
+	// Interval between evaluations of the API server health
+	Interval time.Duration `yaml:"interval" json:"interval"`
+	// MinQPS lower bound of the QPS
+	MinQPS float32 `yaml:"minQPS" json:"minQPS"`
+	// DecreaseFactor factor applied to the QPS when a threshold is exceeded
+	DecreaseFactor float64 `yaml:"decreaseFactor" json:"decreaseFactor"`
+	// IncreaseStep QPS added per healthy interval, a tenth of the job QPS when 0
+	IncreaseStep float32 `yaml:"increaseStep" json:"increaseStep,omitempty"`
+	// MaxThrottledRequests number of 429 responses per interval that triggers a decrease
+	MaxThrottledRequests int `yaml:"maxThrottledRequests" json:"maxThrottledRequests"`
+	// MaxInqueueRequests requests queued by API Priority and Fairness that trigger a decrease, from Prometheus
+	MaxInqueueRequests float64 `yaml:"maxInqueueRequests" json:"maxInqueueRequests,omitempty"`
+	// MaxRequestLatency P99 latency of the mutating API requests that triggers a decrease, from Prometheus
+	MaxRequestLatency time.Duration `yaml:"maxRequestLatency" json:"maxRequestLatency,omitempty"`
+}
+
+// ArrivalRate open-loop load model, creations arrive following a distribution and never wait for the previous ones
+type ArrivalRate struct {
+	// Distribution of the arrivals in time
+	Distribution ArrivalDistribution `yaml:"distribution" json:"distribution"`
+	// Rate mean number of arrivals per second
+	Rate float64 `yaml:"rate" json:"rate"`
+	// MaxInFlight maximum number of concurrent creation requests, unlimited when 0
+	MaxInFlight int `yaml:"maxInFlight" json:"maxInFlight,omitempty"`
+	// MaxQueued maximum number of arrivals waiting for a request slot, the arrivals exceeding it are dropped
+	MaxQueued int `yaml:"maxQueued" json:"maxQueued,omitempty"`
+}
+
+// QPSRamp describes how the QPS of a job evolves from StartQPS to the job QPS
+type QPSRamp struct {
+	// Profile shape of the ramp
+	Profile RampProfile `yaml:"profile" json:"profile"`
+	// StartQPS QPS when the job starts
+	StartQPS float32 `yaml:"startQPS" json:"startQPS"`
+	// Duration time to reach the job QPS
+	Duration time.Duration `yaml:"duration" json:"duration"`
+	// Steps number of increments of the step profile
+	Steps int `yaml:"steps" json:"steps,omitempty"`
+}
+
+// ListOptions defines the pagination and consistency of the LIST requests issued by waiters, object verification
+// and read jobs, and whether watches receive bookmarks
+type ListOptions struct {
+	// Limit page size of the LIST requests, each operation uses its own page size when 0
+	Limit int64 `yaml:"limit" json:"limit,omitempty"`
+	// ResourceVersion semantic of the LIST requests
+	ResourceVersion ResourceVersionSemantic `yaml:"resourceVersion" json:"resourceVersion"`
+	// DisableWatchBookmarks disables bookmark events in the watches of the waiters and measurements
+	DisableWatchBookmarks bool `yaml:"disableWatchBookmarks" json:"disableWatchBookmarks"`
 }
 
 type WaitOptions struct {
@@ -237,7 +1191,12 @@ type StatusPath struct {
 }
 
 type KubeClientProvider struct {
-	restConfig *rest.Config
+	restConfig     *rest.Config
+	kubeConfigPath string
+	kubeContext    string
+	// transport shared by the clients when the transport is tuned beyond what the REST config supports
+	transport     http.RoundTripper
+	transportOnce sync.Once
 }
 
 // Execution mode for Patch jobs
@@ -267,3 +1226,114 @@ var metricsClosing = map[MetricsClosing]struct{}{
 	AfterMeasurements: {},
 	AfterJob:          {},
 }
+
+// ErrorBreachPolicy defines what to do once a job breaches its error limits
+type ErrorBreachPolicy string
+
+const (
+	// ErrorBreachStop halts the job keeping the objects created so far
+	ErrorBreachStop ErrorBreachPolicy = "stop"
+	// ErrorBreachCleanup halts the job and garbage collects its objects
+	ErrorBreachCleanup ErrorBreachPolicy = "cleanup"
+)
+
+var errorBreachPolicies = map[ErrorBreachPolicy]struct{}{
+	ErrorBreachStop:    {},
+	ErrorBreachCleanup: {},
+}
+
+// GCPolicy defines which jobs are garbage collected depending on their outcome
+type GCPolicy string
+
+const (
+	// GCAlways garbage collects the job regardless of its outcome
+	GCAlways GCPolicy = "always"
+	// GCOnSuccess garbage collects the job when it succeeds, keeping the objects of failed jobs for debugging
+	GCOnSuccess GCPolicy = "onSuccess"
+	// GCOnFailure garbage collects the job when it fails, keeping the objects of successful jobs for inspection
+	GCOnFailure GCPolicy = "onFailure"
+)
+
+var gcPolicies = map[GCPolicy]struct{}{
+	GCAlways:    {},
+	GCOnSuccess: {},
+	GCOnFailure: {},
+}
+
+// ChurnPodDeletion defines how churn removes the pods of the churned objects
+type ChurnPodDeletion string
+
+const (
+	// ChurnPodDelete deletes the objects directly, bypassing the PodDisruptionBudgets
+	ChurnPodDelete ChurnPodDeletion = "delete"
+	// ChurnPodEvict evicts the pods through the eviction API before deleting the objects, honoring the PodDisruptionBudgets
+	ChurnPodEvict ChurnPodDeletion = "evict"
+)
+
+var churnPodDeletions = map[ChurnPodDeletion]struct{}{
+	ChurnPodDelete: {},
+	ChurnPodEvict:  {},
+}
+
+// WaiterMode defines how the object waiters track readiness
+type WaiterMode string
+
+const (
+	// WaiterModePoll lists the objects periodically
+	WaiterModePoll WaiterMode = "poll"
+	// WaiterModeWatch checks a local cache fed by a watch per resource
+	WaiterModeWatch WaiterMode = "watch"
+)
+
+var waiterModes = map[WaiterMode]struct{}{
+	WaiterModePoll:  {},
+	WaiterModeWatch: {},
+}
+
+// ResourceVersionSemantic defines the consistency of LIST requests
+type ResourceVersionSemantic string
+
+const (
+	// ResourceVersionMostRecent lists without resourceVersion, a consistent read of the most recent data
+	ResourceVersionMostRecent ResourceVersionSemantic = "mostRecent"
+	// ResourceVersionAny lists with resourceVersion=0, served from the watch cache of the API server
+	ResourceVersionAny ResourceVersionSemantic = "any"
+)
+
+var resourceVersionSemantics = map[ResourceVersionSemantic]struct{}{
+	ResourceVersionMostRecent: {},
+	ResourceVersionAny:        {},
+}
+
+// RampProfile shape of a QPS ramp
+type RampProfile string
+
+const (
+	// RampLinear increases the QPS at a constant pace
+	RampLinear RampProfile = "linear"
+	// RampStep increases the QPS in evenly spaced steps
+	RampStep RampProfile = "step"
+	// RampExponential increases the QPS by a constant factor per time unit
+	RampExponential RampProfile = "exponential"
+)
+
+var rampProfiles = map[RampProfile]struct{}{
+	RampLinear:      {},
+	RampStep:        {},
+	RampExponential: {},
+}
+
+// ArrivalDistribution distribution of the arrivals of an open-loop job
+type ArrivalDistribution string
+
+const (
+	// ArrivalConstant arrivals evenly spaced in time
+	ArrivalConstant ArrivalDistribution = "constant"
+	// ArrivalPoisson arrivals following a Poisson process, with exponentially distributed inter-arrival times
+	ArrivalPoisson ArrivalDistribution = "poisson"
+)
+
+var arrivalDistributions = map[ArrivalDistribution]struct{}{
+	ArrivalConstant: {},
+	ArrivalPoisson:  {},
+}
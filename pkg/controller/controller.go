@@ -0,0 +1,388 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	uid "github.com/google/uuid"
+	"github.com/kube-burner/kube-burner/pkg/burner"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	"github.com/kube-burner/kube-burner/pkg/util/junit"
+	"github.com/kube-burner/kube-burner/pkg/util/metrics"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// KubeBurnerJobGVR is the GroupVersionResource of the KubeBurnerJob custom resource
+var KubeBurnerJobGVR = schema.GroupVersionResource{
+	Group:    "kube-burner.io",
+	Version:  "v1alpha1",
+	Resource: "kubeburnerjobs",
+}
+
+// KubeBurnerJob phases
+const (
+	PhasePending   = "Pending"
+	PhaseRunning   = "Running"
+	PhaseSucceeded = "Succeeded"
+	PhaseFailed    = "Failed"
+)
+
+// Status condition types of a KubeBurnerJob
+const (
+	ConditionProgressing  = "Progressing"
+	ConditionMeasurements = "MeasurementsPassed"
+	ConditionAlerts       = "AlertsPassed"
+	ConditionComplete     = "Complete"
+	ConditionFailed       = "Failed"
+)
+
+// Default benchmark timeout when not specified in the KubeBurnerJob
+const defaultTimeout = 4 * time.Hour
+
+// Controller runs the benchmarks declared by KubeBurnerJob objects
+type Controller struct {
+	kubeClientProvider *config.KubeClientProvider
+	dynamicClient      dynamic.Interface
+	namespace          string
+	queue              workqueue.TypedRateLimitingInterface[string]
+}
+
+// benchmark KubeBurnerJob validated and ready to run
+type benchmark struct {
+	namespace      string
+	name           string
+	uuid           string
+	configSpec     config.Spec
+	metricsProfile string
+	alertProfile   string
+}
+
+// NewController creates a controller watching KubeBurnerJobs in the given namespace, all namespaces when empty
+func NewController(kubeClientProvider *config.KubeClientProvider, namespace string) *Controller {
+	_, restConfig := kubeClientProvider.DefaultClientSet()
+	return &Controller{
+		kubeClientProvider: kubeClientProvider,
+		dynamicClient:      dynamic.NewForConfigOrDie(restConfig),
+		namespace:          namespace,
+		queue:              workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[string]()),
+	}
+}
+
+// Run starts the controller, benchmarks are executed one at a time until the context is cancelled
+func (c *Controller) Run(ctx context.Context) error {
+	defer c.queue.ShutDown()
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(c.dynamicClient, 0, c.namespace, nil)
+	informer := factory.ForResource(KubeBurnerJobGVR).Informer()
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj any) {
+			c.enqueue(obj.(*unstructured.Unstructured))
+		},
+	})
+	factory.Start(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return fmt.Errorf("error syncing KubeBurnerJob informer")
+	}
+	c.failInterrupted(ctx, informer.GetStore().List())
+	go func() {
+		<-ctx.Done()
+		c.queue.ShutDown()
+	}()
+	log.Infof("Watching KubeBurnerJobs")
+	for {
+		key, shutdown := c.queue.Get()
+		if shutdown {
+			return nil
+		}
+		if err := c.reconcile(ctx, key); err != nil {
+			log.Errorf("Error reconciling KubeBurnerJob %s, retrying: %v", key, err)
+			c.queue.AddRateLimited(key)
+		} else {
+			c.queue.Forget(key)
+		}
+		c.queue.Done(key)
+	}
+}
+
+// enqueue queues the KubeBurnerJobs that haven't been executed yet
+func (c *Controller) enqueue(kbj *unstructured.Unstructured) {
+	phase, _, _ := unstructured.NestedString(kbj.Object, "status", "phase")
+	if phase != "" && phase != PhasePending {
+		return
+	}
+	key, err := cache.MetaNamespaceKeyFunc(kbj)
+	if err != nil {
+		log.Error(err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+// failInterrupted fails the KubeBurnerJobs left running by a previous controller, their benchmarks were interrupted
+func (c *Controller) failInterrupted(ctx context.Context, objs []any) {
+	for _, obj := range objs {
+		kbj := obj.(*unstructured.Unstructured)
+		if phase, _, _ := unstructured.NestedString(kbj.Object, "status", "phase"); phase != PhaseRunning {
+			continue
+		}
+		log.Warnf("KubeBurnerJob %s/%s was left running by a previous controller, marking it as failed", kbj.GetNamespace(), kbj.GetName())
+		c.updateStatus(ctx, kbj.GetNamespace(), kbj.GetName(), func(status map[string]any) {
+			finish(status, PhaseFailed, -1, "Interrupted", "the controller restarted while the benchmark was running")
+		})
+	}
+}
+
+// reconcile runs the benchmark of a KubeBurnerJob, returns an error when it has to be retried
+func (c *Controller) reconcile(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		log.Error(err)
+		return nil
+	}
+	kbj, err := c.dynamicClient.Resource(KubeBurnerJobGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if phase, _, _ := unstructured.NestedString(kbj.Object, "status", "phase"); phase != "" && phase != PhasePending {
+		return nil
+	}
+	// The files of the configmap are written into a directory of the benchmark, so that those of previous ones aren't picked up.
+	// Benchmarks run one at a time, their relative paths are resolved from it
+	workDir, err := os.MkdirTemp("", fmt.Sprintf("kube-burner-%s-", name))
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(workDir)
+	restoreWorkDir, err := chdir(workDir)
+	if err != nil {
+		return err
+	}
+	defer restoreWorkDir()
+	b, err := c.validate(kbj, workDir)
+	if err != nil {
+		log.Errorf("Invalid KubeBurnerJob %s: %v", key, err)
+		c.updateStatus(ctx, namespace, name, func(status map[string]any) {
+			finish(status, PhaseFailed, -1, "InvalidSpec", err.Error())
+		})
+		return nil
+	}
+	log.Infof("Running KubeBurnerJob %s with UUID %s", key, b.uuid)
+	c.updateStatus(ctx, namespace, name, func(status map[string]any) {
+		status["phase"] = PhaseRunning
+		status["uuid"] = b.uuid
+		status["startTime"] = now()
+		status["completedJobs"] = int64(0)
+		setCondition(status, ConditionProgressing, metav1.ConditionTrue, "Started", "benchmark started")
+	})
+	junit.Reset()
+	burner.ObservePhases(c.progress(ctx, b))
+	rc, err := c.runBenchmark(ctx, b)
+	burner.ObservePhases(nil)
+	// Records the outcome even when the controller is stopping
+	statusCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	c.updateStatus(statusCtx, namespace, name, func(status map[string]any) {
+		setResultCondition(status, ConditionMeasurements, junit.SuiteThresholds, "ThresholdsMet", "ThresholdsFailed", "latency thresholds and data-quality checks")
+		setResultCondition(status, ConditionAlerts, junit.SuiteAlerts, "NoAlerts", "AlertsFired", "alerts")
+		switch {
+		case ctx.Err() != nil:
+			finish(status, PhaseFailed, rc, "Interrupted", fmt.Sprintf("the controller stopped while the benchmark was running: %v", err))
+		case err != nil || rc != 0:
+			message := fmt.Sprintf("benchmark finished with return code %d", rc)
+			if err != nil {
+				message = fmt.Sprintf("%s: %v", message, err)
+			}
+			finish(status, PhaseFailed, rc, PhaseFailed, message)
+		default:
+			finish(status, PhaseSucceeded, rc, PhaseSucceeded, "benchmark finished successfully")
+		}
+	})
+	return nil
+}
+
+// validate checks the spec of the KubeBurnerJob and parses the configuration of its benchmark
+func (c *Controller) validate(kbj *unstructured.Unstructured, workDir string) (benchmark, error) {
+	b := benchmark{namespace: kbj.GetNamespace(), name: kbj.GetName()}
+	configMap, _, _ := unstructured.NestedString(kbj.Object, "spec", "configMap")
+	b.uuid, _, _ = unstructured.NestedString(kbj.Object, "spec", "uuid")
+	rawTimeout, _, _ := unstructured.NestedString(kbj.Object, "spec", "timeout")
+	if b.uuid == "" {
+		b.uuid = uid.NewString()
+	}
+	if configMap == "" {
+		return b, fmt.Errorf("spec.configMap is required")
+	}
+	timeout := defaultTimeout
+	if rawTimeout != "" {
+		var err error
+		if timeout, err = time.ParseDuration(rawTimeout); err != nil {
+			return b, fmt.Errorf("invalid timeout: %v", err)
+		}
+	}
+	clientSet, _ := c.kubeClientProvider.DefaultClientSet()
+	var err error
+	b.metricsProfile, b.alertProfile, err = config.WriteConfigMap(clientSet, configMap, b.namespace, workDir)
+	if err != nil {
+		return b, err
+	}
+	configFileReader, err := fileutils.GetWorkloadReader(filepath.Join(workDir, "config.yml"), nil)
+	if err != nil {
+		return b, err
+	}
+	b.configSpec, err = config.ParseWithUserdata(b.uuid, timeout, configFileReader, nil, false, nil)
+	return b, err
+}
+
+// runBenchmark executes the benchmark, which is halted when the context is cancelled
+func (c *Controller) runBenchmark(ctx context.Context, b benchmark) (int, error) {
+	metricsScraper, err := metrics.NewScraper(metrics.ScraperConfig{
+		ConfigSpec:         &b.configSpec,
+		AlertProfile:       b.alertProfile,
+		MetricsProfile:     b.metricsProfile,
+		KubeClientProvider: c.kubeClientProvider,
+	})
+	if err != nil {
+		return 1, err
+	}
+	defer metricsScraper.Close()
+	return burner.RunWithContext(ctx, b.configSpec, c.kubeClientProvider, metricsScraper, nil, nil)
+}
+
+// progress returns the phase observer reporting the progress of the benchmark in the KubeBurnerJob status
+func (c *Controller) progress(ctx context.Context, b benchmark) burner.PhaseObserver {
+	return func(job, phase, message string) {
+		c.updateStatus(ctx, b.namespace, b.name, func(status map[string]any) {
+			switch phase {
+			case burner.PhaseJobStarted:
+				status["currentJob"] = job
+			case burner.PhaseJobFinished:
+				completedJobs, _, _ := unstructured.NestedInt64(status, "completedJobs")
+				status["completedJobs"] = completedJobs + 1
+			}
+			setCondition(status, ConditionProgressing, metav1.ConditionTrue, phase, message)
+		})
+	}
+}
+
+// updateStatus applies the given change to the status subresource of the KubeBurnerJob, retrying on conflicts
+func (c *Controller) updateStatus(ctx context.Context, namespace, name string, change func(status map[string]any)) {
+	client := c.dynamicClient.Resource(KubeBurnerJobGVR).Namespace(namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		kbj, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		status, _, _ := unstructured.NestedMap(kbj.Object, "status")
+		if status == nil {
+			status = map[string]any{}
+		}
+		change(status)
+		unstructured.SetNestedMap(kbj.Object, status, "status")
+		_, err = client.UpdateStatus(ctx, kbj, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		log.Errorf("Error updating status of KubeBurnerJob %s/%s: %v", namespace, name, err)
+	}
+}
+
+// finish sets the final phase of the KubeBurnerJob and its Complete or Failed condition
+func finish(status map[string]any, phase string, rc int, reason, message string) {
+	status["phase"] = phase
+	status["completionTime"] = now()
+	status["returnCode"] = int64(rc)
+	delete(status, "currentJob")
+	setCondition(status, ConditionProgressing, metav1.ConditionFalse, reason, message)
+	conditionType := ConditionComplete
+	if phase == PhaseFailed {
+		conditionType = ConditionFailed
+	}
+	setCondition(status, conditionType, metav1.ConditionTrue, reason, message)
+}
+
+// setResultCondition sets a condition from the outcome of the test cases of the given junit suite
+func setResultCondition(status map[string]any, conditionType, suite, passedReason, failedReason, what string) {
+	tests, failures := junit.Tests(suite), junit.Failures(suite)
+	switch {
+	case tests == 0:
+		setCondition(status, conditionType, metav1.ConditionUnknown, "NotEvaluated", fmt.Sprintf("no %s evaluated", what))
+	case len(failures) > 0:
+		setCondition(status, conditionType, metav1.ConditionFalse, failedReason, strings.Join(failures, "\n"))
+	default:
+		setCondition(status, conditionType, metav1.ConditionTrue, passedReason, fmt.Sprintf("%d %s passed", tests, what))
+	}
+}
+
+// setCondition adds or replaces a condition of the status, its transition time only changes along with its status
+func setCondition(status map[string]any, conditionType string, conditionStatus metav1.ConditionStatus, reason, message string) {
+	conditions, _, _ := unstructured.NestedSlice(status, "conditions")
+	condition := map[string]any{
+		"type":               conditionType,
+		"status":             string(conditionStatus),
+		"lastTransitionTime": now(),
+		"reason":             reason,
+		"message":            message,
+	}
+	for i, existing := range conditions {
+		existing, _ := existing.(map[string]any)
+		if existing["type"] != conditionType {
+			continue
+		}
+		if existing["status"] == string(conditionStatus) {
+			condition["lastTransitionTime"] = existing["lastTransitionTime"]
+		}
+		conditions[i] = condition
+		status["conditions"] = conditions
+		return
+	}
+	status["conditions"] = append(conditions, condition)
+}
+
+func now() string {
+	return metav1.Now().UTC().Format(time.RFC3339)
+}
+
+// chdir changes the working directory, returning the function restoring the previous one
+func chdir(dir string) (func(), error) {
+	previous, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chdir(dir); err != nil {
+		return nil, err
+	}
+	return func() {
+		if err := os.Chdir(previous); err != nil {
+			log.Errorf("Error restoring the working directory %s: %v", previous, err)
+		}
+	}, nil
+}
@@ -0,0 +1,332 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboards
+
+import (
+	"fmt"
+
+	"github.com/kube-burner/kube-burner/pkg/util/metrics"
+)
+
+// Supported datasources: Elasticsearch or OpenSearch indexers, and Prometheus fed by the remote write indexer
+const (
+	DatasourceElasticsearch = "elasticsearch"
+	DatasourcePrometheus    = "prometheus"
+)
+
+const (
+	// datasourceInput input Grafana asks for when importing the dashboard
+	datasourceInput = "DS_KUBE_BURNER"
+	// Metric name of the pod latency quantiles
+	podLatencyQuantiles = "podLatencyQuantilesMeasurement"
+	schemaVersion       = 39
+	// Width of the grid of the dashboard
+	gridWidth = 24
+)
+
+// Dashboard Grafana dashboard
+type Dashboard struct {
+	Inputs        []input    `json:"__inputs,omitempty"`
+	UID           string     `json:"uid"`
+	Title         string     `json:"title"`
+	Description   string     `json:"description"`
+	Tags          []string   `json:"tags"`
+	Editable      bool       `json:"editable"`
+	SchemaVersion int        `json:"schemaVersion"`
+	Time          timeRange  `json:"time"`
+	Templating    templating `json:"templating"`
+	Panels        []panel    `json:"panels"`
+}
+
+// input of the dashboard filled when it's imported
+type input struct {
+	Name     string `json:"name"`
+	Label    string `json:"label"`
+	Type     string `json:"type"`
+	PluginID string `json:"pluginId"`
+}
+
+type timeRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type templating struct {
+	List []variable `json:"list"`
+}
+
+// variable template variable of the dashboard
+type variable struct {
+	Name       string        `json:"name"`
+	Label      string        `json:"label"`
+	Type       string        `json:"type"`
+	Datasource datasourceRef `json:"datasource"`
+	Query      string        `json:"query"`
+	Definition string        `json:"definition"`
+	// Refresh 2 refreshes the values when the time range changes
+	Refresh int `json:"refresh"`
+	Sort    int `json:"sort"`
+}
+
+type datasourceRef struct {
+	Type string `json:"type"`
+	UID  string `json:"uid"`
+}
+
+type gridPos struct {
+	H int `json:"h"`
+	W int `json:"w"`
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+type panel struct {
+	ID              int              `json:"id"`
+	Type            string           `json:"type"`
+	Title           string           `json:"title"`
+	GridPos         gridPos          `json:"gridPos"`
+	Datasource      *datasourceRef   `json:"datasource,omitempty"`
+	Targets         []map[string]any `json:"targets,omitempty"`
+	Transformations []map[string]any `json:"transformations,omitempty"`
+	FieldConfig     *fieldConfig     `json:"fieldConfig,omitempty"`
+	Collapsed       bool             `json:"collapsed,omitempty"`
+}
+
+type fieldConfig struct {
+	Defaults  map[string]any `json:"defaults"`
+	Overrides []any          `json:"overrides"`
+}
+
+// layout places the panels of the dashboard from left to right and top to bottom
+type layout struct {
+	dashboard  *Dashboard
+	datasource datasourceRef
+	x, y       int
+	// height of the tallest panel of the current line
+	height int
+}
+
+// New returns the dashboard of the documents indexed by kube-burner into the given type of datasource: the job
+// summaries, the pod latency quantiles and the alerts of a benchmark, selected by its UUID. Grafana asks for the
+// datasource when importing the dashboard, unless its UID is given
+func New(datasourceType, datasourceUID string) (*Dashboard, error) {
+	d := &Dashboard{
+		UID:           "kube-burner-" + datasourceType,
+		Title:         "kube-burner",
+		Description:   "Results of the kube-burner benchmarks, generated by kube-burner dashboards",
+		Tags:          []string{"kube-burner"},
+		Editable:      true,
+		SchemaVersion: schemaVersion,
+		Time:          timeRange{From: "now-7d", To: "now"},
+	}
+	l := &layout{dashboard: d, datasource: datasourceRef{Type: datasourceType, UID: datasourceUID}}
+	if datasourceUID == "" {
+		l.datasource.UID = fmt.Sprintf("${%s}", datasourceInput)
+		d.Inputs = []input{{Name: datasourceInput, Label: "kube-burner datasource", Type: "datasource", PluginID: datasourceType}}
+	}
+	switch datasourceType {
+	case DatasourceElasticsearch:
+		l.elasticsearch()
+	case DatasourcePrometheus:
+		l.prometheus()
+	default:
+		return nil, fmt.Errorf("unsupported datasource %s, expected %s or %s", datasourceType, DatasourceElasticsearch, DatasourcePrometheus)
+	}
+	return d, nil
+}
+
+// row starts a row of panels
+func (l *layout) row(title string) {
+	l.newLine()
+	l.add(panel{Type: "row", Title: title}, gridWidth, 1)
+	l.newLine()
+}
+
+// add places the panel after the previous one, or in a new line when it doesn't fit
+func (l *layout) add(p panel, width, height int) {
+	if l.x+width > gridWidth {
+		l.newLine()
+	}
+	p.ID = len(l.dashboard.Panels) + 1
+	p.GridPos = gridPos{H: height, W: width, X: l.x, Y: l.y}
+	if p.Type != "row" {
+		p.Datasource = &l.datasource
+	}
+	l.dashboard.Panels = append(l.dashboard.Panels, p)
+	l.x += width
+	l.height = max(l.height, height)
+}
+
+func (l *layout) newLine() {
+	if l.x > 0 {
+		l.x, l.y, l.height = 0, l.y+l.height, 0
+	}
+}
+
+// uuidVariable adds the UUID variable, listing the benchmarks with the given query
+func (l *layout) uuidVariable(query string) {
+	l.dashboard.Templating.List = append(l.dashboard.Templating.List, variable{
+		Name:       "uuid",
+		Label:      "UUID",
+		Type:       "query",
+		Datasource: l.datasource,
+		Query:      query,
+		Definition: query,
+		Refresh:    2,
+	})
+}
+
+// filterFields transformation keeping the given fields, in that order
+func filterFields(fields ...string) []map[string]any {
+	indexByName := make(map[string]int)
+	for i, field := range fields {
+		indexByName[field] = i
+	}
+	return []map[string]any{
+		{"id": "filterFieldsByName", "options": map[string]any{"include": map[string]any{"names": fields}}},
+		{"id": "organize", "options": map[string]any{"indexByName": indexByName}},
+	}
+}
+
+// unit field configuration setting the unit of the numeric fields
+func unit(unit string) *fieldConfig {
+	return &fieldConfig{Defaults: map[string]any{"unit": unit}, Overrides: []any{}}
+}
+
+// elasticsearch adds the panels querying the documents indexed by the Elasticsearch and OpenSearch indexers
+func (l *layout) elasticsearch() {
+	l.uuidVariable(`{"find": "terms", "field": "uuid.keyword", "query": "metricName.keyword: jobSummary"}`)
+	documents := func(metricName string) map[string]any {
+		return map[string]any{
+			"refId":      "A",
+			"query":      fmt.Sprintf(`metricName.keyword: "%s" AND uuid.keyword: "$uuid"`, metricName),
+			"metrics":    []map[string]any{{"id": "1", "type": "raw_data", "settings": map[string]any{"size": "500"}}},
+			"bucketAggs": []any{},
+			"timeField":  "timestamp",
+		}
+	}
+	byTerm := func(metricName, field, term string) map[string]any {
+		target := documents(metricName)
+		target["metrics"] = []map[string]any{{"id": "1", "type": "max", "field": field}}
+		target["bucketAggs"] = []map[string]any{{
+			"id":       "2",
+			"type":     "terms",
+			"field":    term,
+			"settings": map[string]any{"order": "desc", "orderBy": "1", "size": "20", "min_doc_count": "1"},
+		}}
+		return target
+	}
+	l.row("Job summary")
+	l.add(panel{
+		Type:            "table",
+		Title:           "Jobs",
+		Targets:         []map[string]any{documents("jobSummary")},
+		Transformations: filterFields("timestamp", "jobConfig.name", "jobConfig.jobType", "jobConfig.jobIterations", "elapsedTime", "achievedQps", "passed", "executionErrors"),
+	}, 16, 8)
+	l.add(panel{
+		Type:        "barchart",
+		Title:       "Elapsed time",
+		Targets:     []map[string]any{byTerm("jobSummary", "elapsedTime", "jobConfig.name.keyword")},
+		FieldConfig: unit("s"),
+	}, 8, 8)
+	l.row("Pod latency")
+	l.add(panel{
+		Type:            "table",
+		Title:           "Pod latency quantiles",
+		Targets:         []map[string]any{documents(podLatencyQuantiles)},
+		Transformations: filterFields("jobName", "quantileName", "P50", "P95", "P99", "avg", "max", "samples"),
+		FieldConfig:     unit("ms"),
+	}, 16, 8)
+	l.add(panel{
+		Type:        "barchart",
+		Title:       "P99 pod latency",
+		Targets:     []map[string]any{byTerm(podLatencyQuantiles, "P99", "quantileName.keyword")},
+		FieldConfig: unit("ms"),
+	}, 8, 8)
+	l.row("Alerts")
+	l.add(panel{
+		Type:            "table",
+		Title:           "Alerts",
+		Targets:         []map[string]any{documents("alert")},
+		Transformations: filterFields("timestamp", "severity", "description", "value"),
+	}, gridWidth, 8)
+}
+
+// prometheus adds the panels querying the series written by the remote write indexer: kube_burner_<metricName> for
+// the documents with a value, and kube_burner_<metricName>_<field> for each numeric field of the rest
+func (l *layout) prometheus() {
+	l.uuidVariable(fmt.Sprintf("label_values(%s, uuid)", metrics.SeriesName("jobSummary", "elapsedTime")))
+	// Instant queries of the last sample of the benchmark in the time range, as a table
+	last := func(refID, series string) map[string]any {
+		return map[string]any{
+			"refId":   refID,
+			"expr":    fmt.Sprintf(`last_over_time(%s{uuid="$uuid"}[$__range])`, series),
+			"instant": true,
+			"range":   false,
+			"format":  "table",
+		}
+	}
+	// merge joins the tables of the queries, naming their values
+	merge := func(exclude []string, values map[string]string) []map[string]any {
+		excludeByName := map[string]bool{"Time": true, "__name__": true}
+		for _, field := range exclude {
+			excludeByName[field] = true
+		}
+		return []map[string]any{
+			{"id": "merge", "options": map[string]any{}},
+			{"id": "organize", "options": map[string]any{"excludeByName": excludeByName, "renameByName": values}},
+		}
+	}
+	l.row("Job summary")
+	l.add(panel{
+		Type:            "table",
+		Title:           "Jobs",
+		Targets:         []map[string]any{last("A", metrics.SeriesName("jobSummary", "elapsedTime")), last("B", metrics.SeriesName("jobSummary", "achievedQps"))},
+		Transformations: merge(nil, map[string]string{"Value #A": "elapsedTime", "Value #B": "achievedQps"}),
+	}, gridWidth, 8)
+	l.row("Pod latency")
+	var quantiles []map[string]any
+	values := make(map[string]string)
+	for i, field := range []string{"P50", "P95", "P99", "avg", "max"} {
+		refID := string(rune('A' + i))
+		quantiles = append(quantiles, last(refID, metrics.SeriesName(podLatencyQuantiles, field)))
+		values["Value #"+refID] = field
+	}
+	l.add(panel{
+		Type:            "table",
+		Title:           "Pod latency quantiles",
+		Targets:         quantiles,
+		Transformations: merge([]string{"uuid"}, values),
+		FieldConfig:     unit("ms"),
+	}, 16, 8)
+	p99 := last("A", metrics.SeriesName(podLatencyQuantiles, "P99"))
+	p99["expr"] = fmt.Sprintf("max by (quantileName) (%s)", p99["expr"])
+	l.add(panel{
+		Type:            "barchart",
+		Title:           "P99 pod latency",
+		Targets:         []map[string]any{p99},
+		Transformations: merge(nil, map[string]string{"Value": "P99"}),
+		FieldConfig:     unit("ms"),
+	}, 8, 8)
+	l.row("Alerts")
+	alerts := last("A", metrics.SeriesName("alert", ""))
+	alerts["expr"] = fmt.Sprintf(`count_over_time(%s{uuid="$uuid"}[$__range])`, metrics.SeriesName("alert", ""))
+	l.add(panel{
+		Type:            "table",
+		Title:           "Alerts",
+		Targets:         []map[string]any{alerts},
+		Transformations: merge([]string{"uuid"}, map[string]string{"Value": "occurrences"}),
+	}, gridWidth, 8)
+}
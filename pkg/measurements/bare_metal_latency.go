@@ -0,0 +1,598 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	bareMetalLatencyMeasurement          = "bareMetalLatencyMeasurement"
+	bareMetalLatencyQuantilesMeasurement = "bareMetalLatencyQuantilesMeasurement"
+	bareMetalSummaryMeasurement          = "bareMetalSummaryMeasurement"
+	machineLatencyMeasurement            = "machineLatencyMeasurement"
+	bmRegistering                        = "Registering"
+	bmInspecting                         = "Inspecting"
+	bmAvailable                          = "Available"
+	bmProvisioning                       = "Provisioning"
+	bmProvisioned                        = "Provisioned"
+	bmNodeReady                          = "NodeReady"
+	machineProvisioned                   = "MachineProvisioned"
+	machineRunning                       = "MachineRunning"
+	metal3Group                          = "metal3.io"
+	clusterAPIGroup                      = "cluster.x-k8s.io"
+	// Prefix of the metrics keys of the Machines, the BareMetalHosts use their UID
+	machineKeyPrefix = "machine/"
+)
+
+var (
+	supportedBareMetalConditions = map[string]struct{}{
+		bmRegistering:      {},
+		bmInspecting:       {},
+		bmAvailable:        {},
+		bmProvisioning:     {},
+		bmProvisioned:      {},
+		bmNodeReady:        {},
+		machineProvisioned: {},
+		machineRunning:     {},
+	}
+	// Provisioning states of the hosts being operated by the baremetal-operator, bounded by its provisioning limit
+	bareMetalTransientStates = map[string]struct{}{
+		"registering":                {},
+		"inspecting":                 {},
+		"match profile":              {},
+		"preparing":                  {},
+		"provisioning":               {},
+		"deprovisioning":             {},
+		"powering off before delete": {},
+	}
+)
+
+// bareMetalHostMetric holds the provisioning state machine latencies of a BareMetalHost
+type bareMetalHostMetric struct {
+	// Timestamp creation time of the hosts created during the job, or time their first state change was observed
+	Timestamp           time.Time `json:"timestamp"`
+	registering         time.Time
+	RegisteringLatency  int `json:"registeringLatency"`
+	inspecting          time.Time
+	InspectingLatency   int `json:"inspectingLatency"`
+	available           time.Time
+	AvailableLatency    int `json:"availableLatency"`
+	provisioning        time.Time
+	ProvisioningLatency int `json:"provisioningLatency"`
+	provisioned         time.Time
+	ProvisionedLatency  int `json:"provisionedLatency"`
+	nodeReady           time.Time
+	NodeReadyLatency    int    `json:"nodeReadyLatency"`
+	NodeName            string `json:"nodeName,omitempty"`
+	State               string `json:"state"`
+	// Errors times the host entered an error, ErrorType the last one
+	Errors        int    `json:"errors"`
+	ErrorType     string `json:"errorType,omitempty"`
+	lastErrorType string
+	UUID          string `json:"uuid"`
+	Name          string `json:"hostName"`
+	Namespace     string `json:"namespace"`
+	JobName       string `json:"jobName,omitempty"`
+	MetricName    string `json:"metricName"`
+	Metadata      any    `json:"metadata,omitempty"`
+}
+
+// machineMetric holds the phase latencies of a Cluster API Machine
+type machineMetric struct {
+	Timestamp          time.Time `json:"timestamp"`
+	provisioned        time.Time
+	ProvisionedLatency int `json:"provisionedLatency"`
+	running            time.Time
+	RunningLatency     int    `json:"runningLatency"`
+	Phase              string `json:"phase"`
+	Failed             bool   `json:"failed"`
+	FailureReason      string `json:"failureReason,omitempty"`
+	NodeName           string `json:"nodeName,omitempty"`
+	Cluster            string `json:"cluster,omitempty"`
+	UUID               string `json:"uuid"`
+	Name               string `json:"machineName"`
+	Namespace          string `json:"namespace"`
+	JobName            string `json:"jobName,omitempty"`
+	MetricName         string `json:"metricName"`
+	Metadata           any    `json:"metadata,omitempty"`
+}
+
+// bareMetalSummary holds the outcome of the host provisioning of a job
+type bareMetalSummary struct {
+	Timestamp   time.Time `json:"timestamp"`
+	MetricName  string    `json:"metricName"`
+	UUID        string    `json:"uuid"`
+	JobName     string    `json:"jobName,omitempty"`
+	Hosts       int       `json:"hosts"`
+	Provisioned int       `json:"provisioned"`
+	Failed      int       `json:"failed"`
+	// PeakConcurrency maximum number of hosts in transient provisioning states at the same time
+	PeakConcurrency int            `json:"peakConcurrency"`
+	ErrorTypes      map[string]int `json:"errorTypes,omitempty"`
+	Machines        int            `json:"machines"`
+	MachinesFailed  int            `json:"machinesFailed"`
+	Metadata        any            `json:"metadata,omitempty"`
+}
+
+// readyNode Ready node, by the BareMetalHost it runs on
+type readyNode struct {
+	name  string
+	ready time.Time
+}
+
+type bareMetalLatency struct {
+	BaseMeasurement
+	startTime time.Time
+	// nodes Ready nodes by provider ID
+	nodes sync.Map
+	mu    sync.Mutex
+	// hostStates current provisioning state of every host, to calculate the concurrency
+	hostStates      map[string]string
+	peakConcurrency int
+	summary         []any
+}
+
+type bareMetalLatencyMeasurementFactory struct {
+	BaseMeasurementFactory
+}
+
+func newBareMetalLatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any) (MeasurementFactory, error) {
+	if err := verifyMeasurementConfig(measurement, supportedBareMetalConditions); err != nil {
+		return nil, err
+	}
+	return bareMetalLatencyMeasurementFactory{
+		BaseMeasurementFactory: NewBaseMeasurementFactory(configSpec, measurement, metadata),
+	}, nil
+}
+
+func (bmf bareMetalLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) Measurement {
+	return &bareMetalLatency{
+		BaseMeasurement: bmf.NewBaseLatency(jobConfig, clientSet, restConfig, bareMetalLatencyMeasurement, bareMetalLatencyQuantilesMeasurement, embedCfg),
+	}
+}
+
+// handleCreateHost tracks every host, those created before the measurement started are measured from their first
+// state change
+func (b *bareMetalLatency) handleCreateHost(obj any) {
+	host := obj.(*unstructured.Unstructured)
+	hm := bareMetalHostMetric{
+		Name:       host.GetName(),
+		Namespace:  host.GetNamespace(),
+		MetricName: bareMetalLatencyMeasurement,
+		UUID:       b.Uuid,
+		JobName:    b.JobConfig.Name,
+		Metadata:   b.Metadata,
+	}
+	if creationTime := host.GetCreationTimestamp().Time; !creationTime.Before(b.startTime) {
+		hm.Timestamp = creationTime.UTC()
+	} else {
+		hm.State, _, _ = unstructured.NestedString(host.Object, "status", "provisioning", "state")
+		hm.lastErrorType, _, _ = unstructured.NestedString(host.Object, "status", "errorType")
+	}
+	b.metrics.LoadOrStore(string(host.GetUID()), hm)
+	b.handleUpdateHost(obj)
+}
+
+// handleUpdateHost records the first time each provisioning state is observed, along with the errors of the host
+func (b *bareMetalLatency) handleUpdateHost(obj any) {
+	host := obj.(*unstructured.Unstructured)
+	value, exists := b.metrics.Load(string(host.GetUID()))
+	if !exists {
+		return
+	}
+	hm := value.(bareMetalHostMetric)
+	now := time.Now().UTC()
+	state, _, _ := unstructured.NestedString(host.Object, "status", "provisioning", "state")
+	if state != hm.State {
+		log.Debugf("BareMetalHost %s/%s %s", host.GetNamespace(), host.GetName(), state)
+		if hm.Timestamp.IsZero() {
+			hm.Timestamp = now
+		}
+		hm.State = state
+		switch state {
+		case "registering":
+			if hm.registering.IsZero() {
+				hm.registering = now
+			}
+		case "inspecting":
+			if hm.inspecting.IsZero() {
+				hm.inspecting = now
+			}
+		// Hosts were ready before being available
+		case "available", "ready":
+			if hm.available.IsZero() {
+				hm.available = now
+			}
+		case "provisioning":
+			if hm.provisioning.IsZero() {
+				hm.provisioning = now
+			}
+		case "provisioned":
+			if hm.provisioned.IsZero() {
+				hm.provisioned = now
+			}
+		}
+	}
+	errorType, _, _ := unstructured.NestedString(host.Object, "status", "errorType")
+	if errorType != "" && errorType != hm.lastErrorType {
+		errorMessage, _, _ := unstructured.NestedString(host.Object, "status", "errorMessage")
+		log.Debugf("BareMetalHost %s/%s %s: %s", host.GetNamespace(), host.GetName(), errorType, errorMessage)
+		hm.Errors++
+		hm.ErrorType = errorType
+	}
+	hm.lastErrorType = errorType
+	b.metrics.Store(string(host.GetUID()), hm)
+	b.updateConcurrency(string(host.GetUID()), state)
+}
+
+// updateConcurrency records the state of the host and the peak of hosts in transient states
+func (b *bareMetalLatency) updateConcurrency(uid, state string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if state == "" {
+		delete(b.hostStates, uid)
+	} else {
+		b.hostStates[uid] = state
+	}
+	var concurrency int
+	for _, s := range b.hostStates {
+		if _, transient := bareMetalTransientStates[s]; transient {
+			concurrency++
+		}
+	}
+	b.peakConcurrency = max(b.peakConcurrency, concurrency)
+}
+
+func (b *bareMetalLatency) handleDeleteHost(obj any) {
+	if host, ok := obj.(*unstructured.Unstructured); ok {
+		b.updateConcurrency(string(host.GetUID()), "")
+	}
+}
+
+// handleNode records the Ready nodes by provider ID
+func (b *bareMetalLatency) handleNode(obj any) {
+	node := obj.(*corev1.Node)
+	if node.Spec.ProviderID == "" {
+		return
+	}
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady && c.Status == corev1.ConditionTrue {
+			b.nodes.Store(node.Spec.ProviderID, readyNode{name: node.Name, ready: c.LastTransitionTime.UTC()})
+		}
+	}
+}
+
+// handleCreateMachine tracks the Machines created after the measurement started
+func (b *bareMetalLatency) handleCreateMachine(obj any) {
+	machine := obj.(*unstructured.Unstructured)
+	if machine.GetCreationTimestamp().Time.Before(b.startTime) {
+		return
+	}
+	b.metrics.LoadOrStore(machineKeyPrefix+string(machine.GetUID()), machineMetric{
+		Timestamp:  machine.GetCreationTimestamp().UTC(),
+		Name:       machine.GetName(),
+		Namespace:  machine.GetNamespace(),
+		Cluster:    machine.GetLabels()["cluster.x-k8s.io/cluster-name"],
+		MetricName: machineLatencyMeasurement,
+		UUID:       b.Uuid,
+		JobName:    b.JobConfig.Name,
+		Metadata:   b.Metadata,
+	})
+	b.handleUpdateMachine(obj)
+}
+
+// handleUpdateMachine records the first time the Machine is provisioned and running, along with its failures
+func (b *bareMetalLatency) handleUpdateMachine(obj any) {
+	machine := obj.(*unstructured.Unstructured)
+	key := machineKeyPrefix + string(machine.GetUID())
+	value, exists := b.metrics.Load(key)
+	if !exists {
+		return
+	}
+	mm := value.(machineMetric)
+	now := time.Now().UTC()
+	mm.Phase, _, _ = unstructured.NestedString(machine.Object, "status", "phase")
+	mm.NodeName, _, _ = unstructured.NestedString(machine.Object, "status", "nodeRef", "name")
+	switch mm.Phase {
+	case "Provisioned":
+		if mm.provisioned.IsZero() {
+			mm.provisioned = now
+		}
+	case "Running":
+		// Provisioned can be skipped between two observations
+		if mm.provisioned.IsZero() {
+			mm.provisioned = now
+		}
+		if mm.running.IsZero() {
+			log.Debugf("Machine %s/%s running on node %s", machine.GetNamespace(), machine.GetName(), mm.NodeName)
+			mm.running = now
+		}
+	}
+	failureReason, _, _ := unstructured.NestedString(machine.Object, "status", "failureReason")
+	if mm.Phase == "Failed" || failureReason != "" {
+		mm.Failed = true
+		mm.FailureReason = failureReason
+	}
+	b.metrics.Store(key, mm)
+}
+
+// Start starts the BareMetalHost and node watchers, along with the Machine one when Cluster API is installed
+func (b *bareMetalLatency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	b.summary = nil
+	b.nodes = sync.Map{}
+	b.hostStates = make(map[string]string)
+	b.peakConcurrency = 0
+	// Creation timestamps have a precision of one second
+	b.startTime = time.Now().Truncate(time.Second)
+	groups, err := b.ClientSet.Discovery().ServerGroups()
+	if err != nil {
+		log.Fatalf("Error discovering API groups: %v", err)
+	}
+	preferredVersions := make(map[string]string)
+	for _, group := range groups.Groups {
+		preferredVersions[group.Name] = group.PreferredVersion.Version
+	}
+	if _, ok := preferredVersions[metal3Group]; !ok {
+		log.Fatalf("API group %s not available, Metal3 is not installed in the cluster", metal3Group)
+	}
+	dynamicClient, err := dynamic.NewForConfig(b.RestConfig)
+	if err != nil {
+		log.Fatalf("Error creating dynamic client: %v", err)
+	}
+	measurementWatchers := []MeasurementWatcher{
+		{
+			restClient: b.ClientSet.CoreV1().RESTClient().(*rest.RESTClient),
+			name:       "nodeWatcher",
+			resource:   "nodes",
+			handlers: &cache.ResourceEventHandlerFuncs{
+				AddFunc: b.handleNode,
+				UpdateFunc: func(oldObj, newObj any) {
+					b.handleNode(newObj)
+				},
+			},
+		},
+		{
+			dynamicClient: dynamicClient,
+			gvr:           schema.GroupVersionResource{Group: metal3Group, Version: preferredVersions[metal3Group], Resource: "baremetalhosts"},
+			name:          "bareMetalHostWatcher",
+			resource:      "baremetalhosts",
+			handlers: &cache.ResourceEventHandlerFuncs{
+				AddFunc: b.handleCreateHost,
+				UpdateFunc: func(oldObj, newObj any) {
+					b.handleUpdateHost(newObj)
+				},
+				DeleteFunc: b.handleDeleteHost,
+			},
+		},
+	}
+	if version, ok := preferredVersions[clusterAPIGroup]; ok {
+		measurementWatchers = append(measurementWatchers, MeasurementWatcher{
+			dynamicClient: dynamicClient,
+			gvr:           schema.GroupVersionResource{Group: clusterAPIGroup, Version: version, Resource: "machines"},
+			name:          "machineWatcher",
+			resource:      "machines",
+			handlers: &cache.ResourceEventHandlerFuncs{
+				AddFunc: b.handleCreateMachine,
+				UpdateFunc: func(oldObj, newObj any) {
+					b.handleUpdateMachine(newObj)
+				},
+			},
+		})
+	} else {
+		log.Debugf("API group %s not available, Machines won't be measured", clusterAPIGroup)
+	}
+	b.startMeasurement(measurementWatchers)
+	return nil
+}
+
+// Collect is not supported by this measurement
+func (b *bareMetalLatency) Collect(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	return nil
+}
+
+// Stop stops the measurement and summarizes the provisioning outcome of the hosts and Machines
+func (b *bareMetalLatency) Stop() error {
+	err := b.StopMeasurement(b.normalizeMetrics, b.getLatency)
+	summary := bareMetalSummary{
+		Timestamp:  time.Now().UTC(),
+		MetricName: bareMetalSummaryMeasurement,
+		UUID:       b.Uuid,
+		JobName:    b.JobConfig.Name,
+		ErrorTypes: make(map[string]int),
+		Metadata:   b.Metadata,
+	}
+	b.metrics.Range(func(key, value any) bool {
+		switch m := value.(type) {
+		case bareMetalHostMetric:
+			if m.Timestamp.IsZero() {
+				return true
+			}
+			summary.Hosts++
+			if !m.provisioned.IsZero() {
+				summary.Provisioned++
+			}
+			if m.Errors > 0 {
+				summary.Failed++
+				summary.ErrorTypes[m.ErrorType]++
+			}
+		case machineMetric:
+			summary.Machines++
+			if m.Failed {
+				summary.MachinesFailed++
+			}
+		}
+		return true
+	})
+	b.mu.Lock()
+	summary.PeakConcurrency = b.peakConcurrency
+	b.mu.Unlock()
+	log.Infof("%s: %d BareMetalHosts, %d provisioned, %d failed, peak concurrency: %d", b.JobConfig.Name, summary.Hosts, summary.Provisioned, summary.Failed, summary.PeakConcurrency)
+	if summary.Machines > 0 {
+		log.Infof("%s: %d Machines, %d failed", b.JobConfig.Name, summary.Machines, summary.MachinesFailed)
+	}
+	b.summary = []any{summary}
+	return err
+}
+
+// Index indexes the host and Machine latencies, their quantiles and the summary documents
+func (b *bareMetalLatency) Index(jobName string, indexerList map[string]indexers.Indexer) error {
+	var hosts, machines []any
+	for _, normLatency := range b.normLatencies {
+		if _, ok := normLatency.(machineMetric); ok {
+			machines = append(machines, normLatency)
+		} else {
+			hosts = append(hosts, normLatency)
+		}
+	}
+	metricMap := map[string][]any{
+		b.MeasurementName:           hosts,
+		b.QuantilesMeasurementName:  b.latencyQuantiles,
+		machineLatencyMeasurement:   machines,
+		bareMetalSummaryMeasurement: b.summary,
+	}
+	return b.indexLatencyMeasurement(jobName, metricMap, indexerList)
+}
+
+// hostNodes returns the Ready nodes by the UID of their host, or its namespace and name, from their provider ID, like
+// metal3://<namespace>/<host>/<machine>, metal3://<host uid> or baremetalhost:///<namespace>/<host>/<host uid>
+func (b *bareMetalLatency) hostNodes() map[string]readyNode {
+	nodes := make(map[string]readyNode)
+	b.nodes.Range(func(key, value any) bool {
+		_, id, ok := strings.Cut(key.(string), "://")
+		if !ok {
+			return true
+		}
+		parts := strings.Split(strings.Trim(id, "/"), "/")
+		if len(parts) >= 2 {
+			nodes[parts[0]+"/"+parts[1]] = value.(readyNode)
+		}
+		nodes[parts[len(parts)-1]] = value.(readyNode)
+		return true
+	})
+	return nodes
+}
+
+// normalizeMetrics calculates the latencies of the hosts that changed state and of the provisioned Machines
+func (b *bareMetalLatency) normalizeMetrics() float64 {
+	total := 0
+	errored := 0
+	nodes := b.hostNodes()
+	latency := func(t, since time.Time, errorFlag *int) int {
+		if t.IsZero() {
+			return 0
+		}
+		l := int(t.Sub(since).Milliseconds())
+		if l < 0 {
+			*errorFlag = 1
+			return 0
+		}
+		return l
+	}
+	b.metrics.Range(func(key, value any) bool {
+		errorFlag := 0
+		switch m := value.(type) {
+		case bareMetalHostMetric:
+			// Hosts whose state didn't change during the job
+			if m.Timestamp.IsZero() {
+				log.Tracef("BareMetalHost %v latency ignored as its state didn't change", m.Name)
+				return true
+			}
+			node, ok := nodes[key.(string)]
+			if !ok {
+				node, ok = nodes[m.Namespace+"/"+m.Name]
+			}
+			// Nodes Ready before the host was provisioned ran on a previous provisioning of the host
+			if ok && !m.provisioned.IsZero() && !node.ready.Before(m.provisioned.Truncate(time.Second)) {
+				m.NodeName = node.name
+				m.nodeReady = node.ready
+			}
+			m.RegisteringLatency = latency(m.registering, m.Timestamp, &errorFlag)
+			m.InspectingLatency = latency(m.inspecting, m.Timestamp, &errorFlag)
+			m.AvailableLatency = latency(m.available, m.Timestamp, &errorFlag)
+			m.ProvisioningLatency = latency(m.provisioning, m.Timestamp, &errorFlag)
+			m.ProvisionedLatency = latency(m.provisioned, m.Timestamp, &errorFlag)
+			m.NodeReadyLatency = latency(m.nodeReady, m.Timestamp, &errorFlag)
+			if errorFlag == 1 {
+				log.Tracef("Latencies of BareMetalHost %v falling under negative case. So explicitly setting them to 0", m.Name)
+			}
+			b.normLatencies = append(b.normLatencies, m)
+		case machineMetric:
+			if m.provisioned.IsZero() {
+				log.Tracef("Machine %v latency ignored as it was not provisioned", m.Name)
+				return true
+			}
+			m.ProvisionedLatency = latency(m.provisioned, m.Timestamp, &errorFlag)
+			m.RunningLatency = latency(m.running, m.Timestamp, &errorFlag)
+			b.normLatencies = append(b.normLatencies, m)
+		}
+		total++
+		errored += errorFlag
+		return true
+	})
+	if total == 0 {
+		return 0.0
+	}
+	return float64(errored) / float64(total) * 100.0
+}
+
+func (b *bareMetalLatency) getLatency(normLatency any) map[string]float64 {
+	latencies := map[string]float64{}
+	switch m := normLatency.(type) {
+	case bareMetalHostMetric:
+		// States not observed aren't part of the quantiles
+		if !m.registering.IsZero() {
+			latencies[bmRegistering] = float64(m.RegisteringLatency)
+		}
+		if !m.inspecting.IsZero() {
+			latencies[bmInspecting] = float64(m.InspectingLatency)
+		}
+		if !m.available.IsZero() {
+			latencies[bmAvailable] = float64(m.AvailableLatency)
+		}
+		if !m.provisioning.IsZero() {
+			latencies[bmProvisioning] = float64(m.ProvisioningLatency)
+		}
+		if !m.provisioned.IsZero() {
+			latencies[bmProvisioned] = float64(m.ProvisionedLatency)
+		}
+		if !m.nodeReady.IsZero() {
+			latencies[bmNodeReady] = float64(m.NodeReadyLatency)
+		}
+	case machineMetric:
+		latencies[machineProvisioned] = float64(m.ProvisionedLatency)
+		if !m.running.IsZero() {
+			latencies[machineRunning] = float64(m.RunningLatency)
+		}
+	}
+	return latencies
+}
@@ -15,7 +15,11 @@
 package measurements
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"math"
+	"strings"
 	"sync"
 
 	"github.com/cloud-bulldozer/go-commons/v2/indexers"
@@ -27,6 +31,9 @@ import (
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
@@ -45,38 +52,74 @@ type BaseMeasurement struct {
 	metrics                  sync.Map
 	MeasurementName          string
 	latencyQuantiles         []any
+	latencyHistograms        []any
 	QuantilesMeasurementName string
 	normLatencies            []any
+	// latencyFunc returns the latencies of a normalized metric by condition, set once the measurement stops
+	latencyFunc  func(any) map[string]float64
+	GlobalConfig config.GlobalConfig
+	// Data-quality checks values
+	quality map[string]float64
+	// Streaming of the documents during the job, when enabled
+	stream         *latencyStream
+	streamJobName  string
+	streamIndexers map[string]indexers.Indexer
 }
 
 type MeasurementWatcher struct {
-	restClient    *rest.RESTClient
+	restClient *rest.RESTClient
+	// dynamicClient watches the gvr resource as unstructured, used when the resource API version isn't known beforehand
+	dynamicClient dynamic.Interface
+	gvr           schema.GroupVersionResource
 	name          string
 	resource      string
 	labelSelector string
+	fieldSelector string
 	handlers      *cache.ResourceEventHandlerFuncs
 }
 
 func (bm *BaseMeasurement) startMeasurement(measurementWatchers []MeasurementWatcher) {
 	// Reset latency slices, required in multi-job benchmarks
-	bm.latencyQuantiles, bm.normLatencies = nil, nil
+	bm.latencyQuantiles, bm.latencyHistograms, bm.normLatencies, bm.quality = nil, nil, nil, nil
 	bm.metrics = sync.Map{}
+	if bm.stream != nil {
+		go bm.runStream()
+	}
 
 	bm.watchers = make([]*watchers.Watcher, len(measurementWatchers))
 	for i, measurementWatcher := range measurementWatchers {
 		log.Infof("Creating %v latency watcher for %s", measurementWatcher.resource, bm.JobConfig.Name)
-		bm.watchers[i] = watchers.NewWatcher(
-			measurementWatcher.restClient,
-			measurementWatcher.name,
-			measurementWatcher.resource,
-			corev1.NamespaceAll,
-			func(options *metav1.ListOptions) {
-				if measurementWatcher.labelSelector != "" {
-					options.LabelSelector = measurementWatcher.labelSelector
-				}
-			},
-			nil,
-		)
+		optionsModifier := func(options *metav1.ListOptions) {
+			if measurementWatcher.labelSelector != "" {
+				options.LabelSelector = measurementWatcher.labelSelector
+			}
+			if measurementWatcher.fieldSelector != "" {
+				options.FieldSelector = measurementWatcher.fieldSelector
+			}
+			// Reflectors always request bookmarks
+			if bm.JobConfig.ListOptions.DisableWatchBookmarks {
+				options.AllowWatchBookmarks = false
+			}
+		}
+		if measurementWatcher.dynamicClient != nil {
+			bm.watchers[i] = watchers.NewDynamicWatcher(
+				measurementWatcher.dynamicClient,
+				measurementWatcher.name,
+				measurementWatcher.gvr,
+				corev1.NamespaceAll,
+				optionsModifier,
+				nil,
+			)
+		} else {
+			bm.watchers[i] = watchers.NewWatcher(
+				measurementWatcher.restClient,
+				measurementWatcher.name,
+				measurementWatcher.resource,
+				corev1.NamespaceAll,
+				optionsModifier,
+				nil,
+			)
+		}
 		if measurementWatcher.handlers != nil {
 			bm.watchers[i].Informer.AddEventHandler(measurementWatcher.handlers)
 		}
@@ -95,7 +138,27 @@ func (bm *BaseMeasurement) stopWatchers() {
 func (bm *BaseMeasurement) StopMeasurement(normalizeMetrics func() float64, getLatency func(any) map[string]float64) error {
 	var err error
 	defer bm.stopWatchers()
+	bm.latencyFunc = getLatency
+	bm.stopStream()
 	errorRate := normalizeMetrics()
+	var tracked int
+	bm.metrics.Range(func(any, any) bool {
+		tracked++
+		return true
+	})
+	normalized := len(bm.normLatencies)
+	if bm.stream != nil {
+		errorRate = bm.stream.errorRate(errorRate, normalized)
+		tracked += bm.stream.streamed
+		normalized += bm.stream.streamed
+	}
+	if tracked > 0 {
+		bm.recordQuality(qualityMissingEvents, float64(tracked-normalized)/float64(tracked)*100)
+		bm.recordQuality(qualityClockSkew, errorRate)
+		if gaps := bm.watchGaps(); gaps.Relists > 0 {
+			bm.recordQuality(qualityMissedObjects, math.Min(float64(gaps.MissedObjects)/float64(tracked)*100, 100))
+		}
+	}
 	if errorRate > 10.00 {
 		log.Error("Latency errors beyond 10%. Hence invalidating the results")
 		return fmt.Errorf("something is wrong with system under test. %v latencies error rate was: %.2f", bm.MeasurementName, errorRate)
@@ -106,6 +169,10 @@ func (bm *BaseMeasurement) StopMeasurement(normalizeMetrics func() float64, getL
 	}
 	for _, q := range bm.latencyQuantiles {
 		pq := q.(metrics.LatencyQuantiles)
+		if pq.Group != "" {
+			log.Infof("%s: %v %s=%s 99th: %v max: %v avg: %v", bm.JobConfig.Name, pq.QuantileName, bm.Config.GroupBy, pq.Group, pq.P99, pq.Max, pq.Avg)
+			continue
+		}
 		log.Infof("%s: %v 99th: %v max: %v avg: %v", bm.JobConfig.Name, pq.QuantileName, pq.P99, pq.Max, pq.Avg)
 	}
 	if errorRate > 0 {
@@ -118,66 +185,148 @@ func (bm *BaseMeasurement) GetMetrics() *sync.Map {
 	return &bm.metrics
 }
 
-func (bm *BaseMeasurement) Index(jobName string, indexerList map[string]indexers.Indexer) {
+func (bm *BaseMeasurement) Index(jobName string, indexerList map[string]indexers.Indexer) error {
 	metricMap := map[string][]any{
 		bm.MeasurementName:          bm.normLatencies,
 		bm.QuantilesMeasurementName: bm.latencyQuantiles,
 	}
-	bm.indexLatencyMeasurement(jobName, metricMap, indexerList)
+	return utilerrors.NewAggregate(append(bm.streamErrors(), bm.indexLatencyMeasurement(jobName, metricMap, indexerList)))
 }
 
-// Keep this method to allow reuse when overriding Index
-func (bm *BaseMeasurement) indexLatencyMeasurement(jobName string, metricMap map[string][]any, indexerList map[string]indexers.Indexer) {
-	indexDocuments := func(indexer indexers.Indexer, metricName string, data []any) {
+// Keep this method to allow reuse when overriding Index, returns the errors of all the indexers
+func (bm *BaseMeasurement) indexLatencyMeasurement(jobName string, metricMap map[string][]any, indexerList map[string]indexers.Indexer) error {
+	var errs []error
+	indexDocuments := func(indexer indexers.Indexer, metricName, documentsName string, data []any) {
 		log.Infof("Indexing metric %s", metricName)
 		indexingOpts := indexers.IndexingOpts{
-			MetricName: fmt.Sprintf("%s-%s", metricName, jobName),
+			MetricName: documentsName,
 		}
 		log.Debugf("Indexing [%d] documents: %s", len(data), metricName)
 		resp, err := indexer.Index(data, indexingOpts)
 		if err != nil {
-			log.Error(err.Error())
+			errs = append(errs, fmt.Errorf("error indexing %s: %v", metricName, err))
 		} else {
 			log.Info(resp)
 		}
 	}
+	// Histograms are indexed along with the quantiles
+	if _, ok := metricMap[bm.QuantilesMeasurementName]; ok && len(bm.latencyHistograms) > 0 {
+		metricMap = maps.Clone(metricMap)
+		metricMap[bm.histogramMeasurementName()] = bm.latencyHistograms
+	}
 	for metricName, data := range metricMap {
+		documentsName := fmt.Sprintf("%s-%s", metricName, jobName)
+		// Streamed documents are indexed in parts, the last one holding those normalized when the measurement stops
+		if bm.stream != nil && metricName == bm.MeasurementName {
+			if len(data) == 0 {
+				continue
+			}
+			documentsName = bm.stream.partName(metricName)
+		}
 		// Use the configured TimeseriesIndexer or QuantilesIndexer when specified or else use all indexers
-		if bm.Config.TimeseriesIndexer != "" && (metricName == podLatencyMeasurement || metricName == svcLatencyMeasurement || metricName == nodeLatencyMeasurement || metricName == pvcLatencyMeasurement) {
+		if bm.Config.TimeseriesIndexer != "" && (metricName == podLatencyMeasurement || metricName == podTimelineMeasurement || metricName == svcLatencyMeasurement || metricName == dnsLatencyMeasurement || metricName == nodeLatencyMeasurement || metricName == pvcLatencyMeasurement || metricName == draLatencyMeasurement || metricName == criStatsMeasurement || metricName == kubeletNodeStatsMeasurement || metricName == kubeletPodStatsMeasurement || metricName == criStartLatencyMeasurement || metricName == schedulerThroughputMeasurement || metricName == bareMetalLatencyMeasurement || metricName == machineLatencyMeasurement) {
 			indexer := indexerList[bm.Config.TimeseriesIndexer]
-			indexDocuments(indexer, metricName, data)
-		} else if bm.Config.QuantilesIndexer != "" && (metricName == podLatencyQuantilesMeasurement || metricName == svcLatencyQuantilesMeasurement || metricName == nodeLatencyQuantilesMeasurement || metricName == pvcLatencyQuantilesMeasurement) {
+			indexDocuments(indexer, metricName, documentsName, data)
+		} else if bm.Config.QuantilesIndexer != "" && (metricName == podLatencyQuantilesMeasurement || metricName == svcLatencyQuantilesMeasurement || metricName == dnsLatencyQuantilesMeasurement || metricName == nodeLatencyQuantilesMeasurement || metricName == pvcLatencyQuantilesMeasurement || metricName == draLatencyQuantilesMeasurement || metricName == criStartLatencyQuantilesMeasurement || metricName == schedulerThroughputQuantilesMeasurement || metricName == bareMetalLatencyQuantilesMeasurement || (len(bm.latencyHistograms) > 0 && metricName == bm.histogramMeasurementName())) {
 			indexer := indexerList[bm.Config.QuantilesIndexer]
-			indexDocuments(indexer, metricName, data)
+			indexDocuments(indexer, metricName, documentsName, data)
 		} else {
 			for _, indexer := range indexerList {
-				indexDocuments(indexer, metricName, data)
+				indexDocuments(indexer, metricName, documentsName, data)
 			}
 		}
 	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// nodeLabelGroupPrefix prefix of the groupBy values grouping the metrics by a label of the node the pod ran on, like
+// node:kubernetes.io/arch, instead of a label of the pod
+const nodeLabelGroupPrefix = "node:"
+
+// nodeLabelGroups returns the value of the groupBy node label by node name, nil when grouping by a pod label
+func (bm *BaseMeasurement) nodeLabelGroups() map[string]string {
+	label, ok := strings.CutPrefix(bm.Config.GroupBy, nodeLabelGroupPrefix)
+	if !ok {
+		return nil
+	}
+	groups := make(map[string]string)
+	nodes, err := bm.ClientSet.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		log.Errorf("%s: error listing nodes to group by %s: %v", bm.JobConfig.Name, label, err)
+		return groups
+	}
+	for _, node := range nodes.Items {
+		groups[node.Name] = node.Labels[label]
+	}
+	return groups
+}
+
+// groupedMetric is implemented by the metrics supporting per-group quantiles
+type groupedMetric interface {
+	group() string
+}
+
+type quantileKey struct {
+	group     string
+	condition string
+}
+
+// addLatencies adds the latencies of each condition of the given metric, and of its group when grouping
+func (bm *BaseMeasurement) addLatencies(quantileMap map[quantileKey][]float64, normLatency any, getLatency func(any) map[string]float64) {
+	var group string
+	if gm, ok := normLatency.(groupedMetric); ok && bm.Config.GroupBy != "" {
+		group = gm.group()
+	}
+	for condition, latency := range getLatency(normLatency) {
+		quantileMap[quantileKey{condition: condition}] = append(quantileMap[quantileKey{condition: condition}], latency)
+		if group != "" {
+			quantileMap[quantileKey{group: group, condition: condition}] = append(quantileMap[quantileKey{group: group, condition: condition}], latency)
+		}
+	}
+}
 
+// workerLatencies returns the given latencies when running as a worker process, nil otherwise
+func (bm *BaseMeasurement) workerLatencies(latencies []float64) []float64 {
+	if bm.GlobalConfig.Workers > 1 {
+		return latencies
+	}
+	return nil
 }
 
 // Common function to calculate quantiles for both node and pod latencies
 // Receives a function to get the latencies for each condition
 func (bm *BaseMeasurement) calculateQuantiles(getLatency func(any) map[string]float64) {
-	quantileMap := map[string][]float64{}
+	quantileMap := map[quantileKey][]float64{}
+	// The latencies of the streamed documents are kept for the quantiles
+	if bm.stream != nil {
+		quantileMap = bm.stream.latencies
+	}
 	for _, normLatency := range bm.normLatencies {
-		for condition, latency := range getLatency(normLatency) {
-			quantileMap[condition] = append(quantileMap[condition], latency)
-		}
+		bm.addLatencies(quantileMap, normLatency, getLatency)
 	}
-	calcSummary := func(name string, inputLatencies []float64) metrics.LatencyQuantiles {
+	calcSummary := func(name, group string, inputLatencies []float64) metrics.LatencyQuantiles {
 		latencySummary := metrics.NewLatencySummary(inputLatencies, name)
 		latencySummary.UUID = bm.Uuid
 		latencySummary.Metadata = bm.Metadata
 		latencySummary.MetricName = bm.QuantilesMeasurementName
 		latencySummary.JobName = bm.JobConfig.Name
+		latencySummary.Group = group
+		latencySummary.Latencies = bm.workerLatencies(inputLatencies)
 		return latencySummary
 	}
 
 	bm.latencyQuantiles = make([]any, 0, len(quantileMap))
-	for condition, latencies := range quantileMap {
-		bm.latencyQuantiles = append(bm.latencyQuantiles, calcSummary(condition, latencies))
+	bm.latencyHistograms = nil
+	for key, latencies := range quantileMap {
+		bm.latencyQuantiles = append(bm.latencyQuantiles, calcSummary(key.condition, key.group, latencies))
+		if bm.Config.Histogram != nil {
+			histogram := metrics.NewLatencyHistogram(latencies, key.condition, *bm.Config.Histogram)
+			histogram.UUID = bm.Uuid
+			histogram.Metadata = bm.Metadata
+			histogram.MetricName = bm.histogramMeasurementName()
+			histogram.JobName = bm.JobConfig.Name
+			histogram.Group = key.group
+			bm.latencyHistograms = append(bm.latencyHistograms, histogram)
+		}
 	}
 }
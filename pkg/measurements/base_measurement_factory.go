@@ -37,18 +37,20 @@ var (
 )
 
 type BaseMeasurementFactory struct {
-	Config   types.Measurement
-	Uuid     string
-	Runid    string
-	Metadata map[string]any
+	Config       types.Measurement
+	Uuid         string
+	Runid        string
+	Metadata     map[string]any
+	GlobalConfig config.GlobalConfig
 }
 
 func NewBaseMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any) BaseMeasurementFactory {
 	return BaseMeasurementFactory{
-		Config:   measurement,
-		Uuid:     configSpec.GlobalConfig.UUID,
-		Runid:    configSpec.GlobalConfig.RUNID,
-		Metadata: metadata,
+		Config:       measurement,
+		Uuid:         configSpec.GlobalConfig.UUID,
+		Runid:        configSpec.GlobalConfig.RUNID,
+		Metadata:     metadata,
+		GlobalConfig: configSpec.GlobalConfig,
 	}
 }
 
@@ -71,6 +73,7 @@ func (bmf BaseMeasurementFactory) NewBaseLatency(
 		MeasurementName:          measurementName,
 		QuantilesMeasurementName: quantilesMeasurementName,
 		EmbedCfg:                 embedCfg,
+		GlobalConfig:             bmf.GlobalConfig,
 	}
 }
 
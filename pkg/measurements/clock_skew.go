@@ -0,0 +1,181 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	nodeLeaseNamespace = "kube-node-lease"
+	// Requests sampling the API server clock, spread over more than a second as the Date header has a precision of one second
+	apiServerClockSamples        = 10
+	apiServerClockSampleInterval = 110 * time.Millisecond
+)
+
+// clockSkewEstimator estimates the clock offset of each node from the renewals of its node lease. The renewTime of
+// the lease is set by the kubelet clock, comparing it with the time the update is received gives the offset plus the
+// propagation delay, hence the highest sample is the best estimation of the offset. Samples are taken against the
+// kube-burner clock, the offset of the API server clock is subtracted so that node offsets are relative to the API server,
+// which sets the creation timestamps
+type clockSkewEstimator struct {
+	mutex   sync.Mutex
+	offsets map[string]time.Duration
+	// apiServerOffset clock offset of the API server, positive when its clock is ahead of the kube-burner one
+	apiServerOffset time.Duration
+}
+
+func newClockSkewEstimator() *clockSkewEstimator {
+	return &clockSkewEstimator{
+		offsets: make(map[string]time.Duration),
+	}
+}
+
+func (cs *clockSkewEstimator) watcher(clientSet kubernetes.Interface) MeasurementWatcher {
+	return MeasurementWatcher{
+		restClient:    clientSet.CoordinationV1().RESTClient().(*rest.RESTClient),
+		name:          "nodeLeaseWatcher",
+		resource:      "leases",
+		fieldSelector: "metadata.namespace=" + nodeLeaseNamespace,
+		handlers: &cache.ResourceEventHandlerFuncs{
+			// Leases from the initial list may have been renewed long ago, only renewals are sampled
+			UpdateFunc: func(oldObj, newObj any) {
+				cs.handleLease(newObj)
+			},
+		},
+	}
+}
+
+func (cs *clockSkewEstimator) handleLease(obj any) {
+	lease, ok := obj.(*coordinationv1.Lease)
+	if !ok || lease.Spec.RenewTime == nil {
+		return
+	}
+	sample := lease.Spec.RenewTime.Sub(time.Now())
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	if offset, exists := cs.offsets[lease.Name]; !exists || sample > offset {
+		cs.offsets[lease.Name] = sample
+	}
+}
+
+// dateRecorder records the Date header of the responses along with the time the request was sent and the response received
+type dateRecorder struct {
+	http.RoundTripper
+	mutex    sync.Mutex
+	date     time.Time
+	sent     time.Time
+	received time.Time
+	failed   bool
+}
+
+func (dr *dateRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	sent := time.Now()
+	resp, err := dr.RoundTripper.RoundTrip(req)
+	received := time.Now()
+	dr.mutex.Lock()
+	defer dr.mutex.Unlock()
+	dr.sent, dr.received, dr.failed = sent, received, true
+	if err == nil {
+		date, parseErr := http.ParseTime(resp.Header.Get("Date"))
+		dr.date, dr.failed = date, parseErr != nil
+	}
+	return resp, err
+}
+
+// measureAPIServerOffset estimates the clock offset of the API server from the Date header of its responses. The server time
+// of each response is within [Date, Date+1s) at some point between the request and the response, bounding the offset.
+// Intersecting the bounds of samples spread over a second narrows the offset down to the round-trip time
+func (cs *clockSkewEstimator) measureAPIServerOffset(restConfig *rest.Config) {
+	recorder := &dateRecorder{}
+	config := rest.CopyConfig(restConfig)
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		recorder.RoundTripper = rt
+		return recorder
+	})
+	clientSet, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		log.Warnf("Error creating client to sample the API server clock, assuming it's synchronized with kube-burner: %v", err)
+		return
+	}
+	var lower, upper time.Duration
+	var samples int
+	for i := range apiServerClockSamples {
+		if i > 0 {
+			time.Sleep(apiServerClockSampleInterval)
+		}
+		clientSet.Discovery().ServerVersion()
+		recorder.mutex.Lock()
+		date, sent, received, failed := recorder.date, recorder.sent, recorder.received, recorder.failed
+		recorder.mutex.Unlock()
+		if failed {
+			continue
+		}
+		sampleLower, sampleUpper := date.Sub(received), date.Add(time.Second).Sub(sent)
+		// Bounds not overlapping come from a delayed sample, start over from the latest one
+		if samples == 0 || sampleLower > upper || sampleUpper < lower {
+			lower, upper = sampleLower, sampleUpper
+		} else {
+			lower, upper = max(lower, sampleLower), min(upper, sampleUpper)
+		}
+		samples++
+	}
+	if samples == 0 {
+		log.Warn("Couldn't sample the API server clock, assuming it's synchronized with kube-burner")
+		return
+	}
+	offset := (lower + upper) / 2
+	log.Debugf("API server clock offset: %v ± %v", offset.Truncate(time.Millisecond), ((upper - lower) / 2).Truncate(time.Millisecond))
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	cs.apiServerOffset = offset
+}
+
+// offset returns the estimated clock offset of the node relative to the API server, positive when the node clock is ahead
+func (cs *clockSkewEstimator) offset(node string) time.Duration {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	offset, exists := cs.offsets[node]
+	if !exists {
+		return 0
+	}
+	return offset - cs.apiServerOffset
+}
+
+// skewedNodes returns the percentage of nodes whose clock offset exceeds the given maximum
+func (cs *clockSkewEstimator) skewedNodes(maxClockSkew time.Duration) float64 {
+	cs.mutex.Lock()
+	defer cs.mutex.Unlock()
+	if len(cs.offsets) == 0 {
+		return 0
+	}
+	var skewed int
+	for node, offset := range cs.offsets {
+		offset -= cs.apiServerOffset
+		if offset.Abs() > maxClockSkew {
+			log.Warnf("Clock of node %s is %v off, beyond the maximum clock skew: %v", node, offset.Truncate(time.Millisecond), maxClockSkew)
+			skewed++
+		}
+	}
+	return float64(skewed) / float64(len(cs.offsets)) * 100
+}
@@ -0,0 +1,668 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/metrics"
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	kutil "github.com/kube-burner/kube-burner/pkg/util"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	criStatsMeasurement                 = "criStatsMeasurement"
+	criStartLatencyMeasurement          = "criStartLatencyMeasurement"
+	criStartLatencyQuantilesMeasurement = "criStartLatencyQuantilesMeasurement"
+	criAgentNs                          = "kube-burner-cri-stats"
+	criAgentName                        = "cri-agent"
+	defaultCRIStatsInterval             = 15 * time.Second
+	defaultCRIAgentImage                = "quay.io/cloud-bulldozer/fedora-nc:latest"
+	criAgentTimeout                     = 5 * time.Minute
+	sandboxCreatedCondition             = "SandboxCreated"
+	containerCreatedCondition           = "ContainerCreated"
+	containerStartedCondition           = "ContainerStarted"
+	// Lists the cpu.stat files of the pod cgroups, one line per file: <path> <key> <value> ...
+	cgroupCPUStatScript = `find /sys/fs/cgroup -maxdepth 6 -name cpu.stat -path '*pod*' | while read f; do echo "$f $(tr '\n' ' ' < $f)"; done`
+)
+
+// Pod cgroup directories, e.g. kubepods-burstable-pod<uid>.slice or pod<uid>
+var podCgroupRegex = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})`)
+
+var supportedCRIStatsConditions = map[string]struct{}{
+	sandboxCreatedCondition:   {},
+	containerCreatedCondition: {},
+	containerStartedCondition: {},
+}
+
+// criStatsMetric holds the runtime stats of a pod, sampled periodically
+type criStatsMetric struct {
+	Timestamp  time.Time `json:"timestamp"`
+	MetricName string    `json:"metricName"`
+	UUID       string    `json:"uuid"`
+	JobName    string    `json:"jobName,omitempty"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"podName"`
+	NodeName   string    `json:"nodeName"`
+	Containers int       `json:"containers"`
+	// CPUUsage cores used by the pod containers since the previous sample
+	CPUUsage         float64 `json:"cpuUsage"`
+	MemoryWorkingSet uint64  `json:"memoryWorkingSet"`
+	// Throttling of the pod cgroup since the previous sample
+	CPUThrottledPeriods int64   `json:"cpuThrottledPeriods"`
+	CPUThrottledRatio   float64 `json:"cpuThrottledRatio"`
+	CPUThrottledTime    int64   `json:"cpuThrottledTime"`
+	Metadata            any     `json:"metadata,omitempty"`
+}
+
+// criStartMetric holds the start phases of a container as reported by the container runtime
+type criStartMetric struct {
+	Timestamp               time.Time `json:"timestamp"`
+	MetricName              string    `json:"metricName"`
+	UUID                    string    `json:"uuid"`
+	JobName                 string    `json:"jobName,omitempty"`
+	Namespace               string    `json:"namespace"`
+	Name                    string    `json:"podName"`
+	ContainerName           string    `json:"containerName"`
+	NodeName                string    `json:"nodeName"`
+	SandboxCreatedLatency   int       `json:"sandboxCreatedLatency"`
+	ContainerCreatedLatency int       `json:"containerCreatedLatency"`
+	ContainerStartedLatency int       `json:"containerStartedLatency"`
+	Metadata                any       `json:"metadata,omitempty"`
+	sandboxID               string
+}
+
+// criPodCounters cumulative counters of a pod, used to compute the stats between samples
+type criPodCounters struct {
+	timestamp       time.Time
+	cpuUsage        uint64
+	periods         int64
+	throttled       int64
+	throttledMicros int64
+}
+
+// crictl JSON outputs, only the fields used
+type criSandbox struct {
+	ID       string `json:"id"`
+	Metadata struct {
+		Name      string `json:"name"`
+		UID       string `json:"uid"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	CreatedAt json.Number `json:"createdAt"`
+}
+
+type criContainer struct {
+	ID           string `json:"id"`
+	PodSandboxID string `json:"podSandboxId"`
+	Metadata     struct {
+		Name    string `json:"name"`
+		Attempt int    `json:"attempt"`
+	} `json:"metadata"`
+}
+
+type criContainerStatus struct {
+	Status struct {
+		ID        string    `json:"id"`
+		CreatedAt time.Time `json:"createdAt"`
+		StartedAt time.Time `json:"startedAt"`
+	} `json:"status"`
+}
+
+type criContainerStats struct {
+	Attributes struct {
+		Labels map[string]string `json:"labels"`
+	} `json:"attributes"`
+	CPU struct {
+		UsageCoreNanoSeconds struct {
+			Value json.Number `json:"value"`
+		} `json:"usageCoreNanoSeconds"`
+	} `json:"cpu"`
+	Memory struct {
+		WorkingSetBytes struct {
+			Value json.Number `json:"value"`
+		} `json:"workingSetBytes"`
+	} `json:"memory"`
+}
+
+type criStats struct {
+	BaseMeasurement
+	mu       sync.Mutex
+	samples  []any
+	counters map[string]criPodCounters
+	// Containers whose start phases were already recorded
+	started map[string]struct{}
+	stopCh  chan struct{}
+	doneCh  chan struct{}
+}
+
+type criStatsMeasurementFactory struct {
+	BaseMeasurementFactory
+}
+
+func newCRIStatsMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any) (MeasurementFactory, error) {
+	if err := verifyMeasurementConfig(measurement, supportedCRIStatsConditions); err != nil {
+		return nil, err
+	}
+	if measurement.CRIStatsInterval <= 0 {
+		measurement.CRIStatsInterval = defaultCRIStatsInterval
+	}
+	if measurement.CRIAgentImage == "" {
+		measurement.CRIAgentImage = defaultCRIAgentImage
+	}
+	return criStatsMeasurementFactory{
+		BaseMeasurementFactory: NewBaseMeasurementFactory(configSpec, measurement, metadata),
+	}, nil
+}
+
+func (cmf criStatsMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) Measurement {
+	return &criStats{
+		BaseMeasurement: cmf.NewBaseLatency(jobConfig, clientSet, restConfig, criStartLatencyMeasurement, criStartLatencyQuantilesMeasurement, embedCfg),
+	}
+}
+
+// Start deploys the node agents and starts sampling the runtime stats of the job pods
+func (c *criStats) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	// Reset latency slices, required in multi-job benchmarks
+	c.latencyQuantiles, c.normLatencies, c.samples, c.quality = nil, nil, nil, nil
+	c.counters = make(map[string]criPodCounters)
+	c.started = make(map[string]struct{})
+	if err := c.deployAgents(); err != nil {
+		return fmt.Errorf("error deploying CRI agents: %v", err)
+	}
+	c.stopCh = make(chan struct{})
+	c.doneCh = make(chan struct{})
+	go c.run()
+	return nil
+}
+
+func (c *criStats) run() {
+	defer close(c.doneCh)
+	ticker := time.NewTicker(c.Config.CRIStatsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			c.sample()
+		}
+	}
+}
+
+// Collect is not supported by this measurement
+func (c *criStats) Collect(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	return nil
+}
+
+// Stop takes a last sample, removes the node agents and calculates the start phases quantiles
+func (c *criStats) Stop() error {
+	var err error
+	if c.stopCh == nil {
+		return nil
+	}
+	close(c.stopCh)
+	<-c.doneCh
+	c.stopCh = nil
+	c.sample()
+	// 5 minutes should be more than enough to cleanup this namespace
+	ctx, cancel := context.WithTimeout(context.Background(), criAgentTimeout)
+	defer cancel()
+	kutil.CleanupNamespaces(ctx, c.ClientSet, fmt.Sprintf("kubernetes.io/metadata.name=%s", criAgentNs))
+	// The sandbox phase is accounted once per pod
+	sandboxes := make(map[string]struct{})
+	c.calculateQuantiles(func(normLatency any) map[string]float64 {
+		m := normLatency.(criStartMetric)
+		latencies := map[string]float64{
+			containerCreatedCondition: float64(m.ContainerCreatedLatency),
+			containerStartedCondition: float64(m.ContainerStartedLatency),
+		}
+		if _, seen := sandboxes[m.sandboxID]; !seen {
+			sandboxes[m.sandboxID] = struct{}{}
+			latencies[sandboxCreatedCondition] = float64(m.SandboxCreatedLatency)
+		}
+		return latencies
+	})
+	if len(c.Config.LatencyThresholds) > 0 {
+		err = metrics.CheckThreshold(c.Config.LatencyThresholds, c.latencyQuantiles)
+	}
+	for _, q := range c.latencyQuantiles {
+		pq := q.(metrics.LatencyQuantiles)
+		log.Infof("%s: %v 99th: %v max: %v avg: %v", c.JobConfig.Name, pq.QuantileName, pq.P99, pq.Max, pq.Avg)
+	}
+	var maxThrottledRatio float64
+	var maxWorkingSet uint64
+	for _, s := range c.samples {
+		m := s.(criStatsMetric)
+		maxThrottledRatio = math.Max(maxThrottledRatio, m.CPUThrottledRatio)
+		maxWorkingSet = max(maxWorkingSet, m.MemoryWorkingSet)
+	}
+	if len(c.samples) > 0 {
+		log.Infof("%s: CRI stats max pod CPU throttled ratio: %.2f%% max pod memory working set: %d bytes", c.JobConfig.Name, maxThrottledRatio, maxWorkingSet)
+	} else {
+		log.Warnf("No CRI stats collected for job %s", c.JobConfig.Name)
+	}
+	return err
+}
+
+// Index indexes the start phases, their quantiles and the runtime stats samples
+func (c *criStats) Index(jobName string, indexerList map[string]indexers.Indexer) error {
+	metricMap := map[string][]any{
+		c.MeasurementName:          c.normLatencies,
+		c.QuantilesMeasurementName: c.latencyQuantiles,
+		criStatsMeasurement:        c.samples,
+	}
+	return c.indexLatencyMeasurement(jobName, metricMap, indexerList)
+}
+
+// deployAgents creates the privileged DaemonSet running the node agents, with the host filesystem mounted,
+// and waits until all its pods are ready
+func (c *criStats) deployAgents() error {
+	nsLabels := map[string]string{"pod-security.kubernetes.io/enforce": "privileged"}
+	if err := kutil.CreateNamespace(c.ClientSet, criAgentNs, nsLabels, nil); err != nil {
+		return err
+	}
+	labels := map[string]string{"app": criAgentName}
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: criAgentName},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					TerminationGracePeriodSeconds: ptr.To[int64](0),
+					Tolerations:                   []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+					Containers: []corev1.Container{
+						{
+							Name:            criAgentName,
+							Image:           c.Config.CRIAgentImage,
+							Command:         []string{"sleep", "inf"},
+							ImagePullPolicy: corev1.PullAlways,
+							SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(true)},
+							VolumeMounts:    []corev1.VolumeMount{{Name: "host", MountPath: "/host"}},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name:         "host",
+							VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/"}},
+						},
+					},
+				},
+			},
+		},
+	}
+	if _, err := c.ClientSet.AppsV1().DaemonSets(criAgentNs).Create(context.TODO(), ds, metav1.CreateOptions{}); err != nil {
+		if errors.IsAlreadyExists(err) {
+			log.Warn(err)
+		} else {
+			return err
+		}
+	}
+	log.Infof("Waiting for the CRI agents in namespace %s to be ready", criAgentNs)
+	return wait.PollUntilContextTimeout(context.TODO(), time.Second, criAgentTimeout, true, func(ctx context.Context) (bool, error) {
+		ds, err := c.ClientSet.AppsV1().DaemonSets(criAgentNs).Get(ctx, criAgentName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		return ds.Status.DesiredNumberScheduled > 0 && ds.Status.NumberReady == ds.Status.DesiredNumberScheduled, nil
+	})
+}
+
+// sample samples the runtime stats of the job pods from the agents running on their nodes
+func (c *criStats) sample() {
+	pods, err := c.ClientSet.CoreV1().Pods(corev1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("kube-burner-runid=%v", c.Runid),
+		FieldSelector: "status.phase=Running",
+	})
+	if err != nil {
+		log.Errorf("Error listing pods: %v", err)
+		return
+	}
+	podsByNode := make(map[string]map[string]corev1.Pod)
+	for _, pod := range pods.Items {
+		if podsByNode[pod.Spec.NodeName] == nil {
+			podsByNode[pod.Spec.NodeName] = make(map[string]corev1.Pod)
+		}
+		podsByNode[pod.Spec.NodeName][string(pod.UID)] = pod
+	}
+	agents, err := c.ClientSet.CoreV1().Pods(criAgentNs).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("app=%s", criAgentName),
+	})
+	if err != nil {
+		log.Errorf("Error listing CRI agents: %v", err)
+		return
+	}
+	var wg sync.WaitGroup
+	for _, agent := range agents.Items {
+		if len(podsByNode[agent.Spec.NodeName]) == 0 || agent.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		wg.Add(1)
+		go func(agent corev1.Pod) {
+			defer wg.Done()
+			if err := c.sampleNode(agent, podsByNode[agent.Spec.NodeName]); err != nil {
+				log.Warnf("Error sampling CRI stats on node %s: %v", agent.Spec.NodeName, err)
+			}
+		}(agent)
+	}
+	wg.Wait()
+}
+
+// sampleNode samples the stats of the given pods, running crictl on the node through its agent
+func (c *criStats) sampleNode(agent corev1.Pod, pods map[string]corev1.Pod) error {
+	var sandboxList struct {
+		Items []criSandbox `json:"items"`
+	}
+	if err := c.crictl(agent, &sandboxList, "pods", "-o", "json", "--label", "kube-burner-runid="+c.Runid); err != nil {
+		return err
+	}
+	sandboxes := make(map[string]criSandbox)
+	for _, sandbox := range sandboxList.Items {
+		if _, ok := pods[sandbox.Metadata.UID]; ok {
+			sandboxes[sandbox.ID] = sandbox
+		}
+	}
+	if len(sandboxes) == 0 {
+		return nil
+	}
+	if err := c.recordStartPhases(agent, pods, sandboxes); err != nil {
+		return err
+	}
+	var statsList struct {
+		Stats []criContainerStats `json:"stats"`
+	}
+	if err := c.crictl(agent, &statsList, "stats", "-o", "json"); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	samples := make(map[string]*criStatsMetric)
+	current := make(map[string]criPodCounters)
+	for _, stats := range statsList.Stats {
+		uid := stats.Attributes.Labels["io.kubernetes.pod.uid"]
+		pod, ok := pods[uid]
+		if !ok {
+			continue
+		}
+		if samples[uid] == nil {
+			samples[uid] = &criStatsMetric{
+				Timestamp:  now,
+				MetricName: criStatsMeasurement,
+				UUID:       c.Uuid,
+				JobName:    c.JobConfig.Name,
+				Namespace:  pod.Namespace,
+				Name:       pod.Name,
+				NodeName:   pod.Spec.NodeName,
+				Metadata:   c.Metadata,
+			}
+		}
+		cpuUsage, _ := strconv.ParseUint(stats.CPU.UsageCoreNanoSeconds.Value.String(), 10, 64)
+		workingSet, _ := strconv.ParseUint(stats.Memory.WorkingSetBytes.Value.String(), 10, 64)
+		samples[uid].Containers++
+		samples[uid].MemoryWorkingSet += workingSet
+		counters := current[uid]
+		counters.timestamp = now
+		counters.cpuUsage += cpuUsage
+		current[uid] = counters
+	}
+	cpuStats, err := c.cgroupCPUStats(agent)
+	if err != nil {
+		log.Warnf("Error reading pod cgroups on node %s: %v", agent.Spec.NodeName, err)
+	}
+	for uid, counters := range current {
+		if stat, ok := cpuStats[uid]; ok {
+			counters.periods, counters.throttled = stat["nr_periods"], stat["nr_throttled"]
+			if usec, ok := stat["throttled_usec"]; ok {
+				counters.throttledMicros = usec
+			} else {
+				// cgroup v1 reports nanoseconds
+				counters.throttledMicros = stat["throttled_time"] / 1000
+			}
+		}
+		current[uid] = counters
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for uid, counters := range current {
+		previous, ok := c.counters[uid]
+		if !ok {
+			// The first sample accounts the pod usage since the creation of its sandbox
+			previous = criPodCounters{timestamp: pods[uid].CreationTimestamp.UTC()}
+			for _, sandbox := range sandboxes {
+				if sandbox.Metadata.UID == uid {
+					previous.timestamp = nanoTime(sandbox.CreatedAt)
+				}
+			}
+		}
+		s := samples[uid]
+		if elapsed := counters.timestamp.Sub(previous.timestamp).Seconds(); elapsed > 0 && counters.cpuUsage >= previous.cpuUsage {
+			s.CPUUsage = math.Round(float64(counters.cpuUsage-previous.cpuUsage)/1e9/elapsed*1000) / 1000
+		}
+		// Counters are reset when the pod cgroup is recreated
+		if counters.periods >= previous.periods && counters.throttled >= previous.throttled {
+			s.CPUThrottledPeriods = counters.throttled - previous.throttled
+			s.CPUThrottledTime = (counters.throttledMicros - previous.throttledMicros) / 1000
+			if periods := counters.periods - previous.periods; periods > 0 {
+				s.CPUThrottledRatio = math.Round(float64(s.CPUThrottledPeriods)/float64(periods)*10000) / 100
+			}
+		}
+		c.counters[uid] = counters
+		c.samples = append(c.samples, *s)
+	}
+	return nil
+}
+
+// recordStartPhases records the start phases of the first attempt of the containers not seen before
+func (c *criStats) recordStartPhases(agent corev1.Pod, pods map[string]corev1.Pod, sandboxes map[string]criSandbox) error {
+	var containerList struct {
+		Containers []criContainer `json:"containers"`
+	}
+	if err := c.crictl(agent, &containerList, "ps", "-a", "-o", "json"); err != nil {
+		return err
+	}
+	containers := make(map[string]criContainer)
+	var ids []string
+	c.mu.Lock()
+	for _, container := range containerList.Containers {
+		_, started := c.started[container.ID]
+		if _, ok := sandboxes[container.PodSandboxID]; ok && !started && container.Metadata.Attempt == 0 {
+			containers[container.ID] = container
+			ids = append(ids, container.ID)
+		}
+	}
+	c.mu.Unlock()
+	if len(containers) == 0 {
+		return nil
+	}
+	statuses, err := c.inspectContainers(agent, ids)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, status := range statuses {
+		container, ok := containers[status.Status.ID]
+		// Containers not started yet are inspected again in the next sample
+		if !ok || status.Status.StartedAt.IsZero() || status.Status.StartedAt.Unix() <= 0 {
+			continue
+		}
+		sandbox := sandboxes[container.PodSandboxID]
+		pod := pods[sandbox.Metadata.UID]
+		scheduled := pod.CreationTimestamp.Time
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodScheduled && condition.Status == corev1.ConditionTrue {
+				scheduled = condition.LastTransitionTime.Time
+			}
+		}
+		sandboxCreated := nanoTime(sandbox.CreatedAt)
+		c.normLatencies = append(c.normLatencies, criStartMetric{
+			Timestamp:               sandboxCreated,
+			MetricName:              criStartLatencyMeasurement,
+			UUID:                    c.Uuid,
+			JobName:                 c.JobConfig.Name,
+			Namespace:               pod.Namespace,
+			Name:                    pod.Name,
+			ContainerName:           container.Metadata.Name,
+			NodeName:                pod.Spec.NodeName,
+			SandboxCreatedLatency:   int(sandboxCreated.Sub(scheduled).Milliseconds()),
+			ContainerCreatedLatency: int(status.Status.CreatedAt.Sub(sandboxCreated).Milliseconds()),
+			ContainerStartedLatency: int(status.Status.StartedAt.Sub(status.Status.CreatedAt).Milliseconds()),
+			Metadata:                c.Metadata,
+			sandboxID:               sandbox.ID,
+		})
+		c.started[container.ID] = struct{}{}
+	}
+	return nil
+}
+
+// cgroupCPUStats returns the cpu.stat counters of the pod cgroups found on the node, indexed by pod UID
+func (c *criStats) cgroupCPUStats(agent corev1.Pod) (map[string]map[string]int64, error) {
+	output, err := c.execAgent(agent, "chroot", "/host", "sh", "-c", cgroupCPUStatScript)
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[string]map[string]int64)
+	// Container cgroups are nested in the pod ones, the shortest path of each pod is kept
+	paths := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		match := podCgroupRegex.FindStringSubmatch(fields[0])
+		if match == nil {
+			continue
+		}
+		// The systemd cgroup driver replaces the dashes of the UID with underscores
+		pod := strings.ReplaceAll(match[1], "_", "-")
+		if paths[pod] != "" && len(paths[pod]) < len(fields[0]) {
+			continue
+		}
+		paths[pod] = fields[0]
+		stat := make(map[string]int64)
+		for i := 1; i+1 < len(fields); i += 2 {
+			if v, err := strconv.ParseInt(fields[i+1], 10, 64); err == nil {
+				stat[fields[i]] = v
+			}
+		}
+		stats[pod] = stat
+	}
+	return stats, nil
+}
+
+// crictl runs crictl in the host of the agent, decoding its JSON output
+func (c *criStats) crictl(agent corev1.Pod, v any, args ...string) error {
+	output, err := c.execAgent(agent, append([]string{"chroot", "/host", "crictl"}, args...)...)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(output, v); err != nil {
+		return fmt.Errorf("error decoding crictl output: %v", err)
+	}
+	return nil
+}
+
+// inspectContainers returns the status of the given containers. Depending on the crictl version, inspecting
+// several containers outputs either a list or one object per container
+func (c *criStats) inspectContainers(agent corev1.Pod, ids []string) ([]criContainerStatus, error) {
+	output, err := c.execAgent(agent, append([]string{"chroot", "/host", "crictl", "inspect", "-o", "json"}, ids...)...)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []criContainerStatus
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			return statuses, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("error decoding crictl output: %v", err)
+		}
+		var items []criContainerStatus
+		if json.Unmarshal(raw, &items) != nil {
+			var item criContainerStatus
+			if err := json.Unmarshal(raw, &item); err != nil {
+				return nil, fmt.Errorf("error decoding crictl output: %v", err)
+			}
+			items = append(items, item)
+		}
+		statuses = append(statuses, items...)
+	}
+}
+
+// execAgent runs the command in the agent pod, returning its standard output
+func (c *criStats) execAgent(agent corev1.Pod, command ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	req := c.ClientSet.CoreV1().
+		RESTClient().
+		Post().
+		Resource("pods").
+		Name(agent.Name).
+		Namespace(agent.Namespace).
+		SubResource("exec")
+	req.VersionedParams(&corev1.PodExecOptions{
+		Command:   command,
+		Container: criAgentName,
+		Stdout:    true,
+		Stderr:    true,
+	}, scheme.ParameterCodec)
+	exec, err := remotecommand.NewSPDYExecutor(c.RestConfig, "POST", req.URL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to establish SPDYExecutor on %s: %s", agent.Name, err)
+	}
+	err = exec.StreamWithContext(context.TODO(), remotecommand.StreamOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("command failed on %s: %v %s", agent.Name, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// nanoTime converts the nanoseconds since epoch timestamps reported by crictl
+func nanoTime(ns json.Number) time.Time {
+	n, _ := strconv.ParseInt(ns.String(), 10, 64)
+	return time.Unix(0, n).UTC()
+}
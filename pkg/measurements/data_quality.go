@@ -0,0 +1,161 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/util/junit"
+	"github.com/kube-burner/kube-burner/pkg/watchers"
+	log "github.com/sirupsen/logrus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+const (
+	dataQualityMeasurement = "dataQuality"
+	// Percentage of the tracked objects whose final condition was never observed
+	qualityMissingEvents = "missingEvents"
+	// Percentage of the tracked objects with negative latencies, caused by clock skew between the nodes, the API server and kube-burner
+	qualityClockSkew = "clockSkew"
+	// Percentage of the nodes whose clock offset exceeds the configured maxClockSkew
+	qualitySkewedNodes = "skewedNodes"
+	// Percentage of the DNS prober pods without results
+	qualityMissingProbes = "missingProbes"
+	// Percentage of the tracked objects created or deleted while the watch was interrupted
+	qualityMissedObjects = "missedObjects"
+)
+
+// qualityCheck result of a data-quality check, values are percentages
+type qualityCheck struct {
+	Measurement string   `json:"measurement"`
+	Check       string   `json:"check"`
+	Value       float64  `json:"value"`
+	Threshold   *float64 `json:"threshold,omitempty"`
+	Passed      bool     `json:"passed"`
+}
+
+// dataQuality document holding the data-quality checks of all the measurements of a job
+type dataQuality struct {
+	Timestamp  time.Time      `json:"timestamp"`
+	MetricName string         `json:"metricName"`
+	UUID       string         `json:"uuid"`
+	JobName    string         `json:"jobName,omitempty"`
+	Checks     []qualityCheck `json:"checks"`
+	WatchGaps  []watchGaps    `json:"watchGaps,omitempty"`
+	Passed     bool           `json:"passed"`
+	Metadata   any            `json:"metadata,omitempty"`
+}
+
+// watchGaps interruptions of the watches of a measurement
+type watchGaps struct {
+	Measurement string `json:"measurement"`
+	watchers.WatchGaps
+}
+
+// qualityReporter is implemented by the measurements reporting data-quality checks
+type qualityReporter interface {
+	qualityChecks() []qualityCheck
+	watchGaps() watchers.WatchGaps
+}
+
+// recordQuality records the value of a data-quality check
+func (bm *BaseMeasurement) recordQuality(check string, value float64) {
+	if bm.quality == nil {
+		bm.quality = make(map[string]float64)
+	}
+	bm.quality[check] = value
+}
+
+// watchGaps returns the interruptions of the watches of the measurement
+func (bm *BaseMeasurement) watchGaps() watchers.WatchGaps {
+	var gaps watchers.WatchGaps
+	for _, watcher := range bm.watchers {
+		gaps = gaps.Add(watcher.Gaps())
+	}
+	return gaps
+}
+
+// qualityChecks evaluates the recorded data-quality checks against the configured thresholds
+func (bm *BaseMeasurement) qualityChecks() []qualityCheck {
+	var checks []qualityCheck
+	for check, value := range bm.quality {
+		qc := qualityCheck{
+			Measurement: bm.Config.Name,
+			Check:       check,
+			Value:       value,
+			Passed:      true,
+		}
+		if threshold, exists := bm.Config.QualityThresholds[check]; exists {
+			qc.Threshold = &threshold
+			qc.Passed = value <= threshold
+		}
+		checks = append(checks, qc)
+	}
+	for check := range bm.Config.QualityThresholds {
+		if _, exists := bm.quality[check]; !exists {
+			log.Warnf("%s: data-quality check %s not reported by the measurement", bm.Config.Name, check)
+		}
+	}
+	return checks
+}
+
+// evaluateQuality gathers the data-quality checks of the measurements, returns an error when any of them is beyond its threshold
+func (ms *Measurements) evaluateQuality() error {
+	var errs []error
+	ms.quality = nil
+	doc := dataQuality{
+		Timestamp:  time.Now().UTC(),
+		MetricName: dataQualityMeasurement,
+		UUID:       ms.uuid,
+		JobName:    ms.jobName,
+		Passed:     true,
+		Metadata:   ms.metadata,
+	}
+	for name, measurement := range ms.MeasurementsMap {
+		if qr, ok := measurement.(qualityReporter); ok {
+			doc.Checks = append(doc.Checks, qr.qualityChecks()...)
+			if gaps := qr.watchGaps(); gaps != (watchers.WatchGaps{}) {
+				log.Warnf("%s: %s watches were interrupted %d times, %d objects were potentially missed", ms.jobName, name, gaps.Closures+gaps.Expired, gaps.MissedObjects)
+				doc.WatchGaps = append(doc.WatchGaps, watchGaps{Measurement: name, WatchGaps: gaps})
+			}
+		}
+	}
+	if len(doc.Checks) == 0 && len(doc.WatchGaps) == 0 {
+		return nil
+	}
+	slices.SortFunc(doc.WatchGaps, func(a, b watchGaps) int { return cmp.Compare(a.Measurement, b.Measurement) })
+	slices.SortFunc(doc.Checks, func(a, b qualityCheck) int {
+		return cmp.Or(cmp.Compare(a.Measurement, b.Measurement), cmp.Compare(a.Check, b.Check))
+	})
+	for _, qc := range doc.Checks {
+		log.Infof("%s: %s data quality %s: %.2f%%", ms.jobName, qc.Measurement, qc.Check, qc.Value)
+		if qc.Threshold == nil {
+			continue
+		}
+		var err error
+		if !qc.Passed {
+			doc.Passed = false
+			err = fmt.Errorf("%s: data quality %s (%.2f%%) higher than configured threshold: %.2f%%", qc.Measurement, qc.Check, qc.Value, *qc.Threshold)
+			errs = append(errs, err)
+		}
+		testCase := fmt.Sprintf("%s %s %s <= %.2f%%", ms.jobName, qc.Measurement, qc.Check, *qc.Threshold)
+		junit.AddTestCase(junit.SuiteThresholds, testCase, 0, err)
+	}
+	ms.quality = append(ms.quality, doc)
+	return utilerrors.NewAggregate(errs)
+}
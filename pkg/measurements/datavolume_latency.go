@@ -25,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
@@ -178,7 +179,7 @@ func (dv *dvLatency) Stop() error {
 	return dv.StopMeasurement(dv.normalizeMetrics, dv.getLatency)
 }
 
-func (dv *dvLatency) Collect(measurementWg *sync.WaitGroup) {
+func (dv *dvLatency) Collect(measurementWg *sync.WaitGroup) error {
 	defer measurementWg.Done()
 	var dataVolumes []cdiv1beta1.DataVolume
 	labelSelector := labels.SelectorFromSet(dv.JobConfig.NamespaceLabels)
@@ -187,13 +188,15 @@ func (dv *dvLatency) Collect(measurementWg *sync.WaitGroup) {
 	}
 	kubeVirtClient, err := kubecli.GetKubevirtClientFromRESTConfig(dv.RestConfig)
 	if err != nil {
-		log.Fatalf("Failed to get kubevirt client - %v", err)
+		return fmt.Errorf("failed to get kubevirt client: %v", err)
 	}
+	var errs []error
 	namespaces := strings.Split(dv.JobConfig.Namespace, ",")
 	for _, namespace := range namespaces {
 		dvList, err := kubeVirtClient.CdiClient().CdiV1beta1().DataVolumes(namespace).List(context.TODO(), options)
 		if err != nil {
-			log.Errorf("error listing dataVolumes in namespace %s: %v", namespace, err)
+			errs = append(errs, fmt.Errorf("error listing dataVolumes in namespace %s: %v", namespace, err))
+			continue
 		}
 		dataVolumes = append(dataVolumes, dvList.Items...)
 	}
@@ -222,6 +225,7 @@ func (dv *dvLatency) Collect(measurementWg *sync.WaitGroup) {
 			JobName:    dv.JobConfig.Name,
 		})
 	}
+	return utilerrors.NewAggregate(errs)
 }
 
 func (dv *dvLatency) normalizeMetrics() float64 {
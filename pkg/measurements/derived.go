@@ -0,0 +1,211 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/metrics"
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	"github.com/montanaflynn/stats"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// derived measurement evaluating an expression of the latencies of each object of another measurement, like the
+// scheduling share of the pod startup latency, whose quantiles are indexed along with those of the source
+type derived struct {
+	BaseMeasurement
+	expr derivedExpr
+	// sources measurements of the job, the source is stopped before the derived measurements
+	sources   map[string]Measurement
+	documents []any
+}
+
+// derivedQuantiles quantiles of a derived measurement, they aren't latencies hence they aren't truncated to integers
+type derivedQuantiles struct {
+	QuantileName string                      `json:"quantileName"`
+	Group        string                      `json:"group,omitempty"`
+	UUID         string                      `json:"uuid"`
+	P99          float64                     `json:"P99"`
+	P95          float64                     `json:"P95"`
+	P50          float64                     `json:"P50"`
+	Min          float64                     `json:"min"`
+	Max          float64                     `json:"max"`
+	Avg          float64                     `json:"avg"`
+	Samples      int                         `json:"samples"`
+	StdDev       float64                     `json:"stdDev"`
+	P99CI        *metrics.ConfidenceInterval `json:"P99CI,omitempty"`
+	P95CI        *metrics.ConfidenceInterval `json:"P95CI,omitempty"`
+	P50CI        *metrics.ConfidenceInterval `json:"P50CI,omitempty"`
+	Expression   string                      `json:"expression"`
+	Timestamp    time.Time                   `json:"timestamp"`
+	MetricName   string                      `json:"metricName"`
+	JobName      string                      `json:"jobName,omitempty"`
+	Metadata     any                         `json:"metadata,omitempty"`
+}
+
+type derivedMeasurementFactory struct {
+	BaseMeasurementFactory
+	expr derivedExpr
+}
+
+func newDerivedMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any) (MeasurementFactory, error) {
+	source := measurement.Derived.Source
+	idx := slices.IndexFunc(configSpec.GlobalConfig.Measurements, func(m types.Measurement) bool { return m.Name == source })
+	if idx < 0 {
+		return nil, fmt.Errorf("measurement %s: source measurement %s isn't configured", measurement.Name, source)
+	}
+	if sourceMeasurement := configSpec.GlobalConfig.Measurements[idx]; sourceMeasurement.Derived != nil || sourceMeasurement.Plugin != nil {
+		return nil, fmt.Errorf("measurement %s: source measurement %s must be a built-in measurement", measurement.Name, source)
+	} else if sourceMeasurement.Streaming != nil {
+		return nil, fmt.Errorf("measurement %s: source measurement %s streams its documents, they're not available once it stops", measurement.Name, source)
+	}
+	expr, err := parseExpression(measurement.Derived.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("measurement %s: invalid expression %q: %v", measurement.Name, measurement.Derived.Expression, err)
+	}
+	return derivedMeasurementFactory{
+		BaseMeasurementFactory: NewBaseMeasurementFactory(configSpec, measurement, metadata),
+		expr:                   expr,
+	}, nil
+}
+
+func (dmf derivedMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) Measurement {
+	return &derived{
+		BaseMeasurement: dmf.NewBaseLatency(jobConfig, clientSet, restConfig, dmf.Config.Name, "", embedCfg),
+		expr:            dmf.expr,
+	}
+}
+
+// latencySource measurements exposing the latencies of their objects once they're stopped
+type latencySource interface {
+	latencyDocuments() ([]any, func(any) map[string]float64)
+}
+
+func (bm *BaseMeasurement) latencyDocuments() ([]any, func(any) map[string]float64) {
+	return bm.normLatencies, bm.latencyFunc
+}
+
+// Start is not required by this measurement, it's computed when it stops
+func (d *derived) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	d.documents = nil
+	return nil
+}
+
+// Collect is not required by this measurement
+func (d *derived) Collect(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	return nil
+}
+
+// Stop evaluates the expression for each object of the source measurement, objects for which it divides by zero are
+// skipped, and calculates the quantiles of the results, globally and by group when the source groups its objects
+func (d *derived) Stop() error {
+	d.documents = nil
+	source, ok := d.sources[d.Config.Derived.Source].(latencySource)
+	if !ok {
+		return fmt.Errorf("%s: source measurement %s not found", d.Config.Name, d.Config.Derived.Source)
+	}
+	docs, getLatency := source.latencyDocuments()
+	if getLatency == nil {
+		return fmt.Errorf("%s: source measurement %s doesn't report latencies", d.Config.Name, d.Config.Derived.Source)
+	}
+	values := map[string][]float64{}
+	var skipped int
+	for _, doc := range docs {
+		latencies := getLatency(doc)
+		value, err := d.expr(latencies)
+		if errors.Is(err, errDivisionByZero) {
+			skipped++
+			continue
+		} else if err != nil {
+			conditions := slices.Sorted(maps.Keys(latencies))
+			return fmt.Errorf("%s: %v, the conditions of %s are %s", d.Config.Name, err, d.Config.Derived.Source, strings.Join(conditions, ", "))
+		}
+		values[""] = append(values[""], value)
+		if gm, ok := doc.(groupedMetric); ok && gm.group() != "" {
+			values[gm.group()] = append(values[gm.group()], value)
+		}
+	}
+	if skipped > 0 {
+		log.Warnf("%s: %d objects skipped, the expression divides by zero", d.Config.Name, skipped)
+	}
+	for _, group := range slices.Sorted(maps.Keys(values)) {
+		q := d.summary(group, values[group])
+		if group == "" {
+			log.Infof("%s: %s 99th: %v max: %v avg: %v", d.JobConfig.Name, d.Config.Name, q.P99, q.Max, q.Avg)
+		}
+		d.documents = append(d.documents, q)
+	}
+	return nil
+}
+
+func (d *derived) summary(group string, values []float64) derivedQuantiles {
+	round := func(value float64, err error) float64 {
+		return math.Round(value*10000) / 10000
+	}
+	roundInterval := func(interval *metrics.ConfidenceInterval) *metrics.ConfidenceInterval {
+		if interval == nil {
+			return nil
+		}
+		return &metrics.ConfidenceInterval{Lower: round(interval.Lower, nil), Upper: round(interval.Upper, nil)}
+	}
+	intervals := metrics.PercentileIntervals(values)
+	return derivedQuantiles{
+		QuantileName: d.Config.Name,
+		Group:        group,
+		UUID:         d.Uuid,
+		P99:          round(stats.Percentile(values, 99)),
+		P95:          round(stats.Percentile(values, 95)),
+		P50:          round(stats.Percentile(values, 50)),
+		Min:          round(stats.Min(values)),
+		Max:          round(stats.Max(values)),
+		Avg:          round(stats.Mean(values)),
+		Samples:      len(values),
+		StdDev:       round(stats.StandardDeviation(values)),
+		P99CI:        roundInterval(intervals[2]),
+		P95CI:        roundInterval(intervals[1]),
+		P50CI:        roundInterval(intervals[0]),
+		Expression:   d.Config.Derived.Expression,
+		Timestamp:    time.Now().UTC(),
+		MetricName:   d.Config.Name,
+		JobName:      d.JobConfig.Name,
+		Metadata:     d.Metadata,
+	}
+}
+
+// Index sends the quantiles to the quantiles indexer, or to all of them when not set
+func (d *derived) Index(jobName string, indexerList map[string]indexers.Indexer) error {
+	if len(d.documents) == 0 {
+		return nil
+	}
+	if d.Config.QuantilesIndexer != "" {
+		indexerList = map[string]indexers.Indexer{d.Config.QuantilesIndexer: indexerList[d.Config.QuantilesIndexer]}
+	}
+	return d.indexLatencyMeasurement(jobName, map[string][]any{d.Config.Name: d.documents}, indexerList)
+}
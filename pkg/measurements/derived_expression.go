@@ -0,0 +1,197 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"unicode"
+)
+
+// errDivisionByZero the expression can't be evaluated for the object
+var errDivisionByZero = errors.New("division by zero")
+
+// derivedExpr compiled arithmetic expression, evaluated with the latencies of an object by condition
+type derivedExpr func(vars map[string]float64) (float64, error)
+
+// expressionParser recursive descent parser of arithmetic expressions of numbers and variables, supporting the + - * /
+// operators, unary minus and parentheses
+type expressionParser struct {
+	tokens []string
+	pos    int
+}
+
+// parseExpression compiles the given expression
+func parseExpression(expression string) (derivedExpr, error) {
+	tokens, err := tokenizeExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+	p := &expressionParser{tokens: tokens}
+	expr, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+func tokenizeExpression(expression string) ([]string, error) {
+	var tokens []string
+	runes := []rune(expression)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '+' || r == '-' || r == '*' || r == '/' || r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		default:
+			return nil, fmt.Errorf("unexpected character %q", r)
+		}
+	}
+	return tokens, nil
+}
+
+func (p *expressionParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// parseSum sum := product (("+" | "-") product)*
+func (p *expressionParser) parseSum() (derivedExpr, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.next(); op == "+" || op == "-"; op = p.next() {
+		p.pos++
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr(op, left, right)
+	}
+	return left, nil
+}
+
+// parseProduct product := unary (("*" | "/") unary)*
+func (p *expressionParser) parseProduct() (derivedExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.next(); op == "*" || op == "/"; op = p.next() {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryExpr(op, left, right)
+	}
+	return left, nil
+}
+
+// parseUnary unary := "-" unary | number | variable | "(" sum ")"
+func (p *expressionParser) parseUnary() (derivedExpr, error) {
+	token := p.next()
+	p.pos++
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case token == "-":
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(vars map[string]float64) (float64, error) {
+			v, err := operand(vars)
+			return -v, err
+		}, nil
+	case token == "(":
+		expr, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return expr, nil
+	case unicode.IsDigit(rune(token[0])) || token[0] == '.':
+		value, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", token)
+		}
+		return func(map[string]float64) (float64, error) { return value, nil }, nil
+	case unicode.IsLetter(rune(token[0])) || token[0] == '_':
+		return func(vars map[string]float64) (float64, error) {
+			value, ok := vars[token]
+			if !ok {
+				return 0, fmt.Errorf("unknown condition %s", token)
+			}
+			return value, nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unexpected %q", token)
+	}
+}
+
+func binaryExpr(op string, left, right derivedExpr) derivedExpr {
+	return func(vars map[string]float64) (float64, error) {
+		l, err := left(vars)
+		if err != nil {
+			return 0, err
+		}
+		r, err := right(vars)
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case "+":
+			return l + r, nil
+		case "-":
+			return l - r, nil
+		case "*":
+			return l * r, nil
+		default:
+			if r == 0 {
+				return 0, errDivisionByZero
+			}
+			return l / r, nil
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+)
+
+// disruptionIndexer stamps the description of the overlapping disruption windows on the documents of the
+// measurements before indexing them
+type disruptionIndexer struct {
+	indexer      indexers.Indexer
+	globalConfig config.GlobalConfig
+	// jobStart start of the measurements, the documents summarizing the job span from it until they're indexed
+	jobStart time.Time
+}
+
+// stampDisruptions wraps the indexers so that they stamp the disruption windows, returns them as they are when no
+// disruption window is configured
+func (ms *Measurements) stampDisruptions(indexerList map[string]indexers.Indexer) map[string]indexers.Indexer {
+	if len(ms.globalConfig.DisruptionWindows) == 0 {
+		return indexerList
+	}
+	stamping := make(map[string]indexers.Indexer, len(indexerList))
+	for alias, indexer := range indexerList {
+		stamping[alias] = disruptionIndexer{indexer: indexer, globalConfig: ms.globalConfig, jobStart: ms.started}
+	}
+	return stamping
+}
+
+func (d disruptionIndexer) Index(documents []any, opts indexers.IndexingOpts) (string, error) {
+	stamped := make([]any, 0, len(documents))
+	for _, document := range documents {
+		stamped = append(stamped, d.stamp(document, opts.MetricName))
+	}
+	return d.indexer.Index(stamped, opts)
+}
+
+// stamp returns the document with the disruptions overlapping it. Per-object documents span from their timestamp to
+// their endTimestamp, if any, while quantiles, histograms, SLO evaluations and data-quality checks span the whole job.
+// Documents already stamped by their measurement are left untouched
+func (d disruptionIndexer) stamp(document any, documentsName string) any {
+	var fields map[string]any
+	j, err := json.Marshal(document)
+	if err != nil || json.Unmarshal(j, &fields) != nil {
+		return document
+	}
+	if _, ok := fields["disruptions"]; ok {
+		return document
+	}
+	start, end := parseTimestamp(fields["timestamp"]), parseTimestamp(fields["endTimestamp"])
+	if end.IsZero() {
+		end = start
+	}
+	_, summary := fields["quantileName"]
+	if summary || strings.HasPrefix(documentsName, sloEvaluationMeasurement) || strings.HasPrefix(documentsName, dataQualityMeasurement) {
+		start, end = d.jobStart, time.Now().UTC()
+	}
+	if start.IsZero() {
+		return document
+	}
+	disruptions := d.globalConfig.Disruptions(start, end)
+	if len(disruptions) == 0 {
+		return document
+	}
+	fields["disruptions"] = disruptions
+	return fields
+}
+
+// parseTimestamp parses a timestamp of a decoded document, zero when it's missing
+func parseTimestamp(value any) time.Time {
+	s, _ := value.(string)
+	timestamp, _ := time.Parse(time.RFC3339Nano, s)
+	return timestamp
+}
@@ -0,0 +1,293 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/metrics"
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	"github.com/prometheus/common/expfmt"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	dnsLatencyMeasurement          = "dnsLatencyMeasurement"
+	dnsLatencyQuantilesMeasurement = "dnsLatencyQuantilesMeasurement"
+	corednsMeasurement             = "corednsMeasurement"
+	// Label identifying the DNS prober pods
+	dnsProberLabel = "kube-burner.io/dns-prober"
+	// Prefix of the log lines written by the DNS prober pods: dnsprobe <latency ms> <status>
+	dnsProbePrefix       = "dnsprobe"
+	dnsLookupCondition   = "DNSLookup"
+	corednsMetricsPort   = "9153"
+	defaultCorednsNs     = "kube-system"
+	defaultCorednsLabels = "k8s-app=kube-dns"
+)
+
+var supportedDNSConditions = map[string]struct{}{
+	dnsLookupCondition: {},
+}
+
+// dnsMetric holds the DNS query results of a prober pod
+type dnsMetric struct {
+	Timestamp    time.Time `json:"timestamp"`
+	MetricName   string    `json:"metricName"`
+	UUID         string    `json:"uuid"`
+	JobName      string    `json:"jobName,omitempty"`
+	Namespace    string    `json:"namespace"`
+	Name         string    `json:"podName"`
+	NodeName     string    `json:"nodeName"`
+	Queries      int       `json:"queries"`
+	NXDomain     int       `json:"nxdomain"`
+	Errors       int       `json:"errors"`
+	NXDomainRate float64   `json:"nxdomainRate"`
+	ErrorRate    float64   `json:"errorRate"`
+	Metadata     any       `json:"metadata,omitempty"`
+}
+
+// corednsMetric holds the CoreDNS counters increase during the job
+type corednsMetric struct {
+	Timestamp        time.Time `json:"timestamp"`
+	MetricName       string    `json:"metricName"`
+	UUID             string    `json:"uuid"`
+	JobName          string    `json:"jobName,omitempty"`
+	Requests         float64   `json:"requests"`
+	NXDomain         float64   `json:"nxdomain"`
+	CacheHits        float64   `json:"cacheHits"`
+	CacheMisses      float64   `json:"cacheMisses"`
+	CacheHitRatio    float64   `json:"cacheHitRatio"`
+	ForwardRequests  float64   `json:"forwardRequests"`
+	ForwardResponses float64   `json:"forwardResponses"`
+	Metadata         any       `json:"metadata,omitempty"`
+}
+
+// corednsCounters sum of the CoreDNS counters across all its pods
+type corednsCounters map[string]float64
+
+type dnsLatency struct {
+	BaseMeasurement
+	corednsStart corednsCounters
+	coredns      []any
+}
+
+type dnsLatencyMeasurementFactory struct {
+	BaseMeasurementFactory
+}
+
+func newDNSLatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any) (MeasurementFactory, error) {
+	if err := verifyMeasurementConfig(measurement, supportedDNSConditions); err != nil {
+		return nil, err
+	}
+	if measurement.CorednsNamespace == "" {
+		measurement.CorednsNamespace = defaultCorednsNs
+	}
+	if measurement.CorednsLabelSelector == "" {
+		measurement.CorednsLabelSelector = defaultCorednsLabels
+	}
+	return dnsLatencyMeasurementFactory{
+		BaseMeasurementFactory: NewBaseMeasurementFactory(configSpec, measurement, metadata),
+	}, nil
+}
+
+func (dlmf dnsLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) Measurement {
+	return &dnsLatency{
+		BaseMeasurement: dlmf.NewBaseLatency(jobConfig, clientSet, restConfig, dnsLatencyMeasurement, dnsLatencyQuantilesMeasurement, embedCfg),
+	}
+}
+
+// Start takes a snapshot of the CoreDNS counters
+func (d *dnsLatency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	// Reset latency slices, required in multi-job benchmarks
+	d.latencyQuantiles, d.normLatencies, d.coredns, d.quality = nil, nil, nil, nil
+	d.corednsStart = d.scrapeCoredns()
+	return nil
+}
+
+// Collect is not supported by this measurement
+func (d *dnsLatency) Collect(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	return nil
+}
+
+// Stop collects the results of the DNS prober pods and the CoreDNS counters increase
+func (d *dnsLatency) Stop() error {
+	var err error
+	latencies := d.collectProbes()
+	if len(latencies) > 0 {
+		latencySummary := metrics.NewLatencySummary(latencies, dnsLookupCondition)
+		latencySummary.UUID = d.Uuid
+		latencySummary.Timestamp = time.Now().UTC()
+		latencySummary.Metadata = d.Metadata
+		latencySummary.MetricName = dnsLatencyQuantilesMeasurement
+		latencySummary.JobName = d.JobConfig.Name
+		latencySummary.Latencies = d.workerLatencies(latencies)
+		d.latencyQuantiles = append(d.latencyQuantiles, latencySummary)
+		log.Infof("%s: %s 99th: %vms max: %vms avg: %vms", d.JobConfig.Name, dnsLookupCondition, latencySummary.P99, latencySummary.Max, latencySummary.Avg)
+		if len(d.Config.LatencyThresholds) > 0 {
+			err = metrics.CheckThreshold(d.Config.LatencyThresholds, d.latencyQuantiles)
+		}
+	} else {
+		log.Warnf("No DNS probe results found for job %s", d.JobConfig.Name)
+	}
+	if d.corednsStart != nil {
+		end := d.scrapeCoredns()
+		increase := func(name string) float64 {
+			return max(end[name]-d.corednsStart[name], 0)
+		}
+		m := corednsMetric{
+			Timestamp:        time.Now().UTC(),
+			MetricName:       corednsMeasurement,
+			UUID:             d.Uuid,
+			JobName:          d.JobConfig.Name,
+			Requests:         increase("coredns_dns_requests_total"),
+			NXDomain:         increase("coredns_dns_responses_total/NXDOMAIN"),
+			CacheHits:        increase("coredns_cache_hits_total"),
+			CacheMisses:      increase("coredns_cache_misses_total"),
+			ForwardRequests:  increase("coredns_forward_requests_total"),
+			ForwardResponses: increase("coredns_forward_responses_total"),
+			Metadata:         d.Metadata,
+		}
+		if m.CacheHits+m.CacheMisses > 0 {
+			m.CacheHitRatio = m.CacheHits / (m.CacheHits + m.CacheMisses)
+		}
+		log.Infof("%s: CoreDNS requests: %.0f cache hit ratio: %.2f forwarded requests: %.0f", d.JobConfig.Name, m.Requests, m.CacheHitRatio, m.ForwardRequests)
+		d.coredns = append(d.coredns, m)
+	}
+	return err
+}
+
+// Index indexes the prober results, the lookup quantiles and the CoreDNS document
+func (d *dnsLatency) Index(jobName string, indexerList map[string]indexers.Indexer) error {
+	metricMap := map[string][]any{
+		d.MeasurementName:          d.normLatencies,
+		d.QuantilesMeasurementName: d.latencyQuantiles,
+		corednsMeasurement:         d.coredns,
+	}
+	return d.indexLatencyMeasurement(jobName, metricMap, indexerList)
+}
+
+// collectProbes parses the logs of the prober pods, returns the latencies of the successful lookups
+func (d *dnsLatency) collectProbes() []float64 {
+	var latencies []float64
+	var missingProbes int
+	pods, err := d.ClientSet.CoreV1().Pods(corev1.NamespaceAll).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("kube-burner-runid=%v,%s=true", d.Runid, dnsProberLabel),
+	})
+	if err != nil {
+		log.Errorf("Error listing DNS prober pods: %v", err)
+		return latencies
+	}
+	for _, pod := range pods.Items {
+		logs, err := d.ClientSet.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(context.TODO())
+		if err != nil {
+			log.Errorf("Error reading logs from DNS prober %s/%s: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		m := dnsMetric{
+			Timestamp:  pod.CreationTimestamp.UTC(),
+			MetricName: dnsLatencyMeasurement,
+			UUID:       d.Uuid,
+			JobName:    d.JobConfig.Name,
+			Namespace:  pod.Namespace,
+			Name:       pod.Name,
+			NodeName:   pod.Spec.NodeName,
+			Metadata:   d.Metadata,
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(logs))
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) != 3 || fields[0] != dnsProbePrefix {
+				continue
+			}
+			m.Queries++
+			switch fields[2] {
+			case "NOERROR":
+				if latency, err := strconv.ParseFloat(fields[1], 64); err == nil {
+					latencies = append(latencies, latency)
+				}
+			case "NXDOMAIN":
+				m.NXDomain++
+			default:
+				m.Errors++
+			}
+		}
+		if m.Queries > 0 {
+			m.NXDomainRate = float64(m.NXDomain) / float64(m.Queries) * 100
+			m.ErrorRate = float64(m.Errors) / float64(m.Queries) * 100
+		} else {
+			missingProbes++
+		}
+		d.normLatencies = append(d.normLatencies, m)
+	}
+	if len(pods.Items) > 0 {
+		d.recordQuality(qualityMissingProbes, float64(missingProbes)/float64(len(pods.Items))*100)
+	}
+	return latencies
+}
+
+// scrapeCoredns returns the sum of the CoreDNS counters across all its pods
+func (d *dnsLatency) scrapeCoredns() corednsCounters {
+	counters := corednsCounters{}
+	pods, err := d.ClientSet.CoreV1().Pods(d.Config.CorednsNamespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: d.Config.CorednsLabelSelector,
+	})
+	if err != nil {
+		log.Errorf("Error listing CoreDNS pods: %v", err)
+		return nil
+	}
+	for _, pod := range pods.Items {
+		data, err := d.ClientSet.CoreV1().Pods(pod.Namespace).ProxyGet("http", pod.Name, corednsMetricsPort, "metrics", nil).DoRaw(context.TODO())
+		if err != nil {
+			log.Warnf("Error scraping CoreDNS pod %s/%s: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		var parser expfmt.TextParser
+		families, err := parser.TextToMetricFamilies(bytes.NewReader(data))
+		if err != nil {
+			log.Warnf("Error parsing CoreDNS pod %s/%s metrics: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		for name, family := range families {
+			for _, metric := range family.GetMetric() {
+				counters[name] += metric.GetCounter().GetValue()
+				if name != "coredns_dns_responses_total" {
+					continue
+				}
+				for _, label := range metric.GetLabel() {
+					if label.GetName() == "rcode" {
+						counters[name+"/"+label.GetValue()] += metric.GetCounter().GetValue()
+					}
+				}
+			}
+		}
+	}
+	return counters
+}
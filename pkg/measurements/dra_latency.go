@@ -0,0 +1,330 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	draLatencyMeasurement          = "draLatencyMeasurement"
+	draLatencyQuantilesMeasurement = "draLatencyQuantilesMeasurement"
+	draDriverMeasurement           = "draDriverMeasurement"
+	draAllocated                   = "Allocated"
+	draReserved                    = "Reserved"
+	draGroup                       = "resource.k8s.io"
+)
+
+var (
+	supportedDRAConditions = map[string]struct{}{
+		draAllocated: {},
+		draReserved:  {},
+	}
+)
+
+// draMetric holds the latencies of a ResourceClaim
+type draMetric struct {
+	Timestamp        time.Time `json:"timestamp"`
+	allocated        time.Time
+	AllocatedLatency int `json:"allocatedLatency"`
+	reserved         time.Time
+	ReservedLatency  int    `json:"reservedLatency"`
+	UUID             string `json:"uuid"`
+	Name             string `json:"claimName"`
+	JobName          string `json:"jobName,omitempty"`
+	Namespace        string `json:"namespace"`
+	MetricName       string `json:"metricName"`
+	DeviceClass      string `json:"deviceClass"`
+	Driver           string `json:"driver"`
+	Devices          int    `json:"devices"`
+	JobIteration     int    `json:"jobIteration"`
+	Replica          int    `json:"replica"`
+	Metadata         any    `json:"metadata,omitempty"`
+}
+
+// draDriverMetric holds the allocation throughput of a DRA driver
+type draDriverMetric struct {
+	Timestamp       time.Time `json:"timestamp"`
+	MetricName      string    `json:"metricName"`
+	UUID            string    `json:"uuid"`
+	JobName         string    `json:"jobName,omitempty"`
+	Driver          string    `json:"driver"`
+	Allocations     int       `json:"allocations"`
+	FirstAllocation time.Time `json:"firstAllocation"`
+	LastAllocation  time.Time `json:"lastAllocation"`
+	Throughput      float64   `json:"throughput"`
+	Metadata        any       `json:"metadata,omitempty"`
+}
+
+type draLatency struct {
+	BaseMeasurement
+	drivers []any
+	// skipped when dynamic resource allocation is not enabled in the cluster
+	skipped bool
+}
+
+type draLatencyMeasurementFactory struct {
+	BaseMeasurementFactory
+}
+
+func newDRALatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any) (MeasurementFactory, error) {
+	if err := verifyMeasurementConfig(measurement, supportedDRAConditions); err != nil {
+		return nil, err
+	}
+	return draLatencyMeasurementFactory{
+		BaseMeasurementFactory: NewBaseMeasurementFactory(configSpec, measurement, metadata),
+	}, nil
+}
+
+func (dlmf draLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) Measurement {
+	return &draLatency{
+		BaseMeasurement: dlmf.NewBaseLatency(jobConfig, clientSet, restConfig, draLatencyMeasurement, draLatencyQuantilesMeasurement, embedCfg),
+	}
+}
+
+// handleCreateClaim tracks the claims created by the benchmark, either directly or from a ResourceClaimTemplate
+func (d *draLatency) handleCreateClaim(obj any) {
+	claim := obj.(*unstructured.Unstructured)
+	claimLabels := claim.GetLabels()
+	var deviceClass string
+	if requests, _, _ := unstructured.NestedSlice(claim.Object, "spec", "devices", "requests"); len(requests) > 0 {
+		if request, ok := requests[0].(map[string]any); ok {
+			deviceClass, _, _ = unstructured.NestedString(request, "deviceClassName")
+			if deviceClass == "" {
+				// resource.k8s.io/v1 nests the device class under exactly
+				deviceClass, _, _ = unstructured.NestedString(request, "exactly", "deviceClassName")
+			}
+		}
+	}
+	d.metrics.LoadOrStore(string(claim.GetUID()), draMetric{
+		Timestamp:    time.Now().UTC(),
+		Namespace:    claim.GetNamespace(),
+		Name:         claim.GetName(),
+		DeviceClass:  deviceClass,
+		MetricName:   draLatencyMeasurement,
+		UUID:         d.Uuid,
+		JobName:      d.JobConfig.Name,
+		Metadata:     d.Metadata,
+		JobIteration: getIntFromLabels(claimLabels, config.KubeBurnerLabelJobIteration),
+		Replica:      getIntFromLabels(claimLabels, config.KubeBurnerLabelReplica),
+	})
+	d.handleUpdateClaim(obj)
+}
+
+// handleUpdateClaim records the allocation and reservation timestamps of the claim
+func (d *draLatency) handleUpdateClaim(obj any) {
+	claim := obj.(*unstructured.Unstructured)
+	value, exists := d.metrics.Load(string(claim.GetUID()))
+	if !exists {
+		return
+	}
+	dm := value.(draMetric)
+	now := time.Now().UTC()
+	if dm.allocated.IsZero() {
+		if results, found, _ := unstructured.NestedSlice(claim.Object, "status", "allocation", "devices", "results"); found {
+			log.Debugf("ResourceClaim %s/%s allocated", claim.GetNamespace(), claim.GetName())
+			dm.allocated = now
+			dm.Devices = len(results)
+			if len(results) > 0 {
+				if result, ok := results[0].(map[string]any); ok {
+					dm.Driver, _, _ = unstructured.NestedString(result, "driver")
+				}
+			}
+		}
+	}
+	if dm.reserved.IsZero() {
+		if reservedFor, _, _ := unstructured.NestedSlice(claim.Object, "status", "reservedFor"); len(reservedFor) > 0 {
+			log.Debugf("ResourceClaim %s/%s reserved", claim.GetNamespace(), claim.GetName())
+			dm.reserved = now
+		}
+	}
+	d.metrics.Store(string(claim.GetUID()), dm)
+}
+
+// Start starts the ResourceClaim watcher using the API version served by the cluster
+func (d *draLatency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	d.drivers = nil
+	gvr, err := d.resourceClaimGVR()
+	if err != nil {
+		log.Warnf("%s: skipping draLatency measurement: %v", d.JobConfig.Name, err)
+		d.skipped = true
+		return nil
+	}
+	d.skipped = false
+	dynamicClient, err := dynamic.NewForConfig(d.RestConfig)
+	if err != nil {
+		log.Fatalf("Error creating dynamic client: %v", err)
+	}
+	d.startMeasurement(
+		[]MeasurementWatcher{
+			{
+				dynamicClient: dynamicClient,
+				gvr:           gvr,
+				name:          "resourceClaimWatcher",
+				resource:      gvr.Resource,
+				labelSelector: fmt.Sprintf("kube-burner-runid=%v", d.Runid),
+				handlers: &cache.ResourceEventHandlerFuncs{
+					AddFunc: d.handleCreateClaim,
+					UpdateFunc: func(oldObj, newObj any) {
+						d.handleUpdateClaim(newObj)
+					},
+				},
+			},
+		},
+	)
+	return nil
+}
+
+// resourceClaimGVR returns the ResourceClaim resource of the preferred resource.k8s.io version
+func (d *draLatency) resourceClaimGVR() (schema.GroupVersionResource, error) {
+	groups, err := d.ClientSet.Discovery().ServerGroups()
+	if err != nil {
+		return schema.GroupVersionResource{}, fmt.Errorf("error discovering API groups: %v", err)
+	}
+	for _, group := range groups.Groups {
+		if group.Name == draGroup {
+			log.Debugf("Using %s to watch ResourceClaims", group.PreferredVersion.GroupVersion)
+			return schema.GroupVersionResource{Group: draGroup, Version: group.PreferredVersion.Version, Resource: "resourceclaims"}, nil
+		}
+	}
+	return schema.GroupVersionResource{}, fmt.Errorf("API group %s not available, dynamic resource allocation is not enabled in the cluster", draGroup)
+}
+
+// Collect is not supported by this measurement
+func (d *draLatency) Collect(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	return nil
+}
+
+// Stop stops the measurement and calculates the allocation throughput of each driver
+func (d *draLatency) Stop() error {
+	if d.skipped {
+		return nil
+	}
+	err := d.StopMeasurement(d.normalizeMetrics, d.getLatency)
+	drivers := map[string]*draDriverMetric{}
+	for _, normLatency := range d.normLatencies {
+		m := normLatency.(draMetric)
+		if m.Driver == "" {
+			continue
+		}
+		dm, exists := drivers[m.Driver]
+		if !exists {
+			dm = &draDriverMetric{
+				Timestamp:       time.Now().UTC(),
+				MetricName:      draDriverMeasurement,
+				UUID:            d.Uuid,
+				JobName:         d.JobConfig.Name,
+				Driver:          m.Driver,
+				FirstAllocation: m.allocated,
+				LastAllocation:  m.allocated,
+				Metadata:        d.Metadata,
+			}
+			drivers[m.Driver] = dm
+		}
+		dm.Allocations++
+		if m.allocated.Before(dm.FirstAllocation) {
+			dm.FirstAllocation = m.allocated
+		}
+		if m.allocated.After(dm.LastAllocation) {
+			dm.LastAllocation = m.allocated
+		}
+	}
+	for _, dm := range drivers {
+		dm.Throughput = float64(dm.Allocations)
+		if elapsed := dm.LastAllocation.Sub(dm.FirstAllocation).Seconds(); elapsed >= 1 {
+			dm.Throughput = float64(dm.Allocations) / elapsed
+		}
+		log.Infof("%s: DRA driver %s allocated %d claims, throughput: %.2f allocations/s", d.JobConfig.Name, dm.Driver, dm.Allocations, dm.Throughput)
+		d.drivers = append(d.drivers, *dm)
+	}
+	return err
+}
+
+// Index indexes the claim latencies, their quantiles and the driver throughput documents
+func (d *draLatency) Index(jobName string, indexerList map[string]indexers.Indexer) error {
+	if d.skipped {
+		return nil
+	}
+	metricMap := map[string][]any{
+		d.MeasurementName:          d.normLatencies,
+		d.QuantilesMeasurementName: d.latencyQuantiles,
+		draDriverMeasurement:       d.drivers,
+	}
+	return d.indexLatencyMeasurement(jobName, metricMap, indexerList)
+}
+
+// normalizeMetrics calculates the latencies of the allocated claims
+func (d *draLatency) normalizeMetrics() float64 {
+	totalClaims := 0
+	erroredClaims := 0
+	d.metrics.Range(func(key, value any) bool {
+		m := value.(draMetric)
+		// Claims not consumed by any pod are never allocated
+		if m.allocated.IsZero() {
+			log.Tracef("ResourceClaim %v latency ignored as it was not allocated", m.Name)
+			return true
+		}
+		errorFlag := 0
+		m.AllocatedLatency = int(m.allocated.Sub(m.Timestamp).Milliseconds())
+		if m.AllocatedLatency < 0 {
+			log.Tracef("AllocatedLatency for claim %v falling under negative case. So explicitly setting it to 0", m.Name)
+			errorFlag = 1
+			m.AllocatedLatency = 0
+		}
+		if !m.reserved.IsZero() {
+			m.ReservedLatency = int(m.reserved.Sub(m.Timestamp).Milliseconds())
+			if m.ReservedLatency < 0 {
+				log.Tracef("ReservedLatency for claim %v falling under negative case. So explicitly setting it to 0", m.Name)
+				errorFlag = 1
+				m.ReservedLatency = 0
+			}
+		}
+		totalClaims++
+		erroredClaims += errorFlag
+		d.normLatencies = append(d.normLatencies, m)
+		return true
+	})
+	if totalClaims == 0 {
+		return 0.0
+	}
+	return float64(erroredClaims) / float64(totalClaims) * 100.0
+}
+
+func (d *draLatency) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(draMetric)
+	latencies := map[string]float64{
+		draAllocated: float64(m.AllocatedLatency),
+	}
+	if !m.reserved.IsZero() {
+		latencies[draReserved] = float64(m.ReservedLatency)
+	}
+	return latencies
+}
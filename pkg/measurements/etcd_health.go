@@ -0,0 +1,399 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	"github.com/prometheus/common/expfmt"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+const (
+	etcdHealthMeasurement       = "etcdHealthMeasurement"
+	etcdObjectCountMeasurement  = "etcdObjectCountMeasurement"
+	defaultEtcdNamespace        = "kube-system"
+	defaultEtcdLabelSelector    = "component=etcd"
+	defaultEtcdHealthInterval   = 30 * time.Second
+	etcdctlTimeout              = 30 * time.Second
+	apiserverStorageObjects     = "apiserver_storage_objects"
+	apiserverStorageObjectLabel = "resource"
+)
+
+// Endpoint and client certificates of the etcd members deployed by kubeadm
+var defaultEtcdctlArgs = []string{
+	"--endpoints=https://127.0.0.1:2379",
+	"--cacert=/etc/kubernetes/pki/etcd/ca.crt",
+	"--cert=/etc/kubernetes/pki/etcd/server.crt",
+	"--key=/etc/kubernetes/pki/etcd/server.key",
+}
+
+// etcdHealthMetric growth and events of the etcd cluster during a job
+type etcdHealthMetric struct {
+	Timestamp    time.Time `json:"timestamp"`
+	EndTimestamp time.Time `json:"endTimestamp"`
+	MetricName   string    `json:"metricName"`
+	UUID         string    `json:"uuid"`
+	JobName      string    `json:"jobName,omitempty"`
+	Version      string    `json:"version"`
+	Members      int       `json:"members"`
+	// Sizes of the largest member, in bytes
+	DBSizeBefore      int64 `json:"dbSizeBefore"`
+	DBSizeAfter       int64 `json:"dbSizeAfter"`
+	DBSizeGrowth      int64 `json:"dbSizeGrowth"`
+	DBSizeInUseBefore int64 `json:"dbSizeInUseBefore"`
+	DBSizeInUseAfter  int64 `json:"dbSizeInUseAfter"`
+	DBSizeInUseGrowth int64 `json:"dbSizeInUseGrowth"`
+	// RevisionGrowth number of revisions, one per write transaction, during the job
+	RevisionGrowth int64 `json:"revisionGrowth"`
+	// LeaderChanges raft terms started during the job, each one a leader election
+	LeaderChanges int64 `json:"leaderChanges"`
+	// Compactions and defragmentations observed as drops of the in use and total sizes of the members
+	Compactions int `json:"compactions"`
+	Defrags     int `json:"defrags"`
+	Metadata    any `json:"metadata,omitempty"`
+}
+
+// etcdObjectCountMetric number of objects of a resource stored in etcd before and after a job
+type etcdObjectCountMetric struct {
+	Timestamp  time.Time `json:"timestamp"`
+	MetricName string    `json:"metricName"`
+	UUID       string    `json:"uuid"`
+	JobName    string    `json:"jobName,omitempty"`
+	Resource   string    `json:"resource"`
+	Before     int64     `json:"before"`
+	After      int64     `json:"after"`
+	Delta      int64     `json:"delta"`
+	Metadata   any       `json:"metadata,omitempty"`
+}
+
+// etcdMemberStatus etcdctl endpoint status JSON output, only the fields used
+type etcdMemberStatus struct {
+	Endpoint string `json:"Endpoint"`
+	Status   struct {
+		Header struct {
+			MemberID uint64 `json:"member_id"`
+			Revision int64  `json:"revision"`
+		} `json:"header"`
+		Version     string `json:"version"`
+		DBSize      int64  `json:"dbSize"`
+		DBSizeInUse int64  `json:"dbSizeInUse"`
+		Leader      uint64 `json:"leader"`
+		RaftTerm    uint64 `json:"raftTerm"`
+	} `json:"Status"`
+}
+
+type etcdHealth struct {
+	BaseMeasurement
+	mu         sync.Mutex
+	start      time.Time
+	first      []etcdMemberStatus
+	last       []etcdMemberStatus
+	countStart map[string]int64
+	// Drops of the member sizes since the job started
+	compactions int
+	defrags     int
+	health      []any
+	counts      []any
+	stopCh      chan struct{}
+	doneCh      chan struct{}
+}
+
+type etcdHealthMeasurementFactory struct {
+	BaseMeasurementFactory
+}
+
+func newEtcdHealthMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any) (MeasurementFactory, error) {
+	if measurement.EtcdNamespace == "" {
+		measurement.EtcdNamespace = defaultEtcdNamespace
+	}
+	if measurement.EtcdLabelSelector == "" {
+		measurement.EtcdLabelSelector = defaultEtcdLabelSelector
+	}
+	if _, err := labels.Parse(measurement.EtcdLabelSelector); err != nil {
+		return nil, fmt.Errorf("invalid etcdLabelSelector %q: %v", measurement.EtcdLabelSelector, err)
+	}
+	if measurement.EtcdctlArgs == nil {
+		measurement.EtcdctlArgs = defaultEtcdctlArgs
+	}
+	if measurement.EtcdHealthInterval <= 0 {
+		measurement.EtcdHealthInterval = defaultEtcdHealthInterval
+	}
+	return etcdHealthMeasurementFactory{
+		BaseMeasurementFactory: NewBaseMeasurementFactory(configSpec, measurement, metadata),
+	}, nil
+}
+
+func (emf etcdHealthMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) Measurement {
+	return &etcdHealth{
+		BaseMeasurement: emf.NewBaseLatency(jobConfig, clientSet, restConfig, etcdHealthMeasurement, "", embedCfg),
+	}
+}
+
+// Start records the initial status of the etcd members and object counts, and starts sampling the members
+func (e *etcdHealth) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	e.health, e.counts = nil, nil
+	e.first, e.last = nil, nil
+	e.compactions, e.defrags = 0, 0
+	e.start = time.Now().UTC()
+	counts, err := e.objectCounts()
+	if err != nil {
+		log.Warnf("Error getting the object counts of the API server: %v", err)
+	}
+	e.countStart = counts
+	e.sample()
+	e.stopCh = make(chan struct{})
+	e.doneCh = make(chan struct{})
+	go e.run()
+	return nil
+}
+
+func (e *etcdHealth) run() {
+	defer close(e.doneCh)
+	ticker := time.NewTicker(e.Config.EtcdHealthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.stopCh:
+			return
+		case <-ticker.C:
+			e.sample()
+		}
+	}
+}
+
+// Collect is not supported by this measurement
+func (e *etcdHealth) Collect(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	return nil
+}
+
+// Stop takes a last sample and computes the growth of etcd and of the object counts during the job
+func (e *etcdHealth) Stop() error {
+	if e.stopCh == nil {
+		return nil
+	}
+	close(e.stopCh)
+	<-e.doneCh
+	e.stopCh = nil
+	e.sample()
+	end := time.Now().UTC()
+	if len(e.first) > 0 && len(e.last) > 0 {
+		before, after := etcdClusterStatus(e.first), etcdClusterStatus(e.last)
+		m := etcdHealthMetric{
+			Timestamp:         e.start,
+			EndTimestamp:      end,
+			MetricName:        etcdHealthMeasurement,
+			UUID:              e.Uuid,
+			JobName:           e.JobConfig.Name,
+			Version:           after.Status.Version,
+			Members:           len(e.last),
+			DBSizeBefore:      before.Status.DBSize,
+			DBSizeAfter:       after.Status.DBSize,
+			DBSizeGrowth:      after.Status.DBSize - before.Status.DBSize,
+			DBSizeInUseBefore: before.Status.DBSizeInUse,
+			DBSizeInUseAfter:  after.Status.DBSizeInUse,
+			DBSizeInUseGrowth: after.Status.DBSizeInUse - before.Status.DBSizeInUse,
+			RevisionGrowth:    after.Status.Header.Revision - before.Status.Header.Revision,
+			LeaderChanges:     int64(after.Status.RaftTerm) - int64(before.Status.RaftTerm),
+			Compactions:       e.compactions,
+			Defrags:           e.defrags,
+			Metadata:          e.Metadata,
+		}
+		e.health = append(e.health, m)
+		log.Infof("%s: etcd DB size %d -> %d bytes (in use %d -> %d), %d revisions, %d leader changes, %d compactions, %d defrags",
+			e.JobConfig.Name, m.DBSizeBefore, m.DBSizeAfter, m.DBSizeInUseBefore, m.DBSizeInUseAfter, m.RevisionGrowth, m.LeaderChanges, m.Compactions, m.Defrags)
+	} else {
+		log.Warnf("No etcd status collected for job %s", e.JobConfig.Name)
+	}
+	if e.countStart == nil {
+		return nil
+	}
+	counts, err := e.objectCounts()
+	if err != nil {
+		log.Warnf("Error getting the object counts of the API server: %v", err)
+		return nil
+	}
+	// Resources counted before or after the job
+	resources := maps.Clone(e.countStart)
+	maps.Copy(resources, counts)
+	for _, resource := range slices.Sorted(maps.Keys(resources)) {
+		before, after := e.countStart[resource], counts[resource]
+		if before == after {
+			continue
+		}
+		e.counts = append(e.counts, etcdObjectCountMetric{
+			Timestamp:  end,
+			MetricName: etcdObjectCountMeasurement,
+			UUID:       e.Uuid,
+			JobName:    e.JobConfig.Name,
+			Resource:   resource,
+			Before:     before,
+			After:      after,
+			Delta:      after - before,
+			Metadata:   e.Metadata,
+		})
+	}
+	return nil
+}
+
+// Index indexes the etcd growth and the object counts that changed during the job
+func (e *etcdHealth) Index(jobName string, indexerList map[string]indexers.Indexer) error {
+	metricMap := map[string][]any{
+		etcdHealthMeasurement:      e.health,
+		etcdObjectCountMeasurement: e.counts,
+	}
+	return e.indexLatencyMeasurement(jobName, metricMap, indexerList)
+}
+
+// etcdClusterStatus returns the status of the largest member, the one whose growth is the most relevant, with the
+// highest revision and raft term among the members
+func etcdClusterStatus(members []etcdMemberStatus) etcdMemberStatus {
+	var status etcdMemberStatus
+	for _, member := range members {
+		if member.Status.DBSize > status.Status.DBSize {
+			status.Status.DBSize, status.Status.DBSizeInUse = member.Status.DBSize, member.Status.DBSizeInUse
+		}
+		status.Status.Header.Revision = max(status.Status.Header.Revision, member.Status.Header.Revision)
+		status.Status.RaftTerm = max(status.Status.RaftTerm, member.Status.RaftTerm)
+		status.Status.Version = member.Status.Version
+	}
+	return status
+}
+
+// sample gets the status of the etcd members, accounting the drops of their sizes since the previous sample
+func (e *etcdHealth) sample() {
+	members, err := e.memberStatus()
+	if err != nil {
+		log.Warnf("Error getting the etcd status: %v", err)
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	previous := make(map[uint64]etcdMemberStatus, len(e.last))
+	for _, member := range e.last {
+		previous[member.Status.Header.MemberID] = member
+	}
+	for _, member := range members {
+		prev, ok := previous[member.Status.Header.MemberID]
+		if !ok {
+			continue
+		}
+		if member.Status.DBSize < prev.Status.DBSize {
+			log.Infof("etcd member %s defragmented: DB size %d -> %d bytes", member.Endpoint, prev.Status.DBSize, member.Status.DBSize)
+			e.defrags++
+		} else if member.Status.DBSizeInUse < prev.Status.DBSizeInUse {
+			log.Debugf("etcd member %s compacted: DB size in use %d -> %d bytes", member.Endpoint, prev.Status.DBSizeInUse, member.Status.DBSizeInUse)
+			e.compactions++
+		}
+	}
+	if e.first == nil {
+		e.first = members
+	}
+	e.last = members
+}
+
+// memberStatus runs etcdctl endpoint status in the first running etcd pod
+func (e *etcdHealth) memberStatus() ([]etcdMemberStatus, error) {
+	pods, err := e.ClientSet.CoreV1().Pods(e.Config.EtcdNamespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: e.Config.EtcdLabelSelector,
+		FieldSelector: "status.phase=" + string(corev1.PodRunning),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pods.Items) == 0 {
+		return nil, fmt.Errorf("no etcd pods match %s in namespace %s", e.Config.EtcdLabelSelector, e.Config.EtcdNamespace)
+	}
+	pod := pods.Items[0]
+	command := slices.Concat([]string{"etcdctl"}, e.Config.EtcdctlArgs, []string{"endpoint", "status", "--cluster", "-w", "json"})
+	var stdout, stderr bytes.Buffer
+	req := e.ClientSet.CoreV1().
+		RESTClient().
+		Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		SubResource("exec")
+	req.VersionedParams(&corev1.PodExecOptions{
+		Command:   command,
+		Container: pod.Spec.Containers[0].Name,
+		Stdout:    true,
+		Stderr:    true,
+	}, scheme.ParameterCodec)
+	exec, err := remotecommand.NewSPDYExecutor(e.RestConfig, "POST", req.URL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to establish SPDYExecutor on %s: %s", pod.Name, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), etcdctlTimeout)
+	defer cancel()
+	if err := exec.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr}); err != nil {
+		return nil, fmt.Errorf("etcdctl failed on %s: %v %s", pod.Name, err, strings.TrimSpace(stderr.String()))
+	}
+	var members []etcdMemberStatus
+	if err := json.Unmarshal(stdout.Bytes(), &members); err != nil {
+		return nil, fmt.Errorf("error decoding etcdctl output: %v", err)
+	}
+	return members, nil
+}
+
+// objectCounts returns the number of objects stored in etcd by resource, as reported by the API server
+func (e *etcdHealth) objectCounts() (map[string]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), etcdctlTimeout)
+	defer cancel()
+	data, err := e.ClientSet.CoreV1().RESTClient().Get().AbsPath("/metrics").DoRaw(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the API server metrics: %v", err)
+	}
+	counts := make(map[string]int64)
+	family, ok := families[apiserverStorageObjects]
+	if !ok {
+		return counts, nil
+	}
+	for _, metric := range family.GetMetric() {
+		for _, label := range metric.GetLabel() {
+			// Counts not known yet are reported as -1
+			if label.GetName() == apiserverStorageObjectLabel && metric.GetGauge().GetValue() >= 0 {
+				counts[label.GetValue()] = int64(metric.GetGauge().GetValue())
+			}
+		}
+	}
+	return counts, nil
+}
@@ -15,10 +15,13 @@
 package measurements
 
 import (
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/cloud-bulldozer/go-commons/v2/indexers"
 	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/metrics"
 	"github.com/kube-burner/kube-burner/pkg/measurements/types"
 	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
 	log "github.com/sirupsen/logrus"
@@ -28,12 +31,27 @@ import (
 )
 
 type MeasurementsFactory struct {
-	Metadata  map[string]any
-	Factories map[string]MeasurementFactory
+	Metadata     map[string]any
+	Factories    map[string]MeasurementFactory
+	uuid         string
+	globalConfig config.GlobalConfig
 }
 
 type Measurements struct {
 	MeasurementsMap map[string]Measurement
+	uuid            string
+	jobName         string
+	metadata        map[string]any
+	globalConfig    config.GlobalConfig
+	// started when the measurements started, or collected the objects created before
+	started time.Time
+	// Data-quality documents
+	quality []any
+	// SLO evaluation documents and violations of the warn SLOs
+	sloDocuments []any
+	sloWarnings  []string
+	// results receives the result of every measurement phase, when set
+	results chan<- Result
 }
 
 type MeasurementFactory interface {
@@ -44,8 +62,8 @@ type NewMeasurementFactory func(config.Spec, types.Measurement, map[string]any)
 type Measurement interface {
 	Start(*sync.WaitGroup) error
 	Stop() error
-	Collect(*sync.WaitGroup)
-	Index(string, map[string]indexers.Indexer)
+	Collect(*sync.WaitGroup) error
+	Index(string, map[string]indexers.Indexer) error
 	GetMetrics() *sync.Map
 }
 
@@ -56,13 +74,23 @@ var measurementFactoryMap = map[string]NewMeasurementFactory{
 	"nodeLatency":           newNodeLatencyMeasurementFactory,
 	"vmiLatency":            newVmiLatencyMeasurementFactory,
 	"serviceLatency":        newServiceLatencyMeasurementFactory,
+	"dnsLatency":            newDNSLatencyMeasurementFactory,
+	"draLatency":            newDRALatencyMeasurementFactory,
 	"pprof":                 newPprofLatencyMeasurementFactory,
 	"netpolLatency":         newNetpolLatencyMeasurementFactory,
 	"dataVolumeLatency":     newDvLatencyMeasurementFactory,
 	"volumeSnapshotLatency": newvolumeSnapshotLatencyMeasurementFactory,
+	"criStats":              newCRIStatsMeasurementFactory,
+	"kubeletStats":          newKubeletStatsMeasurementFactory,
+	"etcdHealth":            newEtcdHealthMeasurementFactory,
+	"imagePullLatency":      newImagePullLatencyMeasurementFactory,
+	"hpaLatency":            newHPALatencyMeasurementFactory,
+	"schedulerThroughput":   newSchedulerThroughputMeasurementFactory,
+	"bareMetalLatency":      newBareMetalLatencyMeasurementFactory,
 }
 
-func isIndexerOk(configSpec config.Spec, measurement types.Measurement) bool {
+// IsIndexerOk returns whether the indexers referenced by the measurement are configured
+func IsIndexerOk(configSpec config.Spec, measurement types.Measurement) bool {
 	if measurement.QuantilesIndexer != "" || measurement.TimeseriesIndexer != "" {
 		for _, indexer := range configSpec.MetricsEndpoints {
 			if indexer.Alias == measurement.QuantilesIndexer || indexer.Alias == measurement.TimeseriesIndexer {
@@ -74,6 +102,13 @@ func isIndexerOk(configSpec config.Spec, measurement types.Measurement) bool {
 	return true
 }
 
+// IsSupported returns whether the measurement is built-in, registered by a wrapper, a plugin or derived from other measurements,
+// unsupported measurements are skipped
+func IsSupported(measurement types.Measurement) bool {
+	_, exists := measurementFactoryMap[measurement.Name]
+	return exists || measurement.Plugin != nil || measurement.Derived != nil
+}
+
 // NewMeasurementsFactory initializes the measurement facture
 func NewMeasurementsFactory(configSpec config.Spec, metadata map[string]any, additionalMeasurementFactoryMap map[string]NewMeasurementFactory) *MeasurementsFactory {
 	// Add from additionalMeasurementFactoryMap without overwriting
@@ -84,11 +119,13 @@ func NewMeasurementsFactory(configSpec config.Spec, metadata map[string]any, add
 	}
 
 	measurementsFactory := MeasurementsFactory{
-		Metadata:  metadata,
-		Factories: make(map[string]MeasurementFactory, len(configSpec.GlobalConfig.Measurements)),
+		Metadata:     metadata,
+		Factories:    make(map[string]MeasurementFactory, len(configSpec.GlobalConfig.Measurements)),
+		uuid:         configSpec.GlobalConfig.UUID,
+		globalConfig: configSpec.GlobalConfig,
 	}
 	for _, measurement := range configSpec.GlobalConfig.Measurements {
-		if !isIndexerOk(configSpec, measurement) {
+		if !IsIndexerOk(configSpec, measurement) {
 			log.Fatalf("One of the indexers for measurement %s has not been found", measurement.Name)
 		}
 		if _, alreadyRegistered := measurementsFactory.Factories[measurement.Name]; alreadyRegistered {
@@ -96,10 +133,38 @@ func NewMeasurementsFactory(configSpec config.Spec, metadata map[string]any, add
 			continue
 		}
 		newMeasurementFactoryFunc, exists := measurementFactoryMap[measurement.Name]
+		if measurement.Plugin != nil && measurement.Derived != nil {
+			log.Fatalf("Measurement [%s]: plugin and derived are mutually exclusive", measurement.Name)
+		}
+		if measurement.Plugin != nil {
+			if exists {
+				log.Fatalf("Measurement [%s]: plugins can't take the name of a built-in measurement", measurement.Name)
+			}
+			newMeasurementFactoryFunc, exists = newPluginMeasurementFactory, true
+		}
+		if measurement.Derived != nil {
+			if exists {
+				log.Fatalf("Measurement [%s]: derived measurements can't take the name of a built-in measurement", measurement.Name)
+			}
+			newMeasurementFactoryFunc, exists = newDerivedMeasurementFactory, true
+		}
 		if !exists {
 			log.Warnf("Measurement [%s] is not supported", measurement.Name)
 			continue
 		}
+		if measurement.Streaming != nil {
+			if err := validateStreaming(&measurement); err != nil {
+				log.Fatal(err.Error())
+			}
+		}
+		if measurement.Histogram != nil {
+			if err := validateHistogram(&measurement); err != nil {
+				log.Fatal(err.Error())
+			}
+		}
+		if err := validateSLOs(&measurement); err != nil {
+			log.Fatal(err.Error())
+		}
 		mf, err := newMeasurementFactoryFunc(configSpec, measurement, metadata)
 		if err != nil {
 			log.Fatal(err.Error())
@@ -113,54 +178,163 @@ func NewMeasurementsFactory(configSpec config.Spec, metadata map[string]any, add
 func (msf *MeasurementsFactory) NewMeasurements(jobConfig *config.Job, kubeClientProvider *config.KubeClientProvider, embedCfg *fileutils.EmbedConfiguration) *Measurements {
 	ms := Measurements{
 		MeasurementsMap: make(map[string]Measurement, len(msf.Factories)),
+		uuid:            msf.uuid,
+		jobName:         jobConfig.Name,
+		metadata:        msf.Metadata,
+		globalConfig:    msf.globalConfig,
 	}
 	clientSet, restConfig := kubeClientProvider.ClientSet(jobConfig.QPS, jobConfig.Burst)
 	for name, factory := range msf.Factories {
 		ms.MeasurementsMap[name] = factory.NewMeasurement(jobConfig, clientSet, restConfig, embedCfg)
 	}
+	for _, measurement := range ms.MeasurementsMap {
+		if d, ok := measurement.(*derived); ok {
+			d.sources = ms.MeasurementsMap
+		}
+	}
 
 	return &ms
 }
 
-// Start starts registered measurements
-func (ms *Measurements) Start() {
-	var wg sync.WaitGroup
-	for _, measurement := range ms.MeasurementsMap {
-		wg.Add(1)
-		go measurement.Start(&wg)
+// NotifyResults sends the result of every measurement to the given channel as soon as it finishes each phase, on top
+// of returning them. Sends are blocking, the channel must be drained while the measurements run
+func (ms *Measurements) NotifyResults(results chan<- Result) {
+	ms.results = results
+}
+
+// record returns the result of a measurement phase, notifying it when requested
+func (ms *Measurements) record(name string, phase Phase, start time.Time, err error) Result {
+	result := newResult(name, phase, start, err)
+	if ms.results != nil {
+		ms.results <- result
 	}
-	wg.Wait()
+	return result
 }
 
-func (ms *Measurements) Collect() {
+// runConcurrently runs a phase of all the registered measurements concurrently, each of them releasing the wait group
+func (ms *Measurements) runConcurrently(phase Phase, run func(Measurement, *sync.WaitGroup) error) Results {
 	var wg sync.WaitGroup
-	for _, measurement := range ms.MeasurementsMap {
+	var mu sync.Mutex
+	results := make(Results, 0, len(ms.MeasurementsMap))
+	for name, measurement := range ms.MeasurementsMap {
 		wg.Add(1)
-		go measurement.Collect(&wg)
+		go func(name string, measurement Measurement) {
+			defer wg.Done()
+			var measurementWg sync.WaitGroup
+			measurementWg.Add(1)
+			start := time.Now()
+			err := run(measurement, &measurementWg)
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, ms.record(name, phase, start, err))
+		}(name, measurement)
 	}
 	wg.Wait()
+	return results
 }
 
-// Stop stops registered measurements
-// returns a concatenated list of error strings with a new line between each string
-func (ms *Measurements) Stop() error {
-	errs := []error{}
-	for name, measurement := range ms.MeasurementsMap {
-		log.Infof("Stopping measurement: %s", name)
-		errs = append(errs, measurement.Stop())
+// Start starts registered measurements, returns the result of each of them
+func (ms *Measurements) Start() Results {
+	ms.started = time.Now().UTC()
+	return ms.runConcurrently(PhaseStart, Measurement.Start)
+}
+
+// Collect collects the metrics of the objects created before the registered measurements started, returns the result
+// of each of them
+func (ms *Measurements) Collect() Results {
+	if ms.started.IsZero() {
+		ms.started = time.Now().UTC()
+	}
+	return ms.runConcurrently(PhaseCollect, Measurement.Collect)
+}
+
+// Stop stops registered measurements, returns the result of each of them, latency thresholds included, along with the
+// result of the SLOs and data-quality checks of the job
+func (ms *Measurements) Stop() Results {
+	results := make(Results, 0, len(ms.MeasurementsMap)+2)
+	// Derived measurements are stopped once their sources are
+	for _, stopDerived := range []bool{false, true} {
+		for name, measurement := range ms.MeasurementsMap {
+			if _, ok := measurement.(*derived); ok != stopDerived {
+				continue
+			}
+			log.Infof("Stopping measurement: %s", name)
+			start := time.Now()
+			results = append(results, ms.record(name, PhaseStop, start, measurement.Stop()))
+		}
 	}
-	return utilerrors.NewAggregate(errs)
+	start := time.Now()
+	results = append(results, ms.record(sloEvaluationMeasurement, PhaseStop, start, ms.evaluateSLOs()))
+	start = time.Now()
+	return append(results, ms.record(dataQualityMeasurement, PhaseStop, start, ms.evaluateQuality()))
 }
 
 // Index iterates over the createFuncs map, indexes collected data from each measurement.
 //
 // jobName is the name of the job to index data for.
 // indexerList is a variadic parameter of indexers.Indexer implementations.
-func (ms *Measurements) Index(jobName string, indexerList map[string]indexers.Indexer) {
+//
+// Returns the result of each measurement, along with the result of indexing the SLO evaluations and data-quality
+// checks of the job
+func (ms *Measurements) Index(jobName string, indexerList map[string]indexers.Indexer) Results {
+	results := make(Results, 0, len(ms.MeasurementsMap)+2)
+	indexerList = ms.stampDisruptions(indexerList)
 	for name, measurement := range ms.MeasurementsMap {
 		log.Infof("Indexing collected data from measurement: %s", name)
-		measurement.Index(jobName, indexerList)
+		start := time.Now()
+		results = append(results, ms.record(name, PhaseIndex, start, measurement.Index(jobName, indexerList)))
+	}
+	for name, documents := range map[string][]any{sloEvaluationMeasurement: ms.sloDocuments, dataQualityMeasurement: ms.quality} {
+		if len(documents) == 0 {
+			continue
+		}
+		var errs []error
+		start := time.Now()
+		metricName := fmt.Sprintf("%s-%s", name, jobName)
+		for _, indexer := range indexerList {
+			resp, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: metricName})
+			if err != nil {
+				errs = append(errs, err)
+			} else {
+				log.Info(resp)
+			}
+		}
+		results = append(results, ms.record(name, PhaseIndex, start, utilerrors.NewAggregate(errs)))
+	}
+	return results
+}
+
+// quantilesReporter measurements exposing the latency quantiles computed when they're stopped
+type quantilesReporter interface {
+	quantiles() []any
+}
+
+func (bm *BaseMeasurement) quantiles() []any {
+	return bm.latencyQuantiles
+}
+
+// LatencyQuantiles returns the global latency quantiles of the stopped measurements, by <quantiles metric>.<quantile name>.<statistic>
+func (ms *Measurements) LatencyQuantiles() map[string]float64 {
+	quantiles := make(map[string]float64)
+	for _, measurement := range ms.MeasurementsMap {
+		reporter, ok := measurement.(quantilesReporter)
+		if !ok {
+			continue
+		}
+		for _, q := range reporter.quantiles() {
+			lq, ok := q.(metrics.LatencyQuantiles)
+			if !ok || lq.Group != "" {
+				continue
+			}
+			prefix := lq.MetricName + "." + lq.QuantileName
+			quantiles[prefix+".P50"] = float64(lq.P50)
+			quantiles[prefix+".P95"] = float64(lq.P95)
+			quantiles[prefix+".P99"] = float64(lq.P99)
+			quantiles[prefix+".avg"] = float64(lq.Avg)
+			quantiles[prefix+".max"] = float64(lq.Max)
+		}
 	}
+	return quantiles
 }
 
 func (ms *Measurements) GetMetrics() []*sync.Map {
@@ -0,0 +1,69 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+)
+
+const (
+	defaultHistogramMin                = time.Millisecond
+	defaultHistogramMax                = time.Hour
+	defaultHistogramSignificantFigures = 2
+)
+
+// Measurements whose quantiles are calculated by calculateQuantiles, hence supporting histograms
+var histogramMeasurements = []string{"podLatency", "jobLatency", "pvcLatency", "nodeLatency", "vmiLatency", "draLatency", "dataVolumeLatency", "volumeSnapshotLatency", "criStats", "imagePullLatency", "hpaLatency", "schedulerThroughput", "bareMetalLatency"}
+
+func validateHistogram(measurement *types.Measurement) error {
+	if !slices.Contains(histogramMeasurements, measurement.Name) {
+		return fmt.Errorf("measurement %s doesn't support histograms", measurement.Name)
+	}
+	histogram := measurement.Histogram
+	if len(histogram.Buckets) > 0 {
+		for i, bucket := range histogram.Buckets {
+			if bucket <= 0 || (i > 0 && bucket <= histogram.Buckets[i-1]) {
+				return fmt.Errorf("measurement %s: histogram buckets must be positive and ascending", measurement.Name)
+			}
+		}
+		return nil
+	}
+	if histogram.Min == 0 {
+		histogram.Min = defaultHistogramMin
+	}
+	if histogram.Max == 0 {
+		histogram.Max = defaultHistogramMax
+	}
+	if histogram.SignificantFigures == 0 {
+		histogram.SignificantFigures = defaultHistogramSignificantFigures
+	}
+	if histogram.Min < 0 || histogram.Max <= histogram.Min {
+		return fmt.Errorf("measurement %s: histogram min must be positive and lower than max", measurement.Name)
+	}
+	if histogram.SignificantFigures < 1 || histogram.SignificantFigures > 5 {
+		return fmt.Errorf("measurement %s: histogram significantFigures must be between 1 and 5", measurement.Name)
+	}
+	return nil
+}
+
+// histogramMeasurementName returns the metric name of the histograms, like podLatencyHistogramMeasurement
+func (bm *BaseMeasurement) histogramMeasurementName() string {
+	return strings.Replace(bm.QuantilesMeasurementName, "Quantiles", "Histogram", 1)
+}
@@ -0,0 +1,362 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	hpaLatencyMeasurement          = "hpaLatencyMeasurement"
+	hpaLatencyQuantilesMeasurement = "hpaLatencyQuantilesMeasurement"
+	hpaScaleDecision               = "ScaleDecision"
+	hpaPodsReady                   = "PodsReady"
+	hpaScaleUp                     = "ScaleUp"
+)
+
+var (
+	supportedHPAConditions = map[string]struct{}{
+		hpaScaleDecision: {},
+		hpaPodsReady:     {},
+		hpaScaleUp:       {},
+	}
+)
+
+// hpaMetric holds the latencies of a scale up of a HorizontalPodAutoscaler
+type hpaMetric struct {
+	// Timestamp first time the metrics of the autoscaler were observed above their targets
+	Timestamp time.Time `json:"timestamp"`
+	decision  time.Time
+	ready     time.Time
+	// ScaleDecisionLatency time from the metric breach to the scale decision
+	ScaleDecisionLatency int `json:"scaleDecisionLatency"`
+	// PodsReadyLatency time from the scale decision to all the replicas of the target being ready
+	PodsReadyLatency int `json:"podsReadyLatency"`
+	// ScaleUpLatency time from the metric breach to all the replicas of the target being ready
+	ScaleUpLatency int    `json:"scaleUpLatency"`
+	FromReplicas   int32  `json:"fromReplicas"`
+	ToReplicas     int32  `json:"toReplicas"`
+	Target         string `json:"target"`
+	MetricName     string `json:"metricName"`
+	UUID           string `json:"uuid"`
+	JobName        string `json:"jobName,omitempty"`
+	JobIteration   int    `json:"jobIteration"`
+	Replica        int    `json:"replica"`
+	Namespace      string `json:"namespace"`
+	Name           string `json:"hpaName"`
+	Metadata       any    `json:"metadata,omitempty"`
+}
+
+// hpaState scale up in progress of an autoscaler
+type hpaState struct {
+	// breach first observation of the metrics above their targets, zero when they aren't
+	breach        time.Time
+	lastScaleTime time.Time
+	// scaleUp key of the scale up waiting for the replicas of the target, empty when there's none
+	scaleUp string
+	scales  int
+}
+
+type hpaLatency struct {
+	BaseMeasurement
+	mu sync.Mutex
+	// states by autoscaler UID
+	states map[string]*hpaState
+	// scaleUps keys of the scale ups waiting for the replicas of their target, by target
+	scaleUps map[string]string
+}
+
+type hpaLatencyMeasurementFactory struct {
+	BaseMeasurementFactory
+}
+
+func newHPALatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any) (MeasurementFactory, error) {
+	if err := verifyMeasurementConfig(measurement, supportedHPAConditions); err != nil {
+		return nil, err
+	}
+	return hpaLatencyMeasurementFactory{
+		BaseMeasurementFactory: NewBaseMeasurementFactory(configSpec, measurement, metadata),
+	}, nil
+}
+
+func (hlmf hpaLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) Measurement {
+	return &hpaLatency{
+		BaseMeasurement: hlmf.NewBaseLatency(jobConfig, clientSet, restConfig, hpaLatencyMeasurement, hpaLatencyQuantilesMeasurement, embedCfg),
+	}
+}
+
+// hpaTarget returns the key of the workload scaled by the autoscaler
+func hpaTarget(hpa *autoscalingv2.HorizontalPodAutoscaler) string {
+	return fmt.Sprintf("%s/%s/%s", hpa.Spec.ScaleTargetRef.Kind, hpa.Namespace, hpa.Spec.ScaleTargetRef.Name)
+}
+
+// metricsBreached tells whether any metric of the autoscaler is above its target
+func metricsBreached(hpa *autoscalingv2.HorizontalPodAutoscaler) bool {
+	for i, status := range hpa.Status.CurrentMetrics {
+		if i >= len(hpa.Spec.Metrics) {
+			break
+		}
+		spec := hpa.Spec.Metrics[i]
+		var target autoscalingv2.MetricTarget
+		var current autoscalingv2.MetricValueStatus
+		switch {
+		case spec.Resource != nil && status.Resource != nil:
+			target, current = spec.Resource.Target, status.Resource.Current
+		case spec.ContainerResource != nil && status.ContainerResource != nil:
+			target, current = spec.ContainerResource.Target, status.ContainerResource.Current
+		case spec.Pods != nil && status.Pods != nil:
+			target, current = spec.Pods.Target, status.Pods.Current
+		case spec.Object != nil && status.Object != nil:
+			target, current = spec.Object.Target, status.Object.Current
+		case spec.External != nil && status.External != nil:
+			target, current = spec.External.Target, status.External.Current
+		default:
+			continue
+		}
+		if aboveTarget(target, current) {
+			return true
+		}
+	}
+	return false
+}
+
+func aboveTarget(target autoscalingv2.MetricTarget, current autoscalingv2.MetricValueStatus) bool {
+	greater := func(current, target *resource.Quantity) bool {
+		return current != nil && target != nil && current.Cmp(*target) > 0
+	}
+	switch target.Type {
+	case autoscalingv2.UtilizationMetricType:
+		return target.AverageUtilization != nil && current.AverageUtilization != nil && *current.AverageUtilization > *target.AverageUtilization
+	case autoscalingv2.AverageValueMetricType:
+		return greater(current.AverageValue, target.AverageValue)
+	case autoscalingv2.ValueMetricType:
+		return greater(current.Value, target.Value)
+	}
+	return false
+}
+
+func (h *hpaLatency) handleHPA(obj any) {
+	hpa, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler)
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	state, exists := h.states[string(hpa.UID)]
+	if !exists {
+		state = &hpaState{}
+		if hpa.Status.LastScaleTime != nil {
+			state.lastScaleTime = hpa.Status.LastScaleTime.Time
+		}
+		h.states[string(hpa.UID)] = state
+	}
+	breached := metricsBreached(hpa)
+	if breached && state.breach.IsZero() {
+		state.breach = now
+	}
+	scaled := hpa.Status.LastScaleTime != nil && hpa.Status.LastScaleTime.After(state.lastScaleTime)
+	if scaled {
+		state.lastScaleTime = hpa.Status.LastScaleTime.Time
+	}
+	// Scale ups only, scale downs aren't a reaction to a breach
+	if scaled && hpa.Status.DesiredReplicas > hpa.Status.CurrentReplicas {
+		breach := state.breach
+		if breach.IsZero() || breach.After(state.lastScaleTime) {
+			// The breach happened between two observations of the autoscaler
+			breach = state.lastScaleTime
+		}
+		state.scales++
+		key := fmt.Sprintf("%s/%d", hpa.UID, state.scales)
+		if state.scaleUp != "" {
+			// A new scale up supersedes the one still waiting for its replicas
+			h.metrics.Delete(state.scaleUp)
+		}
+		state.scaleUp = key
+		h.scaleUps[hpaTarget(hpa)] = key
+		labels := hpa.GetLabels()
+		h.metrics.Store(key, hpaMetric{
+			Timestamp:    breach,
+			decision:     state.lastScaleTime,
+			FromReplicas: hpa.Status.CurrentReplicas,
+			ToReplicas:   hpa.Status.DesiredReplicas,
+			Target:       hpaTarget(hpa),
+			MetricName:   hpaLatencyMeasurement,
+			UUID:         h.Uuid,
+			JobName:      h.JobConfig.Name,
+			JobIteration: getIntFromLabels(labels, config.KubeBurnerLabelJobIteration),
+			Replica:      getIntFromLabels(labels, config.KubeBurnerLabelReplica),
+			Namespace:    hpa.Namespace,
+			Name:         hpa.Name,
+			Metadata:     h.Metadata,
+		})
+		state.breach = time.Time{}
+	} else if !breached {
+		state.breach = time.Time{}
+	}
+}
+
+// handleWorkload completes the scale up of the workload once all its replicas are ready
+func (h *hpaLatency) handleWorkload(kind, namespace, name string, readyReplicas int32) {
+	target := fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key, exists := h.scaleUps[target]
+	if !exists {
+		return
+	}
+	value, exists := h.metrics.Load(key)
+	if !exists {
+		return
+	}
+	m := value.(hpaMetric)
+	if readyReplicas < m.ToReplicas {
+		return
+	}
+	m.ready = time.Now().UTC()
+	h.metrics.Store(key, m)
+	delete(h.scaleUps, target)
+	for _, state := range h.states {
+		if state.scaleUp == key {
+			state.scaleUp = ""
+		}
+	}
+}
+
+func (h *hpaLatency) handleDeployment(obj any) {
+	if deployment, ok := obj.(*appsv1.Deployment); ok {
+		h.handleWorkload("Deployment", deployment.Namespace, deployment.Name, deployment.Status.ReadyReplicas)
+	}
+}
+
+func (h *hpaLatency) handleStatefulSet(obj any) {
+	if statefulSet, ok := obj.(*appsv1.StatefulSet); ok {
+		h.handleWorkload("StatefulSet", statefulSet.Namespace, statefulSet.Name, statefulSet.Status.ReadyReplicas)
+	}
+}
+
+func (h *hpaLatency) handleReplicaSet(obj any) {
+	if replicaSet, ok := obj.(*appsv1.ReplicaSet); ok {
+		h.handleWorkload("ReplicaSet", replicaSet.Namespace, replicaSet.Name, replicaSet.Status.ReadyReplicas)
+	}
+}
+
+// start hpaLatency measurement
+func (h *hpaLatency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	h.states = make(map[string]*hpaState)
+	h.scaleUps = make(map[string]string)
+	labelSelector := fmt.Sprintf("kube-burner-runid=%v", h.Runid)
+	appsClient := h.ClientSet.AppsV1().RESTClient().(*rest.RESTClient)
+	workloadHandlers := func(handle func(any)) *cache.ResourceEventHandlerFuncs {
+		return &cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj any) {
+				handle(newObj)
+			},
+		}
+	}
+	h.startMeasurement(
+		[]MeasurementWatcher{
+			{
+				restClient:    h.ClientSet.AutoscalingV2().RESTClient().(*rest.RESTClient),
+				name:          "hpaWatcher",
+				resource:      "horizontalpodautoscalers",
+				labelSelector: labelSelector,
+				handlers: &cache.ResourceEventHandlerFuncs{
+					AddFunc: h.handleHPA,
+					UpdateFunc: func(oldObj, newObj any) {
+						h.handleHPA(newObj)
+					},
+				},
+			},
+			{
+				restClient:    appsClient,
+				name:          "hpaDeploymentWatcher",
+				resource:      "deployments",
+				labelSelector: labelSelector,
+				handlers:      workloadHandlers(h.handleDeployment),
+			},
+			{
+				restClient:    appsClient,
+				name:          "hpaStatefulSetWatcher",
+				resource:      "statefulsets",
+				labelSelector: labelSelector,
+				handlers:      workloadHandlers(h.handleStatefulSet),
+			},
+			{
+				restClient:    appsClient,
+				name:          "hpaReplicaSetWatcher",
+				resource:      "replicasets",
+				labelSelector: labelSelector,
+				handlers:      workloadHandlers(h.handleReplicaSet),
+			},
+		},
+	)
+	return nil
+}
+
+func (h *hpaLatency) Collect(measurementWg *sync.WaitGroup) error {
+	log.Info("Collect method doesn't apply to hpaLatency by design")
+	defer measurementWg.Done()
+	return nil
+}
+
+// Stop stops hpaLatency measurement
+func (h *hpaLatency) Stop() error {
+	return h.StopMeasurement(h.normalizeMetrics, h.getLatency)
+}
+
+func (h *hpaLatency) GetMetrics() *sync.Map {
+	return &h.metrics
+}
+
+func (h *hpaLatency) normalizeMetrics() float64 {
+	h.metrics.Range(func(key, value any) bool {
+		m := value.(hpaMetric)
+		// Scale ups whose replicas never got ready are skipped
+		if m.ready.IsZero() {
+			log.Tracef("Scale up of HPA %s/%s ignored as its replicas didn't get ready", m.Namespace, m.Name)
+			return true
+		}
+		m.ScaleDecisionLatency = int(m.decision.Sub(m.Timestamp).Milliseconds())
+		m.PodsReadyLatency = int(max(m.ready.Sub(m.decision), 0).Milliseconds())
+		m.ScaleUpLatency = int(m.ready.Sub(m.Timestamp).Milliseconds())
+		h.normLatencies = append(h.normLatencies, m)
+		return true
+	})
+	return 0
+}
+
+func (h *hpaLatency) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(hpaMetric)
+	return map[string]float64{
+		hpaScaleDecision: float64(m.ScaleDecisionLatency),
+		hpaPodsReady:     float64(m.PodsReadyLatency),
+		hpaScaleUp:       float64(m.ScaleUpLatency),
+	}
+}
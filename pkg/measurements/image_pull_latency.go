@@ -0,0 +1,241 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	imagePullLatencyMeasurement          = "imagePullLatencyMeasurement"
+	imagePullLatencyQuantilesMeasurement = "imagePullLatencyQuantilesMeasurement"
+	imagePull                            = "Pull"
+	imagePullIncludingWaiting            = "PullIncludingWaiting"
+	// Quantiles are calculated per image size bucket
+	imageSizeGroup = "imageSize"
+)
+
+var (
+	supportedImagePullConditions = map[string]struct{}{
+		imagePull:                 {},
+		imagePullIncludingWaiting: {},
+	}
+	// Message of the Pulled events emitted by the kubelet, the image size is reported since Kubernetes 1.30
+	imagePulledRegex = regexp.MustCompile(`^Successfully pulled image "([^"]+)" in (\S+) \((\S+) including waiting\)(?:\. Image size: (\d+) bytes)?`)
+	// Image size buckets, by upper bound in bytes
+	imageSizeBuckets = []struct {
+		name  string
+		bound int64
+	}{
+		{"0-10Mi", 10 << 20},
+		{"10Mi-100Mi", 100 << 20},
+		{"100Mi-1Gi", 1 << 30},
+	}
+)
+
+type imagePullMetric struct {
+	Timestamp                   time.Time `json:"timestamp"`
+	podUID                      string
+	PullLatency                 int    `json:"pullLatency"`
+	PullIncludingWaitingLatency int    `json:"pullIncludingWaitingLatency"`
+	Image                       string `json:"image"`
+	Size                        int64  `json:"imageSize,omitempty"`
+	SizeBucket                  string `json:"sizeBucket"`
+	MetricName                  string `json:"metricName"`
+	UUID                        string `json:"uuid"`
+	JobName                     string `json:"jobName,omitempty"`
+	JobIteration                int    `json:"jobIteration"`
+	Replica                     int    `json:"replica"`
+	Namespace                   string `json:"namespace"`
+	Name                        string `json:"podName"`
+	NodeName                    string `json:"nodeName"`
+	Metadata                    any    `json:"metadata,omitempty"`
+}
+
+func (im imagePullMetric) group() string {
+	return im.SizeBucket
+}
+
+type imagePullLatency struct {
+	BaseMeasurement
+	// pods labels of the pods created by the benchmark, by UID
+	pods sync.Map
+}
+
+type imagePullLatencyMeasurementFactory struct {
+	BaseMeasurementFactory
+}
+
+func newImagePullLatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any) (MeasurementFactory, error) {
+	if err := verifyMeasurementConfig(measurement, supportedImagePullConditions); err != nil {
+		return nil, err
+	}
+	return imagePullLatencyMeasurementFactory{
+		BaseMeasurementFactory: NewBaseMeasurementFactory(configSpec, measurement, metadata),
+	}, nil
+}
+
+func (iplmf imagePullLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) Measurement {
+	ipl := &imagePullLatency{
+		BaseMeasurement: iplmf.NewBaseLatency(jobConfig, clientSet, restConfig, imagePullLatencyMeasurement, imagePullLatencyQuantilesMeasurement, embedCfg),
+	}
+	ipl.Config.GroupBy = imageSizeGroup
+	return ipl
+}
+
+// imageSizeBucket returns the bucket of the image size
+func imageSizeBucket(size int64) string {
+	if size == 0 {
+		return "unknown"
+	}
+	for _, bucket := range imageSizeBuckets {
+		if size < bucket.bound {
+			return bucket.name
+		}
+	}
+	return "1Gi+"
+}
+
+// handlePod tracks the pods created by the benchmark, image pull events don't carry their labels
+func (ipl *imagePullLatency) handlePod(obj any) {
+	pod := obj.(*corev1.Pod)
+	ipl.pods.Store(string(pod.UID), pod.Labels)
+}
+
+// handlePulledEvent records the latencies reported by the kubelet, images already present on the node are skipped
+func (ipl *imagePullLatency) handlePulledEvent(obj any) {
+	event := obj.(*corev1.Event)
+	match := imagePulledRegex.FindStringSubmatch(event.Message)
+	if match == nil {
+		return
+	}
+	pull, err := time.ParseDuration(match[2])
+	if err != nil {
+		log.Debugf("Invalid image pull duration in event %s: %v", event.Name, err)
+		return
+	}
+	pullIncludingWaiting, err := time.ParseDuration(match[3])
+	if err != nil {
+		log.Debugf("Invalid image pull duration in event %s: %v", event.Name, err)
+		return
+	}
+	var size int64
+	if match[4] != "" {
+		size, _ = strconv.ParseInt(match[4], 10, 64)
+	}
+	timestamp := event.EventTime.UTC()
+	if timestamp.IsZero() {
+		timestamp = event.FirstTimestamp.UTC()
+	}
+	ipl.metrics.LoadOrStore(string(event.UID), imagePullMetric{
+		Timestamp:                   timestamp,
+		podUID:                      string(event.InvolvedObject.UID),
+		PullLatency:                 int(pull.Milliseconds()),
+		PullIncludingWaitingLatency: int(pullIncludingWaiting.Milliseconds()),
+		Image:                       match[1],
+		Size:                        size,
+		SizeBucket:                  imageSizeBucket(size),
+		MetricName:                  imagePullLatencyMeasurement,
+		UUID:                        ipl.Uuid,
+		JobName:                     ipl.JobConfig.Name,
+		Namespace:                   event.InvolvedObject.Namespace,
+		Name:                        event.InvolvedObject.Name,
+		NodeName:                    event.Source.Host,
+		Metadata:                    ipl.Metadata,
+	})
+}
+
+// start imagePullLatency measurement
+func (ipl *imagePullLatency) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	ipl.pods = sync.Map{}
+	ipl.startMeasurement(
+		[]MeasurementWatcher{
+			{
+				restClient:    ipl.ClientSet.CoreV1().RESTClient().(*rest.RESTClient),
+				name:          "podWatcher",
+				resource:      "pods",
+				labelSelector: fmt.Sprintf("kube-burner-runid=%v", ipl.Runid),
+				handlers: &cache.ResourceEventHandlerFuncs{
+					AddFunc: ipl.handlePod,
+				},
+			},
+			{
+				restClient:    ipl.ClientSet.CoreV1().RESTClient().(*rest.RESTClient),
+				name:          "imagePullEventWatcher",
+				resource:      "events",
+				fieldSelector: "reason=Pulled,involvedObject.kind=Pod",
+				handlers: &cache.ResourceEventHandlerFuncs{
+					AddFunc: ipl.handlePulledEvent,
+					UpdateFunc: func(oldObj, newObj any) {
+						ipl.handlePulledEvent(newObj)
+					},
+				},
+			},
+		},
+	)
+	return nil
+}
+
+// collects image pull measurements triggered in the past
+func (ipl *imagePullLatency) Collect(measurementWg *sync.WaitGroup) error {
+	log.Info("Collect method doesn't apply to imagePullLatency by design")
+	defer measurementWg.Done()
+	return nil
+}
+
+// stop image pull latency measurement
+func (ipl *imagePullLatency) Stop() error {
+	return ipl.StopMeasurement(ipl.normalizeMetrics, ipl.getLatency)
+}
+
+// normalizeMetrics keeps the pulls of the pods created by the benchmark
+func (ipl *imagePullLatency) normalizeMetrics() float64 {
+	ipl.metrics.Range(func(key, value any) bool {
+		m := value.(imagePullMetric)
+		labels, ok := ipl.pods.Load(m.podUID)
+		if !ok {
+			ipl.metrics.Delete(key)
+			return true
+		}
+		podLabels := labels.(map[string]string)
+		m.JobIteration = getIntFromLabels(podLabels, config.KubeBurnerLabelJobIteration)
+		m.Replica = getIntFromLabels(podLabels, config.KubeBurnerLabelReplica)
+		ipl.normLatencies = append(ipl.normLatencies, m)
+		return true
+	})
+	return 0
+}
+
+func (ipl *imagePullLatency) getLatency(normLatency any) map[string]float64 {
+	imagePullMetric := normLatency.(imagePullMetric)
+	return map[string]float64{
+		imagePull:                 float64(imagePullMetric.PullLatency),
+		imagePullIncludingWaiting: float64(imagePullMetric.PullIncludingWaitingLatency),
+	}
+}
@@ -29,6 +29,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
@@ -116,6 +117,9 @@ func (j *jobLatency) handleUpdateJob(obj any) {
 				}
 			}
 			j.metrics.Store(string(job.UID), jm)
+			if !jm.jobComplete.IsZero() {
+				j.objectCompleted()
+			}
 		}
 	}
 }
@@ -123,6 +127,7 @@ func (j *jobLatency) handleUpdateJob(obj any) {
 // start jobLatency measurement
 func (j *jobLatency) Start(measurementWg *sync.WaitGroup) error {
 	defer measurementWg.Done()
+	j.prepareStream(j.normalizer, j.getLatency)
 	j.startMeasurement(
 		[]MeasurementWatcher{
 			{
@@ -143,9 +148,10 @@ func (j *jobLatency) Start(measurementWg *sync.WaitGroup) error {
 }
 
 // collects job measurements triggered in the past
-func (j *jobLatency) Collect(measurementWg *sync.WaitGroup) {
+func (j *jobLatency) Collect(measurementWg *sync.WaitGroup) error {
 	defer measurementWg.Done()
 	var jobs []batchv1.Job
+	var errs []error
 	labelSelector := labels.SelectorFromSet(j.JobConfig.NamespaceLabels)
 	options := metav1.ListOptions{
 		LabelSelector: labelSelector.String(),
@@ -154,7 +160,8 @@ func (j *jobLatency) Collect(measurementWg *sync.WaitGroup) {
 	for _, namespace := range namespaces {
 		jobList, err := j.ClientSet.BatchV1().Jobs(namespace).List(context.TODO(), options)
 		if err != nil {
-			log.Errorf("error listing jobs in namespace %s: %v", namespace, err)
+			errs = append(errs, fmt.Errorf("error listing jobs in namespace %s: %v", namespace, err))
+			continue
 		}
 		jobs = append(jobs, jobList.Items...)
 	}
@@ -179,6 +186,7 @@ func (j *jobLatency) Collect(measurementWg *sync.WaitGroup) {
 			JobName:     j.JobConfig.Name,
 		})
 	}
+	return utilerrors.NewAggregate(errs)
 }
 
 // Stop stops jobLatency measurement
@@ -191,12 +199,24 @@ func (j *jobLatency) GetMetrics() *sync.Map {
 }
 
 func (j *jobLatency) normalizeMetrics() float64 {
-	j.metrics.Range(func(key, value any) bool {
+	normalize := j.normalizer()
+	j.metrics.Range(func(_, value any) bool {
+		if m, _, ok := normalize(value); ok {
+			j.normLatencies = append(j.normLatencies, m)
+		}
+		return true
+	})
+	return 0
+}
+
+// normalizer returns the function calculating the latencies of a job, jobs that didn't complete are skipped
+func (j *jobLatency) normalizer() func(value any) (any, int, bool) {
+	return func(value any) (any, int, bool) {
 		m := value.(jobMetric)
 		// If a job does not reach the Complete state (this timestamp isn't set), we skip that job
 		if m.jobComplete.IsZero() {
 			log.Tracef("Job %v latency ignored as it did not reach Ready state", m.Name)
-			return true
+			return nil, 0, false
 		}
 		m.StartTimeLatency = int(m.startTime.Sub(m.Timestamp).Milliseconds())
 		if m.StartTimeLatency < 0 {
@@ -204,10 +224,8 @@ func (j *jobLatency) normalizeMetrics() float64 {
 			m.StartTimeLatency = 0
 		}
 		m.CompletionLatency = int(m.jobComplete.Sub(m.Timestamp).Milliseconds())
-		j.normLatencies = append(j.normLatencies, m)
-		return true
-	})
-	return 0
+		return m, 0, true
+	}
 }
 
 func (j *jobLatency) getLatency(normLatency any) map[string]float64 {
@@ -0,0 +1,329 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	kubeletNodeStatsMeasurement = "kubeletNodeStatsMeasurement"
+	kubeletPodStatsMeasurement  = "kubeletPodStatsMeasurement"
+	defaultKubeletStatsInterval = 15 * time.Second
+	// Maximum number of kubelets scraped concurrently
+	kubeletStatsConcurrency = 20
+)
+
+// kubeletNodeStatsMetric resource usage of a node, sampled periodically
+type kubeletNodeStatsMetric struct {
+	Timestamp  time.Time `json:"timestamp"`
+	MetricName string    `json:"metricName"`
+	UUID       string    `json:"uuid"`
+	JobName    string    `json:"jobName,omitempty"`
+	NodeName   string    `json:"nodeName"`
+	// CPUUsage cores used by the node
+	CPUUsage         float64 `json:"cpuUsage"`
+	MemoryWorkingSet uint64  `json:"memoryWorkingSet"`
+	MemoryRSS        uint64  `json:"memoryRSS"`
+	MemoryAvailable  uint64  `json:"memoryAvailable"`
+	// Pods running on the node
+	Pods     int `json:"pods"`
+	Metadata any `json:"metadata,omitempty"`
+}
+
+// kubeletPodStatsMetric resource usage of a pod of the job, sampled periodically
+type kubeletPodStatsMetric struct {
+	Timestamp  time.Time `json:"timestamp"`
+	MetricName string    `json:"metricName"`
+	UUID       string    `json:"uuid"`
+	JobName    string    `json:"jobName,omitempty"`
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"podName"`
+	NodeName   string    `json:"nodeName"`
+	Containers int       `json:"containers"`
+	// CPUUsage cores used by the pod containers
+	CPUUsage         float64 `json:"cpuUsage"`
+	MemoryWorkingSet uint64  `json:"memoryWorkingSet"`
+	MemoryRSS        uint64  `json:"memoryRSS"`
+	Metadata         any     `json:"metadata,omitempty"`
+}
+
+// Kubelet Summary API types, only the fields used
+type kubeletCPUStats struct {
+	UsageNanoCores *uint64 `json:"usageNanoCores"`
+}
+
+type kubeletMemoryStats struct {
+	AvailableBytes  *uint64 `json:"availableBytes"`
+	WorkingSetBytes *uint64 `json:"workingSetBytes"`
+	RSSBytes        *uint64 `json:"rssBytes"`
+}
+
+type kubeletSummary struct {
+	Node struct {
+		NodeName string              `json:"nodeName"`
+		CPU      *kubeletCPUStats    `json:"cpu"`
+		Memory   *kubeletMemoryStats `json:"memory"`
+	} `json:"node"`
+	Pods []struct {
+		PodRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+			UID       string `json:"uid"`
+		} `json:"podRef"`
+		Containers []struct {
+			Name string `json:"name"`
+		} `json:"containers"`
+		CPU    *kubeletCPUStats    `json:"cpu"`
+		Memory *kubeletMemoryStats `json:"memory"`
+	} `json:"pods"`
+}
+
+type kubeletStats struct {
+	BaseMeasurement
+	mu          sync.Mutex
+	nodeSamples []any
+	podSamples  []any
+	stopCh      chan struct{}
+	doneCh      chan struct{}
+}
+
+type kubeletStatsMeasurementFactory struct {
+	BaseMeasurementFactory
+}
+
+func newKubeletStatsMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any) (MeasurementFactory, error) {
+	if measurement.KubeletStatsInterval <= 0 {
+		measurement.KubeletStatsInterval = defaultKubeletStatsInterval
+	}
+	if _, err := labels.Parse(measurement.KubeletStatsNodeSelector); err != nil {
+		return nil, fmt.Errorf("invalid kubeletStatsNodeSelector %q: %v", measurement.KubeletStatsNodeSelector, err)
+	}
+	return kubeletStatsMeasurementFactory{
+		BaseMeasurementFactory: NewBaseMeasurementFactory(configSpec, measurement, metadata),
+	}, nil
+}
+
+func (kmf kubeletStatsMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) Measurement {
+	return &kubeletStats{
+		BaseMeasurement: kmf.NewBaseLatency(jobConfig, clientSet, restConfig, kubeletNodeStatsMeasurement, "", embedCfg),
+	}
+}
+
+// Start starts sampling the kubelets
+func (k *kubeletStats) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	// Reset samples, required in multi-job benchmarks
+	k.nodeSamples, k.podSamples = nil, nil
+	k.stopCh = make(chan struct{})
+	k.doneCh = make(chan struct{})
+	go k.run()
+	return nil
+}
+
+func (k *kubeletStats) run() {
+	defer close(k.doneCh)
+	k.sample()
+	ticker := time.NewTicker(k.Config.KubeletStatsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-k.stopCh:
+			return
+		case <-ticker.C:
+			k.sample()
+		}
+	}
+}
+
+// Collect is not supported by this measurement
+func (k *kubeletStats) Collect(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	return nil
+}
+
+// Stop takes a last sample and summarizes the peak usage of the nodes and the job pods
+func (k *kubeletStats) Stop() error {
+	if k.stopCh == nil {
+		return nil
+	}
+	close(k.stopCh)
+	<-k.doneCh
+	k.stopCh = nil
+	k.sample()
+	if len(k.nodeSamples) == 0 {
+		log.Warnf("No kubelet stats collected for job %s", k.JobConfig.Name)
+		return nil
+	}
+	var maxNodeCPU, maxPodCPU float64
+	var maxNodeWorkingSet, maxPodWorkingSet uint64
+	for _, s := range k.nodeSamples {
+		m := s.(kubeletNodeStatsMetric)
+		maxNodeCPU = math.Max(maxNodeCPU, m.CPUUsage)
+		maxNodeWorkingSet = max(maxNodeWorkingSet, m.MemoryWorkingSet)
+	}
+	for _, s := range k.podSamples {
+		m := s.(kubeletPodStatsMetric)
+		maxPodCPU = math.Max(maxPodCPU, m.CPUUsage)
+		maxPodWorkingSet = max(maxPodWorkingSet, m.MemoryWorkingSet)
+	}
+	log.Infof("%s: kubelet stats max node CPU usage: %.3f cores max node memory working set: %d bytes", k.JobConfig.Name, maxNodeCPU, maxNodeWorkingSet)
+	log.Infof("%s: kubelet stats max pod CPU usage: %.3f cores max pod memory working set: %d bytes", k.JobConfig.Name, maxPodCPU, maxPodWorkingSet)
+	return nil
+}
+
+// Index indexes the node and pod samples
+func (k *kubeletStats) Index(jobName string, indexerList map[string]indexers.Indexer) error {
+	metricMap := map[string][]any{
+		kubeletNodeStatsMeasurement: k.nodeSamples,
+		kubeletPodStatsMeasurement:  k.podSamples,
+	}
+	return k.indexLatencyMeasurement(jobName, metricMap, indexerList)
+}
+
+// sample scrapes the Summary API of the selected kubelets through the API server proxy
+func (k *kubeletStats) sample() {
+	ctx, cancel := context.WithTimeout(context.Background(), k.Config.KubeletStatsInterval)
+	defer cancel()
+	nodes, err := k.ClientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: k.Config.KubeletStatsNodeSelector})
+	if err != nil {
+		log.Errorf("Error listing nodes: %v", err)
+		return
+	}
+	pods, err := k.ClientSet.CoreV1().Pods(corev1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("kube-burner-runid=%v", k.Runid),
+		FieldSelector: "status.phase=Running",
+	})
+	if err != nil {
+		log.Errorf("Error listing pods: %v", err)
+		return
+	}
+	jobPods := make(map[string]struct{}, len(pods.Items))
+	for _, pod := range pods.Items {
+		jobPods[string(pod.UID)] = struct{}{}
+	}
+	now := time.Now().UTC()
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, kubeletStatsConcurrency)
+	for _, node := range nodes.Items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(nodeName string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			summary, err := k.summary(ctx, nodeName)
+			if err != nil {
+				log.Warnf("Error sampling kubelet stats on node %s: %v", nodeName, err)
+				return
+			}
+			k.record(now, nodeName, summary, jobPods)
+		}(node.Name)
+	}
+	wg.Wait()
+}
+
+// summary returns the stats of the kubelet of the node
+func (k *kubeletStats) summary(ctx context.Context, nodeName string) (*kubeletSummary, error) {
+	raw, err := k.ClientSet.CoreV1().RESTClient().
+		Get().
+		Resource("nodes").
+		Name(nodeName).
+		SubResource("proxy").
+		Suffix("stats", "summary").
+		Do(ctx).
+		Raw()
+	if err != nil {
+		return nil, err
+	}
+	var summary kubeletSummary
+	if err := json.Unmarshal(raw, &summary); err != nil {
+		return nil, fmt.Errorf("error decoding stats summary: %v", err)
+	}
+	return &summary, nil
+}
+
+// record appends the node sample and the samples of the job pods running on it
+func (k *kubeletStats) record(timestamp time.Time, nodeName string, summary *kubeletSummary, jobPods map[string]struct{}) {
+	nodeSample := kubeletNodeStatsMetric{
+		Timestamp:  timestamp,
+		MetricName: kubeletNodeStatsMeasurement,
+		UUID:       k.Uuid,
+		JobName:    k.JobConfig.Name,
+		NodeName:   nodeName,
+		Pods:       len(summary.Pods),
+		Metadata:   k.Metadata,
+	}
+	nodeSample.CPUUsage = nanoCores(summary.Node.CPU)
+	nodeSample.MemoryWorkingSet, nodeSample.MemoryRSS, nodeSample.MemoryAvailable = memoryBytes(summary.Node.Memory)
+	var podSamples []any
+	for _, pod := range summary.Pods {
+		if _, ok := jobPods[pod.PodRef.UID]; !ok {
+			continue
+		}
+		podSample := kubeletPodStatsMetric{
+			Timestamp:  timestamp,
+			MetricName: kubeletPodStatsMeasurement,
+			UUID:       k.Uuid,
+			JobName:    k.JobConfig.Name,
+			Namespace:  pod.PodRef.Namespace,
+			Name:       pod.PodRef.Name,
+			NodeName:   nodeName,
+			Containers: len(pod.Containers),
+			CPUUsage:   nanoCores(pod.CPU),
+			Metadata:   k.Metadata,
+		}
+		podSample.MemoryWorkingSet, podSample.MemoryRSS, _ = memoryBytes(pod.Memory)
+		podSamples = append(podSamples, podSample)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.nodeSamples = append(k.nodeSamples, nodeSample)
+	k.podSamples = append(k.podSamples, podSamples...)
+}
+
+// nanoCores converts the CPU usage reported by the kubelet to cores
+func nanoCores(cpu *kubeletCPUStats) float64 {
+	if cpu == nil || cpu.UsageNanoCores == nil {
+		return 0
+	}
+	return math.Round(float64(*cpu.UsageNanoCores)/1e6) / 1000
+}
+
+// memoryBytes returns the working set, RSS and available memory reported by the kubelet
+func memoryBytes(memory *kubeletMemoryStats) (workingSet, rss, available uint64) {
+	if memory == nil {
+		return 0, 0, 0
+	}
+	return ptr.Deref(memory.WorkingSetBytes, 0), ptr.Deref(memory.RSSBytes, 0), ptr.Deref(memory.AvailableBytes, 0)
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+)
+
+const (
+	// Resamples of the bootstrap confidence intervals
+	bootstrapResamples = 200
+	// Confidence level of the intervals
+	confidenceLevel = 0.95
+)
+
+// ConfidenceInterval bootstrap confidence interval of a percentile
+type ConfidenceInterval struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+// Percentiles reported by the quantile documents
+var reportedPercentiles = []float64{50, 95, 99}
+
+// PercentileIntervals returns the bootstrap confidence intervals of the 50th, 95th and 99th percentiles of the
+// given values, nil when there are fewer than two values. The seed is fixed, so that the intervals of the same
+// values don't change from one run to another
+func PercentileIntervals(input []float64) []*ConfidenceInterval {
+	n := len(input)
+	if n < 2 {
+		return make([]*ConfidenceInterval, len(reportedPercentiles))
+	}
+	sorted := slices.Sorted(slices.Values(input))
+	// Ranks of the values each percentile is calculated from, in ascending order
+	var ranks []int
+	for _, percent := range reportedPercentiles {
+		lower, upper := percentileRanks(n, percent)
+		ranks = append(ranks, lower, upper)
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	// A resample is represented by the number of times each value is drawn, so the values at the given ranks are
+	// found with a single pass over the sorted values instead of sorting every resample
+	counts := make([]int32, n)
+	estimates := make([][]float64, len(reportedPercentiles))
+	values := make([]float64, len(ranks))
+	for range bootstrapResamples {
+		clear(counts)
+		for range n {
+			counts[rng.IntN(n)]++
+		}
+		cumulative, idx := 0, 0
+		for i, count := range counts {
+			cumulative += int(count)
+			for idx < len(ranks) && ranks[idx] < cumulative {
+				values[idx] = sorted[i]
+				idx++
+			}
+		}
+		for p := range reportedPercentiles {
+			estimates[p] = append(estimates[p], (values[2*p]+values[2*p+1])/2)
+		}
+	}
+	intervals := make([]*ConfidenceInterval, len(reportedPercentiles))
+	tail := (1 - confidenceLevel) / 2
+	for p, estimate := range estimates {
+		slices.Sort(estimate)
+		intervals[p] = &ConfidenceInterval{
+			Lower: estimate[int(tail*bootstrapResamples)],
+			Upper: estimate[int((1-tail)*bootstrapResamples)-1],
+		}
+	}
+	return intervals
+}
+
+// percentileRanks returns the ranks, 0-based, of the values averaged to calculate a percentile, in the same way as
+// stats.Percentile
+func percentileRanks(n int, percent float64) (int, int) {
+	index := percent / 100 * float64(n)
+	i := int(index)
+	switch {
+	case index == float64(i):
+		return max(i-1, 0), max(i-1, 0)
+	case index > 1:
+		return i - 1, i
+	default:
+		return 0, 0
+	}
+}
+
+// lowSamplesWarning returns the percentiles that the given number of samples is too low for, a percentile requires
+// at least one sample above it, like 100 samples for the 99th percentile. Empty when there are enough samples
+func lowSamplesWarning(samples int) string {
+	var percentiles []string
+	for _, percent := range reportedPercentiles {
+		if float64(samples)*(100-percent)/100 < 1 {
+			percentiles = append(percentiles, fmt.Sprintf("P%v (%d required)", percent, int(100/(100-percent))))
+		}
+	}
+	return strings.Join(percentiles, ", ")
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+)
+
+// LatencyHistogram holds the latency histogram of a condition. Buckets only depend on the configuration, so that the
+// histograms of different runs can be merged adding up the counts of their buckets
+type LatencyHistogram struct {
+	QuantileName string            `json:"quantileName"`
+	Group        string            `json:"group,omitempty"`
+	UUID         string            `json:"uuid"`
+	Count        int               `json:"count"`
+	Sum          float64           `json:"sum"`
+	Buckets      []HistogramBucket `json:"buckets"`
+	Timestamp    time.Time         `json:"timestamp"`
+	MetricName   string            `json:"metricName"`
+	JobName      string            `json:"jobName,omitempty"`
+	Metadata     any               `json:"metadata,omitempty"`
+}
+
+// HistogramBucket latencies from Lower, included, to Upper, excluded, in milliseconds. The overflow bucket has no
+// upper bound
+type HistogramBucket struct {
+	Lower float64  `json:"lower"`
+	Upper *float64 `json:"upper"`
+	Count int      `json:"count"`
+}
+
+// NewLatencyHistogram returns the histogram of the given latencies, in milliseconds, empty buckets are left out
+func NewLatencyHistogram(input []float64, name string, spec types.Histogram) LatencyHistogram {
+	histogram := LatencyHistogram{
+		QuantileName: name,
+		Count:        len(input),
+		Timestamp:    time.Now().UTC(),
+	}
+	buckets := map[float64]*HistogramBucket{}
+	for _, latency := range input {
+		histogram.Sum += latency
+		lower, upper := histogramBucket(latency, spec)
+		if bucket, ok := buckets[lower]; ok {
+			bucket.Count++
+			continue
+		}
+		bucket := &HistogramBucket{Lower: lower, Count: 1}
+		if !math.IsInf(upper, 1) {
+			bucket.Upper = &upper
+		}
+		buckets[lower] = bucket
+	}
+	for _, lower := range slices.Sorted(maps.Keys(buckets)) {
+		histogram.Buckets = append(histogram.Buckets, *buckets[lower])
+	}
+	return histogram
+}
+
+// histogramBucket returns the bounds of the bucket of the given latency, the upper bound of the overflow bucket is +Inf
+func histogramBucket(latency float64, spec types.Histogram) (float64, float64) {
+	if len(spec.Buckets) > 0 {
+		idx := sort.Search(len(spec.Buckets), func(i int) bool {
+			return durationMs(spec.Buckets[i]) > latency
+		})
+		switch idx {
+		case 0:
+			return 0, durationMs(spec.Buckets[0])
+		case len(spec.Buckets):
+			return durationMs(spec.Buckets[idx-1]), math.Inf(1)
+		default:
+			return durationMs(spec.Buckets[idx-1]), durationMs(spec.Buckets[idx])
+		}
+	}
+	minMs, maxMs := durationMs(spec.Min), durationMs(spec.Max)
+	if latency < minMs {
+		return 0, minMs
+	}
+	if latency >= maxMs {
+		return maxMs, math.Inf(1)
+	}
+	// Buckets of each power of ten are as wide as the unit of its last significant figure
+	width := math.Pow(10, math.Floor(math.Log10(latency))-float64(spec.SignificantFigures-1))
+	lower := roundBound(math.Floor(latency/width+1e-9) * width)
+	return max(lower, minMs), min(roundBound(lower+width), maxMs)
+}
+
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// roundBound removes the floating point error of the bucket bounds
+func roundBound(bound float64) float64 {
+	return math.Round(bound*1e6) / 1e6
+}
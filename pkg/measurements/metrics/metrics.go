@@ -16,10 +16,12 @@ package metrics
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"time"
 
 	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/pkg/util/junit"
 	"github.com/montanaflynn/stats"
 	log "github.com/sirupsen/logrus"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -27,18 +29,27 @@ import (
 
 // LatencyQuantiles holds the latency measurement quantiles
 type LatencyQuantiles struct {
-	QuantileName string    `json:"quantileName"`
-	UUID         string    `json:"uuid"`
-	P99          int       `json:"P99"`
-	P95          int       `json:"P95"`
-	P50          int       `json:"P50"`
-	Min          int       `json:"min"`
-	Max          int       `json:"max"`
-	Avg          int       `json:"avg"`
-	Timestamp    time.Time `json:"timestamp"`
-	MetricName   string    `json:"metricName"`
-	JobName      string    `json:"jobName,omitempty"`
-	Metadata     any       `json:"metadata,omitempty"`
+	QuantileName string              `json:"quantileName"`
+	Group        string              `json:"group,omitempty"`
+	UUID         string              `json:"uuid"`
+	P99          int                 `json:"P99"`
+	P95          int                 `json:"P95"`
+	P50          int                 `json:"P50"`
+	Min          int                 `json:"min"`
+	Max          int                 `json:"max"`
+	Avg          int                 `json:"avg"`
+	Samples      int                 `json:"samples"`
+	StdDev       int                 `json:"stdDev"`
+	P99CI        *ConfidenceInterval `json:"P99CI,omitempty"`
+	P95CI        *ConfidenceInterval `json:"P95CI,omitempty"`
+	P50CI        *ConfidenceInterval `json:"P50CI,omitempty"`
+	Timestamp    time.Time           `json:"timestamp"`
+	MetricName   string              `json:"metricName"`
+	JobName      string              `json:"jobName,omitempty"`
+	Metadata     any                 `json:"metadata,omitempty"`
+	// Latencies the quantiles were calculated from, only kept by the worker processes so that the coordinator
+	// calculates the quantiles of the whole benchmark
+	Latencies []float64 `json:"latencies,omitempty"`
 }
 
 // CheckThreshold checks latency thresholds
@@ -48,21 +59,36 @@ func CheckThreshold(thresholds []types.LatencyThreshold, quantiles []any) error
 	log.Info("Evaluating latency thresholds")
 	for _, phase := range thresholds {
 		for _, pq := range quantiles {
+			// Thresholds are only evaluated against global quantiles
+			if pq.(LatencyQuantiles).Group != "" {
+				continue
+			}
 			if phase.ConditionType == pq.(LatencyQuantiles).QuantileName {
 				// Required to access the attribute by name
 				r := reflect.ValueOf(pq.(LatencyQuantiles))
 				v := r.FieldByName(phase.Metric).Int()
+				var err error
 				if v > phase.Threshold.Milliseconds() {
 					latency := float32(v) / 1000
-					err := fmt.Errorf("podLatency: %s %s latency (%.2fs) higher than configured threshold: %v", phase.Metric, phase.ConditionType, latency, phase.Threshold)
+					err = fmt.Errorf("podLatency: %s %s latency (%.2fs) higher than configured threshold: %v", phase.Metric, phase.ConditionType, latency, phase.Threshold)
 					errs = append(errs, err)
 				}
+				testCase := fmt.Sprintf("%s %s %s %s <= %v", pq.(LatencyQuantiles).JobName, pq.(LatencyQuantiles).MetricName, phase.Metric, phase.ConditionType, phase.Threshold)
+				junit.AddTestCase(junit.SuiteThresholds, testCase, 0, err)
 			}
 		}
 	}
 	return utilerrors.NewAggregate(errs)
 }
 
+// truncateInterval truncates the bounds to whole milliseconds, like the percentiles
+func truncateInterval(interval *ConfidenceInterval) *ConfidenceInterval {
+	if interval == nil {
+		return nil
+	}
+	return &ConfidenceInterval{Lower: math.Trunc(interval.Lower), Upper: math.Trunc(interval.Upper)}
+}
+
 func NewLatencySummary(input []float64, name string) LatencyQuantiles {
 	latencyQuantiles := LatencyQuantiles{
 		QuantileName: name,
@@ -80,5 +106,13 @@ func NewLatencySummary(input []float64, name string) LatencyQuantiles {
 	latencyQuantiles.Max = int(val)
 	val, _ = stats.Mean(input)
 	latencyQuantiles.Avg = int(val)
+	val, _ = stats.StandardDeviation(input)
+	latencyQuantiles.StdDev = int(val)
+	latencyQuantiles.Samples = len(input)
+	intervals := PercentileIntervals(input)
+	latencyQuantiles.P50CI, latencyQuantiles.P95CI, latencyQuantiles.P99CI = truncateInterval(intervals[0]), truncateInterval(intervals[1]), truncateInterval(intervals[2])
+	if warning := lowSamplesWarning(len(input)); warning != "" {
+		log.Warnf("%s: %d samples are too few for a meaningful %s", name, len(input), warning)
+	}
 	return latencyQuantiles
 }
@@ -552,6 +552,7 @@ func (n *netpolLatency) normalizeMetrics() {
 		latencySummary.Metadata = n.Metadata
 		latencySummary.MetricName = netpolLatencyQuantilesMeasurement
 		latencySummary.JobName = n.JobConfig.Name
+		latencySummary.Latencies = n.workerLatencies(inputLatencies)
 		return latencySummary
 	}
 	if sLen > 0 {
@@ -560,6 +561,7 @@ func (n *netpolLatency) normalizeMetrics() {
 	}
 }
 
-func (n *netpolLatency) Collect(measurementWg *sync.WaitGroup) {
+func (n *netpolLatency) Collect(measurementWg *sync.WaitGroup) error {
 	defer measurementWg.Done()
+	return nil
 }
@@ -16,6 +16,7 @@ package measurements
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -103,6 +104,7 @@ func (n *nodeLatency) handleUpdateNode(obj any) {
 	node := obj.(*corev1.Node)
 	if value, exists := n.metrics.Load(string(node.UID)); exists {
 		nm := value.(NodeMetric)
+		wasReady := !nm.NodeReady.IsZero()
 		for _, c := range node.Status.Conditions {
 			switch c.Type {
 			case corev1.NodeMemoryPressure:
@@ -125,6 +127,9 @@ func (n *nodeLatency) handleUpdateNode(obj any) {
 			}
 		}
 		n.metrics.Store(string(node.UID), nm)
+		if !wasReady && !nm.NodeReady.IsZero() {
+			n.objectCompleted()
+		}
 	}
 }
 
@@ -135,6 +140,7 @@ func (n *nodeLatency) Start(measurementWg *sync.WaitGroup) error {
 	wg.Add(1)
 	n.Collect(&wg)
 	wg.Wait()
+	n.prepareStream(n.normalizer, n.getLatency)
 	n.startMeasurement(
 		[]MeasurementWatcher{
 			{
@@ -154,14 +160,13 @@ func (n *nodeLatency) Start(measurementWg *sync.WaitGroup) error {
 	return nil
 }
 
-func (n *nodeLatency) Collect(measurementWg *sync.WaitGroup) {
+func (n *nodeLatency) Collect(measurementWg *sync.WaitGroup) error {
 	defer measurementWg.Done()
-	var nodes []corev1.Node
 	nodeList, err := n.ClientSet.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
-		log.Errorf("error listing nodes: %v", err)
+		return fmt.Errorf("error listing nodes: %v", err)
 	}
-	nodes = append(nodes, nodeList.Items...)
+	nodes := nodeList.Items
 
 	n.metrics = sync.Map{}
 	for _, node := range nodes {
@@ -191,6 +196,7 @@ func (n *nodeLatency) Collect(measurementWg *sync.WaitGroup) {
 			Labels:             node.Labels,
 		})
 	}
+	return nil
 }
 
 func (n *nodeLatency) Stop() error {
@@ -198,12 +204,24 @@ func (n *nodeLatency) Stop() error {
 }
 
 func (n *nodeLatency) normalizeLatencies() float64 {
-	n.metrics.Range(func(key, value any) bool {
+	normalize := n.normalizer()
+	n.metrics.Range(func(_, value any) bool {
+		if m, _, ok := normalize(value); ok {
+			n.normLatencies = append(n.normLatencies, m)
+		}
+		return true
+	})
+	return 0
+}
+
+// normalizer returns the function calculating the latencies of a node, nodes that aren't ready are skipped
+func (n *nodeLatency) normalizer() func(value any) (any, int, bool) {
+	return func(value any) (any, int, bool) {
 		m := value.(NodeMetric)
 		// If a node does not reach the Ready state, we skip that node
 		if m.NodeReady.IsZero() {
 			log.Tracef("Node %v latency ignored as it did not reach Ready state", m.Name)
-			return true
+			return nil, 0, false
 		}
 		earliest := m.Timestamp
 		if m.NodeMemoryPressure.Before(earliest) {
@@ -219,10 +237,8 @@ func (n *nodeLatency) normalizeLatencies() float64 {
 		m.NodeDiskPressureLatency = int(m.NodeDiskPressure.Sub(earliest).Milliseconds())
 		m.NodePIDPressureLatency = int(m.NodePIDPressure.Sub(earliest).Milliseconds())
 		m.NodeReadyLatency = int(m.NodeReady.Sub(earliest).Milliseconds())
-		n.normLatencies = append(n.normLatencies, m)
-		return true
-	})
-	return 0
+		return m, 0, true
+	}
 }
 
 func (n *nodeLatency) getLatency(normLatency any) map[string]float64 {
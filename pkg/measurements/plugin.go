@@ -0,0 +1,216 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const defaultPluginStopTimeout = time.Minute
+
+// Environment variables describing the job to the plugins
+const (
+	pluginEnvUUID        = "KUBE_BURNER_UUID"
+	pluginEnvRunID       = "KUBE_BURNER_RUNID"
+	pluginEnvJob         = "KUBE_BURNER_JOB"
+	pluginEnvMeasurement = "KUBE_BURNER_MEASUREMENT"
+	pluginEnvAPIServer   = "KUBE_BURNER_API_SERVER"
+)
+
+// plugin measurement implemented by an external executable, started along with the job and stopped with SIGTERM once
+// it finishes, the JSON documents it writes to its standard output are indexed
+type plugin struct {
+	BaseMeasurement
+	cmd    *exec.Cmd
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+	// doneCh receives the result of the process once it exits
+	doneCh    chan error
+	start     time.Time
+	documents map[string][]any
+}
+
+type pluginMeasurementFactory struct {
+	BaseMeasurementFactory
+}
+
+func newPluginMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any) (MeasurementFactory, error) {
+	if len(measurement.Plugin.Command) == 0 {
+		return nil, fmt.Errorf("measurement %s: plugin.command is required", measurement.Name)
+	}
+	if _, err := exec.LookPath(measurement.Plugin.Command[0]); err != nil {
+		return nil, fmt.Errorf("measurement %s: plugin executable not found: %v", measurement.Name, err)
+	}
+	if measurement.Plugin.StopTimeout <= 0 {
+		measurement.Plugin.StopTimeout = defaultPluginStopTimeout
+	}
+	return pluginMeasurementFactory{
+		BaseMeasurementFactory: NewBaseMeasurementFactory(configSpec, measurement, metadata),
+	}, nil
+}
+
+func (pmf pluginMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) Measurement {
+	return &plugin{
+		BaseMeasurement: pmf.NewBaseLatency(jobConfig, clientSet, restConfig, pmf.Config.Name, "", embedCfg),
+	}
+}
+
+// Start runs the plugin, passing its configuration through its standard input
+func (p *plugin) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	p.documents = nil
+	p.stdout.Reset()
+	p.stderr.Reset()
+	pluginConfig, err := json.Marshal(p.Config.Plugin.Config)
+	if err != nil {
+		return fmt.Errorf("error encoding the configuration of plugin %s: %v", p.Config.Name, err)
+	}
+	p.cmd = exec.Command(p.Config.Plugin.Command[0], p.Config.Plugin.Command[1:]...)
+	p.cmd.Env = append(os.Environ(),
+		pluginEnvUUID+"="+p.Uuid,
+		pluginEnvRunID+"="+p.Runid,
+		pluginEnvJob+"="+p.JobConfig.Name,
+		pluginEnvMeasurement+"="+p.Config.Name,
+	)
+	if p.RestConfig != nil {
+		p.cmd.Env = append(p.cmd.Env, pluginEnvAPIServer+"="+p.RestConfig.Host)
+	}
+	for name, value := range p.Config.Plugin.Env {
+		p.cmd.Env = append(p.cmd.Env, name+"="+value)
+	}
+	p.cmd.Stdin = bytes.NewReader(pluginConfig)
+	p.cmd.Stdout = &p.stdout
+	p.cmd.Stderr = &p.stderr
+	p.start = time.Now().UTC()
+	if err := p.cmd.Start(); err != nil {
+		p.cmd = nil
+		return fmt.Errorf("error starting plugin %s: %v", p.Config.Name, err)
+	}
+	log.Infof("Started plugin %s for job %s with PID %d", p.Config.Name, p.JobConfig.Name, p.cmd.Process.Pid)
+	p.doneCh = make(chan error, 1)
+	go func() {
+		p.doneCh <- p.cmd.Wait()
+	}()
+	return nil
+}
+
+// Stop sends SIGTERM to the plugin, unless it already exited, and reads the documents it wrote once it exits. The
+// plugin is killed when it doesn't exit within the stop timeout
+func (p *plugin) Stop() error {
+	if p.cmd == nil {
+		return nil
+	}
+	defer func() { p.cmd = nil }()
+	var err error
+	select {
+	case err = <-p.doneCh:
+	default:
+		if signalErr := p.cmd.Process.Signal(syscall.SIGTERM); signalErr != nil && !errors.Is(signalErr, os.ErrProcessDone) {
+			log.Warnf("Error stopping plugin %s: %v", p.Config.Name, signalErr)
+		}
+		select {
+		case err = <-p.doneCh:
+		case <-time.After(p.Config.Plugin.StopTimeout):
+			p.cmd.Process.Kill()
+			<-p.doneCh
+			return fmt.Errorf("plugin %s didn't exit within %v after being stopped", p.Config.Name, p.Config.Plugin.StopTimeout)
+		}
+	}
+	if stderr := strings.TrimSpace(p.stderr.String()); stderr != "" {
+		log.Debugf("Plugin %s: %s", p.Config.Name, stderr)
+	}
+	documents, parseErr := p.parseDocuments()
+	p.documents = documents
+	if err != nil {
+		return fmt.Errorf("plugin %s failed: %v", p.Config.Name, err)
+	}
+	return parseErr
+}
+
+// parseDocuments parses the JSON objects, or arrays of them, written by the plugin, by metric name. The run UUID, job
+// name and metadata are added, along with the metric name and timestamp when missing
+func (p *plugin) parseDocuments() (map[string][]any, error) {
+	documents := make(map[string][]any)
+	addDocument := func(document map[string]any) {
+		document["uuid"] = p.Uuid
+		document["jobName"] = p.JobConfig.Name
+		document["metadata"] = p.Metadata
+		metricName, _ := document["metricName"].(string)
+		if metricName == "" {
+			metricName = p.Config.Name
+			document["metricName"] = metricName
+		}
+		if _, ok := document["timestamp"]; !ok {
+			document["timestamp"] = p.start
+		}
+		documents[metricName] = append(documents[metricName], document)
+	}
+	decoder := json.NewDecoder(&p.stdout)
+	for {
+		var value any
+		if err := decoder.Decode(&value); err == io.EOF {
+			break
+		} else if err != nil {
+			return documents, fmt.Errorf("error decoding the output of plugin %s: %v", p.Config.Name, err)
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			addDocument(v)
+		case []any:
+			for _, item := range v {
+				document, ok := item.(map[string]any)
+				if !ok {
+					return documents, fmt.Errorf("plugin %s wrote a document that isn't a JSON object", p.Config.Name)
+				}
+				addDocument(document)
+			}
+		default:
+			return documents, fmt.Errorf("plugin %s wrote a document that isn't a JSON object", p.Config.Name)
+		}
+	}
+	return documents, nil
+}
+
+// Collect is not supported by this measurement
+func (p *plugin) Collect(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	return nil
+}
+
+// Index sends the documents of the plugin to the timeseries indexer, or to all of them when not set
+func (p *plugin) Index(jobName string, indexerList map[string]indexers.Indexer) error {
+	if p.Config.TimeseriesIndexer != "" {
+		indexerList = map[string]indexers.Indexer{p.Config.TimeseriesIndexer: indexerList[p.Config.TimeseriesIndexer]}
+	}
+	return p.indexLatencyMeasurement(jobName, p.documents, indexerList)
+}
@@ -21,6 +21,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
 	"github.com/kube-burner/kube-burner/pkg/config"
 	"github.com/kube-burner/kube-burner/pkg/measurements/types"
 	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
@@ -28,6 +29,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
@@ -58,20 +61,30 @@ type podMetric struct {
 	podReady                      time.Time
 	PodReadyLatency               int `json:"podReadyLatency"`
 	readyToStartContainers        time.Time
-	ReadyToStartContainersLatency int    `json:"readyToStartContainersLatency"`
-	MetricName                    string `json:"metricName"`
-	UUID                          string `json:"uuid"`
-	JobName                       string `json:"jobName,omitempty"`
-	JobIteration                  int    `json:"jobIteration"`
-	Replica                       int    `json:"replica"`
-	Namespace                     string `json:"namespace"`
-	Name                          string `json:"podName"`
-	NodeName                      string `json:"nodeName"`
-	Metadata                      any    `json:"metadata,omitempty"`
+	ReadyToStartContainersLatency int      `json:"readyToStartContainersLatency"`
+	MetricName                    string   `json:"metricName"`
+	UUID                          string   `json:"uuid"`
+	JobName                       string   `json:"jobName,omitempty"`
+	JobIteration                  int      `json:"jobIteration"`
+	Replica                       int      `json:"replica"`
+	Namespace                     string   `json:"namespace"`
+	Name                          string   `json:"podName"`
+	NodeName                      string   `json:"nodeName"`
+	ClockOffset                   int      `json:"clockOffset,omitempty"`
+	ClockSkewed                   bool     `json:"clockSkewed,omitempty"`
+	Group                         string   `json:"group,omitempty"`
+	Disruptions                   []string `json:"disruptions,omitempty"`
+	Metadata                      any      `json:"metadata,omitempty"`
+}
+
+func (pm podMetric) group() string {
+	return pm.Group
 }
 
 type podLatency struct {
 	BaseMeasurement
+	timelines podTimelines
+	skew      *clockSkewEstimator
 }
 
 type podLatencyMeasurementFactory struct {
@@ -82,6 +95,14 @@ func newPodLatencyMeasurementFactory(configSpec config.Spec, measurement types.M
 	if err := verifyMeasurementConfig(measurement, supportedPodConditions); err != nil {
 		return nil, err
 	}
+	if measurement.TimelineSampleRate < 0 || measurement.TimelineSampleRate > 100 {
+		return nil, fmt.Errorf("timelineSampleRate must be between 0 and 100: %v", measurement.TimelineSampleRate)
+	}
+	if label, ok := strings.CutPrefix(measurement.GroupBy, nodeLabelGroupPrefix); ok {
+		if errs := validation.IsQualifiedName(label); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid groupBy node label %q: %s", label, strings.Join(errs, ", "))
+		}
+	}
 	return podLatencyMeasurementFactory{
 		BaseMeasurementFactory: NewBaseMeasurementFactory(configSpec, measurement, metadata),
 	}, nil
@@ -106,11 +127,18 @@ func (p *podLatency) handleCreatePod(obj any) {
 		Metadata:     p.Metadata,
 		JobIteration: getIntFromLabels(podLabels, config.KubeBurnerLabelJobIteration),
 		Replica:      getIntFromLabels(podLabels, config.KubeBurnerLabelReplica),
+		Group:        podLabels[p.Config.GroupBy],
 	})
+	if p.Config.TimelineSampleRate > 0 {
+		p.timelineCreatePod(pod)
+	}
 }
 
 func (p *podLatency) handleUpdatePod(obj any) {
 	pod := obj.(*corev1.Pod)
+	if p.Config.TimelineSampleRate > 0 {
+		p.timelineUpdatePod(pod)
+	}
 	if value, exists := p.metrics.Load(string(pod.UID)); exists {
 		pm := value.(podMetric)
 		if pm.podReady.IsZero() {
@@ -142,6 +170,9 @@ func (p *podLatency) handleUpdatePod(obj any) {
 				}
 			}
 			p.metrics.Store(string(pod.UID), pm)
+			if !pm.podReady.IsZero() {
+				p.objectCompleted()
+			}
 		}
 	}
 }
@@ -149,29 +180,42 @@ func (p *podLatency) handleUpdatePod(obj any) {
 // start podLatency measurement
 func (p *podLatency) Start(measurementWg *sync.WaitGroup) error {
 	defer measurementWg.Done()
-	p.startMeasurement(
-		[]MeasurementWatcher{
-			{
-				restClient:    p.ClientSet.CoreV1().RESTClient().(*rest.RESTClient),
-				name:          "podWatcher",
-				resource:      "pods",
-				labelSelector: fmt.Sprintf("kube-burner-runid=%v", p.Runid),
-				handlers: &cache.ResourceEventHandlerFuncs{
-					AddFunc: p.handleCreatePod,
-					UpdateFunc: func(oldObj, newObj any) {
-						p.handleUpdatePod(newObj)
-					},
+	p.timelines = podTimelines{
+		sampleRate: p.Config.TimelineSampleRate,
+		timelines:  map[string]*podTimeline{},
+	}
+	measurementWatchers := []MeasurementWatcher{
+		{
+			restClient:    p.ClientSet.CoreV1().RESTClient().(*rest.RESTClient),
+			name:          "podWatcher",
+			resource:      "pods",
+			labelSelector: fmt.Sprintf("kube-burner-runid=%v", p.Runid),
+			handlers: &cache.ResourceEventHandlerFuncs{
+				AddFunc: p.handleCreatePod,
+				UpdateFunc: func(oldObj, newObj any) {
+					p.handleUpdatePod(newObj)
 				},
 			},
 		},
-	)
+	}
+	if p.Config.TimelineSampleRate > 0 {
+		measurementWatchers = append(measurementWatchers, p.timelineWatcher())
+	}
+	if p.Config.ClockSkewCorrection {
+		p.skew = newClockSkewEstimator()
+		measurementWatchers = append(measurementWatchers, p.skew.watcher(p.ClientSet))
+		go p.skew.measureAPIServerOffset(p.RestConfig)
+	}
+	p.prepareStream(p.normalizer, p.getLatency)
+	p.startMeasurement(measurementWatchers)
 	return nil
 }
 
 // collects pod measurements triggered in the past
-func (p *podLatency) Collect(measurementWg *sync.WaitGroup) {
+func (p *podLatency) Collect(measurementWg *sync.WaitGroup) error {
 	defer measurementWg.Done()
 	var pods []corev1.Pod
+	var errs []error
 	labelSelector := labels.SelectorFromSet(p.JobConfig.NamespaceLabels)
 	options := metav1.ListOptions{
 		LabelSelector: labelSelector.String(),
@@ -180,11 +224,13 @@ func (p *podLatency) Collect(measurementWg *sync.WaitGroup) {
 	for _, namespace := range namespaces {
 		podList, err := p.ClientSet.CoreV1().Pods(namespace).List(context.TODO(), options)
 		if err != nil {
-			log.Errorf("error listing pods in namespace %s: %v", namespace, err)
+			errs = append(errs, fmt.Errorf("error listing pods in namespace %s: %v", namespace, err))
+			continue
 		}
 		pods = append(pods, podList.Items...)
 	}
 	p.metrics = sync.Map{}
+	p.stream = nil
 	for _, pod := range pods {
 		var scheduled, initialized, containersReady, podReady time.Time
 		for _, c := range pod.Status.Conditions {
@@ -211,25 +257,64 @@ func (p *podLatency) Collect(measurementWg *sync.WaitGroup) {
 			containersReady: containersReady,
 			podReady:        podReady,
 			JobName:         p.JobConfig.Name,
+			Group:           pod.Labels[p.Config.GroupBy],
 		})
 	}
+	return utilerrors.NewAggregate(errs)
 }
 
 // Stop stops podLatency measurement
 func (p *podLatency) Stop() error {
+	if p.skew != nil {
+		p.recordQuality(qualitySkewedNodes, p.skew.skewedNodes(p.Config.MaxClockSkew))
+	}
 	return p.StopMeasurement(p.normalizeMetrics, p.getLatency)
 }
 
+// Index indexes the pod latency documents and the sampled pod timelines, if enabled
+func (p *podLatency) Index(jobName string, indexerList map[string]indexers.Indexer) error {
+	metricMap := map[string][]any{
+		p.MeasurementName:          p.normLatencies,
+		p.QuantilesMeasurementName: p.latencyQuantiles,
+	}
+	if p.Config.TimelineSampleRate > 0 {
+		metricMap[podTimelineMeasurement] = p.timelineDocuments()
+	}
+	return utilerrors.NewAggregate(append(p.streamErrors(), p.indexLatencyMeasurement(jobName, metricMap, indexerList)))
+}
+
 func (p *podLatency) normalizeMetrics() float64 {
 	totalPods := 0
 	erroredPods := 0
+	normalize := p.normalizer()
+	p.metrics.Range(func(_, value any) bool {
+		m, errorFlag, ok := normalize(value)
+		if !ok {
+			return true
+		}
+		totalPods++
+		erroredPods += errorFlag
+		p.normLatencies = append(p.normLatencies, m)
+		return true
+	})
+	if totalPods == 0 {
+		return 0.0
+	}
+	return float64(erroredPods) / float64(totalPods) * 100.0
+}
 
-	p.metrics.Range(func(key, value any) bool {
+// normalizer returns the function calculating the latencies of a pod, pods that aren't ready are skipped
+func (p *podLatency) normalizer() func(value any) (any, int, bool) {
+	nodeGroups := p.nodeLabelGroups()
+	return func(value any) (any, int, bool) {
 		m := value.(podMetric)
+		if nodeGroups != nil {
+			m.Group = nodeGroups[m.NodeName]
+		}
 		// If a pod does not reach the Running state (this timestamp isn't set), we skip that pod
 		if m.podReady.IsZero() {
 			log.Tracef("Pod %v latency ignored as it did not reach Ready state", m.Name)
-			return true
+			return nil, 0, false
 		}
 		// latencyTime should be always larger than zero, however, in some cases, it might be a
 		// negative value due to the precision of timestamp can only get to the level of second
@@ -239,6 +324,15 @@ func (p *podLatency) normalizeMetrics() float64 {
 		// v2 latencies are currently under AB testing which blindly trust kubernetes as source of
 		// truth and will prevent us from those over 1s delays as well as <0 cases.
 		errorFlag := 0
+		if p.skew != nil {
+			// The conditions below PodScheduled are set by the kubelet, hence they're shifted by the node clock offset
+			offset := p.skew.offset(m.NodeName)
+			m.ClockOffset = int(offset.Milliseconds())
+			m.ClockSkewed = offset.Abs() > p.Config.MaxClockSkew
+			m.initialized = m.initialized.Add(-offset)
+			m.containersReady = m.containersReady.Add(-offset)
+			m.podReady = m.podReady.Add(-offset)
+		}
 		m.ContainersReadyLatency = int(m.containersReady.Sub(m.Timestamp).Milliseconds())
 		if m.ContainersReadyLatency < 0 {
 			log.Tracef("ContainersReadyLatency for pod %v falling under negative case. So explicitly setting it to 0", m.Name)
@@ -266,15 +360,9 @@ func (p *podLatency) normalizeMetrics() float64 {
 			errorFlag = 1
 			m.PodReadyLatency = 0
 		}
-		totalPods++
-		erroredPods += errorFlag
-		p.normLatencies = append(p.normLatencies, m)
-		return true
-	})
-	if totalPods == 0 {
-		return 0.0
+		m.Disruptions = p.GlobalConfig.Disruptions(m.Timestamp, m.podReady)
+		return m, errorFlag, true
 	}
-	return float64(erroredPods) / float64(totalPods) * 100.0
 }
 
 func (p *podLatency) getLatency(normLatency any) map[string]float64 {
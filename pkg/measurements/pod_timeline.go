@@ -0,0 +1,178 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const podTimelineMeasurement = "podTimelineMeasurement"
+
+// podTimelineEntry is a pod condition transition, phase change or event
+type podTimelineEntry struct {
+	Type      string    `json:"type"`
+	Status    string    `json:"status,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// podTimeline holds the complete timeline of a sampled pod
+type podTimeline struct {
+	Timestamp    time.Time          `json:"timestamp"`
+	MetricName   string             `json:"metricName"`
+	UUID         string             `json:"uuid"`
+	JobName      string             `json:"jobName,omitempty"`
+	JobIteration int                `json:"jobIteration"`
+	Replica      int                `json:"replica"`
+	Namespace    string             `json:"namespace"`
+	Name         string             `json:"podName"`
+	NodeName     string             `json:"nodeName"`
+	Transitions  []podTimelineEntry `json:"transitions"`
+	Events       []podTimelineEntry `json:"events,omitempty"`
+	Metadata     any                `json:"metadata,omitempty"`
+	// Last recorded status of each condition type and phase
+	lastStatus map[string]string
+}
+
+// podTimelines tracks the timelines of the pods sampled by the podLatency measurement
+type podTimelines struct {
+	sampleRate float64
+	timelines  map[string]*podTimeline
+	mu         sync.Mutex
+}
+
+// sampled returns whether the given pod UID belongs to the sample, the decision is deterministic
+func (pt *podTimelines) sampled(uid string) bool {
+	h := fnv.New32a()
+	h.Write([]byte(uid))
+	return float64(h.Sum32()%10000) < pt.sampleRate*100
+}
+
+func (p *podLatency) timelineCreatePod(pod *corev1.Pod) {
+	if !p.timelines.sampled(string(pod.UID)) {
+		return
+	}
+	podLabels := pod.GetLabels()
+	p.timelines.mu.Lock()
+	defer p.timelines.mu.Unlock()
+	if _, exists := p.timelines.timelines[string(pod.UID)]; exists {
+		return
+	}
+	p.timelines.timelines[string(pod.UID)] = &podTimeline{
+		Timestamp:    pod.CreationTimestamp.UTC(),
+		MetricName:   podTimelineMeasurement,
+		UUID:         p.Uuid,
+		JobName:      p.JobConfig.Name,
+		JobIteration: getIntFromLabels(podLabels, config.KubeBurnerLabelJobIteration),
+		Replica:      getIntFromLabels(podLabels, config.KubeBurnerLabelReplica),
+		Namespace:    pod.Namespace,
+		Name:         pod.Name,
+		Metadata:     p.Metadata,
+		lastStatus:   map[string]string{},
+	}
+}
+
+// timelineUpdatePod records the condition transitions and phase changes of a sampled pod
+func (p *podLatency) timelineUpdatePod(pod *corev1.Pod) {
+	p.timelines.mu.Lock()
+	defer p.timelines.mu.Unlock()
+	timeline, exists := p.timelines.timelines[string(pod.UID)]
+	if !exists {
+		return
+	}
+	if pod.Spec.NodeName != "" {
+		timeline.NodeName = pod.Spec.NodeName
+	}
+	if phase := string(pod.Status.Phase); phase != "" && timeline.lastStatus["Phase"] != phase {
+		timeline.lastStatus["Phase"] = phase
+		timeline.Transitions = append(timeline.Transitions, podTimelineEntry{
+			Type:      "Phase",
+			Status:    phase,
+			Timestamp: time.Now().UTC(),
+		})
+	}
+	for _, c := range pod.Status.Conditions {
+		if timeline.lastStatus[string(c.Type)] == string(c.Status) {
+			continue
+		}
+		timeline.lastStatus[string(c.Type)] = string(c.Status)
+		timeline.Transitions = append(timeline.Transitions, podTimelineEntry{
+			Type:      string(c.Type),
+			Status:    string(c.Status),
+			Reason:    c.Reason,
+			Message:   c.Message,
+			Timestamp: c.LastTransitionTime.UTC(),
+		})
+	}
+}
+
+// timelineEvent records the events, like image pulls, involving a sampled pod
+func (p *podLatency) timelineEvent(obj any) {
+	event := obj.(*corev1.Event)
+	p.timelines.mu.Lock()
+	defer p.timelines.mu.Unlock()
+	timeline, exists := p.timelines.timelines[string(event.InvolvedObject.UID)]
+	if !exists {
+		return
+	}
+	timestamp := event.EventTime.UTC()
+	if timestamp.IsZero() {
+		timestamp = event.LastTimestamp.UTC()
+	}
+	if timestamp.IsZero() {
+		timestamp = event.FirstTimestamp.UTC()
+	}
+	timeline.Events = append(timeline.Events, podTimelineEntry{
+		Type:      event.Type,
+		Reason:    event.Reason,
+		Message:   event.Message,
+		Timestamp: timestamp,
+	})
+}
+
+// timelineWatcher returns the watcher collecting the events of the sampled pods
+func (p *podLatency) timelineWatcher() MeasurementWatcher {
+	return MeasurementWatcher{
+		restClient:    p.ClientSet.CoreV1().RESTClient().(*rest.RESTClient),
+		name:          "podEventWatcher",
+		resource:      "events",
+		fieldSelector: "involvedObject.kind=Pod",
+		handlers: &cache.ResourceEventHandlerFuncs{
+			AddFunc: p.timelineEvent,
+			UpdateFunc: func(oldObj, newObj any) {
+				p.timelineEvent(newObj)
+			},
+		},
+	}
+}
+
+// timelineDocuments returns the timelines of the sampled pods
+func (p *podLatency) timelineDocuments() []any {
+	p.timelines.mu.Lock()
+	defer p.timelines.mu.Unlock()
+	documents := make([]any, 0, len(p.timelines.timelines))
+	for _, timeline := range p.timelines.timelines {
+		documents = append(documents, *timeline)
+	}
+	return documents
+}
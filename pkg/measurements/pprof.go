@@ -17,11 +17,17 @@ package measurements
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/base64"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -29,6 +35,7 @@ import (
 	"github.com/cloud-bulldozer/go-commons/v2/indexers"
 	"github.com/kube-burner/kube-burner/pkg/config"
 	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/pkg/measurements/util"
 	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
@@ -41,10 +48,30 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 )
 
+const pprofProfileMeasurement = "pprofProfile"
+
 type pprof struct {
 	BaseMeasurement
 
 	stopChannel chan bool
+	// httpClients clients of the direct and portForward targets, by target name
+	httpClients map[string]*http.Client
+	mu          sync.Mutex
+	profiles    []any
+}
+
+// pprofProfile indexed document of a pprof file collected during a job
+type pprofProfile struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Target     string    `json:"target"`
+	Namespace  string    `json:"namespace,omitempty"`
+	Pod        string    `json:"pod,omitempty"`
+	File       string    `json:"file"`
+	Size       int64     `json:"size"`
+	UUID       string    `json:"uuid"`
+	MetricName string    `json:"metricName"`
+	JobName    string    `json:"jobName,omitempty"`
+	Metadata   any       `json:"metadata,omitempty"`
 }
 
 type pprofLatencyMeasurementFactory struct {
@@ -52,17 +79,52 @@ type pprofLatencyMeasurementFactory struct {
 }
 
 func newPprofLatencyMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any) (MeasurementFactory, error) {
-	for _, target := range measurement.PProfTargets {
+	for i, target := range measurement.PProfTargets {
 		if target.BearerToken != "" && (target.CertFile != "" || target.Cert != "") {
 			return nil, fmt.Errorf("bearerToken and cert auth methods cannot be specified together in the same target")
 		}
+		switch target.Mode {
+		case "":
+			measurement.PProfTargets[i].Mode = types.PProfExec
+		case types.PProfExec, types.PProfDirect:
+		case types.PProfPortForward:
+			if target.Namespace == "" {
+				return nil, fmt.Errorf("pprof target %s: portForward mode requires a namespace", target.Name)
+			}
+		default:
+			return nil, fmt.Errorf("pprof target %s: invalid mode %s", target.Name, target.Mode)
+		}
+	}
+	if measurement.PProfIndexer != "" {
+		metricsDirectory, err := localMetricsDirectory(configSpec, measurement.PProfIndexer)
+		if err != nil {
+			return nil, err
+		}
+		if !filepath.IsAbs(measurement.PProfDirectory) {
+			measurement.PProfDirectory = filepath.Join(metricsDirectory, measurement.PProfDirectory)
+		}
 	}
-
 	return pprofLatencyMeasurementFactory{
 		BaseMeasurementFactory: NewBaseMeasurementFactory(configSpec, measurement, metadata),
 	}, nil
 }
 
+// localMetricsDirectory returns the metrics directory of the local indexer with the given alias
+func localMetricsDirectory(configSpec config.Spec, alias string) (string, error) {
+	for _, endpoint := range configSpec.MetricsEndpoints {
+		if endpoint.Alias != alias {
+			continue
+		}
+		for _, indexerConfig := range append([]config.IndexerConfig{endpoint.IndexerConfig}, endpoint.Indexers...) {
+			if indexerConfig.Type == indexers.LocalIndexer {
+				return indexerConfig.MetricsDirectory, nil
+			}
+		}
+		return "", fmt.Errorf("pprofIndexer %s is not a local indexer", alias)
+	}
+	return "", fmt.Errorf("pprofIndexer %s not found", alias)
+}
+
 func (plmf pprofLatencyMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) Measurement {
 	return &pprof{
 		BaseMeasurement: plmf.NewBaseLatency(jobConfig, clientSet, restConfig, "", "", embedCfg),
@@ -74,7 +136,18 @@ func (p *pprof) Start(measurementWg *sync.WaitGroup) error {
 	var wg sync.WaitGroup
 	err := os.MkdirAll(p.Config.PProfDirectory, 0744)
 	if err != nil {
-		log.Fatalf("Error creating pprof directory: %s", err)
+		return fmt.Errorf("error creating pprof directory: %s", err)
+	}
+	p.profiles = nil
+	p.httpClients = make(map[string]*http.Client)
+	for _, target := range p.Config.PProfTargets {
+		if target.Mode == types.PProfDirect || target.Mode == types.PProfPortForward {
+			client, err := pprofHTTPClient(target, p.Config.PProfInterval)
+			if err != nil {
+				return fmt.Errorf("pprof target %s: %v", target.Name, err)
+			}
+			p.httpClients[target.Name] = client
+		}
 	}
 	p.stopChannel = make(chan bool)
 	p.getPProf(&wg, true)
@@ -98,22 +171,69 @@ func (p *pprof) Start(measurementWg *sync.WaitGroup) error {
 	return nil
 }
 
+// pprofHTTPClient returns the client fetching the profiles of a target from kube-burner, like curl -k does from the pods
+func pprofHTTPClient(target types.PProftarget, timeout time.Duration) (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	var certificate tls.Certificate
+	var err error
+	switch {
+	case target.CertFile != "" && target.KeyFile != "":
+		certificate, err = tls.LoadX509KeyPair(target.CertFile, target.KeyFile)
+	case target.Cert != "" && target.Key != "":
+		var certData, keyData []byte
+		if certData, err = base64.StdEncoding.DecodeString(target.Cert); err != nil {
+			return nil, fmt.Errorf("error decoding certificate: %v", err)
+		}
+		if keyData, err = base64.StdEncoding.DecodeString(target.Key); err != nil {
+			return nil, fmt.Errorf("error decoding private key: %v", err)
+		}
+		certificate, err = tls.X509KeyPair(certData, keyData)
+	default:
+		return &http.Client{Timeout: timeout, Transport: &http.Transport{TLSClientConfig: tlsConfig}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error loading client certificate: %v", err)
+	}
+	tlsConfig.Certificates = []tls.Certificate{certificate}
+	return &http.Client{Timeout: timeout, Transport: &http.Transport{TLSClientConfig: tlsConfig}}, nil
+}
+
 func (p *pprof) getPods(target types.PProftarget) []corev1.Pod {
 	labelSelector := labels.Set(target.LabelSelector).String()
 	podList, err := p.ClientSet.CoreV1().Pods(target.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: labelSelector})
 	if err != nil {
 		log.Errorf("Error found listing pods labeled with %s: %s", labelSelector, err)
+		return nil
 	}
 	return podList.Items
 }
 
 func (p *pprof) getPProf(wg *sync.WaitGroup, first bool) {
 	var err error
-	var command []string
 	for pos, target := range p.Config.PProfTargets {
 		log.Infof("Collecting %s pprof", target.Name)
+		if target.Mode == types.PProfDirect {
+			wg.Add(1)
+			go func(target types.PProftarget) {
+				defer wg.Done()
+				p.savePProf(target, corev1.Pod{}, func(f *os.File) error {
+					return p.fetchPProf(target, target.URL, f)
+				})
+			}(target)
+			continue
+		}
 		podList := p.getPods(target)
 		for _, pod := range podList {
+			if target.Mode == types.PProfPortForward {
+				wg.Add(1)
+				go func(target types.PProftarget, pod corev1.Pod) {
+					defer wg.Done()
+					p.savePProf(target, pod, func(f *os.File) error {
+						return p.portForwardPProf(target, pod, f)
+					})
+				}(target, pod)
+				continue
+			}
 			var cert, privKey io.Reader
 			if target.CertFile != "" && target.KeyFile != "" && first {
 				// target is a copy of one of the slice elements, so we need to modify the target object directly from the slice
@@ -140,64 +260,176 @@ func (p *pprof) getPProf(wg *sync.WaitGroup, first bool) {
 			wg.Add(1)
 			go func(target types.PProftarget, pod corev1.Pod) {
 				defer wg.Done()
-				pprofFile := fmt.Sprintf("%s-%s-%d.pprof", target.Name, pod.Name, time.Now().Unix())
-				f, err := os.Create(path.Join(p.Config.PProfDirectory, pprofFile))
-				var stderr bytes.Buffer
-				if err != nil {
-					log.Errorf("Error creating pprof file %s: %s", pprofFile, err)
-					return
-				}
-				defer f.Close()
 				if cert != nil && privKey != nil && first {
-					if err = p.copyCertsToPod(pod, cert, privKey); err != nil {
+					if err := p.copyCertsToPod(pod, cert, privKey); err != nil {
 						log.Error(err)
 						return
 					}
 				}
-				if target.BearerToken != "" {
-					command = []string{"curl", "-sSLkH", fmt.Sprintf("Authorization: Bearer %s", target.BearerToken), target.URL}
-				} else if target.Cert != "" && target.Key != "" {
-					command = []string{"curl", "-sSLk", "--cert", "/tmp/pprof.crt", "--key", "/tmp/pprof.key", target.URL}
-				} else {
-					command = []string{"curl", "-sSLk", target.URL}
-				}
-				req := p.ClientSet.CoreV1().
-					RESTClient().
-					Post().
-					Resource("pods").
-					Name(pod.Name).
-					Namespace(pod.Namespace).
-					SubResource("exec")
-				log.Debugf("Collecting pprof using URL: %s", req.URL())
-				req.VersionedParams(&corev1.PodExecOptions{
-					Command:   command,
-					Container: pod.Spec.Containers[0].Name,
-					Stdin:     false,
-					Stderr:    true,
-					Stdout:    true,
-				}, scheme.ParameterCodec)
-				log.Debugf("Executing %s in pod %s", command, pod.Name)
-				exec, err := remotecommand.NewSPDYExecutor(p.RestConfig, "POST", req.URL())
-				if err != nil {
-					log.Errorf("Failed to execute pprof command on %s: %s", target.Name, err)
-				}
-				err = exec.StreamWithContext(context.TODO(), remotecommand.StreamOptions{
-					Stdin:  nil,
-					Stdout: f,
-					Stderr: &stderr,
+				p.savePProf(target, pod, func(f *os.File) error {
+					return p.execPProf(target, pod, f)
 				})
-				if err != nil {
-					log.Errorf("Failed to get pprof from %s: %s", pod.Name, stderr.String())
-					os.Remove(f.Name())
-				}
 			}(p.Config.PProfTargets[pos], pod)
 		}
 	}
 	wg.Wait()
 }
 
-func (p *pprof) Collect(measurementWg *sync.WaitGroup) {
+// savePProf writes the profile of a target, or one of its pods, to the pprof directory and records it
+func (p *pprof) savePProf(target types.PProftarget, pod corev1.Pod, fetch func(*os.File) error) {
+	timestamp := time.Now().UTC()
+	pprofFile := fmt.Sprintf("%s-%d.pprof", target.Name, timestamp.Unix())
+	if pod.Name != "" {
+		pprofFile = fmt.Sprintf("%s-%s-%d.pprof", target.Name, pod.Name, timestamp.Unix())
+	}
+	f, err := os.Create(path.Join(p.Config.PProfDirectory, pprofFile))
+	if err != nil {
+		log.Errorf("Error creating pprof file %s: %s", pprofFile, err)
+		return
+	}
+	defer f.Close()
+	if err := fetch(f); err != nil {
+		log.Error(err)
+		os.Remove(f.Name())
+		return
+	}
+	var size int64
+	if info, err := f.Stat(); err == nil {
+		size = info.Size()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.profiles = append(p.profiles, pprofProfile{
+		Timestamp:  timestamp,
+		Target:     target.Name,
+		Namespace:  pod.Namespace,
+		Pod:        pod.Name,
+		File:       f.Name(),
+		Size:       size,
+		UUID:       p.Uuid,
+		MetricName: pprofProfileMeasurement,
+		JobName:    p.JobConfig.Name,
+		Metadata:   p.Metadata,
+	})
+}
+
+// execPProf runs curl in the first container of the pod to get the profile
+func (p *pprof) execPProf(target types.PProftarget, pod corev1.Pod, f *os.File) error {
+	var command []string
+	var stderr bytes.Buffer
+	if target.BearerToken != "" {
+		command = []string{"curl", "-sSLkH", fmt.Sprintf("Authorization: Bearer %s", target.BearerToken), target.URL}
+	} else if target.Cert != "" && target.Key != "" {
+		command = []string{"curl", "-sSLk", "--cert", "/tmp/pprof.crt", "--key", "/tmp/pprof.key", target.URL}
+	} else {
+		command = []string{"curl", "-sSLk", target.URL}
+	}
+	req := p.ClientSet.CoreV1().
+		RESTClient().
+		Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		SubResource("exec")
+	log.Debugf("Collecting pprof using URL: %s", req.URL())
+	req.VersionedParams(&corev1.PodExecOptions{
+		Command:   command,
+		Container: pod.Spec.Containers[0].Name,
+		Stdin:     false,
+		Stderr:    true,
+		Stdout:    true,
+	}, scheme.ParameterCodec)
+	log.Debugf("Executing %s in pod %s", command, pod.Name)
+	exec, err := remotecommand.NewSPDYExecutor(p.RestConfig, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to execute pprof command on %s: %s", target.Name, err)
+	}
+	err = exec.StreamWithContext(context.TODO(), remotecommand.StreamOptions{
+		Stdin:  nil,
+		Stdout: f,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get pprof from %s: %s", pod.Name, stderr.String())
+	}
+	return nil
+}
+
+// portForwardPProf gets the profile through a port-forward to the pod, the host of the target URL is replaced by the
+// local end of the port-forward
+func (p *pprof) portForwardPProf(target types.PProftarget, pod corev1.Pod, f *os.File) error {
+	targetURL, err := url.Parse(target.URL)
+	if err != nil {
+		return fmt.Errorf("pprof target %s: invalid URL %s: %v", target.Name, target.URL, err)
+	}
+	port := target.Port
+	if port == 0 {
+		if port, err = strconv.Atoi(targetURL.Port()); err != nil {
+			return fmt.Errorf("pprof target %s: port required, the URL %s has no port", target.Name, target.URL)
+		}
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	localPort := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	forwarder, err := util.NewPodPortForwarder(p.ClientSet, *p.RestConfig, fmt.Sprintf("%d:%d", localPort, port), pod.Namespace, pod.Name)
+	if err != nil {
+		return fmt.Errorf("failed to port-forward to %s: %v", pod.Name, err)
+	}
+	defer forwarder.CancelPodPortForwarder()
+	targetURL.Host = fmt.Sprintf("127.0.0.1:%d", localPort)
+	return p.fetchPProf(target, targetURL.String(), f)
+}
+
+// fetchPProf gets the profile from kube-burner, URLs without host are fetched from the API server with the
+// credentials of the kubeconfig
+func (p *pprof) fetchPProf(target types.PProftarget, targetURL string, f *os.File) error {
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return fmt.Errorf("pprof target %s: invalid URL %s: %v", target.Name, targetURL, err)
+	}
+	if u.Host == "" {
+		req := p.ClientSet.CoreV1().RESTClient().Get().AbsPath(u.Path)
+		for param, values := range u.Query() {
+			for _, value := range values {
+				req.Param(param, value)
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.TODO(), p.Config.PProfInterval)
+		defer cancel()
+		stream, err := req.Stream(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get pprof from %s: %v", target.Name, err)
+		}
+		defer stream.Close()
+		_, err = io.Copy(f, stream)
+		return err
+	}
+	req, err := http.NewRequest(http.MethodGet, targetURL, nil)
+	if err != nil {
+		return err
+	}
+	if target.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+target.BearerToken)
+	}
+	resp, err := p.httpClients[target.Name].Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to get pprof from %s: %v", target.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to get pprof from %s: %s %s", target.Name, resp.Status, strings.TrimSpace(string(body)))
+	}
+	_, err = io.Copy(f, resp.Body)
+	return err
+}
+
+func (p *pprof) Collect(measurementWg *sync.WaitGroup) error {
 	defer measurementWg.Done()
+	return nil
 }
 
 func (p *pprof) Stop() error {
@@ -205,8 +437,20 @@ func (p *pprof) Stop() error {
 	return nil
 }
 
-// Fake index function for pprof
-func (p *pprof) Index(_ string, _ map[string]indexers.Indexer) {
+// Index indexes a document per pprof file collected, only in the pprof indexer when set
+func (p *pprof) Index(jobName string, indexerList map[string]indexers.Indexer) error {
+	p.mu.Lock()
+	profiles := p.profiles
+	p.mu.Unlock()
+	if len(profiles) == 0 {
+		return nil
+	}
+	if p.Config.PProfIndexer != "" {
+		if indexer, ok := indexerList[p.Config.PProfIndexer]; ok {
+			indexerList = map[string]indexers.Indexer{p.Config.PProfIndexer: indexer}
+		}
+	}
+	return p.indexLatencyMeasurement(jobName, map[string][]any{pprofProfileMeasurement: profiles}, indexerList)
 }
 
 func readCerts(cert, privKey string) (string, string, error) {
@@ -24,6 +24,7 @@ import (
 	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
@@ -32,6 +33,8 @@ import (
 const (
 	pvcLatencyMeasurement          = "pvcLatencyMeasurement"
 	pvcLatencyQuantilesMeasurement = "pvcLatencyQuantilesMeasurement"
+	pvcAttached                    = "Attached"
+	pvcMounted                     = "Mounted"
 )
 
 var (
@@ -39,6 +42,8 @@ var (
 		string(corev1.ClaimPending): {},
 		string(corev1.ClaimBound):   {},
 		string(corev1.ClaimLost):    {},
+		pvcAttached:                 {},
+		pvcMounted:                  {},
 	}
 )
 
@@ -49,7 +54,12 @@ type pvcMetric struct {
 	bound          int64
 	BindingLatency int `json:"bindingLatency"`
 	lost           int64
-	LostLatency    int    `json:"lostLatency"`
+	LostLatency    int `json:"lostLatency"`
+	attached       int64
+	AttachLatency  int    `json:"attachLatency,omitempty"`
+	MountLatency   int    `json:"mountLatency,omitempty"`
+	MountFailures  int    `json:"mountFailures,omitempty"`
+	VolumeName     string `json:"volumeName,omitempty"`
 	UUID           string `json:"uuid"`
 	Name           string `json:"pvcName"`
 	JobName        string `json:"jobName,omitempty"`
@@ -64,6 +74,29 @@ type pvcMetric struct {
 
 type pvcLatency struct {
 	BaseMeasurement
+	volumes volumeTracker
+}
+
+// volumeTracker times of the attachments of the volumes and of the setup of the pods using the PVCs
+type volumeTracker struct {
+	mu sync.Mutex
+	// attachments by PV name
+	attachments map[string]*volumeAttachmentTimes
+	// claims mount times by PVC namespace/name, set by the first pod using the claim
+	claims map[string]*claimMountTimes
+	// mountFailures count of FailedMount events by pod namespace/name and event UID
+	mountFailures map[string]map[string]int32
+}
+
+type volumeAttachmentTimes struct {
+	created  int64
+	attached int64
+}
+
+type claimMountTimes struct {
+	pod       string
+	scheduled int64
+	ready     int64
 }
 
 type pvcLatencyMeasurementFactory struct {
@@ -124,6 +157,7 @@ func (p *pvcLatency) handleUpdatePVC(obj any) {
 				if pm.bound == 0 {
 					log.Debugf("PVC %s is bound", pvc.Name)
 					pm.bound = time.Now().UTC().UnixMilli()
+					pm.VolumeName = pvc.Spec.VolumeName
 				}
 			}
 			if pvc.Status.Phase == corev1.ClaimLost {
@@ -139,12 +173,87 @@ func (p *pvcLatency) handleUpdatePVC(obj any) {
 	}
 }
 
+// handleVolumeAttachment records when the attachments of the volumes are created and attached
+func (p *pvcLatency) handleVolumeAttachment(obj any) {
+	va := obj.(*storagev1.VolumeAttachment)
+	if va.Spec.Source.PersistentVolumeName == nil {
+		return
+	}
+	now := time.Now().UTC().UnixMilli()
+	p.volumes.mu.Lock()
+	defer p.volumes.mu.Unlock()
+	times, exists := p.volumes.attachments[*va.Spec.Source.PersistentVolumeName]
+	if !exists {
+		times = &volumeAttachmentTimes{created: now}
+		p.volumes.attachments[*va.Spec.Source.PersistentVolumeName] = times
+	}
+	if va.Status.Attached && times.attached == 0 {
+		log.Debugf("Volume %s is attached to node %s", *va.Spec.Source.PersistentVolumeName, va.Spec.NodeName)
+		times.attached = now
+	}
+}
+
+// handlePodVolumes records when the first pod using each PVC is scheduled and ready to start its containers,
+// which happens once its volumes are mounted
+func (p *pvcLatency) handlePodVolumes(obj any) {
+	pod := obj.(*corev1.Pod)
+	var scheduled, ready bool
+	for _, c := range pod.Status.Conditions {
+		switch c.Type {
+		case corev1.PodScheduled:
+			scheduled = c.Status == corev1.ConditionTrue
+		case corev1.PodReadyToStartContainers:
+			ready = c.Status == corev1.ConditionTrue
+		}
+	}
+	now := time.Now().UTC().UnixMilli()
+	p.volumes.mu.Lock()
+	defer p.volumes.mu.Unlock()
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		key := pod.Namespace + "/" + volume.PersistentVolumeClaim.ClaimName
+		times, exists := p.volumes.claims[key]
+		if !exists {
+			times = &claimMountTimes{pod: pod.Namespace + "/" + pod.Name}
+			p.volumes.claims[key] = times
+		}
+		if times.pod != pod.Namespace+"/"+pod.Name {
+			continue
+		}
+		if scheduled && times.scheduled == 0 {
+			times.scheduled = now
+		}
+		if ready && times.ready == 0 {
+			times.ready = now
+		}
+	}
+}
+
+// handleFailedMount counts the FailedMount events of the pods, repeated events are aggregated by the kubelet
+func (p *pvcLatency) handleFailedMount(obj any) {
+	event := obj.(*corev1.Event)
+	key := event.InvolvedObject.Namespace + "/" + event.InvolvedObject.Name
+	p.volumes.mu.Lock()
+	defer p.volumes.mu.Unlock()
+	if p.volumes.mountFailures[key] == nil {
+		p.volumes.mountFailures[key] = make(map[string]int32)
+	}
+	p.volumes.mountFailures[key][string(event.UID)] = max(event.Count, 1)
+}
+
 // start pvcLatency measurement
 func (p *pvcLatency) Start(measurementWg *sync.WaitGroup) error {
 	defer measurementWg.Done()
-	if p.JobConfig.JobType == config.ReadJob || p.JobConfig.JobType == config.PatchJob || p.JobConfig.JobType == config.DeletionJob {
+	if p.JobConfig.JobType == config.ReadJob || p.JobConfig.JobType == config.PatchJob || p.JobConfig.JobType == config.DeletionJob || p.JobConfig.JobType == config.InflateJob {
 		log.Fatalf("Unsupported jobType:%s for pvcLatency metric", p.JobConfig.JobType)
 	}
+	p.volumes = volumeTracker{
+		attachments:   make(map[string]*volumeAttachmentTimes),
+		claims:        make(map[string]*claimMountTimes),
+		mountFailures: make(map[string]map[string]int32),
+	}
 	p.startMeasurement(
 		[]MeasurementWatcher{
 			{
@@ -159,15 +268,51 @@ func (p *pvcLatency) Start(measurementWg *sync.WaitGroup) error {
 					},
 				},
 			},
+			{
+				restClient: p.ClientSet.StorageV1().RESTClient().(*rest.RESTClient),
+				name:       "volumeAttachmentWatcher",
+				resource:   "volumeattachments",
+				handlers: &cache.ResourceEventHandlerFuncs{
+					AddFunc: p.handleVolumeAttachment,
+					UpdateFunc: func(oldObj, newObj any) {
+						p.handleVolumeAttachment(newObj)
+					},
+				},
+			},
+			{
+				restClient:    p.ClientSet.CoreV1().RESTClient().(*rest.RESTClient),
+				name:          "pvcPodWatcher",
+				resource:      "pods",
+				labelSelector: fmt.Sprintf("kube-burner-runid=%v", p.Runid),
+				handlers: &cache.ResourceEventHandlerFuncs{
+					AddFunc: p.handlePodVolumes,
+					UpdateFunc: func(oldObj, newObj any) {
+						p.handlePodVolumes(newObj)
+					},
+				},
+			},
+			{
+				restClient:    p.ClientSet.CoreV1().RESTClient().(*rest.RESTClient),
+				name:          "failedMountWatcher",
+				resource:      "events",
+				fieldSelector: "reason=FailedMount,involvedObject.kind=Pod",
+				handlers: &cache.ResourceEventHandlerFuncs{
+					AddFunc: p.handleFailedMount,
+					UpdateFunc: func(oldObj, newObj any) {
+						p.handleFailedMount(newObj)
+					},
+				},
+			},
 		},
 	)
 	return nil
 }
 
 // collects PVC measurements triggered in the past
-func (p *pvcLatency) Collect(measurementWg *sync.WaitGroup) {
+func (p *pvcLatency) Collect(measurementWg *sync.WaitGroup) error {
 	log.Info("Collect method doesn't apply to PVC by design")
 	defer measurementWg.Done()
+	return nil
 }
 
 // helper to fetch the storage class name (handles nil cases)
@@ -218,6 +363,8 @@ func (p *pvcLatency) normalizeMetrics() float64 {
 			m.LostLatency = 0
 		}
 
+		p.volumeLatencies(&m)
+
 		totalPVCs++
 		erroredPVCs += errorFlag
 		p.normLatencies = append(p.normLatencies, m)
@@ -229,11 +376,40 @@ func (p *pvcLatency) normalizeMetrics() float64 {
 	return float64(erroredPVCs) / float64(totalPVCs) * 100.0
 }
 
+// volumeLatencies sets the attach latency, from the creation of the volume attachment until it's attached, and the
+// mount latency, from the pod being scheduled, or the volume being attached if later, until the pod is ready to start its containers
+func (p *pvcLatency) volumeLatencies(m *pvcMetric) {
+	p.volumes.mu.Lock()
+	defer p.volumes.mu.Unlock()
+	if times, ok := p.volumes.attachments[m.VolumeName]; ok && m.VolumeName != "" && times.attached != 0 {
+		m.attached = times.attached
+		m.AttachLatency = int(times.attached - times.created)
+	}
+	times, ok := p.volumes.claims[m.Namespace+"/"+m.Name]
+	if !ok {
+		return
+	}
+	for _, count := range p.volumes.mountFailures[times.pod] {
+		m.MountFailures += int(count)
+	}
+	if times.ready != 0 && times.scheduled != 0 {
+		m.MountLatency = max(int(times.ready-max(times.scheduled, m.attached)), 0)
+	}
+}
+
 func (p *pvcLatency) getLatency(normLatency any) map[string]float64 {
 	pvcMetric := normLatency.(pvcMetric)
-	return map[string]float64{
+	latencies := map[string]float64{
 		string(corev1.ClaimPending): float64(pvcMetric.PendingLatency),
 		string(corev1.ClaimBound):   float64(pvcMetric.BindingLatency),
 		string(corev1.ClaimLost):    float64(pvcMetric.LostLatency),
 	}
+	// Volumes not requiring attachment and claims not used by any pod have no attach or mount latencies
+	if pvcMetric.attached != 0 {
+		latencies[pvcAttached] = float64(pvcMetric.AttachLatency)
+	}
+	if pvcMetric.MountLatency != 0 {
+		latencies[pvcMounted] = float64(pvcMetric.MountLatency)
+	}
+	return latencies
 }
@@ -0,0 +1,109 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"fmt"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// Phase phase of the lifecycle of a measurement
+type Phase string
+
+const (
+	PhaseStart   Phase = "start"
+	PhaseCollect Phase = "collect"
+	PhaseStop    Phase = "stop"
+	PhaseIndex   Phase = "index"
+)
+
+// MeasurementError error returned by a measurement in one of its phases
+type MeasurementError struct {
+	Measurement string
+	Phase       Phase
+	Err         error
+}
+
+func (e *MeasurementError) Error() string {
+	return fmt.Sprintf("measurement %s %s: %v", e.Measurement, e.Phase, e.Err)
+}
+
+func (e *MeasurementError) Unwrap() error {
+	return e.Err
+}
+
+// Result status of a measurement once one of its phases finished
+type Result struct {
+	Measurement string
+	Phase       Phase
+	// Duration time the measurement took to run the phase
+	Duration time.Duration
+	// Err error returned by the measurement, nil when it succeeded
+	Err *MeasurementError
+}
+
+// Failed returns whether the measurement failed the phase
+func (r Result) Failed() bool {
+	return r.Err != nil
+}
+
+// Results status of the measurements of a job once one of their phases finished
+type Results []Result
+
+// Failed returns the results of the measurements that failed the phase
+func (r Results) Failed() Results {
+	var failed Results
+	for _, result := range r {
+		if result.Failed() {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Get returns the result of the given measurement
+func (r Results) Get(measurement string) (Result, bool) {
+	for _, result := range r {
+		if result.Measurement == measurement {
+			return result, true
+		}
+	}
+	return Result{}, false
+}
+
+// Err returns the aggregate of the errors of the measurements that failed the phase, each of them a
+// *MeasurementError, or nil when all succeeded
+func (r Results) Err() error {
+	var errs []error
+	for _, result := range r.Failed() {
+		errs = append(errs, result.Err)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// newResult returns the result of a measurement phase that started at the given time
+func newResult(measurement string, phase Phase, start time.Time, err error) Result {
+	result := Result{
+		Measurement: measurement,
+		Phase:       phase,
+		Duration:    time.Since(start),
+	}
+	if err != nil {
+		result.Err = &MeasurementError{Measurement: measurement, Phase: phase, Err: err}
+	}
+	return result
+}
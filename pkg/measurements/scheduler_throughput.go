@@ -0,0 +1,460 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/pkg/measurements/util"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	schedulerThroughputMeasurement          = "schedulerThroughputMeasurement"
+	schedulerThroughputQuantilesMeasurement = "schedulerThroughputQuantilesMeasurement"
+	schedulerThroughputSummaryMeasurement   = "schedulerThroughputSummary"
+	podScheduledCondition                   = "PodScheduled"
+	defaultSchedulerNs                      = "kube-system"
+	defaultSchedulerLabels                  = "component=kube-scheduler"
+	schedulerMetricsPort                    = 10259
+)
+
+var supportedSchedulerThroughputConditions = map[string]struct{}{
+	podScheduledCondition: {},
+}
+
+// kube-scheduler histograms, by the name used in the summary. Older names are kept as fallbacks
+var schedulerHistograms = map[string][]string{
+	"e2e":     {"scheduler_pod_scheduling_sli_duration_seconds", "scheduler_pod_scheduling_duration_seconds"},
+	"attempt": {"scheduler_scheduling_attempt_duration_seconds", "scheduler_e2e_scheduling_duration_seconds"},
+	"bind":    {"scheduler_framework_extension_point_duration_seconds"},
+}
+
+// schedulerPodMetric scheduling of a pod created by the job, as observed by the measurement
+type schedulerPodMetric struct {
+	created   time.Time
+	scheduled time.Time
+}
+
+// schedulerThroughputSample pods scheduled within a second of the job
+type schedulerThroughputSample struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Scheduled  int       `json:"scheduled"`
+	MetricName string    `json:"metricName"`
+	UUID       string    `json:"uuid"`
+	JobName    string    `json:"jobName,omitempty"`
+	Metadata   any       `json:"metadata,omitempty"`
+}
+
+// schedulerThroughputSummary throughput of kube-scheduler over the job, and the breakdown of the scheduling time
+// of all the pods it scheduled meanwhile, from its metrics. Latencies are in milliseconds
+type schedulerThroughputSummary struct {
+	Timestamp     time.Time `json:"timestamp"`
+	PodsScheduled int       `json:"podsScheduled"`
+	// Duration seconds from the creation of the first pod to the scheduling of the last one
+	Duration       float64 `json:"duration"`
+	AvgThroughput  float64 `json:"avgThroughput"`
+	PeakThroughput int     `json:"peakThroughput"`
+	// From the kube-scheduler metrics, absent when they can't be scraped
+	AttemptsPerPod float64 `json:"attemptsPerPod,omitempty"`
+	E2EAvg         float64 `json:"e2eAvg,omitempty"`
+	E2EP50         float64 `json:"e2eP50,omitempty"`
+	E2EP99         float64 `json:"e2eP99,omitempty"`
+	QueueWaitAvg   float64 `json:"queueWaitAvg,omitempty"`
+	AttemptAvg     float64 `json:"attemptAvg,omitempty"`
+	AttemptP50     float64 `json:"attemptP50,omitempty"`
+	AttemptP99     float64 `json:"attemptP99,omitempty"`
+	BindAvg        float64 `json:"bindAvg,omitempty"`
+	BindP50        float64 `json:"bindP50,omitempty"`
+	BindP99        float64 `json:"bindP99,omitempty"`
+	MetricName     string  `json:"metricName"`
+	UUID           string  `json:"uuid"`
+	JobName        string  `json:"jobName,omitempty"`
+	Metadata       any     `json:"metadata,omitempty"`
+}
+
+// histogram sum of the samples of a kube-scheduler histogram across its label sets and pods
+type histogram struct {
+	sum   float64
+	count float64
+	// buckets cumulative counts by upper bound
+	buckets map[float64]float64
+}
+
+type schedulerThroughput struct {
+	BaseMeasurement
+	schedulerStart map[string]*histogram
+	samples        []any
+	summaries      []any
+}
+
+type schedulerThroughputMeasurementFactory struct {
+	BaseMeasurementFactory
+}
+
+func newSchedulerThroughputMeasurementFactory(configSpec config.Spec, measurement types.Measurement, metadata map[string]any) (MeasurementFactory, error) {
+	if err := verifyMeasurementConfig(measurement, supportedSchedulerThroughputConditions); err != nil {
+		return nil, err
+	}
+	if measurement.SchedulerNamespace == "" {
+		measurement.SchedulerNamespace = defaultSchedulerNs
+	}
+	if measurement.SchedulerLabelSelector == "" {
+		measurement.SchedulerLabelSelector = defaultSchedulerLabels
+	}
+	return schedulerThroughputMeasurementFactory{
+		BaseMeasurementFactory: NewBaseMeasurementFactory(configSpec, measurement, metadata),
+	}, nil
+}
+
+func (stmf schedulerThroughputMeasurementFactory) NewMeasurement(jobConfig *config.Job, clientSet kubernetes.Interface, restConfig *rest.Config, embedCfg *fileutils.EmbedConfiguration) Measurement {
+	return &schedulerThroughput{
+		BaseMeasurement: stmf.NewBaseLatency(jobConfig, clientSet, restConfig, schedulerThroughputMeasurement, schedulerThroughputQuantilesMeasurement, embedCfg),
+	}
+}
+
+func (s *schedulerThroughput) handleAdd(obj any) {
+	pod, ok := obj.(*corev1.Pod)
+	// Pods with nodeName set on creation bypass the scheduler
+	if !ok || pod.Spec.NodeName != "" {
+		return
+	}
+	s.metrics.LoadOrStore(string(pod.UID), schedulerPodMetric{created: time.Now().UTC()})
+}
+
+func (s *schedulerThroughput) handleUpdate(oldObj, newObj any) {
+	pod, ok := newObj.(*corev1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return
+	}
+	now := time.Now().UTC()
+	if value, exists := s.metrics.Load(string(pod.UID)); exists {
+		m := value.(schedulerPodMetric)
+		if m.scheduled.IsZero() {
+			m.scheduled = now
+			s.metrics.Store(string(pod.UID), m)
+		}
+	}
+}
+
+// Start watches the pods of the job and takes a snapshot of the kube-scheduler histograms
+func (s *schedulerThroughput) Start(measurementWg *sync.WaitGroup) error {
+	defer measurementWg.Done()
+	s.samples, s.summaries = nil, nil
+	s.schedulerStart = s.scrapeScheduler()
+	s.startMeasurement(
+		[]MeasurementWatcher{
+			{
+				restClient:    s.ClientSet.CoreV1().RESTClient().(*rest.RESTClient),
+				name:          "podWatcher",
+				resource:      "pods",
+				labelSelector: fmt.Sprintf("kube-burner-runid=%v", s.Runid),
+				handlers: &cache.ResourceEventHandlerFuncs{
+					AddFunc:    s.handleAdd,
+					UpdateFunc: s.handleUpdate,
+				},
+			},
+		},
+	)
+	return nil
+}
+
+func (s *schedulerThroughput) Collect(measurementWg *sync.WaitGroup) error {
+	log.Info("Collect method doesn't apply to schedulerThroughput by design")
+	defer measurementWg.Done()
+	return nil
+}
+
+// Stop computes the throughput timeseries and the scheduling breakdown of the job
+func (s *schedulerThroughput) Stop() error {
+	err := s.StopMeasurement(s.normalizeMetrics, s.getLatency)
+	s.summarize()
+	return err
+}
+
+func (s *schedulerThroughput) normalizeMetrics() float64 {
+	s.metrics.Range(func(key, value any) bool {
+		m := value.(schedulerPodMetric)
+		if !m.scheduled.IsZero() {
+			s.normLatencies = append(s.normLatencies, m)
+		}
+		return true
+	})
+	return 0
+}
+
+func (s *schedulerThroughput) getLatency(normLatency any) map[string]float64 {
+	m := normLatency.(schedulerPodMetric)
+	return map[string]float64{
+		podScheduledCondition: float64(m.scheduled.Sub(m.created).Milliseconds()),
+	}
+}
+
+// summarize builds the per-second throughput samples and the summary of the job
+func (s *schedulerThroughput) summarize() {
+	summary := schedulerThroughputSummary{
+		Timestamp:     time.Now().UTC(),
+		PodsScheduled: len(s.normLatencies),
+		MetricName:    schedulerThroughputSummaryMeasurement,
+		UUID:          s.Uuid,
+		JobName:       s.JobConfig.Name,
+		Metadata:      s.Metadata,
+	}
+	if len(s.normLatencies) > 0 {
+		first, last := time.Time{}, time.Time{}
+		perSecond := make(map[int64]int)
+		for _, normLatency := range s.normLatencies {
+			m := normLatency.(schedulerPodMetric)
+			if first.IsZero() || m.created.Before(first) {
+				first = m.created
+			}
+			if m.scheduled.After(last) {
+				last = m.scheduled
+			}
+			perSecond[m.scheduled.Unix()]++
+		}
+		// Seconds without scheduled pods are samples too, so that the timeseries shows the stalls of the scheduler
+		for second := first.Unix(); second <= last.Unix(); second++ {
+			s.samples = append(s.samples, schedulerThroughputSample{
+				Timestamp:  time.Unix(second, 0).UTC(),
+				Scheduled:  perSecond[second],
+				MetricName: schedulerThroughputMeasurement,
+				UUID:       s.Uuid,
+				JobName:    s.JobConfig.Name,
+				Metadata:   s.Metadata,
+			})
+			summary.PeakThroughput = max(summary.PeakThroughput, perSecond[second])
+		}
+		summary.Duration = math.Round(last.Sub(first).Seconds()*1000) / 1000
+		if summary.Duration > 0 {
+			summary.AvgThroughput = math.Round(float64(summary.PodsScheduled)/summary.Duration*1000) / 1000
+		}
+	}
+	if s.schedulerStart != nil {
+		if end := s.scrapeScheduler(); end != nil {
+			s.schedulerBreakdown(&summary, s.schedulerStart, end)
+		}
+	}
+	log.Infof("%s: %d pods scheduled, throughput avg: %v pods/s peak: %v pods/s", s.JobConfig.Name, summary.PodsScheduled, summary.AvgThroughput, summary.PeakThroughput)
+	if summary.E2EAvg > 0 {
+		log.Infof("%s: kube-scheduler e2e avg: %vms, queue wait avg: %vms, attempt avg: %vms, bind avg: %vms", s.JobConfig.Name, summary.E2EAvg, summary.QueueWaitAvg, summary.AttemptAvg, summary.BindAvg)
+	}
+	s.summaries = append(s.summaries, summary)
+}
+
+// schedulerBreakdown fills the summary with the increase of the kube-scheduler histograms during the job. Queue wait
+// is the scheduling time not spent in scheduling attempts
+func (s *schedulerThroughput) schedulerBreakdown(summary *schedulerThroughputSummary, start, end map[string]*histogram) {
+	increase := func(name string) *histogram {
+		if end[name] == nil {
+			return &histogram{}
+		}
+		h := &histogram{sum: end[name].sum, count: end[name].count, buckets: maps.Clone(end[name].buckets)}
+		if start[name] != nil {
+			h.sum -= start[name].sum
+			h.count -= start[name].count
+			for bound, count := range start[name].buckets {
+				h.buckets[bound] -= count
+			}
+		}
+		return h
+	}
+	avg := func(h *histogram) float64 {
+		if h.count <= 0 {
+			return 0
+		}
+		return math.Round(h.sum/h.count*1000*1000) / 1000
+	}
+	e2e, attempt, bind := increase("e2e"), increase("attempt"), increase("bind")
+	summary.E2EAvg, summary.AttemptAvg, summary.BindAvg = avg(e2e), avg(attempt), avg(bind)
+	summary.E2EP50, summary.E2EP99 = e2e.quantile(0.5), e2e.quantile(0.99)
+	summary.AttemptP50, summary.AttemptP99 = attempt.quantile(0.5), attempt.quantile(0.99)
+	summary.BindP50, summary.BindP99 = bind.quantile(0.5), bind.quantile(0.99)
+	if e2e.count > 0 {
+		summary.AttemptsPerPod = math.Round(attempt.count/e2e.count*1000) / 1000
+		summary.QueueWaitAvg = math.Round(max(e2e.sum-attempt.sum, 0)/e2e.count*1000*1000) / 1000
+	}
+}
+
+// quantile estimates the quantile in milliseconds from the buckets, interpolating linearly as histogram_quantile does
+func (h *histogram) quantile(q float64) float64 {
+	if h.count <= 0 {
+		return 0
+	}
+	bounds := make([]float64, 0, len(h.buckets))
+	for bound := range h.buckets {
+		bounds = append(bounds, bound)
+	}
+	sort.Float64s(bounds)
+	rank := q * h.count
+	var lowerBound, lowerCount float64
+	for _, bound := range bounds {
+		count := h.buckets[bound]
+		if count >= rank {
+			if math.IsInf(bound, 1) {
+				// The quantile falls in the +Inf bucket, its lower bound is the best estimate
+				return math.Round(lowerBound*1000*1000) / 1000
+			}
+			value := lowerBound
+			if count > lowerCount {
+				value += (bound - lowerBound) * (rank - lowerCount) / (count - lowerCount)
+			}
+			return math.Round(value*1000*1000) / 1000
+		}
+		lowerBound, lowerCount = bound, count
+	}
+	return math.Round(lowerBound*1000*1000) / 1000
+}
+
+// scrapeScheduler returns the kube-scheduler histograms summed across all its pods, nil when none can be scraped.
+// kube-scheduler only serves its metrics over HTTPS to authorized clients, so they're scraped through a
+// port-forward with the credentials of kube-burner
+func (s *schedulerThroughput) scrapeScheduler() map[string]*histogram {
+	pods, err := s.ClientSet.CoreV1().Pods(s.Config.SchedulerNamespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: s.Config.SchedulerLabelSelector,
+		FieldSelector: "status.phase=" + string(corev1.PodRunning),
+	})
+	if err != nil {
+		log.Warnf("Error listing kube-scheduler pods, scheduling breakdown disabled: %v", err)
+		return nil
+	}
+	if len(pods.Items) == 0 {
+		log.Warnf("No kube-scheduler pods match %s in namespace %s, scheduling breakdown disabled", s.Config.SchedulerLabelSelector, s.Config.SchedulerNamespace)
+		return nil
+	}
+	var scraped bool
+	histograms := make(map[string]*histogram)
+	for _, pod := range pods.Items {
+		data, err := s.scrapeSchedulerPod(pod)
+		if err != nil {
+			log.Warnf("Error scraping kube-scheduler pod %s/%s: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		var parser expfmt.TextParser
+		families, err := parser.TextToMetricFamilies(bytes.NewReader(data))
+		if err != nil {
+			log.Warnf("Error parsing kube-scheduler pod %s/%s metrics: %v", pod.Namespace, pod.Name, err)
+			continue
+		}
+		scraped = true
+		for name, metricNames := range schedulerHistograms {
+			for _, metricName := range metricNames {
+				family, ok := families[metricName]
+				if !ok {
+					continue
+				}
+				addHistogram(histograms, name, family)
+				break
+			}
+		}
+	}
+	if !scraped {
+		return nil
+	}
+	return histograms
+}
+
+// addHistogram adds the samples of the family to the named histogram, only the Bind extension point is kept from
+// the framework histograms
+func addHistogram(histograms map[string]*histogram, name string, family *dto.MetricFamily) {
+	h, ok := histograms[name]
+	if !ok {
+		h = &histogram{buckets: make(map[float64]float64)}
+		histograms[name] = h
+	}
+	for _, metric := range family.GetMetric() {
+		if name == "bind" && !slices.ContainsFunc(metric.GetLabel(), func(label *dto.LabelPair) bool {
+			return label.GetName() == "extension_point" && label.GetValue() == "Bind"
+		}) {
+			continue
+		}
+		hist := metric.GetHistogram()
+		h.sum += hist.GetSampleSum()
+		h.count += float64(hist.GetSampleCount())
+		var inf bool
+		for _, bucket := range hist.GetBucket() {
+			h.buckets[bucket.GetUpperBound()] += float64(bucket.GetCumulativeCount())
+			inf = inf || math.IsInf(bucket.GetUpperBound(), 1)
+		}
+		if !inf {
+			h.buckets[math.Inf(1)] += float64(hist.GetSampleCount())
+		}
+	}
+}
+
+// scrapeSchedulerPod gets the metrics of a kube-scheduler pod through a port-forward
+func (s *schedulerThroughput) scrapeSchedulerPod(pod corev1.Pod) ([]byte, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	localPort := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	forwarder, err := util.NewPodPortForwarder(s.ClientSet, *s.RestConfig, fmt.Sprintf("%d:%d", localPort, schedulerMetricsPort), pod.Namespace, pod.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer forwarder.CancelPodPortForwarder()
+	// The serving certificate of kube-scheduler is self-signed, the credentials are still those of the kubeconfig
+	restConfig := rest.CopyConfig(s.RestConfig)
+	restConfig.Host = fmt.Sprintf("https://127.0.0.1:%d", localPort)
+	restConfig.Insecure = true
+	restConfig.CAFile, restConfig.CAData = "", nil
+	restConfig.Timeout = 30 * time.Second
+	client, err := rest.HTTPClientFor(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Get(restConfig.Host + "/metrics")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// Index indexes the throughput timeseries, the scheduling quantiles and the summary of the job
+func (s *schedulerThroughput) Index(jobName string, indexerList map[string]indexers.Indexer) error {
+	metricMap := map[string][]any{
+		s.MeasurementName:                     s.samples,
+		s.QuantilesMeasurementName:            s.latencyQuantiles,
+		schedulerThroughputSummaryMeasurement: s.summaries,
+	}
+	return s.indexLatencyMeasurement(jobName, metricMap, indexerList)
+}
@@ -17,6 +17,7 @@ package measurements
 import (
 	"context"
 	"fmt"
+	"maps"
 	"sync"
 	"time"
 
@@ -28,9 +29,12 @@ import (
 	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
 	log "github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	lcorev1 "k8s.io/client-go/listers/core/v1"
+	ldiscoveryv1 "k8s.io/client-go/listers/discovery/v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 )
@@ -43,21 +47,30 @@ const (
 type serviceLatency struct {
 	BaseMeasurement
 
-	epLister  lcorev1.EndpointsLister
-	svcLister lcorev1.ServiceLister
+	epLister    lcorev1.EndpointsLister
+	svcLister   lcorev1.ServiceLister
+	sliceLister ldiscoveryv1.EndpointSliceLister
+	podLister   lcorev1.PodLister
+	// ctx cancelled when the measurement stops, ending the wait for the EndpointSlices convergence
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 type svcMetric struct {
-	Timestamp         time.Time          `json:"timestamp"`
-	IPAssignedLatency time.Duration      `json:"ipAssigned,omitempty"`
-	ReadyLatency      time.Duration      `json:"ready"`
-	MetricName        string             `json:"metricName"`
-	UUID              string             `json:"uuid"`
-	Namespace         string             `json:"namespace"`
-	Name              string             `json:"service"`
-	ServiceType       corev1.ServiceType `json:"type"`
-	JobName           string             `json:"jobName,omitempty"`
-	Metadata          any                `json:"metadata,omitempty"`
+	Timestamp         time.Time     `json:"timestamp"`
+	IPAssignedLatency time.Duration `json:"ipAssigned,omitempty"`
+	ReadyLatency      time.Duration `json:"ready"`
+	// TrafficLatency time since the service was created until its traffic works
+	TrafficLatency time.Duration `json:"trafficReady,omitempty"`
+	// EndpointSlicesLatency time since the service was created until its EndpointSlices hold all its ready pods
+	EndpointSlicesLatency time.Duration      `json:"endpointSlicesConverged,omitempty"`
+	MetricName            string             `json:"metricName"`
+	UUID                  string             `json:"uuid"`
+	Namespace             string             `json:"namespace"`
+	Name                  string             `json:"service"`
+	ServiceType           corev1.ServiceType `json:"type"`
+	JobName               string             `json:"jobName,omitempty"`
+	Metadata              any                `json:"metadata,omitempty"`
 }
 
 type serviceLatencyMeasurementFactory struct {
@@ -92,8 +105,21 @@ func (s *serviceLatency) handleCreateSvc(obj any) {
 	go func(svc *corev1.Service) {
 		var ips []string
 		var port int32
-		var ipAssignedLatency time.Duration
+		var ipAssignedLatency, endpointSlicesLatency time.Duration
+		var slicesWg sync.WaitGroup
 		now := time.Now()
+		// EndpointSlices may converge while the connectivity is checked
+		if len(svc.Spec.Selector) > 0 {
+			slicesWg.Add(1)
+			go func() {
+				defer slicesWg.Done()
+				if err := s.waitForEndpointSlices(svc); err != nil {
+					log.Debugf("EndpointSlices of service %v/%v didn't converge: %v", svc.Namespace, svc.Name, err)
+					return
+				}
+				endpointSlicesLatency = time.Since(now)
+			}()
+		}
 		// If service is loadbalancer first wait for the IP assignment
 		if svc.Spec.Type == corev1.ServiceTypeLoadBalancer {
 			if err := s.waitForIngress(svc); err != nil {
@@ -142,18 +168,22 @@ func (s *serviceLatency) handleCreateSvc(obj any) {
 			}
 		}
 		svcLatency := time.Since(endpointsReadyTs)
+		trafficLatency := time.Since(now)
 		log.Debugf("Service %v/%v latency was: %vms", svc.Namespace, svc.Name, svcLatency.Milliseconds())
+		slicesWg.Wait()
 		s.metrics.Store(string(svc.UID), svcMetric{
-			Name:              svc.Name,
-			Namespace:         svc.Namespace,
-			Timestamp:         svc.CreationTimestamp.UTC(),
-			MetricName:        svcLatencyMeasurement,
-			ServiceType:       svc.Spec.Type,
-			ReadyLatency:      svcLatency,
-			UUID:              s.Uuid,
-			IPAssignedLatency: ipAssignedLatency,
-			JobName:           s.JobConfig.Name,
-			Metadata:          s.Metadata,
+			Name:                  svc.Name,
+			Namespace:             svc.Namespace,
+			Timestamp:             svc.CreationTimestamp.UTC(),
+			MetricName:            svcLatencyMeasurement,
+			ServiceType:           svc.Spec.Type,
+			ReadyLatency:          svcLatency,
+			TrafficLatency:        trafficLatency,
+			EndpointSlicesLatency: endpointSlicesLatency,
+			UUID:                  s.Uuid,
+			IPAssignedLatency:     ipAssignedLatency,
+			JobName:               s.JobConfig.Name,
+			Metadata:              s.Metadata,
 		})
 	}(svc)
 }
@@ -162,6 +192,7 @@ func (s *serviceLatency) handleCreateSvc(obj any) {
 func (s *serviceLatency) Start(measurementWg *sync.WaitGroup) error {
 	// Reset latency slices, required in multi-job benchmarks
 	s.latencyQuantiles, s.normLatencies = nil, nil
+	s.ctx, s.cancel = context.WithCancel(context.Background())
 	defer measurementWg.Done()
 	err := deployPodInNamespace(s.ClientSet, types.SvcLatencyNs, types.SvcLatencyCheckerName, "quay.io/cloud-bulldozer/fedora-nc:latest", []string{"sleep", "inf"})
 	if err != nil {
@@ -185,10 +216,25 @@ func (s *serviceLatency) Start(measurementWg *sync.WaitGroup) error {
 				labelSelector: "",
 				handlers:      nil,
 			},
+			{
+				restClient: s.ClientSet.DiscoveryV1().RESTClient().(*rest.RESTClient),
+				name:       "endpointSliceWatcher",
+				resource:   "endpointslices",
+				handlers:   nil,
+			},
+			{
+				restClient:    s.ClientSet.CoreV1().RESTClient().(*rest.RESTClient),
+				name:          "svcPodWatcher",
+				resource:      "pods",
+				labelSelector: fmt.Sprintf("kube-burner-runid=%v", s.Runid),
+				handlers:      nil,
+			},
 		},
 	)
 	s.svcLister = lcorev1.NewServiceLister(s.watchers[0].Informer.GetIndexer())
 	s.epLister = lcorev1.NewEndpointsLister(s.watchers[1].Informer.GetIndexer())
+	s.sliceLister = ldiscoveryv1.NewEndpointSliceLister(s.watchers[2].Informer.GetIndexer())
+	s.podLister = lcorev1.NewPodLister(s.watchers[3].Informer.GetIndexer())
 	return nil
 }
 
@@ -197,6 +243,7 @@ func (s *serviceLatency) Stop() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer func() {
 		cancel()
+		s.cancel()
 		s.stopWatchers()
 	}()
 	kutil.CleanupNamespaces(ctx, s.ClientSet, fmt.Sprintf("kubernetes.io/metadata.name=%s", types.SvcLatencyNs))
@@ -211,16 +258,20 @@ func (s *serviceLatency) Stop() error {
 
 func (s *serviceLatency) normalizeMetrics() {
 	var latencies []float64
-	var ipAssignedLatencies []float64
+	var ipAssignedLatencies, trafficLatencies, endpointSlicesLatencies []float64
 	sLen := 0
 	s.metrics.Range(func(key, value any) bool {
 		sLen++
 		metric := value.(svcMetric)
 		latencies = append(latencies, float64(metric.ReadyLatency))
+		trafficLatencies = append(trafficLatencies, float64(metric.TrafficLatency))
 		s.normLatencies = append(s.normLatencies, metric)
 		if metric.IPAssignedLatency != 0 {
 			ipAssignedLatencies = append(ipAssignedLatencies, float64(metric.IPAssignedLatency))
 		}
+		if metric.EndpointSlicesLatency != 0 {
+			endpointSlicesLatencies = append(endpointSlicesLatencies, float64(metric.EndpointSlicesLatency))
+		}
 		return true
 	})
 	calcSummary := func(name string, inputLatencies []float64) metrics.LatencyQuantiles {
@@ -230,10 +281,15 @@ func (s *serviceLatency) normalizeMetrics() {
 		latencySummary.Metadata = s.Metadata
 		latencySummary.MetricName = svcLatencyQuantilesMeasurement
 		latencySummary.JobName = s.JobConfig.Name
+		latencySummary.Latencies = s.workerLatencies(inputLatencies)
 		return latencySummary
 	}
 	if sLen > 0 {
 		s.latencyQuantiles = append(s.latencyQuantiles, calcSummary("Ready", latencies))
+		s.latencyQuantiles = append(s.latencyQuantiles, calcSummary("TrafficReady", trafficLatencies))
+	}
+	if len(endpointSlicesLatencies) > 0 {
+		s.latencyQuantiles = append(s.latencyQuantiles, calcSummary("EndpointSlicesConverged", endpointSlicesLatencies))
 	}
 	if len(ipAssignedLatencies) > 0 {
 		s.latencyQuantiles = append(s.latencyQuantiles, calcSummary("IPAssigned", ipAssignedLatencies))
@@ -256,6 +312,47 @@ func (s *serviceLatency) waitForEndpoints(svc *corev1.Service) error {
 	return err
 }
 
+// waitForEndpointSlices waits until the ready endpoints of the EndpointSlices of the service are the ready pods it selects
+func (s *serviceLatency) waitForEndpointSlices(svc *corev1.Service) error {
+	podSelector := labels.SelectorFromSet(svc.Spec.Selector)
+	sliceSelector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: svc.Name})
+	return wait.PollUntilContextCancel(s.ctx, 100*time.Millisecond, true, func(ctx context.Context) (done bool, err error) {
+		pods, err := s.podLister.Pods(svc.Namespace).List(podSelector)
+		if err != nil {
+			return false, nil
+		}
+		readyPods := make(map[string]struct{})
+		for _, pod := range pods {
+			if pod.DeletionTimestamp == nil && pod.Status.PodIP != "" && isPodReady(pod) {
+				readyPods[pod.Name] = struct{}{}
+			}
+		}
+		slices, err := s.sliceLister.EndpointSlices(svc.Namespace).List(sliceSelector)
+		if err != nil {
+			return false, nil
+		}
+		// Dual-stack services have a slice per address family holding the same pods
+		readyEndpoints := make(map[string]struct{})
+		for _, slice := range slices {
+			for _, endpoint := range slice.Endpoints {
+				if endpoint.TargetRef != nil && endpoint.TargetRef.Kind == "Pod" && endpoint.Conditions.Ready != nil && *endpoint.Conditions.Ready {
+					readyEndpoints[endpoint.TargetRef.Name] = struct{}{}
+				}
+			}
+		}
+		return len(readyPods) > 0 && maps.Equal(readyPods, readyEndpoints), nil
+	})
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
 func (s *serviceLatency) waitForIngress(svc *corev1.Service) error {
 	err := wait.PollUntilContextCancel(context.TODO(), 100*time.Millisecond, true, func(ctx context.Context) (done bool, err error) {
 		svc, err := s.svcLister.Services(svc.Namespace).Get(svc.Name)
@@ -270,6 +367,7 @@ func (s *serviceLatency) waitForIngress(svc *corev1.Service) error {
 	return err
 }
 
-func (s *serviceLatency) Collect(measurementWg *sync.WaitGroup) {
+func (s *serviceLatency) Collect(measurementWg *sync.WaitGroup) error {
 	defer measurementWg.Done()
+	return nil
 }
@@ -0,0 +1,151 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/measurements/metrics"
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	"github.com/kube-burner/kube-burner/pkg/util/junit"
+	log "github.com/sirupsen/logrus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+const sloEvaluationMeasurement = "sloEvaluation"
+
+// Statistics of the latency quantiles an SLO can apply to
+var sloMetrics = []string{"P50", "P95", "P99", "Avg", "Max"}
+
+// sloEvaluation result of evaluating an SLO against the global quantiles of its condition, latencies in milliseconds
+type sloEvaluation struct {
+	Timestamp     time.Time         `json:"timestamp"`
+	MetricName    string            `json:"metricName"`
+	UUID          string            `json:"uuid"`
+	JobName       string            `json:"jobName,omitempty"`
+	Measurement   string            `json:"measurement"`
+	ConditionType string            `json:"conditionType"`
+	Metric        string            `json:"metric"`
+	Value         int               `json:"value"`
+	Threshold     int64             `json:"threshold"`
+	Severity      types.SLOSeverity `json:"severity"`
+	Passed        bool              `json:"passed"`
+	Metadata      any               `json:"metadata,omitempty"`
+}
+
+func validateSLOs(measurement *types.Measurement) error {
+	for i := range measurement.SLOs {
+		slo := &measurement.SLOs[i]
+		if slo.ConditionType == "" {
+			return fmt.Errorf("measurement %s: SLO conditionType is required", measurement.Name)
+		}
+		if !slices.Contains(sloMetrics, slo.Metric) {
+			return fmt.Errorf("measurement %s: invalid SLO metric %q, valid values are %v", measurement.Name, slo.Metric, sloMetrics)
+		}
+		if slo.Threshold <= 0 {
+			return fmt.Errorf("measurement %s: SLO threshold of %s %s must be positive", measurement.Name, slo.Metric, slo.ConditionType)
+		}
+		switch slo.Severity {
+		case "":
+			slo.Severity = types.SLOFail
+		case types.SLOWarn, types.SLOFail:
+		default:
+			return fmt.Errorf("measurement %s: invalid SLO severity %q, valid values are %s and %s", measurement.Name, slo.Severity, types.SLOWarn, types.SLOFail)
+		}
+	}
+	return nil
+}
+
+// sloReporter is implemented by the measurements evaluating SLOs
+type sloReporter interface {
+	sloEvaluations() []sloEvaluation
+}
+
+// sloEvaluations evaluates the configured SLOs against the global latency quantiles of the measurement
+func (bm *BaseMeasurement) sloEvaluations() []sloEvaluation {
+	var evaluations []sloEvaluation
+	for _, slo := range bm.Config.SLOs {
+		idx := slices.IndexFunc(bm.latencyQuantiles, func(q any) bool {
+			lq, ok := q.(metrics.LatencyQuantiles)
+			return ok && lq.Group == "" && lq.QuantileName == slo.ConditionType
+		})
+		if idx < 0 {
+			log.Warnf("%s: no %s latencies to evaluate the SLO against", bm.Config.Name, slo.ConditionType)
+			continue
+		}
+		value := int(reflect.ValueOf(bm.latencyQuantiles[idx]).FieldByName(slo.Metric).Int())
+		evaluations = append(evaluations, sloEvaluation{
+			Measurement:   bm.Config.Name,
+			ConditionType: slo.ConditionType,
+			Metric:        slo.Metric,
+			Value:         value,
+			Threshold:     slo.Threshold.Milliseconds(),
+			Severity:      slo.Severity,
+			Passed:        int64(value) <= slo.Threshold.Milliseconds(),
+		})
+	}
+	return evaluations
+}
+
+// evaluateSLOs gathers the SLO evaluations of the measurements, violated warn SLOs are logged and recorded as warnings
+// of the job, returns an error when any fail SLO is violated
+func (ms *Measurements) evaluateSLOs() error {
+	var errs []error
+	ms.sloDocuments = nil
+	ms.sloWarnings = nil
+	var evaluations []sloEvaluation
+	for _, measurement := range ms.MeasurementsMap {
+		if sr, ok := measurement.(sloReporter); ok {
+			evaluations = append(evaluations, sr.sloEvaluations()...)
+		}
+	}
+	slices.SortFunc(evaluations, func(a, b sloEvaluation) int {
+		return cmp.Or(cmp.Compare(a.Measurement, b.Measurement), cmp.Compare(a.ConditionType, b.ConditionType), cmp.Compare(a.Metric, b.Metric))
+	})
+	timestamp := time.Now().UTC()
+	for _, evaluation := range evaluations {
+		evaluation.Timestamp = timestamp
+		evaluation.MetricName = sloEvaluationMeasurement
+		evaluation.UUID = ms.uuid
+		evaluation.JobName = ms.jobName
+		evaluation.Metadata = ms.metadata
+		ms.sloDocuments = append(ms.sloDocuments, evaluation)
+		testCase := fmt.Sprintf("%s %s %s %s <= %dms", ms.jobName, evaluation.Measurement, evaluation.Metric, evaluation.ConditionType, evaluation.Threshold)
+		if evaluation.Passed {
+			junit.AddTestCase(junit.SuiteThresholds, testCase, 0, nil)
+			continue
+		}
+		violation := fmt.Sprintf("%s: %s %s latency (%dms) higher than SLO: %dms", evaluation.Measurement, evaluation.Metric, evaluation.ConditionType, evaluation.Value, evaluation.Threshold)
+		if evaluation.Severity == types.SLOWarn {
+			log.Warn(violation)
+			ms.sloWarnings = append(ms.sloWarnings, violation)
+			junit.AddTestCase(junit.SuiteThresholds, testCase, 0, nil)
+			continue
+		}
+		err := fmt.Errorf("%s", violation)
+		errs = append(errs, err)
+		junit.AddTestCase(junit.SuiteThresholds, testCase, 0, err)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// SLOWarnings returns the violations of the warn SLOs of the stopped measurements
+func (ms *Measurements) SLOWarnings() []string {
+	return ms.sloWarnings
+}
@@ -25,6 +25,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
@@ -143,7 +144,7 @@ func (vsl *volumeSnapshotLatency) Stop() error {
 	return vsl.StopMeasurement(vsl.normalizeMetrics, vsl.getLatency)
 }
 
-func (vsl *volumeSnapshotLatency) Collect(measurementWg *sync.WaitGroup) {
+func (vsl *volumeSnapshotLatency) Collect(measurementWg *sync.WaitGroup) error {
 	defer measurementWg.Done()
 	var volumeSnapshots []volumesnapshotv1.VolumeSnapshot
 	labelSelector := labels.SelectorFromSet(vsl.JobConfig.NamespaceLabels)
@@ -152,13 +153,15 @@ func (vsl *volumeSnapshotLatency) Collect(measurementWg *sync.WaitGroup) {
 	}
 	kubeVirtClient, err := kubecli.GetKubevirtClientFromRESTConfig(vsl.RestConfig)
 	if err != nil {
-		log.Fatalf("Failed to get kubevirt client - %v", err)
+		return fmt.Errorf("failed to get kubevirt client: %v", err)
 	}
+	var errs []error
 	namespaces := strings.Split(vsl.JobConfig.Namespace, ",")
 	for _, namespace := range namespaces {
 		vsList, err := kubeVirtClient.KubernetesSnapshotClient().SnapshotV1().VolumeSnapshots(namespace).List(context.TODO(), options)
 		if err != nil {
-			log.Errorf("error listing volumeSnapshots in namespace %s: %v", namespace, err)
+			errs = append(errs, fmt.Errorf("error listing volumeSnapshots in namespace %s: %v", namespace, err))
+			continue
 		}
 		volumeSnapshots = append(volumeSnapshots, vsList.Items...)
 	}
@@ -174,6 +177,7 @@ func (vsl *volumeSnapshotLatency) Collect(measurementWg *sync.WaitGroup) {
 			JobName:    vsl.JobConfig.Name,
 		})
 	}
+	return utilerrors.NewAggregate(errs)
 }
 
 func (vsl *volumeSnapshotLatency) normalizeMetrics() float64 {
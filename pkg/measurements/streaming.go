@@ -0,0 +1,234 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package measurements
+
+import (
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/measurements/types"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultStreamingFlushInterval = 30 * time.Second
+	defaultStreamingBufferSize    = 1000
+)
+
+// Measurements supporting streaming
+var streamingMeasurements = []string{"podLatency", "jobLatency", "nodeLatency"}
+
+// streamNormalizer returns the function normalizing the metric of an object, reporting whether the object completed
+// along with its error flag. It's called before each flush, so that what it depends on is refreshed
+type streamNormalizer func() func(value any) (doc any, errorFlag int, complete bool)
+
+// streamingMeasurement is implemented by the measurements embedding BaseMeasurement
+type streamingMeasurement interface {
+	setStreamIndexers(jobName string, indexerList map[string]indexers.Indexer)
+}
+
+// latencyStream indexes the documents of the completed objects during the job, keeping only their latencies for the
+// quantiles calculated once the measurement stops
+type latencyStream struct {
+	config     types.Streaming
+	jobName    string
+	indexers   map[string]indexers.Indexer
+	normalizer streamNormalizer
+	getLatency func(any) map[string]float64
+	// completed objects since the last flush
+	completed atomic.Int64
+	flushCh   chan struct{}
+	stopCh    chan struct{}
+	doneCh    chan struct{}
+	buffer    []any
+	// part number of the next indexed chunk of documents
+	part int
+	// streamed and errored documents, accounted in the error rate of the measurement
+	streamed  int
+	errored   int
+	latencies map[quantileKey][]float64
+	// flushed keys of the tracked metrics already streamed, objects tracked again afterwards, like after a relist,
+	// aren't indexed twice
+	flushed map[any]struct{}
+	errs    []error
+}
+
+func validateStreaming(measurement *types.Measurement) error {
+	if !slices.Contains(streamingMeasurements, measurement.Name) {
+		return fmt.Errorf("measurement %s doesn't support streaming", measurement.Name)
+	}
+	if measurement.Streaming.FlushInterval == 0 {
+		measurement.Streaming.FlushInterval = defaultStreamingFlushInterval
+	}
+	if measurement.Streaming.BufferSize == 0 {
+		measurement.Streaming.BufferSize = defaultStreamingBufferSize
+	}
+	if measurement.Streaming.FlushInterval < 0 || measurement.Streaming.BufferSize < 0 {
+		return fmt.Errorf("measurement %s: streaming flushInterval and bufferSize must be positive", measurement.Name)
+	}
+	return nil
+}
+
+// StreamTo enables the streaming of the measurements configured to stream their documents to the given indexers,
+// it must be called before the measurements start
+func (ms *Measurements) StreamTo(indexerList map[string]indexers.Indexer) {
+	for _, measurement := range ms.MeasurementsMap {
+		if sm, ok := measurement.(streamingMeasurement); ok {
+			sm.setStreamIndexers(ms.jobName, ms.stampDisruptions(indexerList))
+		}
+	}
+}
+
+func (bm *BaseMeasurement) setStreamIndexers(jobName string, indexerList map[string]indexers.Indexer) {
+	if bm.Config.Streaming == nil {
+		return
+	}
+	bm.streamJobName, bm.streamIndexers = jobName, indexerList
+}
+
+// prepareStream prepares the stream of the measurement when enabled, it's started along with the watchers
+func (bm *BaseMeasurement) prepareStream(normalizer streamNormalizer, getLatency func(any) map[string]float64) {
+	bm.stream = nil
+	if bm.Config.Streaming == nil || len(bm.streamIndexers) == 0 {
+		return
+	}
+	indexerList := bm.streamIndexers
+	if bm.Config.TimeseriesIndexer != "" {
+		indexerList = map[string]indexers.Indexer{bm.Config.TimeseriesIndexer: bm.streamIndexers[bm.Config.TimeseriesIndexer]}
+	}
+	bm.stream = &latencyStream{
+		config:     *bm.Config.Streaming,
+		jobName:    bm.streamJobName,
+		indexers:   indexerList,
+		normalizer: normalizer,
+		getLatency: getLatency,
+		flushCh:    make(chan struct{}, 1),
+		stopCh:     make(chan struct{}),
+		doneCh:     make(chan struct{}),
+		latencies:  map[quantileKey][]float64{},
+		flushed:    map[any]struct{}{},
+	}
+}
+
+// runStream collects the completed objects every flush interval, or as soon as the buffer size is reached
+func (bm *BaseMeasurement) runStream() {
+	s := bm.stream
+	log.Infof("Streaming %s documents of job %s every %v or %d documents", bm.MeasurementName, s.jobName, s.config.FlushInterval, s.config.BufferSize)
+	defer close(s.doneCh)
+	ticker := time.NewTicker(s.config.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+		case <-s.flushCh:
+		}
+		bm.collectStream()
+	}
+}
+
+// objectCompleted counts an object whose metric is complete, triggering a flush once the buffer size is reached
+func (bm *BaseMeasurement) objectCompleted() {
+	if bm.stream == nil || bm.stream.completed.Add(1) < int64(bm.stream.config.BufferSize) {
+		return
+	}
+	select {
+	case bm.stream.flushCh <- struct{}{}:
+	default:
+	}
+}
+
+// collectStream normalizes the completed objects and indexes their documents in chunks of the buffer size, they're
+// then dropped from the tracked metrics
+func (bm *BaseMeasurement) collectStream() {
+	s := bm.stream
+	s.completed.Store(0)
+	normalize := s.normalizer()
+	bm.metrics.Range(func(key, value any) bool {
+		if _, ok := s.flushed[key]; ok {
+			bm.metrics.Delete(key)
+			return true
+		}
+		doc, errorFlag, complete := normalize(value)
+		if !complete {
+			return true
+		}
+		bm.metrics.Delete(key)
+		s.flushed[key] = struct{}{}
+		s.streamed++
+		s.errored += errorFlag
+		bm.addLatencies(s.latencies, doc, s.getLatency)
+		s.buffer = append(s.buffer, doc)
+		if len(s.buffer) >= s.config.BufferSize {
+			bm.flushStream()
+		}
+		return true
+	})
+	bm.flushStream()
+}
+
+func (bm *BaseMeasurement) flushStream() {
+	s := bm.stream
+	if len(s.buffer) == 0 {
+		return
+	}
+	if err := bm.indexLatencyMeasurement(s.jobName, map[string][]any{bm.MeasurementName: s.buffer}, s.indexers); err != nil {
+		log.Errorf("%s: error streaming documents: %v", bm.MeasurementName, err)
+		s.errs = append(s.errs, err)
+	}
+	s.buffer = nil
+}
+
+// stopStream stops the stream, the objects that completed since the last flush are indexed along with the rest once
+// the measurement stops, those tracked again after being streamed are dropped
+func (bm *BaseMeasurement) stopStream() {
+	if bm.stream == nil {
+		return
+	}
+	close(bm.stream.stopCh)
+	<-bm.stream.doneCh
+	for key := range bm.stream.flushed {
+		bm.metrics.Delete(key)
+	}
+	log.Infof("%s: %d documents streamed in %d parts", bm.MeasurementName, bm.stream.streamed, bm.stream.part)
+}
+
+// streamErrors returns the errors indexing the streamed documents
+func (bm *BaseMeasurement) streamErrors() []error {
+	if bm.stream == nil {
+		return nil
+	}
+	return bm.stream.errs
+}
+
+// partName returns the name of the next chunk of documents, each chunk is indexed under its own name so that the
+// indexers writing a file per metric don't overwrite the previous ones
+func (s *latencyStream) partName(metricName string) string {
+	s.part++
+	return fmt.Sprintf("%s-%s-%05d", metricName, s.jobName, s.part)
+}
+
+// errorRate returns the error rate of the streamed documents along with those normalized when the measurement stops
+func (s *latencyStream) errorRate(errorRate float64, normalized int) float64 {
+	total := s.streamed + normalized
+	if total == 0 {
+		return 0
+	}
+	return (float64(s.errored) + errorRate/100*float64(normalized)) / float64(total) * 100
+}
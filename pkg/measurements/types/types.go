@@ -28,6 +28,7 @@ func (m *Measurement) UnmarshalMeasurement(unmarshal func(any) error) error {
 	measurement := rawMeasurement{
 		PProfDirectory: pprofDirectory,
 		ServiceTimeout: 5 * time.Second,
+		MaxClockSkew:   time.Second,
 	}
 	if err := unmarshal(&measurement); err != nil {
 		return err
@@ -42,18 +43,64 @@ type Measurement struct {
 	Name string `yaml:"name"`
 	// LatencyThresholds config
 	LatencyThresholds []LatencyThreshold `yaml:"thresholds"`
+	// SLOs service level objectives of the latency quantiles, evaluated when the measurement stops
+	SLOs []SLO `yaml:"slos"`
+	// QualityThresholds maximum percentage accepted for each data-quality check
+	QualityThresholds map[string]float64 `yaml:"qualityThresholds"`
 	// PPRofTargets targets config
 	PProfTargets []PProftarget `yaml:"pprofTargets"`
 	// PPRofInterval pprof collect interval
 	PProfInterval time.Duration `yaml:"pprofInterval"`
 	// PProfDirectory output directory
 	PProfDirectory string `yaml:"pprofDirectory"`
+	// PProfIndexer alias of the local indexer whose metrics directory, and tarball, receives the pprof files
+	PProfIndexer string `yaml:"pprofIndexer"`
 	// Service latency endpoint timeout
 	ServiceTimeout time.Duration `yaml:"svcTimeout"`
 	// Defines the indexer for quantile metrics
 	QuantilesIndexer string `yaml:"quantilesIndexer"`
 	// Defines the indexer for timeseries
 	TimeseriesIndexer string `yaml:"timeseriesIndexer"`
+	// GroupBy label used to calculate additional per-group quantiles
+	GroupBy string `yaml:"groupBy"`
+	// TimelineSampleRate percentage of pods whose complete condition timeline is indexed
+	TimelineSampleRate float64 `yaml:"timelineSampleRate"`
+	// ClockSkewCorrection corrects the kubelet reported timestamps with the clock offset of each node
+	ClockSkewCorrection bool `yaml:"clockSkewCorrection"`
+	// MaxClockSkew maximum node clock offset before flagging the node as skewed
+	MaxClockSkew time.Duration `yaml:"maxClockSkew"`
+	// CorednsNamespace namespace of the CoreDNS pods scraped by the dnsLatency measurement
+	CorednsNamespace string `yaml:"corednsNamespace"`
+	// CorednsLabelSelector label selector of the CoreDNS pods scraped by the dnsLatency measurement
+	CorednsLabelSelector string `yaml:"corednsLabelSelector"`
+	// SchedulerNamespace namespace of the kube-scheduler pods scraped by the schedulerThroughput measurement
+	SchedulerNamespace string `yaml:"schedulerNamespace"`
+	// SchedulerLabelSelector label selector of the kube-scheduler pods scraped by the schedulerThroughput measurement
+	SchedulerLabelSelector string `yaml:"schedulerLabelSelector"`
+	// CRIStatsInterval how often the criStats measurement samples the container runtime stats
+	CRIStatsInterval time.Duration `yaml:"criStatsInterval"`
+	// CRIAgentImage image of the criStats node agents
+	CRIAgentImage string `yaml:"criAgentImage"`
+	// KubeletStatsInterval how often the kubeletStats measurement scrapes the kubelets
+	KubeletStatsInterval time.Duration `yaml:"kubeletStatsInterval"`
+	// KubeletStatsNodeSelector label selector of the nodes whose kubelets are scraped, all by default
+	KubeletStatsNodeSelector string `yaml:"kubeletStatsNodeSelector"`
+	// EtcdNamespace namespace of the etcd pods the etcdHealth measurement runs etcdctl in
+	EtcdNamespace string `yaml:"etcdNamespace"`
+	// EtcdLabelSelector label selector of the etcd pods
+	EtcdLabelSelector string `yaml:"etcdLabelSelector"`
+	// EtcdctlArgs etcdctl arguments selecting the endpoint and the client certificates
+	EtcdctlArgs []string `yaml:"etcdctlArgs"`
+	// EtcdHealthInterval how often the status of the etcd members is sampled
+	EtcdHealthInterval time.Duration `yaml:"etcdHealthInterval"`
+	// Plugin external executable implementing the measurement, whose name is then free
+	Plugin *MeasurementPlugin `yaml:"plugin"`
+	// Streaming indexes the documents of the objects as they complete, instead of holding them until the job finishes
+	Streaming *Streaming `yaml:"streaming"`
+	// Derived measurement computed from the latencies of another measurement, whose name is then free
+	Derived *DerivedMeasurement `yaml:"derived"`
+	// Histogram indexes the latency histogram of each condition along with its quantiles
+	Histogram *Histogram `yaml:"histogram"`
 }
 
 // LatencyThreshold holds the thresholds configuration
@@ -66,10 +113,48 @@ type LatencyThreshold struct {
 	Threshold time.Duration `yaml:"threshold"`
 }
 
+// SLOSeverity what a violated SLO does
+type SLOSeverity string
+
+const (
+	// SLOWarn logs the violation and records it in the job summary
+	SLOWarn SLOSeverity = "warn"
+	// SLOFail fails the measurement, hence the run
+	SLOFail SLOSeverity = "fail"
+)
+
+// SLO objective of a latency quantile of a condition
+type SLO struct {
+	// ConditionType quantile name the objective applies to, like Ready
+	ConditionType string `yaml:"conditionType"`
+	// Metric statistic of the quantile: P50, P95, P99, Avg or Max
+	Metric string `yaml:"metric"`
+	// Threshold maximum latency accepted
+	Threshold time.Duration `yaml:"threshold"`
+	// Severity of a violation, fail by default
+	Severity SLOSeverity `yaml:"severity"`
+}
+
+// PProfMode how kube-burner reaches the pprof endpoint of a target
+type PProfMode string
+
+const (
+	// PProfExec runs curl in the target pods
+	PProfExec PProfMode = "exec"
+	// PProfDirect fetches the target URL from kube-burner, paths are fetched from the API server
+	PProfDirect PProfMode = "direct"
+	// PProfPortForward fetches the target URL through a port-forward to each target pod
+	PProfPortForward PProfMode = "portForward"
+)
+
 // PProftarget pprof targets to collect
 type PProftarget struct {
 	// Name pprof target name
 	Name string `yaml:"name"`
+	// Mode exec, direct or portForward, exec by default
+	Mode PProfMode `yaml:"mode"`
+	// Port pod port forwarded in portForward mode, the port of the URL by default
+	Port int `yaml:"port"`
 	// Namespace pod namespace
 	Namespace string `yaml:"namespace"`
 	// LabelSelector get pprof from pods with these labels
@@ -88,6 +173,46 @@ type PProftarget struct {
 	Key string `yaml:"key"`
 }
 
+// MeasurementPlugin external executable run during each job, whose JSON output is indexed
+type MeasurementPlugin struct {
+	// Command executable and its arguments
+	Command []string `yaml:"command"`
+	// Config configuration passed to the plugin as JSON through its standard input
+	Config map[string]any `yaml:"config"`
+	// Env extra environment variables of the plugin
+	Env map[string]string `yaml:"env"`
+	// StopTimeout time given to the plugin to output its documents and exit once stopped
+	StopTimeout time.Duration `yaml:"stopTimeout"`
+}
+
+// DerivedMeasurement expression evaluated for each object of the source measurement, whose quantiles are indexed
+type DerivedMeasurement struct {
+	// Source measurement, like podLatency
+	Source string `yaml:"source"`
+	// Expression arithmetic expression of the latencies of the source conditions, like PodScheduled / Ready
+	Expression string `yaml:"expression"`
+}
+
+// Histogram buckets of the latency histograms, log-linear like HDR histograms unless explicit buckets are given
+type Histogram struct {
+	// Buckets upper bounds of the buckets, replacing the log-linear buckets
+	Buckets []time.Duration `yaml:"buckets"`
+	// Min upper bound of the first log-linear bucket
+	Min time.Duration `yaml:"min"`
+	// Max lower bound of the overflow bucket
+	Max time.Duration `yaml:"max"`
+	// SignificantFigures precision of the log-linear buckets, their width is the value rounded down to these figures
+	SignificantFigures int `yaml:"significantFigures"`
+}
+
+// Streaming bounds the documents held in memory, flushing them to the indexers during the job
+type Streaming struct {
+	// FlushInterval how often the documents of the completed objects are indexed
+	FlushInterval time.Duration `yaml:"flushInterval"`
+	// BufferSize completed objects triggering a flush before the interval elapses, and documents sent in each request
+	BufferSize int `yaml:"bufferSize"`
+}
+
 const (
 	SvcLatencyNs          = "kube-burner-service-latency"
 	SvcLatencyCheckerName = "svc-checker"
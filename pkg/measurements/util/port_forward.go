@@ -14,6 +14,8 @@ import (
 type PodPortForwarder struct {
 	PodName  string
 	StopChan chan struct{}
+	// LocalPort is the local end of the forwarded port, chosen by the forwarder when requested as 0
+	LocalPort int
 }
 
 func NewPodPortForwarder(clientset kubernetes.Interface, restConfig rest.Config, port, namespace, podName string) (*PodPortForwarder, error) {
@@ -61,10 +63,14 @@ func NewPodPortForwarder(clientset kubernetes.Interface, restConfig rest.Config,
 		return &PodPortForwarder{}, err
 	}
 
-	return &PodPortForwarder{
+	ppf := &PodPortForwarder{
 		PodName:  podName,
 		StopChan: stopChan,
-	}, nil
+	}
+	if ports, err := forwarder.GetPorts(); err == nil && len(ports) > 0 {
+		ppf.LocalPort = int(ports[0].Local)
+	}
+	return ppf, nil
 }
 
 func (ppf *PodPortForwarder) CancelPodPortForwarder() {
@@ -40,10 +40,11 @@ const (
 
 var (
 	supportedVMIConditions map[string]struct{} = map[string]struct{}{
-		"VMI" + string(kvv1.Pending):    {},
-		"VMI" + string(kvv1.Scheduling): {},
-		"VMI" + string(kvv1.Scheduled):  {},
-		"VMI" + string(kvv1.Running):    {},
+		"VMI" + string(kvv1.Pending):                              {},
+		"VMI" + string(kvv1.Scheduling):                           {},
+		"VMI" + string(kvv1.Scheduled):                            {},
+		"VMI" + string(kvv1.Running):                              {},
+		"VMI" + string(kvv1.VirtualMachineInstanceAgentConnected): {},
 	}
 )
 
@@ -72,6 +73,8 @@ type vmiMetric struct {
 	VMIScheduledLatency       int64 `json:"vmiScheduledLatency"`
 	vmiRunning                time.Time
 	VMIRunningLatency         int64 `json:"vmiRunningLatency"`
+	vmiAgentConnected         time.Time
+	VMIAgentConnectedLatency  int64 `json:"vmiAgentConnectedLatency,omitempty"`
 	vmReady                   time.Time
 	VMReadyLatency            int64  `json:"vmReadyLatency"`
 	MetricName                string `json:"metricName"`
@@ -176,6 +179,16 @@ func (vmi *vmiLatency) handleUpdateVMI(obj any) {
 	}
 	if vmiM, ok := vmi.metrics.Load(mapID); ok {
 		vmiMetric := vmiM.(vmiMetric)
+		// The guest agent connects once the VMI is running
+		if vmiMetric.vmiAgentConnected.IsZero() {
+			for _, c := range vmiObj.Status.Conditions {
+				if c.Type == kvv1.VirtualMachineInstanceAgentConnected && c.Status == corev1.ConditionTrue {
+					log.Debugf("VMI %s guest agent is connected", vmiObj.Name)
+					vmiMetric.vmiAgentConnected = time.Now().UTC()
+					vmi.metrics.Store(mapID, vmiMetric)
+				}
+			}
+		}
 		if vmiMetric.vmiRunning.IsZero() {
 			switch vmiObj.Status.Phase {
 			case kvv1.Pending:
@@ -334,8 +347,9 @@ func setConfigDefaults(config *rest.Config) {
 	config.NegotiatedSerializer = serializer.WithoutConversionCodecFactory{CodecFactory: codecs}
 }
 
-func (vmi *vmiLatency) Collect(measurementWg *sync.WaitGroup) {
+func (vmi *vmiLatency) Collect(measurementWg *sync.WaitGroup) error {
 	defer measurementWg.Done()
+	return nil
 }
 
 // Stop stops vmiLatency measurement
@@ -356,6 +370,9 @@ func (vmi *vmiLatency) normalizeMetrics() float64 {
 		m.VMISchedulingLatency = m.vmiScheduling.Sub(m.Timestamp).Milliseconds()
 		m.VMIScheduledLatency = m.vmiScheduled.Sub(m.Timestamp).Milliseconds()
 		m.VMIRunningLatency = m.vmiRunning.Sub(m.Timestamp).Milliseconds()
+		if !m.vmiAgentConnected.IsZero() {
+			m.VMIAgentConnectedLatency = m.vmiAgentConnected.Sub(m.Timestamp).Milliseconds()
+		}
 		m.PodCreatedLatency = m.podCreated.Sub(m.Timestamp).Milliseconds()
 		m.PodScheduledLatency = m.podScheduled.Sub(m.Timestamp).Milliseconds()
 		m.PodInitializedLatency = m.podInitialized.Sub(m.Timestamp).Milliseconds()
@@ -372,7 +389,7 @@ func (vmi *vmiLatency) normalizeMetrics() float64 {
 
 func (vmi *vmiLatency) getLatency(normLatency any) map[string]float64 {
 	vmiMetric := normLatency.(vmiMetric)
-	return map[string]float64{
+	latencies := map[string]float64{
 		"VM" + string(kvv1.VirtualMachineReady): float64(vmiMetric.VMReadyLatency),
 		"VMICreated":                            float64(vmiMetric.VMICreatedLatency),
 		"VMI" + string(kvv1.Pending):            float64(vmiMetric.VMIPendingLatency),
@@ -384,6 +401,11 @@ func (vmi *vmiLatency) getLatency(normLatency any) map[string]float64 {
 		"Pod" + string(corev1.PodInitialized):   float64(vmiMetric.PodInitializedLatency),
 		"Pod" + string(corev1.ContainersReady):  float64(vmiMetric.PodContainersReadyLatency),
 	}
+	// VMIs without guest agent are excluded from the agent connection quantiles
+	if !vmiMetric.vmiAgentConnected.IsZero() {
+		latencies["VMI"+string(kvv1.VirtualMachineInstanceAgentConnected)] = float64(vmiMetric.VMIAgentConnectedLatency)
+	}
+	return latencies
 }
 
 // Returns the parent VM UID if there is one
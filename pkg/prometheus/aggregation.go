@@ -0,0 +1,143 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Aggregation functions, besides the pXX percentiles
+const (
+	aggregationAvg = "avg"
+	aggregationMin = "min"
+	aggregationMax = "max"
+)
+
+// aggregation summarizes the datapoints of a range query over the job window before indexing, so that long jobs
+// don't index every datapoint when only their summaries are needed
+type aggregation struct {
+	// Functions avg, min, max or pXX, like p95 or p99.9
+	Functions []string `yaml:"functions"`
+	// By labels the series are grouped by, each series is summarized on its own by default
+	By []string `yaml:"by"`
+	// KeepRaw indexes the datapoints along with their summaries
+	KeepRaw bool `yaml:"keepRaw"`
+}
+
+// validate checks the aggregation functions
+func (a aggregation) validate() error {
+	if len(a.Functions) == 0 {
+		return fmt.Errorf("aggregate requires at least one function")
+	}
+	for _, function := range a.Functions {
+		if _, err := percentile(function); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// percentile returns the quantile of a pXX function, or -1 for the rest of functions
+func percentile(function string) (float64, error) {
+	switch function {
+	case aggregationAvg, aggregationMin, aggregationMax:
+		return -1, nil
+	}
+	if p, ok := strings.CutPrefix(function, "p"); ok {
+		if q, err := strconv.ParseFloat(p, 64); err == nil && q > 0 && q < 100 {
+			return q / 100, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid aggregation function %s, expected %s, %s, %s or pXX", function, aggregationAvg, aggregationMin, aggregationMax)
+}
+
+// aggregateDatapoints summarizes the datapoints of each series, or group of series, into a document per function,
+// timestamped at the end of the job. The scrape gaps and disruptions of the datapoints are kept
+func aggregateDatapoints(datapoints []any, a aggregation, timestamp time.Time) []any {
+	type group struct {
+		first       metric
+		values      []float64
+		scrapeGaps  []string
+		disruptions []string
+	}
+	groups := make(map[string]*group)
+	for _, datapoint := range datapoints {
+		m := datapoint.(metric)
+		labels := m.Labels
+		if a.By != nil {
+			labels = make(map[string]string)
+			for _, label := range a.By {
+				if value, ok := m.Labels[label]; ok {
+					labels[label] = value
+				}
+			}
+		}
+		var key strings.Builder
+		for _, label := range slices.Sorted(maps.Keys(labels)) {
+			fmt.Fprintf(&key, "%s=%q,", label, labels[label])
+		}
+		g, ok := groups[key.String()]
+		if !ok {
+			m.Labels = labels
+			g = &group{first: m}
+			groups[key.String()] = g
+		}
+		g.values = append(g.values, m.Value)
+		for _, gap := range m.ScrapeGaps {
+			if !slices.Contains(g.scrapeGaps, gap) {
+				g.scrapeGaps = append(g.scrapeGaps, gap)
+			}
+		}
+		for _, disruption := range m.Disruptions {
+			if !slices.Contains(g.disruptions, disruption) {
+				g.disruptions = append(g.disruptions, disruption)
+			}
+		}
+	}
+	var documents []any
+	for _, key := range slices.Sorted(maps.Keys(groups)) {
+		g := groups[key]
+		for _, function := range a.Functions {
+			m := g.first
+			m.Timestamp = timestamp
+			m.ChurnMetric = false
+			m.ScrapeGaps, m.Disruptions = g.scrapeGaps, g.disruptions
+			m.Aggregation = function
+			m.Samples = len(g.values)
+			switch function {
+			case aggregationAvg:
+				var sum float64
+				for _, v := range g.values {
+					sum += v
+				}
+				m.Value = sum / float64(len(g.values))
+			case aggregationMin:
+				m.Value = slices.Min(g.values)
+			case aggregationMax:
+				m.Value = slices.Max(g.values)
+			default:
+				q, _ := percentile(function)
+				m.Value = quantile(g.values, q)
+			}
+			documents = append(documents, m)
+		}
+	}
+	return documents
+}
@@ -0,0 +1,248 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+const anomalyMetric = "metricAnomaly"
+
+// Anomaly types
+const (
+	anomalyChangepoint = "changepoint"
+	anomalySpike       = "spike"
+)
+
+// anomaly changepoint or spike of a series scraped during a job
+type anomaly struct {
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"`
+	// Metric name of the metric the series belongs to
+	Metric string            `json:"metric"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Query  string            `json:"query"`
+	// Baseline mean of the series before a changepoint, median of the series around a spike
+	Baseline float64 `json:"baseline"`
+	// Value mean of the series after a changepoint, value of a spike
+	Value float64 `json:"value"`
+	// Change relative change of the value over the baseline in percent, 0 when the baseline is 0
+	Change float64 `json:"change"`
+	// Score shift of the mean in standard errors for changepoints, robust z-score for spikes
+	Score      float64 `json:"score"`
+	UUID       string  `json:"uuid"`
+	MetricName string  `json:"metricName"`
+	JobName    string  `json:"jobName"`
+	Metadata   any     `json:"metadata,omitempty"`
+}
+
+// changepoint level shift of a series, at index of the first sample of the new level
+type changepoint struct {
+	index  int
+	before float64
+	after  float64
+	score  float64
+}
+
+// detectAnomalies flags the changepoints and spikes of the series returned by a range query
+func (p *Prometheus) detectAnomalies(metricName, query string, job Job, matrix model.Matrix) []any {
+	anomalyDetection := p.ConfigSpec.GlobalConfig.AnomalyDetection
+	if anomalyDetection == nil || (len(anomalyDetection.Metrics) > 0 && !slices.Contains(anomalyDetection.Metrics, metricName)) {
+		return nil
+	}
+	var anomalies []any
+	for _, stream := range matrix {
+		var values []float64
+		var timestamps []time.Time
+		for _, sample := range stream.Values {
+			if math.IsNaN(float64(sample.Value)) || math.IsInf(float64(sample.Value), 0) {
+				continue
+			}
+			values = append(values, float64(sample.Value))
+			timestamps = append(timestamps, sample.Timestamp.Time().UTC())
+		}
+		labels := make(map[string]string)
+		for k, v := range stream.Metric {
+			if k != model.MetricNameLabel {
+				labels[string(k)] = string(v)
+			}
+		}
+		newAnomaly := func(anomalyType string, index int, baseline, value, score float64) anomaly {
+			a := anomaly{
+				Timestamp:  timestamps[index],
+				Type:       anomalyType,
+				Metric:     metricName,
+				Labels:     labels,
+				Query:      query,
+				Baseline:   baseline,
+				Value:      value,
+				Score:      math.Round(score*100) / 100,
+				UUID:       p.UUID,
+				MetricName: anomalyMetric,
+				JobName:    job.JobConfig.Name,
+				Metadata:   p.metadata,
+			}
+			if baseline != 0 {
+				a.Change = math.Round((value-baseline)/math.Abs(baseline)*10000) / 100
+			}
+			return a
+		}
+		cps := changepoints(values, anomalyDetection.MinSegment, anomalyDetection.Threshold)
+		for _, cp := range cps {
+			anomalies = append(anomalies, newAnomaly(anomalyChangepoint, cp.index, cp.before, cp.after, cp.score))
+		}
+		if anomalyDetection.SpikeThreshold == 0 {
+			continue
+		}
+		// Spikes are relative to the level of the segment between changepoints they belong to
+		bounds := []int{0}
+		for _, cp := range cps {
+			bounds = append(bounds, cp.index)
+		}
+		bounds = append(bounds, len(values))
+		for i := 1; i < len(bounds); i++ {
+			segment := values[bounds[i-1]:bounds[i]]
+			median := quantile(segment, 0.5)
+			for index, score := range spikes(segment, median, anomalyDetection.SpikeThreshold, anomalyDetection.MinSegment) {
+				anomalies = append(anomalies, newAnomaly(anomalySpike, bounds[i-1]+index, median, segment[index], score))
+			}
+		}
+	}
+	return anomalies
+}
+
+// changepoints finds the level shifts of the series by binary segmentation: the split maximizing the shift of the
+// mean, in standard errors of the pooled standard deviation, is a changepoint when its score reaches the threshold,
+// and both segments are searched again. Segments better explained by a linear trend than by a level shift, like
+// the ones of gauges growing along with the objects created, aren't split
+func changepoints(values []float64, minSegment int, threshold float64) []changepoint {
+	sum := make([]float64, len(values)+1)
+	sumSq := make([]float64, len(values)+1)
+	sumT := make([]float64, len(values)+1)
+	sumTSq := make([]float64, len(values)+1)
+	sumTV := make([]float64, len(values)+1)
+	for i, v := range values {
+		t := float64(i)
+		sum[i+1] = sum[i] + v
+		sumSq[i+1] = sumSq[i] + v*v
+		sumT[i+1] = sumT[i] + t
+		sumTSq[i+1] = sumTSq[i] + t*t
+		sumTV[i+1] = sumTV[i] + t*v
+	}
+	// residual sum of squares of the least squares line fitting the segment
+	linearSS := func(lo, hi int) float64 {
+		n := float64(hi - lo)
+		meanT := (sumT[hi] - sumT[lo]) / n
+		meanV := (sum[hi] - sum[lo]) / n
+		sxx := sumTSq[hi] - sumTSq[lo] - n*meanT*meanT
+		sxy := sumTV[hi] - sumTV[lo] - n*meanT*meanV
+		syy := sumSq[hi] - sumSq[lo] - n*meanV*meanV
+		return math.Max(syy-sxy*sxy/sxx, 0)
+	}
+	var found []changepoint
+	var segment func(lo, hi int)
+	segment = func(lo, hi int) {
+		n := hi - lo
+		if n < 2*minSegment {
+			return
+		}
+		best := changepoint{index: -1}
+		bestSS := 0.0
+		for k := lo + minSegment; k <= hi-minSegment; k++ {
+			nL, nR := float64(k-lo), float64(hi-k)
+			meanL := (sum[k] - sum[lo]) / nL
+			meanR := (sum[hi] - sum[k]) / nR
+			if meanL == meanR {
+				continue
+			}
+			ss := math.Max(sumSq[k]-sumSq[lo]-nL*meanL*meanL, 0) + math.Max(sumSq[hi]-sumSq[k]-nR*meanR*meanR, 0)
+			variance := ss / float64(n-2)
+			// Noise floor, so that flat segments don't turn every shift into a changepoint
+			floor := 1e-3 * math.Max(math.Abs(meanL), math.Abs(meanR))
+			variance = math.Max(variance, floor*floor)
+			score := math.Abs(meanR-meanL) / math.Sqrt(variance*(1/nL+1/nR))
+			if score > best.score {
+				best = changepoint{index: k, before: meanL, after: meanR, score: score}
+				bestSS = ss
+			}
+		}
+		if best.index < 0 || best.score < threshold || linearSS(lo, hi) <= bestSS {
+			return
+		}
+		segment(lo, best.index)
+		found = append(found, best)
+		segment(best.index, hi)
+	}
+	segment(0, len(values))
+	return found
+}
+
+// spikes finds the samples whose robust z-score, based on the median absolute deviation, reaches the threshold.
+// Only the peak of consecutive outliers is a spike, and runs of at least minSegment outliers are left to the
+// changepoint detection
+func spikes(values []float64, median, threshold float64, minSegment int) map[int]float64 {
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - median)
+	}
+	// 1.4826 scales the MAD to the standard deviation of normally distributed data, the mean absolute deviation
+	// is the fallback of series mostly constant
+	scale := 1.4826 * quantile(deviations, 0.5)
+	if scale == 0 {
+		var meanDeviation float64
+		for _, d := range deviations {
+			meanDeviation += d
+		}
+		scale = 1.2533 * meanDeviation / float64(len(values))
+	}
+	found := make(map[int]float64)
+	if scale == 0 {
+		return found
+	}
+	for start := 0; start < len(values); start++ {
+		if deviations[start]/scale < threshold {
+			continue
+		}
+		end, peak := start, start
+		for end < len(values) && deviations[end]/scale >= threshold {
+			if deviations[end] > deviations[peak] {
+				peak = end
+			}
+			end++
+		}
+		if end-start < minSegment {
+			found[peak] = deviations[peak] / scale
+		}
+		start = end
+	}
+	return found
+}
+
+// quantile returns the given quantile of the values, interpolating between the closest ranks
+func quantile(values []float64, q float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	rank := q * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
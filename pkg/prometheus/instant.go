@@ -0,0 +1,81 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Points of a job instant queries are evaluated at
+const (
+	evaluateAtStart      = "start"
+	evaluateAtEnd        = "end"
+	evaluateAtChurnStart = "churnStart"
+	evaluateAtChurnEnd   = "churnEnd"
+)
+
+// evaluationPoints returns the points of the job the instant query is evaluated at, the end of the job by default,
+// preceded by its start with captureStart
+func (md metricDefinition) evaluationPoints() []string {
+	if len(md.EvaluateAt) > 0 {
+		return md.EvaluateAt
+	}
+	if md.CaptureStart {
+		return []string{evaluateAtStart, evaluateAtEnd}
+	}
+	return []string{evaluateAtEnd}
+}
+
+// parseEvaluationPoint returns the anchor and offset of an evaluation point, like end-5m
+func parseEvaluationPoint(point string) (string, time.Duration, error) {
+	anchor, offset := point, time.Duration(0)
+	if i := strings.IndexAny(point, "+-"); i >= 0 {
+		var err error
+		anchor = point[:i]
+		if offset, err = time.ParseDuration(point[i:]); err != nil {
+			return "", 0, fmt.Errorf("invalid offset of evaluation point %s: %v", point, err)
+		}
+	}
+	switch anchor {
+	case evaluateAtStart, evaluateAtEnd, evaluateAtChurnStart, evaluateAtChurnEnd:
+		return anchor, offset, nil
+	}
+	return "", 0, fmt.Errorf("invalid evaluation point %s, expected %s, %s, %s or %s, optionally offset by a duration", point, evaluateAtStart, evaluateAtEnd, evaluateAtChurnStart, evaluateAtChurnEnd)
+}
+
+// evaluationTime returns the time of the evaluation point in the job, false for the churn points of jobs that didn't churn
+func evaluationTime(point string, job Job) (time.Time, bool) {
+	anchor, offset, _ := parseEvaluationPoint(point)
+	var timestamp time.Time
+	switch anchor {
+	case evaluateAtStart:
+		timestamp = job.Start
+	case evaluateAtEnd:
+		timestamp = job.End
+	case evaluateAtChurnStart:
+		if job.ChurnStart == nil {
+			return timestamp, false
+		}
+		timestamp = *job.ChurnStart
+	case evaluateAtChurnEnd:
+		if job.ChurnEnd == nil {
+			return timestamp, false
+		}
+		timestamp = *job.ChurnEnd
+	}
+	return timestamp.Add(offset), true
+}
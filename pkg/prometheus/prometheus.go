@@ -41,6 +41,7 @@ func NewPrometheusClient(configSpec config.Spec, url string, auth Auth, step tim
 		Endpoint:   url,
 		indexer:    indexer,
 		metadata:   metadata,
+		auth:       auth,
 	}
 	log.Infof("👽 Initializing prometheus client with URL: %s", url)
 	p.Client, err = prometheus.NewClient(url, auth.Token, auth.Username, auth.Password, auth.SkipTLSVerify)
@@ -64,6 +65,13 @@ func (p *Prometheus) ScrapeJobsMetrics(jobList ...Job) error {
 			log.Infof("Skipping indexing in job: %v", eachJob.JobConfig.Name)
 			continue
 		}
+		p.jobGaps = p.scrapeGaps(eachJob)
+		if len(p.jobGaps) > 0 {
+			log.Warnf("Job %s: %d scrape gaps, the affected datapoints are annotated with them", eachJob.JobConfig.Name, len(p.jobGaps))
+			for _, gap := range p.jobGaps {
+				docsToIndex[scrapeGapMetric] = append(docsToIndex[scrapeGapMetric], gap)
+			}
+		}
 		for _, metricProfile := range p.MetricProfiles {
 			log.Infof("🔍 Endpoint: %v; profile: %v start: %v end: %v; job: %v, metricsClosing: %v", p.Endpoint,
 				metricProfile.name,
@@ -72,7 +80,6 @@ func (p *Prometheus) ScrapeJobsMetrics(jobList ...Job) error {
 				eachJob.JobConfig.Name,
 				eachJob.JobConfig.MetricsClosing)
 			for _, metric := range metricProfile.metrics {
-				requiresInstant := false
 				t, _ := template.New("").Parse(metric.Query)
 				if err := t.Execute(&renderedQuery, vars); err != nil {
 					log.Warnf("Error rendering query: %v", err)
@@ -81,16 +88,37 @@ func (p *Prometheus) ScrapeJobsMetrics(jobList ...Job) error {
 				query := renderedQuery.String()
 				renderedQuery.Reset()
 				if metric.Instant {
-					if metric.CaptureStart {
-						docsToIndex[metric.MetricName+"-start"] = append(docsToIndex[metric.MetricName+"-start"], p.runInstantQuery(query, metric.MetricName+"-start", jobStart, eachJob)...)
+					for _, point := range metric.evaluationPoints() {
+						timestamp, ok := evaluationTime(point, eachJob)
+						if !ok {
+							log.Debugf("Job %s: skipping %s of %s, the job doesn't churn", eachJob.JobConfig.Name, point, metric.MetricName)
+							continue
+						}
+						metricName := metric.MetricName
+						if point != evaluateAtEnd {
+							metricName += "-" + point
+						}
+						docsToIndex[metricName] = append(docsToIndex[metricName], p.runInstantQuery(query, metricName, timestamp, eachJob)...)
 					}
-					docsToIndex[metric.MetricName] = append(docsToIndex[metric.MetricName], p.runInstantQuery(query, metric.MetricName, jobEnd, eachJob)...)
 				} else {
-					requiresInstant = ((jobEnd.Sub(jobStart).Milliseconds())%(p.Step.Milliseconds()) != 0)
-					docsToIndex[metric.MetricName] = append(docsToIndex[metric.MetricName], p.runRangeQuery(query, metric.MetricName, jobStart, jobEnd, eachJob)...)
-				}
-				if requiresInstant {
-					docsToIndex[metric.MetricName] = append(docsToIndex[metric.MetricName], p.runInstantQuery(query, metric.MetricName, jobEnd, eachJob)...)
+					requiresInstant := ((jobEnd.Sub(jobStart).Milliseconds())%(p.Step.Milliseconds()) != 0)
+					datapoints, anomalies := p.runRangeQuery(query, metric.MetricName, jobStart, jobEnd, eachJob)
+					if requiresInstant {
+						datapoints = append(datapoints, p.runInstantQuery(query, metric.MetricName, jobEnd, eachJob)...)
+					}
+					if metric.Aggregate != nil {
+						aggregated := aggregateDatapoints(datapoints, *metric.Aggregate, jobEnd)
+						log.Debugf("Job %s: %d datapoints of %s aggregated into %d documents", eachJob.JobConfig.Name, len(datapoints), metric.MetricName, len(aggregated))
+						if !metric.Aggregate.KeepRaw {
+							datapoints = nil
+						}
+						datapoints = append(datapoints, aggregated...)
+					}
+					docsToIndex[metric.MetricName] = append(docsToIndex[metric.MetricName], datapoints...)
+					if len(anomalies) > 0 {
+						log.Infof("Job %s: %d anomalies detected in %s", eachJob.JobConfig.Name, len(anomalies), metric.MetricName)
+						docsToIndex[anomalyMetric] = append(docsToIndex[anomalyMetric], anomalies...)
+					}
 				}
 			}
 		}
@@ -149,6 +177,22 @@ func (p *Prometheus) ReadProfile(location string, embedCfg *fileutils.EmbedConfi
 		if md.MetricName == "" {
 			return fmt.Errorf("metricName not defined in query number %d", i+1)
 		}
+		if len(md.EvaluateAt) > 0 && !md.Instant {
+			return fmt.Errorf("%s: evaluateAt requires instant", md.MetricName)
+		}
+		for _, point := range md.EvaluateAt {
+			if _, _, err := parseEvaluationPoint(point); err != nil {
+				return fmt.Errorf("%s: %v", md.MetricName, err)
+			}
+		}
+		if md.Aggregate != nil {
+			if md.Instant {
+				return fmt.Errorf("%s: aggregate requires a range query", md.MetricName)
+			}
+			if err := md.Aggregate.validate(); err != nil {
+				return fmt.Errorf("%s: %v", md.MetricName, err)
+			}
+		}
 	}
 	p.MetricProfiles = append(p.MetricProfiles, metricProfile)
 	return nil
@@ -180,6 +224,8 @@ func (p *Prometheus) createMetric(query, metricName string, job Job, labels mode
 			m.ChurnMetric = true
 		}
 	}
+	m.Disruptions = p.ConfigSpec.GlobalConfig.Disruptions(timestamp, timestamp)
+	m.ScrapeGaps = p.scrapeGapsAt(timestamp, m.Labels["job"])
 	return m
 }
 
@@ -199,8 +245,8 @@ func (p *Prometheus) runInstantQuery(query, metricName string, timestamp time.Ti
 	return datapoints
 }
 
-// runRangeQuery function to run a range query
-func (p *Prometheus) runRangeQuery(query, metricName string, jobStart, jobEnd time.Time, job Job) []any {
+// runRangeQuery function to run a range query, it returns the datapoints and the anomalies detected in them
+func (p *Prometheus) runRangeQuery(query, metricName string, jobStart, jobEnd time.Time, job Job) ([]any, []any) {
 	var v model.Value
 	var err error
 	var datapoints []any
@@ -208,12 +254,13 @@ func (p *Prometheus) runRangeQuery(query, metricName string, jobStart, jobEnd ti
 	v, err = p.Client.QueryRange(query, jobStart, jobEnd, p.Step)
 	if err != nil {
 		log.Warnf("Error found with query %s: %s", query, err)
-		return []any{}
+		return []any{}, nil
 	}
 	if err = p.parseMatrix(metricName, query, job, v, &datapoints); err != nil {
 		log.Warnf("Error found parsing result from query %s: %s", query, err)
+		return datapoints, nil
 	}
-	return datapoints
+	return datapoints, p.detectAnomalies(metricName, query, job, v.(model.Matrix))
 }
 
 // Indexes datapoints to a specified indexer.
@@ -0,0 +1,336 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/prometheus/common/model"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	scrapeGapMetric = "scrapeGap"
+	targetsTimeout  = 30 * time.Second
+)
+
+// Scrape gap reasons
+const (
+	// The scrape of the target failed
+	scrapeGapDown = "down"
+	// The target wasn't scraped, like when Prometheus was down
+	scrapeGapMissing = "missing"
+	// Prometheus failed to truncate its head block, its samples may be lost
+	scrapeGapTruncation = "headTruncationFailed"
+)
+
+// PromQL keywords, and the grouping modifiers followed by a list of label names, they're never metric names
+var (
+	promQLKeywords  = []string{"and", "or", "unless", "bool", "offset", "inf", "nan"}
+	promQLModifiers = []string{"by", "without", "on", "ignoring", "group_left", "group_right"}
+)
+
+// scrapeGap period a scrape target wasn't scraped successfully during a job, or Prometheus failed to truncate its head
+type scrapeGap struct {
+	Timestamp    time.Time `json:"timestamp"`
+	EndTimestamp time.Time `json:"endTimestamp"`
+	Reason       string    `json:"reason"`
+	// ScrapeJob and Instance of the target, only the instance of Prometheus for head truncations
+	ScrapeJob string `json:"scrapeJob,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	// Duration time in milliseconds
+	Duration   int64  `json:"duration"`
+	UUID       string `json:"uuid"`
+	MetricName string `json:"metricName"`
+	JobName    string `json:"jobName"`
+	Metadata   any    `json:"metadata,omitempty"`
+}
+
+// activeTarget target returned by the targets API
+type activeTarget struct {
+	Labels    map[string]string `json:"labels"`
+	Health    string            `json:"health"`
+	LastError string            `json:"lastError"`
+}
+
+// SetTargetHealth sets the scrape targets checked, the scrape jobs exposing the metrics of the profiles when no jobs
+// are given
+func (p *Prometheus) SetTargetHealth(targetHealth config.TargetHealth) {
+	p.targetHealth = &targetHealth
+	p.scrapeJobs = targetHealth.Jobs
+	if len(p.scrapeJobs) == 0 {
+		var names []string
+		for _, metricProfile := range p.MetricProfiles {
+			for _, metric := range metricProfile.metrics {
+				for _, name := range metricNames(metric.Query) {
+					if !slices.Contains(names, name) {
+						names = append(names, name)
+					}
+				}
+			}
+		}
+		if len(names) > 0 {
+			query := fmt.Sprintf(`group by (job) ({__name__=~"%s"})`, strings.Join(names, "|"))
+			v, err := p.Client.Query(query, time.Now().UTC())
+			if err != nil {
+				log.Warnf("Error finding the scrape jobs of the metrics profiles: %v", err)
+			} else if vector, ok := v.(model.Vector); ok {
+				for _, sample := range vector {
+					if job := string(sample.Metric["job"]); job != "" {
+						p.scrapeJobs = append(p.scrapeJobs, job)
+					}
+				}
+			}
+		}
+	}
+	if len(p.scrapeJobs) == 0 {
+		log.Warnf("No scrape jobs found for the metrics profiles of %s, their targets aren't checked", p.Endpoint)
+		return
+	}
+	slices.Sort(p.scrapeJobs)
+	log.Infof("Checking the targets of the scrape jobs %s", strings.Join(p.scrapeJobs, ", "))
+}
+
+// CheckTargets checks that the targets of the scrape jobs are up, returns an error when any is down and the policy is
+// fail. The check is skipped when the targets can't be listed
+func (p *Prometheus) CheckTargets() error {
+	if p.targetHealth == nil || len(p.scrapeJobs) == 0 {
+		return nil
+	}
+	targets, err := p.activeTargets()
+	if err != nil {
+		log.Warnf("Error listing the targets of %s: %v", p.Endpoint, err)
+		return nil
+	}
+	var checked int
+	var down []string
+	for _, target := range targets {
+		if !slices.Contains(p.scrapeJobs, target.Labels["job"]) {
+			continue
+		}
+		checked++
+		if target.Health != "up" {
+			down = append(down, fmt.Sprintf("%s/%s (%s: %s)", target.Labels["job"], target.Labels["instance"], target.Health, target.LastError))
+		}
+	}
+	if len(down) == 0 {
+		log.Infof("All %d targets of the scrape jobs are up", checked)
+		return nil
+	}
+	err = fmt.Errorf("%d/%d targets of %s down: %s", len(down), checked, p.Endpoint, strings.Join(down, ", "))
+	if p.targetHealth.Policy == config.ClusterHealthFail {
+		return err
+	}
+	log.Warn(err.Error())
+	return nil
+}
+
+func (p *Prometheus) activeTargets() ([]activeTarget, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), targetsTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.Endpoint, "/")+"/api/v1/targets?state=active", nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.auth.Username != "" {
+		req.SetBasicAuth(p.auth.Username, p.auth.Password)
+	}
+	if p.auth.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.auth.Token)
+	}
+	client := &http.Client{
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: &tls.Config{InsecureSkipVerify: p.auth.SkipTLSVerify}},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("targets API returned %s", resp.Status)
+	}
+	var response struct {
+		Data struct {
+			ActiveTargets []activeTarget `json:"activeTargets"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("invalid targets API response: %v", err)
+	}
+	return response.Data.ActiveTargets, nil
+}
+
+// scrapeGaps returns the periods the targets of the scrape jobs weren't scraped successfully during the job, from
+// their up series, and the periods Prometheus failed to truncate its head. Their precision is the step
+func (p *Prometheus) scrapeGaps(job Job) []scrapeGap {
+	if p.targetHealth == nil || len(p.scrapeJobs) == 0 {
+		return nil
+	}
+	var gaps []scrapeGap
+	var jobMatchers []string
+	for _, scrapeJob := range p.scrapeJobs {
+		jobMatchers = append(jobMatchers, strings.ReplaceAll(regexp.QuoteMeta(scrapeJob), `\`, `\\`))
+	}
+	up, err := p.Client.QueryRange(fmt.Sprintf(`up{job=~"%s"}`, strings.Join(jobMatchers, "|")), job.Start, job.End, p.Step)
+	if err != nil {
+		log.Warnf("Error querying the up series of the scrape targets: %v", err)
+	} else if matrix, ok := up.(model.Matrix); ok {
+		for _, series := range matrix {
+			gaps = append(gaps, p.seriesGaps(series, job, false)...)
+		}
+	}
+	truncations, err := p.Client.QueryRange("prometheus_tsdb_head_truncations_failed_total", job.Start, job.End, p.Step)
+	if err != nil {
+		log.Warnf("Error querying the head truncations of Prometheus: %v", err)
+	} else if matrix, ok := truncations.(model.Matrix); ok {
+		for _, series := range matrix {
+			gaps = append(gaps, p.seriesGaps(series, job, true)...)
+		}
+	}
+	return gaps
+}
+
+// seriesGaps returns the gaps of an up series: the samples with value 0 and the samples missing between the first
+// and the last one, as targets may come and go. The gaps of a failed head truncations series are its increases
+func (p *Prometheus) seriesGaps(series *model.SampleStream, job Job, truncations bool) []scrapeGap {
+	var gaps []scrapeGap
+	add := func(start, end time.Time, reason string) {
+		if end.After(job.End) {
+			end = job.End
+		}
+		if n := len(gaps); n > 0 && gaps[n-1].Reason == reason && !start.After(gaps[n-1].EndTimestamp) {
+			gaps[n-1].EndTimestamp = end
+			return
+		}
+		gap := scrapeGap{
+			Timestamp:    start,
+			EndTimestamp: end,
+			Reason:       reason,
+			Instance:     string(series.Metric["instance"]),
+			UUID:         p.UUID,
+			MetricName:   scrapeGapMetric,
+			JobName:      job.JobConfig.Name,
+			Metadata:     p.metadata,
+		}
+		if !truncations {
+			gap.ScrapeJob = string(series.Metric["job"])
+		}
+		gaps = append(gaps, gap)
+	}
+	for i, sample := range series.Values {
+		timestamp := sample.Timestamp.Time().UTC()
+		if truncations {
+			if i > 0 && sample.Value > series.Values[i-1].Value {
+				add(series.Values[i-1].Timestamp.Time().UTC(), timestamp, scrapeGapTruncation)
+			}
+			continue
+		}
+		if i > 0 && timestamp.Sub(series.Values[i-1].Timestamp.Time().UTC()) > p.Step {
+			add(series.Values[i-1].Timestamp.Time().UTC(), timestamp, scrapeGapMissing)
+		}
+		if sample.Value == 0 {
+			add(timestamp, timestamp.Add(p.Step), scrapeGapDown)
+		}
+	}
+	for i := range gaps {
+		gaps[i].Duration = gaps[i].EndTimestamp.Sub(gaps[i].Timestamp).Milliseconds()
+	}
+	return gaps
+}
+
+// scrapeGapsAt returns the scrape gaps of the job being scraped at the given time, those of other scrape jobs are
+// ignored when the series has a job label
+func (p *Prometheus) scrapeGapsAt(timestamp time.Time, scrapeJob string) []string {
+	var gaps []string
+	for _, gap := range p.jobGaps {
+		if timestamp.Before(gap.Timestamp) || timestamp.After(gap.EndTimestamp) {
+			continue
+		}
+		if scrapeJob != "" && gap.ScrapeJob != "" && gap.ScrapeJob != scrapeJob {
+			continue
+		}
+		description := gap.Reason
+		if gap.ScrapeJob != "" {
+			description += " " + gap.ScrapeJob
+		}
+		if !slices.Contains(gaps, description) {
+			gaps = append(gaps, description)
+		}
+	}
+	return gaps
+}
+
+// metricNames returns the metric names selected by a PromQL query. It's a lexical approximation: the identifiers
+// that aren't functions, keywords, label names or durations, names that don't exist are harmless
+func metricNames(query string) []string {
+	var names []string
+	var braces int
+	// skipList skips the label list of a grouping modifier
+	var skipList bool
+	isIdentifier := func(c byte, first bool) bool {
+		return c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+	}
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '"' || c == '\'' || c == '`':
+			// String literal
+			for i++; i < len(query) && query[i] != c; i++ {
+				if query[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case c == '{':
+			braces++
+		case c == '}':
+			braces--
+		case c == ')':
+			skipList = false
+		case c >= '0' && c <= '9':
+			// Number or duration
+			for i+1 < len(query) && (isIdentifier(query[i+1], false) || query[i+1] == '.') {
+				i++
+			}
+		case isIdentifier(c, true):
+			start := i
+			for i+1 < len(query) && isIdentifier(query[i+1], false) {
+				i++
+			}
+			identifier := query[start : i+1]
+			if braces > 0 || skipList {
+				continue
+			}
+			next := strings.TrimLeft(query[i+1:], " \t\n")
+			switch {
+			case slices.Contains(promQLModifiers, strings.ToLower(identifier)):
+				skipList = strings.HasPrefix(next, "(")
+			case slices.Contains(promQLKeywords, strings.ToLower(identifier)), strings.HasPrefix(next, "("):
+				// Function or aggregation
+			default:
+				names = append(names, identifier)
+			}
+		}
+	}
+	return names
+}
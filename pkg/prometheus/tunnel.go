@@ -0,0 +1,177 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/measurements/util"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// Tunnel exposes a Prometheus endpoint not reachable from the host through a local URL until it's closed
+type Tunnel struct {
+	URL     string
+	closers []func()
+}
+
+// Close tears down the listeners and port-forwards of the tunnel
+func (t *Tunnel) Close() {
+	if t == nil {
+		return
+	}
+	for i := len(t.closers) - 1; i >= 0; i-- {
+		t.closers[i]()
+	}
+	t.closers = nil
+}
+
+// NewUnixSocketTunnel exposes the Prometheus API served at the given UNIX socket in a local TCP port
+func NewUnixSocketTunnel(socket string) (*Tunnel, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	// Verify the socket is reachable before handing the endpoint over
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("error connecting to UNIX socket %s: %v", socket, err)
+	}
+	conn.Close()
+	go func() {
+		for {
+			local, err := listener.Accept()
+			if err != nil {
+				if !errors.Is(err, net.ErrClosed) {
+					log.Errorf("Error accepting connection for UNIX socket %s: %v", socket, err)
+				}
+				return
+			}
+			go proxyUnixSocket(local, socket)
+		}
+	}()
+	log.Infof("Tunneling UNIX socket %s through %s", socket, listener.Addr().String())
+	return &Tunnel{
+		URL:     fmt.Sprintf("http://%s", listener.Addr().String()),
+		closers: []func(){func() { listener.Close() }},
+	}, nil
+}
+
+func proxyUnixSocket(local net.Conn, socket string) {
+	defer local.Close()
+	remote, err := net.Dial("unix", socket)
+	if err != nil {
+		log.Errorf("Error connecting to UNIX socket %s: %v", socket, err)
+		return
+	}
+	defer remote.Close()
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(remote, local)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(local, remote)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+// NewPortForwardTunnel forwards a local port to the first running pod matching the given port-forward configuration.
+// When authenticate is set, requests are sent through a local proxy authenticating them with the credentials of the kubeconfig,
+// whatever their kind, this is useful to go through oauth proxies
+func NewPortForwardTunnel(clientSet kubernetes.Interface, restConfig *rest.Config, pf config.PortForward, authenticate, skipTLSVerify bool) (*Tunnel, error) {
+	if pf.Port == 0 {
+		return nil, fmt.Errorf("portForward.port is required")
+	}
+	scheme := pf.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	podList, err := clientSet.CoreV1().Pods(pf.Namespace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.Set(pf.LabelSelector).String(),
+		FieldSelector: "status.phase=" + string(corev1.PodRunning),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(podList.Items) == 0 {
+		return nil, fmt.Errorf("no running pods found in namespace %s with selector %s", pf.Namespace, labels.Set(pf.LabelSelector))
+	}
+	podName := podList.Items[0].Name
+	// The forwarder binds a free local port by itself, avoiding races with other processes
+	forwarder, err := util.NewPodPortForwarder(clientSet, *restConfig, fmt.Sprintf("0:%d", pf.Port), pf.Namespace, podName)
+	if err != nil {
+		return nil, err
+	}
+	tunnel := &Tunnel{
+		URL:     fmt.Sprintf("%s://127.0.0.1:%d", scheme, forwarder.LocalPort),
+		closers: []func(){forwarder.CancelPodPortForwarder},
+	}
+	log.Infof("Tunneling pod %s/%s:%d through 127.0.0.1:%d", pf.Namespace, podName, pf.Port, forwarder.LocalPort)
+	if authenticate {
+		if err := tunnel.authenticate(restConfig, skipTLSVerify); err != nil {
+			tunnel.Close()
+			return nil, err
+		}
+	}
+	return tunnel, nil
+}
+
+// authenticate fronts the tunnel with a local reverse proxy using the transport of the rest config,
+// which injects and refreshes any kind of kubeconfig credentials: tokens, exec plugins, auth providers...
+func (t *Tunnel) authenticate(restConfig *rest.Config, skipTLSVerify bool) error {
+	target, err := url.Parse(t.URL)
+	if err != nil {
+		return err
+	}
+	// The TLS settings of the apiserver don't apply to the tunneled pod
+	proxyConfig := rest.CopyConfig(restConfig)
+	proxyConfig.TLSClientConfig = rest.TLSClientConfig{Insecure: skipTLSVerify}
+	transport, err := rest.TransportFor(proxyConfig)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.Transport = transport
+	server := &http.Server{Handler: proxy, ReadHeaderTimeout: 30 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Error serving Prometheus tunnel proxy: %v", err)
+		}
+	}()
+	t.URL = fmt.Sprintf("http://%s", listener.Addr().String())
+	t.closers = append(t.closers, func() { server.Close() })
+	return nil
+}
@@ -40,6 +40,12 @@ type Prometheus struct {
 	ConfigSpec     config.Spec
 	metadata       map[string]any
 	indexer        *indexers.Indexer
+	auth           Auth
+	// targetHealth scrape targets checked, and scrapeJobs their scrape jobs
+	targetHealth *config.TargetHealth
+	scrapeJobs   []string
+	// jobGaps scrape gaps of the job being scraped
+	jobGaps []scrapeGap
 }
 
 type Job struct {
@@ -49,6 +55,421 @@ type Job struct {
 	ChurnEnd         *time.Time
 	JobConfig        config.Job
 	ObjectOperations int32
+	DiscoveryLatency time.Duration
+	WarmUpLatency    time.Duration
+	// WaitForLatency time spent waiting for the waitFor gate of the job
+	WaitForLatency time.Duration
+	// TargetNodeCount number of nodes matching the targetNodes and excludeNodes selectors of the job
+	TargetNodeCount int
+	// API overhead of the object waiters
+	WaiterListRequests int64
+	WaiterWatchEvents  int64
+	// QPSTimeseries target and achieved QPS of jobs with a QPS ramp
+	QPSTimeseries []QPSSample
+	// ArrivalStats accounting of the arrivals of open-loop jobs
+	ArrivalStats *ArrivalStats
+	// IterationPacing accounting of the iteration slots of calendar-paced jobs
+	IterationPacing *IterationPacing
+	// ThrottlingEvents QPS decreases of jobs with adaptive QPS
+	ThrottlingEvents []ThrottlingEvent
+	// Reconfigurations live changes of the parameters of the job
+	Reconfigurations []Reconfiguration
+	// PDBBlockedEvictions pod evictions of churn cycles blocked by PodDisruptionBudgets
+	PDBBlockedEvictions []PDBBlockedEviction
+	// HelmReleases release operations of helm jobs
+	HelmReleases []HelmRelease
+	// FioResults results of fio jobs
+	FioResults []FioResult
+	// NetworkResults results of network jobs
+	NetworkResults []NetworkResult
+	// InflateSamples growth of the objects of inflate jobs, by iteration
+	InflateSamples []InflateSample
+	// MeshOverhead comparison of the phases of a mesh overhead job, set in its mesh phase
+	MeshOverhead *MeshOverhead
+	// SchedulerCache comparison of the phases of a scheduler cache job, set in its warm phase
+	SchedulerCache *SchedulerCache
+	// Namespaces namespaces created by the job, their cost is attributed to it
+	Namespaces []string
+	// Cost cost of the namespaces created by the job during its execution
+	Cost *JobCost
+	// ClientSamples resource usage of kube-burner sampled during the job
+	ClientSamples []ClientSample
+	// ClientUsage summary of the client samples of the job
+	ClientUsage *ClientUsage
+	// NodeIncidents unhealthy node conditions observed during the job
+	NodeIncidents []NodeIncident
+	// AlertmanagerAlerts alerts of the Alertmanager of the cluster firing during the job
+	AlertmanagerAlerts []AlertmanagerAlert
+	// ClusterHealthChecks health checks of the cluster taken during the job
+	ClusterHealthChecks []ClusterHealthCheck
+	// Comparison comparison with the other cluster of an A/B benchmark
+	Comparison *Comparison
+	// IdentityRequests requests sent with each identity of the job
+	IdentityRequests map[string]int64
+	// WasmHookStats objects handed to the wasm hook of the job
+	WasmHookStats *WasmHookStats
+	// SLOWarnings violations of the warn SLOs of the measurements of the job
+	SLOWarnings []string
+	// Artifacts documents parsed from the artifacts copied from the pods of the job
+	Artifacts []ArtifactDocument
+	// ArtifactFiles number of artifact files copied from the pods of the job
+	ArtifactFiles int
+}
+
+// ArtifactDocument document parsed from a file copied from a pod
+type ArtifactDocument struct {
+	Timestamp time.Time `json:"timestamp"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	Container string    `json:"container"`
+	// Path of the file in the container
+	Path string `json:"path"`
+	// Data content of the file, or one of its elements or lines
+	Data       any    `json:"data"`
+	MetricName string `json:"metricName"`
+}
+
+// CompareResult result of a job on one of the clusters of an A/B benchmark
+type CompareResult struct {
+	// Cluster API server URL
+	Cluster          string    `json:"cluster"`
+	Start            time.Time `json:"start"`
+	End              time.Time `json:"end"`
+	ElapsedTime      float64   `json:"elapsedTime"`
+	ObjectOperations int32     `json:"objectOperations"`
+	AchievedQps      float64   `json:"achievedQps"`
+	Passed           bool      `json:"passed"`
+	// Quantiles latency quantiles of the measurements, by <quantiles metric>.<quantile name>.<statistic>
+	Quantiles map[string]float64 `json:"quantiles,omitempty"`
+}
+
+// Comparison comparison of a job run in lockstep on the two clusters of an A/B benchmark
+type Comparison struct {
+	A CompareResult `json:"a"`
+	B CompareResult `json:"b"`
+	// StartSkew time in milliseconds between the start of the job on cluster a and on cluster b
+	StartSkew int64 `json:"startSkew"`
+	// Relative differences of cluster b over cluster a, in percent
+	ElapsedTimeDelta float64            `json:"elapsedTimeDelta"`
+	AchievedQpsDelta float64            `json:"achievedQpsDelta"`
+	QuantileDeltas   map[string]float64 `json:"quantileDeltas,omitempty"`
+}
+
+// ClientSample resource usage of kube-burner, and of the machine or pod running it, at a point in time
+type ClientSample struct {
+	Timestamp time.Time `json:"timestamp"`
+	// CPU percentage of the available CPUs used by the kube-burner process
+	CPU float64 `json:"cpu"`
+	// HostCPU percentage of the CPUs of the host in use
+	HostCPU float64 `json:"hostCPU"`
+	// RSS resident memory of the kube-burner process in bytes
+	RSS int64 `json:"rss"`
+	// Memory percentage of the available memory used by the kube-burner process
+	Memory float64 `json:"memory"`
+	// Network throughput of the network interfaces in bytes/s
+	RxBytesPerSecond float64 `json:"rxBytesPerSecond"`
+	TxBytesPerSecond float64 `json:"txBytesPerSecond"`
+	// ThrottledPeriods CFS periods of the cgroup throttled since the previous sample
+	ThrottledPeriods int64 `json:"throttledPeriods"`
+	// Periods CFS periods of the cgroup elapsed since the previous sample
+	Periods    int64 `json:"periods"`
+	Goroutines int   `json:"goroutines"`
+}
+
+// ClientUsage resource usage of kube-burner during a job, saturated when it may have been the bottleneck
+type ClientUsage struct {
+	Hostname  string `json:"hostname"`
+	InCluster bool   `json:"inCluster"`
+	// CPUs CPUs available to kube-burner, limited by the cgroup CPU quota
+	CPUs float64 `json:"cpus"`
+	// MemoryLimit memory available to kube-burner in bytes, limited by the cgroup memory limit
+	MemoryLimit      int64   `json:"memoryLimit"`
+	AvgCPU           float64 `json:"avgCPU"`
+	P95CPU           float64 `json:"p95CPU"`
+	MaxCPU           float64 `json:"maxCPU"`
+	AvgHostCPU       float64 `json:"avgHostCPU"`
+	MaxRSS           int64   `json:"maxRSS"`
+	MaxMemory        float64 `json:"maxMemory"`
+	RxBytes          int64   `json:"rxBytes"`
+	TxBytes          int64   `json:"txBytes"`
+	ThrottledPeriods int64   `json:"throttledPeriods"`
+	MaxGoroutines    int     `json:"maxGoroutines"`
+	Saturated        bool    `json:"saturated"`
+	// SaturationReasons thresholds exceeded by the client
+	SaturationReasons []string `json:"saturationReasons,omitempty"`
+}
+
+// JobCost cost allocated to the namespaces of a job by OpenCost or Kubecost, in the currency of the provider
+type JobCost struct {
+	Namespaces       int     `json:"namespaces"`
+	CPUCost          float64 `json:"cpuCost"`
+	GPUCost          float64 `json:"gpuCost"`
+	RAMCost          float64 `json:"ramCost"`
+	PVCost           float64 `json:"pvCost"`
+	NetworkCost      float64 `json:"networkCost"`
+	LoadBalancerCost float64 `json:"loadBalancerCost"`
+	TotalCost        float64 `json:"totalCost"`
+	// CostPerOperation total cost divided by the object operations of the job
+	CostPerOperation float64 `json:"costPerOperation,omitempty"`
+}
+
+// MeshPhaseStats measurements of a phase of a mesh overhead job
+type MeshPhaseStats struct {
+	// Pods pods created by the phase
+	Pods int `json:"pods"`
+	// SidecarPods pods running a mesh sidecar
+	SidecarPods int `json:"sidecarPods"`
+	// Pod readiness latencies in milliseconds, since their creation
+	PodReadyP50 int64 `json:"podReadyP50"`
+	PodReadyP99 int64 `json:"podReadyP99"`
+	PodReadyAvg int64 `json:"podReadyAvg"`
+	// Memory memory footprint of the workload in bytes
+	Memory float64 `json:"memory"`
+	// RequestLatency request latency of the workload, in the unit of the request latency query
+	RequestLatency float64 `json:"requestLatency,omitempty"`
+	// ControlPlane metrics of the mesh control plane
+	ControlPlane map[string]float64 `json:"controlPlane,omitempty"`
+}
+
+// MeshOverhead overhead of the service mesh: the deltas between the mesh and the baseline phases
+type MeshOverhead struct {
+	Provider            string             `json:"provider"`
+	Baseline            MeshPhaseStats     `json:"baseline"`
+	Mesh                MeshPhaseStats     `json:"mesh"`
+	PodReadyP50Delta    int64              `json:"podReadyP50Delta"`
+	PodReadyP99Delta    int64              `json:"podReadyP99Delta"`
+	PodReadyAvgDelta    int64              `json:"podReadyAvgDelta"`
+	MemoryDelta         float64            `json:"memoryDelta"`
+	MemoryPerPodDelta   float64            `json:"memoryPerPodDelta"`
+	RequestLatencyDelta float64            `json:"requestLatencyDelta,omitempty"`
+	ControlPlaneDelta   map[string]float64 `json:"controlPlaneDelta,omitempty"`
+}
+
+// SchedulerPhaseStats scheduling latencies of a phase of a scheduler cache job
+type SchedulerPhaseStats struct {
+	// Pods pods scheduled by the phase
+	Pods int `json:"pods"`
+	// Scheduling latencies in milliseconds, since the creation of the pods
+	ScheduledP50 int64 `json:"scheduledP50"`
+	ScheduledP99 int64 `json:"scheduledP99"`
+	ScheduledAvg int64 `json:"scheduledAvg"`
+	ScheduledMax int64 `json:"scheduledMax"`
+	// RestartLatency time in milliseconds the restarted kube-scheduler took to acquire its lease, cold phase only
+	RestartLatency int64 `json:"restartLatency,omitempty"`
+}
+
+// SchedulerCache cold cache penalty of kube-scheduler: the deltas between the cold and the warm phases
+type SchedulerCache struct {
+	Cold              SchedulerPhaseStats `json:"cold"`
+	Warm              SchedulerPhaseStats `json:"warm"`
+	ScheduledP50Delta int64               `json:"scheduledP50Delta"`
+	ScheduledP99Delta int64               `json:"scheduledP99Delta"`
+	ScheduledAvgDelta int64               `json:"scheduledAvgDelta"`
+	ScheduledMaxDelta int64               `json:"scheduledMaxDelta"`
+}
+
+// HelmRelease outcome of a release operation of a helm job
+type HelmRelease struct {
+	Timestamp time.Time `json:"timestamp"`
+	Release   string    `json:"release"`
+	Namespace string    `json:"namespace"`
+	Iteration int       `json:"iteration"`
+	Operation string    `json:"operation"`
+	// Latency time in milliseconds taken by helm to perform the operation, including the wait for the release readiness
+	Latency int64  `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+// FioResult result of an I/O direction of a fio job run by a fio pod, or its error
+type FioResult struct {
+	Timestamp    time.Time `json:"timestamp"`
+	Namespace    string    `json:"namespace"`
+	Pod          string    `json:"pod"`
+	NodeName     string    `json:"nodeName,omitempty"`
+	PVC          string    `json:"pvc"`
+	StorageClass string    `json:"storageClass,omitempty"`
+	Iteration    int       `json:"iteration"`
+	FioJob       string    `json:"fioJob,omitempty"`
+	// Operation I/O direction: read, write or trim
+	Operation string  `json:"operation,omitempty"`
+	IOPS      float64 `json:"iops"`
+	// Bandwidth in bytes per second
+	Bandwidth int64 `json:"bandwidth"`
+	IOBytes   int64 `json:"ioBytes"`
+	// Runtime in milliseconds
+	Runtime int64 `json:"runtime"`
+	// Completion latencies in microseconds
+	LatencyAvg  float64 `json:"latencyAvg"`
+	LatencyP50  float64 `json:"latencyP50"`
+	LatencyP95  float64 `json:"latencyP95"`
+	LatencyP99  float64 `json:"latencyP99"`
+	LatencyP999 float64 `json:"latencyP999"`
+	Error       string  `json:"error,omitempty"`
+}
+
+// NetworkResult result of a client and server pair of a network job, or its error
+type NetworkResult struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Namespace  string    `json:"namespace"`
+	Iteration  int       `json:"iteration"`
+	Topology   string    `json:"topology"`
+	Protocol   string    `json:"protocol"`
+	ClientNode string    `json:"clientNode,omitempty"`
+	ServerNode string    `json:"serverNode,omitempty"`
+	Streams    int       `json:"streams"`
+	// Throughput received throughput in bits per second
+	Throughput  float64 `json:"throughput"`
+	Retransmits int64   `json:"retransmits,omitempty"`
+	// Jitter UDP jitter in milliseconds
+	Jitter      float64 `json:"jitter,omitempty"`
+	LostPercent float64 `json:"lostPercent,omitempty"`
+	// Request/response latencies in microseconds
+	TransactionRate float64 `json:"transactionRate,omitempty"`
+	LatencyAvg      float64 `json:"latencyAvg,omitempty"`
+	LatencyP50      float64 `json:"latencyP50,omitempty"`
+	LatencyP90      float64 `json:"latencyP90,omitempty"`
+	LatencyP99      float64 `json:"latencyP99,omitempty"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// InflateSample size and update latency of the objects of an inflate job after growing them in an iteration
+type InflateSample struct {
+	Timestamp time.Time `json:"timestamp"`
+	Iteration int       `json:"iteration"`
+	Kind      string    `json:"kind"`
+	Objects   int       `json:"objects"`
+	// PaddingSize size in bytes of the padding of the objects
+	PaddingSize int `json:"paddingSize"`
+	// Object sizes in bytes, as returned by the API server
+	AvgObjectSize int64 `json:"avgObjectSize"`
+	MaxObjectSize int64 `json:"maxObjectSize"`
+	// Update latencies in milliseconds
+	LatencyAvg float64 `json:"latencyAvg"`
+	LatencyP99 float64 `json:"latencyP99"`
+	LatencyMax float64 `json:"latencyMax"`
+	// DBSize size in bytes of the etcd database reported by the API server, when available
+	DBSize int64 `json:"dbSize,omitempty"`
+	Errors int   `json:"errors,omitempty"`
+}
+
+// PDBBlockedEviction pod eviction refused by a PodDisruptionBudget at least once
+type PDBBlockedEviction struct {
+	Timestamp time.Time `json:"timestamp"`
+	Namespace string    `json:"namespace"`
+	Pod       string    `json:"pod"`
+	// Wait time in milliseconds from the first eviction attempt until the pod was evicted or the timeout was reached
+	Wait     int64 `json:"wait"`
+	Attempts int   `json:"attempts"`
+	Evicted  bool  `json:"evicted"`
+}
+
+// ThrottlingEvent QPS decrease triggered by an API server health signal
+type ThrottlingEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Reason signal exceeding its threshold: throttledRequests, inqueueRequests or requestLatency
+	Reason      string  `json:"reason"`
+	Value       float64 `json:"value"`
+	Threshold   float64 `json:"threshold"`
+	PreviousQPS float64 `json:"previousQps"`
+	QPS         float64 `json:"qps"`
+}
+
+// Reconfiguration live change of a parameter of a running job
+type Reconfiguration struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Parameter changed: qps, burst, churnPercent or churnDelay, in seconds
+	Parameter     string  `json:"parameter"`
+	PreviousValue float64 `json:"previousValue"`
+	Value         float64 `json:"value"`
+}
+
+// ClusterHealthCheck result of a periodic health check of the cluster
+type ClusterHealthCheck struct {
+	Timestamp time.Time `json:"timestamp"`
+	Healthy   bool      `json:"healthy"`
+	// APIServerReady whether the API server readyz endpoint succeeded
+	APIServerReady bool `json:"apiServerReady"`
+	// APIServerLatency time in milliseconds the readyz endpoint took to respond
+	APIServerLatency int64    `json:"apiServerLatency"`
+	Nodes            int      `json:"nodes"`
+	UnhealthyNodes   int      `json:"unhealthyNodes"`
+	Issues           []string `json:"issues,omitempty"`
+	// FailedChecks consecutive failed checks, this one included
+	FailedChecks int `json:"failedChecks"`
+	// Degraded whether the consecutive failed checks reached the threshold
+	Degraded bool `json:"degraded"`
+}
+
+// WasmHookStats outcome of the objects handed to the wasm hook of a job
+type WasmHookStats struct {
+	Objects int64 `json:"objects"`
+	Mutated int64 `json:"mutated"`
+	Vetoed  int64 `json:"vetoed"`
+	Failed  int64 `json:"failed"`
+}
+
+// AlertmanagerAlert alert of the Alertmanager of the cluster
+type AlertmanagerAlert struct {
+	// Timestamp time the alert started firing
+	Timestamp time.Time `json:"timestamp"`
+	// EndTimestamp time the alert was observed resolved, nil while it's still firing
+	EndTimestamp *time.Time        `json:"endTimestamp,omitempty"`
+	Fingerprint  string            `json:"fingerprint"`
+	AlertName    string            `json:"alertName"`
+	Severity     string            `json:"severity,omitempty"`
+	State        string            `json:"state"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	// PreExisting whether the alert was already firing when the benchmark started
+	PreExisting bool `json:"preExisting"`
+	// Duration time in milliseconds the alert was firing, until the end of the job while it's still firing
+	Duration int64 `json:"duration"`
+}
+
+// NodeIncident period a node condition was unhealthy
+type NodeIncident struct {
+	Timestamp time.Time `json:"timestamp"`
+	// EndTimestamp time the condition recovered, nil while it's still unhealthy
+	EndTimestamp *time.Time `json:"endTimestamp,omitempty"`
+	Node         string     `json:"node"`
+	Condition    string     `json:"condition"`
+	Status       string     `json:"status"`
+	Reason       string     `json:"reason,omitempty"`
+	Message      string     `json:"message,omitempty"`
+	// Duration time in milliseconds the condition was unhealthy, until the end of the job while it's still unhealthy
+	Duration int64 `json:"duration"`
+}
+
+// ArrivalStats arrivals offered by an open-loop job and what happened to them
+type ArrivalStats struct {
+	Distribution string  `json:"distribution"`
+	Rate         float64 `json:"rate"`
+	OfferedRate  float64 `json:"offeredRate"`
+	Arrivals     int     `json:"arrivals"`
+	Queued       int     `json:"queued"`
+	Dropped      int     `json:"dropped"`
+	MaxInFlight  int     `json:"maxInFlight"`
+	MaxQueued    int     `json:"maxQueued"`
+	// AvgQueueWait average time in milliseconds the queued arrivals waited for a request slot
+	AvgQueueWait int64 `json:"avgQueueWait"`
+}
+
+// IterationPacing iteration slots of a calendar-paced job and how many of them were skipped
+type IterationPacing struct {
+	IterationsPerMinute float64 `json:"iterationsPerMinute"`
+	Slots               int     `json:"slots"`
+	Iterations          int     `json:"iterations"`
+	// SkippedSlots slots passed while the previous iteration was still running
+	SkippedSlots int `json:"skippedSlots"`
+	// MaxIterationTime longest time in milliseconds an iteration took since its slot
+	MaxIterationTime int64 `json:"maxIterationTime"`
+}
+
+// QPSSample target and achieved QPS of a job at a given time
+type QPSSample struct {
+	Timestamp   time.Time `json:"timestamp"`
+	TargetQPS   float64   `json:"targetQps"`
+	AchievedQPS float64   `json:"achievedQps"`
 }
 
 type metricProfile struct {
@@ -62,6 +483,11 @@ type metricDefinition struct {
 	MetricName   string `yaml:"metricName"`
 	Instant      bool   `yaml:"instant"`
 	CaptureStart bool   `yaml:"captureStart"`
+	// EvaluateAt points of the job the instant query is evaluated at: start, end, churnStart or churnEnd, optionally
+	// offset by a duration, like end-5m. Defaults to end
+	EvaluateAt []string `yaml:"evaluateAt"`
+	// Aggregate summarizes the datapoints of the range query over the job, indexed instead of the datapoints
+	Aggregate *aggregation `yaml:"aggregate"`
 }
 
 type metric struct {
@@ -71,6 +497,10 @@ type metric struct {
 	UUID        string            `json:"uuid"`
 	Query       string            `json:"query"`
 	ChurnMetric bool              `json:"churnMetric,omitempty"`
+	Disruptions []string          `json:"disruptions,omitempty"`
+	ScrapeGaps  []string          `json:"scrapeGaps,omitempty"`
+	Aggregation string            `json:"aggregation,omitempty"`
+	Samples     int               `json:"samples,omitempty"`
 	MetricName  string            `json:"metricName,omitempty"`
 	JobName     string            `json:"jobName,omitempty"`
 	Metadata    any               `json:"metadata,omitempty"`
@@ -0,0 +1,237 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Supported report formats
+const (
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+)
+
+const (
+	jobSummaryMetric      = "jobSummary"
+	alertMetric           = "alert"
+	quantilesMetricSuffix = "QuantilesMeasurement"
+)
+
+// document holds the fields used to classify the indexed documents
+type document struct {
+	MetricName string   `json:"metricName"`
+	UUID       string   `json:"uuid"`
+	Query      string   `json:"query"`
+	Value      *float64 `json:"value"`
+	JobName    string   `json:"jobName"`
+}
+
+type jobSummary struct {
+	JobConfig struct {
+		Name          string  `json:"name"`
+		JobType       string  `json:"jobType"`
+		JobIterations int     `json:"jobIterations"`
+		QPS           float32 `json:"qps"`
+		Burst         int     `json:"burst"`
+	} `json:"jobConfig"`
+	Timestamp       time.Time `json:"timestamp"`
+	ElapsedTime     float64   `json:"elapsedTime"`
+	AchievedQps     float64   `json:"achievedQps"`
+	Passed          bool      `json:"passed"`
+	ExecutionErrors string    `json:"executionErrors"`
+}
+
+type quantile struct {
+	QuantileName string `json:"quantileName"`
+	Group        string `json:"group"`
+	P50          int    `json:"P50"`
+	P95          int    `json:"P95"`
+	P99          int    `json:"P99"`
+	Max          int    `json:"max"`
+	Avg          int    `json:"avg"`
+	// Width of the P99 bar, relative to the highest P99 of the table
+	BarWidth int `json:"-"`
+}
+
+type latencyTable struct {
+	MetricName string
+	JobName    string
+	Quantiles  []quantile
+}
+
+type alert struct {
+	Timestamp   time.Time `json:"timestamp"`
+	Severity    string    `json:"severity"`
+	Description string    `json:"description"`
+}
+
+type metricSummary struct {
+	MetricName string
+	JobName    string
+	Samples    int
+	Min        float64
+	Avg        float64
+	Max        float64
+}
+
+// Report holds the results of a benchmark
+type Report struct {
+	UUID      string
+	Generated time.Time
+	Jobs      []jobSummary
+	Latencies []latencyTable
+	Alerts    []alert
+	Metrics   []metricSummary
+}
+
+// NewReport builds the report of the given UUID from the documents found in the metrics directory
+func NewReport(metricsDirectory, uuid string) (Report, error) {
+	report := Report{
+		UUID:      uuid,
+		Generated: time.Now().UTC(),
+	}
+	latencies := map[string]*latencyTable{}
+	metrics := map[string]*metricSummary{}
+	files, err := filepath.Glob(filepath.Join(metricsDirectory, "*.json"))
+	if err != nil {
+		return report, err
+	}
+	if len(files) == 0 {
+		return report, fmt.Errorf("no metric files found in %s", metricsDirectory)
+	}
+	for _, file := range files {
+		var rawDocuments []json.RawMessage
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return report, err
+		}
+		if err := json.Unmarshal(data, &rawDocuments); err != nil {
+			log.Warnf("Skipping %s: %v", file, err)
+			continue
+		}
+		for _, rawDocument := range rawDocuments {
+			var doc document
+			if err := json.Unmarshal(rawDocument, &doc); err != nil || doc.UUID != uuid {
+				continue
+			}
+			switch {
+			case doc.MetricName == jobSummaryMetric:
+				var js jobSummary
+				if err := json.Unmarshal(rawDocument, &js); err == nil {
+					report.Jobs = append(report.Jobs, js)
+				}
+			case doc.MetricName == alertMetric:
+				var a alert
+				if err := json.Unmarshal(rawDocument, &a); err == nil {
+					report.Alerts = append(report.Alerts, a)
+				}
+			case strings.HasSuffix(doc.MetricName, quantilesMetricSuffix):
+				var q quantile
+				if err := json.Unmarshal(rawDocument, &q); err != nil {
+					continue
+				}
+				key := doc.MetricName + "/" + doc.JobName
+				if _, exists := latencies[key]; !exists {
+					latencies[key] = &latencyTable{MetricName: doc.MetricName, JobName: doc.JobName}
+				}
+				latencies[key].Quantiles = append(latencies[key].Quantiles, q)
+			case doc.Query != "" && doc.Value != nil:
+				key := doc.MetricName + "/" + doc.JobName
+				ms, exists := metrics[key]
+				if !exists {
+					ms = &metricSummary{MetricName: doc.MetricName, JobName: doc.JobName, Min: math.Inf(1), Max: math.Inf(-1)}
+					metrics[key] = ms
+				}
+				ms.Samples++
+				ms.Min = min(ms.Min, *doc.Value)
+				ms.Max = max(ms.Max, *doc.Value)
+				// Running average to avoid keeping all the samples
+				ms.Avg += (*doc.Value - ms.Avg) / float64(ms.Samples)
+			}
+		}
+	}
+	if len(report.Jobs) == 0 {
+		return report, fmt.Errorf("no job summaries found for UUID %s in %s", uuid, metricsDirectory)
+	}
+	slices.SortFunc(report.Jobs, func(a, b jobSummary) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	slices.SortFunc(report.Alerts, func(a, b alert) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	for _, lt := range latencies {
+		slices.SortFunc(lt.Quantiles, func(a, b quantile) int {
+			return cmp.Or(cmp.Compare(a.Group, b.Group), cmp.Compare(a.QuantileName, b.QuantileName))
+		})
+		maxP99 := 0
+		for _, q := range lt.Quantiles {
+			maxP99 = max(maxP99, q.P99)
+		}
+		for i := range lt.Quantiles {
+			if maxP99 > 0 {
+				lt.Quantiles[i].BarWidth = lt.Quantiles[i].P99 * 100 / maxP99
+			}
+		}
+		report.Latencies = append(report.Latencies, *lt)
+	}
+	slices.SortFunc(report.Latencies, func(a, b latencyTable) int {
+		return cmp.Or(cmp.Compare(a.JobName, b.JobName), cmp.Compare(a.MetricName, b.MetricName))
+	})
+	for _, ms := range metrics {
+		report.Metrics = append(report.Metrics, *ms)
+	}
+	slices.SortFunc(report.Metrics, func(a, b metricSummary) int {
+		return cmp.Or(cmp.Compare(a.JobName, b.JobName), cmp.Compare(a.MetricName, b.MetricName))
+	})
+	return report, nil
+}
+
+// Render writes the report in the given format
+func (r Report) Render(w io.Writer, format string) error {
+	funcs := map[string]any{
+		"round": func(v float64) string {
+			return fmt.Sprintf("%.2f", v)
+		},
+	}
+	switch format {
+	case FormatHTML:
+		tpl, err := htmltemplate.New("report").Funcs(funcs).Parse(htmlTemplate)
+		if err != nil {
+			return err
+		}
+		return tpl.Execute(w, r)
+	case FormatMarkdown:
+		tpl, err := texttemplate.New("report").Funcs(funcs).Parse(markdownTemplate)
+		if err != nil {
+			return err
+		}
+		return tpl.Execute(w, r)
+	}
+	return fmt.Errorf("unsupported report format %s, supported formats: %s, %s", format, FormatHTML, FormatMarkdown)
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+const markdownTemplate = `# Kube-burner report
+
+- UUID: {{ .UUID }}
+- Generated: {{ .Generated.Format "2006-01-02T15:04:05Z07:00" }}
+
+## Jobs
+
+| Job | Type | Iterations | QPS/Burst | Elapsed time (s) | Achieved QPS | Passed |
+|-----|------|------------|-----------|------------------|--------------|--------|
+{{- range .Jobs }}
+| {{ .JobConfig.Name }} | {{ .JobConfig.JobType }} | {{ .JobConfig.JobIterations }} | {{ .JobConfig.QPS }}/{{ .JobConfig.Burst }} | {{ round .ElapsedTime }} | {{ round .AchievedQps }} | {{ .Passed }} |
+{{- end }}
+{{- range .Jobs }}{{ if .ExecutionErrors }}
+
+**{{ .JobConfig.Name }} errors**: {{ .ExecutionErrors }}
+{{- end }}{{ end }}
+{{- if .Latencies }}
+
+## Latencies
+{{- range .Latencies }}
+
+### {{ .JobName }}: {{ .MetricName }}
+
+| Quantile | Group | P50 (ms) | P95 (ms) | P99 (ms) | Max (ms) | Avg (ms) |
+|----------|-------|----------|----------|----------|----------|----------|
+{{- range .Quantiles }}
+| {{ .QuantileName }} | {{ .Group }} | {{ .P50 }} | {{ .P95 }} | {{ .P99 }} | {{ .Max }} | {{ .Avg }} |
+{{- end }}
+{{- end }}
+{{- end }}
+
+## Alerts
+{{ if .Alerts }}
+| Timestamp | Severity | Description |
+|-----------|----------|-------------|
+{{- range .Alerts }}
+| {{ .Timestamp.Format "2006-01-02T15:04:05Z07:00" }} | {{ .Severity }} | {{ .Description }} |
+{{- end }}
+{{- else }}
+No alerts fired
+{{- end }}
+{{- if .Metrics }}
+
+## Metrics
+
+| Job | Metric | Samples | Min | Avg | Max |
+|-----|--------|---------|-----|-----|-----|
+{{- range .Metrics }}
+| {{ .JobName }} | {{ .MetricName }} | {{ .Samples }} | {{ round .Min }} | {{ round .Avg }} | {{ round .Max }} |
+{{- end }}
+{{- end }}
+`
+
+const htmlTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Kube-burner report {{ .UUID }}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+th { background: #eee; }
+.bar { background: #e8590c; height: 12px; }
+.chart { width: 300px; }
+.failed { color: #c92a2a; font-weight: bold; }
+.critical, .error { color: #c92a2a; }
+.warning { color: #e67700; }
+</style>
+</head>
+<body>
+<h1>Kube-burner report</h1>
+<p>UUID: {{ .UUID }}<br>Generated: {{ .Generated.Format "2006-01-02T15:04:05Z07:00" }}</p>
+<h2>Jobs</h2>
+<table>
+<tr><th>Job</th><th>Type</th><th>Iterations</th><th>QPS/Burst</th><th>Elapsed time (s)</th><th>Achieved QPS</th><th>Passed</th><th>Errors</th></tr>
+{{- range .Jobs }}
+<tr><td>{{ .JobConfig.Name }}</td><td>{{ .JobConfig.JobType }}</td><td>{{ .JobConfig.JobIterations }}</td><td>{{ .JobConfig.QPS }}/{{ .JobConfig.Burst }}</td><td>{{ round .ElapsedTime }}</td><td>{{ round .AchievedQps }}</td><td{{ if not .Passed }} class="failed"{{ end }}>{{ .Passed }}</td><td>{{ .ExecutionErrors }}</td></tr>
+{{- end }}
+</table>
+{{- if .Latencies }}
+<h2>Latencies</h2>
+{{- range .Latencies }}
+<h3>{{ .JobName }}: {{ .MetricName }}</h3>
+<table>
+<tr><th>Quantile</th><th>Group</th><th>P50 (ms)</th><th>P95 (ms)</th><th>P99 (ms)</th><th>Max (ms)</th><th>Avg (ms)</th><th>P99</th></tr>
+{{- range .Quantiles }}
+<tr><td>{{ .QuantileName }}</td><td>{{ .Group }}</td><td>{{ .P50 }}</td><td>{{ .P95 }}</td><td>{{ .P99 }}</td><td>{{ .Max }}</td><td>{{ .Avg }}</td><td class="chart"><div class="bar" style="width: {{ .BarWidth }}%"></div></td></tr>
+{{- end }}
+</table>
+{{- end }}
+{{- end }}
+<h2>Alerts</h2>
+{{- if .Alerts }}
+<table>
+<tr><th>Timestamp</th><th>Severity</th><th>Description</th></tr>
+{{- range .Alerts }}
+<tr><td>{{ .Timestamp.Format "2006-01-02T15:04:05Z07:00" }}</td><td class="{{ .Severity }}">{{ .Severity }}</td><td>{{ .Description }}</td></tr>
+{{- end }}
+</table>
+{{- else }}
+<p>No alerts fired</p>
+{{- end }}
+{{- if .Metrics }}
+<h2>Metrics</h2>
+<table>
+<tr><th>Job</th><th>Metric</th><th>Samples</th><th>Min</th><th>Avg</th><th>Max</th></tr>
+{{- range .Metrics }}
+<tr><td>{{ .JobName }}</td><td>{{ .MetricName }}</td><td>{{ .Samples }}</td><td>{{ round .Min }}</td><td>{{ round .Avg }}</td><td>{{ round .Max }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+</body>
+</html>
+`
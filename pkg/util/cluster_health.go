@@ -2,6 +2,9 @@ package util
 
 import (
 	"context"
+	"fmt"
+	"maps"
+	"slices"
 
 	log "github.com/sirupsen/logrus"
 
@@ -19,26 +22,36 @@ func ClusterHealthCheck(clientSet kubernetes.Interface) {
 }
 
 func ClusterHealthyVanillaK8s(clientset kubernetes.Interface) bool {
-	var isHealthy = true
-	nodes, err := clientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	_, issues, err := NodeHealthIssues(clientset)
 	if err != nil {
 		log.Errorf("Error getting nodes: %v", err)
 		return false
 	}
+	for _, node := range slices.Sorted(maps.Keys(issues)) {
+		for _, issue := range issues[node] {
+			log.Error(issue)
+		}
+	}
+	return len(issues) == 0
+}
 
+// NodeHealthIssues returns the number of nodes and the issues of the unhealthy ones: a Ready condition not True, or a
+// MemoryPressure, DiskPressure, PIDPressure or any other condition not False
+func NodeHealthIssues(clientset kubernetes.Interface) (int, map[string][]string, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return 0, nil, err
+	}
+	issues := make(map[string][]string)
 	for _, node := range nodes.Items {
-		// Check condition for node health check status as Ready, MemoryPressure, DiskPressure, PIDPressure
 		for _, condition := range node.Status.Conditions {
 			if condition.Type == "Ready" && condition.Status != "True" {
-				isHealthy = false
-				log.Errorf("Node %s is not Ready", node.Name)
+				issues[node.Name] = append(issues[node.Name], fmt.Sprintf("Node %s is not Ready", node.Name))
 			}
 			if condition.Type != "Ready" && condition.Status != "False" { //nolint:goconst
-				isHealthy = false
-				log.Errorf("Node %s is experiencing %s", node.Name, condition.Type)
+				issues[node.Name] = append(issues[node.Name], fmt.Sprintf("Node %s is experiencing %s", node.Name, condition.Type))
 			}
-
 		}
 	}
-	return isHealthy
+	return len(nodes.Items), issues, nil
 }
@@ -0,0 +1,170 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/expfmt"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ClusterMetadataKey metadata key of the discovered cluster metadata
+const ClusterMetadataKey = "cluster"
+
+const clusterMetadataTimeout = 30 * time.Second
+
+// Name prefixes of the DaemonSets deployed by the most common CNI plugins
+var cniDaemonSets = []struct {
+	prefix string
+	cni    string
+}{
+	{"ovnkube-node", "ovn-kubernetes"},
+	{"calico-node", "calico"},
+	{"cilium", "cilium"},
+	{"kube-flannel", "flannel"},
+	{"aws-node", "aws-vpc-cni"},
+	{"azure-cns", "azure-cni"},
+	{"antrea-agent", "antrea"},
+	{"weave-net", "weave"},
+	{"kube-router", "kube-router"},
+	{"kindnet", "kindnet"},
+	{"sdn", "openshift-sdn"},
+}
+
+// Version suffixes of the Kubernetes distributions
+var versionDistributions = map[string]string{
+	"+k3s":  "k3s",
+	"+rke2": "rke2",
+	"-eks-": "eks",
+	"-gke.": "gke",
+}
+
+// ClusterMetadata description of the cluster attached to the indexed documents, so that runs on different clusters
+// can be compared
+type ClusterMetadata struct {
+	Version      string `json:"version"`
+	Distribution string `json:"distribution,omitempty"`
+	// Provider infrastructure provider, from the provider ID of the nodes
+	Provider      string         `json:"provider,omitempty"`
+	CNI           string         `json:"cni,omitempty"`
+	Nodes         int            `json:"nodes"`
+	NodeRoles     map[string]int `json:"nodeRoles,omitempty"`
+	InstanceTypes map[string]int `json:"instanceTypes,omitempty"`
+	Architectures map[string]int `json:"architectures,omitempty"`
+	// FeatureGates alpha and beta features of the API server and whether they're enabled
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+}
+
+// DiscoverClusterMetadata discovers the metadata of the cluster, what can't be discovered is left empty
+func DiscoverClusterMetadata(clientSet kubernetes.Interface) ClusterMetadata {
+	ctx, cancel := context.WithTimeout(context.Background(), clusterMetadataTimeout)
+	defer cancel()
+	var metadata ClusterMetadata
+	if version, err := clientSet.Discovery().ServerVersion(); err != nil {
+		log.Warnf("Cluster metadata: error getting the server version: %v", err)
+	} else {
+		metadata.Version = version.GitVersion
+		for suffix, distribution := range versionDistributions {
+			if strings.Contains(version.GitVersion, suffix) {
+				metadata.Distribution = distribution
+			}
+		}
+	}
+	if groups, err := clientSet.Discovery().ServerGroups(); err == nil {
+		for _, group := range groups.Groups {
+			if group.Name == "config.openshift.io" {
+				metadata.Distribution = "openshift"
+			}
+		}
+	}
+	if nodes, err := clientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{}); err != nil {
+		log.Warnf("Cluster metadata: error listing nodes: %v", err)
+	} else {
+		metadata.Nodes = len(nodes.Items)
+		metadata.NodeRoles = make(map[string]int)
+		metadata.InstanceTypes = make(map[string]int)
+		metadata.Architectures = make(map[string]int)
+		for _, node := range nodes.Items {
+			if provider, _, ok := strings.Cut(node.Spec.ProviderID, "://"); ok && metadata.Provider == "" {
+				metadata.Provider = provider
+			}
+			for label := range node.Labels {
+				if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok {
+					metadata.NodeRoles[role]++
+				}
+			}
+			if instanceType := node.Labels["node.kubernetes.io/instance-type"]; instanceType != "" {
+				metadata.InstanceTypes[instanceType]++
+			}
+			metadata.Architectures[node.Status.NodeInfo.Architecture]++
+		}
+	}
+	if daemonSets, err := clientSet.AppsV1().DaemonSets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{}); err != nil {
+		log.Warnf("Cluster metadata: error listing daemonsets: %v", err)
+	} else {
+	cni:
+		for _, candidate := range cniDaemonSets {
+			for _, ds := range daemonSets.Items {
+				if strings.HasPrefix(ds.Name, candidate.prefix) {
+					metadata.CNI = candidate.cni
+					break cni
+				}
+			}
+		}
+	}
+	metadata.FeatureGates = featureGates(ctx, clientSet)
+	return metadata
+}
+
+// featureGates returns the alpha and beta features of the API server from its kubernetes_feature_enabled metric
+func featureGates(ctx context.Context, clientSet kubernetes.Interface) map[string]bool {
+	data, err := clientSet.CoreV1().RESTClient().Get().AbsPath("/metrics").DoRaw(ctx)
+	if err != nil {
+		log.Warnf("Cluster metadata: error getting the API server metrics: %v", err)
+		return nil
+	}
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(data))
+	if err != nil {
+		log.Warnf("Cluster metadata: error parsing the API server metrics: %v", err)
+		return nil
+	}
+	family, ok := families["kubernetes_feature_enabled"]
+	if !ok {
+		return nil
+	}
+	features := make(map[string]bool)
+	for _, metric := range family.GetMetric() {
+		var name, stage string
+		for _, label := range metric.GetLabel() {
+			switch label.GetName() {
+			case "name":
+				name = label.GetValue()
+			case "stage":
+				stage = label.GetValue()
+			}
+		}
+		if stage == "ALPHA" || stage == "BETA" {
+			features[name] = metric.GetGauge().GetValue() > 0
+		}
+	}
+	return features
+}
@@ -0,0 +1,209 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Sides of an A/B benchmark
+const (
+	CompareSideA = "a"
+	CompareSideB = "b"
+)
+
+// lockstep keeps the two sides of an A/B benchmark in step: a side doesn't start a job until the other one reaches it,
+// and once they finish it they exchange their results
+type lockstep struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	// started highest job sequence number reached by each side
+	started map[string]int
+	// results results of each side by job sequence number
+	results map[string]map[int][]byte
+	// done sides whose process finished
+	done map[string]bool
+}
+
+func peerSide(side string) string {
+	if side == CompareSideA {
+		return CompareSideB
+	}
+	return CompareSideA
+}
+
+// RunComparison launches two processes re-executing the current command line, sharing the same UUID, one against the
+// cluster of the command line and the other against the given kubeconfig and context. Returns the highest return code
+func RunComparison(uuid, kubeConfig, kubeContext string) int {
+	ls := &lockstep{
+		started: map[string]int{CompareSideA: -1, CompareSideB: -1},
+		results: map[string]map[int][]byte{CompareSideA: {}, CompareSideB: {}},
+		done:    map[string]bool{},
+	}
+	ls.cond = sync.NewCond(&ls.mu)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatalf("Error starting the A/B lockstep server: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/start", ls.handleStart)
+	mux.HandleFunc("/result", ls.handleResult)
+	go http.Serve(listener, mux)
+	log.Infof("Launching A/B benchmark with UUID %s", uuid)
+	sides := []string{CompareSideA, CompareSideB}
+	var names []string
+	var args [][]string
+	for _, side := range sides {
+		sideArgs := slices.Concat(os.Args[1:], []string{
+			fmt.Sprintf("--uuid=%s", uuid),
+			fmt.Sprintf("--compare-side=%s", side),
+			fmt.Sprintf("--compare-address=%s", listener.Addr()),
+		})
+		// The last occurrence of a flag wins
+		if side == CompareSideB {
+			if kubeConfig != "" {
+				sideArgs = append(sideArgs, fmt.Sprintf("--kubeconfig=%s", kubeConfig))
+			}
+			if kubeContext != "" {
+				sideArgs = append(sideArgs, fmt.Sprintf("--kube-context=%s", kubeContext))
+			}
+		}
+		names = append(names, fmt.Sprintf("Cluster %s", side))
+		args = append(args, sideArgs)
+	}
+	return runProcesses(names, args, func(i int) {
+		ls.mu.Lock()
+		ls.done[sides[i]] = true
+		ls.cond.Broadcast()
+		ls.mu.Unlock()
+	})
+}
+
+func lockstepParams(r *http.Request) (string, int, error) {
+	side := r.URL.Query().Get("side")
+	if side != CompareSideA && side != CompareSideB {
+		return "", 0, fmt.Errorf("invalid side %q", side)
+	}
+	seq, err := strconv.Atoi(r.URL.Query().Get("seq"))
+	return side, seq, err
+}
+
+// handleStart blocks until the other side reaches the job, or finishes
+func (ls *lockstep) handleStart(w http.ResponseWriter, r *http.Request) {
+	side, seq, err := lockstepParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	peer := peerSide(side)
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.started[side] = max(ls.started[side], seq)
+	ls.cond.Broadcast()
+	for !ls.done[peer] && ls.started[peer] < seq {
+		ls.cond.Wait()
+	}
+}
+
+// handleResult stores the result of the job and returns the one of the other side, or no content when the other side
+// didn't run the job: it moved on to the next one, or finished, without sending its result
+func (ls *lockstep) handleResult(w http.ResponseWriter, r *http.Request) {
+	side, seq, err := lockstepParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	peer := peerSide(side)
+	ls.mu.Lock()
+	ls.results[side][seq] = result
+	ls.cond.Broadcast()
+	var peerResult []byte
+	for {
+		var ok bool
+		if peerResult, ok = ls.results[peer][seq]; ok || ls.done[peer] || ls.started[peer] > seq {
+			break
+		}
+		ls.cond.Wait()
+	}
+	ls.mu.Unlock()
+	if peerResult == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.Write(peerResult)
+}
+
+// LockstepClient client of the A/B lockstep server used by each side
+type LockstepClient struct {
+	Side    string
+	address string
+	// Requests wait for the other side, they aren't timed out
+	client *http.Client
+}
+
+// NewLockstepClient returns a client of the lockstep server listening on address
+func NewLockstepClient(address, side string) *LockstepClient {
+	return &LockstepClient{Side: side, address: address, client: &http.Client{}}
+}
+
+func (c *LockstepClient) post(path string, seq int, body []byte) (*http.Response, error) {
+	url := fmt.Sprintf("http://%s%s?side=%s&seq=%d", c.address, path, c.Side, seq)
+	resp, err := c.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		resp.Body.Close()
+		return nil, fmt.Errorf("lockstep server returned %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// Start waits until the other side reaches the job with the given sequence number
+func (c *LockstepClient) Start(seq int) error {
+	resp, err := c.post("/start", seq, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Exchange sends the result of the job and returns the one of the other side, nil when it didn't run the job
+func (c *LockstepClient) Exchange(seq int, result []byte) ([]byte, error) {
+	resp, err := c.post("/result", seq, result)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+	return io.ReadAll(resp.Body)
+}
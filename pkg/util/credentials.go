@@ -0,0 +1,248 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	redacted        = "<redacted>"
+	passwordCharset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	// Shorter values aren't redacted, they would match unrelated text
+	minSecretLength = 6
+)
+
+// Validity of the generated certificates, fixed so that certificates are the same on every run
+var (
+	certNotBefore = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	certNotAfter  = time.Date(2100, time.January, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// sensitiveValues values generated by the credential functions, redacted from the logs and the indexed errors
+var sensitiveValues = struct {
+	sync.RWMutex
+	values   map[string]struct{}
+	replacer *strings.Replacer
+}{values: make(map[string]struct{})}
+
+func init() {
+	AddRenderingFunction("RandomPassword", randomPassword)
+	AddRenderingFunction("Htpasswd", htpasswd)
+	AddRenderingFunction("SelfSignedCert", selfSignedCert)
+	AddRenderingFunction("SSHKeyPair", sshKeyPair)
+	log.AddHook(redactHook{})
+}
+
+// seededReader returns a source of random bytes derived from the seed, distinct for each purpose
+func seededReader(purpose, seed string) *rand.ChaCha8 {
+	return rand.NewChaCha8(sha256.Sum256([]byte(purpose + ":" + seed)))
+}
+
+// seededKey returns an Ed25519 key derived from the random source
+func seededKey(r io.Reader) (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := io.ReadFull(r, seed); err != nil {
+		return nil, nil, err
+	}
+	private := ed25519.NewKeyFromSeed(seed)
+	return private.Public().(ed25519.PublicKey), private, nil
+}
+
+// addSensitive registers a generated value, along with its base64 encoding as it's commonly used in Secrets
+func addSensitive(value string) {
+	if len(value) < minSecretLength {
+		return
+	}
+	sensitiveValues.Lock()
+	defer sensitiveValues.Unlock()
+	sensitiveValues.values[value] = struct{}{}
+	sensitiveValues.values[base64.StdEncoding.EncodeToString([]byte(value))] = struct{}{}
+	sensitiveValues.replacer = nil
+}
+
+// addSensitivePEM registers a PEM encoded key, and each line of its body so that it's redacted once indented
+func addSensitivePEM(value string) {
+	addSensitive(value)
+	for _, line := range strings.Split(value, "\n") {
+		if !strings.HasPrefix(line, "-----") {
+			addSensitive(line)
+		}
+	}
+}
+
+// RedactSecrets replaces the values generated by the credential functions found in s
+func RedactSecrets(s string) string {
+	sensitiveValues.RLock()
+	replacer, empty := sensitiveValues.replacer, len(sensitiveValues.values) == 0
+	sensitiveValues.RUnlock()
+	if empty {
+		return s
+	}
+	if replacer == nil {
+		sensitiveValues.Lock()
+		if sensitiveValues.replacer == nil {
+			var oldnew []string
+			for value := range sensitiveValues.values {
+				oldnew = append(oldnew, value, redacted)
+			}
+			sensitiveValues.replacer = strings.NewReplacer(oldnew...)
+		}
+		replacer = sensitiveValues.replacer
+		sensitiveValues.Unlock()
+	}
+	return replacer.Replace(s)
+}
+
+// redactHook redacts the generated credentials from the log entries
+type redactHook struct{}
+
+func (redactHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+func (redactHook) Fire(entry *log.Entry) error {
+	entry.Message = RedactSecrets(entry.Message)
+	if len(entry.Data) == 0 {
+		return nil
+	}
+	// The fields may be shared with other entries, they're replaced rather than modified
+	data := make(log.Fields, len(entry.Data))
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			value = RedactSecrets(v)
+		case error:
+			if redactedErr := RedactSecrets(v.Error()); redactedErr != v.Error() {
+				value = errors.New(redactedErr)
+			}
+		case fmt.Stringer:
+			if redactedValue := RedactSecrets(v.String()); redactedValue != v.String() {
+				value = redactedValue
+			}
+		}
+		data[key] = value
+	}
+	entry.Data = data
+	return nil
+}
+
+// randomPassword returns an alphanumeric password derived from the seed
+func randomPassword(seed string, length int) (string, error) {
+	if length < 1 {
+		return "", fmt.Errorf("invalid password length %d", length)
+	}
+	r := rand.New(seededReader("password", seed))
+	password := make([]byte, length)
+	for i := range password {
+		password[i] = passwordCharset[r.IntN(len(passwordCharset))]
+	}
+	addSensitive(string(password))
+	return string(password), nil
+}
+
+// htpasswd returns an htpasswd entry with the bcrypt hash of the password
+func htpasswd(user, password string) (string, error) {
+	// Passwords not generated by RandomPassword must be redacted too
+	addSensitive(password)
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%s", user, hash), nil
+}
+
+// selfSignedCert returns a self-signed Ed25519 certificate and its private key derived from the seed, as PEM.
+// Ed25519 is used because its keys, unlike RSA and ECDSA ones, can be derived deterministically
+func selfSignedCert(seed, commonName string, dnsNames ...string) (map[string]string, error) {
+	r := seededReader("certificate", seed)
+	public, private, err := seededKey(r)
+	if err != nil {
+		return nil, err
+	}
+	serial := make([]byte, 16)
+	if _, err := io.ReadFull(r, serial); err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          new(big.Int).SetBytes(serial),
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              append([]string{commonName}, dnsNames...),
+		NotBefore:             certNotBefore,
+		NotAfter:              certNotAfter,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	cert, err := x509.CreateCertificate(r, template, template, public, private)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}))
+	addSensitivePEM(keyPEM)
+	return map[string]string{
+		"Cert": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})),
+		"Key":  keyPEM,
+	}, nil
+}
+
+// sshKeyPair returns an Ed25519 SSH key pair derived from the seed: the private key in OpenSSH format and
+// the public key in authorized_keys format
+func sshKeyPair(seed, comment string) (map[string]string, error) {
+	public, private, err := seededKey(seededReader("ssh", seed))
+	if err != nil {
+		return nil, err
+	}
+	block, err := ssh.MarshalPrivateKey(private, comment)
+	if err != nil {
+		return nil, err
+	}
+	sshPublic, err := ssh.NewPublicKey(public)
+	if err != nil {
+		return nil, err
+	}
+	privatePEM := string(pem.EncodeToMemory(block))
+	addSensitivePEM(privatePEM)
+	authorizedKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPublic)))
+	if comment != "" {
+		authorizedKey += " " + comment
+	}
+	return map[string]string{
+		"PrivateKey": privatePEM,
+		"PublicKey":  authorizedKey,
+	}, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Test suites
+const (
+	SuiteJobs       = "jobs"
+	SuiteThresholds = "thresholds"
+	SuiteAlerts     = "alerts"
+)
+
+type testSuites struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Suites   []testSuite `xml:"testsuite"`
+}
+
+type testSuite struct {
+	Name     string     `xml:"name,attr"`
+	Tests    int        `xml:"tests,attr"`
+	Failures int        `xml:"failures,attr"`
+	Time     string     `xml:"time,attr"`
+	Cases    []testCase `xml:"testcase"`
+}
+
+type testCase struct {
+	Name      string   `xml:"name,attr"`
+	Classname string   `xml:"classname,attr"`
+	Time      string   `xml:"time,attr"`
+	Failure   *failure `xml:"failure,omitempty"`
+	duration  time.Duration
+}
+
+type failure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+var (
+	suites = map[string]*testSuite{}
+	mu     sync.Mutex
+)
+
+// AddTestCase records a test case in the given suite, the test case fails when err is not nil
+func AddTestCase(suite, name string, duration time.Duration, err error) {
+	mu.Lock()
+	defer mu.Unlock()
+	ts, exists := suites[suite]
+	if !exists {
+		ts = &testSuite{Name: suite}
+		suites[suite] = ts
+	}
+	tc := testCase{
+		Name:      name,
+		Classname: "kube-burner." + suite,
+		Time:      fmt.Sprintf("%.3f", duration.Seconds()),
+		duration:  duration,
+	}
+	if err != nil {
+		tc.Failure = &failure{
+			Message: err.Error(),
+			Type:    suite,
+			Text:    err.Error(),
+		}
+		ts.Failures++
+	}
+	ts.Tests++
+	ts.Cases = append(ts.Cases, tc)
+}
+
+// Write writes the recorded test cases into the given file in JUnit XML format
+func Write(file, name string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	report := testSuites{Name: name}
+	for _, suite := range []string{SuiteJobs, SuiteThresholds, SuiteAlerts} {
+		ts, exists := suites[suite]
+		if !exists {
+			continue
+		}
+		var elapsed time.Duration
+		for _, tc := range ts.Cases {
+			elapsed += tc.duration
+		}
+		ts.Time = fmt.Sprintf("%.3f", elapsed.Seconds())
+		report.Tests += ts.Tests
+		report.Failures += ts.Failures
+		report.Suites = append(report.Suites, *ts)
+	}
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append([]byte(xml.Header), data...), 0644)
+}
+
+// Failures returns the failure messages of the recorded test cases of the given suite
+func Failures(suite string) []string {
+	mu.Lock()
+	defer mu.Unlock()
+	var failures []string
+	if ts, exists := suites[suite]; exists {
+		for _, tc := range ts.Cases {
+			if tc.Failure != nil {
+				failures = append(failures, tc.Failure.Message)
+			}
+		}
+	}
+	return failures
+}
+
+// Tests returns the number of recorded test cases of the given suite
+func Tests(suite string) int {
+	mu.Lock()
+	defer mu.Unlock()
+	if ts, exists := suites[suite]; exists {
+		return ts.Tests
+	}
+	return 0
+}
+
+// Reset discards the recorded test cases, used by processes running several benchmarks
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	suites = map[string]*testSuite{}
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	log "github.com/sirupsen/logrus"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// Monitoring stacks whose metrics endpoints are discovered
+const (
+	stackOpenShift           = "openshift-monitoring"
+	stackGMP                 = "google-managed-prometheus"
+	stackKubePrometheusStack = "kube-prometheus-stack"
+)
+
+const (
+	openShiftMonitoringNamespace = "openshift-monitoring"
+	gmpNamespace                 = "gmp-system"
+	// Label of the services created by prometheus-operator for its Prometheus instances
+	prometheusOperatedLabel = "operated-prometheus=true"
+)
+
+// Summary metadata field holding the discovered endpoints by indexer alias
+const discoveredEndpointsMetadata = "discoveredMetricsEndpoints"
+
+var routeGVR = schema.GroupVersionResource{Group: "route.openshift.io", Version: "v1", Resource: "routes"}
+
+// DiscoveredEndpoint metrics endpoint discovered from an in-cluster monitoring stack
+type DiscoveredEndpoint struct {
+	Stack    string `json:"stack"`
+	Endpoint string `json:"endpoint"`
+}
+
+// endpointDiscoverer discovers the endpoint of a monitoring stack, returns an empty endpoint when the stack isn't installed
+type endpointDiscoverer func(ctx context.Context, clientSet kubernetes.Interface, restConfig *rest.Config, metricsEndpoint *config.MetricsEndpoint) (string, error)
+
+// DiscoverEndpoint sets up the metrics endpoint from the first well-known monitoring stack found in the cluster:
+// OpenShift monitoring, Google Managed Prometheus and kube-prometheus-stack, in that order
+func DiscoverEndpoint(metricsEndpoint *config.MetricsEndpoint, kubeClientProvider *config.KubeClientProvider, timeout time.Duration) (*DiscoveredEndpoint, error) {
+	if kubeClientProvider == nil {
+		return nil, fmt.Errorf("metrics endpoint discovery requires access to the Kubernetes API")
+	}
+	clientSet, restConfig := kubeClientProvider.DefaultClientSet()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	for _, stack := range []struct {
+		name     string
+		discover endpointDiscoverer
+	}{
+		{stackOpenShift, discoverOpenShift(timeout)},
+		{stackGMP, discoverGMP},
+		{stackKubePrometheusStack, discoverKubePrometheusStack},
+	} {
+		endpoint, err := stack.discover(ctx, clientSet, restConfig, metricsEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("error discovering %s endpoint: %v", stack.name, err)
+		}
+		if endpoint != "" {
+			log.Infof("Discovered %s metrics endpoint: %s", stack.name, endpoint)
+			return &DiscoveredEndpoint{Stack: stack.name, Endpoint: endpoint}, nil
+		}
+	}
+	return nil, fmt.Errorf("no well-known monitoring stack found in the cluster")
+}
+
+// discoverOpenShift uses the Thanos querier route, authenticating with the kubeconfig token, or otherwise with a token
+// of the Prometheus service account lasting the benchmark timeout
+func discoverOpenShift(timeout time.Duration) endpointDiscoverer {
+	return func(ctx context.Context, clientSet kubernetes.Interface, restConfig *rest.Config, metricsEndpoint *config.MetricsEndpoint) (string, error) {
+		dynamicClient, err := dynamic.NewForConfig(restConfig)
+		if err != nil {
+			return "", err
+		}
+		route, err := dynamicClient.Resource(routeGVR).Namespace(openShiftMonitoringNamespace).Get(ctx, "thanos-querier", metav1.GetOptions{})
+		if err != nil {
+			// Not an OpenShift cluster, or not allowed to read the route
+			log.Debugf("Thanos querier route not found: %v", err)
+			return "", nil
+		}
+		host, _, _ := unstructured.NestedString(route.Object, "spec", "host")
+		if host == "" {
+			return "", nil
+		}
+		if metricsEndpoint.Token == "" && metricsEndpoint.Username == "" {
+			metricsEndpoint.Token = restConfig.BearerToken
+		}
+		if metricsEndpoint.Token == "" && metricsEndpoint.Username == "" {
+			expiration := int64((timeout + time.Hour).Seconds())
+			tokenRequest, err := clientSet.CoreV1().ServiceAccounts(openShiftMonitoringNamespace).CreateToken(ctx, "prometheus-k8s", &authenticationv1.TokenRequest{
+				Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expiration},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				return "", fmt.Errorf("error requesting a token of service account %s/prometheus-k8s: %v", openShiftMonitoringNamespace, err)
+			}
+			metricsEndpoint.Token = tokenRequest.Status.Token
+		}
+		metricsEndpoint.Endpoint = "https://" + host
+		return metricsEndpoint.Endpoint, nil
+	}
+}
+
+// discoverGMP uses the Prometheus API of Cloud Monitoring for the project of the nodes, authenticating with the
+// access token of the gcloud CLI
+func discoverGMP(ctx context.Context, clientSet kubernetes.Interface, restConfig *rest.Config, metricsEndpoint *config.MetricsEndpoint) (string, error) {
+	if _, err := clientSet.CoreV1().Namespaces().Get(ctx, gmpNamespace, metav1.GetOptions{}); err != nil {
+		log.Debugf("Namespace %s not found: %v", gmpNamespace, err)
+		return "", nil
+	}
+	nodes, err := clientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{Limit: 1})
+	if err != nil {
+		return "", err
+	}
+	// providerID has the form gce://<project>/<zone>/<instance>
+	if len(nodes.Items) == 0 || !strings.HasPrefix(nodes.Items[0].Spec.ProviderID, "gce://") {
+		return "", fmt.Errorf("project of the cluster not found in the providerID of its nodes")
+	}
+	project := strings.Split(strings.TrimPrefix(nodes.Items[0].Spec.ProviderID, "gce://"), "/")[0]
+	if metricsEndpoint.Token == "" && metricsEndpoint.Username == "" {
+		out, err := exec.CommandContext(ctx, "gcloud", "auth", "print-access-token").Output()
+		if err != nil {
+			return "", fmt.Errorf("error getting an access token from the gcloud CLI: %v", err)
+		}
+		metricsEndpoint.Token = strings.TrimSpace(string(out))
+	}
+	metricsEndpoint.Endpoint = fmt.Sprintf("https://monitoring.googleapis.com/v1/projects/%s/location/global/prometheus", project)
+	return metricsEndpoint.Endpoint, nil
+}
+
+// discoverKubePrometheusStack tunnels to the Prometheus pods of the first Prometheus instance managed by
+// prometheus-operator, which kube-prometheus-stack deploys
+func discoverKubePrometheusStack(ctx context.Context, clientSet kubernetes.Interface, restConfig *rest.Config, metricsEndpoint *config.MetricsEndpoint) (string, error) {
+	services, err := clientSet.CoreV1().Services(metav1.NamespaceAll).List(ctx, metav1.ListOptions{LabelSelector: prometheusOperatedLabel})
+	if err != nil {
+		log.Debugf("Error listing prometheus-operator services: %v", err)
+		return "", nil
+	}
+	if len(services.Items) == 0 {
+		return "", nil
+	}
+	namespace := services.Items[0].Namespace
+	metricsEndpoint.PortForward = &config.PortForward{
+		Namespace:     namespace,
+		LabelSelector: map[string]string{"app.kubernetes.io/name": "prometheus"},
+		Port:          9090,
+		Scheme:        "http",
+	}
+	return fmt.Sprintf("portForward %s/app.kubernetes.io/name=prometheus:9090", namespace), nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	log "github.com/sirupsen/logrus"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// destination is one of the indexers documents are fanned out to
+type destination struct {
+	name    string
+	indexer indexers.Indexer
+}
+
+// fanOutIndexer writes the same documents to several indexers simultaneously
+type fanOutIndexer struct {
+	destinations []destination
+}
+
+// NewFanOutIndexer creates an indexer writing documents to all the given indexer configurations
+func NewFanOutIndexer(indexerConfigs []config.IndexerConfig) (indexers.Indexer, error) {
+	var fanOut fanOutIndexer
+	for i, indexerConfig := range indexerConfigs {
+		indexer, err := NewIndexer(indexerConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error creating %s indexer #%d: %v", indexerConfig.Type, i, err)
+		}
+		fanOut.destinations = append(fanOut.destinations, destination{
+			name:    fmt.Sprintf("%s-%d", indexerConfig.Type, i),
+			indexer: indexer,
+		})
+	}
+	return &fanOut, nil
+}
+
+// Index writes the documents to every destination, a failing destination doesn't prevent indexing in the others
+func (f *fanOutIndexer) Index(documents []any, opts indexers.IndexingOpts) (string, error) {
+	var wg sync.WaitGroup
+	responses := make([]string, len(f.destinations))
+	errs := make([]error, len(f.destinations))
+	for i, d := range f.destinations {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := d.indexer.Index(documents, opts)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %v", d.name, err)
+				return
+			}
+			log.Debugf("%s: %s", d.name, resp)
+			responses[i] = fmt.Sprintf("%s: %s", d.name, resp)
+		}()
+	}
+	wg.Wait()
+	var succeeded []string
+	for _, resp := range responses {
+		if resp != "" {
+			succeeded = append(succeeded, resp)
+		}
+	}
+	err := utilerrors.NewAggregate(errs)
+	if err != nil && len(succeeded) > 0 {
+		// Report the successful destinations, callers only log the error on failure
+		log.Infof("%s indexed in %d/%d destinations: %s", opts.MetricName, len(succeeded), len(f.destinations), strings.Join(succeeded, "; "))
+	}
+	return strings.Join(succeeded, "; "), err
+}
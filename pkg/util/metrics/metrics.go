@@ -19,6 +19,7 @@ import (
 
 	"github.com/cloud-bulldozer/go-commons/v2/indexers"
 	"github.com/kube-burner/kube-burner/pkg/alerting"
+	"github.com/kube-burner/kube-burner/pkg/config"
 	"github.com/kube-burner/kube-burner/pkg/prometheus"
 	"github.com/kube-burner/kube-burner/pkg/util"
 	log "github.com/sirupsen/logrus"
@@ -26,21 +27,39 @@ import (
 
 // Processes common config and executes according to the caller
 func ProcessMetricsScraperConfig(scraperConfig ScraperConfig) Scraper {
+	scraper, err := NewScraper(scraperConfig)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	return scraper
+}
+
+// NewScraper creates the indexers, Prometheus clients and alert managers of the metrics endpoints
+func NewScraper(scraperConfig ScraperConfig) (scraper Scraper, err error) {
 	userMetadata := make(map[string]any)
 	if len(scraperConfig.ConfigSpec.MetricsEndpoints) == 0 && scraperConfig.MetricsEndpoint == "" {
-		return Scraper{}
+		return Scraper{}, nil
 	}
-	var err error
 	var indexer *indexers.Indexer
 	var indexerAlias string
 	indexerList := make(map[string]indexers.Indexer)
 	var prometheusClients []*prometheus.Prometheus
 	var alertM *alerting.AlertManager
 	var alertMs []*alerting.AlertManager
+	var recordingRules []*RecordingRules
+	var tunnels []*prometheus.Tunnel
+	// The tunnels already open are closed when a later endpoint fails
+	defer func() {
+		if err != nil {
+			for _, tunnel := range tunnels {
+				tunnel.Close()
+			}
+		}
+	}()
 	if scraperConfig.UserMetaData != "" {
 		userMetadata, err = util.ReadUserMetadata(scraperConfig.UserMetaData)
 		if err != nil {
-			log.Fatalf("Error reading provided user metadata: %v", err)
+			return Scraper{}, fmt.Errorf("error reading provided user metadata: %v", err)
 		}
 	}
 	// Combine users provided metadata with metrics and summary metadata
@@ -52,24 +71,79 @@ func ProcessMetricsScraperConfig(scraperConfig ScraperConfig) Scraper {
 			scraperConfig.MetricsMetadata[k] = v
 		}
 	}
+	if scraperConfig.ConfigSpec.GlobalConfig.ClusterMetadata && scraperConfig.KubeClientProvider != nil {
+		// Set before creating the Prometheus clients and alert managers, which keep a reference of the metrics metadata
+		clientSet, _ := scraperConfig.KubeClientProvider.DefaultClientSet()
+		clusterMetadata := util.DiscoverClusterMetadata(clientSet)
+		if scraperConfig.SummaryMetadata == nil {
+			scraperConfig.SummaryMetadata = make(map[string]any)
+		}
+		if scraperConfig.MetricsMetadata == nil {
+			scraperConfig.MetricsMetadata = make(map[string]any)
+		}
+		scraperConfig.SummaryMetadata[util.ClusterMetadataKey] = clusterMetadata
+		scraperConfig.MetricsMetadata[util.ClusterMetadataKey] = clusterMetadata
+		log.Infof("🔎 Discovered cluster metadata: version %s, %d nodes", clusterMetadata.Version, clusterMetadata.Nodes)
+	}
 	// MetricsEndpoint has preference over the configuration file
 	if scraperConfig.MetricsEndpoint != "" {
 		scraperConfig.ConfigSpec.MetricsEndpoints = DecodeMetricsEndpoint(scraperConfig.MetricsEndpoint)
 	}
 	for pos, metricsEndpoint := range scraperConfig.ConfigSpec.MetricsEndpoints {
 		indexer = nil
+		if metricsEndpoint.Alias == "" {
+			indexerAlias = fmt.Sprintf("indexer-%d", pos)
+		} else {
+			indexerAlias = metricsEndpoint.Alias
+		}
 		if metricsEndpoint.Type != "" {
-			if metricsEndpoint.Alias == "" {
-				indexerAlias = fmt.Sprintf("indexer-%d", pos)
+			log.Infof("📁 Creating %s indexer: %s", metricsEndpoint.Type, indexerAlias)
+			newIndexer, err := NewIndexer(metricsEndpoint.IndexerConfig)
+			if err != nil {
+				return Scraper{}, fmt.Errorf("error creating indexer %d: %v", pos, err)
+			}
+			indexer = &newIndexer
+			indexerList[indexerAlias] = newIndexer
+		} else if len(metricsEndpoint.Indexers) > 0 {
+			log.Infof("📁 Creating fan-out indexer with %d destinations: %s", len(metricsEndpoint.Indexers), indexerAlias)
+			fanOut, err := NewFanOutIndexer(metricsEndpoint.Indexers)
+			if err != nil {
+				return Scraper{}, fmt.Errorf("error creating indexer %d: %v", pos, err)
+			}
+			indexer = &fanOut
+			indexerList[indexerAlias] = fanOut
+		}
+		if metricsEndpoint.RecordingRules != nil {
+			rr, err := readRecordingRules(*metricsEndpoint.RecordingRules, scraperConfig.EmbedCfg)
+			if err != nil {
+				return Scraper{}, fmt.Errorf("error reading recording rules of endpoint #%d: %v", pos, err)
+			}
+			recordingRules = append(recordingRules, rr)
+		}
+		if (len(metricsEndpoint.Metrics) > 0 || len(metricsEndpoint.Alerts) > 0) && metricsEndpoint.Endpoint == "" && metricsEndpoint.UnixSocket == "" && metricsEndpoint.PortForward == nil {
+			discovered, err := DiscoverEndpoint(&metricsEndpoint, scraperConfig.KubeClientProvider, scraperConfig.ConfigSpec.GlobalConfig.Timeout)
+			if err != nil {
+				log.Warnf("No endpoint given for metrics endpoint #%d and discovery failed, its metrics and alerts are skipped: %v", pos, err)
 			} else {
-				indexerAlias = metricsEndpoint.Alias
+				if scraperConfig.SummaryMetadata == nil {
+					scraperConfig.SummaryMetadata = make(map[string]any)
+				}
+				discoveredEndpoints, _ := scraperConfig.SummaryMetadata[discoveredEndpointsMetadata].(map[string]DiscoveredEndpoint)
+				if discoveredEndpoints == nil {
+					discoveredEndpoints = make(map[string]DiscoveredEndpoint)
+					scraperConfig.SummaryMetadata[discoveredEndpointsMetadata] = discoveredEndpoints
+				}
+				discoveredEndpoints[indexerAlias] = *discovered
 			}
-			log.Infof("📁 Creating %s indexer: %s", metricsEndpoint.Type, indexerAlias)
-			indexer, err = indexers.NewIndexer(metricsEndpoint.IndexerConfig)
+		}
+		if len(metricsEndpoint.Metrics) > 0 || len(metricsEndpoint.Alerts) > 0 {
+			tunnel, err := setupTunnel(&metricsEndpoint, scraperConfig.KubeClientProvider)
 			if err != nil {
-				log.Fatalf("Error creating indexer %d: %v", pos, err.Error())
+				return Scraper{}, err
+			}
+			if tunnel != nil {
+				tunnels = append(tunnels, tunnel)
 			}
-			indexerList[indexerAlias] = *indexer
 		}
 		if (len(metricsEndpoint.Metrics) > 0 || len(metricsEndpoint.Alerts) > 0) && metricsEndpoint.Endpoint != "" {
 			auth := prometheus.Auth{
@@ -80,21 +154,25 @@ func ProcessMetricsScraperConfig(scraperConfig ScraperConfig) Scraper {
 			}
 			p, err := prometheus.NewPrometheusClient(*scraperConfig.ConfigSpec, metricsEndpoint.Endpoint, auth, metricsEndpoint.Step, scraperConfig.MetricsMetadata, indexer)
 			if err != nil {
-				log.Fatal(err)
+				return Scraper{}, err
 			}
 			prometheusClients = append(prometheusClients, p)
 			for _, metricProfile := range metricsEndpoint.Metrics {
 				if indexer == nil {
-					log.Fatalf("Metrics profile is configured for endpoint #%d but no indexer was defined", pos)
+					return Scraper{}, fmt.Errorf("metrics profile is configured for endpoint #%d but no indexer was defined", pos)
 				}
 				if err := p.ReadProfile(metricProfile, scraperConfig.EmbedCfg); err != nil {
-					log.Fatal(err.Error())
+					return Scraper{}, err
 				}
 			}
+			if metricsEndpoint.TargetHealth != nil {
+				p.SetTargetHealth(*metricsEndpoint.TargetHealth)
+			}
 			for _, alertProfile := range metricsEndpoint.Alerts {
 				if alertM, err = alerting.NewAlertManager(alertProfile, scraperConfig.ConfigSpec.GlobalConfig.UUID, p, indexer, scraperConfig.MetricsMetadata, scraperConfig.EmbedCfg); err != nil {
-					log.Fatalf("Error creating alert manager: %s", err)
+					return Scraper{}, fmt.Errorf("error creating alert manager: %s", err)
 				}
+				alertM.SetNotifications(scraperConfig.ConfigSpec.GlobalConfig.AlertNotifications)
 				alertMs = append(alertMs, alertM)
 			}
 		}
@@ -105,5 +183,32 @@ func ProcessMetricsScraperConfig(scraperConfig ScraperConfig) Scraper {
 		IndexerList:       indexerList,
 		SummaryMetadata:   scraperConfig.SummaryMetadata,
 		MetricsMetadata:   scraperConfig.MetricsMetadata,
+		RecordingRules:    recordingRules,
+		Tunnels:           tunnels,
+	}, nil
+}
+
+// setupTunnel overrides the endpoint URL when Prometheus is reached through a UNIX socket or a port-forward
+func setupTunnel(metricsEndpoint *config.MetricsEndpoint, kubeClientProvider *config.KubeClientProvider) (*prometheus.Tunnel, error) {
+	var tunnel *prometheus.Tunnel
+	var err error
+	switch {
+	case metricsEndpoint.UnixSocket != "":
+		tunnel, err = prometheus.NewUnixSocketTunnel(metricsEndpoint.UnixSocket)
+	case metricsEndpoint.PortForward != nil:
+		if kubeClientProvider == nil {
+			return nil, fmt.Errorf("portForward requires access to the Kubernetes API")
+		}
+		clientSet, restConfig := kubeClientProvider.DefaultClientSet()
+		// Authenticate with the kubeconfig credentials when no other credentials are given
+		authenticate := metricsEndpoint.Token == "" && metricsEndpoint.Username == ""
+		tunnel, err = prometheus.NewPortForwardTunnel(clientSet, restConfig, *metricsEndpoint.PortForward, authenticate, metricsEndpoint.SkipTLSVerify)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error setting up Prometheus tunnel: %v", err)
 	}
+	metricsEndpoint.Endpoint = tunnel.URL
+	return tunnel, nil
 }
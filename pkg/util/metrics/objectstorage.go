@@ -0,0 +1,278 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/azureblob"
+	"gocloud.dev/blob/gcsblob"
+	"gocloud.dev/blob/s3blob"
+	"gocloud.dev/gcp"
+)
+
+// ObjectStorageIndexer writes gzip-compressed JSONL documents to S3, GCS or Azure Blob
+const ObjectStorageIndexer indexers.IndexerType = "objectStorage"
+
+const (
+	defaultObjectPrefix = "{{.UUID}}"
+	// Objects larger than the part size are uploaded in several parts
+	objectPartSize = 8 << 20
+)
+
+// objectKeyData data available in the prefix template
+type objectKeyData struct {
+	UUID       string
+	JobName    string
+	MetricName string
+}
+
+// objectStorage uploads documents to a bucket, one object per metric and indexing call
+type objectStorage struct {
+	bucketURL string
+	bucket    *blob.Bucket
+	prefix    *template.Template
+	// parts objects uploaded per metric and job, metrics are indexed once per job and must not overwrite each other
+	parts     map[string]int
+	partsLock sync.Mutex
+}
+
+func newObjectStorageIndexer(indexerConfig config.IndexerConfig) (*objectStorage, error) {
+	bucketURL, err := url.Parse(indexerConfig.BucketURL)
+	if err != nil || bucketURL.Host == "" {
+		return nil, fmt.Errorf("invalid bucketURL %q", indexerConfig.BucketURL)
+	}
+	prefix, err := template.New("prefix").Option("missingkey=error").Parse(cmp.Or(indexerConfig.Prefix, defaultObjectPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid prefix template: %v", err)
+	}
+	tlsConfig, err := indexerTLSConfig(indexerConfig)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	o := &objectStorage{
+		bucketURL: strings.TrimSuffix(indexerConfig.BucketURL, "/"),
+		prefix:    prefix,
+		parts:     make(map[string]int),
+	}
+	ctx := context.Background()
+	switch bucketURL.Scheme {
+	case "s3":
+		o.bucket, err = openS3Bucket(ctx, indexerConfig, bucketURL.Host, tlsConfig)
+	case "gs":
+		o.bucket, err = openGCSBucket(ctx, indexerConfig, bucketURL.Host, transport)
+	case "azblob":
+		o.bucket, err = openAzureBucket(ctx, indexerConfig, bucketURL, transport)
+	default:
+		return nil, fmt.Errorf("unsupported bucketURL scheme %q, supported schemes: s3, gs, azblob", bucketURL.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening bucket %s: %v", indexerConfig.BucketURL, err)
+	}
+	return o, nil
+}
+
+// openS3Bucket opens the bucket with the static keys, when given, or the credentials of the AWS default chain:
+// environment, shared configuration and credentials files, web identity, ECS and instance profile
+func openS3Bucket(ctx context.Context, indexerConfig config.IndexerConfig, bucket string, tlsConfig *tls.Config) (*blob.Bucket, error) {
+	// The buildable client keeps honoring AWS_CA_BUNDLE
+	httpClient := awshttp.NewBuildableClient().WithTransportOptions(func(t *http.Transport) {
+		t.TLSClientConfig = tlsConfig
+	})
+	opts := []func(*awsconfig.LoadOptions) error{
+		awsconfig.WithHTTPClient(httpClient),
+	}
+	if indexerConfig.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(indexerConfig.Region))
+	}
+	if indexerConfig.AccessKey != "" || indexerConfig.SecretKey != "" {
+		if indexerConfig.AccessKey == "" || indexerConfig.SecretKey == "" {
+			return nil, fmt.Errorf("accessKey and secretKey must be given together")
+		}
+		opts = append(opts, awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(indexerConfig.AccessKey, indexerConfig.SecretKey, "")))
+	}
+	awsConfig, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if awsConfig.Region == "" {
+		awsConfig.Region = "us-east-1"
+	}
+	client := s3.NewFromConfig(awsConfig, func(o *s3.Options) {
+		// Path-style addressing for custom endpoints, like MinIO
+		if indexerConfig.BucketEndpoint != "" {
+			o.BaseEndpoint = &indexerConfig.BucketEndpoint
+			o.UsePathStyle = true
+		}
+	})
+	return s3blob.OpenBucketV2(ctx, client, bucket, nil)
+}
+
+// openGCSBucket opens the bucket with the Google application default credentials
+func openGCSBucket(ctx context.Context, indexerConfig config.IndexerConfig, bucket string, transport http.RoundTripper) (*blob.Bucket, error) {
+	if indexerConfig.AccessKey != "" || indexerConfig.SecretKey != "" || indexerConfig.BucketEndpoint != "" {
+		return nil, fmt.Errorf("accessKey, secretKey and bucketEndpoint aren't supported by GCS, which uses the application default credentials")
+	}
+	creds, err := gcp.DefaultCredentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := gcp.NewHTTPClient(transport, gcp.CredentialsTokenSource(creds))
+	if err != nil {
+		return nil, err
+	}
+	return gcsblob.OpenBucket(ctx, client, bucket, nil)
+}
+
+// openAzureBucket opens the container of the azblob://account/container URL with the SAS token, when given, or the
+// credentials of the environment: account key, connection string or the Azure default credential chain
+func openAzureBucket(ctx context.Context, indexerConfig config.IndexerConfig, bucketURL *url.URL, transport *http.Transport) (*blob.Bucket, error) {
+	containerName := strings.Trim(bucketURL.Path, "/")
+	if containerName == "" {
+		return nil, fmt.Errorf("azblob bucketURL must have the format azblob://account/container")
+	}
+	serviceURL := indexerConfig.BucketEndpoint
+	if serviceURL == "" {
+		u, err := azureblob.NewServiceURL(&azureblob.ServiceURLOptions{AccountName: bucketURL.Host})
+		if err != nil {
+			return nil, err
+		}
+		serviceURL = string(u)
+	}
+	var client *container.Client
+	var err error
+	sasToken := strings.TrimPrefix(cmp.Or(indexerConfig.SASToken, envOr("AZURE_STORAGE_SAS_TOKEN", "")), "?")
+	if sasToken != "" {
+		containerURL, err := url.JoinPath(serviceURL, containerName)
+		if err != nil {
+			return nil, err
+		}
+		clientOpts := &container.ClientOptions{}
+		clientOpts.Transport = &http.Client{Transport: transport}
+		client, err = container.NewClientWithNoCredential(containerURL+"?"+sasToken, clientOpts)
+		if err != nil {
+			return nil, err
+		}
+	} else if client, err = azureblob.NewDefaultClient(azureblob.ServiceURL(serviceURL), azureblob.ContainerName(containerName)); err != nil {
+		return nil, err
+	}
+	return azureblob.OpenBucket(ctx, client, nil)
+}
+
+// Index uploads the documents as a gzip-compressed JSONL object named after the metric, the job and the part number
+func (o *objectStorage) Index(documents []any, opts indexers.IndexingOpts) (string, error) {
+	if len(documents) == 0 {
+		return fmt.Sprintf("Indexing skipped due to %v docs", len(documents)), nil
+	}
+	if opts.MetricName == "" {
+		return "", fmt.Errorf("MetricName shouldn't be empty")
+	}
+	key, err := o.objectKey(documents[0], opts.MetricName)
+	if err != nil {
+		return "", err
+	}
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	// The writer uploads the object in parts as the compressed documents fill them
+	w, err := o.bucket.NewWriter(ctx, key, &blob.WriterOptions{
+		ContentType: "application/gzip",
+		BufferSize:  objectPartSize,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error uploading %s: %v", key, err)
+	}
+	gz := gzip.NewWriter(w)
+	enc := json.NewEncoder(gz)
+	for _, document := range documents {
+		if err := enc.Encode(document); err != nil {
+			// Canceling the context before closing the writer aborts the upload
+			cancel()
+			w.Close()
+			return "", fmt.Errorf("JSON encoding error: %s", err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		cancel()
+		w.Close()
+		return "", fmt.Errorf("gzip compression error: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("error uploading %s: %v", key, err)
+	}
+	return fmt.Sprintf("Object %s/%s uploaded with %d documents in %v", o.bucketURL, key, len(documents), time.Since(start).Truncate(time.Millisecond)), nil
+}
+
+// objectKey renders the object prefix using the uuid and jobName fields of the document, the object is named
+// <metricName>[-<jobName>]-<part>.jsonl.gz so that every indexing call of the same metric uploads a new object
+func (o *objectStorage) objectKey(document any, metricName string) (string, error) {
+	var fields struct {
+		UUID    string `json:"uuid"`
+		JobName string `json:"jobName"`
+	}
+	if j, err := json.Marshal(document); err == nil {
+		json.Unmarshal(j, &fields)
+	}
+	var prefix bytes.Buffer
+	err := o.prefix.Execute(&prefix, objectKeyData{
+		UUID:       fields.UUID,
+		JobName:    fields.JobName,
+		MetricName: metricName,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error rendering object prefix: %v", err)
+	}
+	name := metricName
+	if fields.JobName != "" {
+		name += "-" + fields.JobName
+	}
+	name = path.Join(strings.Trim(prefix.String(), "/"), name)
+	o.partsLock.Lock()
+	part := o.parts[name]
+	o.parts[name]++
+	o.partsLock.Unlock()
+	return fmt.Sprintf("%s-%05d.jsonl.gz", name, part), nil
+}
+
+func envOr(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+)
+
+// fakeS3 stores the objects uploaded with single part PUT requests, along with their authorization headers
+type fakeS3 struct {
+	objects        map[string][]byte
+	authorizations []string
+	lock           sync.Mutex
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "unexpected method "+r.Method, http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.objects[r.URL.Path] = body
+	f.authorizations = append(f.authorizations, r.Header.Get("Authorization"))
+	w.Header().Set("ETag", `"etag"`)
+}
+
+func TestObjectStorageS3(t *testing.T) {
+	fake := &fakeS3{objects: make(map[string][]byte)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	t.Setenv("AWS_CONFIG_FILE", "/dev/null")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", "/dev/null")
+	indexer, err := newObjectStorageIndexer(config.IndexerConfig{ObjectStorage: config.ObjectStorage{
+		BucketURL:      "s3://benchmarks",
+		BucketEndpoint: server.URL,
+		Region:         "eu-west-1",
+		AccessKey:      "AKIDEXAMPLE",
+		SecretKey:      "secret",
+		Prefix:         "runs/{{.UUID}}/{{.MetricName}}",
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	documents := []any{
+		map[string]any{"uuid": "u1", "jobName": "j", "metricName": "podLatencyMeasurement", "value": 1},
+		map[string]any{"uuid": "u1", "jobName": "j", "metricName": "podLatencyMeasurement", "value": 2},
+	}
+	for range 2 {
+		if _, err := indexer.Index(documents, indexers.IndexingOpts{MetricName: "podLatencyMeasurement"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// Every indexing call of the same metric and job uploads a new part, into the bucket addressed in the path
+	for _, key := range []string{
+		"/benchmarks/runs/u1/podLatencyMeasurement/podLatencyMeasurement-j-00000.jsonl.gz",
+		"/benchmarks/runs/u1/podLatencyMeasurement/podLatencyMeasurement-j-00001.jsonl.gz",
+	} {
+		object, ok := fake.objects[key]
+		if !ok {
+			t.Fatalf("object %s not uploaded, uploaded objects: %v", key, fake.objects)
+		}
+		gz, err := gzip.NewReader(bytes.NewReader(object))
+		if err != nil {
+			t.Fatalf("%s: invalid gzip object: %v", key, err)
+		}
+		dec := json.NewDecoder(gz)
+		var count int
+		for dec.More() {
+			var document map[string]any
+			if err := dec.Decode(&document); err != nil {
+				t.Fatalf("%s: invalid JSONL object: %v", key, err)
+			}
+			count++
+		}
+		if count != len(documents) {
+			t.Errorf("%s: got %d documents, expected %d", key, count, len(documents))
+		}
+	}
+	for _, authorization := range fake.authorizations {
+		if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") || !strings.Contains(authorization, "/eu-west-1/s3/aws4_request") {
+			t.Errorf("unexpected authorization header %s", authorization)
+		}
+	}
+	if _, err := indexer.Index(documents, indexers.IndexingOpts{}); err == nil {
+		t.Error("expected an error indexing without a metric name")
+	}
+}
+
+func TestObjectStorageConfig(t *testing.T) {
+	invalid := []config.ObjectStorage{
+		{BucketURL: "benchmarks"},
+		{BucketURL: "ftp://benchmarks"},
+		{BucketURL: "s3://benchmarks", AccessKey: "AKIDEXAMPLE"},
+		{BucketURL: "s3://benchmarks", Prefix: "{{.UUID"},
+		{BucketURL: "gs://benchmarks", BucketEndpoint: "http://localhost:9000"},
+		{BucketURL: "azblob://account"},
+	}
+	for _, objectStorage := range invalid {
+		if _, err := newObjectStorageIndexer(config.IndexerConfig{ObjectStorage: objectStorage}); err == nil {
+			t.Errorf("%+v: expected an error", objectStorage)
+		}
+	}
+}
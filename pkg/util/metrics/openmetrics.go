@@ -0,0 +1,164 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"maps"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// OpenMetricsIndexer writes documents as samples into OpenMetrics files, which promtool converts into Prometheus TSDB blocks
+const OpenMetricsIndexer indexers.IndexerType = "openMetrics"
+
+const openMetricsExtension = ".om"
+
+var openMetricsEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// openMetrics converts documents into samples like the remote write indexer. Each metric name is written to its own file,
+// which is rewritten whenever more documents of that metric name are indexed
+type openMetrics struct {
+	directory string
+	mu        sync.Mutex
+	// series of each file, by metric name
+	files map[string]map[string]*timeSeries
+}
+
+func newOpenMetricsIndexer(indexerConfig config.IndexerConfig) (*openMetrics, error) {
+	if err := os.MkdirAll(indexerConfig.MetricsDirectory, 0744); err != nil {
+		return nil, fmt.Errorf("error creating metrics directory: %v", err)
+	}
+	return &openMetrics{
+		directory: indexerConfig.MetricsDirectory,
+		files:     make(map[string]map[string]*timeSeries),
+	}, nil
+}
+
+// Index adds the samples of the documents to the file of the metric name
+func (om *openMetrics) Index(documents []any, opts indexers.IndexingOpts) (string, error) {
+	if len(documents) == 0 {
+		return fmt.Sprintf("Indexing skipped due to %v docs", len(documents)), nil
+	}
+	om.mu.Lock()
+	defer om.mu.Unlock()
+	series, exists := om.files[opts.MetricName]
+	if !exists {
+		series = make(map[string]*timeSeries)
+		om.files[opts.MetricName] = series
+	}
+	for _, document := range documents {
+		if err := addDocumentSamples(series, document); err != nil {
+			return "", err
+		}
+	}
+	path := filepath.Join(om.directory, sanitizeLabelName(opts.MetricName)+openMetricsExtension)
+	if err := writeOpenMetrics(path, series); err != nil {
+		return "", fmt.Errorf("error writing %s: %v", path, err)
+	}
+	return fmt.Sprintf("%d series written to %s", len(series), path), nil
+}
+
+// writeOpenMetrics writes the series grouped in metric families, with their samples in chronological order
+func writeOpenMetrics(path string, series map[string]*timeSeries) error {
+	families := make(map[string][]*timeSeries)
+	for _, key := range slices.Sorted(maps.Keys(series)) {
+		ts := series[key]
+		for _, l := range ts.labels {
+			if l.name == "__name__" {
+				families[l.value] = append(families[l.value], ts)
+				break
+			}
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	for _, name := range slices.Sorted(maps.Keys(families)) {
+		fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+		for _, ts := range families[name] {
+			var labels []string
+			for _, l := range ts.labels {
+				if l.name != "__name__" {
+					labels = append(labels, fmt.Sprintf(`%s="%s"`, l.name, openMetricsEscaper.Replace(l.value)))
+				}
+			}
+			slices.SortFunc(ts.samples, func(a, b sample) int {
+				return cmp.Compare(a.timestamp, b.timestamp)
+			})
+			// Samples sharing a timestamp are rejected by promtool, the same document may be indexed twice
+			ts.samples = slices.CompactFunc(ts.samples, func(a, b sample) bool {
+				return a.timestamp == b.timestamp
+			})
+			for _, s := range ts.samples {
+				fmt.Fprintf(w, "%s{%s} %s %d.%03d\n", name, strings.Join(labels, ","), formatOpenMetricsValue(s.value), s.timestamp/1000, s.timestamp%1000)
+			}
+		}
+	}
+	fmt.Fprintln(w, "# EOF")
+	return w.Flush()
+}
+
+func formatOpenMetricsValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// CreateTSDBBlocks converts the OpenMetrics files of the indexer into Prometheus TSDB blocks with promtool
+func CreateTSDBBlocks(indexerConfig config.IndexerConfig) error {
+	promtool, err := exec.LookPath("promtool")
+	if err != nil {
+		return fmt.Errorf("promtool is required to create TSDB blocks: %v", err)
+	}
+	files, err := filepath.Glob(filepath.Join(indexerConfig.MetricsDirectory, "*"+openMetricsExtension))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(indexerConfig.TSDBDirectory, 0744); err != nil {
+		return fmt.Errorf("error creating TSDB directory: %v", err)
+	}
+	start := time.Now()
+	for _, file := range files {
+		out, err := exec.Command(promtool, "tsdb", "create-blocks-from", "openmetrics", file, indexerConfig.TSDBDirectory).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("error creating TSDB blocks from %s: %v: %s", file, err, strings.TrimSpace(string(out)))
+		}
+	}
+	log.Infof("TSDB blocks of %d metric files generated at %s in %v", len(files), indexerConfig.TSDBDirectory, time.Since(start).Truncate(time.Millisecond))
+	return nil
+}
@@ -0,0 +1,306 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/opensearch-project/opensearch-go"
+	"github.com/opensearch-project/opensearch-go/opensearchutil"
+	log "github.com/sirupsen/logrus"
+)
+
+// Supported index periods
+const (
+	IndexPeriodDaily   = "daily"
+	IndexPeriodMonthly = "monthly"
+)
+
+var indexPeriodLayouts = map[string]string{
+	IndexPeriodDaily:   "2006.01.02",
+	IndexPeriodMonthly: "2006.01",
+}
+
+// openSearch indexes documents in OpenSearch, supporting data streams and date suffixed indices
+type openSearch struct {
+	client      *opensearch.Client
+	index       string
+	dataStream  bool
+	indexPeriod string
+	replace     bool
+	// Indices already created
+	indices sync.Map
+}
+
+// NewIndexer creates the indexer described by the given configuration
+func NewIndexer(indexerConfig config.IndexerConfig) (indexers.Indexer, error) {
+	// The Elasticsearch client of go-commons neither accepts TLS settings nor deterministic document IDs, the OpenSearch
+	// client speaks the same API
+	if indexerConfig.Type == indexers.OpenSearchIndexer || indexerConfig.Type == indexers.ElasticIndexer {
+		return newOpenSearchIndexer(indexerConfig)
+	}
+	if indexerConfig.DataStream || indexerConfig.IndexPeriod != "" || indexerConfig.Replace {
+		return nil, fmt.Errorf("dataStream, indexPeriod and replace are only supported by the %s and %s indexers", indexers.ElasticIndexer, indexers.OpenSearchIndexer)
+	}
+	switch indexerConfig.Type {
+	case ObjectStorageIndexer:
+		return newObjectStorageIndexer(indexerConfig)
+	case RemoteWriteIndexer:
+		return newRemoteWriteIndexer(indexerConfig)
+	case OpenMetricsIndexer:
+		return newOpenMetricsIndexer(indexerConfig)
+	}
+	indexer, err := indexers.NewIndexer(indexerConfig.IndexerConfig)
+	if err != nil {
+		return nil, err
+	}
+	return *indexer, nil
+}
+
+func newOpenSearchIndexer(indexerConfig config.IndexerConfig) (*openSearch, error) {
+	if indexerConfig.Index == "" {
+		return nil, fmt.Errorf("index name not specified")
+	}
+	if _, ok := indexPeriodLayouts[indexerConfig.IndexPeriod]; indexerConfig.IndexPeriod != "" && !ok {
+		return nil, fmt.Errorf("unsupported indexPeriod %s, supported values: %s, %s", indexerConfig.IndexPeriod, IndexPeriodDaily, IndexPeriodMonthly)
+	}
+	if indexerConfig.DataStream && indexerConfig.IndexPeriod != "" {
+		return nil, fmt.Errorf("dataStream and indexPeriod are mutually exclusive, data streams handle rollover by themselves")
+	}
+	if indexerConfig.DataStream && indexerConfig.Replace {
+		return nil, fmt.Errorf("replace isn't supported by data streams, whose documents can't be overwritten")
+	}
+	tlsConfig, err := indexerTLSConfig(indexerConfig)
+	if err != nil {
+		return nil, err
+	}
+	client, err := opensearch.NewClient(opensearch.Config{
+		Addresses: indexerConfig.Servers,
+		Username:  indexerConfig.Username,
+		Password:  indexerConfig.Password,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating the OpenSearch client: %v", err)
+	}
+	r, err := client.Cluster.Health()
+	if err != nil {
+		return nil, fmt.Errorf("OpenSearch health check failed: %v", err)
+	}
+	defer r.Body.Close()
+	if r.IsError() {
+		return nil, fmt.Errorf("unexpected OpenSearch health check status code: %d", r.StatusCode)
+	}
+	o := &openSearch{
+		client:      client,
+		index:       strings.ToLower(indexerConfig.Index),
+		dataStream:  indexerConfig.DataStream,
+		indexPeriod: indexerConfig.IndexPeriod,
+		replace:     indexerConfig.Replace,
+	}
+	if o.dataStream {
+		if err := o.putDataStreamTemplate(); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// putDataStreamTemplate creates the index template enabling the data stream, OpenSearch creates the data stream on the first write
+func (o *openSearch) putDataStreamTemplate() error {
+	template := fmt.Sprintf(`{"index_patterns": ["%s"], "data_stream": {}, "priority": 100}`, o.index)
+	r, err := o.client.Indices.PutIndexTemplate(o.index, strings.NewReader(template))
+	if err != nil {
+		return fmt.Errorf("error creating data stream template %s: %v", o.index, err)
+	}
+	defer r.Body.Close()
+	if r.IsError() {
+		return fmt.Errorf("error creating data stream template %s: %s", o.index, r.String())
+	}
+	return nil
+}
+
+// targetIndex returns the index or data stream to write to, creating the index when required
+func (o *openSearch) targetIndex() (string, error) {
+	if o.dataStream {
+		return o.index, nil
+	}
+	index := o.index
+	if o.indexPeriod != "" {
+		index = fmt.Sprintf("%s-%s", o.index, time.Now().UTC().Format(indexPeriodLayouts[o.indexPeriod]))
+	}
+	if _, exists := o.indices.Load(index); exists {
+		return index, nil
+	}
+	r, err := o.client.Indices.Exists([]string{index})
+	if err != nil {
+		return index, fmt.Errorf("error checking index %s: %v", index, err)
+	}
+	r.Body.Close()
+	if r.IsError() {
+		r, err = o.client.Indices.Create(index)
+		if err != nil {
+			return index, fmt.Errorf("error creating index %s: %v", index, err)
+		}
+		defer r.Body.Close()
+		// Another process may have created the index meanwhile
+		if r.IsError() && !strings.Contains(r.String(), "resource_already_exists_exception") {
+			return index, fmt.Errorf("error creating index %s: %s", index, r.String())
+		}
+	}
+	o.indices.Store(index, true)
+	return index, nil
+}
+
+// Index indexes the documents using the bulk API
+func (o *openSearch) Index(documents []any, opts indexers.IndexingOpts) (string, error) {
+	var statString string
+	var statsLock sync.Mutex
+	stats := make(map[string]int)
+	if len(documents) == 0 {
+		return fmt.Sprintf("Indexing skipped due to %v docs", len(documents)), nil
+	}
+	index, err := o.targetIndex()
+	if err != nil {
+		return "", err
+	}
+	// Documents already indexed with the same ID are skipped, so that indexing the same documents again is idempotent
+	action := "create"
+	if o.replace {
+		action = "index"
+	}
+	bi, err := opensearchutil.NewBulkIndexer(opensearchutil.BulkIndexerConfig{
+		Client:     o.client,
+		Index:      index,
+		FlushBytes: 5e+6,
+		NumWorkers: runtime.NumCPU(),
+		Timeout:    10 * time.Minute,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error creating the bulk indexer: %v", err)
+	}
+	start := time.Now().UTC()
+	for _, document := range documents {
+		j, err := json.Marshal(document)
+		if err != nil {
+			return "", fmt.Errorf("cannot encode document %v: %v", document, err)
+		}
+		if o.dataStream {
+			if j, err = addTimestampField(j); err != nil {
+				return "", err
+			}
+		}
+		docID, err := documentID(j)
+		if err != nil {
+			return "", err
+		}
+		err = bi.Add(context.Background(), opensearchutil.BulkIndexerItem{
+			Action:     action,
+			Body:       bytes.NewReader(j),
+			DocumentID: docID,
+			OnSuccess: func(_ context.Context, _ opensearchutil.BulkIndexerItem, res opensearchutil.BulkIndexerResponseItem) {
+				statsLock.Lock()
+				defer statsLock.Unlock()
+				stats[res.Result]++
+			},
+			OnFailure: func(_ context.Context, item opensearchutil.BulkIndexerItem, res opensearchutil.BulkIndexerResponseItem, err error) {
+				statsLock.Lock()
+				defer statsLock.Unlock()
+				if res.Status == http.StatusConflict {
+					stats["skipped"]++
+					return
+				}
+				stats["failed"]++
+				log.Debugf("Failed to index document %s: %s %v", item.DocumentID, res.Error.Reason, err)
+			},
+		})
+		if err != nil {
+			return "", fmt.Errorf("unexpected OpenSearch indexing error: %v", err)
+		}
+	}
+	if err := bi.Close(context.Background()); err != nil {
+		return "", fmt.Errorf("unexpected OpenSearch error: %v", err)
+	}
+	for stat, val := range stats {
+		statString += fmt.Sprintf(" %s=%d", stat, val)
+	}
+	if stats["failed"] > 0 {
+		return "", fmt.Errorf("error indexing %s in %s:%s", opts.MetricName, index, statString)
+	}
+	return fmt.Sprintf("Indexing finished in %v:%v", time.Since(start).Truncate(time.Millisecond), statString), nil
+}
+
+// documentIdentityFields are the fields identifying a document, along with its labels and string fields
+var documentIdentityFields = []string{"uuid", "jobName", "metricName", "timestamp"}
+
+// documentID returns the ID of the document, the hash of its UUID, job name, metric name and timestamp, along with its
+// labels and string fields telling apart the documents of the same metric, like the pod or the quantile names.
+// Numeric fields and the metadata are excluded, so that a document re-indexed with different values or metadata keeps its ID
+func documentID(document []byte) (string, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(document, &fields); err != nil {
+		return "", fmt.Errorf("cannot decode document: %v", err)
+	}
+	identity := make(map[string]any)
+	for _, field := range documentIdentityFields {
+		identity[field] = fields[field]
+	}
+	// Job summaries hold the job name in their configuration
+	if jobConfig, ok := fields["jobConfig"].(map[string]any); ok && identity["jobName"] == nil {
+		identity["jobName"] = jobConfig["name"]
+	}
+	identity["labels"] = fields["labels"]
+	for field, value := range fields {
+		if _, ok := value.(string); ok && field != "@timestamp" {
+			identity[field] = value
+		}
+	}
+	// Map keys are sorted when marshaled
+	j, err := json.Marshal(identity)
+	if err != nil {
+		return "", fmt.Errorf("cannot encode document identity: %v", err)
+	}
+	hash := sha256.Sum256(j)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// addTimestampField copies the document timestamp into the @timestamp field required by data streams
+func addTimestampField(document []byte) ([]byte, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(document, &fields); err != nil {
+		return nil, fmt.Errorf("cannot decode document: %v", err)
+	}
+	if _, exists := fields["@timestamp"]; exists {
+		return document, nil
+	}
+	if timestamp, exists := fields["timestamp"]; exists {
+		fields["@timestamp"] = timestamp
+	} else {
+		fields["@timestamp"] = time.Now().UTC()
+	}
+	return json.Marshal(fields)
+}
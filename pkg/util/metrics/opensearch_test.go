@@ -0,0 +1,64 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDocumentID(t *testing.T) {
+	id := func(document string) string {
+		t.Helper()
+		documentID, err := documentID([]byte(document))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return documentID
+	}
+	base := id(`{"uuid":"u1","jobName":"j","metricName":"podLatencyMeasurement","timestamp":"2025-03-04T10:00:00Z","podName":"p1","value":1,"metadata":{"k":"v"}}`)
+	same := []string{
+		// Numeric fields and metadata don't identify documents
+		`{"uuid":"u1","jobName":"j","metricName":"podLatencyMeasurement","timestamp":"2025-03-04T10:00:00Z","podName":"p1","value":2,"metadata":{"k":"w"}}`,
+		// Neither does the @timestamp field added to data streams
+		`{"uuid":"u1","jobName":"j","metricName":"podLatencyMeasurement","timestamp":"2025-03-04T10:00:00Z","@timestamp":"2025-03-04T10:00:01Z","podName":"p1","value":1}`,
+	}
+	for _, document := range same {
+		if id(document) != base {
+			t.Errorf("%s: expected the ID of the original document", document)
+		}
+	}
+	different := []string{
+		`{"uuid":"u2","jobName":"j","metricName":"podLatencyMeasurement","timestamp":"2025-03-04T10:00:00Z","podName":"p1","value":1}`,
+		`{"uuid":"u1","jobName":"k","metricName":"podLatencyMeasurement","timestamp":"2025-03-04T10:00:00Z","podName":"p1","value":1}`,
+		`{"uuid":"u1","jobName":"j","metricName":"podLatencyQuantilesMeasurement","timestamp":"2025-03-04T10:00:00Z","podName":"p1","value":1}`,
+		`{"uuid":"u1","jobName":"j","metricName":"podLatencyMeasurement","timestamp":"2025-03-04T10:00:30Z","podName":"p1","value":1}`,
+		`{"uuid":"u1","jobName":"j","metricName":"podLatencyMeasurement","timestamp":"2025-03-04T10:00:00Z","podName":"p2","value":1}`,
+		`{"uuid":"u1","jobName":"j","metricName":"podLatencyMeasurement","timestamp":"2025-03-04T10:00:00Z","podName":"p1","value":1,"labels":{"node":"n1"}}`,
+	}
+	for _, document := range different {
+		if id(document) == base {
+			t.Errorf("%s: expected a different ID than the original document", document)
+		}
+	}
+	// Job summaries hold the job name in their configuration
+	summary := `{"uuid":"u1","metricName":"jobSummary","timestamp":"2025-03-04T10:00:00Z","jobConfig":{"name":"%s"}}`
+	if id(fmt.Sprintf(summary, "a")) == id(fmt.Sprintf(summary, "b")) {
+		t.Error("expected job summaries of different jobs to have different IDs")
+	}
+	if _, err := documentID([]byte("not json")); err == nil {
+		t.Error("expected an error decoding an invalid document")
+	}
+}
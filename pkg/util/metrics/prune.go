@@ -0,0 +1,320 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/opensearch-project/opensearch-go/opensearchapi"
+	"github.com/opensearch-project/opensearch-go/opensearchtransport"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	jobSummaryMetric = "jobSummary"
+	pruneSearchSize  = 1000
+)
+
+// Date suffixed indices may not exist
+var ignoreUnavailable = true
+
+// PruneOptions retention policy applied by Prune
+type PruneOptions struct {
+	// OlderThan prunes the runs started before this long ago
+	OlderThan time.Duration
+	// Workload only prunes the runs of this workload, matching the workload metadata field or the job names
+	Workload string
+	// DryRun only lists the runs to prune
+	DryRun bool
+}
+
+// PrunedRun benchmark run whose documents are pruned
+type PrunedRun struct {
+	UUID      string
+	Timestamp time.Time
+	Workload  string
+	Documents int
+	// Location directory or index holding the documents
+	Location string
+}
+
+// summaryDocument fields of the job summaries identifying a run
+type summaryDocument struct {
+	UUID      string    `json:"uuid"`
+	Timestamp time.Time `json:"timestamp"`
+	Workload  string    `json:"workload"`
+	JobConfig struct {
+		Name string `json:"name"`
+	} `json:"jobConfig"`
+}
+
+func (s summaryDocument) workload() string {
+	if s.Workload != "" {
+		return s.Workload
+	}
+	return s.JobConfig.Name
+}
+
+// ParseRetention parses a retention period, a duration also accepting days (d) and weeks (w), e.g. 90d
+func ParseRetention(retention string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if value, found := strings.CutSuffix(retention, suffix); found {
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid retention period %s", retention)
+			}
+			return time.Duration(n * float64(unit)), nil
+		}
+	}
+	return time.ParseDuration(retention)
+}
+
+// Prune deletes the documents of the runs matching the retention policy from the store of the indexer,
+// only the local, Elasticsearch and OpenSearch indexers are supported
+func Prune(indexerConfig config.IndexerConfig, opts PruneOptions) ([]PrunedRun, error) {
+	if opts.OlderThan <= 0 {
+		return nil, fmt.Errorf("the retention period must be greater than 0")
+	}
+	cutoff := time.Now().Add(-opts.OlderThan).UTC()
+	switch indexerConfig.Type {
+	case indexers.LocalIndexer:
+		return pruneLocal(indexerConfig.MetricsDirectory, cutoff, opts)
+	case indexers.ElasticIndexer, indexers.OpenSearchIndexer:
+		return pruneIndex(indexerConfig, cutoff, opts)
+	}
+	return nil, fmt.Errorf("pruning is not supported by the %s indexer", indexerConfig.Type)
+}
+
+// pruneLocal prunes the run directories found under the metrics directory, those holding a job summary file
+func pruneLocal(metricsDirectory string, cutoff time.Time, opts PruneOptions) ([]PrunedRun, error) {
+	var runs []PrunedRun
+	err := filepath.WalkDir(metricsDirectory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() != jobSummaryMetric+".json" {
+			return err
+		}
+		runDirectory := filepath.Dir(path)
+		var summaries []summaryDocument
+		if err := readDocuments(path, &summaries); err != nil || len(summaries) == 0 {
+			log.Warnf("Skipping %s: no job summaries found", runDirectory)
+			return nil
+		}
+		run := PrunedRun{UUID: summaries[0].UUID, Timestamp: summaries[0].Timestamp, Location: runDirectory}
+		for _, summary := range summaries {
+			if summary.Timestamp.Before(run.Timestamp) {
+				run.Timestamp = summary.Timestamp
+			}
+			if opts.Workload != "" && summary.workload() == opts.Workload {
+				run.Workload = opts.Workload
+			} else if run.Workload == "" {
+				run.Workload = summary.workload()
+			}
+		}
+		if !run.Timestamp.Before(cutoff) || (opts.Workload != "" && run.Workload != opts.Workload) {
+			return nil
+		}
+		files, err := filepath.Glob(filepath.Join(runDirectory, "*.json"))
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			var documents []json.RawMessage
+			if readDocuments(file, &documents) == nil {
+				run.Documents += len(documents)
+			}
+		}
+		runs = append(runs, run)
+		if opts.DryRun {
+			return nil
+		}
+		for _, file := range files {
+			if err := os.Remove(file); err != nil {
+				return err
+			}
+		}
+		// Remove the run directory as well when nothing else is left in it
+		if entries, err := os.ReadDir(runDirectory); err == nil && len(entries) == 0 && runDirectory != filepath.Clean(metricsDirectory) {
+			os.Remove(runDirectory)
+		}
+		return nil
+	})
+	return runs, err
+}
+
+func readDocuments(file string, documents any) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, documents)
+}
+
+// pruneIndex prunes the runs from the index, including its date suffixed indices
+func pruneIndex(indexerConfig config.IndexerConfig, cutoff time.Time, opts PruneOptions) ([]PrunedRun, error) {
+	if indexerConfig.Index == "" {
+		return nil, fmt.Errorf("index name not specified")
+	}
+	tlsConfig, err := indexerTLSConfig(indexerConfig)
+	if err != nil {
+		return nil, err
+	}
+	var urls []*url.URL
+	for _, server := range indexerConfig.Servers {
+		u, err := url.Parse(server)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server %s: %v", server, err)
+		}
+		urls = append(urls, u)
+	}
+	// The transport is used directly, the clients refuse to talk to the Elasticsearch versions they don't support
+	client, err := opensearchtransport.New(opensearchtransport.Config{
+		URLs:      urls,
+		Username:  indexerConfig.Username,
+		Password:  indexerConfig.Password,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating the client: %v", err)
+	}
+	index := strings.ToLower(indexerConfig.Index)
+	indices := []string{index, index + "-*"}
+	runs, err := searchRuns(client, indices, cutoff, opts.Workload)
+	if err != nil {
+		return nil, err
+	}
+	for i := range runs {
+		runs[i].Location = index
+		query := runQuery(runs[i].UUID)
+		var r *opensearchapi.Response
+		if opts.DryRun {
+			var resp struct {
+				Count int `json:"count"`
+			}
+			r, err = opensearchapi.CountRequest{Index: indices, Body: query, IgnoreUnavailable: &ignoreUnavailable}.Do(context.TODO(), client)
+			if err == nil {
+				err = decodeResponse(r, &resp)
+			}
+			runs[i].Documents = resp.Count
+		} else {
+			var resp struct {
+				Deleted int `json:"deleted"`
+			}
+			r, err = opensearchapi.DeleteByQueryRequest{Index: indices, Body: query, Conflicts: "proceed", IgnoreUnavailable: &ignoreUnavailable}.Do(context.TODO(), client)
+			if err == nil {
+				err = decodeResponse(r, &resp)
+			}
+			runs[i].Documents = resp.Deleted
+		}
+		if err != nil {
+			return runs[:i], fmt.Errorf("error pruning run %s: %v", runs[i].UUID, err)
+		}
+	}
+	return runs, nil
+}
+
+// searchRuns returns the runs whose job summaries are older than the cutoff
+func searchRuns(client opensearchapi.Transport, indices []string, cutoff time.Time, workload string) ([]PrunedRun, error) {
+	filters := []any{
+		exactQuery("metricName", jobSummaryMetric),
+		map[string]any{"range": map[string]any{"timestamp": map[string]any{"lt": cutoff.Format(time.RFC3339)}}},
+	}
+	if workload != "" {
+		filters = append(filters, map[string]any{"bool": map[string]any{
+			"should": []any{
+				exactQuery("workload", workload),
+				exactQuery("jobConfig.name", workload),
+			},
+			"minimum_should_match": 1,
+		}})
+	}
+	runs := make(map[string]*PrunedRun)
+	var searchAfter []any
+	for {
+		body := map[string]any{
+			"query":   map[string]any{"bool": map[string]any{"filter": filters}},
+			"sort":    []any{map[string]any{"timestamp": "asc"}},
+			"_source": []string{"uuid", "timestamp", "workload", "jobConfig.name"},
+		}
+		if searchAfter != nil {
+			body["search_after"] = searchAfter
+		}
+		query, _ := json.Marshal(body)
+		var resp struct {
+			Hits struct {
+				Hits []struct {
+					Source summaryDocument `json:"_source"`
+					Sort   []any           `json:"sort"`
+				} `json:"hits"`
+			} `json:"hits"`
+		}
+		size := pruneSearchSize
+		r, err := opensearchapi.SearchRequest{Index: indices, Body: bytes.NewReader(query), Size: &size, IgnoreUnavailable: &ignoreUnavailable}.Do(context.TODO(), client)
+		if err == nil {
+			err = decodeResponse(r, &resp)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error searching job summaries: %v", err)
+		}
+		for _, hit := range resp.Hits.Hits {
+			if _, exists := runs[hit.Source.UUID]; !exists && hit.Source.UUID != "" {
+				runs[hit.Source.UUID] = &PrunedRun{UUID: hit.Source.UUID, Timestamp: hit.Source.Timestamp, Workload: hit.Source.workload()}
+			}
+		}
+		if len(resp.Hits.Hits) < pruneSearchSize {
+			break
+		}
+		searchAfter = resp.Hits.Hits[len(resp.Hits.Hits)-1].Sort
+	}
+	var prunedRuns []PrunedRun
+	for _, run := range runs {
+		prunedRuns = append(prunedRuns, *run)
+	}
+	slices.SortFunc(prunedRuns, func(a, b PrunedRun) int { return a.Timestamp.Compare(b.Timestamp) })
+	return prunedRuns, nil
+}
+
+// runQuery matches all the documents of a run
+func runQuery(uuid string) *bytes.Reader {
+	query, _ := json.Marshal(map[string]any{"query": exactQuery("uuid", uuid)})
+	return bytes.NewReader(query)
+}
+
+// exactQuery matches the documents whose field is exactly the value, with a term query on its keyword subfield.
+// Phrase queries on the analyzed field would also match values containing the phrase, which must not be deleted
+func exactQuery(field, value string) map[string]any {
+	return map[string]any{"term": map[string]any{field + ".keyword": value}}
+}
+
+// decodeResponse decodes the body of a successful response into the given value
+func decodeResponse(r *opensearchapi.Response, v any) error {
+	defer r.Body.Close()
+	if r.IsError() {
+		return fmt.Errorf("%s", r.String())
+	}
+	return json.NewDecoder(r.Body).Decode(v)
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRetention(t *testing.T) {
+	tests := map[string]time.Duration{
+		"90d":  90 * 24 * time.Hour,
+		"1.5d": 36 * time.Hour,
+		"2w":   14 * 24 * time.Hour,
+		"12h":  12 * time.Hour,
+		"30m":  30 * time.Minute,
+	}
+	for retention, expected := range tests {
+		duration, err := ParseRetention(retention)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", retention, err)
+		} else if duration != expected {
+			t.Errorf("%s: got %v, expected %v", retention, duration, expected)
+		}
+	}
+	for _, retention := range []string{"", "d", "xd", "2y", "90"} {
+		if _, err := ParseRetention(retention); err == nil {
+			t.Errorf("%q: expected an error", retention)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"github.com/kube-burner/kube-burner/pkg/util"
+	"github.com/kube-burner/kube-burner/pkg/util/fileutils"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+var prometheusRuleGVR = schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "prometheusrules"}
+
+// RuleGroup Prometheus rule group
+type RuleGroup struct {
+	Name     string `yaml:"name" json:"name"`
+	Interval string `yaml:"interval,omitempty" json:"interval,omitempty"`
+	Rules    []Rule `yaml:"rules" json:"rules"`
+}
+
+// Rule Prometheus recording rule
+type Rule struct {
+	Record string            `yaml:"record" json:"record"`
+	Expr   string            `yaml:"expr" json:"expr"`
+	Labels map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+}
+
+// RecordingRules rule groups of a metrics endpoint, installed for the duration of the benchmark
+type RecordingRules struct {
+	config.RecordingRules
+	Groups []RuleGroup
+	// name of the PrometheusRule or ruler namespace holding the groups
+	name          string
+	dynamicClient dynamic.Interface
+}
+
+// readRecordingRules reads and validates the rule files
+func readRecordingRules(recordingRules config.RecordingRules, embedCfg *fileutils.EmbedConfiguration) (*RecordingRules, error) {
+	if recordingRules.RulerURL == "" && recordingRules.Namespace == "" {
+		return nil, fmt.Errorf("recordingRules require either a namespace or a rulerURL")
+	}
+	rr := &RecordingRules{RecordingRules: recordingRules}
+	for _, file := range recordingRules.Files {
+		f, err := fileutils.GetMetricsReader(file, embedCfg)
+		if err != nil {
+			return nil, fmt.Errorf("error reading rule file %s: %s", file, err)
+		}
+		content, err := io.ReadAll(f)
+		if err != nil {
+			return nil, fmt.Errorf("error reading rule file %s: %s", file, err)
+		}
+		rendered, err := util.RenderTemplate(content, util.EnvToMap(), util.MissingKeyError, []string{})
+		if err != nil {
+			return nil, fmt.Errorf("template error in %s: %s", file, err)
+		}
+		var ruleFile struct {
+			Groups []RuleGroup `yaml:"groups"`
+		}
+		yamlDec := yaml.NewDecoder(bytes.NewReader(rendered))
+		yamlDec.KnownFields(true)
+		if err := yamlDec.Decode(&ruleFile); err != nil {
+			return nil, fmt.Errorf("error decoding rule file %s: %s", file, err)
+		}
+		for _, group := range ruleFile.Groups {
+			for i, rule := range group.Rules {
+				if rule.Record == "" || rule.Expr == "" {
+					return nil, fmt.Errorf("rule %d of group %s in %s: record and expr are required", i+1, group.Name, file)
+				}
+			}
+		}
+		rr.Groups = append(rr.Groups, ruleFile.Groups...)
+	}
+	return rr, nil
+}
+
+// Install installs the rule groups, the series they record are available once Prometheus evaluates them
+func (rr *RecordingRules) Install(kubeClientProvider *config.KubeClientProvider, uuid string) error {
+	rr.name = "kube-burner-" + uuid
+	if rr.RulerURL != "" {
+		for _, group := range rr.Groups {
+			body, err := yaml.Marshal(group)
+			if err != nil {
+				return err
+			}
+			if err := rr.rulerRequest(http.MethodPost, body); err != nil {
+				return fmt.Errorf("error creating rule group %s: %v", group.Name, err)
+			}
+		}
+		log.Infof("Recording rules installed in ruler namespace %s: %d groups", rr.name, len(rr.Groups))
+		return nil
+	}
+	_, restConfig := kubeClientProvider.DefaultClientSet()
+	rr.dynamicClient = dynamic.NewForConfigOrDie(restConfig)
+	labels := map[string]string{"kube-burner-uuid": uuid}
+	maps.Copy(labels, rr.Labels)
+	groups := make([]any, len(rr.Groups))
+	for i, group := range rr.Groups {
+		rules := make([]any, len(group.Rules))
+		for j, rule := range group.Rules {
+			r := map[string]any{"record": rule.Record, "expr": rule.Expr}
+			if len(rule.Labels) > 0 {
+				r["labels"] = toAnyMap(rule.Labels)
+			}
+			rules[j] = r
+		}
+		g := map[string]any{"name": group.Name, "rules": rules}
+		if group.Interval != "" {
+			g["interval"] = group.Interval
+		}
+		groups[i] = g
+	}
+	prometheusRule := &unstructured.Unstructured{}
+	prometheusRule.SetGroupVersionKind(prometheusRuleGVR.GroupVersion().WithKind("PrometheusRule"))
+	prometheusRule.SetName(rr.name)
+	prometheusRule.SetLabels(labels)
+	prometheusRule.Object["spec"] = map[string]any{"groups": groups}
+	_, err := rr.dynamicClient.Resource(prometheusRuleGVR).Namespace(rr.Namespace).Create(context.TODO(), prometheusRule, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("error creating PrometheusRule %s/%s: %v", rr.Namespace, rr.name, err)
+	}
+	log.Infof("Recording rules installed in PrometheusRule %s/%s: %d groups", rr.Namespace, rr.name, len(rr.Groups))
+	return nil
+}
+
+// Remove removes the rule groups installed
+func (rr *RecordingRules) Remove() error {
+	if rr.name == "" {
+		return nil
+	}
+	var err error
+	if rr.RulerURL != "" {
+		err = rr.rulerRequest(http.MethodDelete, nil)
+	} else if rr.dynamicClient != nil {
+		err = rr.dynamicClient.Resource(prometheusRuleGVR).Namespace(rr.Namespace).Delete(context.TODO(), rr.name, metav1.DeleteOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("error removing recording rules %s: %v", rr.name, err)
+	}
+	log.Infof("Recording rules %s removed", rr.name)
+	rr.name = ""
+	return nil
+}
+
+// rulerRequest sends a request to the rules namespace endpoint of the ruler API
+func (rr *RecordingRules) rulerRequest(method string, body []byte) error {
+	endpoint := fmt.Sprintf("%s/config/v1/rules/%s", strings.TrimSuffix(rr.RulerURL, "/"), url.PathEscape(rr.name))
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/yaml")
+	for k, v := range rr.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := (&http.Client{Timeout: time.Minute}).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, msg)
+	}
+	return nil
+}
+
+func toAnyMap(m map[string]string) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
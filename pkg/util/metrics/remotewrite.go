@@ -0,0 +1,294 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cloud-bulldozer/go-commons/v2/indexers"
+	"github.com/golang/snappy"
+	"github.com/kube-burner/kube-burner/pkg/config"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// RemoteWriteIndexer sends documents as samples to a Prometheus remote_write endpoint
+const RemoteWriteIndexer indexers.IndexerType = "remoteWrite"
+
+const (
+	remoteWriteMetricPrefix = "kube_burner_"
+	// Maximum number of samples sent per request
+	remoteWriteBatchSize = 10000
+)
+
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// Fields not converted into labels
+var remoteWriteSkippedFields = map[string]struct{}{
+	"timestamp":  {},
+	"metricName": {},
+	"query":      {},
+	"metadata":   {},
+	"labels":     {},
+}
+
+type label struct {
+	name, value string
+}
+
+type sample struct {
+	value     float64
+	timestamp int64
+}
+
+// timeSeries samples of a unique set of labels
+type timeSeries struct {
+	labels  []label
+	samples []sample
+}
+
+// remoteWrite converts documents into samples: documents with a value field, like the ones from Prometheus queries, become a sample
+// of the kube_burner_<metricName> series, other documents generate a kube_burner_<metricName>_<field> sample per numeric field.
+// String fields and the labels of the document become labels of the series
+type remoteWrite struct {
+	url      string
+	username string
+	password string
+	headers  map[string]string
+	client   *http.Client
+}
+
+func newRemoteWriteIndexer(indexerConfig config.IndexerConfig) (*remoteWrite, error) {
+	if indexerConfig.RemoteWriteURL == "" {
+		return nil, fmt.Errorf("remoteWriteURL not specified")
+	}
+	tlsConfig, err := indexerTLSConfig(indexerConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &remoteWrite{
+		url:      indexerConfig.RemoteWriteURL,
+		username: indexerConfig.Username,
+		password: indexerConfig.Password,
+		headers:  indexerConfig.Headers,
+		client: &http.Client{
+			Timeout:   time.Minute,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}, nil
+}
+
+// Index converts the documents into time series and sends them in batches
+func (rw *remoteWrite) Index(documents []any, opts indexers.IndexingOpts) (string, error) {
+	if len(documents) == 0 {
+		return fmt.Sprintf("Indexing skipped due to %v docs", len(documents)), nil
+	}
+	start := time.Now()
+	series := map[string]*timeSeries{}
+	for _, document := range documents {
+		if err := addDocumentSamples(series, document); err != nil {
+			return "", err
+		}
+	}
+	keys := slices.Sorted(maps.Keys(series))
+	var batch []*timeSeries
+	var batchSamples, totalSamples int
+	for i, key := range keys {
+		ts := series[key]
+		// Samples of a series must be sent in chronological order
+		slices.SortFunc(ts.samples, func(a, b sample) int {
+			return cmp.Compare(a.timestamp, b.timestamp)
+		})
+		batch = append(batch, ts)
+		batchSamples += len(ts.samples)
+		if batchSamples >= remoteWriteBatchSize || i == len(keys)-1 {
+			if err := rw.send(batch); err != nil {
+				return "", fmt.Errorf("error sending %s samples: %v", opts.MetricName, err)
+			}
+			totalSamples += batchSamples
+			batch, batchSamples = nil, 0
+		}
+	}
+	return fmt.Sprintf("Remote write finished in %v: series=%d samples=%d", time.Since(start).Truncate(time.Millisecond), len(series), totalSamples), nil
+}
+
+// addDocumentSamples adds the samples generated from a document
+func addDocumentSamples(series map[string]*timeSeries, document any) error {
+	j, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("cannot encode document %v: %v", document, err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(j, &fields); err != nil {
+		// Not an object, nothing to convert
+		return nil
+	}
+	metricName, _ := fields["metricName"].(string)
+	if metricName == "" {
+		return nil
+	}
+	timestamp := time.Now().UnixMilli()
+	if ts, ok := fields["timestamp"].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+			timestamp = t.UnixMilli()
+		}
+	}
+	var labels []label
+	if docLabels, ok := fields["labels"].(map[string]any); ok {
+		for name, value := range docLabels {
+			labels = append(labels, label{sanitizeLabelName(name), fmt.Sprint(value)})
+		}
+	}
+	values := map[string]float64{}
+	for name, value := range fields {
+		if _, skipped := remoteWriteSkippedFields[name]; skipped {
+			continue
+		}
+		switch v := value.(type) {
+		case string:
+			if v != "" {
+				labels = append(labels, label{sanitizeLabelName(name), v})
+			}
+		case float64:
+			values[name] = v
+		}
+	}
+	if value, ok := values["value"]; ok {
+		// Documents from Prometheus queries
+		addSample(series, SeriesName(metricName, ""), labels, sample{value, timestamp})
+		return nil
+	}
+	for name, value := range values {
+		addSample(series, SeriesName(metricName, name), labels, sample{value, timestamp})
+	}
+	return nil
+}
+
+// SeriesName returns the name of the series the remote write indexer writes a numeric field of the documents with the
+// given metric name to, kube_burner_<metricName>_<field>, or kube_burner_<metricName> when the field is empty
+func SeriesName(metricName, field string) string {
+	name := remoteWriteMetricPrefix + sanitizeLabelName(metricName)
+	if field != "" {
+		name += "_" + toSnakeCase(field)
+	}
+	return name
+}
+
+func addSample(series map[string]*timeSeries, name string, labels []label, s sample) {
+	seriesLabels := append([]label{{"__name__", name}}, labels...)
+	// Labels must be sorted by name and unique, the document labels have preference over its fields
+	slices.SortStableFunc(seriesLabels, func(a, b label) int {
+		return cmp.Compare(a.name, b.name)
+	})
+	seriesLabels = slices.CompactFunc(seriesLabels, func(a, b label) bool {
+		return a.name == b.name
+	})
+	var key strings.Builder
+	for _, l := range seriesLabels {
+		fmt.Fprintf(&key, "%s=%q,", l.name, l.value)
+	}
+	ts, exists := series[key.String()]
+	if !exists {
+		ts = &timeSeries{labels: seriesLabels}
+		series[key.String()] = ts
+	}
+	ts.samples = append(ts.samples, s)
+}
+
+// send sends a WriteRequest protobuf message, snappy compressed
+func (rw *remoteWrite) send(batch []*timeSeries) error {
+	var writeRequest []byte
+	for _, ts := range batch {
+		var tsMsg []byte
+		for _, l := range ts.labels {
+			var labelMsg []byte
+			labelMsg = protowire.AppendTag(labelMsg, 1, protowire.BytesType)
+			labelMsg = protowire.AppendString(labelMsg, l.name)
+			labelMsg = protowire.AppendTag(labelMsg, 2, protowire.BytesType)
+			labelMsg = protowire.AppendString(labelMsg, l.value)
+			tsMsg = protowire.AppendTag(tsMsg, 1, protowire.BytesType)
+			tsMsg = protowire.AppendBytes(tsMsg, labelMsg)
+		}
+		for _, s := range ts.samples {
+			var sampleMsg []byte
+			sampleMsg = protowire.AppendTag(sampleMsg, 1, protowire.Fixed64Type)
+			sampleMsg = protowire.AppendFixed64(sampleMsg, math.Float64bits(s.value))
+			sampleMsg = protowire.AppendTag(sampleMsg, 2, protowire.VarintType)
+			sampleMsg = protowire.AppendVarint(sampleMsg, uint64(s.timestamp))
+			tsMsg = protowire.AppendTag(tsMsg, 2, protowire.BytesType)
+			tsMsg = protowire.AppendBytes(tsMsg, sampleMsg)
+		}
+		writeRequest = protowire.AppendTag(writeRequest, 1, protowire.BytesType)
+		writeRequest = protowire.AppendBytes(writeRequest, tsMsg)
+	}
+	req, err := http.NewRequest(http.MethodPost, rw.url, bytes.NewReader(snappy.Encode(nil, writeRequest)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("User-Agent", "kube-burner")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	for name, value := range rw.headers {
+		req.Header.Set(name, value)
+	}
+	if rw.username != "" {
+		req.SetBasicAuth(rw.username, rw.password)
+	}
+	resp, err := rw.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s", resp.Status, msg)
+	}
+	return nil
+}
+
+func sanitizeLabelName(name string) string {
+	name = invalidLabelChars.ReplaceAllString(name, "_")
+	if name != "" && unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	return name
+}
+
+// toSnakeCase converts field names like podReadyLatency into pod_ready_latency
+func toSnakeCase(name string) string {
+	var snake strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				snake.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		snake.WriteRune(r)
+	}
+	return sanitizeLabelName(snake.String())
+}
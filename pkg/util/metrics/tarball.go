@@ -87,6 +87,11 @@ func ImportTarball(tarball string, indexer *indexers.Indexer) error {
 		if err == io.EOF {
 			break
 		}
+		// Only the metric files are imported, the tarball may hold other files like pprof profiles
+		if filepath.Ext(hdr.Name) != ".json" {
+			log.Debugf("Skipping %s", hdr.Name)
+			continue
+		}
 		_, err = io.Copy(&rawData, tr)
 		json.Unmarshal(rawData.Bytes(), &metrics)
 		rawData.Reset()
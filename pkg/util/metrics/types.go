@@ -32,6 +32,8 @@ type ScraperConfig struct {
 	MetricsProfile  string
 	AlertProfile    string
 	EmbedCfg        *fileutils.EmbedConfiguration
+	// KubeClientProvider is required to tunnel to in-cluster Prometheus endpoints
+	KubeClientProvider *config.KubeClientProvider
 }
 
 // ScraperResponse holds parsed data related to scraper and target indexer
@@ -41,4 +43,15 @@ type Scraper struct {
 	IndexerList       map[string]indexers.Indexer
 	SummaryMetadata   map[string]any
 	MetricsMetadata   map[string]any
+	// RecordingRules installed for the duration of the benchmark
+	RecordingRules []*RecordingRules
+	// Tunnels to the Prometheus endpoints, open while the scraper is in use
+	Tunnels []*prometheus.Tunnel
+}
+
+// Close tears down the tunnels to the Prometheus endpoints
+func (s Scraper) Close() {
+	for _, tunnel := range s.Tunnels {
+		tunnel.Close()
+	}
 }
@@ -16,7 +16,11 @@ package metrics
 
 import (
 	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
 	"io"
+	"os"
 
 	"github.com/kube-burner/kube-burner/pkg/config"
 	"github.com/kube-burner/kube-burner/pkg/util"
@@ -47,3 +51,29 @@ func DecodeMetricsEndpoint(metricsEndpointPath string) []config.MetricsEndpoint
 	}
 	return metricsEndpoints
 }
+
+// indexerTLSConfig returns the TLS configuration of the indexer, with its client certificate and CA bundle when given
+func indexerTLSConfig(indexerConfig config.IndexerConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: indexerConfig.InsecureSkipVerify}
+	if (indexerConfig.CertFile == "") != (indexerConfig.KeyFile == "") {
+		return nil, fmt.Errorf("certFile and keyFile must be set together")
+	}
+	if indexerConfig.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(indexerConfig.CertFile, indexerConfig.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if indexerConfig.CAFile != "" {
+		caBundle, err := os.ReadFile(indexerConfig.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA bundle: %v", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", indexerConfig.CAFile)
+		}
+	}
+	return tlsConfig, nil
+}
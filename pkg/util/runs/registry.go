@@ -0,0 +1,170 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runs
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Statuses of a run
+const (
+	StatusRunning = "running"
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+)
+
+// RegistryEnv environment variable overriding the default registry file
+const RegistryEnv = "KUBE_BURNER_RUNS_REGISTRY"
+
+// Run entry of the registry
+type Run struct {
+	UUID     string `json:"uuid"`
+	Workload string `json:"workload"`
+	// Config configuration file, URL, OCI bundle or ConfigMap of the run
+	Config string   `json:"config"`
+	Jobs   []string `json:"jobs"`
+	// Cluster API server URL
+	Cluster string `json:"cluster"`
+	// Side of A/B benchmarks, which run once per cluster with the same UUID
+	CompareSide string    `json:"compareSide,omitempty"`
+	Host        string    `json:"host"`
+	User        string    `json:"user"`
+	Version     string    `json:"version"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Status      string    `json:"status"`
+	ReturnCode  int       `json:"returnCode"`
+}
+
+// Duration of the run, until now while it's running
+func (r Run) Duration() time.Duration {
+	if r.End.IsZero() {
+		return time.Since(r.Start).Round(time.Second)
+	}
+	return r.End.Sub(r.Start).Round(time.Second)
+}
+
+// DefaultPath returns the registry file set in the environment, ~/.kube-burner/runs.jsonl otherwise
+func DefaultPath() string {
+	if path := os.Getenv(RegistryEnv); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".kube-burner", "runs.jsonl")
+}
+
+// Record appends the state of the run to the registry file. The registry is append-only, each run is recorded when
+// it starts and when it finishes, the latest entry of a run being its current state
+func Record(path string, run Run) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if run.Host == "" {
+		run.Host, _ = os.Hostname()
+	}
+	if run.User == "" {
+		run.User = cmpOr(os.Getenv("USER"), os.Getenv("USERNAME"))
+	}
+	line, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	// A single write per entry, so that concurrent runs don't interleave their entries
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// List returns the runs of the registry, the most recent first
+func List(path string) ([]Run, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	type runKey struct {
+		uuid        string
+		cluster     string
+		compareSide string
+	}
+	var runs []Run
+	index := make(map[runKey]int)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var run Run
+		if err := json.Unmarshal(scanner.Bytes(), &run); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		key := runKey{run.UUID, run.Cluster, run.CompareSide}
+		if i, ok := index[key]; ok {
+			runs[i] = run
+			continue
+		}
+		index[key] = len(runs)
+		runs = append(runs, run)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(runs, func(a, b Run) int {
+		return b.Start.Compare(a.Start)
+	})
+	return runs, nil
+}
+
+// Find returns the runs whose UUID starts with the given prefix
+func Find(path, uuidPrefix string) ([]Run, error) {
+	all, err := List(path)
+	if err != nil {
+		return nil, err
+	}
+	var runs []Run
+	for _, run := range all {
+		if strings.HasPrefix(run.UUID, uuidPrefix) {
+			runs = append(runs, run)
+		}
+	}
+	return runs, nil
+}
+
+func cmpOr(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -38,6 +38,14 @@ const (
 
 var funcMap = sprig.GenericFuncMap()
 
+// unameToKubeArch maps the uname -m machine names to the Kubernetes architectures
+var unameToKubeArch = map[string]string{
+	"x86_64":  "amd64",
+	"aarch64": "arm64",
+	"armv7l":  "arm",
+	"i686":    "386",
+}
+
 func init() {
 	AddRenderingFunction("Binomial", combin.Binomial)
 	AddRenderingFunction("IndexToCombination", combin.IndexToCombination)
@@ -55,6 +63,21 @@ func init() {
 		}
 		return strings.Join(retAddrs, " ")
 	}
+	// Architecture names differ between Kubernetes, which uses the GOARCH ones, and uname -m, used by many image tags
+	funcMap["kubeArch"] = func(arch string) string {
+		if kubeArch, ok := unameToKubeArch[arch]; ok {
+			return kubeArch
+		}
+		return arch
+	}
+	funcMap["unameArch"] = func(arch string) string {
+		for unameArch, kubeArch := range unameToKubeArch {
+			if kubeArch == arch {
+				return unameArch
+			}
+		}
+		return arch
+	}
 	funcMap["ReadFile"] = func(filePath string) (string, error) {
 		// Open the file
 		file, err := os.Open(filePath)
@@ -0,0 +1,94 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// RunWorkers launches the given number of worker processes re-executing the current command line,
+// all of them share the same UUID and hand their results over in a subdirectory of resultsDirectory.
+// Returns the highest return code of the workers
+func RunWorkers(workers int, uuid, resultsDirectory string) int {
+	log.Infof("Launching %d workers with UUID %s", workers, uuid)
+	var names []string
+	var args [][]string
+	for i := range workers {
+		names = append(names, fmt.Sprintf("Worker %d", i))
+		args = append(args, slices.Concat(os.Args[1:], []string{
+			fmt.Sprintf("--uuid=%s", uuid),
+			fmt.Sprintf("--worker-index=%d", i),
+			fmt.Sprintf("--worker-results=%s", filepath.Join(resultsDirectory, fmt.Sprintf("worker-%d", i))),
+		}))
+	}
+	return runProcesses(names, args, nil)
+}
+
+// runProcesses runs the kube-burner executable once per list of arguments, calling exited, if not nil, as each
+// process finishes. Returns the highest return code of the processes
+func runProcesses(names []string, args [][]string, exited func(int)) int {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var rc int
+	executable, err := os.Executable()
+	if err != nil {
+		log.Fatalf("Error finding kube-burner executable: %v", err)
+	}
+	for i := range args {
+		cmd := exec.Command(executable, args[i]...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Start(); err != nil {
+			log.Fatalf("Error starting %s: %v", names[i], err)
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			processRC := 0
+			if err := cmd.Wait(); err != nil {
+				var exitErr *exec.ExitError
+				if errors.As(err, &exitErr) {
+					processRC = exitErr.ExitCode()
+				} else {
+					processRC = 1
+				}
+				log.Errorf("%s finished with rc %d: %v", names[i], processRC, err)
+			} else {
+				log.Infof("%s finished successfully", names[i])
+			}
+			if exited != nil {
+				exited(i)
+			}
+			mu.Lock()
+			rc = max(rc, processRC)
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+	return rc
+}
+
+// WorkerIterations returns the range of iterations [start, end) assigned to the given worker
+func WorkerIterations(iterations, workers, workerIndex int) (int, int) {
+	return workerIndex * iterations / workers, (workerIndex + 1) * iterations / workers
+}
@@ -26,6 +26,7 @@ type Watcher struct {
 	name        string
 	stopChannel chan struct{}
 	Informer    cache.SharedIndexInformer
+	gaps        *gapTracker
 }
 
 // WatcherManager type to manage watchers
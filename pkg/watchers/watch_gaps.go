@@ -0,0 +1,192 @@
+// Copyright 2025 The Kube-burner Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watchers
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+)
+
+// The API server closes the watches once their timeout expires, closures within this margin aren't gaps
+const watchTimeoutMargin = time.Second
+
+// WatchGaps interruptions of the watch of a watcher, and the objects whose events were potentially missed by them
+type WatchGaps struct {
+	// Closures watches closed before their timeout, by the API server or the network
+	Closures int `json:"closures"`
+	// Expired watches ended by a too old resource version error
+	Expired int `json:"expired"`
+	// Relists lists issued after the initial one, to recover from the interruptions
+	Relists int `json:"relists"`
+	// MissedObjects objects created or deleted while the watch was interrupted, only found by the relists
+	MissedObjects int `json:"missedObjects"`
+}
+
+// Add returns the sum of both gaps
+func (g WatchGaps) Add(o WatchGaps) WatchGaps {
+	return WatchGaps{
+		Closures:      g.Closures + o.Closures,
+		Expired:       g.Expired + o.Expired,
+		Relists:       g.Relists + o.Relists,
+		MissedObjects: g.MissedObjects + o.MissedObjects,
+	}
+}
+
+// gapTracker accounts the watch gaps of an informer. Informers recover from them by re-listing, the objects
+// created or deleted meanwhile are detected comparing the relists with the informer store
+type gapTracker struct {
+	name string
+	mu   sync.Mutex
+	gaps WatchGaps
+	// lists initial lists and relists, pages excluded
+	lists int
+	// keys of the objects of the ongoing list
+	listedKeys map[string]struct{}
+	storeKeys  func() []string
+}
+
+// wrap accounts the lists and watches of the ListWatch
+func (t *gapTracker) wrap(lw *cache.ListWatch) {
+	listFunc, watchFunc := lw.ListFunc, lw.WatchFunc
+	lw.ListFunc = func(options metav1.ListOptions) (runtime.Object, error) {
+		list, err := listFunc(options)
+		if err == nil {
+			t.listed(options, list)
+		}
+		return list, err
+	}
+	lw.WatchFunc = func(options metav1.ListOptions) (watch.Interface, error) {
+		w, err := watchFunc(options)
+		if err != nil {
+			return w, err
+		}
+		gw := &gapWatch{
+			Interface: w,
+			result:    make(chan watch.Event),
+			stopCh:    make(chan struct{}),
+		}
+		go t.proxy(gw, time.Duration(ptr.Deref(options.TimeoutSeconds, 0))*time.Second)
+		return gw, nil
+	}
+}
+
+// listed accounts a list page, the relisted objects are compared with the store once the last page is received
+func (t *gapTracker) listed(options metav1.ListOptions, list runtime.Object) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if options.Continue == "" {
+		t.lists++
+		t.listedKeys = make(map[string]struct{})
+	}
+	if t.lists == 1 {
+		return
+	}
+	meta.EachListItem(list, func(obj runtime.Object) error {
+		if key, err := cache.MetaNamespaceKeyFunc(obj); err == nil {
+			t.listedKeys[key] = struct{}{}
+		}
+		return nil
+	})
+	if listMeta, err := meta.ListAccessor(list); err != nil || listMeta.GetContinue() != "" {
+		return
+	}
+	t.gaps.Relists++
+	var missed int
+	storeKeys := make(map[string]struct{})
+	for _, key := range t.storeKeys() {
+		storeKeys[key] = struct{}{}
+		if _, exists := t.listedKeys[key]; !exists {
+			missed++
+		}
+	}
+	for key := range t.listedKeys {
+		if _, exists := storeKeys[key]; !exists {
+			missed++
+		}
+	}
+	t.gaps.MissedObjects += missed
+	log.Warnf("%s: watch re-listed, %d objects were created or deleted while it was interrupted", t.name, missed)
+}
+
+// proxy forwards the watch events, accounting the too old resource version errors and the early closures
+func (t *gapTracker) proxy(gw *gapWatch, timeout time.Duration) {
+	defer close(gw.result)
+	start := time.Now()
+	var expired bool
+	for {
+		select {
+		case <-gw.stopCh:
+			return
+		case event, ok := <-gw.Interface.ResultChan():
+			if !ok {
+				if !expired && (timeout == 0 || time.Since(start) < timeout-watchTimeoutMargin) {
+					t.mu.Lock()
+					t.gaps.Closures++
+					t.mu.Unlock()
+					log.Debugf("%s: watch closed after %v", t.name, time.Since(start).Round(time.Second))
+				}
+				return
+			}
+			if event.Type == watch.Error {
+				if err := apierrors.FromObject(event.Object); apierrors.IsResourceExpired(err) || apierrors.IsGone(err) {
+					expired = true
+					t.mu.Lock()
+					t.gaps.Expired++
+					t.mu.Unlock()
+					log.Warnf("%s: watch expired: %v", t.name, err)
+				}
+			}
+			select {
+			case gw.result <- event:
+			case <-gw.stopCh:
+				return
+			}
+		}
+	}
+}
+
+func (t *gapTracker) summary() WatchGaps {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.gaps
+}
+
+// gapWatch watch whose events are forwarded by the gap tracker
+type gapWatch struct {
+	watch.Interface
+	result   chan watch.Event
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+func (w *gapWatch) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+func (w *gapWatch) Stop() {
+	w.stopOnce.Do(func() {
+		close(w.stopCh)
+	})
+	w.Interface.Stop()
+}
@@ -15,10 +15,16 @@
 package watchers
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 )
@@ -33,13 +39,43 @@ func NewWatcher(restClient *rest.RESTClient, name string, resource string, names
 		namespace,
 		optionsModifier,
 	)
+	return newWatcher(name, lw, nil, indexers)
+}
+
+// NewDynamicWatcher return a new ListWatcher of the specified resource and namespace, objects are handled as unstructured
+func NewDynamicWatcher(dynamicClient dynamic.Interface, name string, gvr schema.GroupVersionResource, namespace string, optionsModifier func(options *metav1.ListOptions), indexers cache.Indexers) *Watcher {
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			optionsModifier(&options)
+			return dynamicClient.Resource(gvr).Namespace(namespace).List(context.TODO(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			optionsModifier(&options)
+			return dynamicClient.Resource(gvr).Namespace(namespace).Watch(context.TODO(), options)
+		},
+	}
+	return newWatcher(name, lw, &unstructured.Unstructured{}, indexers)
+}
+
+// newWatcher returns a watcher accounting the gaps of its watch
+func newWatcher(name string, lw *cache.ListWatch, objType runtime.Object, indexers cache.Indexers) *Watcher {
+	gaps := &gapTracker{name: name}
+	gaps.wrap(lw)
+	informer := cache.NewSharedIndexInformer(lw, objType, 0, indexers)
+	gaps.storeKeys = informer.GetStore().ListKeys
 	return &Watcher{
 		name:        name,
 		stopChannel: make(chan struct{}),
-		Informer:    cache.NewSharedIndexInformer(lw, nil, 0, indexers),
+		Informer:    informer,
+		gaps:        gaps,
 	}
 }
 
+// Gaps returns the interruptions of the watch and the objects potentially missed by them
+func (p *Watcher) Gaps() WatchGaps {
+	return p.gaps.summary()
+}
+
 // StartAndCacheSync starts informer and waits for the cache be synced.
 func (p *Watcher) StartAndCacheSync() error {
 	go p.Informer.Run(p.stopChannel)
@@ -90,13 +90,15 @@ func (wh *WorkloadHelper) RunWithAdditionalVars(configFile string, additionalVar
 		ConfigSpec.MetricsEndpoints[pos].Token = wh.PrometheusToken
 	}
 	metricsScraper := metrics.ProcessMetricsScraperConfig(metrics.ScraperConfig{
-		ConfigSpec:      &ConfigSpec,
-		MetricsEndpoint: wh.MetricsEndpoint,
-		SummaryMetadata: wh.SummaryMetadata,
-		MetricsMetadata: wh.MetricsMetadata,
-		UserMetaData:    wh.UserMetadata,
-		EmbedCfg:        wh.embedCfg,
+		ConfigSpec:         &ConfigSpec,
+		MetricsEndpoint:    wh.MetricsEndpoint,
+		SummaryMetadata:    wh.SummaryMetadata,
+		MetricsMetadata:    wh.MetricsMetadata,
+		UserMetaData:       wh.UserMetadata,
+		EmbedCfg:           wh.embedCfg,
+		KubeClientProvider: wh.kubeClientProvider,
 	})
+	defer metricsScraper.Close()
 	rc, err := burner.Run(ConfigSpec, wh.kubeClientProvider, metricsScraper, additionalMeasurementFactoryMap, wh.embedCfg)
 	if err != nil {
 		log.Error(err.Error())
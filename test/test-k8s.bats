@@ -273,3 +273,83 @@ teardown_file() {
     check_metrics_not_created_for_job ${job} ${metric}
   done
 }
+
+@test "kube-burner validate" {
+  run_cmd ${KUBE_BURNER} validate -c kube-burner.yml
+  run_cmd ${KUBE_BURNER} validate -c kube-burner-virt.yml
+  printf "jobs:\n  - name: invalid\n    jobType: create\n" > /tmp/kube-burner-invalid.yml
+  run ${KUBE_BURNER} validate -c /tmp/kube-burner-invalid.yml
+  [ "$status" -ne 0 ]
+}
+
+@test "kube-burner schema" {
+  ${KUBE_BURNER} schema > /tmp/kube-burner-schema.json
+  jq -e '.properties.jobs' /tmp/kube-burner-schema.json
+}
+
+@test "kube-burner render" {
+  run_cmd ${KUBE_BURNER} render -c kube-burner.yml --sample=1 --output-dir=rendered-${UUID}
+  check_file_exists rendered-${UUID}/namespaced.yml
+  run_cmd ${KUBE_BURNER} render -c kube-burner.yml --sample=1 --dry-run
+}
+
+@test "kube-burner describe" {
+  ${KUBE_BURNER} describe -c kube-burner.yml --format=json > /tmp/kube-burner-describe.json
+  jq -e '.' /tmp/kube-burner-describe.json
+}
+
+@test "kube-burner estimate" {
+  ${KUBE_BURNER} estimate -c kube-burner.yml --format=json > /tmp/kube-burner-estimate.json
+  jq -e '.jobs[0].objects > 0' /tmp/kube-burner-estimate.json
+}
+
+@test "kube-burner dashboards" {
+  run_cmd ${KUBE_BURNER} dashboards --datasource=elasticsearch -o dashboard-${UUID}.json
+  jq -e '.panels | length > 0' dashboard-${UUID}.json
+  run_cmd ${KUBE_BURNER} dashboards --datasource=prometheus -o dashboard-prometheus-${UUID}.json
+  jq -e '.panels | length > 0' dashboard-prometheus-${UUID}.json
+}
+
+@test "kube-burner report; runs: local-indexing=true" {
+  export LOCAL_INDEXING=true
+  export KUBE_BURNER_RUNS_REGISTRY; KUBE_BURNER_RUNS_REGISTRY=$(mktemp -d)/runs.jsonl
+  run_cmd ${KUBE_BURNER} init -c kube-burner.yml --uuid="${UUID}" --log-level=debug
+  run_cmd ${KUBE_BURNER} report --uuid="${UUID}" --metrics-directory=${METRICS_FOLDER}
+  check_file_exists kube-burner-report-${UUID}.html
+  run_cmd ${KUBE_BURNER} report --uuid="${UUID}" --metrics-directory=${METRICS_FOLDER} --format=markdown -o report-${UUID}.md
+  check_file_exists report-${UUID}.md
+  ${KUBE_BURNER} runs list --status=passed > /tmp/kube-burner-runs.txt
+  grep "${UUID}" /tmp/kube-burner-runs.txt
+  ${KUBE_BURNER} runs show "${UUID:0:8}" > /tmp/kube-burner-run.json
+  jq -e --arg uuid "${UUID}" '.[0].uuid == $uuid' /tmp/kube-burner-run.json
+}
+
+@test "kube-burner init: jobType helm; os-indexing=true; local-indexing=true" {
+  export ES_INDEXING=true LOCAL_INDEXING=true
+  run_cmd ${KUBE_BURNER} init -c kube-burner-helm.yml --uuid="${UUID}" --log-level=debug
+  check_metric_value helmRelease
+  check_file_list ${METRICS_FOLDER}/jobSummary.json ${METRICS_FOLDER}/helmRelease.json
+}
+
+@test "kube-burner init: jobType fio; local-indexing=true" {
+  export LOCAL_INDEXING=true
+  export STORAGE_CLASS_NAME
+  STORAGE_CLASS_NAME=$(get_default_storage_class)
+  run_cmd ${KUBE_BURNER} init -c kube-burner-fio.yml --uuid="${UUID}" --log-level=debug
+  check_file_list ${METRICS_FOLDER}/jobSummary.json ${METRICS_FOLDER}/fioResult.json
+  jq -e 'all(.[]; has("error") | not)' ${METRICS_FOLDER}/fioResult.json
+}
+
+@test "kube-burner init: jobType network; local-indexing=true" {
+  export LOCAL_INDEXING=true
+  run_cmd ${KUBE_BURNER} init -c kube-burner-network.yml --uuid="${UUID}" --log-level=debug
+  check_file_list ${METRICS_FOLDER}/jobSummary.json ${METRICS_FOLDER}/networkResult.json
+  jq -e 'all(.[]; .throughput > 0)' ${METRICS_FOLDER}/networkResult.json
+}
+
+@test "kube-burner init: jobType inflate; local-indexing=true" {
+  export LOCAL_INDEXING=true
+  run_cmd ${KUBE_BURNER} init -c kube-burner-inflate.yml --uuid="${UUID}" --log-level=debug
+  check_file_list ${METRICS_FOLDER}/jobSummary.json ${METRICS_FOLDER}/inflateSample.json
+  jq -e 'map(.paddingSize) | max == 32768' ${METRICS_FOLDER}/inflateSample.json
+}